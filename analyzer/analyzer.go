@@ -16,6 +16,8 @@ import (
 	"fmt"
 	"github.com/viant/afs"
 	"github.com/viant/linager/analyzer/linage"
+	"github.com/viant/linager/inspector/graph"
+	"github.com/viant/linager/inspector/treesitter"
 	"strings"
 
 	sitter "github.com/smacker/go-tree-sitter"
@@ -27,8 +29,20 @@ type FuncSummary struct {
 	Params []*linage.Identifier
 	// Returns holds the return identifiers in order (named returns or function ident for anonymous)
 	Returns []*linage.Identifier
+	// Results holds the declared result types in order
+	Results []string
 	// Flows maps a parameter index to a list of return indices indicating data flows
 	Flows map[int][]int
+	// Sites holds return statements in source order with flows of each statement, merged by Flows
+	Sites []*ReturnSite
+	// Statements holds the number of statements in the function body
+	Statements int
+	// Recursive indicates the function calls itself
+	Recursive bool
+	// Inline maps a return index to parameter (field) selections it returns, e.g. u.Name
+	Inline map[int][]InlineFlow
+	// opaque marks return indices computed from anything other than parameter selections
+	opaque map[int]bool
 }
 
 // -----------------------------------------------------------------------------
@@ -36,9 +50,10 @@ type FuncSummary struct {
 // -----------------------------------------------------------------------------
 
 type Analyzer struct {
-	parser *sitter.Parser
-	match  MatcherFn
-	fs     afs.Service
+	parser   *sitter.Parser
+	language *sitter.Language
+	match    MatcherFn
+	fs       afs.Service
 	// Language tag for this analyzer instance (e.g., "go", "java")
 	Language string
 	// optional service name for normalization across microservices
@@ -56,12 +71,68 @@ type Analyzer struct {
 	projectFiles []string
 	// annotationHooks holds callbacks to process annotations and add custom data-flow edges
 	annotationHooks []AnnotationHook
-	// plugins holds registered AnalyzerPlugin instances for custom analysis
+	// plugins holds registered AnalyzerPlugin instances for custom analysis, ordered by priority
 	plugins []AnalyzerPlugin
+	// dispatch caches plugins dispatched by node type
+	dispatch map[string]*nodePlugins
 	// interprocedural toggles inter-procedural call-return analysis
 	interprocedural bool
 	// funcSummaries holds parsed function signatures and flow summaries
 	funcSummaries map[*linage.Identifier]*FuncSummary
+	// overloads holds Java methods and constructors by name, resolved by invocation arity and argument types
+	overloads map[string][]*overload
+	// funcValues holds function and method identifiers bound to variables, keyed by Identifier.FuncRef
+	funcValues map[string]*linage.Identifier
+	// funcTypes holds names of Go function types, e.g. type Handler func(ctx context.Context) error
+	funcTypes map[string]bool
+	// fieldFuncs holds functions assigned to function typed struct fields in analyzed files, keyed by Type.field
+	fieldFuncs map[string][]*linage.Identifier
+	// fieldCalls holds calls through function typed struct fields, see linkFieldCalls
+	fieldCalls []*fieldCall
+	// inlineMaxStatements enables inlining of trivial functions with up to the given number of statements
+	inlineMaxStatements int
+	// callSites holds call-return sites of the current file considered for inlining
+	callSites []*callSite
+	// errorTracking tags XFER edges between error identifiers as error flows
+	errorTracking bool
+	// errorIdents holds identifier IDs of error typed function returns
+	errorIdents map[string]bool
+	// errorCuts holds src->dst pairs carrying data but not the error itself (e.g. fmt.Errorf with %v)
+	errorCuts map[string]bool
+	// trees retains parsed trees for custom queries, nil unless enabled with WithRetainTrees
+	trees *treesitter.Trees
+	// classifier labels identifiers and struct fields with sensitivity labels, nil unless enabled with WithClassifier
+	classifier graph.Classifier
+	// trace streams walk events as JSON lines, nil unless enabled with WithTrace
+	trace *tracer
+	// redactor replaces secrets in edge literals, attributes and annotations of finished models, nil unless enabled with WithRedactor
+	redactor *graph.Redactor
+	// fieldLabels holds struct type name -> field name -> labels assigned from field declarations
+	fieldLabels map[string]map[string][]string
+	// limits holds file size, parse deadline and node budget guards
+	limits *graph.Config
+	// budget limits nodes visited while analyzing the current file, see graph.Config.NodeLimit
+	budget *treesitter.Budget
+	// maxLiteralLength limits literal values recorded on edges, DefaultMaxLiteralLength when 0, negative disables truncation
+	maxLiteralLength int
+	// maxClosureDepth limits XFER hops spanned by summary edges, 0 for unlimited
+	maxClosureDepth int
+	// maxSummaryEdges limits summary edges added per package model, 0 for unlimited
+	maxSummaryEdges int
+	// confidence overrides linage.DefaultConfidence of heuristic edges by provenance, see WithConfidence
+	confidence map[string]float64
+	// dependencyRoots lists directories dependency packages are loaded from by import path, see WithDependencyRoots
+	dependencyRoots []string
+	// maxDependencyPackages caps loaded dependency packages, DefaultMaxDependencyPackages when 0
+	maxDependencyPackages int
+	// dependencyTypes holds external struct fields: import path.Type -> field name -> field type qualified by import paths
+	dependencyTypes map[string]map[string]string
+	// dependencyPackages holds looked up dependency import paths, true when found under a dependency root
+	dependencyPackages map[string]bool
+	// interfaces holds Go interface type name -> method names, used to devirtualize interface method calls
+	interfaces map[string][]string
+	// maxDevirtualTargets caps implementations an interface method call fans out to, DefaultMaxDevirtualTargets when 0
+	maxDevirtualTargets int
 }
 
 // handleGo captures a goroutine invocation as a concurrent call
@@ -83,10 +154,11 @@ func (a *Analyzer) handleGo(n *sitter.Node, src []byte, Scope *linage.Scope, mod
 	// mark concurrent call for the function identifier(s)
 	fnNode := callNode.ChildByFieldName("function")
 	if fnNode != nil {
-		fns := a.extractIdentifiers(fnNode, src, Scope, model)
-		for _, fn := range fns {
-			model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: fn, Dst: fn, Kind: linage.Call, Scope: Scope.ID + "#go"})
+		fns, callKind, ref := a.callTargets(fnNode, src, Scope, model)
+		if callKind == linage.CallVirtual {
+			defer a.markPossibleSince(model, len(model.DataFlows))
 		}
+		a.addCallEdges(fns, callKind, ref, fnNode, src, Scope, Scope.ID+"#go", model)
 	}
 }
 
@@ -192,59 +264,46 @@ func NewAnalyzer(options ...Option) *Analyzer {
 		importAliases: map[string]string{},
 		// prepare function summaries mapping for interprocedural analysis
 		funcSummaries: make(map[*linage.Identifier]*FuncSummary),
+		funcValues:    map[string]*linage.Identifier{},
+		funcTypes:     map[string]bool{},
+		fieldFuncs:    map[string][]*linage.Identifier{},
+		overloads:     map[string][]*overload{},
+		errorIdents:   map[string]bool{},
+		errorCuts:     map[string]bool{},
+		fieldLabels:   map[string]map[string][]string{},
+		limits:        &graph.Config{},
+
+		dependencyTypes:    map[string]map[string]string{},
+		dependencyPackages: map[string]bool{},
+		interfaces:         map[string][]string{},
+		plugins:            []AnalyzerPlugin{NewWaitGroupPlugin()},
 	}
 	for _, opt := range options {
 		if opt != nil {
 			opt(ret)
 		}
 	}
+	ret.sortPlugins()
+	if ret.trace != nil {
+		ret.trace.setRedactor(ret.redactor)
+	}
 	return ret
 }
 
-// -----------------------------------------------------------------------------
-// Transitive closure across XFER edges (BFS per source)
-// -----------------------------------------------------------------------------
-
-// computeTransitiveClosure adds summary XFER edges per-call-site, preserving original scope context.
-func (a *Analyzer) computeTransitiveClosure(model *linage.PackageModel) {
-	// adjacency of direct XFERs: srcID -> list of dst identifiers
-	adj := map[string][]*linage.Identifier{}
-	for _, e := range model.DataFlows {
-		if e.Kind == linage.Xfer {
-			adj[e.Src.ID] = append(adj[e.Src.ID], e.Dst)
-		}
+// Query runs a tree-sitter query over the retained tree of an analyzed file (see WithRetainTrees)
+func (a *Analyzer) Query(file string, query string) ([]treesitter.QueryMatch, error) {
+	if a.trees == nil {
+		return nil, &graph.ErrNotFound{Kind: "tree", Name: file}
 	}
-	var additional []*linage.DataFlowEdge
-	// For each direct XFER, propagate its context through further XFER chains
-	for _, e := range model.DataFlows {
-		if e.Kind != linage.Xfer {
-			continue
-		}
-		baseSrc := e.Src
-		baseScope := e.Scope
-		// start from the first hop (e.Dst)
-		visited := map[string]bool{e.Dst.ID: true}
-		queue := []*linage.Identifier{e.Dst}
-		for len(queue) > 0 {
-			cur := queue[0]
-			queue = queue[1:]
-			for _, next := range adj[cur.ID] {
-				if visited[next.ID] {
-					continue
-				}
-				visited[next.ID] = true
-				// add a context-sensitive summary edge
-				additional = append(additional, &linage.DataFlowEdge{
-					Src:   baseSrc,
-					Dst:   next,
-					Kind:  linage.Xfer,
-					Scope: baseScope,
-				})
-				queue = append(queue, next)
-			}
-		}
+	return a.trees.Query(file, query)
+}
+
+// Close releases retained parsed trees
+func (a *Analyzer) Close() error {
+	if a.trees == nil {
+		return nil
 	}
-	model.DataFlows = append(model.DataFlows, additional...)
+	return a.trees.Close()
 }
 
 // -----------------------------------------------------------------------------
@@ -263,3 +322,8 @@ func topFileScope(s *linage.Scope) *linage.Scope {
 // AnalyzeAll runs analysis over all detected project roots under the given directory
 // and merges their PackageModels into a single global model.
 // Implementation moved to package.go
+
+// parse parses source code within the configured parse deadline, see WithParseTimeout
+func (a *Analyzer) parse(code []byte) (*sitter.Tree, error) {
+	return treesitter.Parse(a.parser, code, a.limits.ParseDeadline())
+}
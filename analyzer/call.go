@@ -0,0 +1,227 @@
+package analyzer
+
+import (
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/viant/linager/analyzer/linage"
+	"strings"
+)
+
+// callTargets resolves identifiers invoked by a call function expression, the call kind and the qualified callee
+// reference: methods are qualified by the receiver type (svc.Process -> Service.Process), package-qualified calls
+// use the file imports (log.Printf) and are marked external when the target function is not analyzed.
+func (a *Analyzer) callTargets(fnNode *sitter.Node, src []byte, scope *linage.Scope, model *linage.PackageModel) ([]*linage.Identifier, string, string) {
+	ref := strings.TrimSpace(string(src[fnNode.StartByte():fnNode.EndByte()]))
+	if fnNode.Type() == "identifier" {
+		if target := a.boundFunc(scope.Find(ref)); target != nil {
+			// call through a function value variable, e.g. h := user.Validate; h()
+			if target.Kind == "method" {
+				return []*linage.Identifier{target}, linage.CallMethod, methodSymbol(target)
+			}
+			return []*linage.Identifier{target}, linage.CallFunction, target.Name
+		}
+	}
+	if fnNode.Type() != "selector_expression" {
+		return a.extractIdentifiers(fnNode, src, scope, model), linage.CallFunction, ref
+	}
+	if target := a.methodExpression(fnNode, src, scope); target != nil {
+		// method expression call, e.g. Service.Process(svc, order)
+		return []*linage.Identifier{target}, linage.CallMethod, methodSymbol(target)
+	}
+	operand := fnNode.ChildByFieldName("operand")
+	field := fnNode.ChildByFieldName("field")
+	if operand == nil || field == nil || operand.Type() != "identifier" {
+		return a.extractIdentifiers(fnNode, src, scope, model), linage.CallMethod, ref
+	}
+	name := string(src[operand.StartByte():operand.EndByte()])
+	method := string(src[field.StartByte():field.EndByte()])
+	if importPath, ok := a.importAliases[name]; ok && scope.Find(name) == nil {
+		ref = importPath + "." + method
+		if target := a.packageFunc(importPath, method, model); target != nil {
+			return []*linage.Identifier{target}, linage.CallFunction, ref
+		}
+		fns := a.extractIdentifiers(fnNode, src, scope, model)
+		for _, fn := range fns {
+			fn.Kind = "external"
+		}
+		return fns, linage.CallExternal, ref
+	}
+	if receiverType := a.receiverType(name, scope, src); receiverType != "" {
+		ref = receiverType + "." + method
+		if target := scope.Find(ref); target != nil {
+			return []*linage.Identifier{target}, linage.CallMethod, ref
+		}
+		if targets, narrowed := a.devirtualize(receiverType, method, scope.Find(name), model); narrowed {
+			return targets, linage.CallMethod, methodSymbol(targets[0])
+		} else if len(targets) > 0 {
+			return targets, linage.CallVirtual, ref
+		}
+	}
+	return a.extractIdentifiers(fnNode, src, scope, model), linage.CallMethod, ref
+}
+
+// funcValue returns function or method identifier denoted by a value expression (process, svc.Process, pkg.Process,
+// Service.Process or a variable bound to a function value), nil if the expression is not a function value
+func (a *Analyzer) funcValue(expr *sitter.Node, src []byte, scope *linage.Scope, model *linage.PackageModel) *linage.Identifier {
+	switch expr.Type() {
+	case "identifier":
+		return a.boundFunc(scope.Find(string(src[expr.StartByte():expr.EndByte()])))
+	case "selector_expression":
+		if target := a.methodExpression(expr, src, scope); target != nil {
+			return target
+		}
+		operand := expr.ChildByFieldName("operand")
+		field := expr.ChildByFieldName("field")
+		if operand == nil || field == nil || operand.Type() != "identifier" {
+			return nil
+		}
+		name := string(src[operand.StartByte():operand.EndByte()])
+		method := string(src[field.StartByte():field.EndByte()])
+		if importPath, ok := a.importAliases[name]; ok && scope.Find(name) == nil {
+			return a.packageFunc(importPath, method, model)
+		}
+		if receiverType := a.receiverType(name, scope, src); receiverType != "" {
+			return a.boundFunc(scope.Find(receiverType + "." + method))
+		}
+	}
+	return nil
+}
+
+// boundFunc returns the function identifier itself or the function bound to a function value variable
+func (a *Analyzer) boundFunc(id *linage.Identifier) *linage.Identifier {
+	if id == nil {
+		return nil
+	}
+	switch {
+	case id.Kind == "func" || id.Kind == "method":
+		return id
+	case id.FuncRef != "":
+		return a.funcValues[id.FuncRef]
+	}
+	return nil
+}
+
+// bindFuncValue records a function value assigned to a variable
+func (a *Analyzer) bindFuncValue(dst *linage.Identifier, expr *sitter.Node, src []byte, scope *linage.Scope, model *linage.PackageModel) {
+	if target := a.funcValue(expr, src, scope, model); target != nil && target != dst {
+		dst.FuncRef = target.ID
+		a.funcValues[target.ID] = target
+	}
+}
+
+// addFuncArgFlows flows functions passed as arguments (e.g. apply(double, items)) into callee parameters
+func (a *Analyzer) addFuncArgFlows(actuals []*sitter.Node, summary *FuncSummary, src []byte, scope *linage.Scope, model *linage.PackageModel) {
+	for i, actual := range actuals {
+		if i >= len(summary.Params) {
+			break
+		}
+		if target := a.funcValue(actual, src, scope, model); target != nil {
+			model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: target, Dst: summary.Params[i], Kind: linage.Xfer, Scope: scope.ID})
+		}
+	}
+}
+
+// methodSymbol returns receiver qualified method name (e.g. User.Validate) of a method identifier
+func methodSymbol(method *linage.Identifier) string {
+	qualifier := strings.TrimSuffix(method.ID, "."+method.Name)
+	return qualifier[strings.LastIndex(qualifier, ".")+1:] + "." + method.Name
+}
+
+// packageFunc returns an analyzed function of the imported package, nil if the package was not analyzed
+func (a *Analyzer) packageFunc(importPath, name string, model *linage.PackageModel) *linage.Identifier {
+	for _, id := range model.Idents {
+		if id.Kind == "func" && id.Name == name && (id.Package == importPath || strings.HasSuffix(id.Package, "/"+importPath)) {
+			return id
+		}
+	}
+	return nil
+}
+
+// receiverType returns the named type of a local variable or parameter visible in scope (e.g. *Service -> Service)
+func (a *Analyzer) receiverType(name string, scope *linage.Scope, src []byte) string {
+	typeName := ""
+	if id := scope.Find(name); id != nil {
+		typeName = id.Type
+	}
+	if typeName == "" {
+		typeName = a.paramType(name, scope, src)
+	}
+	typeName = strings.TrimLeft(strings.TrimSpace(typeName), "*&")
+	if index := strings.Index(typeName, "["); index > 0 {
+		typeName = typeName[:index] // generic instantiation, e.g. Cache[string]
+	}
+	if strings.ContainsAny(typeName, ".({ ") {
+		return "" // imported, func or anonymous types are not analyzed methods
+	}
+	return typeName
+}
+
+// paramType returns declared type of a receiver or parameter of the enclosing function
+func (a *Analyzer) paramType(name string, scope *linage.Scope, src []byte) string {
+	fnScope := enclosingFunction(scope)
+	if fnScope == nil || fnScope.Parent == nil {
+		return ""
+	}
+	fn := fnScope.Parent.Symbols[fnScope.Name]
+	if fn == nil || fn.Node == nil {
+		return ""
+	}
+	for _, field := range []string{"receiver", "parameters"} {
+		for _, param := range namedChildren(fn.Node.ChildByFieldName(field)) {
+			typeNode := param.ChildByFieldName("type")
+			if typeNode == nil {
+				continue
+			}
+			for _, nameNode := range parameterNames(param) {
+				if string(src[nameNode.StartByte():nameNode.EndByte()]) == name {
+					return string(src[typeNode.StartByte():typeNode.EndByte()])
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// receiverName returns the receiver type name of a method declaration (e.g. func (s *Service[T]) -> Service)
+func receiverName(n *sitter.Node, src []byte) string {
+	for _, param := range namedChildren(n.ChildByFieldName("receiver")) {
+		if typeNode := param.ChildByFieldName("type"); typeNode != nil {
+			name := strings.TrimLeft(string(src[typeNode.StartByte():typeNode.EndByte()]), "*")
+			if index := strings.Index(name, "["); index > 0 {
+				name = name[:index]
+			}
+			return name
+		}
+	}
+	return ""
+}
+
+// addCallEdges records CALL edges for call targets with the call kind, callee reference, mechanism and call site position
+func (a *Analyzer) addCallEdges(fns []*linage.Identifier, kind, ref string, fnNode *sitter.Node, src []byte, scope *linage.Scope, scopeID string, model *linage.PackageModel) {
+	site := fnNode
+	if field := fnNode.ChildByFieldName("field"); fnNode.Type() == "selector_expression" && field != nil {
+		site = field
+	}
+	a.appendCallEdges(fns, kind, ref, a.callMechanism(fnNode, src, scope), site, scope, scopeID, model)
+}
+
+// appendCallEdges records CALL edges for call targets reached by a mechanism at a call site node
+func (a *Analyzer) appendCallEdges(fns []*linage.Identifier, kind, ref, mechanism string, site *sitter.Node, scope *linage.Scope, scopeID string, model *linage.PackageModel) {
+	file := strings.TrimPrefix(topFileScope(scope).ID, model.Path+":")
+	for _, fn := range fns {
+		model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{
+			Src:   fn,
+			Dst:   fn,
+			Kind:  linage.Call,
+			Scope: scopeID,
+			Attributes: map[string]interface{}{
+				linage.CallKindAttribute:      kind,
+				linage.CallRefAttribute:       ref,
+				linage.CallMechanismAttribute: mechanism,
+				linage.FileAttribute:          file,
+				linage.LineAttribute:          int(site.StartPoint().Row) + 1,
+				linage.ColumnAttribute:        int(site.StartPoint().Column) + 1,
+				linage.EndColumnAttribute:     int(site.EndPoint().Column) + 1,
+			},
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package analyzer
+
+import (
+	"encoding/json"
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/viant/linager/analyzer/linage"
+	"github.com/viant/linager/inspector/graph"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// loggingCall matches qualified references of logging calls (log.Printf, log/slog.Info, logger.Error)
+var loggingCall = regexp.MustCompile(`(?i)(^|[/.])(log|slog|logrus|zap|zerolog|glog|klog)\.|logger\.|^fmt\.(Print|Fprint)`)
+
+// codeKinds lists identifier kinds denoting code rather than data
+var codeKinds = map[string]bool{"func": true, "method": true, "type": true, "file": true, "external": true}
+
+// classifyField records labels of a struct field declaration including its tags
+func (a *Analyzer) classifyField(typeName, fieldName, fieldType string, tagNode *sitter.Node, src []byte, model *linage.PackageModel) {
+	ctx := &graph.ClassifyContext{Package: model.Path, Owner: typeName, Name: fieldName, Type: fieldType}
+	if tagNode != nil {
+		if tag, err := strconv.Unquote(string(src[tagNode.StartByte():tagNode.EndByte()])); err == nil {
+			ctx.Tag = reflect.StructTag(tag)
+		}
+	}
+	labels := a.classifier.Classify(ctx)
+	if len(labels) == 0 {
+		return
+	}
+	if a.fieldLabels[typeName] == nil {
+		a.fieldLabels[typeName] = map[string][]string{}
+	}
+	a.fieldLabels[typeName][fieldName] = labels
+}
+
+// classifyIdents labels data identifiers and propagates labels along XFER edges so that derived values inherit them
+func (a *Analyzer) classifyIdents(model *linage.PackageModel) {
+	for _, id := range model.Idents {
+		if codeKinds[id.Kind] {
+			continue
+		}
+		name := id.Name
+		if id.Selector != nil {
+			name = id.Selector.Field
+		}
+		ctx := &graph.ClassifyContext{Package: id.Package, Name: name, Type: id.Type}
+		for key, value := range id.Annotation {
+			ctx.Annotation += "@" + key
+			if value != "" {
+				ctx.Annotation += "(" + value + ")"
+			}
+			ctx.Annotation += " "
+		}
+		id.Labels = graph.AddLabels(id.Labels, a.classifier.Classify(ctx)...)
+	}
+	next := map[*linage.Identifier][]*linage.Identifier{}
+	var queue []*linage.Identifier
+	for _, edge := range model.DataFlows {
+		if edge.Kind != linage.Xfer || edge.Src == edge.Dst {
+			continue
+		}
+		next[edge.Src] = append(next[edge.Src], edge.Dst)
+		if len(edge.Src.Labels) > 0 {
+			queue = append(queue, edge.Src)
+		}
+	}
+	for len(queue) > 0 {
+		src := queue[0]
+		queue = queue[1:]
+		for _, dst := range next[src] {
+			before := len(dst.Labels)
+			if dst.Labels = graph.AddLabels(dst.Labels, src.Labels...); len(dst.Labels) != before {
+				queue = append(queue, dst)
+			}
+		}
+	}
+}
+
+// SensitiveSink describes a logging or external call receiving labeled data
+type SensitiveSink struct {
+	Ref    string   `json:"ref"`  // qualified callee, e.g. log.Printf
+	Kind   string   `json:"kind"` // logging or external
+	Labels []string `json:"labels"`
+	File   string   `json:"file,omitempty"`
+	Line   int      `json:"line,omitempty"`
+	Scope  string   `json:"scope"`
+}
+
+// SensitivityReport summarizes labeled identifiers per package and sinks reached by labeled data
+type SensitivityReport struct {
+	Packages map[string]map[string]int `json:"packages"` // package -> label -> labeled identifier count
+	Sinks    []*SensitiveSink          `json:"sinks"`
+}
+
+// JSON renders the report as indented JSON
+func (r *SensitivityReport) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// NewSensitivityReport builds sensitivity report from identifier labels and CALL edges of labeled callees
+func NewSensitivityReport(model *linage.PackageModel) *SensitivityReport {
+	report := &SensitivityReport{Packages: map[string]map[string]int{}}
+	for _, id := range model.Idents {
+		if codeKinds[id.Kind] || len(id.Labels) == 0 {
+			continue
+		}
+		counts := report.Packages[id.Package]
+		if counts == nil {
+			counts = map[string]int{}
+			report.Packages[id.Package] = counts
+		}
+		for _, label := range id.Labels {
+			counts[label]++
+		}
+	}
+	seen := map[string]bool{}
+	for _, edge := range model.DataFlows {
+		if edge.Kind != linage.Call || !graph.IsSensitive(edge.Src.Labels) || seen[edge.Src.ID] {
+			continue
+		}
+		ref, _ := edge.Attributes[linage.CallRefAttribute].(string)
+		sink := &SensitiveSink{Ref: ref, Labels: edge.Src.Labels, File: edge.Src.File, Scope: edge.Scope}
+		switch {
+		case loggingCall.MatchString(ref):
+			sink.Kind = "logging"
+		case edge.CallKind() == linage.CallExternal:
+			sink.Kind = "external"
+		default:
+			continue
+		}
+		if edge.Src.Node != nil {
+			sink.Line = int(edge.Src.Node.StartPoint().Row) + 1
+		}
+		seen[edge.Src.ID] = true
+		report.Sinks = append(report.Sinks, sink)
+	}
+	sort.Slice(report.Sinks, func(i, j int) bool {
+		if report.Sinks[i].File != report.Sinks[j].File {
+			return report.Sinks[i].File < report.Sinks[j].File
+		}
+		return report.Sinks[i].Line < report.Sinks[j].Line
+	})
+	return report
+}
@@ -0,0 +1,166 @@
+package analyzer
+
+import "github.com/viant/linager/analyzer/linage"
+
+// flowGroup holds direct XFER edges sharing a source and scope, they share summary edges
+type flowGroup struct {
+	src    *linage.Identifier
+	scope  string
+	dsts   []int     // direct destination node indexes
+	scores []float64 // confidence of direct edges by dsts position
+}
+
+// reachSet holds nodes reachable from a node, released once all groups starting at the node are summarized
+type reachSet struct {
+	nodes     []int
+	scores    []float64 // confidence of the path reaching a node by nodes position
+	truncated bool
+	uses      int
+}
+
+// flowGraph indexes identifiers of XFER edges with deduplicated adjacency
+type flowGraph struct {
+	nodes     []*linage.Identifier
+	index     map[string]int
+	adjacency [][]int
+	scores    [][]float64 // highest confidence of XFER edges by adjacency position
+}
+
+func (g *flowGraph) node(id *linage.Identifier) int {
+	if idx, ok := g.index[id.ID]; ok {
+		return idx
+	}
+	g.index[id.ID] = len(g.nodes)
+	g.nodes = append(g.nodes, id)
+	g.adjacency = append(g.adjacency, nil)
+	g.scores = append(g.scores, nil)
+	return len(g.nodes) - 1
+}
+
+// computeTransitiveClosure adds summary XFER edges per-call-site, preserving original scope context: for each direct
+// XFER src->dst, src->next edges are added in the same scope for every next reachable from dst. Reachability is computed
+// once per node over deduplicated adjacency, edges already present are not repeated and depth and edge count limits
+// mark the model as truncated. Edges not scored by a heuristic get confidence 1, summary edges multiply confidences of
+// the direct edge and the breadth-first path they compose, keeping the highest product across direct edges.
+func (a *Analyzer) computeTransitiveClosure(model *linage.PackageModel) {
+	graph := &flowGraph{index: map[string]int{}}
+	linked := map[[2]int]int{} // adjacency position of linked nodes
+	var groups []*flowGroup
+	groupIndex := map[[2]string]int{}
+	reach := map[int]*reachSet{}
+	for _, e := range model.DataFlows {
+		if e.Confidence == 0 {
+			e.Confidence = 1
+		}
+		if e.Kind != linage.Xfer {
+			continue
+		}
+		src, dst := graph.node(e.Src), graph.node(e.Dst)
+		link := [2]int{src, dst}
+		if position, ok := linked[link]; !ok {
+			linked[link] = len(graph.adjacency[src])
+			graph.adjacency[src] = append(graph.adjacency[src], dst)
+			graph.scores[src] = append(graph.scores[src], e.Confidence)
+		} else if e.Confidence > graph.scores[src][position] {
+			graph.scores[src][position] = e.Confidence
+		}
+		key := [2]string{e.Src.ID, e.Scope}
+		idx, ok := groupIndex[key]
+		if !ok {
+			idx = len(groups)
+			groupIndex[key] = idx
+			groups = append(groups, &flowGroup{src: e.Src, scope: e.Scope})
+		}
+		groups[idx].dsts = append(groups[idx].dsts, dst)
+		groups[idx].scores = append(groups[idx].scores, e.Confidence)
+		if reach[dst] == nil {
+			reach[dst] = &reachSet{}
+		}
+		reach[dst].uses++
+	}
+
+	var additional []*linage.DataFlowEdge
+	marks := make([]int, len(graph.nodes))     // group number + 1 of nodes already linked from the group source
+	summaries := make([]int, len(graph.nodes)) // additional position of summary edges to marked nodes
+	visited := make([]int, len(graph.nodes))
+	scores := make([]float64, len(graph.nodes))
+	visit := 0
+outer:
+	for i, group := range groups {
+		for _, dst := range group.dsts {
+			marks[dst] = i + 1
+			summaries[dst] = -1
+		}
+		for j, dst := range group.dsts {
+			set := reach[dst]
+			if set.nodes == nil && !set.truncated {
+				visit++
+				set.nodes, set.scores, set.truncated = a.reachable(graph, dst, visited, scores, visit)
+			}
+			model.Truncated = model.Truncated || set.truncated
+			for k, next := range set.nodes {
+				confidence := group.scores[j] * set.scores[k]
+				if marks[next] == i+1 {
+					if position := summaries[next]; position != -1 && confidence > additional[position].Confidence {
+						additional[position].Confidence = confidence
+					}
+					continue
+				}
+				if a.maxSummaryEdges > 0 && len(additional) >= a.maxSummaryEdges {
+					model.Truncated = true
+					break outer
+				}
+				marks[next] = i + 1
+				summaries[next] = len(additional)
+				// add a context-sensitive summary edge
+				additional = append(additional, &linage.DataFlowEdge{
+					Src:        group.src,
+					Dst:        graph.nodes[next],
+					Kind:       linage.Xfer,
+					Scope:      group.scope,
+					Confidence: confidence,
+				})
+			}
+			if set.uses--; set.uses == 0 {
+				set.nodes, set.scores = nil, nil
+			}
+		}
+	}
+	model.DataFlows = append(model.DataFlows, additional...)
+}
+
+// reachable returns nodes reachable from start in breadth-first order, excluding start; with a closure depth limit only
+// nodes within maxClosureDepth-1 hops are returned (a summary edge spans one more hop) and truncated reports omitted
+// nodes. Returned scores hold the confidence product of the first breadth-first path reaching each node. Nodes with
+// visited set to visit are already reached, scores is indexed by node and reused across calls.
+func (a *Analyzer) reachable(graph *flowGraph, start int, visited []int, scores []float64, visit int) ([]int, []float64, bool) {
+	result := []int{}
+	truncated := false
+	visited[start] = visit
+	scores[start] = 1
+	frontier := []int{start}
+	for depth := 1; len(frontier) > 0; depth++ {
+		var next []int
+		for _, cur := range frontier {
+			for position, dst := range graph.adjacency[cur] {
+				if visited[dst] == visit {
+					continue
+				}
+				if a.maxClosureDepth > 0 && depth >= a.maxClosureDepth {
+					truncated = true
+					continue
+				}
+				visited[dst] = visit
+				scores[dst] = scores[cur] * graph.scores[cur][position]
+				result = append(result, dst)
+				next = append(next, dst)
+			}
+		}
+		frontier = next
+	}
+	resultScores := make([]float64, len(result))
+	for i, node := range result {
+		resultScores[i] = scores[node]
+	}
+	return result, resultScores, truncated
+}
@@ -0,0 +1,335 @@
+package analyzer
+
+import (
+	"fmt"
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/viant/linager/analyzer/linage"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ConfigUsage describes a configuration key and identifiers its value reaches
+type ConfigUsage struct {
+	Key         string               `json:"key"`
+	Source      string               `json:"source"`               // env, viper, flag or tag
+	Dynamic     bool                 `json:"dynamic,omitempty"`    // key built from a non-literal expression
+	Expression  string               `json:"expression,omitempty"` // key expression text for dynamic keys
+	Identifiers []*linage.Identifier `json:"identifiers,omitempty"`
+	Exported    []string             `json:"exported,omitempty"` // exported symbols reached downstream
+}
+
+// ConfigPlugin traces configuration reads (os.Getenv, viper.Get*, flag.*, env struct tags)
+// by emitting XFER edges from a synthetic "config" identifier into receiving identifiers.
+type ConfigPlugin struct {
+	keys    map[string]*linage.Identifier
+	pending map[string]*linage.Identifier // receiving node key -> config identifier
+}
+
+// NewConfigPlugin creates a configuration key tracing plugin
+func NewConfigPlugin() *ConfigPlugin {
+	return &ConfigPlugin{
+		keys:    map[string]*linage.Identifier{},
+		pending: map[string]*linage.Identifier{},
+	}
+}
+
+// NodeTypes limits dispatch to assignments, type specs and calls
+func (p *ConfigPlugin) NodeTypes() []string {
+	return []string{"short_var_declaration", "assignment_statement", "type_spec", "call_expression"}
+}
+
+// BeforeWalk records config reads in assignments, var specs and standalone calls
+func (p *ConfigPlugin) BeforeWalk(n *sitter.Node, src []byte, scope *linage.Scope, model *linage.PackageModel) {
+	switch n.Type() {
+	case "short_var_declaration", "assignment_statement":
+		p.matchAssignment(n.ChildByFieldName("left"), n.ChildByFieldName("right"), src, scope, model)
+	case "type_spec":
+		p.matchEnvTags(n, src, scope, model)
+	case "call_expression":
+		if key, receiver := p.configRead(n, src, model); key != nil && receiver != nil {
+			p.expect(receiver, key, src, scope, model)
+		}
+	}
+}
+
+// AfterResolveIdent links pending receivers to config keys
+func (p *ConfigPlugin) AfterResolveIdent(n *sitter.Node, id *linage.Identifier, scope *linage.Scope, model *linage.PackageModel) {
+	if key, ok := p.pending[p.nodeKey(n, scope, model)]; ok {
+		model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: key, Dst: id, Kind: linage.Xfer, Scope: scope.ID})
+	}
+}
+
+// Usage aggregates configuration keys with every identifier and exported symbol they reach
+func (p *ConfigPlugin) Usage(model *linage.PackageModel) []*ConfigUsage {
+	adj := map[string][]*linage.Identifier{}
+	for _, e := range model.DataFlows {
+		if e.Kind == linage.Xfer {
+			adj[e.Src.ID] = append(adj[e.Src.ID], e.Dst)
+		}
+	}
+	// selector identifiers are created per position; treat same-file selector paths as aliases
+	aliases := map[string][]*linage.Identifier{}
+	for _, id := range model.Idents {
+		if id.Selector != nil {
+			path := id.File + "#" + selectorPath(id.Selector)
+			aliases[path] = append(aliases[path], id)
+		}
+	}
+
+	var result []*ConfigUsage
+	for _, key := range p.keys {
+		usage := &ConfigUsage{Key: key.Name, Source: key.Type}
+		if expr, ok := key.Annotation["dynamic"]; ok {
+			usage.Dynamic = true
+			usage.Expression = expr
+		}
+		visited := map[string]bool{key.ID: true}
+		exported := map[string]bool{}
+		queue := []*linage.Identifier{key}
+		for len(queue) > 0 {
+			cur := queue[0]
+			queue = queue[1:]
+			next := adj[cur.ID]
+			if cur.Selector != nil {
+				next = append(next, aliases[cur.File+"#"+selectorPath(cur.Selector)]...)
+			}
+			for _, id := range next {
+				if visited[id.ID] {
+					continue
+				}
+				visited[id.ID] = true
+				usage.Identifiers = append(usage.Identifiers, id)
+				if isExportedName(id.Name) {
+					exported[id.Name] = true
+				}
+				queue = append(queue, id)
+			}
+		}
+		sort.Slice(usage.Identifiers, func(i, j int) bool { return usage.Identifiers[i].ID < usage.Identifiers[j].ID })
+		for name := range exported {
+			usage.Exported = append(usage.Exported, name)
+		}
+		sort.Strings(usage.Exported)
+		result = append(result, usage)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Key == result[j].Key {
+			return result[i].Source < result[j].Source
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
+
+// matchAssignment maps config reads on the right side to receivers on the left side
+func (p *ConfigPlugin) matchAssignment(left, right *sitter.Node, src []byte, scope *linage.Scope, model *linage.PackageModel) {
+	if left == nil || right == nil {
+		return
+	}
+	receivers := namedChildren(left)
+	values := namedChildren(right)
+	for i, value := range values {
+		if value.Type() != "call_expression" {
+			continue
+		}
+		key, _ := p.configRead(value, src, model)
+		if key == nil || i >= len(receivers) {
+			continue
+		}
+		p.expect(receivers[i], key, src, scope, model)
+	}
+}
+
+// matchEnvTags links struct fields tagged with env:"KEY" to config keys
+func (p *ConfigPlugin) matchEnvTags(n *sitter.Node, src []byte, scope *linage.Scope, model *linage.PackageModel) {
+	nameNode := n.ChildByFieldName("name")
+	typeNode := n.ChildByFieldName("type")
+	if nameNode == nil || typeNode == nil || typeNode.Type() != "struct_type" {
+		return
+	}
+	typeName := string(src[nameNode.StartByte():nameNode.EndByte()])
+	file := strings.TrimPrefix(topFileScope(scope).ID, model.Path+":")
+	for _, list := range namedChildren(typeNode) {
+		for _, decl := range namedChildren(list) {
+			tagNode := decl.ChildByFieldName("tag")
+			if decl.Type() != "field_declaration" || tagNode == nil {
+				continue
+			}
+			tag := reflect.StructTag(strings.Trim(string(src[tagNode.StartByte():tagNode.EndByte()]), "`"))
+			env := strings.Split(tag.Get("env"), ",")[0]
+			if env == "" {
+				continue
+			}
+			key := p.configIdent("tag", env, false, model)
+			for _, field := range structFieldIdents(typeName, file, decl, src, model) {
+				model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: key, Dst: field, Kind: linage.Xfer, Scope: scope.ID})
+			}
+		}
+	}
+}
+
+// structFieldIdents returns identifiers of fields declared by a struct field declaration, keyed by name position
+func structFieldIdents(typeName, file string, decl *sitter.Node, src []byte, model *linage.PackageModel) []*linage.Identifier {
+	var fieldType string
+	if t := decl.ChildByFieldName("type"); t != nil {
+		fieldType = string(src[t.StartByte():t.EndByte()])
+	}
+	var result []*linage.Identifier
+	for _, fieldNode := range namedChildren(decl) {
+		if fieldNode.Type() != "field_identifier" {
+			continue
+		}
+		fieldName := string(src[fieldNode.StartByte():fieldNode.EndByte()])
+		id := fmt.Sprintf("%s::%s::%d", model.Path, file, fieldNode.StartByte())
+		field, ok := model.Idents[id]
+		if !ok {
+			field = &linage.Identifier{
+				ID:        id,
+				Name:      fieldName,
+				Kind:      "field",
+				Package:   model.Path,
+				File:      file,
+				StartByte: fieldNode.StartByte(),
+				Type:      fieldType,
+				Selector:  &linage.Selector{Field: fieldName, Parent: &linage.Selector{Field: typeName}},
+			}
+			model.Idents[id] = field
+		}
+		result = append(result, field)
+	}
+	return result
+}
+
+// expect registers receiver node to be linked with a config key once resolved
+func (p *ConfigPlugin) expect(receiver *sitter.Node, key *linage.Identifier, src []byte, scope *linage.Scope, model *linage.PackageModel) {
+	switch receiver.Type() {
+	case "unary_expression":
+		if operand := receiver.ChildByFieldName("operand"); operand != nil {
+			p.expect(operand, key, src, scope, model)
+		}
+		return
+	case "selector_expression":
+		if field := receiver.ChildByFieldName("field"); field != nil {
+			receiver = field
+		}
+	case "identifier":
+		// reassigned variables resolve to existing identifiers without AfterResolveIdent callback
+		name := string(src[receiver.StartByte():receiver.EndByte()])
+		if existing := scope.Find(name); existing != nil && existing.StartByte != receiver.StartByte() {
+			model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: key, Dst: existing, Kind: linage.Xfer, Scope: scope.ID})
+			return
+		}
+	}
+	p.pending[p.nodeKey(receiver, scope, model)] = key
+}
+
+// flagDefinitions are flag package functions defining a command line flag, other functions (e.g. flag.Set,
+// flag.Lookup, flag.Parse) do not read a flag value
+var flagDefinitions = map[string]bool{
+	"Bool":        true,
+	"BoolFunc":    true,
+	"BoolVar":     true,
+	"Duration":    true,
+	"DurationVar": true,
+	"Float64":     true,
+	"Float64Var":  true,
+	"Func":        true,
+	"Int":         true,
+	"Int64":       true,
+	"Int64Var":    true,
+	"IntVar":      true,
+	"String":      true,
+	"StringVar":   true,
+	"TextVar":     true,
+	"Uint":        true,
+	"Uint64":      true,
+	"Uint64Var":   true,
+	"UintVar":     true,
+	"Var":         true,
+}
+
+// configRead returns config identifier for a recognized config call and an optional receiver argument
+func (p *ConfigPlugin) configRead(call *sitter.Node, src []byte, model *linage.PackageModel) (*linage.Identifier, *sitter.Node) {
+	fn := call.ChildByFieldName("function")
+	if fn == nil || fn.Type() != "selector_expression" {
+		return nil, nil
+	}
+	operand := fn.ChildByFieldName("operand")
+	field := fn.ChildByFieldName("field")
+	if operand == nil || field == nil {
+		return nil, nil
+	}
+	pkg := string(src[operand.StartByte():operand.EndByte()])
+	method := string(src[field.StartByte():field.EndByte()])
+	args := namedChildren(call.ChildByFieldName("arguments"))
+
+	keyIndex := 0
+	var receiver *sitter.Node
+	var source string
+	switch {
+	case pkg == "os" && (method == "Getenv" || method == "LookupEnv"):
+		source = "env"
+	case pkg == "viper" && strings.HasPrefix(method, "Get"):
+		source = "viper"
+	case pkg == "flag" && flagDefinitions[method]:
+		source = "flag"
+		if strings.HasSuffix(method, "Var") {
+			// e.g. flag.StringVar(&cfg.Name, "name", "", "usage") writes the key value into the first argument
+			keyIndex = 1
+			if len(args) > 0 {
+				receiver = args[0]
+			}
+		}
+	default:
+		return nil, nil
+	}
+	if keyIndex >= len(args) {
+		return nil, nil
+	}
+	keyNode := args[keyIndex]
+	text := string(src[keyNode.StartByte():keyNode.EndByte()])
+	switch keyNode.Type() {
+	case "interpreted_string_literal", "raw_string_literal":
+		return p.configIdent(source, strings.Trim(text, "`\""), false, model), receiver
+	}
+	return p.configIdent(source, text, true, model), receiver
+}
+
+// configIdent returns a shared synthetic identifier for a config key
+func (p *ConfigPlugin) configIdent(source, key string, dynamic bool, model *linage.PackageModel) *linage.Identifier {
+	id := fmt.Sprintf("config::%s::%s", source, key)
+	if existing, ok := p.keys[id]; ok {
+		if _, ok := model.Idents[id]; !ok {
+			model.Idents[id] = existing
+		}
+		return existing
+	}
+	ident := &linage.Identifier{ID: id, Name: key, Kind: "config", Type: source}
+	if dynamic {
+		ident.Annotation = linage.Annotations{"dynamic": key}
+	}
+	p.keys[id] = ident
+	model.Idents[id] = ident
+	return ident
+}
+
+func (p *ConfigPlugin) nodeKey(n *sitter.Node, scope *linage.Scope, model *linage.PackageModel) string {
+	return fmt.Sprintf("%s::%s::%d", model.Path, topFileScope(scope).ID, n.StartByte())
+}
+
+// selectorPath renders a selector chain as dotted path (e.g. cfg.Port)
+func selectorPath(sel *linage.Selector) string {
+	if sel == nil {
+		return ""
+	}
+	if sel.Parent == nil {
+		return sel.Field
+	}
+	return selectorPath(sel.Parent) + "." + sel.Field
+}
+
+// isExportedName reports whether name starts with an upper-case letter
+func isExportedName(name string) bool {
+	return name != "" && strings.ToUpper(name[:1]) == name[:1] && strings.ToLower(name[:1]) != name[:1]
+}
@@ -0,0 +1,225 @@
+package analyzer
+
+import (
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/viant/linager/inspector/graph"
+	"github.com/viant/linager/inspector/treesitter"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// DefaultMaxDependencyPackages is the default maximum number of dependency packages loaded for typing
+const DefaultMaxDependencyPackages = 64
+
+// predeclaredTypes are Go types not qualified by a dependency import path
+var predeclaredTypes = map[string]bool{
+	"any": true, "bool": true, "byte": true, "comparable": true, "complex64": true, "complex128": true, "error": true,
+	"float32": true, "float64": true, "int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"rune": true, "string": true, "uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true, "uintptr": true,
+}
+
+// dependencyField returns the declared field type of a struct type of a dependency package (e.g. *s3.PutObjectInput)
+// with the field reference namespaced by the dependency import path; the package is loaded on first use
+func (a *Analyzer) dependencyField(typeName, field string) (string, graph.Ref, bool) {
+	if len(a.dependencyRoots) == 0 {
+		return "", graph.Ref{}, false
+	}
+	importPath, name := a.dependencyType(baseTypeName(typeName))
+	if importPath == "" {
+		return "", graph.Ref{}, false
+	}
+	a.loadDependency(importPath)
+	fieldType, ok := a.dependencyTypes[importPath+"."+name][field]
+	return fieldType, graph.NewTypeRef(importPath, name).WithMember(field), ok
+}
+
+// dependencyType splits a qualified type name into an import path and a type name, the qualifier is an import alias
+// of the current file or an import path of a field type of a loaded dependency type
+func (a *Analyzer) dependencyType(typeName string) (string, string) {
+	index := strings.LastIndex(typeName, ".")
+	if index <= 0 || index < strings.LastIndex(typeName, "/") {
+		return "", ""
+	}
+	qualifier, name := typeName[:index], typeName[index+1:]
+	if importPath, ok := a.importAliases[qualifier]; ok {
+		return importPath, name
+	}
+	if strings.Contains(qualifier, "/") {
+		return qualifier, name
+	}
+	return "", ""
+}
+
+// loadDependency registers struct fields of a dependency package found under a dependency root, only type specs
+// are parsed: dependency code is not walked, so it yields no identifiers or flows
+func (a *Analyzer) loadDependency(importPath string) {
+	if _, ok := a.dependencyPackages[importPath]; ok {
+		return
+	}
+	limit := a.maxDependencyPackages
+	if limit == 0 {
+		limit = DefaultMaxDependencyPackages
+	}
+	if len(a.dependencyPackages) >= limit {
+		return
+	}
+	dir := a.dependencyDir(importPath)
+	a.dependencyPackages[importPath] = dir != ""
+	if dir == "" || a.language == nil {
+		return
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	parser := sitter.NewParser()
+	parser.SetLanguage(a.language)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != ".go" || strings.HasSuffix(name, "_test.go") || a.limits.CheckMode(name, entry.Type()) != nil {
+			continue
+		}
+		code, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil || a.limits.CheckSize(filepath.Join(dir, name), int64(len(code))) != nil {
+			continue
+		}
+		tree, err := treesitter.Parse(parser, code, a.limits.ParseDeadline())
+		if err != nil || tree == nil {
+			continue
+		}
+		a.dependencyStructs(tree.RootNode(), code, importPath)
+	}
+}
+
+// dependencyStructs registers struct fields of a dependency file, field types are qualified by import paths
+func (a *Analyzer) dependencyStructs(root *sitter.Node, src []byte, importPath string) {
+	imports := map[string]string{}
+	for _, decl := range namedChildren(root) {
+		if decl.Type() == "import_declaration" {
+			fileImports(decl, src, imports)
+		}
+	}
+	types := map[string]*sitter.Node{}
+	collectTypeSpecs(root, src, types)
+	for name, spec := range types {
+		body := spec.ChildByFieldName("type")
+		if body == nil || body.Type() != "struct_type" {
+			continue
+		}
+		fields := map[string]string{}
+		for _, list := range namedChildren(body) {
+			for _, decl := range namedChildren(list) {
+				typeNode := decl.ChildByFieldName("type")
+				if decl.Type() != "field_declaration" || typeNode == nil {
+					continue
+				}
+				fieldType := qualifiedType(typeNode, src, importPath, imports)
+				for _, nameNode := range namedChildren(decl) {
+					if nameNode.Type() == "field_identifier" {
+						fields[string(src[nameNode.StartByte():nameNode.EndByte()])] = fieldType
+					}
+				}
+			}
+		}
+		a.dependencyTypes[importPath+"."+name] = fields
+	}
+}
+
+// dependencyDir returns the directory of an import path under dependency roots: root/importPath (vendor or plain
+// directories) or root/module@version/subpath (GOMODCACHE, upper case letters escaped as !lower)
+func (a *Analyzer) dependencyDir(importPath string) string {
+	for _, root := range a.dependencyRoots {
+		if dir := filepath.Join(root, filepath.FromSlash(importPath)); isDir(dir) {
+			return dir
+		}
+		escaped := escapeModulePath(importPath)
+		elements := strings.Split(escaped, "/")
+		for i := len(elements); i > 0; i-- {
+			matches, _ := filepath.Glob(filepath.Join(root, filepath.FromSlash(strings.Join(elements[:i], "/"))+"@*"))
+			if len(matches) == 0 {
+				continue
+			}
+			if dir := filepath.Join(append([]string{matches[len(matches)-1]}, elements[i:]...)...); isDir(dir) {
+				return dir
+			}
+		}
+	}
+	return ""
+}
+
+// fileImports maps import aliases of an import declaration to import paths
+func fileImports(decl *sitter.Node, src []byte, imports map[string]string) {
+	var specs []*sitter.Node
+	for _, child := range namedChildren(decl) {
+		if child.Type() == "import_spec_list" {
+			specs = append(specs, namedChildren(child)...)
+		} else if child.Type() == "import_spec" {
+			specs = append(specs, child)
+		}
+	}
+	for _, spec := range specs {
+		pathNode := spec.ChildByFieldName("path")
+		if pathNode == nil {
+			continue
+		}
+		importPath := strings.Trim(string(src[pathNode.StartByte():pathNode.EndByte()]), "`\"")
+		alias := importPath[strings.LastIndex(importPath, "/")+1:]
+		if nameNode := spec.ChildByFieldName("name"); nameNode != nil {
+			alias = string(src[nameNode.StartByte():nameNode.EndByte()])
+		}
+		imports[alias] = importPath
+	}
+}
+
+// qualifiedType renders a dependency field type with package level type names qualified by import paths,
+// e.g. *Owner -> *example.com/s3.Owner, aws.Config -> github.com/aws/aws.Config
+func qualifiedType(n *sitter.Node, src []byte, importPath string, imports map[string]string) string {
+	switch n.Type() {
+	case "type_identifier":
+		name := string(src[n.StartByte():n.EndByte()])
+		if predeclaredTypes[name] {
+			return name
+		}
+		return importPath + "." + name
+	case "qualified_type":
+		pkgNode, nameNode := n.ChildByFieldName("package"), n.ChildByFieldName("name")
+		if pkgNode != nil && nameNode != nil {
+			if path, ok := imports[string(src[pkgNode.StartByte():pkgNode.EndByte()])]; ok {
+				return path + "." + string(src[nameNode.StartByte():nameNode.EndByte()])
+			}
+		}
+	}
+	if n.NamedChildCount() == 0 {
+		return string(src[n.StartByte():n.EndByte()])
+	}
+	builder := strings.Builder{}
+	offset := n.StartByte()
+	for _, child := range namedChildren(n) {
+		builder.Write(src[offset:child.StartByte()])
+		builder.WriteString(qualifiedType(child, src, importPath, imports))
+		offset = child.EndByte()
+	}
+	builder.Write(src[offset:n.EndByte()])
+	return builder.String()
+}
+
+// escapeModulePath escapes upper case letters of a module path as GOMODCACHE does, e.g. BurntSushi -> !burnt!sushi
+func escapeModulePath(path string) string {
+	builder := strings.Builder{}
+	for _, r := range path {
+		if unicode.IsUpper(r) {
+			builder.WriteRune('!')
+			r = unicode.ToLower(r)
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+// isDir reports whether path is an existing directory
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,149 @@
+package analyzer
+
+import (
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/viant/linager/analyzer/linage"
+	"sort"
+	"strings"
+)
+
+// DefaultMaxDevirtualTargets is the default maximum number of implementations an interface method call fans out to
+const DefaultMaxDevirtualTargets = 8
+
+// interfaceMethods returns method names of a Go interface type, embedded interfaces contribute their known methods
+func (a *Analyzer) interfaceMethods(n *sitter.Node, src []byte) []string {
+	var result []string
+	for _, elem := range namedChildren(n) {
+		if nameNode := elem.ChildByFieldName("name"); nameNode != nil {
+			result = append(result, string(src[nameNode.StartByte():nameNode.EndByte()]))
+			continue
+		}
+		// embedded interface, e.g. type ReadWriter interface { Reader; Write() }
+		embedded := strings.TrimSpace(string(src[elem.StartByte():elem.EndByte()]))
+		result = append(result, a.interfaces[embedded]...)
+	}
+	return result
+}
+
+// devirtualize resolves a call of an interface method to implementations of the package: the implementation assigned
+// to the receiver variable when all its assignments are of one concrete type (narrowed), otherwise every type whose
+// method set covers the interface, up to the configured limit; nil for non interface types or too many implementations
+func (a *Analyzer) devirtualize(iface, method string, receiver *linage.Identifier, model *linage.PackageModel) ([]*linage.Identifier, bool) {
+	ifaceMethods, ok := a.interfaces[iface]
+	if !ok || !containsString(ifaceMethods, method) || a.maxDevirtualTargets < 0 {
+		return nil, false
+	}
+	implementations := a.implementations(iface, ifaceMethods, model)
+	if concrete := a.concreteType(receiver, model); concrete != "" {
+		if target, ok := implementations[concrete]; ok {
+			return []*linage.Identifier{target[method]}, true
+		}
+	}
+	limit := a.maxDevirtualTargets
+	if limit == 0 {
+		limit = DefaultMaxDevirtualTargets
+	}
+	if len(implementations) > limit {
+		return nil, false
+	}
+	var names []string
+	for name := range implementations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var result []*linage.Identifier
+	for _, name := range names {
+		result = append(result, implementations[name][method])
+	}
+	return result, false
+}
+
+// implementations returns method identifiers by type name of package types implementing all interface methods
+func (a *Analyzer) implementations(iface string, ifaceMethods []string, model *linage.PackageModel) map[string]map[string]*linage.Identifier {
+	methods := map[string]map[string]*linage.Identifier{}
+	for _, scope := range model.Scopes {
+		if scope.Kind != "file" {
+			continue
+		}
+		for symbol, id := range scope.Symbols {
+			typeName, name, ok := strings.Cut(symbol, ".")
+			if !ok || id.Kind != "method" || typeName == iface {
+				continue
+			}
+			if methods[typeName] == nil {
+				methods[typeName] = map[string]*linage.Identifier{}
+			}
+			methods[typeName][name] = id
+		}
+	}
+	for typeName, methodSet := range methods {
+		for _, name := range ifaceMethods {
+			if _, ok := methodSet[name]; !ok {
+				delete(methods, typeName)
+				break
+			}
+		}
+	}
+	return methods
+}
+
+// concreteType returns the single concrete type flowing into a receiver variable (e.g. var s Store = pg,
+// var s Store = NewPostgresStore()), empty when unknown or when assignments disagree
+func (a *Analyzer) concreteType(receiver *linage.Identifier, model *linage.PackageModel) string {
+	if receiver == nil {
+		return ""
+	}
+	concrete := ""
+	for _, edge := range model.DataFlows {
+		if edge.Kind != linage.Xfer || edge.Dst != receiver || edge.Src == nil || edge.Src == receiver {
+			continue
+		}
+		typeName := edge.Src.Type
+		if summary, ok := a.funcSummaries[edge.Src]; ok {
+			// function value flowing into the variable, e.g. var s Store = NewPostgresStore()
+			typeName = ""
+			if len(summary.Results) > 0 {
+				typeName = summary.Results[0]
+			}
+		}
+		typeName = baseTypeName(strings.TrimLeft(typeName, "&*"))
+		if typeName == "" || (concrete != "" && concrete != typeName) {
+			return ""
+		}
+		concrete = typeName
+	}
+	return concrete
+}
+
+// markPossibleSince marks CALL and XFER edges added from the start index by a call fanned out to possible implementations
+func (a *Analyzer) markPossibleSince(model *linage.PackageModel, start int) {
+	for _, edge := range model.DataFlows[start:] {
+		if edge.Kind == linage.Read {
+			continue
+		}
+		a.markHeuristic(edge, linage.ProvenanceVirtual)
+		edge.Attributes[linage.PossibleAttribute] = true
+	}
+}
+
+// markHeuristic records the heuristic an edge was emitted by and scores the edge with the heuristic confidence
+func (a *Analyzer) markHeuristic(edge *linage.DataFlowEdge, provenance string) {
+	if edge.Attributes == nil {
+		edge.Attributes = map[string]interface{}{}
+	}
+	edge.Attributes[linage.ProvenanceAttribute] = provenance
+	confidence, ok := a.confidence[provenance]
+	if !ok {
+		confidence = linage.DefaultConfidence[provenance]
+	}
+	edge.Confidence = confidence
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+package analyzer
+
+import (
+	"github.com/viant/linager/analyzer/linage"
+	"github.com/viant/linager/inspector/graph"
+	"path"
+	"sort"
+	"strings"
+)
+
+// LineageRetarget describes a function data point whose edges moved to a moved or renamed function
+type LineageRetarget struct {
+	Kind       string  `json:"kind"` // graph.ChangeMoved or graph.ChangeRenamed
+	Old        string  `json:"old"`  // previous data point ID
+	New        string  `json:"new"`  // current data point ID
+	Confidence float64 `json:"confidence"`
+	Reads      int     `json:"reads,omitempty"`
+	Writes     int     `json:"writes,omitempty"`
+	Calls      int     `json:"calls,omitempty"`
+}
+
+// LineageDiff lists data points added, removed or retargeted between two lineage versions
+type LineageDiff struct {
+	Added      []string           `json:"added,omitempty"`
+	Removed    []string           `json:"removed,omitempty"`
+	Retargeted []*LineageRetarget `json:"retargeted,omitempty"`
+}
+
+// DiffLineage compares data points of two project versions, function data points matching moved or renamed
+// functions of the project diff are reported as retargeted instead of removed and added
+func DiffLineage(prev, next []*linage.DataPoint, diff *graph.ProjectDiff) *LineageDiff {
+	result := &LineageDiff{}
+	prevByID := map[string]*linage.DataPoint{}
+	for _, point := range prev {
+		prevByID[relativeID(point)] = point
+	}
+	nextByID := map[string]*linage.DataPoint{}
+	nextByKey := map[string]*linage.DataPoint{}
+	for _, point := range next {
+		nextByID[relativeID(point)] = point
+		if key := functionPointKey(point); key != "" {
+			nextByKey[key] = point
+		}
+	}
+	var changes []*graph.FunctionChange
+	if diff != nil {
+		changes = append(append(changes, diff.Moved...), diff.Renamed...)
+	}
+	byOldKey := map[string]*graph.FunctionChange{}
+	for _, change := range changes {
+		byOldKey[functionRefKey(change.Old)] = change
+	}
+
+	retargeted := map[string]bool{}
+	for _, point := range prev {
+		if _, ok := nextByID[relativeID(point)]; ok {
+			continue
+		}
+		change, ok := byOldKey[functionPointKey(point)]
+		if !ok {
+			result.Removed = append(result.Removed, point.ID)
+			continue
+		}
+		target, ok := nextByKey[functionRefKey(change.New)]
+		if !ok || prevByID[relativeID(target)] != nil {
+			result.Removed = append(result.Removed, point.ID)
+			continue
+		}
+		retargeted[target.ID] = true
+		result.Retargeted = append(result.Retargeted, &LineageRetarget{
+			Kind:       change.Kind,
+			Old:        point.ID,
+			New:        target.ID,
+			Confidence: change.Confidence,
+			Reads:      len(target.Reads),
+			Writes:     len(target.Writes),
+			Calls:      len(target.Calls),
+		})
+	}
+	for _, point := range next {
+		if _, ok := prevByID[relativeID(point)]; !ok && !retargeted[point.ID] {
+			result.Added = append(result.Added, point.ID)
+		}
+	}
+	sort.Strings(result.Added)
+	sort.Strings(result.Removed)
+	sort.SliceStable(result.Retargeted, func(i, j int) bool { return result.Retargeted[i].Old < result.Retargeted[j].Old })
+	return result
+}
+
+// relativeID returns a data point ID without the package location prefix so that versions analyzed in different
+// directories compare equal
+func relativeID(point *linage.DataPoint) string {
+	return strings.TrimPrefix(point.ID, point.Package)
+}
+
+// functionPointKey returns file base name and Type.Name symbol of a function data point, empty for other kinds;
+// function identifiers are qualified by their file scope ID (package:file)
+func functionPointKey(point *linage.DataPoint) string {
+	if point.Kind != "func" && point.Kind != "method" {
+		return ""
+	}
+	symbol := strings.TrimPrefix(point.ID, point.File+".")
+	file := point.File[strings.LastIndex(point.File, ":")+1:]
+	return path.Base(file) + ":" + symbol
+}
+
+// functionRefKey returns file base name and Type.Name symbol of a project function
+func functionRefKey(ref *graph.FunctionRef) string {
+	symbol := ref.Name
+	if ref.Type != "" {
+		symbol = ref.Type + "." + ref.Name
+	}
+	return path.Base(ref.File) + ":" + symbol
+}
@@ -0,0 +1,483 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/viant/afs/url"
+	"github.com/viant/linager/analyzer/linage"
+	"github.com/viant/linager/inspector/graph"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Endpoint external touch kinds
+const (
+	EndpointTable         = "table"         // SQL table of a query, see SQLPlugin
+	EndpointConfig        = "config"        // configuration key, see ConfigPlugin
+	EndpointLog           = "log"           // logging call site, see LogPlugin
+	EndpointSerialization = "serialization" // serialization call site, see SerializationPlugin
+)
+
+// receiverExpr matches the receiver name of a method signature, e.g. s of func (s *Store) Find()
+var receiverExpr = regexp.MustCompile(`^func\s*\(\s*(\w+)\s`)
+
+// EndpointHop is a function reached from an endpoint entry with the data its body touches
+type EndpointHop struct {
+	Function string   `json:"function"`           // call graph key, e.g. example.com/app/api.ListOrders
+	Caller   string   `json:"caller,omitempty"`   // call graph key of the function the hop was first reached from
+	Depth    int      `json:"depth"`              // calls from the entry
+	Reads    []string `json:"reads,omitempty"`    // project struct fields read, e.g. OrderRequest.CustomerID
+	Writes   []string `json:"writes,omitempty"`   // project struct fields written
+	External []string `json:"external,omitempty"` // tables, config keys, log sinks and serialization boundaries as kind:name, e.g. table:orders
+}
+
+// TypeTouch lists fields of a project type read or written by an endpoint
+type TypeTouch struct {
+	Package string   `json:"package"`
+	Type    string   `json:"type"`
+	Reads   []string `json:"reads,omitempty"`
+	Writes  []string `json:"writes,omitempty"`
+}
+
+// ExternalTouch is a table, configuration key, log sink or serialization boundary touched by an endpoint
+type ExternalTouch struct {
+	Kind      string   `json:"kind"` // EndpointTable, EndpointConfig, EndpointLog or EndpointSerialization
+	Name      string   `json:"name"`
+	Functions []string `json:"functions"` // call graph keys of touching functions
+}
+
+// EndpointReport lists data touched by an entry function and the project functions it calls transitively
+type EndpointReport struct {
+	Entry     string           `json:"entry"`
+	Hops      []*EndpointHop   `json:"hops"` // breadth first from the entry
+	Types     []*TypeTouch     `json:"types,omitempty"`
+	External  []*ExternalTouch `json:"external,omitempty"`
+	Truncated bool             `json:"truncated,omitempty"` // callees beyond depth were not walked
+}
+
+// endpointFunction is a project function or method with its declaration context
+type endpointFunction struct {
+	pkg      *graph.Package
+	file     *graph.File
+	owner    *graph.Type // receiver type of methods
+	function *graph.Function
+}
+
+// name returns the function name as scoped by the analyzer, e.g. Store.Find
+func (f *endpointFunction) name() string {
+	if f.owner != nil {
+		return f.owner.Name + "." + f.function.Name
+	}
+	return f.function.Name
+}
+
+// typeRef is a project type with its declaration context
+type typeRef struct {
+	pkg   *graph.Package
+	file  *graph.File
+	aType *graph.Type
+}
+
+// EndpointLineage walks the project call graph from an entry function, e.g. an HTTP handler, up to depth calls
+// (unlimited when depth <= 0) and collects project struct fields read or written by reached functions (grouped by
+// owning type) with SQL tables, configuration keys and log sinks of the lineage model. Callees are limited to packages
+// matching import path prefixes when given; the entry is always analyzed.
+func EndpointLineage(project *graph.Project, model *linage.PackageModel, entry graph.Ref, depth int, packages ...string) (*EndpointReport, error) {
+	if project == nil {
+		return nil, &graph.ErrNotFound{Kind: "project", Name: entry.String()}
+	}
+	functions := endpointFunctions(project)
+	key := entry.Package + "." + entry.Function
+	if entry.Type != "" {
+		key = entry.Package + "." + entry.Type + "." + entry.Function
+	}
+	if functions[key] == nil {
+		return nil, &graph.ErrNotFound{Kind: "function", Name: entry.String()}
+	}
+	report := &EndpointReport{Entry: key, Hops: []*EndpointHop{{Function: key}}}
+	calls := project.CallGraph()
+	reached := map[string]bool{key: true}
+	for i := 0; i < len(report.Hops); i++ {
+		hop := report.Hops[i]
+		for _, callee := range calls[hop.Function] {
+			if reached[callee] || functions[callee] == nil || !matchesPackage(functions[callee].pkg, packages) {
+				continue
+			}
+			if depth > 0 && hop.Depth >= depth {
+				report.Truncated = true
+				break
+			}
+			reached[callee] = true
+			report.Hops = append(report.Hops, &EndpointHop{Function: callee, Caller: hop.Function, Depth: hop.Depth + 1})
+		}
+	}
+	if model != nil {
+		touches := &endpointTouches{project: project, model: model, report: report, types: map[*graph.Type]*TypeTouch{}, external: map[string]*ExternalTouch{}}
+		touches.index()
+		for _, hop := range report.Hops {
+			touches.collect(hop, functions[hop.Function])
+		}
+		touches.summarize()
+	}
+	return report, nil
+}
+
+// endpointFunctions indexes project functions and methods by call graph key, see graph.Project.CallGraph
+func endpointFunctions(project *graph.Project) map[string]*endpointFunction {
+	result := map[string]*endpointFunction{}
+	for _, pkg := range project.Packages {
+		prefix := pkg.Ref().Package + "."
+		for _, file := range pkg.FileSet {
+			for _, aType := range file.Types {
+				for _, method := range aType.Methods {
+					result[prefix+aType.Name+"."+method.Name] = &endpointFunction{pkg: pkg, file: file, owner: aType, function: method}
+				}
+			}
+			for _, function := range file.Functions {
+				result[prefix+function.Name] = &endpointFunction{pkg: pkg, file: file, function: function}
+			}
+		}
+	}
+	return result
+}
+
+// matchesPackage reports whether a package import path starts with one of prefixes, any package matches no prefixes
+func matchesPackage(pkg *graph.Package, prefixes []string) bool {
+	if len(prefixes) == 0 {
+		return true
+	}
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(pkg.ImportPath, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// samePackage reports whether a project import path and an analyzed package path (e.g. a directory URL) denote the
+// same package, paths rooted differently (module path, directory) match by suffix
+func samePackage(importPath, modelPath string) bool {
+	if strings.Contains(modelPath, "://") {
+		modelPath = url.Path(modelPath)
+	}
+	return importPath == modelPath || strings.HasSuffix(importPath, "/"+modelPath) || strings.HasSuffix(modelPath, "/"+importPath)
+}
+
+// endpointTouches collects data touched by endpoint hops
+type endpointTouches struct {
+	project  *graph.Project
+	model    *linage.PackageModel
+	report   *EndpointReport
+	edges    map[string][]*linage.DataFlowEdge // edges by scope ID
+	types    map[*graph.Type]*TypeTouch
+	external map[string]*ExternalTouch // by kind:name
+}
+
+// index groups model edges by scope
+func (t *endpointTouches) index() {
+	t.edges = map[string][]*linage.DataFlowEdge{}
+	for _, edge := range t.model.DataFlows {
+		t.edges[edge.Scope] = append(t.edges[edge.Scope], edge)
+	}
+}
+
+// collect records fields, tables, config keys, log sinks and serialization boundaries touched within the function scopes of a hop
+func (t *endpointTouches) collect(hop *EndpointHop, function *endpointFunction) {
+	suffix := ":" + path.Base(function.file.Path) + "." + function.name()
+	for _, scope := range t.model.Scopes {
+		if scope.Kind != "function" || !strings.HasSuffix(scope.ID, suffix) || !samePackage(function.pkg.ImportPath, strings.TrimSuffix(scope.ID, suffix)) {
+			continue
+		}
+		vars := t.variables(function, scope)
+		for _, inner := range t.model.Scopes {
+			if inner.ID != scope.ID && !strings.HasPrefix(inner.ID, scope.ID+".") {
+				continue
+			}
+			for _, edge := range t.edges[inner.ID] {
+				t.collectEdge(hop, function, vars, edge)
+			}
+		}
+		// query, log and serialization identifiers are recorded at their call site, with or without flows
+		fileScopeID := strings.TrimSuffix(scope.ID, "."+function.name())
+		for _, id := range t.model.Idents {
+			if (id.Kind == linage.SQLQueryKind || id.Kind == linage.LogSinkKind || id.Kind == linage.SerializationKind) && id.Package+":"+path.Base(id.File) == fileScopeID &&
+				int(id.StartByte) >= scope.StartByte && int(id.StartByte) < scope.EndByte {
+				t.collectExternal(hop, id)
+			}
+		}
+	}
+	sort.Strings(hop.Reads)
+	sort.Strings(hop.Writes)
+	sort.Strings(hop.External)
+}
+
+// variables returns types of receiver, parameters and typed locals of a function by name, and types of variables
+// receiving values of identifiers (e.g. composite literal keys) by identifier ID
+func (t *endpointTouches) variables(function *endpointFunction, scope *linage.Scope) map[string]string {
+	result := map[string]string{}
+	if match := receiverExpr.FindStringSubmatch(function.function.Signature); len(match) > 1 && function.owner != nil {
+		result[match[1]] = function.owner.Name
+	}
+	for _, param := range function.function.Parameters {
+		if param.Type != nil {
+			result[param.Name] = param.Type.Name
+		}
+	}
+	for _, inner := range t.model.Scopes {
+		if inner.ID != scope.ID && !strings.HasPrefix(inner.ID, scope.ID+".") {
+			continue
+		}
+		for _, edge := range t.edges[inner.ID] {
+			for _, id := range []*linage.Identifier{edge.Src, edge.Dst} {
+				if id != nil && id.Selector == nil && id.Type != "" && result[id.Name] == "" {
+					result[id.Name] = id.Type
+				}
+			}
+			if edge.Kind == linage.Xfer && edge.Src != nil && edge.Dst != nil && edge.Dst.Type != "" {
+				result[edge.Src.ID] = edge.Dst.Type
+			}
+		}
+	}
+	return result
+}
+
+// collectEdge records fields selected by an edge: written selectors of WRITE edges, read selectors of READ and XFER
+// edges, receiver fields of method calls and keys of composite literals
+func (t *endpointTouches) collectEdge(hop *EndpointHop, function *endpointFunction, vars map[string]string, edge *linage.DataFlowEdge) {
+	for _, id := range []*linage.Identifier{edge.Src, edge.Dst} {
+		if id != nil && (id.Kind == linage.SQLQueryKind || id.Kind == linage.LogSinkKind || id.Kind == linage.SerializationKind || id.Kind == "config") {
+			t.collectExternal(hop, id)
+		}
+	}
+	switch edge.Kind {
+	case linage.Write:
+		t.collectSelector(hop, function, vars, edge.Dst, true, false)
+	case linage.Read:
+		if edge.Dst != nil && edge.Dst.Selector == nil {
+			t.collectLiteralKey(hop, function, vars, edge.Dst)
+			return
+		}
+		t.collectSelector(hop, function, vars, edge.Dst, false, false)
+	case linage.Xfer:
+		t.collectSelector(hop, function, vars, edge.Src, false, false)
+	case linage.Call:
+		t.collectSelector(hop, function, vars, edge.Dst, false, true)
+	}
+}
+
+// collectLiteralKey records a field written by a composite literal key, literals of qualified types are typed by the
+// variable they are assigned to
+func (t *endpointTouches) collectLiteralKey(hop *EndpointHop, function *endpointFunction, vars map[string]string, id *linage.Identifier) {
+	if literalOf(id) == nil {
+		return
+	}
+	literal := literalType(id, t.model)
+	if literal == "" {
+		literal = vars[id.ID]
+	}
+	if owner := t.resolveType(function.pkg, function.file, literal); owner != nil && owner.aType.GetField(id.Name) != nil {
+		t.touch(hop, owner, id.Name, true)
+	}
+}
+
+// collectSelector records project struct fields of a selector chain rooted at a typed variable, intermediate fields
+// are read; the last selected name is skipped for calls
+func (t *endpointTouches) collectSelector(hop *EndpointHop, function *endpointFunction, vars map[string]string, id *linage.Identifier, write, call bool) {
+	if id == nil || id.Selector == nil {
+		return
+	}
+	var fields []string
+	for sel := id.Selector; sel != nil; sel = sel.Parent {
+		fields = append([]string{sel.Field}, fields...)
+	}
+	if call {
+		fields = fields[:len(fields)-1]
+	}
+	if len(fields) < 2 || vars[fields[0]] == "" {
+		return
+	}
+	owner := t.resolveType(function.pkg, function.file, vars[fields[0]])
+	for i, name := range fields[1:] {
+		if owner == nil {
+			return
+		}
+		field := owner.aType.GetField(name)
+		if field == nil {
+			return
+		}
+		t.touch(hop, owner, name, write && i == len(fields)-2)
+		if field.Type == nil {
+			return
+		}
+		owner = t.resolveType(owner.pkg, owner.file, field.Type.Name)
+	}
+}
+
+// resolveType returns the project type of a type expression as written in a file, e.g. *model.Order
+func (t *endpointTouches) resolveType(pkg *graph.Package, file *graph.File, typeName string) *typeRef {
+	typeName = strings.TrimLeft(typeName, "*&[]")
+	if index := strings.Index(typeName, "["); index != -1 {
+		typeName = typeName[:index] // generic instantiation
+	}
+	if index := strings.LastIndex(typeName, "."); index != -1 {
+		qualifier := typeName[:index]
+		typeName = typeName[index+1:]
+		pkg = nil
+		for _, imp := range file.Imports {
+			if imp.LocalName() == qualifier {
+				pkg = t.lookupPackage(imp.Path)
+				break
+			}
+		}
+		if pkg == nil {
+			return nil
+		}
+	}
+	for _, candidate := range pkg.FileSet {
+		if aType := candidate.LookupType(typeName); aType != nil {
+			return &typeRef{pkg: pkg, file: candidate, aType: aType}
+		}
+	}
+	return nil
+}
+
+// lookupPackage returns a project package by import path, directory based import paths match by package name
+func (t *endpointTouches) lookupPackage(importPath string) *graph.Package {
+	var byName *graph.Package
+	for _, pkg := range t.project.Packages {
+		switch {
+		case samePackage(pkg.ImportPath, importPath):
+			return pkg
+		case byName == nil && pkg.Name == path.Base(importPath):
+			byName = pkg
+		}
+	}
+	return byName
+}
+
+// touch records a field access of a hop and its owning type
+func (t *endpointTouches) touch(hop *EndpointHop, owner *typeRef, field string, write bool) {
+	touched, ok := t.types[owner.aType]
+	if !ok {
+		touched = &TypeTouch{Package: owner.pkg.ImportPath, Type: owner.aType.Name}
+		t.types[owner.aType] = touched
+	}
+	name := owner.aType.Name + "." + field
+	if write {
+		hop.Writes = appendUnique(hop.Writes, name)
+		touched.Writes = appendUnique(touched.Writes, field)
+		return
+	}
+	hop.Reads = appendUnique(hop.Reads, name)
+	touched.Reads = appendUnique(touched.Reads, field)
+}
+
+// collectExternal records tables of a query, a config key, a log sink or a serialization boundary touched by a hop,
+// boundaries are named by their call and serialized type, e.g. json.Marshal(Order)
+func (t *endpointTouches) collectExternal(hop *EndpointHop, id *linage.Identifier) {
+	add := func(kind, name string) {
+		key := kind + ":" + name
+		hop.External = appendUnique(hop.External, key)
+		touched, ok := t.external[key]
+		if !ok {
+			touched = &ExternalTouch{Kind: kind, Name: name}
+			t.external[key] = touched
+		}
+		touched.Functions = appendUnique(touched.Functions, hop.Function)
+	}
+	switch id.Kind {
+	case linage.SQLQueryKind:
+		for _, table := range linage.SQLTables(id) {
+			add(EndpointTable, table)
+		}
+	case "config":
+		add(EndpointConfig, id.Name)
+	case linage.LogSinkKind:
+		add(EndpointLog, id.Name)
+	case linage.SerializationKind:
+		name := id.Name
+		if id.Type != "" {
+			name += "(" + id.Type + ")"
+		}
+		add(EndpointSerialization, name)
+	}
+}
+
+// summarize sorts touched types and external touches into the report
+func (t *endpointTouches) summarize() {
+	for _, touched := range t.types {
+		sort.Strings(touched.Reads)
+		sort.Strings(touched.Writes)
+		t.report.Types = append(t.report.Types, touched)
+	}
+	sort.Slice(t.report.Types, func(i, j int) bool {
+		a, b := t.report.Types[i], t.report.Types[j]
+		if a.Package != b.Package {
+			return a.Package < b.Package
+		}
+		return a.Type < b.Type
+	})
+	for _, touched := range t.external {
+		t.report.External = append(t.report.External, touched)
+	}
+	sort.Slice(t.report.External, func(i, j int) bool {
+		a, b := t.report.External[i], t.report.External[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+}
+
+// JSON renders the report as indented JSON
+func (r *EndpointReport) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// Mermaid renders the call path from the entry as a Mermaid sequence diagram with notes listing data touched at
+// each hop
+func (r *EndpointReport) Mermaid() string {
+	builder := &strings.Builder{}
+	builder.WriteString("sequenceDiagram\n")
+	participants := map[string]string{}
+	for i, hop := range r.Hops {
+		participants[hop.Function] = fmt.Sprintf("h%d", i)
+		fmt.Fprintf(builder, "    participant h%d as %s\n", i, shortFunctionKey(hop.Function))
+	}
+	for _, hop := range r.Hops {
+		participant := participants[hop.Function]
+		if hop.Caller != "" {
+			fmt.Fprintf(builder, "    %s->>%s: %s\n", participants[hop.Caller], participant, hop.Function[strings.LastIndex(hop.Function, ".")+1:])
+		}
+		var notes []string
+		if len(hop.Reads) > 0 {
+			notes = append(notes, "reads "+strings.Join(hop.Reads, ", "))
+		}
+		if len(hop.Writes) > 0 {
+			notes = append(notes, "writes "+strings.Join(hop.Writes, ", "))
+		}
+		for _, external := range hop.External {
+			notes = append(notes, strings.Replace(external, ":", " ", 1))
+		}
+		if len(notes) > 0 {
+			fmt.Fprintf(builder, "    Note over %s: %s\n", participant, strings.Join(notes, "<br/>"))
+		}
+	}
+	return builder.String()
+}
+
+// shortFunctionKey returns a call graph key with the package import path shortened to its last element,
+// e.g. api.ListOrders for example.com/app/api.ListOrders
+func shortFunctionKey(key string) string {
+	return key[strings.LastIndex(key, "/")+1:]
+}
+
+// appendUnique appends a value missing from values
+func appendUnique(values []string, value string) []string {
+	if containsString(values, value) {
+		return values
+	}
+	return append(values, value)
+}
@@ -0,0 +1,170 @@
+package analyzer
+
+import (
+	"fmt"
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/viant/linager/analyzer/linage"
+	"path"
+	"strings"
+)
+
+// errorWrappers maps error constructing calls to wrapped argument positions (nil: none, -1: all arguments)
+var errorWrappers = map[string][]int{
+	"errors.New":          nil,
+	"errors.Errorf":       nil,
+	"errors.Join":         {-1},
+	"errors.Wrap":         {0},
+	"errors.Wrapf":        {0},
+	"errors.WithMessage":  {0},
+	"errors.WithMessagef": {0},
+	"errors.WithStack":    {0},
+	"fmt.Errorf":          nil, // wraps %w arguments, resolved from the format string
+}
+
+// resultIdent returns a synthetic return identifier for an unnamed result at the given position
+func (a *Analyzer) resultIdent(fn *linage.Identifier, position int, resultType string, model *linage.PackageModel) *linage.Identifier {
+	key := fmt.Sprintf("%s#ret%d", fn.ID, position)
+	if id, ok := model.Idents[key]; ok {
+		return id
+	}
+	id := &linage.Identifier{
+		ID:        key,
+		Name:      fn.Name,
+		Kind:      "return",
+		Package:   fn.Package,
+		File:      fn.File,
+		StartByte: fn.StartByte,
+		Type:      resultType,
+	}
+	model.Idents[key] = id
+	return id
+}
+
+// trackErrorCall links errors produced by a call to destination identifiers: wrapping calls continue lineage of
+// the wrapped errors, error constructors and unsummarized calls returning an error become error origins.
+func (a *Analyzer) trackErrorCall(call *sitter.Node, dsts []*linage.Identifier, src []byte, scope *linage.Scope, model *linage.PackageModel) {
+	fnNode := call.ChildByFieldName("function")
+	if fnNode == nil || len(dsts) == 0 {
+		return
+	}
+	fns, _, _ := a.callTargets(fnNode, src, scope, model)
+	for _, fn := range fns {
+		if _, ok := a.funcSummaries[fn]; ok {
+			return // summarized callees are linked through their returns
+		}
+	}
+	name := strings.TrimSpace(string(src[fnNode.StartByte():fnNode.EndByte()]))
+	args := namedChildren(call.ChildByFieldName("arguments"))
+	qualified := a.qualifiedCall(fnNode, src)
+	positions, isWrapper := errorWrappers[qualified]
+	if !isWrapper {
+		// Go convention: an error is returned as the last result
+		dst := dsts[len(dsts)-1]
+		if dst != nil && (a.isErrorIdent(dst) || dst.Name == "err") {
+			a.addErrorFlow(a.originIdent(call, name, scope, model), dst, scope, model)
+		}
+		return
+	}
+	dst := dsts[0]
+	if dst == nil {
+		return // wrapped error assigned to the blank identifier
+	}
+	if dst.Type == "" {
+		dst.Type = "error"
+	}
+	if qualified == "fmt.Errorf" && len(args) > 0 && strings.Contains(string(src[args[0].StartByte():args[0].EndByte()]), "%w") {
+		positions = []int{-1}
+	}
+	wrapped := 0
+	for i, arg := range args {
+		isWrapped := false
+		for _, position := range positions {
+			if position == -1 || position == i {
+				isWrapped = true
+			}
+		}
+		for _, id := range a.extractIdentifiers(arg, src, scope, model) {
+			// arguments reach the new error as data; only wrapped errors carry the error lineage
+			a.errorCuts[id.ID+"->"+dst.ID] = true
+			if isWrapped && a.isErrorIdent(id) {
+				a.addErrorFlow(id, dst, scope, model)
+				wrapped++
+			}
+		}
+	}
+	if wrapped == 0 {
+		a.addErrorFlow(a.originIdent(call, name, scope, model), dst, scope, model)
+	}
+}
+
+// qualifiedCall returns call name qualified with the imported package name (e.g. errors.Wrap for pkg/errors)
+func (a *Analyzer) qualifiedCall(fnNode *sitter.Node, src []byte) string {
+	if fnNode.Type() != "selector_expression" {
+		return ""
+	}
+	operand := fnNode.ChildByFieldName("operand")
+	field := fnNode.ChildByFieldName("field")
+	if operand == nil || field == nil {
+		return ""
+	}
+	pkg := string(src[operand.StartByte():operand.EndByte()])
+	if imported, ok := a.importAliases[pkg]; ok {
+		pkg = path.Base(imported)
+	}
+	return pkg + "." + string(src[field.StartByte():field.EndByte()])
+}
+
+// originIdent returns a synthetic identifier representing an error originating call site
+func (a *Analyzer) originIdent(call *sitter.Node, name string, scope *linage.Scope, model *linage.PackageModel) *linage.Identifier {
+	file := strings.TrimPrefix(topFileScope(scope).ID, model.Path+":")
+	key := fmt.Sprintf("%s::%s::%d#err", model.Path, file, call.StartByte())
+	if id, ok := model.Idents[key]; ok {
+		return id
+	}
+	id := &linage.Identifier{
+		ID:        key,
+		Name:      name,
+		Kind:      "call",
+		Package:   model.Path,
+		File:      file,
+		StartByte: call.StartByte(),
+		Type:      "error",
+	}
+	model.Idents[key] = id
+	return id
+}
+
+// addErrorFlow adds an XFER edge tagged as error flow
+func (a *Analyzer) addErrorFlow(src, dst *linage.Identifier, scope *linage.Scope, model *linage.PackageModel) {
+	if dst.Type == "" {
+		dst.Type = "error"
+	}
+	model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{
+		Src:        src,
+		Dst:        dst,
+		Kind:       linage.Xfer,
+		Scope:      scope.ID,
+		Attributes: map[string]interface{}{linage.FlowAttribute: linage.ErrorFlow},
+	})
+}
+
+// isErrorIdent reports whether identifier holds an error value
+func (a *Analyzer) isErrorIdent(id *linage.Identifier) bool {
+	return id.Type == "error" || a.errorIdents[id.ID]
+}
+
+// tagErrorFlows tags XFER edges between error identifiers as error flows
+func (a *Analyzer) tagErrorFlows(model *linage.PackageModel) {
+	for _, edge := range model.DataFlows {
+		if edge.Kind != linage.Xfer || edge.IsErrorFlow() || edge.Src == edge.Dst {
+			continue
+		}
+		if !a.isErrorIdent(edge.Src) || !a.isErrorIdent(edge.Dst) || a.errorCuts[edge.Src.ID+"->"+edge.Dst.ID] {
+			continue
+		}
+		if edge.Attributes == nil {
+			edge.Attributes = map[string]interface{}{}
+		}
+		edge.Attributes[linage.FlowAttribute] = linage.ErrorFlow
+	}
+}
@@ -0,0 +1,166 @@
+package analyzer
+
+import (
+	"go/constant"
+	"go/token"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/viant/linager/analyzer/linage"
+)
+
+// maxFoldDepth caps the nesting of folded constant expressions, e.g. a chain of 32 concatenations
+const maxFoldDepth = 32
+
+// foldOperators maps Go binary operators to go/constant operations
+var foldOperators = map[string]token.Token{
+	"+": token.ADD, "-": token.SUB, "*": token.MUL, "/": token.QUO, "%": token.REM,
+	"&": token.AND, "|": token.OR, "^": token.XOR, "&^": token.AND_NOT, "<<": token.SHL, ">>": token.SHR,
+}
+
+// foldLiterals maps tree-sitter literal node types to go/constant literal tokens
+var foldLiterals = map[string]token.Token{
+	"int_literal": token.INT, "float_literal": token.FLOAT, "rune_literal": token.CHAR,
+	"interpreted_string_literal": token.STRING, "raw_string_literal": token.STRING,
+}
+
+// foldConstant returns the Go literal of a binary expression whose operands are literals or package constants with
+// known values (see declareSpecNames): int and float arithmetic and string concatenation; expressions with calls,
+// variables or nesting beyond maxFoldDepth are not folded
+func foldConstant(expr *sitter.Node, src []byte, scope *linage.Scope) (string, bool) {
+	expr = unparen(expr)
+	if expr == nil || expr.Type() != "binary_expression" {
+		return "", false
+	}
+	value, ok := foldValue(expr, src, scope, 0)
+	if !ok {
+		return "", false
+	}
+	if value.Kind() == constant.Float {
+		return value.String(), true
+	}
+	return value.ExactString(), true
+}
+
+// foldValue evaluates a constant expression
+func foldValue(expr *sitter.Node, src []byte, scope *linage.Scope, depth int) (constant.Value, bool) {
+	expr = unparen(expr)
+	if expr == nil || depth > maxFoldDepth {
+		return nil, false
+	}
+	switch expr.Type() {
+	case "identifier":
+		id := scope.Find(expr.Content(src))
+		if id == nil || id.Kind != "const" || id.KnownValue == nil {
+			return nil, false
+		}
+		return parseConstant(id.KnownValue.Value)
+	case "unary_expression":
+		operator, operand := expr.ChildByFieldName("operator"), expr.ChildByFieldName("operand")
+		if operator == nil || (operator.Type() != "-" && operator.Type() != "+") {
+			return nil, false
+		}
+		value, ok := foldValue(operand, src, scope, depth+1)
+		if !ok || !isNumeric(value) {
+			return nil, false
+		}
+		return constant.UnaryOp(foldOperators[operator.Type()], value, 0), true
+	case "binary_expression":
+		operator := expr.ChildByFieldName("operator")
+		if operator == nil {
+			return nil, false
+		}
+		op, ok := foldOperators[operator.Type()]
+		if !ok {
+			return nil, false
+		}
+		x, ok := foldValue(expr.ChildByFieldName("left"), src, scope, depth+1)
+		if !ok {
+			return nil, false
+		}
+		y, ok := foldValue(expr.ChildByFieldName("right"), src, scope, depth+1)
+		if !ok {
+			return nil, false
+		}
+		return foldBinary(op, x, y)
+	}
+	if tok, ok := foldLiterals[expr.Type()]; ok {
+		return parseLiteral(expr.Content(src), tok)
+	}
+	return nil, false
+}
+
+// foldBinary applies a binary operation to constants, operations go/constant would reject are not folded
+func foldBinary(op token.Token, x, y constant.Value) (constant.Value, bool) {
+	switch {
+	case x.Kind() == constant.String && y.Kind() == constant.String:
+		if op != token.ADD {
+			return nil, false
+		}
+		return constant.BinaryOp(x, op, y), true
+	case !isNumeric(x) || !isNumeric(y):
+		return nil, false
+	case op == token.SHL || op == token.SHR:
+		shift, ok := constant.Uint64Val(constant.ToInt(y))
+		if !ok || shift > 64 || constant.ToInt(x).Kind() != constant.Int {
+			return nil, false
+		}
+		return constant.Shift(constant.ToInt(x), op, uint(shift)), true
+	}
+	integers := x.Kind() == constant.Int && y.Kind() == constant.Int
+	switch op {
+	case token.QUO, token.REM:
+		if constant.Sign(y) == 0 {
+			return nil, false
+		}
+		if integers && op == token.QUO {
+			op = token.QUO_ASSIGN // integer division
+		}
+	}
+	if !integers && (op == token.REM || op == token.AND || op == token.OR || op == token.XOR || op == token.AND_NOT) {
+		return nil, false
+	}
+	return constant.BinaryOp(x, op, y), true
+}
+
+// parseConstant returns the constant of a recorded Go literal, e.g. a known value
+func parseConstant(literal string) (constant.Value, bool) {
+	for _, tok := range []token.Token{token.INT, token.FLOAT, token.STRING, token.CHAR} {
+		if value, ok := parseLiteral(literal, tok); ok {
+			return value, true
+		}
+	}
+	if len(literal) > 1 && (literal[0] == '-' || literal[0] == '+') {
+		if value, ok := parseConstant(literal[1:]); ok && isNumeric(value) {
+			if literal[0] == '-' {
+				value = constant.UnaryOp(token.SUB, value, 0)
+			}
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// parseLiteral returns the constant of a literal token, runes are folded as integers
+func parseLiteral(literal string, tok token.Token) (constant.Value, bool) {
+	value := constant.MakeFromLiteral(literal, tok, 0)
+	if value.Kind() == constant.Unknown {
+		return nil, false
+	}
+	if tok == token.CHAR {
+		return constant.ToInt(value), true
+	}
+	return value, true
+}
+
+// isNumeric reports whether a constant is an integer or a float
+func isNumeric(value constant.Value) bool {
+	return value.Kind() == constant.Int || value.Kind() == constant.Float
+}
+
+// unparen returns the expression within parentheses
+func unparen(expr *sitter.Node) *sitter.Node {
+	for expr != nil && expr.Type() == "parenthesized_expression" && expr.NamedChildCount() == 1 {
+		expr = expr.NamedChild(0)
+	}
+	return expr
+}
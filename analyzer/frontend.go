@@ -0,0 +1,157 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"github.com/viant/afs"
+	"github.com/viant/afs/file"
+	"github.com/viant/afs/object"
+	"github.com/viant/afs/storage"
+	"github.com/viant/afs/url"
+	"github.com/viant/linager/analyzer/linage"
+	"github.com/viant/linager/inspector/graph"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// LanguageFrontend analyzes source files of a language into data points, a Runner dispatches files to frontends by extension
+type LanguageFrontend interface {
+	// Extensions returns handled file extensions, e.g. .go
+	Extensions() []string
+	// AnalyzeSource returns data points of a source file, project is the directory identifiers are qualified with
+	AnalyzeSource(source []byte, project, path string) ([]*linage.DataPoint, error)
+}
+
+// languageExtensions maps analyzer language tags to source file extensions
+var languageExtensions = map[string][]string{
+	"go":         {".go"},
+	"java":       {".java"},
+	"javascript": {".js", ".jsx", ".ts", ".tsx"},
+}
+
+// Extensions returns source file extensions of the analyzer language, Go when no language tag is set
+func (a *Analyzer) Extensions() []string {
+	if a.Language == "" {
+		return languageExtensions["go"]
+	}
+	return languageExtensions[a.Language]
+}
+
+// AnalyzeSource analyzes a single source file, see AnalyzeSourceCode to analyze files sharing a package model
+func (a *Analyzer) AnalyzeSource(source []byte, project, path string) ([]*linage.DataPoint, error) {
+	model, err := a.AnalyzeModel(source, project, path)
+	if err != nil {
+		return nil, err
+	}
+	return linage.NewDataPoints(model), nil
+}
+
+// sourceFiles holds files of a directory tree grouped by package directory
+type sourceFiles struct {
+	files   map[string][]os.FileInfo        // package URL -> matching files
+	skipped map[string][]*graph.SkippedFile // package URL -> oversized files
+}
+
+// walkSources lists files matching match under root, symlinks (unless followed), special and oversized files are
+// skipped using their stat before download; symlinked directories are not descended
+func walkSources(ctx context.Context, fs afs.Service, root string, match MatcherFn, limits *graph.Config) (*sourceFiles, error) {
+	ret := &sourceFiles{files: map[string][]os.FileInfo{}, skipped: map[string][]*graph.SkippedFile{}}
+	var visitor storage.OnVisit = func(ctx context.Context, baseURL, parent string, info os.FileInfo, reader io.Reader) (bool, error) {
+		if !match(info) {
+			return false, nil
+		}
+		if info.IsDir() {
+			return true, nil
+		}
+		pkg := url.Join(baseURL, parent)
+		if skippedFile := limits.CheckMode(url.Join(pkg, info.Name()), info.Mode()); skippedFile != nil {
+			ret.skipped[pkg] = append(ret.skipped[pkg], skippedFile)
+			return true, nil
+		}
+		if skippedFile := limits.CheckSize(url.Join(pkg, info.Name()), info.Size()); skippedFile != nil {
+			ret.skipped[pkg] = append(ret.skipped[pkg], skippedFile)
+			return true, nil
+		}
+		ret.files[pkg] = append(ret.files[pkg], info)
+		return true, nil
+	}
+	if err := walkTree(ctx, fs, limits.Sources, root, visitor); err != nil {
+		return nil, fmt.Errorf("failed to walk %s: %w", root, graph.NotFoundError("directory", root, err))
+	}
+	return ret, nil
+}
+
+// walkTree walks root like afs.Service.Walk, reading the tree with sources when set; files are then visited without a
+// reader and visitors see the same base URLs and parents as with local walks
+func walkTree(ctx context.Context, fs afs.Service, sources graph.SourceProvider, root string, visitor storage.OnVisit) error {
+	if sources == nil {
+		return fs.Walk(ctx, root, visitor)
+	}
+	baseURL := url.Normalize(root, file.Scheme)
+	rootPath := url.Path(baseURL)
+	return sources.Walk(rootPath, func(location string, info os.FileInfo, err error) error {
+		if err != nil || location == rootPath {
+			return err
+		}
+		parent, err := filepath.Rel(rootPath, filepath.Dir(location))
+		if err != nil {
+			return err
+		}
+		if parent = filepath.ToSlash(parent); parent == "." {
+			parent = ""
+		}
+		toContinue, err := visitor(ctx, baseURL, parent, info, nil)
+		if err == nil && !toContinue && info.IsDir() {
+			return filepath.SkipDir
+		}
+		return err
+	})
+}
+
+// listSources lists a directory like afs.Service.List, reading it with sources when set
+func listSources(ctx context.Context, fs afs.Service, sources graph.SourceProvider, URL string) ([]storage.Object, error) {
+	if sources == nil {
+		return fs.List(ctx, URL)
+	}
+	entries, err := sources.ReadDir(url.Path(URL))
+	if err != nil {
+		return nil, err
+	}
+	result := make([]storage.Object, 0, len(entries))
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, object.New(url.Join(URL, entry.Name()), info, nil))
+	}
+	return result, nil
+}
+
+// fileNames returns names of files
+func fileNames(files []os.FileInfo) []string {
+	result := make([]string, 0, len(files))
+	for _, file := range files {
+		result = append(result, file.Name())
+	}
+	return result
+}
+
+// loadSource downloads a source file, or reads it with sources when set; binary files are returned as skipped
+func loadSource(ctx context.Context, fs afs.Service, sources graph.SourceProvider, URL string) ([]byte, *graph.SkippedFile, error) {
+	var code []byte
+	var err error
+	if sources == nil {
+		code, err = fs.DownloadWithURL(ctx, URL)
+	} else {
+		code, err = sources.ReadFile(url.Path(URL))
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to download %s: %w", URL, graph.NotFoundError("file", URL, err))
+	}
+	if graph.IsBinary(code) {
+		return nil, &graph.SkippedFile{Path: URL, Size: int64(len(code)), Reason: graph.SkipReasonBinary}, nil
+	}
+	return code, nil, nil
+}
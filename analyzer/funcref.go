@@ -0,0 +1,230 @@
+package analyzer
+
+import (
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/viant/linager/analyzer/linage"
+)
+
+// fieldCall is a call through a function typed struct field, linked to functions assigned to the field
+type fieldCall struct {
+	model      *linage.PackageModel
+	key        string // Type.field
+	scopeID    string
+	attributes map[string]interface{} // call site attributes shared by linked CALL edges
+	linked     map[string]bool        // linked function IDs
+}
+
+// methodExpression returns the method denoted by a method expression (Service.Process or (*Service).Process), nil
+// when the selector operand is not a type of the package
+func (a *Analyzer) methodExpression(expr *sitter.Node, src []byte, scope *linage.Scope) *linage.Identifier {
+	if expr == nil || expr.Type() != "selector_expression" {
+		return nil
+	}
+	operand, field := expr.ChildByFieldName("operand"), expr.ChildByFieldName("field")
+	if operand == nil || field == nil || (operand.Type() != "identifier" && operand.Type() != "parenthesized_expression") {
+		return nil
+	}
+	typeName := strings.TrimSpace(string(src[operand.StartByte():operand.EndByte()]))
+	typeName = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(typeName, "("), ")")), "*"))
+	if id := scope.Find(typeName); id == nil || id.Kind != "type" {
+		return nil
+	}
+	method := scope.Find(typeName + "." + string(src[field.StartByte():field.EndByte()]))
+	if method == nil || method.Kind != "method" {
+		return nil
+	}
+	return method
+}
+
+// funcField returns Type.field of a selector of a function typed struct field (e.g. s.callback), empty otherwise
+func (a *Analyzer) funcField(expr *sitter.Node, src []byte, scope *linage.Scope) string {
+	if expr == nil || expr.Type() != "selector_expression" {
+		return ""
+	}
+	operand, field := expr.ChildByFieldName("operand"), expr.ChildByFieldName("field")
+	if operand == nil || field == nil || operand.Type() != "identifier" {
+		return ""
+	}
+	name := string(src[operand.StartByte():operand.EndByte()])
+	if _, ok := a.importAliases[name]; ok && scope.Find(name) == nil {
+		return ""
+	}
+	typeName := a.receiverType(name, scope, src)
+	fieldName := string(src[field.StartByte():field.EndByte()])
+	if fieldType, ok := a.fieldType(scope, typeName, fieldName); !ok || !a.isFuncType(fieldType) {
+		return ""
+	}
+	return typeName + "." + fieldName
+}
+
+// isFuncType reports whether a type is a function type or a named function type of analyzed files
+func (a *Analyzer) isFuncType(typeName string) bool {
+	return strings.HasPrefix(typeName, "func") || a.funcTypes[baseTypeName(typeName)]
+}
+
+// callMechanism returns how a call function expression reaches its callee, see linage.CallMechanismAttribute
+func (a *Analyzer) callMechanism(fnNode *sitter.Node, src []byte, scope *linage.Scope) string {
+	switch {
+	case a.methodExpression(fnNode, src, scope) != nil:
+		return linage.MechanismMethodExpression
+	case a.funcField(fnNode, src, scope) != "":
+		return linage.MechanismFieldIndirect
+	}
+	return linage.MechanismDirect
+}
+
+// linkFuncRefs records function references of an analyzed file that are not calls: functions passed as call arguments,
+// method expressions used as values, and functions assigned to function typed struct fields with the calls through
+// these fields (see linkFieldCalls); scopes are resolved by position, so functions declared later in the file resolve
+func (a *Analyzer) linkFuncRefs(root *sitter.Node, src []byte, fileScope *linage.Scope, model *linage.PackageModel) {
+	var scopes []*linage.Scope
+	for _, scope := range model.Scopes {
+		if scope.ID == fileScope.ID || strings.HasPrefix(scope.ID, fileScope.ID+".") {
+			scopes = append(scopes, scope)
+		}
+	}
+	scopeAt := func(n *sitter.Node) *linage.Scope {
+		result := fileScope
+		for _, scope := range scopes {
+			if int(n.StartByte()) >= scope.StartByte && int(n.EndByte()) <= scope.EndByte && scope.EndByte-scope.StartByte < result.EndByte-result.StartByte {
+				result = scope
+			}
+		}
+		return result
+	}
+	stack := []*sitter.Node{root}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for i := int(n.NamedChildCount()) - 1; i >= 0; i-- {
+			stack = append(stack, n.NamedChild(i))
+		}
+		switch n.Type() {
+		case "call_expression":
+			scope := scopeAt(n)
+			for _, actual := range namedChildren(n.ChildByFieldName("arguments")) {
+				if target := a.funcValue(actual, src, scope, model); target != nil {
+					a.appendCallEdges([]*linage.Identifier{target}, funcCallKind(target), funcRef(target), linage.MechanismPassed, actual, scope, scope.ID, model)
+				}
+			}
+			if fnNode := n.ChildByFieldName("function"); fnNode != nil {
+				if key := a.funcField(fnNode, src, scope); key != "" {
+					a.addFieldCall(key, fnNode, scope, model)
+				}
+			}
+		case "selector_expression":
+			if parent := n.Parent(); parent != nil && (parent.Type() == "argument_list" || parent.Type() == "call_expression" && parent.ChildByFieldName("function") != nil && parent.ChildByFieldName("function").Equal(n)) {
+				continue // passed or called, see above and addCallEdges
+			}
+			scope := scopeAt(n)
+			if target := a.methodExpression(n, src, scope); target != nil {
+				a.appendCallEdges([]*linage.Identifier{target}, linage.CallMethod, methodSymbol(target), linage.MechanismMethodExpression, n, scope, scope.ID, model)
+			}
+		case "assignment_statement":
+			lefts, rights := namedChildren(n.ChildByFieldName("left")), namedChildren(n.ChildByFieldName("right"))
+			if len(lefts) != len(rights) {
+				continue
+			}
+			scope := scopeAt(n)
+			for i, left := range lefts {
+				if key := a.funcField(left, src, scope); key != "" {
+					a.bindFieldFunc(key, a.funcValue(rights[i], src, scope, model))
+				}
+			}
+		case "composite_literal":
+			typeNode, body := n.ChildByFieldName("type"), n.ChildByFieldName("body")
+			if typeNode == nil || body == nil {
+				continue
+			}
+			typeName := baseTypeName(strings.TrimSpace(string(src[typeNode.StartByte():typeNode.EndByte()])))
+			scope := scopeAt(n)
+			for _, elem := range namedChildren(body) {
+				if elem.Type() != "keyed_element" || elem.NamedChildCount() != 2 {
+					continue
+				}
+				keyNode, valNode := elem.NamedChild(0), elem.NamedChild(1)
+				fieldName := strings.TrimSpace(string(src[keyNode.StartByte():keyNode.EndByte()]))
+				if fieldType, ok := a.fieldType(scope, typeName, fieldName); ok && a.isFuncType(fieldType) {
+					a.bindFieldFunc(typeName+"."+fieldName, a.funcValue(unwrapLiteralElement(valNode), src, scope, model))
+				}
+			}
+		}
+	}
+}
+
+// unwrapLiteralElement returns the expression of a literal_element wrapper used by some grammar versions
+func unwrapLiteralElement(n *sitter.Node) *sitter.Node {
+	if n.Type() == "literal_element" && n.NamedChildCount() == 1 {
+		return n.NamedChild(0)
+	}
+	return n
+}
+
+// bindFieldFunc records a function assigned to a function typed struct field
+func (a *Analyzer) bindFieldFunc(key string, target *linage.Identifier) {
+	if target == nil {
+		return
+	}
+	for _, candidate := range a.fieldFuncs[key] {
+		if candidate == target {
+			return
+		}
+	}
+	a.fieldFuncs[key] = append(a.fieldFuncs[key], target)
+}
+
+// addFieldCall records a call through a function typed struct field, linked once functions assigned to the field are known
+func (a *Analyzer) addFieldCall(key string, fnNode *sitter.Node, scope *linage.Scope, model *linage.PackageModel) {
+	site := fnNode
+	if field := fnNode.ChildByFieldName("field"); field != nil {
+		site = field
+	}
+	attributes := map[string]interface{}{
+		linage.CallRefAttribute:       key,
+		linage.CallMechanismAttribute: linage.MechanismFieldIndirect,
+		linage.FileAttribute:          strings.TrimPrefix(topFileScope(scope).ID, model.Path+":"),
+		linage.LineAttribute:          int(site.StartPoint().Row) + 1,
+		linage.ColumnAttribute:        int(site.StartPoint().Column) + 1,
+		linage.EndColumnAttribute:     int(site.EndPoint().Column) + 1,
+	}
+	a.fieldCalls = append(a.fieldCalls, &fieldCall{model: model, key: key, scopeID: scope.ID, attributes: attributes, linked: map[string]bool{}})
+}
+
+// linkFieldCalls adds CALL edges from calls through function typed struct fields to every function assigned to the
+// field so far, calls of other models are linked too when all is set (e.g. a merged model of analyzed packages)
+func (a *Analyzer) linkFieldCalls(model *linage.PackageModel, all bool) {
+	for _, call := range a.fieldCalls {
+		if call.model != model && !all {
+			continue
+		}
+		for _, target := range a.fieldFuncs[call.key] {
+			if call.linked[target.ID] {
+				continue
+			}
+			call.linked[target.ID] = true
+			attributes := map[string]interface{}{linage.CallKindAttribute: funcCallKind(target)}
+			for key, value := range call.attributes {
+				attributes[key] = value
+			}
+			model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: target, Dst: target, Kind: linage.Call, Scope: call.scopeID, Attributes: attributes})
+		}
+	}
+}
+
+// funcCallKind returns the call kind of a function or method identifier
+func funcCallKind(fn *linage.Identifier) string {
+	if fn.Kind == "method" {
+		return linage.CallMethod
+	}
+	return linage.CallFunction
+}
+
+// funcRef returns the callee reference of a function or method identifier, e.g. Service.Process
+func funcRef(fn *linage.Identifier) string {
+	if fn.Kind == "method" {
+		return methodSymbol(fn)
+	}
+	return fn.Name
+}
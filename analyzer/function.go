@@ -0,0 +1,220 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/viant/afs/file"
+	"github.com/viant/afs/url"
+	"github.com/viant/linager/analyzer/linage"
+	"github.com/viant/linager/inspector/graph"
+	"github.com/viant/linager/inspector/treesitter"
+	"path/filepath"
+	"strings"
+)
+
+// AnalyzeFile analyzes a single source file as a package of one file
+func (a *Analyzer) AnalyzeFile(ctx context.Context, filePath string) (*linage.PackageModel, error) {
+	baseURL, name := url.Split(filePath, file.Scheme)
+	return a.analyzePackage(ctx, baseURL, []string{name})
+}
+
+// AnalyzeFunction analyzes a single top-level Go function or method (Name or Type.Name) of a file without analyzing
+// the rest of its package. Imports and earlier declarations of the file are registered without walking their bodies,
+// so calls resolve to stub identifiers; struct types referenced by the function are loaded on demand from the file and
+// its package siblings. The returned model holds the function scope tree, identifiers and flows only, they are a subset
+// of the AnalyzeFile result; the whole file is analyzed when the function can't be located.
+func (a *Analyzer) AnalyzeFunction(ctx context.Context, filePath, functionName string) (*linage.PackageModel, error) {
+	code, skippedFile, err := loadSource(ctx, a.fs, a.limits.Sources, filePath)
+	if err != nil {
+		return nil, err
+	}
+	baseURL, name := url.Split(filePath, file.Scheme)
+	if skippedFile != nil {
+		return &linage.PackageModel{Path: baseURL, Language: a.Language, Idents: map[string]*linage.Identifier{}, Skipped: []*graph.SkippedFile{skippedFile}}, nil
+	}
+	tree, err := a.parse(code)
+	if errors.Is(err, context.DeadlineExceeded) {
+		warning := treesitter.TimeoutWarning(filePath, a.limits.ParseDeadline())
+		a.limits.Diagnostics.AddParseWarning(warning)
+		return &linage.PackageModel{Path: baseURL, Language: a.Language, Idents: map[string]*linage.Identifier{}, Warnings: []*graph.ParseWarning{warning}}, nil
+	}
+	if err != nil || tree == nil {
+		return nil, &graph.ErrParse{Path: filePath, Cause: err}
+	}
+	rootNode := tree.RootNode()
+	var target *sitter.Node
+	var preceding []*sitter.Node
+	for _, decl := range namedChildren(rootNode) {
+		if (decl.Type() == "function_declaration" || decl.Type() == "method_declaration") && functionSymbol(decl, code) == functionName {
+			target = decl
+			break
+		}
+		preceding = append(preceding, decl)
+	}
+	if target == nil {
+		return a.AnalyzeFile(ctx, filePath)
+	}
+
+	a.importAliases = map[string]string{}
+	if a.trace != nil {
+		a.trace.file = filePath
+	}
+	model := &linage.PackageModel{Path: baseURL, Language: a.Language, Idents: map[string]*linage.Identifier{}, Files: []string{name}}
+	a.budget = treesitter.NewBudget(a.limits.NodeLimit())
+	pkgScope := &linage.Scope{ID: baseURL, Kind: "package", Symbols: map[string]*linage.Identifier{}}
+	fileScope := (&linage.Scope{ID: fmt.Sprintf("%s:%s", baseURL, name), Kind: "file", Parent: pkgScope, Symbols: map[string]*linage.Identifier{}}).SetRange(rootNode)
+	pkgScope.Symbols[name] = &linage.Identifier{ID: fileScope.ID, Kind: "file", Name: name, Package: baseURL, File: filePath, StartByte: rootNode.StartByte(), Node: rootNode}
+	model.Scopes = append(model.Scopes, pkgScope, fileScope)
+	a.declarePackageSymbols(rootNode, code, fileScope, model)
+
+	// type model receives imports and type identifiers, only struct field types are used (see loadTypes)
+	typeModel := &linage.PackageModel{Path: baseURL, Idents: map[string]*linage.Identifier{}}
+	typeScope := &linage.Scope{ID: fileScope.ID, Kind: "file", Symbols: map[string]*linage.Identifier{}}
+	types := map[string]*sitter.Node{}
+	for _, decl := range preceding {
+		switch decl.Type() {
+		case "import_declaration":
+			a.walk(decl, code, typeScope, typeModel)
+		case "function_declaration", "method_declaration":
+			a.functionIdent(decl, decl.ChildByFieldName("name"), code, fileScope, model)
+		}
+	}
+	collectTypeSpecs(rootNode, code, types)
+	a.loadTypes(ctx, filePath, typeNames(target, code), types, code, typeScope, typeModel)
+	if a.trace != nil {
+		a.trace.release(typeModel)
+	}
+
+	if a.trees != nil {
+		a.trees.Retain(filePath, tree, code, a.language)
+	}
+	a.handleFunction(target, code, fileScope, model)
+	if warning := a.budget.Warning(filePath); warning != nil {
+		model.Warnings = append(model.Warnings, warning)
+		a.limits.Diagnostics.AddParseWarning(warning)
+	}
+	a.traceStep(model, "function")
+	if a.inlineMaxStatements > 0 {
+		a.inlineCallSites(model)
+	}
+	if a.errorTracking {
+		a.tagErrorFlows(model)
+		a.traceStep(model, "errors")
+	}
+	if a.classifier != nil {
+		a.classifyIdents(model)
+	}
+	a.computeTransitiveClosure(model)
+	a.traceStep(model, "closure")
+	if a.trace != nil {
+		a.trace.release(model)
+	}
+	if a.redactor != nil {
+		model.Redact(a.redactor)
+	}
+	return model, nil
+}
+
+// functionSymbol returns Name or Type.Name of a Go function or method declaration
+func functionSymbol(decl *sitter.Node, src []byte) string {
+	nameNode := decl.ChildByFieldName("name")
+	if nameNode == nil {
+		return ""
+	}
+	name := string(src[nameNode.StartByte():nameNode.EndByte()])
+	if recv := receiverName(decl, src); recv != "" {
+		return recv + "." + name
+	}
+	return name
+}
+
+// typeNames returns type identifiers referenced under a node
+func typeNames(n *sitter.Node, src []byte) []string {
+	var result []string
+	if n.Type() == "type_identifier" {
+		return append(result, string(src[n.StartByte():n.EndByte()]))
+	}
+	for _, child := range namedChildren(n) {
+		result = append(result, typeNames(child, src)...)
+	}
+	return result
+}
+
+// collectTypeSpecs indexes type specs of a file by type name
+func collectTypeSpecs(root *sitter.Node, src []byte, types map[string]*sitter.Node) {
+	for _, decl := range namedChildren(root) {
+		if decl.Type() != "type_declaration" {
+			continue
+		}
+		for _, spec := range namedChildren(decl) {
+			if nameNode := spec.ChildByFieldName("name"); spec.Type() == "type_spec" && nameNode != nil {
+				types[string(src[nameNode.StartByte():nameNode.EndByte()])] = spec
+			}
+		}
+	}
+}
+
+// loadTypes registers struct fields of the named types and of types their fields reference; types not declared in
+// the file are looked up in package siblings, read only when some type is missing
+func (a *Analyzer) loadTypes(ctx context.Context, filePath string, names []string, types map[string]*sitter.Node, src []byte, scope *linage.Scope, model *linage.PackageModel) {
+	sources := map[*sitter.Node][]byte{}
+	for _, spec := range types {
+		sources[spec] = src
+	}
+	loaded := map[string]bool{}
+	siblingsLoaded := false
+	for len(names) > 0 {
+		name := names[0]
+		names = names[1:]
+		if loaded[name] {
+			continue
+		}
+		loaded[name] = true
+		spec, ok := types[name]
+		if !ok && !siblingsLoaded {
+			siblingsLoaded = true
+			a.siblingTypeSpecs(ctx, filePath, types, sources)
+			spec, ok = types[name]
+		}
+		if !ok {
+			continue // predeclared or imported type
+		}
+		a.handleTypeSpec(spec, sources[spec], scope, model)
+		names = append(names, typeNames(spec, sources[spec])...)
+	}
+}
+
+// siblingTypeSpecs indexes type specs of other matching files of the file directory
+func (a *Analyzer) siblingTypeSpecs(ctx context.Context, filePath string, types map[string]*sitter.Node, sources map[*sitter.Node][]byte) {
+	baseURL, name := url.Split(filePath, file.Scheme)
+	objects, err := listSources(ctx, a.fs, a.limits.Sources, baseURL)
+	if err != nil {
+		return
+	}
+	for _, object := range objects {
+		if object.IsDir() || object.Name() == name || filepath.Ext(object.Name()) != filepath.Ext(name) || (a.match != nil && !a.match(object)) {
+			continue
+		}
+		if strings.HasSuffix(object.Name(), "_test.go") != strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		code, skippedFile, err := loadSource(ctx, a.fs, a.limits.Sources, object.URL())
+		if err != nil || skippedFile != nil || a.limits.CheckSize(object.URL(), int64(len(code))) != nil {
+			continue
+		}
+		tree, err := a.parse(code)
+		if err != nil || tree == nil {
+			continue
+		}
+		siblings := map[string]*sitter.Node{}
+		collectTypeSpecs(tree.RootNode(), code, siblings)
+		for typeName, spec := range siblings {
+			if _, ok := types[typeName]; !ok {
+				types[typeName] = spec
+				sources[spec] = code
+			}
+		}
+	}
+}
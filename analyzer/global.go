@@ -0,0 +1,132 @@
+package analyzer
+
+import (
+	"github.com/viant/linager/analyzer/linage"
+	"sort"
+	"strings"
+)
+
+// GlobalWrite describes a write to a package-level variable
+type GlobalWrite struct {
+	Function string `json:"function"`         // enclosing function name
+	Writer   string `json:"writer"`           // init, setter or function
+	Target   string `json:"target,omitempty"` // written expression, e.g. registry or cfg.Name
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Scope    string `json:"scope"`
+}
+
+// GlobalState describes a package-level variable with writes outside its declaration
+type GlobalState struct {
+	ID              string         `json:"id"`
+	Name            string         `json:"name"`
+	Type            string         `json:"type,omitempty"`
+	File            string         `json:"file,omitempty"`
+	Writes          []*GlobalWrite `json:"writes"`
+	RuntimeMutation bool           `json:"runtimeMutation"` // written outside init functions
+}
+
+// GlobalStateReport lists package-level variables mutated outside their declaration
+type GlobalStateReport struct {
+	Globals []*GlobalState `json:"globals"`
+}
+
+// Lookup returns global state by variable name
+func (r *GlobalStateReport) Lookup(name string) *GlobalState {
+	for _, global := range r.Globals {
+		if global.Name == name {
+			return global
+		}
+	}
+	return nil
+}
+
+// NewGlobalStateReport builds global state report from package-level variable WRITE edges
+func NewGlobalStateReport(model *linage.PackageModel) *GlobalStateReport {
+	scopes := make(map[string]*linage.Scope, len(model.Scopes))
+	for _, scope := range model.Scopes {
+		scopes[scope.ID] = scope
+	}
+	byID := map[string]*GlobalState{}
+	report := &GlobalStateReport{}
+	for _, edge := range model.DataFlows {
+		if edge.Kind != linage.Write || edge.Attributes == nil {
+			continue
+		}
+		globalID, ok := edge.Attributes["global"].(string)
+		if !ok {
+			continue
+		}
+		global, ok := byID[globalID]
+		if !ok {
+			ident := model.Idents[globalID]
+			if ident == nil {
+				continue
+			}
+			global = &GlobalState{ID: ident.ID, Name: ident.Name, Type: ident.Type, File: ident.File}
+			byID[globalID] = global
+			report.Globals = append(report.Globals, global)
+		}
+		write := &GlobalWrite{Scope: edge.Scope, Target: edge.Dst.Name, File: edge.Dst.File, Writer: "function"}
+		if edge.Dst.Selector != nil {
+			write.Target = selectorPath(edge.Dst.Selector)
+		}
+		switch line := edge.Attributes["line"].(type) {
+		case int:
+			write.Line = line
+		case float64:
+			write.Line = int(line)
+		}
+		if fn := enclosingFunction(scopes[edge.Scope]); fn != nil {
+			write.Function = fn.Name
+			switch {
+			case isInitScope(fn):
+				write.Writer = "init"
+			case strings.HasPrefix(fn.Name, "Set") && isExportedName(fn.Name):
+				write.Writer = "setter"
+			}
+		}
+		if write.Writer != "init" {
+			global.RuntimeMutation = true
+		}
+		global.Writes = append(global.Writes, write)
+	}
+	sort.Slice(report.Globals, func(i, j int) bool { return report.Globals[i].ID < report.Globals[j].ID })
+	return report
+}
+
+// packageVar returns the package-level variable written through id, or nil for local variables
+func packageVar(id *linage.Identifier, scope *linage.Scope) *linage.Identifier {
+	name := id.Name
+	if id.Selector != nil {
+		root := id.Selector
+		for root.Parent != nil {
+			root = root.Parent
+		}
+		name = root.Field
+	}
+	global := scope.Find(name)
+	if global == nil || global.Kind != "var" {
+		return nil
+	}
+	fileScope := topFileScope(scope)
+	if fileScope.Symbols[name] == global || (fileScope.Parent != nil && fileScope.Parent.Symbols[name] == global) {
+		return global
+	}
+	return nil
+}
+
+// enclosingFunction returns the innermost function scope containing scope
+func enclosingFunction(scope *linage.Scope) *linage.Scope {
+	for cur := scope; cur != nil; cur = cur.Parent {
+		if cur.Kind == "function" {
+			return cur
+		}
+	}
+	return nil
+}
+
+// isInitScope reports whether scope is a package init function
+func isInitScope(scope *linage.Scope) bool {
+	return scope != nil && scope.Kind == "function" && scope.Name == "init" && scope.Parent != nil && scope.Parent.Kind == "file"
+}
@@ -79,7 +79,8 @@ func buildIRGraph(a *Analyzer, model *linage.PackageModel) *IRGraph {
 			Target: normalizeID(a, df.Dst),
 			Type:   string(df.Kind),
 			Properties: map[string]interface{}{
-				"scope": df.Scope,
+				"scope":      df.Scope,
+				"confidence": df.Score(),
 			},
 		}
 		// copy any additional attributes
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	sitter "github.com/smacker/go-tree-sitter"
 	"github.com/viant/linager/analyzer/linage"
+	"github.com/viant/linager/inspector/graph"
 	"strings"
 )
 
@@ -53,78 +54,23 @@ func (a *Analyzer) extractIdentifiers(root *sitter.Node, src []byte, Scope *lina
 		}
 	case "index_expression":
 		// m[k] or arr[i] yields a synthetic element identifier
-		obj := root.ChildByFieldName("object")
-		idx := root.ChildByFieldName("index")
-		if obj != nil && idx != nil {
-			base := a.resolveIdent(obj, nil, src, Scope, model)
-			keyTxt := strings.TrimSpace(string(src[idx.StartByte():idx.EndByte()]))
-			elemKey := fmt.Sprintf("%s[%s]@%d", base.ID, keyTxt, root.StartByte())
-			if elem := model.Idents[elemKey]; elem != nil {
-				return []*linage.Identifier{elem}
-			}
-			elem := &linage.Identifier{
-				ID:        elemKey,
-				Name:      base.Name + "[" + keyTxt + "]",
-				Package:   base.Package,
-				File:      base.File,
-				StartByte: root.StartByte(),
-			}
-			model.Idents[elemKey] = elem
+		if elem := a.elementIdent(root, root.ChildByFieldName("object"), src, Scope, model); elem != nil {
 			return []*linage.Identifier{elem}
 		}
 	}
 	// general recursive extraction
 	stack := []*sitter.Node{root}
-	for len(stack) > 0 {
+	for len(stack) > 0 && a.budget.Visit() {
 		n := stack[len(stack)-1]
 		stack = stack[:len(stack)-1]
 		switch n.Type() {
 		case "identifier":
+			if isBlank(n, src) {
+				continue // the blank identifier holds no value
+			}
 			ids = append(ids, a.resolveIdent(n, nil, src, Scope, model))
 		case "selector_expression":
-			op := n.ChildByFieldName("operand")
-			fld := n.ChildByFieldName("field")
-			base := a.resolveIdent(op, nil, src, Scope, model)
-			field := string(src[fld.StartByte():fld.EndByte()])
-			// build selector with operand as parent if no nested selector
-			var parent *linage.Selector
-			if base.Selector != nil {
-				parent = base.Selector
-			} else {
-				parent = &linage.Selector{Field: base.Name}
-			}
-			sel := &linage.Selector{Field: field, Parent: parent}
-			id := a.resolveIdent(fld, sel, src, Scope, model)
-
-			// Attempt to infer kind/type based on the operand (base) identifier.
-			if base != nil {
-				// 1. Struct field access: if operand has a concrete type that we have
-				//    a field mapping for, propagate the field type.
-				if base.Type != "" {
-					if fieldMap, ok := a.structFields[base.Type]; ok {
-						if t, ok2 := fieldMap[field]; ok2 {
-							id.Type = t
-							if id.Kind == "" {
-								id.Kind = "field"
-							}
-						}
-					}
-				} else {
-					// 2. Package selector (e.g. fmt.Printf). Treat the selected
-					//    identifier as a function if it is later invoked, but as a
-					//    heuristic we mark it as func now so it has at least a kind
-					//    and pseudo type.
-					if id.Kind == "" {
-						id.Kind = "func"
-					}
-					if id.Type == "" {
-						// Not an exact signature, but provides useful metadata.
-						id.Type = "func"
-					}
-				}
-			}
-
-			ids = append(ids, id)
+			ids = append(ids, a.resolveSelector(n, src, Scope, model))
 			continue
 		default:
 			for i := int(n.ChildCount()) - 1; i >= 0; i-- {
@@ -135,11 +81,318 @@ func (a *Analyzer) extractIdentifiers(root *sitter.Node, src []byte, Scope *lina
 	return ids
 }
 
+// elementIdent returns a synthetic element identifier of an index expression (e.g. users[id]) over the obj container,
+// typed with the container element type when known; nil without container or index
+func (a *Analyzer) elementIdent(root, obj *sitter.Node, src []byte, Scope *linage.Scope, model *linage.PackageModel) *linage.Identifier {
+	idx := root.ChildByFieldName("index")
+	if obj == nil || idx == nil {
+		return nil
+	}
+	base := a.resolveIdent(obj, nil, src, Scope, model)
+	keyTxt := strings.TrimSpace(string(src[idx.StartByte():idx.EndByte()]))
+	elemKey := fmt.Sprintf("%s[%s]@%d", base.ID, keyTxt, root.StartByte())
+	if elem := model.Idents[elemKey]; elem != nil {
+		return elem
+	}
+	containerType := base.Type
+	if containerType == "" && obj.Type() == "identifier" {
+		containerType = a.paramType(base.Name, Scope, src)
+	}
+	elem := &linage.Identifier{
+		ID:        elemKey,
+		Name:      base.Name + "[" + keyTxt + "]",
+		Package:   base.Package,
+		File:      base.File,
+		StartByte: root.StartByte(),
+		Type:      elementType(containerType),
+	}
+	model.Idents[elemKey] = elem
+	return elem
+}
+
+// resolveSelector resolves a selector chain (e.g. order.Customer.Address.City) segment by segment: each field identifier
+// gets the declared field type of its operand type and the full Selector parent chain; segments of unknown types keep
+// the textual chain without a type. Call and index operands (e.g. getConfig().Timeout, users[id].Name) select fields of
+// the call result and of the container element.
+func (a *Analyzer) resolveSelector(n *sitter.Node, src []byte, Scope *linage.Scope, model *linage.PackageModel) *linage.Identifier {
+	op := n.ChildByFieldName("operand")
+	fld := n.ChildByFieldName("field")
+	var base *linage.Identifier
+	switch op.Type() {
+	case "selector_expression":
+		base = a.resolveSelector(op, src, Scope, model)
+	case "call_expression":
+		base = a.callResult(op, src, Scope, model)
+	case "index_expression":
+		// Go names the container of an index expression operand
+		if base = a.elementIdent(op, op.ChildByFieldName("operand"), src, Scope, model); base == nil {
+			base = a.resolveIdent(op, nil, src, Scope, model)
+		}
+	default:
+		base = a.resolveIdent(op, nil, src, Scope, model)
+	}
+	field := string(src[fld.StartByte():fld.EndByte()])
+	// build selector with operand as parent if no nested selector
+	parent := base.Selector
+	if parent == nil {
+		parent = &linage.Selector{Field: base.Name}
+	}
+	sel := &linage.Selector{Field: field, Parent: parent}
+	id := a.resolveIdent(fld, sel, src, Scope, model)
+
+	baseType := base.Type
+	if baseType == "" && op.Type() == "identifier" {
+		baseType = a.paramType(base.Name, Scope, src) // receiver or parameter, e.g. o of func (o *Order) Add()
+	}
+	// Attempt to infer kind/type based on the operand (base) identifier.
+	switch {
+	case baseType != "":
+		// 1. Struct field access: if operand has a concrete type that we have
+		//    a field mapping for, propagate the field type.
+		fields, local := a.typeFields(Scope, baseType)
+		if t, ok := fields[field]; ok {
+			id.Type = t
+			if !local {
+				id.Ref = graph.NewTypeRef(model.Path, baseTypeName(baseType)).WithMember(field).String()
+			}
+			if id.Kind == "" {
+				id.Kind = "field"
+			}
+		} else if t, ref, ok := a.dependencyField(baseType, field); ok {
+			// external type loaded with WithDependencyRoots
+			id.Type = t
+			id.Ref = ref.String()
+			if id.Kind == "" {
+				id.Kind = "field"
+			}
+		}
+		if labels := a.fieldLabels[strings.TrimPrefix(baseType, "*")][field]; len(labels) > 0 {
+			// field classified from its declaration tags (e.g. pii:"true")
+			id.Labels = graph.AddLabels(id.Labels, labels...)
+		}
+	case op.Type() != "selector_expression" && op.Type() != "call_expression" && op.Type() != "index_expression":
+		// 2. Package selector (e.g. fmt.Printf). Treat the selected
+		//    identifier as a function if it is later invoked, but as a
+		//    heuristic we mark it as func now so it has at least a kind
+		//    and pseudo type.
+		if id.Kind == "" {
+			id.Kind = "func"
+		}
+		if id.Type == "" {
+			// Not an exact signature, but provides useful metadata.
+			id.Type = "func"
+		}
+	}
+	return id
+}
+
+// callResult returns the call site result identifier of a call selected by a field (e.g. getConfig().Timeout), shared
+// with call-return flows of the call and typed with the first declared result of the callee when known
+func (a *Analyzer) callResult(call *sitter.Node, src []byte, Scope *linage.Scope, model *linage.PackageModel) *linage.Identifier {
+	file := strings.TrimPrefix(topFileScope(Scope).ID, model.Path+":")
+	key := fmt.Sprintf("%s::%s::%d#ret0", model.Path, file, call.StartByte())
+	if id := model.Idents[key]; id != nil {
+		return id
+	}
+	fnNode := call.ChildByFieldName("function")
+	id := &linage.Identifier{
+		ID:        key,
+		Name:      strings.TrimSpace(string(src[fnNode.StartByte():fnNode.EndByte()])) + "()",
+		Package:   model.Path,
+		File:      file,
+		StartByte: call.StartByte(),
+		Kind:      "call",
+	}
+	fns, _, _ := a.callTargets(fnNode, src, Scope, model)
+	for _, fn := range fns {
+		if id.Type = a.resultType(fn); id.Type != "" {
+			break
+		}
+	}
+	if id.Type == "" && fnNode.Type() == "identifier" {
+		id.Type = declaredResultType(call, fnNode.Content(src), src)
+	}
+	model.Idents[key] = id
+	return id
+}
+
+// resultType returns the first declared result type of a function, taken from its summary or its signature
+func (a *Analyzer) resultType(fn *linage.Identifier) string {
+	if summary, ok := a.funcSummaries[fn]; ok && len(summary.Results) > 0 {
+		return summary.Results[0]
+	}
+	if fn == nil || fn.Node == nil {
+		return ""
+	}
+	result := firstResult(fn.Node)
+	if result == nil {
+		return ""
+	}
+	// the signature holds the declaration text up to the body, e.g. func getConfig() *Config
+	start, end := int(result.StartByte()-fn.Node.StartByte()), int(result.EndByte()-fn.Node.StartByte())
+	if end > len(fn.Type) {
+		return ""
+	}
+	return strings.TrimSpace(fn.Type[start:end])
+}
+
+// declaredResultType returns the first result type of a function declared later in the file of a call, e.g. a
+// helper function following its caller
+func declaredResultType(call *sitter.Node, name string, src []byte) string {
+	root := call
+	for root.Parent() != nil {
+		root = root.Parent()
+	}
+	for _, decl := range namedChildren(root) {
+		if decl.Type() != "function_declaration" {
+			continue
+		}
+		if nameNode := decl.ChildByFieldName("name"); nameNode == nil || nameNode.Content(src) != name {
+			continue
+		}
+		if result := firstResult(decl); result != nil {
+			return strings.TrimSpace(result.Content(src))
+		}
+	}
+	return ""
+}
+
+// firstResult returns the type node of the first result of a function declaration
+func firstResult(decl *sitter.Node) *sitter.Node {
+	result := decl.ChildByFieldName("result")
+	if result == nil || result.Type() != "parameter_list" {
+		return result
+	}
+	if result.NamedChildCount() == 0 {
+		return nil
+	}
+	return result.NamedChild(0).ChildByFieldName("type")
+}
+
+// elementType returns the element type of a map, slice, array or pointer to array type, e.g. map[string]*User -> *User
+func elementType(containerType string) string {
+	containerType = strings.TrimPrefix(containerType, "*")
+	switch {
+	case strings.HasPrefix(containerType, "map["):
+		depth := 0
+		for i := len("map"); i < len(containerType); i++ {
+			switch containerType[i] {
+			case '[':
+				depth++
+			case ']':
+				if depth--; depth == 0 {
+					return strings.TrimSpace(containerType[i+1:])
+				}
+			}
+		}
+	case strings.HasPrefix(containerType, "["):
+		if index := strings.Index(containerType, "]"); index > 0 {
+			return strings.TrimSpace(containerType[index+1:])
+		}
+	}
+	return ""
+}
+
+// fieldType returns the declared type of a struct field visible in scope, pointer and generic type names (e.g.
+// *Box[int]) are resolved by their base name
+func (a *Analyzer) fieldType(scope *linage.Scope, typeName, field string) (string, bool) {
+	fields, _ := a.typeFields(scope, typeName)
+	fieldType, ok := fields[field]
+	return fieldType, ok
+}
+
+// typeFields returns struct fields of a type visible in scope: types declared in enclosing function or block scopes
+// shadow package types; local reports a type declared in a function, it has no package graph reference
+func (a *Analyzer) typeFields(scope *linage.Scope, typeName string) (fields map[string]string, local bool) {
+	typeName = baseTypeName(typeName)
+	for ; scope != nil && isLocalScope(scope); scope = scope.Parent {
+		if fields, ok := a.structFields[localTypeKey(scope, typeName)]; ok {
+			return fields, true
+		}
+	}
+	// anonymous struct fields of local types are named after the local type key, e.g. fn#row.Meta$anon
+	return a.structFields[typeName], strings.Contains(typeName, "#")
+}
+
+// localTypeKey returns the structFields key of a type declared in a function or block scope
+func localTypeKey(scope *linage.Scope, typeName string) string {
+	return scope.ID + "#" + typeName
+}
+
+// isLocalScope reports whether a scope is a function body or a block within
+func isLocalScope(scope *linage.Scope) bool {
+	return scope.Kind == "function" || scope.Kind == "block"
+}
+
+// isCallee reports whether node is the function of a call expression
+func isCallee(n *sitter.Node) bool {
+	parent := n.Parent()
+	return parent != nil && parent.Type() == "call_expression" && parent.ChildByFieldName("function") == n
+}
+
+// baseTypeName returns a type name without pointer and type arguments, e.g. *Box[int] -> Box
+func baseTypeName(typeName string) string {
+	typeName = strings.TrimPrefix(typeName, "*")
+	if index := strings.Index(typeName, "["); index > 0 {
+		typeName = typeName[:index]
+	}
+	return typeName
+}
+
+// isBlank reports whether node is the blank identifier "_"
+func isBlank(n *sitter.Node, src []byte) bool {
+	return n != nil && n.Type() == "identifier" && string(src[n.StartByte():n.EndByte()]) == "_"
+}
+
+// assignTargets returns identifiers assigned by left-hand side expressions, a blank identifier yields nil so that
+// positions stay aligned with right-hand side values (e.g. _, err := f())
+func (a *Analyzer) assignTargets(left *sitter.Node, src []byte, Scope *linage.Scope, model *linage.PackageModel) []*linage.Identifier {
+	exprs := []*sitter.Node{left}
+	if left.Type() == "expression_list" {
+		exprs = namedChildren(left)
+	}
+	var result []*linage.Identifier
+	for _, expr := range exprs {
+		if isBlank(expr, src) {
+			result = append(result, nil)
+			continue
+		}
+		result = append(result, a.extractIdentifiers(expr, src, Scope, model)...)
+	}
+	return result
+}
+
+// resultTargets returns variables receiving the idx-th of count right-hand side values, a single call assigned to
+// several variables (e.g. data, err := f()) reaches all of them; blank identifiers are skipped
+func resultTargets(lhs []*linage.Identifier, idx, count int) []*linage.Identifier {
+	if count == 1 && len(lhs) > 1 {
+		return nonBlank(lhs)
+	}
+	if idx < len(lhs) && lhs[idx] != nil {
+		return []*linage.Identifier{lhs[idx]}
+	}
+	return nil
+}
+
+// nonBlank returns identifiers without blank placeholders
+func nonBlank(ids []*linage.Identifier) []*linage.Identifier {
+	var result []*linage.Identifier
+	for _, id := range ids {
+		if id != nil {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
 func (a *Analyzer) resolveIdent(n *sitter.Node, sel *linage.Selector, src []byte, Scope *linage.Scope, model *linage.PackageModel) *linage.Identifier {
 	name := string(src[n.StartByte():n.EndByte()])
 	// reuse existing identifiers (vars, types, funcs) in scope
 	if sel == nil {
 		if existing := Scope.Find(name); existing != nil {
+			if a.trace != nil {
+				a.trace.resolved(existing, Scope, name)
+			}
 			return existing
 		}
 	}
@@ -151,6 +404,9 @@ func (a *Analyzer) resolveIdent(n *sitter.Node, sel *linage.Selector, src []byte
 		if id.Selector == nil && sel != nil {
 			id.Selector = sel
 		}
+		if a.trace != nil {
+			a.trace.resolved(id, Scope, name)
+		}
 		return id
 	}
 
@@ -170,6 +426,10 @@ func (a *Analyzer) resolveIdent(n *sitter.Node, sel *linage.Selector, src []byte
 		Node:       n,
 		Annotation: a.extractAnnotations(n, src),
 	}
+	// callees (e.g. builtins) and imported package names are not declared by the scope
+	if _, imported := a.importAliases[name]; sel == nil && !imported && !isCallee(n) {
+		id.Ref = memberRef(Scope, name, model)
+	}
 	model.Idents[key] = id
 	// invoke annotation hooks to allow custom edge creation based on metadata
 	for _, hook := range a.annotationHooks {
@@ -195,5 +455,8 @@ func (a *Analyzer) resolveIdent(n *sitter.Node, sel *linage.Selector, src []byte
 	if sel == nil { // only plain identifiers go into symbol tables
 		Scope.Symbols[name] = id
 	}
+	if a.trace != nil {
+		a.trace.resolved(id, Scope, name)
+	}
 	return id
 }
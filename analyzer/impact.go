@@ -0,0 +1,399 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/viant/linager/analyzer/linage"
+	"github.com/viant/linager/inspector/graph"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Impact finding categories
+const (
+	ImpactReference  = "reference"
+	ImpactDownstream = "downstream"
+	ImpactContract   = "contract"
+	ImpactTest       = "test"
+)
+
+// serializedTags lists struct tags exposing a field name in serialized payloads
+var serializedTags = []string{"json", "yaml", "xml"}
+
+// columnTags lists struct tags mapping a field to a storage column
+var columnTags = []string{"db", "sql", "gorm", "bigquery"}
+
+var columnAnnotation = regexp.MustCompile(`@Column\s*\(\s*(?:name\s*=\s*)?"([^"]+)"`)
+
+// FieldRef identifies a struct field
+type FieldRef struct {
+	Package string `json:"package,omitempty"` // package name or import path, empty matches any package
+	Type    string `json:"type"`
+	Field   string `json:"field"`
+}
+
+// String returns field reference as Type.Field
+func (f FieldRef) String() string {
+	return f.Type + "." + f.Field
+}
+
+// ImpactFinding describes a single place affected by a field change
+type ImpactFinding struct {
+	Category string `json:"category"`
+	Kind     string `json:"kind,omitempty"` // read, write, identifier, call, json, yaml, xml, column, serialization
+	Name     string `json:"name"`
+	Package  string `json:"package,omitempty"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+}
+
+// ImpactRisk summarizes findings per category
+type ImpactRisk struct {
+	Level      string `json:"level"` // high (external contract), medium (cross package or downstream flows), low
+	References int    `json:"references"`
+	Downstream int    `json:"downstream"`
+	Contracts  int    `json:"contracts"`
+	Tests      int    `json:"tests"`
+	Packages   int    `json:"packages"` // packages referencing the field
+}
+
+// ImpactReport lists everything affected by renaming a struct field
+type ImpactReport struct {
+	Field       FieldRef         `json:"field"`
+	Declaration *ImpactFinding   `json:"declaration,omitempty"`
+	References  []*ImpactFinding `json:"references"`
+	Downstream  []*ImpactFinding `json:"downstream"`
+	Contracts   []*ImpactFinding `json:"contracts"`
+	Tests       []*ImpactFinding `json:"tests"`
+	Risk        ImpactRisk       `json:"risk"`
+	usages      map[string]bool  // data point IDs linked to the field, see linage.AttachGraph
+}
+
+// ImpactAnalysis combines the project graph (declaration, tags, annotations) with the lineage model
+// (field references and XFER flows) to report the impact of renaming a struct field
+func ImpactAnalysis(project *graph.Project, model *linage.PackageModel, ref FieldRef) (*ImpactReport, error) {
+	report := &ImpactReport{Field: ref}
+	if project != nil {
+		pkg, file, field := lookupField(project, ref)
+		if field == nil {
+			return nil, &graph.ErrNotFound{Kind: "field", Name: ref.String()}
+		}
+		report.Declaration = &ImpactFinding{Category: "declaration", Kind: "field", Name: ref.String(), Package: pkg.ImportPath, File: file.Path}
+		report.Contracts = contractFindings(field, report.Declaration)
+		if len(field.UsageRefs) > 0 {
+			report.usages = map[string]bool{}
+			for _, usage := range field.UsageRefs {
+				report.usages[usage] = true
+			}
+		}
+	}
+	if model != nil {
+		report.addModelFindings(model)
+	}
+	report.summarize()
+	return report, nil
+}
+
+// lookupField finds a field declaration in the project
+func lookupField(project *graph.Project, ref FieldRef) (*graph.Package, *graph.File, *graph.Field) {
+	for _, pkg := range project.Packages {
+		if ref.Package != "" && pkg.Name != ref.Package && pkg.ImportPath != ref.Package {
+			continue
+		}
+		for _, file := range pkg.FileSet {
+			for _, aType := range file.Types {
+				if aType.Name != ref.Type {
+					continue
+				}
+				if field := aType.GetField(ref.Field); field != nil {
+					return pkg, file, field
+				}
+			}
+		}
+	}
+	return nil, nil, nil
+}
+
+// contractFindings returns serialized names and storage columns exposed by field tags and annotations, columns of
+// fields mapped by graph.Project MapModels are qualified by their table
+func contractFindings(field *graph.Field, declaration *ImpactFinding) []*ImpactFinding {
+	var result []*ImpactFinding
+	add := func(kind, name string) {
+		if name == "" || name == "-" {
+			return
+		}
+		result = append(result, &ImpactFinding{Category: ImpactContract, Kind: kind, Name: name, Package: declaration.Package, File: declaration.File, Line: declaration.Line})
+	}
+	for _, tag := range serializedTags {
+		if value, ok := field.Tag.Lookup(tag); ok {
+			add(tag, strings.Split(value, ",")[0])
+		}
+	}
+	if field.Column != "" {
+		add("column", field.Column)
+	}
+	for _, tag := range columnTags {
+		value, ok := field.Tag.Lookup(tag)
+		if !ok || field.Column != "" {
+			continue
+		}
+		if tag == "gorm" {
+			// gorm:"column:email;not null"
+			for _, part := range strings.Split(value, ";") {
+				if strings.HasPrefix(part, "column:") {
+					add("column", strings.TrimPrefix(part, "column:"))
+				}
+			}
+			continue
+		}
+		add("column", strings.Split(value, ",")[0])
+	}
+	if match := columnAnnotation.FindStringSubmatch(field.Annotation); len(match) > 1 {
+		add("column", match[1])
+	}
+	return result
+}
+
+// addModelFindings collects field references, downstream identifiers and calls receiving the field value
+func (r *ImpactReport) addModelFindings(model *linage.PackageModel) {
+	written := map[string]bool{}
+	called := map[string]bool{}
+	adj := map[string][]*linage.Identifier{}
+	for _, edge := range model.DataFlows {
+		switch edge.Kind {
+		case linage.Write:
+			written[edge.Dst.ID] = true
+		case linage.Call:
+			called[edge.Src.ID] = true
+		case linage.Xfer:
+			adj[edge.Src.ID] = append(adj[edge.Src.ID], edge.Dst)
+		}
+	}
+
+	var refs []*linage.Identifier
+	for _, id := range model.Idents {
+		if r.isReference(id) || r.isLiteralKey(id, model) {
+			refs = append(refs, id)
+		}
+	}
+	sort.Slice(refs, func(i, j int) bool { return refs[i].ID < refs[j].ID })
+	// a composite literal key is also recorded as a field of the literal variable at the same position, e.g. user.Email
+	positions := map[string]bool{}
+	unique := refs[:0]
+	for _, id := range refs {
+		position := fmt.Sprintf("%s:%s:%d", id.Package, id.File, id.StartByte)
+		if positions[position] {
+			continue
+		}
+		positions[position] = true
+		unique = append(unique, id)
+	}
+	refs = unique
+
+	visited := map[string]bool{}
+	for _, id := range refs {
+		visited[id.ID] = true
+	}
+	for _, id := range refs {
+		finding := identFinding(ImpactReference, id)
+		finding.Kind = "read"
+		if id.Selector == nil { // composite literal key, e.g. User{Email: email}
+			finding.Kind, finding.Name = "write", r.Field.String()
+		} else if written[id.ID] {
+			finding.Kind = "write"
+		}
+		if isTestFile(id.File) {
+			finding.Category = ImpactTest
+			r.Tests = append(r.Tests, finding)
+			continue
+		}
+		r.References = append(r.References, finding)
+		queue := []*linage.Identifier{id}
+		for len(queue) > 0 {
+			cur := queue[0]
+			queue = queue[1:]
+			for _, next := range adj[cur.ID] {
+				if visited[next.ID] {
+					continue
+				}
+				visited[next.ID] = true
+				downstream := identFinding(ImpactDownstream, next)
+				downstream.Kind = "identifier"
+				if called[next.ID] {
+					downstream.Kind = "call"
+				} else if next.Kind == linage.SQLColumnKind {
+					downstream.Kind = "column"
+				}
+				r.Downstream = append(r.Downstream, downstream)
+				queue = append(queue, next)
+			}
+		}
+	}
+	// values of the field type crossing a serialization boundary expose the field in their payloads
+	for _, point := range model.SerializationPoints() {
+		typeName := point.StructType[strings.LastIndex(point.StructType, ".")+1:]
+		if typeName != r.Field.Type || isTestFile(point.Boundary.File) {
+			continue
+		}
+		finding := identFinding(ImpactContract, point.Boundary)
+		finding.Kind = "serialization"
+		r.Contracts = append(r.Contracts, finding)
+	}
+}
+
+// isReference reports whether identifier selects the analyzed field, identifiers linked to graph elements by
+// linage.AttachGraph are matched by their links, others by the selected field name and the owner type of their
+// resolved reference; selectors of unresolved types match by field name only
+func (r *ImpactReport) isReference(id *linage.Identifier) bool {
+	if r.usages[id.ID] {
+		return true
+	}
+	if id.Selector == nil || id.Selector.Field != r.Field.Field {
+		return false
+	}
+	if id.Ref == "" {
+		return true
+	}
+	if len(r.usages) > 0 {
+		return false
+	}
+	ref, err := graph.ParseRef(id.Ref)
+	return err == nil && (ref.Type == r.Field.Type || strings.HasSuffix(ref.Type, "."+r.Field.Type))
+}
+
+// isLiteralKey reports whether identifier is the analyzed field used as a composite literal key of the analyzed type
+func (r *ImpactReport) isLiteralKey(id *linage.Identifier, model *linage.PackageModel) bool {
+	if id.Selector != nil || id.Name != r.Field.Field {
+		return false
+	}
+	name := literalType(id, model)
+	if index := strings.LastIndex(name, "."); index != -1 {
+		name = name[index+1:]
+	}
+	return name != "" && name == r.Field.Type
+}
+
+// literalType returns the type of the composite literal an identifier is a key of as written, e.g. model.User for
+// model.User{Email: email}, empty if the identifier is not a literal key or the type is not an identifier
+func literalType(id *linage.Identifier, model *linage.PackageModel) string {
+	node := literalOf(id)
+	if node == nil {
+		return ""
+	}
+	typeNode := node.ChildByFieldName("type")
+	if typeNode == nil {
+		return ""
+	}
+	// type identifiers are keyed by their position in the same file
+	prefix := id.ID[:strings.LastIndex(id.ID, "::")]
+	typeIdent, ok := model.Idents[fmt.Sprintf("%s::%d", prefix, typeNode.StartByte())]
+	if !ok {
+		return ""
+	}
+	return typeIdent.Name
+}
+
+// literalOf returns the composite literal an identifier is a key of, nil if the identifier is not a literal key
+func literalOf(id *linage.Identifier) *sitter.Node {
+	if id.Selector != nil || id.Node == nil {
+		return nil
+	}
+	child, node := id.Node, id.Node.Parent()
+	for node != nil && node.Type() != "composite_literal" {
+		switch node.Type() {
+		case "keyed_element":
+			if key := node.NamedChild(0); key == nil || key.StartByte() != child.StartByte() {
+				return nil // a value of the literal
+			}
+		case "literal_element", "literal_value":
+		default:
+			return nil
+		}
+		child, node = node, node.Parent()
+	}
+	return node
+}
+
+// summarize computes risk summary
+func (r *ImpactReport) summarize() {
+	packages := map[string]bool{}
+	for _, finding := range r.References {
+		packages[finding.Package] = true
+	}
+	r.Risk = ImpactRisk{
+		References: len(r.References),
+		Downstream: len(r.Downstream),
+		Contracts:  len(r.Contracts),
+		Tests:      len(r.Tests),
+		Packages:   len(packages),
+		Level:      "low",
+	}
+	switch {
+	case r.Risk.Contracts > 0:
+		r.Risk.Level = "high"
+	case r.Risk.Packages > 1 || r.Risk.Downstream > 0:
+		r.Risk.Level = "medium"
+	}
+}
+
+// JSON renders the report as indented JSON
+func (r *ImpactReport) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// Markdown renders the report as a Markdown document
+func (r *ImpactReport) Markdown() string {
+	builder := &strings.Builder{}
+	fmt.Fprintf(builder, "# Impact of renaming %s\n\n", r.Field.String())
+	if r.Declaration != nil {
+		fmt.Fprintf(builder, "Declared in `%s` (%s).\n\n", r.Declaration.File, r.Declaration.Package)
+	}
+	builder.WriteString("## Risk\n\n| Category | Count |\n| --- | --- |\n")
+	fmt.Fprintf(builder, "| References | %d |\n| Downstream | %d |\n| Contracts | %d |\n| Tests | %d |\n| Packages | %d |\n\n", r.Risk.References, r.Risk.Downstream, r.Risk.Contracts, r.Risk.Tests, r.Risk.Packages)
+	fmt.Fprintf(builder, "Risk level: **%s**\n", r.Risk.Level)
+	for _, section := range []struct {
+		title    string
+		findings []*ImpactFinding
+	}{
+		{"References", r.References},
+		{"Downstream", r.Downstream},
+		{"Contracts", r.Contracts},
+		{"Tests", r.Tests},
+	} {
+		fmt.Fprintf(builder, "\n## %s\n\n", section.title)
+		if len(section.findings) == 0 {
+			builder.WriteString("_None._\n")
+			continue
+		}
+		builder.WriteString("| Kind | Name | Package | Location |\n| --- | --- | --- | --- |\n")
+		for _, finding := range section.findings {
+			location := finding.File
+			if finding.Line > 0 {
+				location = fmt.Sprintf("%s:%d", finding.File, finding.Line)
+			}
+			fmt.Fprintf(builder, "| %s | `%s` | %s | %s |\n", finding.Kind, finding.Name, finding.Package, location)
+		}
+	}
+	return builder.String()
+}
+
+// identFinding creates a finding for an identifier, the line is resolved from the retained AST node
+func identFinding(category string, id *linage.Identifier) *ImpactFinding {
+	name := id.Name
+	if id.Selector != nil {
+		name = selectorPath(id.Selector)
+	}
+	// function identifiers keep the file scope ID (package:file) as their file
+	finding := &ImpactFinding{Category: category, Name: name, Package: id.Package, File: strings.TrimPrefix(id.File, id.Package+":")}
+	if id.Node != nil {
+		finding.Line = int(id.Node.StartPoint().Row) + 1
+	}
+	return finding
+}
+
+// isTestFile reports whether file holds tests
+func isTestFile(file string) bool {
+	return strings.HasSuffix(file, "_test.go") || strings.HasSuffix(file, "Test.java") || strings.HasSuffix(file, "Tests.java")
+}
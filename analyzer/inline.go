@@ -0,0 +1,182 @@
+package analyzer
+
+import (
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/viant/linager/analyzer/linage"
+	"strings"
+)
+
+// InlineFlow describes a return value that selects a parameter or one of its fields (e.g. u.Name)
+type InlineFlow struct {
+	Param int      // formal parameter index
+	Path  []string // selected field path, empty when the parameter itself is returned
+}
+
+// callSite captures a call-return site considered for inlining
+type callSite struct {
+	fn       *linage.Identifier
+	scope    *linage.Scope
+	actuals  []*linage.Identifier // actual argument identifiers, nil for complex argument expressions
+	lhs      []*linage.Identifier
+	callRets []*linage.Identifier
+}
+
+// recordInlineFlow records whether a returned expression selects a formal parameter (field)
+func (a *Analyzer) recordInlineFlow(summary *FuncSummary, retIdx int, expr *sitter.Node, src []byte) {
+	if summary.Inline == nil {
+		summary.Inline = map[int][]InlineFlow{}
+		summary.opaque = map[int]bool{}
+	}
+	var path []string
+	node := expr
+	for node != nil && node.Type() == "parenthesized_expression" && node.NamedChildCount() == 1 {
+		node = node.NamedChild(0)
+	}
+	for node != nil && node.Type() == "selector_expression" {
+		field := node.ChildByFieldName("field")
+		if field == nil {
+			break
+		}
+		path = append([]string{string(src[field.StartByte():field.EndByte()])}, path...)
+		node = node.ChildByFieldName("operand")
+	}
+	if node == nil || node.Type() != "identifier" {
+		summary.opaque[retIdx] = true
+		return
+	}
+	name := string(src[node.StartByte():node.EndByte()])
+	for pIdx, param := range summary.Params {
+		if param.Name == name {
+			summary.Inline[retIdx] = append(summary.Inline[retIdx], InlineFlow{Param: pIdx, Path: path})
+			return
+		}
+	}
+	summary.opaque[retIdx] = true
+}
+
+// markRecursion flags the enclosing function summary as recursive when it calls itself
+func (a *Analyzer) markRecursion(fns []*linage.Identifier, scope *linage.Scope) {
+	fnScope := enclosingFunction(scope)
+	if fnScope == nil || fnScope.Parent == nil {
+		return
+	}
+	self := fnScope.Parent.Symbols[fnScope.Name]
+	for _, fn := range fns {
+		if fn != self {
+			continue
+		}
+		if summary, ok := a.funcSummaries[self]; ok {
+			summary.Recursive = true
+		}
+	}
+}
+
+// recordCallSite keeps a call-return site to be inlined once the file is analyzed
+func (a *Analyzer) recordCallSite(fn *linage.Identifier, call *sitter.Node, src []byte, scope *linage.Scope, model *linage.PackageModel, lhs, callRets []*linage.Identifier) {
+	site := &callSite{fn: fn, scope: scope, lhs: lhs, callRets: callRets}
+	for _, arg := range namedChildren(call.ChildByFieldName("arguments")) {
+		var actual *linage.Identifier
+		if arg.Type() == "identifier" || arg.Type() == "selector_expression" {
+			if ids := a.extractIdentifiers(arg, src, scope, model); len(ids) == 1 {
+				actual = ids[0]
+			}
+		}
+		site.actuals = append(site.actuals, actual)
+	}
+	a.callSites = append(a.callSites, site)
+}
+
+// inlineCallSites replaces call-return identifiers of trivial, non-recursive functions with direct
+// flows from the selected argument fields to the call-site variables; CALL edges are preserved.
+func (a *Analyzer) inlineCallSites(model *linage.PackageModel) {
+	sites := a.callSites
+	a.callSites = nil
+	replaced := map[string]bool{}
+	for _, site := range sites {
+		summary, ok := a.funcSummaries[site.fn]
+		if !ok || summary.Recursive || summary.Statements == 0 || summary.Statements > a.inlineMaxStatements {
+			continue
+		}
+		var edges []*linage.DataFlowEdge
+		inlinable := true
+		for retIdx, dst := range site.lhs {
+			if retIdx >= len(site.callRets) {
+				break
+			}
+			if dst == nil {
+				continue
+			}
+			flows := summary.Inline[retIdx]
+			if summary.opaque[retIdx] || len(flows) == 0 {
+				inlinable = false
+				break
+			}
+			for _, flow := range flows {
+				if flow.Param >= len(site.actuals) || site.actuals[flow.Param] == nil {
+					inlinable = false
+					break
+				}
+				selected := a.selectIdent(site.actuals[flow.Param], flow.Path, model)
+				edges = append(edges, &linage.DataFlowEdge{Src: selected, Dst: dst, Kind: linage.Xfer, Scope: site.scope.ID})
+			}
+		}
+		if !inlinable {
+			continue
+		}
+		for _, ret := range site.callRets {
+			replaced[ret.ID] = true
+			delete(model.Idents, ret.ID)
+		}
+		if a.trace != nil {
+			a.trace.summary(site.fn, nil, "inline")
+		}
+		model.DataFlows = append(model.DataFlows, edges...)
+		a.traceStep(model, "inline")
+	}
+	if len(replaced) == 0 {
+		return
+	}
+	flows := model.DataFlows[:0]
+	for _, edge := range model.DataFlows {
+		if replaced[edge.Src.ID] || replaced[edge.Dst.ID] {
+			continue
+		}
+		flows = append(flows, edge)
+	}
+	model.DataFlows = flows
+	if a.trace != nil {
+		a.trace.sync(model)
+	}
+}
+
+// selectIdent returns a field identifier selected from base by path (e.g. user -> user.Name)
+func (a *Analyzer) selectIdent(base *linage.Identifier, path []string, model *linage.PackageModel) *linage.Identifier {
+	if len(path) == 0 {
+		return base
+	}
+	key := base.ID + "." + strings.Join(path, ".")
+	if id, ok := model.Idents[key]; ok {
+		return id
+	}
+	parent := base.Selector
+	if parent == nil {
+		parent = &linage.Selector{Field: base.Name}
+	}
+	typeName := base.Type
+	for _, field := range path {
+		parent = &linage.Selector{Field: field, Parent: parent}
+		typeName, _ = a.fieldType(nil, typeName, field)
+	}
+	id := &linage.Identifier{
+		ID:        key,
+		Name:      path[len(path)-1],
+		Kind:      "field",
+		Package:   base.Package,
+		File:      base.File,
+		StartByte: base.StartByte,
+		Type:      typeName,
+		Selector:  parent,
+	}
+	model.Idents[key] = id
+	return id
+}
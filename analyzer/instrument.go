@@ -0,0 +1,198 @@
+package analyzer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/viant/afs/url"
+	"github.com/viant/linager/analyzer/linage"
+	"github.com/viant/linager/inspector/coder"
+	"github.com/viant/linager/inspector/graph"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// runtimeImport is the import path of the runtime package instrumented files call
+const runtimeImport = "github.com/viant/linager/linagerruntime"
+
+// InstrumentSelection selects symbols whose runtime accesses instrumented programs record
+type InstrumentSelection struct {
+	Functions []*graph.Function // functions and methods of the coder project, touched at entry
+	Fields    []FieldRef        // fields touched after each write site, matched by field name as ImpactAnalysis does
+}
+
+// instrumentedFile collects splices of a project file
+type instrumentedFile struct {
+	file    *graph.File
+	source  []byte
+	splices []*coder.Splice
+	offsets map[int]bool
+}
+
+// Instrument stores a copy of the coder project to URL with linagerruntime.Touch calls at entry of selected functions
+// and after lines writing selected fields, points are data points of the project analysis. Touched references are data
+// point IDs, so that hits recorded by the instrumented program can be merged back with MergeRuntimeHits. Original
+// sources are spliced, the module of the copy has to resolve github.com/viant/linager.
+func Instrument(ctx context.Context, aCoder *coder.Coder, points []*linage.DataPoint, selection *InstrumentSelection, URL string) (*coder.StoreReport, error) {
+	project := aCoder.Project
+	if project == nil {
+		return nil, fmt.Errorf("no project to instrument")
+	}
+	owners := map[*graph.Function]*graph.File{}
+	files := map[string]*graph.File{} // absolute path -> file
+	for _, pkg := range project.Packages {
+		for _, file := range pkg.FileSet {
+			files[projectFilePath(project, file)] = file
+			for _, function := range file.Functions {
+				owners[function] = file
+			}
+			for _, aType := range file.Types {
+				for _, method := range aType.Methods {
+					owners[method] = file
+				}
+			}
+		}
+	}
+	functionIDs := map[string]string{} // package directory|file base:Type.Name -> data point ID
+	for _, point := range points {
+		index := strings.LastIndex(point.File, ":")
+		if key := functionPointKey(point); key != "" && index != -1 {
+			functionIDs[filepath.Clean(url.Path(point.File[:index]))+"|"+key] = point.ID
+		}
+	}
+
+	instrumented := map[string]*instrumentedFile{}
+	lookup := func(location string) (*instrumentedFile, error) {
+		if ret, ok := instrumented[location]; ok {
+			return ret, nil
+		}
+		file, ok := files[location]
+		if !ok {
+			return nil, &graph.ErrNotFound{Kind: "file", Name: location}
+		}
+		source, err := os.ReadFile(location)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", location, graph.NotFoundError("file", location, err))
+		}
+		ret := &instrumentedFile{file: file, source: source, offsets: map[int]bool{}}
+		instrumented[location] = ret
+		return ret, nil
+	}
+
+	for _, function := range selection.Functions {
+		file, ok := owners[function]
+		if !ok || function.Body == nil {
+			return nil, &graph.ErrNotFound{Kind: "function body", Name: function.Name}
+		}
+		location := projectFilePath(project, file)
+		symbol := function.Name
+		if receiver := strings.TrimPrefix(function.Receiver, "*"); receiver != "" {
+			receiver, _, _ = strings.Cut(receiver, "[")
+			symbol = receiver + "." + symbol
+		}
+		ID, ok := functionIDs[filepath.Dir(location)+"|"+filepath.Base(location)+":"+symbol]
+		if !ok {
+			return nil, &graph.ErrNotFound{Kind: "data point", Name: symbol}
+		}
+		target, err := lookup(location)
+		if err != nil {
+			return nil, err
+		}
+		target.insertAtEntry(function.Body.Location.Start+1, "linagerruntime.Touch("+strconv.Quote(ID)+", linagerruntime.KindCall)")
+	}
+
+	fields := map[string]bool{}
+	for _, field := range selection.Fields {
+		fields[field.Field] = true
+	}
+	for _, point := range points {
+		if point.Selector == nil || !fields[point.Selector.Field] || len(point.Writes) == 0 {
+			continue
+		}
+		target, err := lookup(filepath.Join(url.Path(point.Package), point.File))
+		if err != nil {
+			return nil, err
+		}
+		target.insertAfterLine(int(point.StartByte), "linagerruntime.Touch("+strconv.Quote(point.ID)+", linagerruntime.KindWrite)")
+	}
+
+	var splices []*coder.Splice
+	for location, target := range instrumented {
+		parsed, err := parser.ParseFile(token.NewFileSet(), location, target.source, parser.ImportsOnly)
+		if err != nil {
+			return nil, &graph.ErrParse{Path: location, Cause: err}
+		}
+		imported := false
+		for _, spec := range parsed.Imports {
+			imported = imported || spec.Path.Value == strconv.Quote(runtimeImport)
+		}
+		if !imported {
+			target.insert(int(parsed.Name.End())-1, "\n\nimport linagerruntime "+strconv.Quote(runtimeImport))
+		}
+		splices = append(splices, target.splices...)
+	}
+	return aCoder.StoreProject(ctx, URL, coder.WithSplices(splices...))
+}
+
+// insert adds a splice, text inserted at an offset already spliced is skipped
+func (f *instrumentedFile) insert(offset int, text string) {
+	if f.offsets[offset] {
+		return
+	}
+	f.offsets[offset] = true
+	f.splices = append(f.splices, &coder.Splice{Path: f.file.Path, Offset: offset, Text: text})
+}
+
+// insertAtEntry adds a statement after the opening brace of a function body ending at offset, one line bodies are split
+func (f *instrumentedFile) insertAtEntry(offset int, statement string) {
+	text := "\n\t" + statement
+	if offset >= len(f.source) || f.source[offset] != '\n' {
+		text += "\n"
+	}
+	f.insert(offset, text)
+}
+
+// insertAfterLine adds a statement after the line containing offset, indented as the line
+func (f *instrumentedFile) insertAfterLine(offset int, statement string) {
+	if offset > len(f.source) {
+		return
+	}
+	line := f.source[bytes.LastIndexByte(f.source[:offset], '\n')+1:]
+	indent := line[:len(line)-len(bytes.TrimLeft(line, " \t"))]
+	end := bytes.IndexByte(f.source[offset:], '\n')
+	if end == -1 {
+		f.insert(len(f.source), "\n"+string(indent)+statement+"\n")
+		return
+	}
+	f.insert(offset+end+1, string(indent)+statement+"\n")
+}
+
+// projectFilePath returns absolute path of a project file
+func projectFilePath(project *graph.Project, file *graph.File) string {
+	if filepath.IsAbs(file.Path) || project.RootPath == "" {
+		return filepath.Clean(file.Path)
+	}
+	return filepath.Join(project.RootPath, file.Path)
+}
+
+// MergeRuntimeHits records hit counts of runtime references read with linagerruntime.ReadHits in Metadata["runtimeHits"]
+// of data points with matching IDs and returns the number of matched points
+func MergeRuntimeHits(points []*linage.DataPoint, hits map[string]int) int {
+	matched := 0
+	for _, point := range points {
+		count, ok := hits[point.ID]
+		if !ok {
+			continue
+		}
+		if point.Metadata == nil {
+			point.Metadata = map[string]interface{}{}
+		}
+		point.Metadata["runtimeHits"] = count
+		matched++
+	}
+	return matched
+}
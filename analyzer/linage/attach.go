@@ -0,0 +1,300 @@
+package linage
+
+import (
+	"path"
+	"strings"
+
+	"github.com/viant/linager/inspector/graph"
+	"github.com/viant/linager/inspector/repository"
+)
+
+// Unresolved data point reasons, see AttachGraph
+const (
+	ReasonNoRef        = "no reference" // the analyzer assigned no canonical reference, e.g. builtins or fields of unknown types
+	ReasonExternal     = "external"     // declared outside of the project, e.g. standard library or dependencies
+	ReasonGenerated    = "generated"    // declared in a generated file
+	ReasonNotInspected = "not inspected"
+)
+
+// UnresolvedPoint is a data point without a graph element
+type UnresolvedPoint struct {
+	Point  *DataPoint `json:"point"`
+	Reason string     `json:"reason"`
+}
+
+// GraphLinks summarizes data points linked by AttachGraph
+type GraphLinks struct {
+	Linked     int                `json:"linked"`
+	Unresolved []*UnresolvedPoint `json:"unresolved,omitempty"`
+}
+
+// Ratio returns the share of linked project data points, external points are not counted; 1 when there are none
+func (l *GraphLinks) Ratio() float64 {
+	total := l.Linked
+	for _, unresolved := range l.Unresolved {
+		if unresolved.Reason != ReasonExternal {
+			total++
+		}
+	}
+	if total == 0 {
+		return 1
+	}
+	return float64(l.Linked) / float64(total)
+}
+
+// AttachGraph links data points to project graph elements by canonical reference (see graph.Ref): the reference
+// package, a model path, is mapped to the inspected package of the same directory or import path, call sites without
+// a reference resolve to their callee and local variables to their enclosing function. Each linked point gets
+// GraphRef and its ID is added to UsageRefs of the element; other points are reported with a reason
+func AttachGraph(points []*DataPoint, project *graph.Project) *GraphLinks {
+	linker := newGraphLinker(project)
+	callees := map[CodeLocation]string{} // call site -> callee reference
+	for _, point := range points {
+		for _, edge := range point.Calls {
+			if site := edge.Site(); site != nil && point.Ref != "" {
+				callees[siteKey(site)] = point.Ref
+			}
+		}
+	}
+	linker.indexImports(points)
+	links := &GraphLinks{}
+	for _, point := range points {
+		ref := point.Ref
+		if ref == "" {
+			ref = callees[siteKey(&point.Definition)]
+		}
+		var target *graph.RefTarget
+		var reason string
+		if ref == "" {
+			target, reason = linker.resolveCallee(point)
+		} else {
+			target, reason = linker.resolve(point, ref)
+		}
+		if target == nil {
+			links.Unresolved = append(links.Unresolved, &UnresolvedPoint{Point: point, Reason: reason})
+			continue
+		}
+		point.GraphRef = target.Ref.String()
+		addUsage(target, point.ID)
+		links.Linked++
+	}
+	return links
+}
+
+// addUsage adds a data point ID to UsageRefs of the innermost element of a target
+func addUsage(target *graph.RefTarget, id string) {
+	var usages *[]string
+	switch {
+	case target.Parameter != nil:
+		usages = &target.Parameter.UsageRefs
+	case target.Function != nil:
+		usages = &target.Function.UsageRefs
+	case target.Field != nil:
+		usages = &target.Field.UsageRefs
+	case target.Type != nil:
+		usages = &target.Type.UsageRefs
+	case target.Variable != nil:
+		usages = &target.Variable.UsageRefs
+	case target.Constant != nil:
+		usages = &target.Constant.UsageRefs
+	default:
+		return
+	}
+	for _, existing := range *usages {
+		if existing == id {
+			return
+		}
+	}
+	*usages = append(*usages, id)
+}
+
+// siteKey returns a call site lookup key, file paths of call edges and data points are relative to different roots
+func siteKey(site *CodeLocation) CodeLocation {
+	return CodeLocation{FilePath: path.Base(site.FilePath), LineNumber: site.LineNumber, ColumnStart: site.ColumnStart}
+}
+
+// builtins holds predeclared Go functions and types, calls and conversions of which have no graph element
+var builtins = map[string]bool{
+	"append": true, "cap": true, "clear": true, "close": true, "complex": true, "copy": true, "delete": true,
+	"imag": true, "len": true, "make": true, "max": true, "min": true, "new": true, "panic": true, "print": true,
+	"println": true, "real": true, "recover": true, "any": true, "bool": true, "byte": true, "complex64": true,
+	"complex128": true, "error": true, "float32": true, "float64": true, "int": true, "int8": true, "int16": true,
+	"int32": true, "int64": true, "rune": true, "string": true, "uint": true, "uint8": true, "uint16": true,
+	"uint32": true, "uint64": true, "uintptr": true,
+}
+
+// graphLinker resolves canonical references of analyzed packages against a project
+type graphLinker struct {
+	project *graph.Project
+	byDir   map[string]*graph.Package // inspected package by source directory
+	imports map[string]bool           // imported package names by file, see importKey
+}
+
+// newGraphLinker creates a linker indexing project packages by directory
+func newGraphLinker(project *graph.Project) *graphLinker {
+	ret := &graphLinker{project: project, byDir: map[string]*graph.Package{}, imports: map[string]bool{}}
+	for _, pkg := range project.Packages {
+		for _, file := range pkg.FileSet {
+			ret.byDir[path.Dir(file.Path)] = pkg
+		}
+	}
+	return ret
+}
+
+// indexImports records package names of external imports, the analyzer assigns them the imported package path
+func (l *graphLinker) indexImports(points []*DataPoint) {
+	for _, point := range points {
+		if point.Ref != "" || point.Kind != "" || point.Selector != nil || point.Package == "" {
+			continue
+		}
+		if l.lookupPackage(point.Package) == nil && !l.isProjectPath(point.Package) {
+			l.imports[importKey(point.File, point.Name)] = true
+		}
+	}
+}
+
+// isImportMember reports whether a point selects a member of an imported package, e.g. TrimSpace of strings.TrimSpace
+func (l *graphLinker) isImportMember(point *DataPoint) bool {
+	parent := point.Selector
+	if parent != nil {
+		parent = parent.Parent
+	}
+	return parent != nil && parent.Parent == nil && l.imports[importKey(point.File, parent.Field)]
+}
+
+// importKey returns an imported package name lookup key
+func importKey(file, name string) string {
+	return path.Base(file) + ":" + name
+}
+
+// resolve returns the graph element of a data point reference or the reason it is unresolved
+func (l *graphLinker) resolve(point *DataPoint, text string) (*graph.RefTarget, string) {
+	ref, err := graph.ParseRef(text)
+	if err != nil {
+		return nil, ReasonNoRef
+	}
+	pkg := l.lookupPackage(ref.Package)
+	if pkg == nil {
+		if l.isProjectPath(ref.Package) {
+			return nil, l.notInspected(point)
+		}
+		return nil, ReasonExternal
+	}
+	ref.Package = pkg.Ref().Package
+	if target := l.project.ByRef(ref.String()); target != nil {
+		return target, ""
+	}
+	if ref.Kind() == graph.RefKindVariable { // local variables are not graph elements
+		if target := l.project.ByRef(ref.Owner().String()); target != nil {
+			return target, ""
+		}
+	}
+	return nil, l.notInspected(point)
+}
+
+// resolveCallee resolves a data point without a reference by the qualified callee of its CALL edges (e.g.
+// Order.Add) or by its package function name (e.g. normalize used before its declaration), builtins, imported
+// package names and their members are external
+func (l *graphLinker) resolveCallee(point *DataPoint) (*graph.RefTarget, string) {
+	pkg := l.lookupPackage(point.Package)
+	if point.Kind == "external" || pkg == nil && !l.isProjectPath(point.Package) || l.isImportMember(point) {
+		return nil, ReasonExternal
+	}
+	for _, edge := range point.Calls {
+		callee, _ := edge.Attributes[CallRefAttribute].(string)
+		switch kind := edge.CallKind(); {
+		case kind == CallExternal:
+			return nil, ReasonExternal
+		case pkg == nil || callee == "":
+			continue
+		}
+		typeName, name, isMethod := strings.Cut(callee, ".")
+		if !isMethod {
+			name, typeName = typeName, ""
+		}
+		if target := calleeTarget(pkg, typeName, name); target != nil {
+			return target, ""
+		}
+		if !isMethod && edge.CallKind() == CallFunction {
+			return nil, ReasonExternal // builtin, e.g. len or append
+		}
+	}
+	if pkg == nil {
+		return nil, l.notInspected(point)
+	}
+	if point.Selector == nil {
+		if target := calleeTarget(pkg, "", point.Name); target != nil {
+			return target, ""
+		}
+		if builtins[point.Name] {
+			return nil, ReasonExternal
+		}
+	}
+	return nil, ReasonNoRef
+}
+
+// calleeTarget returns a package function or a method of a package type, nil when not found
+func calleeTarget(pkg *graph.Package, typeName, name string) *graph.RefTarget {
+	for _, file := range pkg.FileSet {
+		if typeName == "" {
+			if function := file.LookupFunction(name); function != nil {
+				return &graph.RefTarget{Ref: function.Ref(pkg.Ref()), Package: pkg, File: file, Function: function}
+			}
+			continue
+		}
+		aType := file.LookupType(typeName)
+		if aType == nil {
+			continue
+		}
+		for _, method := range aType.Methods {
+			if method.Name == name {
+				owner := pkg.Ref()
+				owner.Type = typeName
+				return &graph.RefTarget{Ref: method.Ref(owner), Package: pkg, File: file, Type: aType, Function: method}
+			}
+		}
+	}
+	return nil
+}
+
+// lookupPackage returns the inspected package of a model path (a directory or URL) or import path
+func (l *graphLinker) lookupPackage(location string) *graph.Package {
+	dir := strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(location, "file://"), "localhost"), "/")
+	if pkg, ok := l.byDir[dir]; ok {
+		return pkg
+	}
+	for candidate, pkg := range l.byDir {
+		if strings.HasSuffix(candidate, "/"+dir) || strings.HasSuffix(dir, "/"+candidate) {
+			return pkg
+		}
+	}
+	for _, pkg := range l.project.Packages {
+		if pkg.ImportPath != "" && pkg.ImportPath == location {
+			return pkg
+		}
+	}
+	return nil
+}
+
+// isProjectPath reports whether a model path or import path is within the project root or module
+func (l *graphLinker) isProjectPath(location string) bool {
+	dir := strings.TrimPrefix(strings.TrimPrefix(location, "file://"), "localhost")
+	root := l.project.RootPath
+	if root != "" && (dir == root || strings.HasPrefix(dir, strings.TrimSuffix(root, "/")+"/")) {
+		return true
+	}
+	for _, pkg := range l.project.Packages {
+		if pkg.ImportPath != "" && strings.HasPrefix(location, path.Dir(pkg.ImportPath)+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// notInspected returns the reason of a project data point without a graph element
+func (l *graphLinker) notInspected(point *DataPoint) string {
+	if repository.IsGeneratedFile(path.Base(point.Definition.FilePath)) || repository.IsGeneratedFile(path.Base(point.File)) {
+		return ReasonGenerated
+	}
+	return ReasonNotInspected
+}
@@ -0,0 +1,136 @@
+package linage
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	// CallKindAttribute is the CALL edge attribute classifying the callee
+	CallKindAttribute = "callKind"
+	// CallRefAttribute is the CALL edge attribute holding the qualified callee, e.g. Service.Process or net/http.Get
+	CallRefAttribute = "callRef"
+	// FileAttribute is the edge attribute holding the source file of the access site
+	FileAttribute = "file"
+	// LineAttribute is the edge attribute holding the 1-based source line of the access site
+	LineAttribute = "line"
+	// ColumnAttribute and EndColumnAttribute hold the 1-based column span of the call site callee name
+	ColumnAttribute    = "column"
+	EndColumnAttribute = "endColumn"
+	// CallMethod marks calls of methods selected on a receiver value
+	CallMethod = "method"
+	// CallFunction marks calls of package functions, function values and builtins
+	CallFunction = "function"
+	// CallExternal marks calls of imported package functions outside the analyzed set
+	CallExternal = "external"
+	// CallVirtual marks calls of interface methods fanned out to every implementation of the package
+	CallVirtual = "virtual"
+	// CallMechanismAttribute is the CALL edge attribute naming how the caller reaches the callee, see Mechanism*
+	CallMechanismAttribute = "mechanism"
+	// MechanismDirect marks calls naming the callee, e.g. process(), svc.Process() or a call of a function value variable
+	MechanismDirect = "direct"
+	// MechanismMethodExpression marks method expressions resolved to their method, e.g. Service.Process or (*Service).Process
+	MechanismMethodExpression = "method-expression"
+	// MechanismFieldIndirect marks calls through function typed struct fields (s.callback()) linked to every function
+	// assigned to the field
+	MechanismFieldIndirect = "field-indirect"
+	// MechanismPassed marks functions passed as call arguments (e.g. http.HandleFunc("/", handle)), the callee may call them
+	MechanismPassed = "passed"
+	// PossibleAttribute marks CALL and XFER edges of virtual calls, which hold for one of the possible callees only
+	PossibleAttribute = "possible"
+)
+
+// CallKind returns the callee kind recorded on a CALL edge, empty if unknown
+func (e *DataFlowEdge) CallKind() string {
+	if e.Kind != Call || e.Attributes == nil {
+		return ""
+	}
+	kind, _ := e.Attributes[CallKindAttribute].(string)
+	return kind
+}
+
+// CallMechanism returns how a CALL edge reaches its callee, MechanismDirect when not recorded
+func (e *DataFlowEdge) CallMechanism() string {
+	if e.Kind != Call {
+		return ""
+	}
+	if mechanism, _ := e.Attributes[CallMechanismAttribute].(string); mechanism != "" {
+		return mechanism
+	}
+	return MechanismDirect
+}
+
+// Reachable returns sorted IDs of analyzed functions and methods reachable from a function ID through CALL edges, the
+// caller of an edge is the innermost function scope of the edge scope; functions passed as arguments (MechanismPassed) are
+// followed only with includePassed
+func (m *PackageModel) Reachable(from string, includePassed bool) []string {
+	functions := map[string]bool{}
+	for _, scope := range m.Scopes {
+		if scope.Kind == "function" {
+			functions[scope.ID] = true
+		}
+	}
+	callees := map[string][]string{}
+	for _, edge := range m.DataFlows {
+		if edge.Kind != Call || edge.Src == nil || (edge.Src.Kind != "func" && edge.Src.Kind != "method") || (!includePassed && edge.CallMechanism() == MechanismPassed) {
+			continue
+		}
+		if caller := callerScope(edge.Scope, functions); caller != "" {
+			callees[caller] = append(callees[caller], edge.Src.ID)
+		}
+	}
+	reached := map[string]bool{from: true}
+	queue := []string{from}
+	var result []string
+	for len(queue) > 0 {
+		caller := queue[0]
+		queue = queue[1:]
+		for _, callee := range callees[caller] {
+			if !reached[callee] {
+				reached[callee] = true
+				result = append(result, callee)
+				queue = append(queue, callee)
+			}
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// callerScope returns the innermost function scope ID enclosing a scope ID (e.g. app:main.go.run for
+// app:main.go.run.block@12#go), empty outside functions
+func callerScope(scopeID string, functions map[string]bool) string {
+	for scopeID != "" {
+		if functions[scopeID] {
+			return scopeID
+		}
+		index := strings.LastIndexAny(scopeID, ".#")
+		if index < 0 {
+			return ""
+		}
+		scopeID = scopeID[:index]
+	}
+	return ""
+}
+
+// Site returns the access site location recorded on the edge, nil if the edge has no line attribute
+func (e *DataFlowEdge) Site() *CodeLocation {
+	line := e.intAttribute(LineAttribute)
+	if line == 0 {
+		return nil
+	}
+	site := &CodeLocation{LineNumber: line, ColumnStart: e.intAttribute(ColumnAttribute), ColumnEnd: e.intAttribute(EndColumnAttribute)}
+	site.FilePath, _ = e.Attributes[FileAttribute].(string)
+	return site
+}
+
+// intAttribute returns numeric attribute value, decoded JSON numbers are float64
+func (e *DataFlowEdge) intAttribute(key string) int {
+	switch value := e.Attributes[key].(type) {
+	case int:
+		return value
+	case float64:
+		return int(value)
+	}
+	return 0
+}
@@ -0,0 +1,105 @@
+package linage
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// CompactOptions controls PackageModel.Compact
+type CompactOptions struct {
+	// CollapseReads merges READ self-loops of an identifier within a scope into one edge counting its uses, sites and
+	// attributes of the first use are kept
+	CollapseReads bool
+	// DropOrphans removes identifiers referenced by no edge and no scope symbol table, e.g. synthetic identifiers
+	DropOrphans bool
+}
+
+// CompactStats reports model sizes before and after compaction
+type CompactStats struct {
+	EdgesBefore  int `json:"edgesBefore"`
+	EdgesAfter   int `json:"edgesAfter"`
+	IdentsBefore int `json:"identsBefore"`
+	IdentsAfter  int `json:"identsAfter"`
+}
+
+// String returns before and after sizes, e.g. edges 1200 -> 300, identifiers 90 -> 80
+func (s *CompactStats) String() string {
+	return fmt.Sprintf("edges %d -> %d, identifiers %d -> %d", s.EdgesBefore, s.EdgesAfter, s.IdentsBefore, s.IdentsAfter)
+}
+
+// Occurrences returns the number of source edges the edge stands for, see Count
+func (e *DataFlowEdge) Occurrences() int {
+	if e.Count > 0 {
+		return e.Count
+	}
+	return 1
+}
+
+// Compact merges identical edges (same source, destination, kind, scope, literal, confidence and attributes) into
+// the first one counting occurrences in Count, optionally collapses READ self-loops and drops orphan identifiers;
+// edge order is preserved, so queries and exports of the compacted model differ only by counts
+func (m *PackageModel) Compact(opts *CompactOptions) *CompactStats {
+	if opts == nil {
+		opts = &CompactOptions{}
+	}
+	stats := &CompactStats{EdgesBefore: len(m.DataFlows), IdentsBefore: len(m.Idents)}
+	merged := make(map[string]*DataFlowEdge, len(m.DataFlows))
+	edges := m.DataFlows[:0]
+	for _, edge := range m.DataFlows {
+		key := edge.compactKey(opts.CollapseReads)
+		if kept, ok := merged[key]; ok {
+			kept.Count = kept.Occurrences() + edge.Occurrences()
+			continue
+		}
+		merged[key] = edge
+		edges = append(edges, edge)
+	}
+	for i := len(edges); i < len(m.DataFlows); i++ {
+		m.DataFlows[i] = nil // release merged edges held by the backing array
+	}
+	m.DataFlows = edges
+	if opts.DropOrphans {
+		m.dropOrphans()
+	}
+	stats.EdgesAfter, stats.IdentsAfter = len(m.DataFlows), len(m.Idents)
+	return stats
+}
+
+// compactKey returns the key of identical edges, READ self-loops are keyed by identifier and scope only when collapsed
+func (e *DataFlowEdge) compactKey(collapseReads bool) string {
+	src, dst := identID(e.Src), identID(e.Dst)
+	if collapseReads && e.Kind == Read && e.Src == e.Dst {
+		return "read\x00" + src + "\x00" + e.Scope
+	}
+	key := src + "\x00" + dst + "\x00" + string(e.Kind) + "\x00" + e.Scope + "\x00" + e.Literal + "\x00" + strconv.FormatFloat(e.Confidence, 'g', -1, 64)
+	if len(e.Attributes) > 0 {
+		key += "\x00" + fmt.Sprint(e.Attributes) // maps are printed in key order
+	}
+	return key
+}
+
+// dropOrphans removes identifiers referenced by no edge and no scope symbol table
+func (m *PackageModel) dropOrphans() {
+	referenced := map[string]bool{}
+	for _, edge := range m.DataFlows {
+		referenced[identID(edge.Src)] = true
+		referenced[identID(edge.Dst)] = true
+	}
+	for _, scope := range m.Scopes {
+		for _, id := range scope.Symbols {
+			referenced[identID(id)] = true
+		}
+	}
+	for key, id := range m.Idents {
+		if !referenced[identID(id)] && !referenced[key] {
+			delete(m.Idents, key)
+		}
+	}
+}
+
+func identID(id *Identifier) string {
+	if id == nil {
+		return ""
+	}
+	return id.ID
+}
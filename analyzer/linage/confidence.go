@@ -0,0 +1,114 @@
+package linage
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// ProvenanceAttribute is the edge attribute naming the heuristic an edge was emitted by, exact edges have none
+	ProvenanceAttribute = "provenance"
+	// ProvenanceVirtual marks CALL and XFER edges of interface calls fanned out to every possible implementation
+	ProvenanceVirtual = "virtual"
+	// ProvenanceOverload marks CALL edges of Java invocations matching several overloads by name, arity or owner
+	ProvenanceOverload = "overload"
+)
+
+// DefaultConfidence holds the confidence of edges emitted by each heuristic, see ProvenanceAttribute
+var DefaultConfidence = map[string]float64{
+	ProvenanceVirtual:  0.5,
+	ProvenanceOverload: 0.6,
+}
+
+// Confidence buckets, see ConfidenceBucket
+const (
+	ConfidenceHigh   = "high"   // 0.9 and above, e.g. direct syntactic flows
+	ConfidenceMedium = "medium" // 0.5 and above
+	ConfidenceLow    = "low"
+)
+
+// Score returns the edge confidence, edges not scored yet are exact
+func (e *DataFlowEdge) Score() float64 {
+	if e.Confidence == 0 {
+		return 1
+	}
+	return e.Confidence
+}
+
+// Provenance returns the heuristic an edge was emitted by, empty for exact edges
+func (e *DataFlowEdge) Provenance() string {
+	provenance, _ := e.Attributes[ProvenanceAttribute].(string)
+	return provenance
+}
+
+// ConfidenceBucket returns the bucket of a confidence score
+func ConfidenceBucket(score float64) string {
+	switch {
+	case score >= 0.9:
+		return ConfidenceHigh
+	case score >= 0.5:
+		return ConfidenceMedium
+	}
+	return ConfidenceLow
+}
+
+// FilterConfidence returns a shallow copy of the model keeping edges scoring at least min, identifiers and scopes are
+// shared with the model
+func (m *PackageModel) FilterConfidence(min float64) *PackageModel {
+	filtered := *m
+	filtered.DataFlows = nil
+	for _, edge := range m.DataFlows {
+		if edge.Score() >= min {
+			filtered.DataFlows = append(filtered.DataFlows, edge)
+		}
+	}
+	return &filtered
+}
+
+// dotStyles holds DOT edge styles by confidence bucket
+var dotStyles = map[string]string{ConfidenceHigh: "solid", ConfidenceMedium: "dashed", ConfidenceLow: "dotted"}
+
+// WriteDOT writes data flow edges as a Graphviz digraph labeled by edge kind, edges are styled by confidence bucket
+// (solid, dashed, dotted); output is sorted for the same model
+func (m *PackageModel) WriteDOT(w io.Writer) error {
+	lines := make([]string, 0, len(m.DataFlows))
+	for _, edge := range m.DataFlows {
+		if edge.Src == nil || edge.Dst == nil {
+			continue
+		}
+		score := edge.Score()
+		lines = append(lines, fmt.Sprintf("  %q -> %q [label=%q, style=%s, confidence=%s];",
+			edge.Src.ID, edge.Dst.ID, string(edge.Kind), dotStyles[ConfidenceBucket(score)], formatScore(score)))
+	}
+	sort.Strings(lines)
+	_, err := fmt.Fprintf(w, "digraph lineage {\n%s\n}\n", strings.Join(lines, "\n"))
+	return err
+}
+
+// WriteCSV writes data flow edges as CSV rows of src, dst, kind, scope, confidence and provenance in model order
+func (m *PackageModel) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"src", "dst", "kind", "scope", "confidence", "provenance"}); err != nil {
+		return err
+	}
+	for _, edge := range m.DataFlows {
+		if edge.Src == nil || edge.Dst == nil {
+			continue
+		}
+		if err := writer.Write([]string{edge.Src.ID, edge.Dst.ID, string(edge.Kind), edge.Scope, formatScore(edge.Score()), edge.Provenance()}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// formatScore renders a confidence score with up to 3 decimals
+func formatScore(score float64) string {
+	return strconv.FormatFloat(math.Round(score*1000)/1000, 'f', -1, 64)
+}
@@ -1,5 +1,10 @@
 package linage
 
+import (
+	"sort"
+	"strings"
+)
+
 // DataPoint represents an identifier and its data lineage information
 type DataPoint struct {
 	Identifier `yaml:"identity"`      // Identity information
@@ -8,6 +13,8 @@ type DataPoint struct {
 	Writes     []*DataFlowEdge        `yaml:"writes,omitempty"`   // Where the identifier is written
 	Reads      []*DataFlowEdge        `yaml:"reads,omitempty"`    // Where the identifier is read
 	Calls      []*DataFlowEdge        `yaml:"calls,omitempty"`    // Where the identifier is called
+	// GraphRef is the canonical reference of the graph element the point resolves to, see AttachGraph
+	GraphRef string `yaml:"graphRef,omitempty" json:"graphRef,omitempty"`
 }
 
 // CodeLocation represents a location in the code
@@ -22,3 +29,69 @@ type CodeLocation struct {
 type TouchContext struct {
 	Scope string `yaml:"scope"`
 }
+
+// ApplyOwners records owners resolved for the definition file path in Metadata["owners"]
+func (d *DataPoint) ApplyOwners(rules interface{ Owners(path string) []string }) {
+	owners := rules.Owners(d.Definition.FilePath)
+	if len(owners) == 0 {
+		return
+	}
+	if d.Metadata == nil {
+		d.Metadata = map[string]interface{}{}
+	}
+	d.Metadata["owners"] = owners
+}
+
+// NewDataPoints builds data points of model identifiers and scope symbols having syntax nodes, definitions are
+// located at the declared name and reads, writes and calls are collected from data flow edges
+func NewDataPoints(model *PackageModel) []*DataPoint {
+	identifiers := map[*Identifier]bool{}
+	for _, id := range model.Idents {
+		identifiers[id] = true
+	}
+	for _, scope := range model.Scopes {
+		for _, id := range scope.Symbols {
+			identifiers[id] = true
+		}
+	}
+	byIdentifier := map[*Identifier]*DataPoint{}
+	var result []*DataPoint
+	for id := range identifiers {
+		if id.Node == nil || id.Kind == "file" {
+			continue
+		}
+		node := id.Node
+		if name := node.ChildByFieldName("name"); name != nil {
+			node = name // declarations, e.g. func main()
+		}
+		point := &DataPoint{
+			Identifier: *id,
+			Definition: CodeLocation{
+				FilePath:    strings.TrimPrefix(id.File, model.Path+":"),
+				LineNumber:  int(node.StartPoint().Row) + 1,
+				ColumnStart: int(node.StartPoint().Column) + 1,
+				ColumnEnd:   int(node.EndPoint().Column) + 1,
+			},
+		}
+		byIdentifier[id] = point
+		result = append(result, point)
+	}
+	for _, edge := range model.DataFlows {
+		switch edge.Kind {
+		case Read:
+			if point := byIdentifier[edge.Src]; point != nil {
+				point.Reads = append(point.Reads, edge)
+			}
+		case Write:
+			if point := byIdentifier[edge.Dst]; point != nil {
+				point.Writes = append(point.Writes, edge)
+			}
+		case Call:
+			if point := byIdentifier[edge.Src]; point != nil {
+				point.Calls = append(point.Calls, edge)
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
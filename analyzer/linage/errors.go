@@ -0,0 +1,46 @@
+package linage
+
+import "sort"
+
+const (
+	// FlowAttribute is the edge attribute classifying a data flow
+	FlowAttribute = "flow"
+	// ErrorFlow marks XFER edges propagating an error value
+	ErrorFlow = "error"
+)
+
+// IsErrorFlow reports whether the edge propagates an error value
+func (e *DataFlowEdge) IsErrorFlow() bool {
+	return e.Kind == Xfer && e.Attributes != nil && e.Attributes[FlowAttribute] == ErrorFlow
+}
+
+// ErrorOrigins returns call sites an error held by the identifier can originate from,
+// following error flow edges backwards through wrapping calls and call returns
+func (m *PackageModel) ErrorOrigins(identID string) []*Identifier {
+	incoming := map[string][]*Identifier{}
+	for _, edge := range m.DataFlows {
+		if edge.IsErrorFlow() {
+			incoming[edge.Dst.ID] = append(incoming[edge.Dst.ID], edge.Src)
+		}
+	}
+	var origins []*Identifier
+	visited := map[string]bool{identID: true}
+	queue := []string{identID}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, src := range incoming[cur] {
+			if visited[src.ID] {
+				continue
+			}
+			visited[src.ID] = true
+			if len(incoming[src.ID]) == 0 && src.Kind == "call" {
+				origins = append(origins, src)
+				continue
+			}
+			queue = append(queue, src.ID)
+		}
+	}
+	sort.Slice(origins, func(i, j int) bool { return origins[i].ID < origins[j].ID })
+	return origins
+}
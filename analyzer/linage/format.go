@@ -0,0 +1,271 @@
+package linage
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// packageLevel labels data points not belonging to a type
+const packageLevel = "(package)"
+
+// FormatOptions controls data point rendering
+type FormatOptions struct {
+	Kinds      []string // Data point kinds to include (e.g. var, func, method), all kinds when empty
+	MinTouches int      // Minimum number of reads, writes and calls
+	FileGlob   string   // Definition file pattern (path.Match), patterns without a slash match the file base name
+	Expand     []string // Data point IDs or names whose touch points are listed inline with their scopes
+	Markdown   bool     // Render Markdown tables instead of aligned text
+}
+
+// FormatDataPoints renders data points grouped by package and enclosing type, one line per data point with kind, name,
+// definition file:line and touch counts; output is deterministic for the same data points
+func FormatDataPoints(points []*DataPoint, opts *FormatOptions) string {
+	if opts == nil {
+		opts = &FormatOptions{}
+	}
+	groups := groupDataPoints(points, opts)
+	builder := &strings.Builder{}
+	if opts.Markdown {
+		formatMarkdown(builder, groups, opts)
+		return builder.String()
+	}
+	for i, pkg := range groups {
+		if i > 0 {
+			builder.WriteString("\n")
+		}
+		fmt.Fprintf(builder, "package %s\n", pkg.name)
+		for _, group := range pkg.types {
+			if group.name == packageLevel {
+				fmt.Fprintf(builder, "  %s\n", packageLevel)
+			} else {
+				fmt.Fprintf(builder, "  type %s\n", group.name)
+			}
+			kindWidth, nameWidth, definitionWidth := 0, 0, 0
+			for _, point := range group.points {
+				kindWidth = max(kindWidth, len(pointKind(point)))
+				nameWidth = max(nameWidth, len(point.Name))
+				definitionWidth = max(definitionWidth, len(definition(point)))
+			}
+			for _, point := range group.points {
+				fmt.Fprintf(builder, "    %-*s  %-*s  %-*s  reads=%d writes=%d calls=%d\n", kindWidth, pointKind(point), nameWidth, point.Name,
+					definitionWidth, definition(point), len(point.Reads), len(point.Writes), len(point.Calls))
+				if !opts.expands(point) {
+					continue
+				}
+				for _, touch := range touchPoints(point) {
+					fmt.Fprintf(builder, "      %s %s\n", touch.kind, touch.String())
+				}
+			}
+		}
+	}
+	return builder.String()
+}
+
+// FormatDataPointsJSON renders JSON encoded data points as Markdown tables, e.g. for PR comments
+func FormatDataPointsJSON(data []byte, opts *FormatOptions) (string, error) {
+	var points []*DataPoint
+	if err := json.Unmarshal(data, &points); err != nil {
+		return "", fmt.Errorf("failed to decode data points: %w", err)
+	}
+	options := FormatOptions{}
+	if opts != nil {
+		options = *opts
+	}
+	options.Markdown = true
+	return FormatDataPoints(points, &options), nil
+}
+
+func formatMarkdown(builder *strings.Builder, groups []*packageGroup, opts *FormatOptions) {
+	builder.WriteString("# Data points\n")
+	if len(groups) == 0 {
+		builder.WriteString("\n_None._\n")
+		return
+	}
+	for _, pkg := range groups {
+		fmt.Fprintf(builder, "\n## Package `%s`\n", pkg.name)
+		for _, group := range pkg.types {
+			if group.name == packageLevel {
+				builder.WriteString("\n### Package level\n\n")
+			} else {
+				fmt.Fprintf(builder, "\n### Type `%s`\n\n", group.name)
+			}
+			builder.WriteString("| Kind | Name | Definition | Reads | Writes | Calls |\n| --- | --- | --- | --- | --- | --- |\n")
+			var expanded []*DataPoint
+			for _, point := range group.points {
+				fmt.Fprintf(builder, "| %s | %s | %s | %d | %d | %d |\n", pointKind(point), point.Name, definition(point), len(point.Reads), len(point.Writes), len(point.Calls))
+				if opts.expands(point) {
+					expanded = append(expanded, point)
+				}
+			}
+			for _, point := range expanded {
+				fmt.Fprintf(builder, "\n`%s` touch points:\n\n", point.Name)
+				for _, touch := range touchPoints(point) {
+					fmt.Fprintf(builder, "- %s %s\n", touch.kind, touch.String())
+				}
+			}
+		}
+	}
+}
+
+type packageGroup struct {
+	name  string
+	types []*typeGroup
+}
+
+type typeGroup struct {
+	name   string
+	points []*DataPoint
+}
+
+// groupDataPoints filters data points and groups them by package and enclosing type, package level data points first
+func groupDataPoints(points []*DataPoint, opts *FormatOptions) []*packageGroup {
+	byPackage := map[string]map[string][]*DataPoint{}
+	for _, point := range points {
+		if !opts.includes(point) {
+			continue
+		}
+		if byPackage[point.Package] == nil {
+			byPackage[point.Package] = map[string][]*DataPoint{}
+		}
+		typeName := enclosingType(point)
+		byPackage[point.Package][typeName] = append(byPackage[point.Package][typeName], point)
+	}
+	var result []*packageGroup
+	for _, pkgName := range sortedKeys(byPackage) {
+		pkg := &packageGroup{name: pkgName}
+		for _, typeName := range sortedKeys(byPackage[pkgName]) {
+			group := &typeGroup{name: typeName, points: byPackage[pkgName][typeName]}
+			sort.SliceStable(group.points, func(i, j int) bool { return lessDefinition(group.points[i], group.points[j]) })
+			pkg.types = append(pkg.types, group)
+		}
+		sort.SliceStable(pkg.types, func(i, j int) bool {
+			if (pkg.types[i].name == packageLevel) != (pkg.types[j].name == packageLevel) {
+				return pkg.types[i].name == packageLevel
+			}
+			return pkg.types[i].name < pkg.types[j].name
+		})
+		result = append(result, pkg)
+	}
+	return result
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func lessDefinition(a, b *DataPoint) bool {
+	if a.Definition.FilePath != b.Definition.FilePath {
+		return a.Definition.FilePath < b.Definition.FilePath
+	}
+	if a.Definition.LineNumber != b.Definition.LineNumber {
+		return a.Definition.LineNumber < b.Definition.LineNumber
+	}
+	if a.Definition.ColumnStart != b.Definition.ColumnStart {
+		return a.Definition.ColumnStart < b.Definition.ColumnStart
+	}
+	return a.ID < b.ID
+}
+
+// enclosingType returns the receiver type of methods and the name of type declarations, packageLevel otherwise;
+// method identifiers are qualified by the receiver type (file scope ID.Receiver.Name)
+func enclosingType(point *DataPoint) string {
+	switch point.Kind {
+	case "type":
+		return point.Name
+	case "method":
+		qualifier := strings.TrimSuffix(point.ID, "."+point.Name)
+		return qualifier[strings.LastIndex(qualifier, ".")+1:]
+	}
+	return packageLevel
+}
+
+func (o *FormatOptions) includes(point *DataPoint) bool {
+	if len(o.Kinds) > 0 {
+		matched := false
+		for _, kind := range o.Kinds {
+			matched = matched || kind == point.Kind
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(point.Reads)+len(point.Writes)+len(point.Calls) < o.MinTouches {
+		return false
+	}
+	if o.FileGlob != "" {
+		name := point.Definition.FilePath
+		if !strings.Contains(o.FileGlob, "/") {
+			name = path.Base(name)
+		}
+		if matched, _ := path.Match(o.FileGlob, name); !matched {
+			return false
+		}
+	}
+	return true
+}
+
+func (o *FormatOptions) expands(point *DataPoint) bool {
+	for _, ref := range o.Expand {
+		if ref == point.ID || ref == point.Name {
+			return true
+		}
+	}
+	return false
+}
+
+func pointKind(point *DataPoint) string {
+	if point.Kind == "" {
+		return "-"
+	}
+	return point.Kind
+}
+
+func definition(point *DataPoint) string {
+	return fmt.Sprintf("%s:%d", point.Definition.FilePath, point.Definition.LineNumber)
+}
+
+// touchPoint is a read, write or call of a data point
+type touchPoint struct {
+	kind       string
+	scope      string
+	site       *CodeLocation
+	confidence float64
+}
+
+// String renders the touch point scope and site, heuristic touch points end with their confidence
+func (t *touchPoint) String() string {
+	ret := "in " + t.scope
+	if t.site != nil {
+		ret = fmt.Sprintf("in %s at %s:%d", t.scope, t.site.FilePath, t.site.LineNumber)
+	}
+	if t.confidence < 1 {
+		ret += " (confidence " + formatScore(t.confidence) + ")"
+	}
+	return ret
+}
+
+// touchPoints returns reads, writes and calls of a data point with scopes relative to the package
+func touchPoints(point *DataPoint) []*touchPoint {
+	var result []*touchPoint
+	for _, touches := range []struct {
+		kind  string
+		edges []*DataFlowEdge
+	}{{"read", point.Reads}, {"write", point.Writes}, {"call", point.Calls}} {
+		for _, edge := range touches.edges {
+			scope := strings.TrimPrefix(edge.Scope, point.Package+":")
+			if scope == "" {
+				scope = packageLevel
+			}
+			result = append(result, &touchPoint{kind: touches.kind, scope: scope, site: edge.Site(), confidence: edge.Score()})
+		}
+	}
+	return result
+}
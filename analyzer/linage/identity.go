@@ -19,7 +19,17 @@ type Identifier struct {
 	Type       string       `json:"type,omitempty"`
 	Selector   *Selector    `json:"selector,omitempty"`
 	Annotation Annotations  `json:"annotations,omitempty"`
+	Labels     []string     `json:"labels,omitempty"`     // sensitivity labels, e.g. pii
+	FuncRef    string       `json:"funcRef,omitempty"`    // ID of function or method bound to a function value variable
+	Ref        string       `json:"ref,omitempty"`        // canonical reference, see graph.Ref; ID remains its alias
+	KnownValue *KnownValue  `json:"knownValue,omitempty"` // value of constants and variables written a folded constant expression
 	Node       *sitter.Node `json:"-"`
 }
 
+// KnownValue holds a value known without running the code
+type KnownValue struct {
+	Value   string `json:"value"`             // Go literal of the value, e.g. 60 or "http://host/api"
+	Derived bool   `json:"derived,omitempty"` // folded from a constant expression rather than written as a literal
+}
+
 func (i *Identifier) String() string { return i.ID }
@@ -0,0 +1,47 @@
+package linage
+
+const (
+	// LiteralLineAttribute and LiteralColumnAttribute hold the 1-based position of a literal written by an edge
+	LiteralLineAttribute   = "literalLine"
+	LiteralColumnAttribute = "literalColumn"
+	// LiteralDerivedAttribute marks edge literals folded from constant expressions, e.g. 60 for timeout := base * 2
+	LiteralDerivedAttribute = "literalDerived"
+)
+
+// LiteralValue describes a literal value observed flowing into an identifier
+type LiteralValue struct {
+	Value     string          `json:"value"`
+	Locations []*CodeLocation `json:"locations,omitempty"` // assignment sites, empty when positions were not recorded
+}
+
+// LiteralsFor returns distinct literal values written to the identifier in order of first appearance
+func (m *PackageModel) LiteralsFor(identID string) []*LiteralValue {
+	var result []*LiteralValue
+	byValue := map[string]*LiteralValue{}
+	for _, edge := range m.DataFlows {
+		if edge.Literal == "" || edge.Dst == nil || edge.Dst.ID != identID {
+			continue
+		}
+		literal, ok := byValue[edge.Literal]
+		if !ok {
+			literal = &LiteralValue{Value: edge.Literal}
+			byValue[edge.Literal] = literal
+			result = append(result, literal)
+		}
+		if site := edge.LiteralSite(); site != nil {
+			literal.Locations = append(literal.Locations, site)
+		}
+	}
+	return result
+}
+
+// LiteralSite returns the position of the literal written by the edge, nil when not recorded
+func (e *DataFlowEdge) LiteralSite() *CodeLocation {
+	line := e.intAttribute(LiteralLineAttribute)
+	if line == 0 {
+		return nil
+	}
+	site := &CodeLocation{LineNumber: line, ColumnStart: e.intAttribute(LiteralColumnAttribute)}
+	site.FilePath, _ = e.Attributes[FileAttribute].(string)
+	return site
+}
@@ -0,0 +1,55 @@
+package linage
+
+import (
+	"slices"
+	"sort"
+)
+
+const (
+	// LogSinkKind is the kind of synthetic identifiers representing logging call sites
+	LogSinkKind = "log"
+	// LogKeyAttribute holds the literal key a value is logged under (e.g. "email" for .Str("email", u.Email))
+	LogKeyAttribute = "logKey"
+)
+
+// LoggedIdentifier describes a field or variable reaching logging calls
+type LoggedIdentifier struct {
+	Identifier *Identifier   `json:"identifier"`
+	Keys       []string      `json:"keys,omitempty"` // distinct literal log keys in order of first appearance
+	Sinks      []*Identifier `json:"sinks"`          // logging call sites
+}
+
+// LoggedIdentifiers aggregates identifiers transferred into log sinks with the keys they are logged under,
+// ordered by identifier ID
+func (m *PackageModel) LoggedIdentifiers() []*LoggedIdentifier {
+	var result []*LoggedIdentifier
+	byID := map[string]*LoggedIdentifier{}
+	for _, edge := range m.DataFlows {
+		if edge.Kind != Xfer || edge.Src == nil || edge.Dst == nil || edge.Dst.Kind != LogSinkKind {
+			continue
+		}
+		logged, ok := byID[edge.Src.ID]
+		if !ok {
+			logged = &LoggedIdentifier{Identifier: edge.Src}
+			byID[edge.Src.ID] = logged
+			result = append(result, logged)
+		}
+		if key, _ := edge.Attributes[LogKeyAttribute].(string); key != "" && !slices.Contains(logged.Keys, key) {
+			logged.Keys = append(logged.Keys, key)
+		}
+		if !containsIdent(logged.Sinks, edge.Dst) {
+			logged.Sinks = append(logged.Sinks, edge.Dst)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Identifier.ID < result[j].Identifier.ID })
+	return result
+}
+
+func containsIdent(ids []*Identifier, id *Identifier) bool {
+	for _, candidate := range ids {
+		if candidate.ID == id.ID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package linage
+
+import "github.com/viant/linager/inspector/graph"
+
+// Redact replaces secrets in edge literals, string edge attributes, identifier annotation and known values, see
+// graph.Redactor; data points and graphs exported from the model afterwards carry placeholders only
+func (m *PackageModel) Redact(redactor *graph.Redactor) {
+	for _, edge := range m.DataFlows {
+		path := edge.filePath()
+		edge.Literal = redactor.RedactValue(path, edge.Literal)
+		for key, value := range edge.Attributes {
+			if text, ok := value.(string); ok && key != FileAttribute {
+				edge.Attributes[key] = redactor.RedactValue(path, text)
+			}
+		}
+	}
+	visited := map[*Identifier]bool{}
+	for _, id := range m.Idents {
+		id.redact(redactor, visited)
+	}
+	for _, scope := range m.Scopes {
+		for _, id := range scope.Symbols {
+			id.redact(redactor, visited)
+		}
+	}
+}
+
+// filePath returns the file of the edge site, the destination or source identifier file when not recorded
+func (e *DataFlowEdge) filePath() string {
+	if path, _ := e.Attributes[FileAttribute].(string); path != "" {
+		return path
+	}
+	if e.Dst != nil && e.Dst.File != "" {
+		return e.Dst.File
+	}
+	if e.Src != nil {
+		return e.Src.File
+	}
+	return ""
+}
+
+// redact replaces secrets in annotation values and the known value of an identifier once
+func (i *Identifier) redact(redactor *graph.Redactor, visited map[*Identifier]bool) {
+	if i == nil || visited[i] {
+		return
+	}
+	visited[i] = true
+	for key, value := range i.Annotation {
+		i.Annotation[key] = redactor.RedactValue(i.File, value)
+	}
+	if i.KnownValue != nil {
+		i.KnownValue.Value = redactor.RedactValue(i.File, i.KnownValue.Value)
+	}
+}
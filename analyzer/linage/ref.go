@@ -0,0 +1,35 @@
+package linage
+
+import "github.com/viant/linager/inspector/graph"
+
+// ByRef returns the identifier with a canonical reference (see graph.Ref) or, as its alias, with the identifier ID;
+// nil if there is none
+func (m *PackageModel) ByRef(ref string) *Identifier {
+	if parsed, err := graph.ParseRef(ref); err == nil {
+		ref = parsed.String()
+	}
+	var alias *Identifier
+	match := func(id *Identifier) *Identifier {
+		switch {
+		case id == nil:
+		case id.Ref != "" && id.Ref == ref:
+			return id
+		case alias == nil && id.ID == ref:
+			alias = id
+		}
+		return nil
+	}
+	for _, id := range m.Idents {
+		if found := match(id); found != nil {
+			return found
+		}
+	}
+	for _, scope := range m.Scopes {
+		for _, id := range scope.Symbols {
+			if found := match(id); found != nil {
+				return found
+			}
+		}
+	}
+	return alias
+}
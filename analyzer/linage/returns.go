@@ -0,0 +1,44 @@
+package linage
+
+const (
+	// ReturnSiteAttribute is the edge attribute holding the index of the return statement an edge flows through,
+	// return statements of a function are indexed in source order
+	ReturnSiteAttribute = "returnSite"
+	// BranchesAttribute is the edge attribute holding IDs of branches enclosing a return statement within its function,
+	// innermost first, e.g. pkg:file.go.Fn.if@120 or pkg:file.go.Fn.case@240
+	BranchesAttribute = "branches"
+)
+
+// Branch kinds of BranchesAttribute IDs
+const (
+	BranchIf    = "if"
+	BranchElse  = "else"
+	BranchCase  = "case"
+	BranchLoop  = "loop"
+	BranchCatch = "catch"
+)
+
+// ReturnSite returns the index of the return statement the edge flows through, -1 for other edges
+func (e *DataFlowEdge) ReturnSite() int {
+	if _, ok := e.Attributes[ReturnSiteAttribute]; !ok {
+		return -1
+	}
+	return e.intAttribute(ReturnSiteAttribute)
+}
+
+// Branches returns IDs of branches enclosing the return statement the edge flows through, innermost first
+func (e *DataFlowEdge) Branches() []string {
+	switch value := e.Attributes[BranchesAttribute].(type) {
+	case []string:
+		return value
+	case []interface{}: // decoded JSON
+		result := make([]string, 0, len(value))
+		for _, item := range value {
+			if text, ok := item.(string); ok {
+				result = append(result, text)
+			}
+		}
+		return result
+	}
+	return nil
+}
@@ -1,14 +1,45 @@
 package linage
 
+import (
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/viant/linager/inspector/graph"
+	"path"
+	"strings"
+)
+
 // Scope represents a scope in the code
 type Scope struct {
-	ID      string                 `json:"id"`
-	Kind    string                 `json:"kind"`
-	Name    string                 `json:"name,omitempty"`
-	Start   int                    `json:"start"`
-	End     int                    `json:"end"`
-	Parent  *Scope                 `json:"-"`
-	Symbols map[string]*Identifier `json:"symbols,omitempty"`
+	ID   string `json:"id"`
+	Kind string `json:"kind"`
+	Name string `json:"name,omitempty"`
+	// Deprecated: Start holds the start byte offset, use StartByte or StartLine
+	Start int `json:"start"`
+	// Deprecated: End holds the end byte offset, use EndByte or EndLine
+	End       int                    `json:"end"`
+	StartByte int                    `json:"startByte"`
+	EndByte   int                    `json:"endByte"`
+	StartLine int                    `json:"startLine,omitempty"` // 1-based line of the first scope character
+	EndLine   int                    `json:"endLine,omitempty"`   // 1-based line of the last scope character
+	Parent    *Scope                 `json:"-"`
+	Symbols   map[string]*Identifier `json:"symbols,omitempty"`
+}
+
+// SetRange sets scope byte and line ranges from a tree-sitter node
+func (s *Scope) SetRange(n *sitter.Node) *Scope {
+	s.StartByte, s.EndByte = int(n.StartByte()), int(n.EndByte())
+	s.Start, s.End = s.StartByte, s.EndByte
+	s.StartLine = int(n.StartPoint().Row) + 1
+	end := n.EndPoint()
+	s.EndLine = int(end.Row) + 1
+	if end.Column == 0 && end.Row > n.StartPoint().Row {
+		s.EndLine-- // node ends with a new line, e.g. a source file
+	}
+	return s
+}
+
+// ContainsLine reports whether a 1-based line falls within the scope
+func (s *Scope) ContainsLine(line int) bool {
+	return s.StartLine > 0 && line >= s.StartLine && line <= s.EndLine
 }
 
 // Find searches for an identifier in the current scope and its parent scopes
@@ -32,6 +63,13 @@ type DataFlowEdge struct {
 	Dst   *Identifier `json:"dst,omitempty"`
 	Kind  AccessKind  `json:"kind,omitempty"`
 	Scope string      `json:"scope,omitempty"`
+	// Literal holds the value of a basic literal (string, number, bool) written by this edge, e.g. 3 for retryCount := 3
+	Literal string `json:"literal,omitempty"`
+	// Confidence scores the edge from 0 to 1: 1 for direct syntactic flows, the configured confidence of the heuristic
+	// named by ProvenanceAttribute, or the product of composed edges for transitive summary edges; 0 when not scored
+	Confidence float64 `json:"confidence,omitempty"`
+	// Count holds the number of occurrences merged into this edge by PackageModel.Compact, 0 for edges not compacted
+	Count int `json:"count,omitempty"`
 	// Attributes holds optional metadata for this edge (e.g., annotation key/value, source location)
 	Attributes map[string]interface{} `json:"attributes,omitempty"`
 }
@@ -44,6 +82,79 @@ type PackageModel struct {
 	Scopes    []*Scope               `json:"scopes,omitempty"`
 	Idents    map[string]*Identifier `json:"idents,omitempty"`
 	DataFlows []*DataFlowEdge        `json:"dataflows,omitempty"`
+	// Skipped lists source files omitted as oversized or binary
+	Skipped []*graph.SkippedFile `json:"skipped,omitempty"`
+	// Warnings lists source files analyzed partially, e.g. after a parse timeout
+	Warnings []*graph.ParseWarning `json:"warnings,omitempty"`
+	// Truncated indicates transitive summary edges were omitted by closure depth or edge count limits
+	Truncated bool `json:"truncated,omitempty"`
+	// initialized indicates analyzer plugins were initialized with the model, see MarkInitialized
+	initialized bool
+}
+
+// MarkInitialized marks the model as initialized by analyzer plugins, it returns false if it already was
+func (m *PackageModel) MarkInitialized() bool {
+	if m.initialized {
+		return false
+	}
+	m.initialized = true
+	return true
+}
+
+// ScopeForLine returns the innermost scope of a file (file name or file scope ID) containing a 1-based line
+func (m *PackageModel) ScopeForLine(file string, line int) *Scope {
+	var fileScopeID string
+	for _, scope := range m.Scopes {
+		if scope.Kind == "file" && (scope.ID == file || strings.HasSuffix(scope.ID, ":"+path.Base(file))) {
+			fileScopeID = scope.ID
+			break
+		}
+	}
+	if fileScopeID == "" {
+		return nil
+	}
+	var result *Scope
+	for _, scope := range m.Scopes {
+		if scope.ID != fileScopeID && !strings.HasPrefix(scope.ID, fileScopeID+".") || !scope.ContainsLine(line) {
+			continue
+		}
+		if result == nil || scope.EndByte-scope.StartByte < result.EndByte-result.StartByte {
+			result = scope
+		}
+	}
+	return result
+}
+
+// SymbolAt returns the identifier whose name spans a byte offset of a file, the file is matched by its path suffix
+// (e.g. app/main.go) against identifier package and file; nil if no identifier covers the offset
+func (m *PackageModel) SymbolAt(file string, offset int) *Identifier {
+	file = strings.TrimPrefix(path.Clean("/"+file), "/")
+	var result *Identifier
+	match := func(id *Identifier) {
+		if id == nil || id.Kind == "file" || id.Name == "" {
+			return
+		}
+		start := int(id.StartByte)
+		if offset < start || offset >= start+len(id.Name) {
+			return
+		}
+		location := strings.TrimSuffix(id.Package, "/") + "/" + path.Base(id.File)
+		if location != file && !strings.HasSuffix(location, "/"+file) {
+			return
+		}
+		if result == nil || id.ID < result.ID {
+			result = id
+		}
+	}
+	for _, id := range m.Idents {
+		match(id)
+	}
+	for _, scope := range m.Scopes {
+		for _, id := range scope.Symbols {
+			match(id)
+		}
+	}
+	return result
 }
 
 func NewPackageModel() *PackageModel {
@@ -0,0 +1,72 @@
+package linage
+
+import "sort"
+
+const (
+	// SerializationKind is the kind of synthetic identifiers representing serialization call sites, e.g. json.Marshal
+	SerializationKind = "serialization"
+	// SerializationFormatAnnotation holds the format of a serialization boundary, e.g. json
+	SerializationFormatAnnotation = "serializationFormat"
+	// SerializationDirectionAnnotation holds SerializationMarshal or SerializationUnmarshal
+	SerializationDirectionAnnotation = "serializationDirection"
+)
+
+// Serialization directions
+const (
+	SerializationMarshal   = "marshal"   // a value leaves the process, e.g. json.Marshal or Encoder.Encode
+	SerializationUnmarshal = "unmarshal" // a value enters the process, e.g. json.Unmarshal or Decoder.Decode
+)
+
+// SerializationPoint describes a call site where values cross a process boundary
+type SerializationPoint struct {
+	Boundary   *Identifier   `json:"boundary"`
+	Format     string        `json:"format"`               // json, xml, yaml or proto
+	Direction  string        `json:"direction"`            // SerializationMarshal or SerializationUnmarshal
+	StructType string        `json:"structType,omitempty"` // type of the serialized value as written, e.g. model.Order
+	Location   CodeLocation  `json:"location"`
+	Values     []*Identifier `json:"values,omitempty"` // marshaled sources or unmarshal targets
+}
+
+// SerializationPoints lists serialization boundaries with values flowing into (marshal) or out of (unmarshal) them,
+// ordered by file and position
+func (m *PackageModel) SerializationPoints() []*SerializationPoint {
+	var result []*SerializationPoint
+	byID := map[string]*SerializationPoint{}
+	for _, id := range m.Idents {
+		if id.Kind != SerializationKind {
+			continue
+		}
+		point := &SerializationPoint{
+			Boundary:   id,
+			Format:     id.Annotation[SerializationFormatAnnotation],
+			Direction:  id.Annotation[SerializationDirectionAnnotation],
+			StructType: id.Type,
+			Location:   CodeLocation{FilePath: id.File},
+		}
+		if id.Node != nil {
+			point.Location.LineNumber = int(id.Node.StartPoint().Row) + 1
+			point.Location.ColumnStart = int(id.Node.StartPoint().Column) + 1
+		}
+		byID[id.ID] = point
+		result = append(result, point)
+	}
+	for _, edge := range m.DataFlows {
+		if edge.Kind != Xfer || edge.Src == nil || edge.Dst == nil {
+			continue
+		}
+		if point, ok := byID[edge.Dst.ID]; ok && point.Direction == SerializationMarshal && !containsIdent(point.Values, edge.Src) {
+			point.Values = append(point.Values, edge.Src)
+		}
+		if point, ok := byID[edge.Src.ID]; ok && point.Direction == SerializationUnmarshal && !containsIdent(point.Values, edge.Dst) {
+			point.Values = append(point.Values, edge.Dst)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i].Boundary, result[j].Boundary
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.StartByte < b.StartByte
+	})
+	return result
+}
@@ -0,0 +1,31 @@
+package linage
+
+import "strings"
+
+const (
+	// SQLQueryKind is the kind of synthetic identifiers representing SQL query literals passed to calls
+	SQLQueryKind = "sql"
+	// SQLColumnKind is the kind of synthetic identifiers representing table columns shared by models and queries
+	SQLColumnKind = "column"
+	// SQLColumnAttribute holds the query column matched by a field tag value (e.g. user_name for db:"user_name")
+	SQLColumnAttribute = "sqlColumn"
+	// SQLTableAttribute holds the table of a column linked to a model field or query, e.g. users
+	SQLTableAttribute = "sqlTable"
+	// TagKeyAttribute holds the struct tag key of a value derived from a constant, e.g. db
+	TagKeyAttribute = "tagKey"
+	// SQLTablesAnnotation holds comma separated tables of a SQL query identifier, e.g. orders,customers
+	SQLTablesAnnotation = "sqlTables"
+)
+
+// SQLTables returns tables read or written by a SQL query identifier in order of appearance
+func SQLTables(id *Identifier) []string {
+	if tables := id.Annotation[SQLTablesAnnotation]; tables != "" {
+		return strings.Split(tables, ",")
+	}
+	return nil
+}
+
+// SQLColumnID returns the identifier ID of a table column, see SQLColumnKind
+func SQLColumnID(table, column string) string {
+	return "sql:" + table + "." + column
+}
@@ -20,6 +20,8 @@ func Merge(models ...*PackageModel) *PackageModel {
 		}
 		// append dataflow edges
 		merged.DataFlows = append(merged.DataFlows, m.DataFlows...)
+		merged.Skipped = append(merged.Skipped, m.Skipped...)
+		merged.Truncated = merged.Truncated || m.Truncated
 	}
 	return merged
 }
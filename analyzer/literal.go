@@ -0,0 +1,92 @@
+package analyzer
+
+import (
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/viant/linager/analyzer/linage"
+	"strings"
+)
+
+// DefaultMaxLiteralLength is the default maximum number of characters of literal values recorded on edges
+const DefaultMaxLiteralLength = 64
+
+// basicLiterals lists tree-sitter node types of literal values recorded on edges, composite literals are excluded
+var basicLiterals = map[string]bool{
+	"interpreted_string_literal": true,
+	"raw_string_literal":         true,
+	"rune_literal":               true,
+	"int_literal":                true,
+	"float_literal":              true,
+	"imaginary_literal":          true,
+	"true":                       true,
+	"false":                      true,
+}
+
+// recordLiteral sets the value and position of a basic literal or a folded constant expression written by an edge,
+// other expressions are ignored; folded values are marked derived and become the known value of the written identifier
+func (a *Analyzer) recordLiteral(edge *linage.DataFlowEdge, expr *sitter.Node, src []byte, scope *linage.Scope, model *linage.PackageModel) {
+	value := literalValue(expr, src)
+	derived := false
+	if value == "" {
+		if value, derived = foldConstant(expr, src, scope); !derived {
+			return
+		}
+	}
+	edge.Literal = a.truncateLiteral(value)
+	if edge.Attributes == nil {
+		edge.Attributes = map[string]interface{}{}
+	}
+	edge.Attributes[linage.FileAttribute] = strings.TrimPrefix(topFileScope(scope).ID, model.Path+":")
+	edge.Attributes[linage.LiteralLineAttribute] = int(expr.StartPoint().Row) + 1
+	edge.Attributes[linage.LiteralColumnAttribute] = int(expr.StartPoint().Column) + 1
+	if derived {
+		edge.Attributes[linage.LiteralDerivedAttribute] = true
+		if edge.Dst != nil {
+			edge.Dst.KnownValue = &linage.KnownValue{Value: value, Derived: true}
+		}
+	}
+}
+
+// knownValue returns the known value of a constant initialized with a literal or a constant expression, nil otherwise
+func knownValue(expr *sitter.Node, src []byte, scope *linage.Scope) *linage.KnownValue {
+	if value := literalValue(expr, src); value != "" {
+		return &linage.KnownValue{Value: value}
+	}
+	if value, ok := foldConstant(expr, src, scope); ok {
+		return &linage.KnownValue{Value: value, Derived: true}
+	}
+	return nil
+}
+
+// literalValue returns source text of a basic literal (including signed numbers, e.g. -1), empty for other expressions
+func literalValue(expr *sitter.Node, src []byte) string {
+	for expr != nil && (expr.Type() == "literal_element" || expr.Type() == "parenthesized_expression") && expr.NamedChildCount() == 1 {
+		expr = expr.NamedChild(0)
+	}
+	if expr == nil {
+		return ""
+	}
+	if expr.Type() == "unary_expression" {
+		operator, operand := expr.ChildByFieldName("operator"), expr.ChildByFieldName("operand")
+		if operator == nil || operand == nil || (operator.Type() != "-" && operator.Type() != "+") || literalValue(operand, src) == "" {
+			return ""
+		}
+		return string(src[expr.StartByte():expr.EndByte()])
+	}
+	if !basicLiterals[expr.Type()] {
+		return ""
+	}
+	return string(src[expr.StartByte():expr.EndByte()])
+}
+
+// truncateLiteral shortens a literal value to the configured maximum length, marking truncated values with an ellipsis
+func (a *Analyzer) truncateLiteral(value string) string {
+	limit := a.maxLiteralLength
+	if limit == 0 {
+		limit = DefaultMaxLiteralLength
+	}
+	runes := []rune(value)
+	if limit < 0 || len(runes) <= limit {
+		return value
+	}
+	return string(runes[:limit]) + "…"
+}
@@ -0,0 +1,324 @@
+package analyzer
+
+import (
+	"fmt"
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/viant/linager/analyzer/linage"
+	"strings"
+)
+
+var (
+	// printfLogMethods are log package style methods taking unkeyed arguments
+	printfLogMethods = map[string]bool{
+		"Print": true, "Printf": true, "Println": true,
+		"Fatal": true, "Fatalf": true, "Fatalln": true,
+		"Panic": true, "Panicf": true, "Panicln": true,
+		"Tracef": true, "Debugf": true, "Infof": true, "Warnf": true, "Warningf": true, "Errorf": true,
+	}
+	// levelLogMethods are slog and zap style methods taking a message followed by key-value pairs or field constructors
+	levelLogMethods = map[string]bool{
+		"Trace": true, "Debug": true, "Info": true, "Warn": true, "Warning": true, "Error": true, "DPanic": true,
+		"Debugw": true, "Infow": true, "Warnw": true, "Errorw": true, "Fatalw": true, "Panicw": true,
+	}
+	// chainLogTerminals end zerolog style event chains
+	chainLogTerminals = map[string]bool{"Msg": true, "Msgf": true, "Send": true}
+)
+
+// logField is a logged value with its literal key, the key is empty for unkeyed arguments
+type logField struct {
+	key   string
+	value *sitter.Node
+}
+
+// LogPlugin treats logging calls (log.Printf family, slog/zap key-value calls, zerolog event chains) as data sinks:
+// every call site gets a synthetic "log" identifier receiving XFER edges from logged identifiers, literal keys are
+// kept in the linage.LogKeyAttribute edge attribute. Logged function parameters are linked with WithInterprocedural,
+// which declares them.
+type LogPlugin struct{}
+
+// NewLogPlugin creates a logging sink plugin
+func NewLogPlugin() *LogPlugin {
+	return &LogPlugin{}
+}
+
+// NodeTypes limits dispatch to calls
+func (p *LogPlugin) NodeTypes() []string {
+	return []string{"call_expression"}
+}
+
+// BeforeWalk links arguments of recognized logging calls to a call site sink
+func (p *LogPlugin) BeforeWalk(n *sitter.Node, src []byte, scope *linage.Scope, model *linage.PackageModel) {
+	if n.Type() != "call_expression" {
+		return
+	}
+	fields := p.logFields(n, src, scope)
+	if len(fields) == 0 {
+		return
+	}
+	sink := p.sinkIdent(n, src, scope, model)
+	for _, field := range fields {
+		for _, id := range p.argIdents(field.value, src, scope, model) {
+			edge := &linage.DataFlowEdge{Src: id, Dst: sink, Kind: linage.Xfer, Scope: scope.ID}
+			if field.key != "" {
+				edge.Attributes = map[string]interface{}{linage.LogKeyAttribute: field.key}
+			}
+			model.DataFlows = append(model.DataFlows, edge)
+		}
+	}
+}
+
+// AfterResolveIdent is a no-op, logged identifiers are resolved when the call is visited
+func (p *LogPlugin) AfterResolveIdent(n *sitter.Node, id *linage.Identifier, scope *linage.Scope, model *linage.PackageModel) {
+}
+
+// logFields returns logged values of a recognized logging call, nil otherwise
+func (p *LogPlugin) logFields(call *sitter.Node, src []byte, scope *linage.Scope) []*logField {
+	fn := call.ChildByFieldName("function")
+	if fn == nil || fn.Type() != "selector_expression" {
+		return nil
+	}
+	operand := fn.ChildByFieldName("operand")
+	field := fn.ChildByFieldName("field")
+	if operand == nil || field == nil {
+		return nil
+	}
+	method := string(src[field.StartByte():field.EndByte()])
+	args := namedChildren(call.ChildByFieldName("arguments"))
+	if operand.Type() == "call_expression" {
+		if !chainLogTerminals[method] {
+			return nil
+		}
+		return p.chainFields(call, src, scope)
+	}
+	if !p.isLogger(operand, src, scope) {
+		return nil
+	}
+	switch {
+	case printfLogMethods[method]:
+		return unkeyedFields(args)
+	case levelLogMethods[method], strings.HasSuffix(method, "Context") && levelLogMethods[strings.TrimSuffix(method, "Context")]:
+		msgIndex := 0
+		if strings.HasSuffix(method, "Context") {
+			msgIndex = 1 // context argument precedes the message
+		}
+		if len(args) <= msgIndex {
+			return nil
+		}
+		return append(unkeyedFields(args[msgIndex:msgIndex+1]), keyValueFields(args[msgIndex+1:], src)...)
+	case method == "Log" || method == "LogAttrs":
+		if len(args) < 3 { // ctx, level, msg
+			return nil
+		}
+		return append(unkeyedFields(args[2:3]), keyValueFields(args[3:], src)...)
+	}
+	return nil
+}
+
+// chainFields returns logged values of a zerolog style event chain, e.g. log.Info().Str("email", u.Email).Msg("sent");
+// the chain root has to be a logger
+func (p *LogPlugin) chainFields(call *sitter.Node, src []byte, scope *linage.Scope) []*logField {
+	var links []*sitter.Node
+	root := call
+	for root.Type() == "call_expression" {
+		fn := root.ChildByFieldName("function")
+		if fn == nil || fn.Type() != "selector_expression" {
+			return nil
+		}
+		links = append(links, root)
+		root = fn.ChildByFieldName("operand")
+		if root == nil {
+			return nil
+		}
+	}
+	if len(links) < 2 || !p.isLogger(root, src, scope) {
+		return nil
+	}
+	var result []*logField
+	// the last link selects the level on the logger, its arguments are not logged values
+	for _, link := range links[:len(links)-1] {
+		field := link.ChildByFieldName("function").ChildByFieldName("field")
+		method := string(src[field.StartByte():field.EndByte()])
+		args := namedChildren(link.ChildByFieldName("arguments"))
+		switch {
+		case chainLogTerminals[method]:
+			result = append(result, unkeyedFields(args)...)
+		case len(args) >= 2 && isStringLiteral(args[0]):
+			key := literalText(args[0], src)
+			for _, arg := range args[1:] {
+				result = append(result, &logField{key: key, value: arg})
+			}
+		case method == "Err" && len(args) == 1:
+			result = append(result, &logField{key: "error", value: args[0]})
+		default:
+			result = append(result, unkeyedFields(args)...)
+		}
+	}
+	return result
+}
+
+// keyValueFields pairs literal keys with following values (slog.Info("msg", "email", u.Email)), field constructors
+// such as slog.String("email", u.Email) or zap.String("email", u.Email) are keyed by their first argument
+func keyValueFields(args []*sitter.Node, src []byte) []*logField {
+	var result []*logField
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg.Type() == "call_expression" {
+			if fieldArgs := namedChildren(arg.ChildByFieldName("arguments")); len(fieldArgs) >= 2 && isStringLiteral(fieldArgs[0]) {
+				key := literalText(fieldArgs[0], src)
+				for _, value := range fieldArgs[1:] {
+					result = append(result, &logField{key: key, value: value})
+				}
+				continue
+			}
+		}
+		if isStringLiteral(arg) && i+1 < len(args) {
+			result = append(result, &logField{key: literalText(arg, src), value: args[i+1]})
+			i++
+			continue
+		}
+		result = append(result, &logField{value: arg})
+	}
+	return result
+}
+
+// unkeyedFields returns arguments logged without keys
+func unkeyedFields(args []*sitter.Node) []*logField {
+	var result []*logField
+	for _, arg := range args {
+		result = append(result, &logField{value: arg})
+	}
+	return result
+}
+
+// isLogger reports whether a call operand is a logging package or logger: its name or resolved type mentions log
+// (log, slog, zerolog, logrus, s.logger) or zap
+func (p *LogPlugin) isLogger(operand *sitter.Node, src []byte, scope *linage.Scope) bool {
+	text := strings.ToLower(string(src[operand.StartByte():operand.EndByte()]))
+	if strings.Contains(text, "log") || text == "zap" {
+		return true
+	}
+	if operand.Type() != "identifier" {
+		return false
+	}
+	id := scope.Find(string(src[operand.StartByte():operand.EndByte()]))
+	if id == nil {
+		return false
+	}
+	typeName := strings.ToLower(id.Type)
+	return strings.Contains(typeName, "log") || strings.Contains(typeName, "zap")
+}
+
+// argIdents returns variables and selected fields of a logged expression, callees of nested calls are skipped
+func (p *LogPlugin) argIdents(n *sitter.Node, src []byte, scope *linage.Scope, model *linage.PackageModel) []*linage.Identifier {
+	switch n.Type() {
+	case "identifier":
+		if isBlank(n, src) {
+			return nil
+		}
+		if id := scope.Find(string(src[n.StartByte():n.EndByte()])); id != nil {
+			return []*linage.Identifier{id}
+		}
+		return nil
+	case "selector_expression":
+		if id := p.selectorIdent(n, src, scope, model); id != nil {
+			return []*linage.Identifier{id}
+		}
+		return nil
+	case "call_expression":
+		return p.argIdents(n.ChildByFieldName("arguments"), src, scope, model)
+	}
+	var result []*linage.Identifier
+	for _, child := range namedChildren(n) {
+		result = append(result, p.argIdents(child, src, scope, model)...)
+	}
+	return result
+}
+
+// selectorIdent returns the field identifier of a selector chain rooted at a variable (e.g. u.Address.City), using
+// the position key the analyzer resolves selected fields with; package selectors yield nil
+func (p *LogPlugin) selectorIdent(n *sitter.Node, src []byte, scope *linage.Scope, model *linage.PackageModel) *linage.Identifier {
+	var fields []*sitter.Node
+	root := n
+	for root.Type() == "selector_expression" {
+		fields = append([]*sitter.Node{root.ChildByFieldName("field")}, fields...)
+		root = root.ChildByFieldName("operand")
+	}
+	if root.Type() != "identifier" {
+		return nil
+	}
+	base := scope.Find(string(src[root.StartByte():root.EndByte()]))
+	if base == nil {
+		return nil
+	}
+	sel := &linage.Selector{Field: base.Name}
+	for _, field := range fields {
+		sel = &linage.Selector{Field: string(src[field.StartByte():field.EndByte()]), Parent: sel}
+	}
+	fieldNode := fields[len(fields)-1]
+	file := strings.TrimPrefix(topFileScope(scope).ID, model.Path+":")
+	key := fmt.Sprintf("%s::%s::%d", model.Path, file, fieldNode.StartByte())
+	if id, ok := model.Idents[key]; ok {
+		return id
+	}
+	id := &linage.Identifier{
+		ID:        key,
+		Name:      sel.Field,
+		Kind:      "field",
+		Package:   model.Path,
+		File:      file,
+		StartByte: fieldNode.StartByte(),
+		Selector:  sel,
+		Node:      fieldNode,
+	}
+	model.Idents[key] = id
+	return id
+}
+
+// sinkIdent returns the synthetic log identifier of a call site, named after the call with arguments elided
+// (e.g. log.Info().Str().Msg)
+func (p *LogPlugin) sinkIdent(call *sitter.Node, src []byte, scope *linage.Scope, model *linage.PackageModel) *linage.Identifier {
+	file := strings.TrimPrefix(topFileScope(scope).ID, model.Path+":")
+	key := fmt.Sprintf("%s::%s::%d#log", model.Path, file, call.StartByte())
+	if id, ok := model.Idents[key]; ok {
+		return id
+	}
+	id := &linage.Identifier{
+		ID:        key,
+		Name:      callName(call, src),
+		Kind:      linage.LogSinkKind,
+		Package:   model.Path,
+		File:      file,
+		StartByte: call.StartByte(),
+		Node:      call,
+	}
+	model.Idents[key] = id
+	return id
+}
+
+// callName renders a call chain without arguments
+func callName(n *sitter.Node, src []byte) string {
+	switch n.Type() {
+	case "call_expression":
+		fn := n.ChildByFieldName("function")
+		name := callName(fn, src)
+		if n.Parent() != nil && n.Parent().Type() == "selector_expression" {
+			return name + "()"
+		}
+		return name
+	case "selector_expression":
+		operand := n.ChildByFieldName("operand")
+		field := n.ChildByFieldName("field")
+		return callName(operand, src) + "." + string(src[field.StartByte():field.EndByte()])
+	}
+	return string(src[n.StartByte():n.EndByte()])
+}
+
+// isStringLiteral reports whether node is a Go string literal
+func isStringLiteral(n *sitter.Node) bool {
+	return n.Type() == "interpreted_string_literal" || n.Type() == "raw_string_literal"
+}
+
+// literalText returns a string literal value without quotes
+func literalText(n *sitter.Node, src []byte) string {
+	return strings.Trim(string(src[n.StartByte():n.EndByte()]), "`\"")
+}
@@ -0,0 +1,387 @@
+package lsif
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/viant/linager/analyzer/linage"
+	"github.com/viant/linager/inspector/graph"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Version is the LSIF protocol version of exported dumps
+const Version = "0.5.0"
+
+type (
+	// Position is a zero-based LSIF position
+	Position struct {
+		Line      int `json:"line"`
+		Character int `json:"character"`
+	}
+
+	// Element is an LSIF vertex or edge, unused properties are omitted
+	Element struct {
+		ID               int          `json:"id"`
+		Type             string       `json:"type"`
+		Label            string       `json:"label"`
+		Version          string       `json:"version,omitempty"`
+		ProjectRoot      string       `json:"projectRoot,omitempty"`
+		PositionEncoding string       `json:"positionEncoding,omitempty"`
+		ToolInfo         *ToolInfo    `json:"toolInfo,omitempty"`
+		Kind             string       `json:"kind,omitempty"`
+		URI              string       `json:"uri,omitempty"`
+		LanguageID       string       `json:"languageId,omitempty"`
+		Start            *Position    `json:"start,omitempty"`
+		End              *Position    `json:"end,omitempty"`
+		Result           *HoverResult `json:"result,omitempty"`
+		OutV             int          `json:"outV,omitempty"`
+		InV              int          `json:"inV,omitempty"`
+		InVs             []int        `json:"inVs,omitempty"`
+		Document         int          `json:"document,omitempty"`
+		Property         string       `json:"property,omitempty"`
+	}
+
+	// ToolInfo describes the tool producing a dump
+	ToolInfo struct {
+		Name string `json:"name"`
+	}
+
+	// HoverResult holds hover contents, code blocks are MarkedString objects and documentation plain strings
+	HoverResult struct {
+		Contents []interface{} `json:"contents"`
+	}
+
+	// MarkedString is a code block of hover contents
+	MarkedString struct {
+		Language string `json:"language"`
+		Value    string `json:"value"`
+	}
+)
+
+// Exporter writes LSIF dumps of Go definitions and references, hover content is taken from the inspected project
+type Exporter struct {
+	// Root is the project root directory, data point file paths are relative to it
+	Root     string
+	project  *graph.Project
+	encoder  *json.Encoder
+	lastID   int
+	docs     map[string]int // file path -> document vertex ID
+	contains map[int][]int  // document vertex ID -> range vertex IDs
+	ranges   map[string]int // file:line:column -> range vertex ID
+	docOrder []string
+}
+
+// NewExporter creates an exporter for the given project root
+func NewExporter(root string) *Exporter {
+	return &Exporter{Root: root}
+}
+
+// Export writes LSIF vertices and edges as JSON lines for data points, the project is used for hover content and may be nil
+func (e *Exporter) Export(w io.Writer, project *graph.Project, points []*linage.DataPoint) error {
+	e.project = project
+	e.encoder = json.NewEncoder(w)
+	e.lastID = 0
+	e.docs = map[string]int{}
+	e.contains = map[int][]int{}
+	e.ranges = map[string]int{}
+	e.docOrder = nil
+
+	if _, err := e.vertex(&Element{Label: "metaData", Version: Version, ProjectRoot: e.uri(""), PositionEncoding: "utf-16", ToolInfo: &ToolInfo{Name: "linager"}}); err != nil {
+		return err
+	}
+	projectID, err := e.vertex(&Element{Label: "project", Kind: "go"})
+	if err != nil {
+		return err
+	}
+	for _, point := range points {
+		if point.Definition.FilePath == "" || point.Definition.LineNumber == 0 {
+			continue
+		}
+		if err = e.document(point.Definition.FilePath); err != nil {
+			return err
+		}
+		for _, site := range references(point) {
+			if err = e.document(site.FilePath); err != nil {
+				return err
+			}
+		}
+	}
+	if len(e.docOrder) > 0 {
+		var docIDs []int
+		for _, path := range e.docOrder {
+			docIDs = append(docIDs, e.docs[path])
+		}
+		if _, err = e.edge(&Element{Label: "contains", OutV: projectID, InVs: docIDs}); err != nil {
+			return err
+		}
+	}
+	for _, point := range points {
+		if point.Definition.FilePath == "" || point.Definition.LineNumber == 0 {
+			continue
+		}
+		if err = e.point(point); err != nil {
+			return err
+		}
+	}
+	for _, path := range e.docOrder {
+		docID := e.docs[path]
+		if len(e.contains[docID]) == 0 {
+			continue
+		}
+		if _, err = e.edge(&Element{Label: "contains", OutV: docID, InVs: e.contains[docID]}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// point writes definition and reference ranges of a data point linked by a result set
+func (e *Exporter) point(point *linage.DataPoint) error {
+	definition := point.Definition
+	if definition.ColumnEnd <= definition.ColumnStart {
+		definition.ColumnEnd = definition.ColumnStart + len(point.Name)
+	}
+	defRange, created, err := e.rangeOf(&definition)
+	if err != nil || !created {
+		return err // range already describes another identifier declared at the same position
+	}
+	resultSetID, err := e.vertex(&Element{Label: "resultSet"})
+	if err != nil {
+		return err
+	}
+	if _, err = e.edge(&Element{Label: "next", OutV: defRange, InV: resultSetID}); err != nil {
+		return err
+	}
+	if hover := e.hover(point); hover != nil {
+		hoverID, err := e.vertex(&Element{Label: "hoverResult", Result: hover})
+		if err != nil {
+			return err
+		}
+		if _, err = e.edge(&Element{Label: "textDocument/hover", OutV: resultSetID, InV: hoverID}); err != nil {
+			return err
+		}
+	}
+	definitionID, err := e.vertex(&Element{Label: "definitionResult"})
+	if err != nil {
+		return err
+	}
+	if _, err = e.edge(&Element{Label: "textDocument/definition", OutV: resultSetID, InV: definitionID}); err != nil {
+		return err
+	}
+	if _, err = e.edge(&Element{Label: "item", OutV: definitionID, InVs: []int{defRange}, Document: e.docs[definition.FilePath]}); err != nil {
+		return err
+	}
+	referenceID, err := e.vertex(&Element{Label: "referenceResult"})
+	if err != nil {
+		return err
+	}
+	if _, err = e.edge(&Element{Label: "textDocument/references", OutV: resultSetID, InV: referenceID}); err != nil {
+		return err
+	}
+	if _, err = e.edge(&Element{Label: "item", OutV: referenceID, InVs: []int{defRange}, Document: e.docs[definition.FilePath], Property: "definitions"}); err != nil {
+		return err
+	}
+	byDocument := map[string][]int{}
+	var documents []string
+	for _, site := range references(point) {
+		rangeID, created, err := e.rangeOf(site)
+		if err != nil {
+			return err
+		}
+		if !created {
+			continue
+		}
+		if _, err = e.edge(&Element{Label: "next", OutV: rangeID, InV: resultSetID}); err != nil {
+			return err
+		}
+		if _, ok := byDocument[site.FilePath]; !ok {
+			documents = append(documents, site.FilePath)
+		}
+		byDocument[site.FilePath] = append(byDocument[site.FilePath], rangeID)
+	}
+	for _, path := range documents {
+		if _, err = e.edge(&Element{Label: "item", OutV: referenceID, InVs: byDocument[path], Document: e.docs[path], Property: "references"}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// references returns distinct access sites of a data point with recorded positions
+func references(point *linage.DataPoint) []*linage.CodeLocation {
+	var result []*linage.CodeLocation
+	seen := map[string]bool{}
+	for _, edges := range [][]*linage.DataFlowEdge{point.Reads, point.Writes, point.Calls} {
+		for _, edge := range edges {
+			site := edge.Site()
+			if site == nil || site.FilePath == "" {
+				continue
+			}
+			if site.ColumnEnd <= site.ColumnStart {
+				site.ColumnEnd = site.ColumnStart + len(point.Name)
+			}
+			key := fmt.Sprintf("%s:%d:%d", site.FilePath, site.LineNumber, site.ColumnStart)
+			if seen[key] || (site.FilePath == point.Definition.FilePath && site.LineNumber == point.Definition.LineNumber && site.ColumnStart == point.Definition.ColumnStart) {
+				continue
+			}
+			seen[key] = true
+			result = append(result, site)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].FilePath != result[j].FilePath {
+			return result[i].FilePath < result[j].FilePath
+		}
+		if result[i].LineNumber != result[j].LineNumber {
+			return result[i].LineNumber < result[j].LineNumber
+		}
+		return result[i].ColumnStart < result[j].ColumnStart
+	})
+	return result
+}
+
+// hover returns signature and documentation of the data point declaration
+func (e *Exporter) hover(point *linage.DataPoint) *HoverResult {
+	signature, comment := "", ""
+	switch point.Kind {
+	case "func", "method":
+		signature = point.Type
+		if function := e.lookupFunction(point); function != nil {
+			if function.Signature != "" {
+				signature = function.Signature
+			}
+			if function.Comment != nil {
+				comment = function.Comment.Text
+			}
+		}
+	case "type":
+		signature = "type " + point.Name
+		if aType := e.lookupType(point); aType != nil && aType.Comment != nil {
+			comment = aType.Comment.Text
+		}
+	default:
+		if point.Type != "" {
+			signature = point.Name + " " + point.Type
+		}
+	}
+	if signature == "" {
+		return nil
+	}
+	result := &HoverResult{Contents: []interface{}{&MarkedString{Language: "go", Value: signature}}}
+	if comment = strings.TrimSpace(comment); comment != "" {
+		result.Contents = append(result.Contents, comment)
+	}
+	return result
+}
+
+// lookupFunction returns the project function or method of a data point, points linked by linage.AttachGraph
+// resolve by their graph reference
+func (e *Exporter) lookupFunction(point *linage.DataPoint) *graph.Function {
+	if e.project == nil {
+		return nil
+	}
+	if target := e.project.ByRef(point.GraphRef); target != nil && target.Function != nil {
+		return target.Function
+	}
+	receiver := ""
+	if point.Kind == "method" {
+		qualifier := strings.TrimSuffix(point.ID, "."+point.Name)
+		receiver = qualifier[strings.LastIndex(qualifier, ".")+1:]
+	}
+	for _, pkg := range e.project.Packages {
+		for _, file := range pkg.FileSet {
+			if receiver == "" {
+				if function := file.LookupFunction(point.Name); function != nil {
+					return function
+				}
+				continue
+			}
+			if aType := file.LookupType(receiver); aType != nil {
+				for _, method := range aType.Methods {
+					if method.Name == point.Name {
+						return method
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// lookupType returns the project type of a data point, points linked by linage.AttachGraph resolve by their graph
+// reference
+func (e *Exporter) lookupType(point *linage.DataPoint) *graph.Type {
+	if e.project == nil {
+		return nil
+	}
+	if target := e.project.ByRef(point.GraphRef); target != nil && target.Type != nil {
+		return target.Type
+	}
+	for _, pkg := range e.project.Packages {
+		for _, file := range pkg.FileSet {
+			if aType := file.LookupType(point.Name); aType != nil {
+				return aType
+			}
+		}
+	}
+	return nil
+}
+
+// document writes a document vertex once per file
+func (e *Exporter) document(path string) error {
+	if _, ok := e.docs[path]; ok {
+		return nil
+	}
+	id, err := e.vertex(&Element{Label: "document", URI: e.uri(path), LanguageID: "go"})
+	if err != nil {
+		return err
+	}
+	e.docs[path] = id
+	e.docOrder = append(e.docOrder, path)
+	return nil
+}
+
+// rangeOf writes a range vertex for a location unless the position already has one
+func (e *Exporter) rangeOf(location *linage.CodeLocation) (int, bool, error) {
+	key := fmt.Sprintf("%s:%d:%d", location.FilePath, location.LineNumber, location.ColumnStart)
+	if id, ok := e.ranges[key]; ok {
+		return id, false, nil
+	}
+	start := &Position{Line: location.LineNumber - 1, Character: location.ColumnStart - 1}
+	end := &Position{Line: location.LineNumber - 1, Character: location.ColumnEnd - 1}
+	id, err := e.vertex(&Element{Label: "range", Start: start, End: end})
+	if err != nil {
+		return 0, false, err
+	}
+	e.ranges[key] = id
+	docID := e.docs[location.FilePath]
+	e.contains[docID] = append(e.contains[docID], id)
+	return id, true, nil
+}
+
+// uri returns file URI of a root relative path
+func (e *Exporter) uri(path string) string {
+	location := path
+	if !filepath.IsAbs(location) {
+		location = filepath.Join(e.Root, path)
+	}
+	return "file://" + filepath.ToSlash(location)
+}
+
+func (e *Exporter) vertex(element *Element) (int, error) {
+	element.Type = "vertex"
+	return e.write(element)
+}
+
+func (e *Exporter) edge(element *Element) (int, error) {
+	element.Type = "edge"
+	return e.write(element)
+}
+
+func (e *Exporter) write(element *Element) (int, error) {
+	e.lastID++
+	element.ID = e.lastID
+	return element.ID, e.encoder.Encode(element)
+}
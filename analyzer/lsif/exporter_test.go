@@ -0,0 +1,196 @@
+package lsif_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/smacker/go-tree-sitter/golang"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/linager/analyzer"
+	"github.com/viant/linager/analyzer/linage"
+	"github.com/viant/linager/analyzer/lsif"
+	goinspector "github.com/viant/linager/inspector/golang"
+	"github.com/viant/linager/inspector/graph"
+	"testing"
+)
+
+func TestExporter_Export(t *testing.T) {
+	source := `package main
+
+// Greeter builds greetings
+type Greeter struct{}
+
+// Greet returns a greeting for name
+func (g *Greeter) Greet(name string) string {
+	return "hello " + name
+}
+
+// Shout greets loudly
+func Shout(name string) string {
+	return name + "!"
+}
+
+func main() {
+	g := &Greeter{}
+	g.Greet("world")
+	Shout("world")
+	Shout("again")
+}
+`
+	model := linage.NewPackageModel()
+	anAnalyzer := analyzer.NewAnalyzer(analyzer.WithLanguage(golang.GetLanguage()), analyzer.WithMatcher(analyzer.GolangFiles))
+	if !assert.NoError(t, anAnalyzer.AnalyzeSourceCode("/app", []byte(source), "main.go", linage.NewScope(), model)) {
+		return
+	}
+	file, err := goinspector.NewInspector(&graph.Config{IncludeUnexported: true}).InspectSource([]byte(source))
+	if !assert.NoError(t, err) {
+		return
+	}
+	project := &graph.Project{Name: "app", Packages: []*graph.Package{{Name: "main", FileSet: []*graph.File{file}}}}
+
+	output := &bytes.Buffer{}
+	if !assert.NoError(t, lsif.NewExporter("/app").Export(output, project, linage.NewDataPoints(model))) {
+		return
+	}
+	elements, err := validate(output.Bytes())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// Shout is referenced from two call sites of main
+	shout := findRange(elements, 11, 5)
+	if !assert.NotNil(t, shout, "Shout definition range") {
+		return
+	}
+	resultSet := outTarget(elements, "next", shout.ID)
+	references := outTarget(elements, "textDocument/references", resultSet)
+	var referenced []int
+	for _, element := range elements {
+		if element.Label == "item" && element.OutV == references && element.Property == "references" {
+			for _, id := range element.InVs {
+				referenced = append(referenced, elements[id].Start.Line)
+			}
+		}
+	}
+	assert.Equal(t, []int{18, 19}, referenced)
+	hover := elements[outTarget(elements, "textDocument/hover", resultSet)]
+	if assert.NotNil(t, hover.Result) {
+		assert.Equal(t, map[string]interface{}{"language": "go", "value": "func Shout(name string) string"}, hover.Result.Contents[0])
+		assert.Equal(t, "Shout greets loudly", hover.Result.Contents[1])
+	}
+
+	// method call resolves to the method definition
+	greet := findRange(elements, 6, 18)
+	if assert.NotNil(t, greet, "Greet definition range") {
+		greetRefs := outTarget(elements, "textDocument/references", outTarget(elements, "next", greet.ID))
+		assert.NotZero(t, greetRefs)
+	}
+}
+
+// element captures LSIF element properties checked by tests
+type element struct {
+	ID       int    `json:"id"`
+	Type     string `json:"type"`
+	Label    string `json:"label"`
+	URI      string `json:"uri"`
+	OutV     int    `json:"outV"`
+	InV      int    `json:"inV"`
+	InVs     []int  `json:"inVs"`
+	Document int    `json:"document"`
+	Property string `json:"property"`
+	Start    *struct {
+		Line      int `json:"line"`
+		Character int `json:"character"`
+	} `json:"start"`
+	Result *struct {
+		Contents []interface{} `json:"contents"`
+	} `json:"result"`
+}
+
+// validate checks LSIF 0.5 structure rules: unique ids, metaData first, edges referencing previously emitted vertices,
+// item edges referencing documents and ranges contained by their documents
+func validate(data []byte) (map[int]*element, error) {
+	elements := map[int]*element{}
+	contained := map[int]int{} // range -> document
+	var items []*element
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		item := &element{}
+		if err := json.Unmarshal(scanner.Bytes(), item); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if item.ID == 0 || elements[item.ID] != nil {
+			return nil, fmt.Errorf("line %d: invalid or duplicate id %d", line, item.ID)
+		}
+		if line == 1 && item.Label != "metaData" {
+			return nil, fmt.Errorf("first element is %v, expected metaData", item.Label)
+		}
+		elements[item.ID] = item
+		switch item.Type {
+		case "vertex":
+			continue
+		case "edge":
+		default:
+			return nil, fmt.Errorf("line %d: invalid type %v", line, item.Type)
+		}
+		targets := item.InVs
+		if item.InV != 0 {
+			targets = append(targets, item.InV)
+		}
+		if len(targets) == 0 {
+			return nil, fmt.Errorf("line %d: edge %v without targets", line, item.Label)
+		}
+		for _, id := range append(targets, item.OutV) {
+			if vertex := elements[id]; vertex == nil || vertex.Type != "vertex" {
+				return nil, fmt.Errorf("line %d: edge %v references unknown vertex %d", line, item.Label, id)
+			}
+		}
+		switch item.Label {
+		case "contains":
+			if elements[item.OutV].Label == "document" {
+				for _, id := range item.InVs {
+					contained[id] = item.OutV
+				}
+			}
+		case "item":
+			if document := elements[item.Document]; document == nil || document.Label != "document" {
+				return nil, fmt.Errorf("line %d: item edge references unknown document %d", line, item.Document)
+			}
+			items = append(items, item)
+		}
+	}
+	for _, item := range items {
+		for _, id := range item.InVs {
+			if contained[id] != item.Document {
+				return nil, fmt.Errorf("item %d: range %d is not contained by document %d", item.ID, id, item.Document)
+			}
+		}
+	}
+	for id, vertex := range elements {
+		if vertex.Label == "range" && contained[id] == 0 {
+			return nil, fmt.Errorf("range %d is not contained by any document", id)
+		}
+	}
+	return elements, scanner.Err()
+}
+
+// findRange returns range vertex starting at zero-based line and character
+func findRange(elements map[int]*element, line, character int) *element {
+	for _, item := range elements {
+		if item.Label == "range" && item.Start.Line == line && item.Start.Character == character {
+			return item
+		}
+	}
+	return nil
+}
+
+// outTarget returns the target vertex of the labeled edge leaving a vertex
+func outTarget(elements map[int]*element, label string, outV int) int {
+	for _, item := range elements {
+		if item.Label == label && item.OutV == outV {
+			return item.InV
+		}
+	}
+	return 0
+}
@@ -0,0 +1,168 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/viant/linager/analyzer/linage"
+	"sort"
+	"strings"
+)
+
+// minMappedFields is the number of distinct field pairs making a function a struct-to-struct mapping
+const minMappedFields = 2
+
+// FieldMapping describes a source struct field flowing into a destination struct field
+type FieldMapping struct {
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	Renamed     bool   `json:"renamed,omitempty"` // source and destination field names differ, e.g. Amount -> Total
+}
+
+// MappingReport describes a function copying fields of one struct type into fields of another (e.g. DTO conversion)
+type MappingReport struct {
+	Function        string          `json:"function"`
+	File            string          `json:"file,omitempty"`
+	Line            int             `json:"line,omitempty"`
+	SourceType      string          `json:"sourceType"`
+	DestinationType string          `json:"destinationType"`
+	Fields          []*FieldMapping `json:"fields"`
+	Unmapped        []string        `json:"unmapped,omitempty"` // destination fields not assigned from the source type
+}
+
+// MappingReports lists struct-to-struct mappings
+type MappingReports []*MappingReport
+
+// MappingReports detects functions where several fields of one struct type flow into fields of another struct type,
+// both explicit assignments (out.Name = in.Name) and composite literal keys (Out{Name: in.Name}) are covered;
+// struct field declarations are taken from files analyzed by this analyzer
+func (a *Analyzer) MappingReports(model *linage.PackageModel) MappingReports {
+	scopes := make(map[string]*linage.Scope, len(model.Scopes))
+	for _, scope := range model.Scopes {
+		scopes[scope.ID] = scope
+	}
+	byKey := map[string]*MappingReport{}
+	seen := map[string]bool{}
+	var result MappingReports
+	for _, edge := range model.DataFlows {
+		if edge.Kind != linage.Xfer || edge.Src.Selector == nil || edge.Dst.Selector == nil {
+			continue
+		}
+		scope := scopes[edge.Scope]
+		fn := enclosingFunction(scope)
+		if fn == nil {
+			continue
+		}
+		srcType, dstType := a.ownerType(edge.Src.Selector, scope), a.ownerType(edge.Dst.Selector, scope)
+		if srcType == "" || dstType == "" || srcType == dstType {
+			continue
+		}
+		key := fn.ID + ":" + srcType + ":" + dstType
+		report, ok := byKey[key]
+		if !ok {
+			report = &MappingReport{Function: fn.Name, File: mappingFile(fn, model), Line: fn.StartLine, SourceType: srcType, DestinationType: dstType}
+			byKey[key] = report
+			result = append(result, report)
+		}
+		pair := &FieldMapping{Source: edge.Src.Selector.Field, Destination: edge.Dst.Selector.Field}
+		if pairKey := key + ":" + pair.Source + ":" + pair.Destination; !seen[pairKey] {
+			seen[pairKey] = true
+			pair.Renamed = pair.Source != pair.Destination
+			report.Fields = append(report.Fields, pair)
+		}
+	}
+
+	var reports MappingReports
+	for _, report := range result {
+		if len(report.Fields) < minMappedFields {
+			continue
+		}
+		mapped := map[string]bool{}
+		for _, pair := range report.Fields {
+			mapped[pair.Destination] = true
+		}
+		for field := range a.structFields[report.DestinationType] {
+			if !mapped[field] {
+				report.Unmapped = append(report.Unmapped, field)
+			}
+		}
+		sort.Strings(report.Unmapped)
+		sort.SliceStable(report.Fields, func(i, j int) bool { return report.Fields[i].Destination < report.Fields[j].Destination })
+		reports = append(reports, report)
+	}
+	sort.SliceStable(reports, func(i, j int) bool {
+		if reports[i].File != reports[j].File {
+			return reports[i].File < reports[j].File
+		}
+		return reports[i].Line < reports[j].Line
+	})
+	return reports
+}
+
+// ownerType returns the struct type declaring the selected field, resolved from the root variable type visible in
+// scope and the struct fields along the selector chain; empty when unknown
+func (a *Analyzer) ownerType(sel *linage.Selector, scope *linage.Scope) string {
+	var path []string
+	for cur := sel.Parent; cur != nil; cur = cur.Parent {
+		path = append([]string{cur.Field}, path...)
+	}
+	if len(path) == 0 || scope == nil {
+		return ""
+	}
+	root := scope.Find(path[0])
+	if root == nil || root.Type == "" {
+		return ""
+	}
+	typeName := root.Type
+	for _, field := range path[1:] {
+		var ok bool
+		if typeName, ok = a.fieldType(scope, typeName, field); !ok {
+			return ""
+		}
+	}
+	typeName = baseTypeName(typeName)
+	if _, ok := a.fieldType(scope, typeName, sel.Field); !ok {
+		return ""
+	}
+	return typeName
+}
+
+// mappingFile returns the file of a function scope, function scopes are nested in file scopes identified by package:file
+func mappingFile(fn *linage.Scope, model *linage.PackageModel) string {
+	fileScope := topFileScope(fn)
+	return strings.TrimPrefix(fileScope.ID, model.Path+":")
+}
+
+// JSON renders the reports as indented JSON
+func (r MappingReports) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// Markdown renders the reports as a Markdown document with a field table per mapping
+func (r MappingReports) Markdown() string {
+	builder := &strings.Builder{}
+	builder.WriteString("# Struct mappings\n")
+	if len(r) == 0 {
+		builder.WriteString("\n_None._\n")
+		return builder.String()
+	}
+	for _, report := range r {
+		fmt.Fprintf(builder, "\n## %s → %s\n\n", report.SourceType, report.DestinationType)
+		location := report.File
+		if report.Line > 0 {
+			location = fmt.Sprintf("%s:%d", report.File, report.Line)
+		}
+		fmt.Fprintf(builder, "Mapped in `%s` (%s).\n\n", report.Function, location)
+		builder.WriteString("| Source | Destination | Renamed |\n| --- | --- | --- |\n")
+		for _, pair := range report.Fields {
+			renamed := ""
+			if pair.Renamed {
+				renamed = "yes"
+			}
+			fmt.Fprintf(builder, "| %s.%s | %s.%s | %s |\n", report.SourceType, pair.Source, report.DestinationType, pair.Destination, renamed)
+		}
+		for _, field := range report.Unmapped {
+			fmt.Fprintf(builder, "| _unmapped_ | %s.%s | |\n", report.DestinationType, field)
+		}
+	}
+	return builder.String()
+}
@@ -3,6 +3,7 @@ package analyzer
 import (
 	"fmt"
 	"github.com/viant/linager/analyzer/linage"
+	"github.com/viant/linager/inspector/graph"
 	"strings"
 
 	sitter "github.com/smacker/go-tree-sitter"
@@ -13,27 +14,52 @@ import (
 // -----------------------------------------------------------------------------
 
 func (a *Analyzer) walk(n *sitter.Node, src []byte, scope *linage.Scope, model *linage.PackageModel) {
-	// plugin hooks before processing each AST node
-	for _, plugin := range a.plugins {
+	// nodes beyond the file budget are not analyzed, see graph.Config.NodeLimit
+	if !a.budget.Visit() {
+		return
+	}
+	// plugin hooks before and after processing each AST node
+	if a.trace != nil {
+		a.trace.enter(n, src, model)
+	}
+	plugins := a.nodePlugins(n.Type())
+	for _, plugin := range plugins.before {
 		plugin.BeforeWalk(n, src, scope, model)
 	}
+	a.visit(n, src, scope, model)
+	for _, plugin := range plugins.after {
+		plugin.AfterWalk(n, src, scope, model)
+	}
+	if a.trace != nil {
+		a.trace.exit(model)
+	}
+}
+
+// visit applies default processing to an AST node and its subtree
+func (a *Analyzer) visit(n *sitter.Node, src []byte, scope *linage.Scope, model *linage.PackageModel) {
 	switch n.Type() {
 	case "block":
-		blk := &linage.Scope{ID: fmt.Sprintf("%s.block@%d", scope.ID, n.StartByte()), Kind: "block", Parent: scope, Symbols: map[string]*linage.Identifier{}, Start: int(n.StartByte()), End: int(n.EndByte())}
+		blk := (&linage.Scope{ID: fmt.Sprintf("%s.block@%d", scope.ID, n.StartByte()), Kind: "block", Parent: scope, Symbols: map[string]*linage.Identifier{}}).SetRange(n)
 		model.Scopes = append(model.Scopes, blk)
 		for i := 0; i < int(n.ChildCount()); i++ {
 			a.walk(n.Child(i), src, blk, model)
 		}
 		return
-	case "function_declaration":
+	case "function_declaration", "method_declaration", "constructor_declaration":
 		a.handleFunction(n, src, scope, model)
 		return
+	case "method_invocation", "object_creation_expression":
+		a.handleInvocation(n, src, scope, model)
+		return
 	case "type_spec":
 		a.handleTypeSpec(n, src, scope, model)
 		return
 	case "short_var_declaration", "assignment_statement":
 		a.handleAssignment(n, src, scope, model)
 		return
+	case "var_spec", "const_spec":
+		a.handleVarSpec(n, src, scope, model)
+		return
 	case "call_expression":
 		a.handleCall(n, src, scope, model)
 		return
@@ -57,6 +83,17 @@ func (a *Analyzer) walk(n *sitter.Node, src []byte, scope *linage.Scope, model *
 		// capture return flows: map returned identifiers into function summary
 		a.handleReturn(n, src, scope, model)
 		return
+	case "labeled_statement":
+		// labels name statements only, they are not identifiers of the enclosing scope
+		for i := 0; i < int(n.NamedChildCount()); i++ {
+			if child := n.NamedChild(i); child.Type() != "label_name" && child.Type() != "identifier" {
+				a.walk(child, src, scope, model)
+			}
+		}
+		return
+	case "goto_statement", "break_statement", "continue_statement":
+		// jumps move no data, their labels are not identifiers
+		return
 	}
 
 	for i := 0; i < int(n.ChildCount()); i++ {
@@ -67,29 +104,17 @@ func (a *Analyzer) walk(n *sitter.Node, src []byte, scope *linage.Scope, model *
 // -------------------- Declarations -------------------------
 
 func (a *Analyzer) handleFunction(n *sitter.Node, src []byte, current *linage.Scope, model *linage.PackageModel) {
-	fnNameNode := n.ChildByFieldName("name")
-	name := string(src[fnNameNode.StartByte():fnNameNode.EndByte()])
-	fnID := fmt.Sprintf("%s.%s", current.ID, name)
-	fnScope := &linage.Scope{ID: fnID, Kind: "function", Name: name, Parent: current, Symbols: map[string]*linage.Identifier{}, Start: int(n.StartByte()), End: int(n.EndByte())}
-	// create function identifier with signature
-	// signature: raw text from func start to body start, e.g. "func main(x int) error"
-	var signature string
-	if body := n.ChildByFieldName("body"); body != nil {
-		signature = strings.TrimSpace(string(src[n.StartByte():body.StartByte()]))
-	}
-	ident := &linage.Identifier{
-		ID:         fnID,
-		Name:       name,
-		Kind:       "func",
-		Package:    model.Path,
-		File:       current.ID,
-		StartByte:  fnNameNode.StartByte(),
-		Type:       signature,
-		Node:       n,
-		Annotation: a.extractAnnotations(n, src),
+	ident := a.functionIdent(n, n.ChildByFieldName("name"), src, current, model)
+	symbol := strings.TrimPrefix(ident.ID, current.ID+".")
+	fnID := ident.ID
+	fnScope := (&linage.Scope{ID: fnID, Kind: "function", Name: symbol, Parent: current, Symbols: map[string]*linage.Identifier{}}).SetRange(n)
+	// Java formal parameters are typed so that invocations taking them resolve overloads
+	for _, param := range namedChildren(n.ChildByFieldName("parameters")) {
+		nameNode, typeNode := param.ChildByFieldName("name"), param.ChildByFieldName("type")
+		if param.Type() == "formal_parameter" && nameNode != nil && typeNode != nil {
+			a.resolveIdent(nameNode, nil, src, fnScope, model).Type = javaTypeText(typeNode, src)
+		}
 	}
-	// register function identifier in current scope
-	current.Symbols[name] = ident
 	model.Scopes = append(model.Scopes, fnScope)
 	// inter-procedural summary: capture formal parameters and return identifiers
 	if a.interprocedural {
@@ -98,11 +123,14 @@ func (a *Analyzer) handleFunction(n *sitter.Node, src []byte, current *linage.Sc
 		if paramsNode := n.ChildByFieldName("parameters"); paramsNode != nil {
 			for i := 0; i < int(paramsNode.NamedChildCount()); i++ {
 				param := paramsNode.NamedChild(i)
-				if param.Type() != "parameter" {
+				if param.Type() != "parameter_declaration" && param.Type() != "variadic_parameter_declaration" {
 					continue
 				}
-				if nameNode := param.ChildByFieldName("name"); nameNode != nil {
+				for _, nameNode := range parameterNames(param) {
 					paramIdent := a.resolveIdent(nameNode, nil, src, fnScope, model)
+					if typeNode := param.ChildByFieldName("type"); typeNode != nil && paramIdent.Type == "" {
+						paramIdent.Type = strings.TrimSpace(string(src[typeNode.StartByte():typeNode.EndByte()]))
+					}
 					summary.Params = append(summary.Params, paramIdent)
 				}
 			}
@@ -112,22 +140,48 @@ func (a *Analyzer) handleFunction(n *sitter.Node, src []byte, current *linage.Sc
 			if resultNode.Type() == "parameter_list" {
 				for i := 0; i < int(resultNode.NamedChildCount()); i++ {
 					param := resultNode.NamedChild(i)
-					if param.Type() != "parameter" {
+					if param.Type() != "parameter_declaration" {
+						continue
+					}
+					var resultType string
+					if typeNode := param.ChildByFieldName("type"); typeNode != nil {
+						resultType = strings.TrimSpace(string(src[typeNode.StartByte():typeNode.EndByte()]))
+					}
+					names := parameterNames(param)
+					if len(names) == 0 {
+						// unnamed result in a list, e.g. (int, error): use a synthetic return identifier per position
+						summary.Returns = append(summary.Returns, a.resultIdent(ident, len(summary.Returns), resultType, model))
+						summary.Results = append(summary.Results, resultType)
 						continue
 					}
-					if nameNode := param.ChildByFieldName("name"); nameNode != nil {
+					for _, nameNode := range names {
 						retIdent := a.resolveIdent(nameNode, nil, src, fnScope, model)
+						retIdent.Type = resultType
 						summary.Returns = append(summary.Returns, retIdent)
+						summary.Results = append(summary.Results, resultType)
 					}
 				}
 			} else {
 				// anonymous return: use function identifier as return
 				summary.Returns = append(summary.Returns, ident)
+				summary.Results = append(summary.Results, strings.TrimSpace(string(src[resultNode.StartByte():resultNode.EndByte()])))
 			}
 		} else {
 			// no explicit result: default to function identifier as return
 			summary.Returns = append(summary.Returns, ident)
 		}
+		for i, ret := range summary.Returns {
+			if i < len(summary.Results) && summary.Results[i] == "error" {
+				a.errorIdents[ret.ID] = true
+			}
+		}
+		if body := n.ChildByFieldName("body"); body != nil {
+			for _, stmt := range namedChildren(body) {
+				if stmt.Type() != "comment" {
+					summary.Statements++
+				}
+			}
+		}
 		a.funcSummaries[ident] = summary
 	}
 
@@ -137,6 +191,74 @@ func (a *Analyzer) handleFunction(n *sitter.Node, src []byte, current *linage.Sc
 	}
 }
 
+// functionIdent returns the identifier of a function, method or constructor declaration registered in the current
+// scope, declaring it on first use: methods are qualified by the receiver type so that same named methods of different
+// types do not collide, Java methods and constructors by the owner type and parameter types so that overloads do not
+func (a *Analyzer) functionIdent(n, fnNameNode *sitter.Node, src []byte, current *linage.Scope, model *linage.PackageModel) *linage.Identifier {
+	name := string(src[fnNameNode.StartByte():fnNameNode.EndByte()])
+	symbol, kind := name, "func"
+	javaSym, owner, params := javaSymbol(n, name, src)
+	switch {
+	case javaSym != "":
+		symbol, kind = javaSym, "method"
+	case n.Type() == "method_declaration":
+		if recv := receiverName(n, src); recv != "" {
+			symbol, kind = recv+"."+name, "method"
+		}
+	}
+	fnID := fmt.Sprintf("%s.%s", current.ID, symbol)
+	if ident := current.Symbols[symbol]; ident != nil && ident.ID == fnID && ident.StartByte == fnNameNode.StartByte() {
+		return ident // declared ahead of the walk
+	}
+	// create function identifier with signature
+	// signature: raw text from func start to body start, e.g. "func main(x int) error"
+	var signature string
+	if body := n.ChildByFieldName("body"); body != nil {
+		signature = strings.TrimSpace(string(src[n.StartByte():body.StartByte()]))
+	}
+	ident := &linage.Identifier{
+		ID:         fnID,
+		Name:       name,
+		Kind:       kind,
+		Package:    model.Path,
+		File:       current.ID,
+		StartByte:  fnNameNode.StartByte(),
+		Type:       signature,
+		Ref:        functionRef(n, name, src, model).String(),
+		Node:       n,
+		Annotation: a.extractAnnotations(n, src),
+	}
+	// register function identifier in current scope
+	current.Symbols[symbol] = ident
+	if javaSym != "" {
+		a.overloads[name] = append(a.overloads[name], &overload{ident: ident, owner: owner, params: params})
+	}
+	return ident
+}
+
+// parameterNames returns name nodes of a parameter declaration (e.g. a, b in "a, b int")
+func parameterNames(param *sitter.Node) []*sitter.Node {
+	var names []*sitter.Node
+	for i := 0; i < int(param.ChildCount()); i++ {
+		if param.FieldNameForChild(i) == "name" {
+			names = append(names, param.Child(i))
+		}
+	}
+	return names
+}
+
+// namedChildren returns named children of a node
+func namedChildren(n *sitter.Node) []*sitter.Node {
+	if n == nil {
+		return nil
+	}
+	var result []*sitter.Node
+	for i := 0; i < int(n.NamedChildCount()); i++ {
+		result = append(result, n.NamedChild(i))
+	}
+	return result
+}
+
 // handle type specifications (e.g., type Foo struct {...})
 func (a *Analyzer) handleTypeSpec(n *sitter.Node, src []byte, scope *linage.Scope, model *linage.PackageModel) {
 	// In the Go grammar `type_spec` may expose the identifier as either a
@@ -177,56 +299,86 @@ func (a *Analyzer) handleTypeSpec(n *sitter.Node, src []byte, scope *linage.Scop
 		}
 	}
 
+	if typeNode != nil && typeNode.Type() == "interface_type" {
+		a.interfaces[id.Name] = a.interfaceMethods(typeNode, src)
+	}
+	if typeNode != nil && typeNode.Type() == "function_type" {
+		a.funcTypes[id.Name] = true
+	}
 	if typeNode != nil && typeNode.Type() == "struct_type" {
-		// Find the field declaration list (named "body" in older grammars or
-		// "field_declaration_list" in newer ones).
-		body := typeNode.ChildByFieldName("body")
-		if body == nil {
-			for i := 0; i < int(typeNode.NamedChildCount()); i++ {
-				cand := typeNode.NamedChild(i)
-				if cand.Type() == "field_declaration_list" {
-					body = cand
-					break
-				}
-			}
+		key := id.Name
+		if isLocalScope(scope) {
+			key = localTypeKey(scope, id.Name) // e.g. two functions declaring type row
 		}
+		a.registerStructFields(key, typeNode, src, model)
+	}
+}
 
-		if body != nil {
-			fields := map[string]string{}
-			for i := 0; i < int(body.NamedChildCount()); i++ {
-				fldDecl := body.NamedChild(i)
-				if fldDecl.Type() != "field_declaration" {
-					continue
-				}
-				// resolve field type (first child with type_identifier / qualified_type / etc.)
-				var fieldType string
-				typeChild := fldDecl.ChildByFieldName("type")
-				if typeChild == nil {
-					// fallback – pick last named child assuming it is the type
-					if fldDecl.NamedChildCount() > 0 {
-						last := fldDecl.NamedChild(int(fldDecl.NamedChildCount()) - 1)
-						if last != nil && (strings.HasSuffix(last.Type(), "_identifier") || strings.HasSuffix(last.Type(), "_type") || last.Type() == "type_identifier") {
-							typeChild = last
-						}
-					}
+// registerStructFields captures field types of a struct type node, inline struct fields are registered as anonymous
+// types named after their owner path (e.g. Outer.Config$anon) so that selector chains resolve through them
+func (a *Analyzer) registerStructFields(typeName string, typeNode *sitter.Node, src []byte, model *linage.PackageModel) {
+	// Find the field declaration list (named "body" in older grammars or
+	// "field_declaration_list" in newer ones).
+	body := typeNode.ChildByFieldName("body")
+	if body == nil {
+		for i := 0; i < int(typeNode.NamedChildCount()); i++ {
+			cand := typeNode.NamedChild(i)
+			if cand.Type() == "field_declaration_list" {
+				body = cand
+				break
+			}
+		}
+	}
+	if body == nil {
+		return
+	}
+	fields := map[string]string{}
+	for i := 0; i < int(body.NamedChildCount()); i++ {
+		fldDecl := body.NamedChild(i)
+		if fldDecl.Type() != "field_declaration" {
+			continue
+		}
+		// resolve field type (first child with type_identifier / qualified_type / etc.)
+		var fieldType string
+		typeChild := fldDecl.ChildByFieldName("type")
+		if typeChild == nil {
+			// fallback – pick last named child assuming it is the type
+			if fldDecl.NamedChildCount() > 0 {
+				last := fldDecl.NamedChild(int(fldDecl.NamedChildCount()) - 1)
+				if last != nil && (strings.HasSuffix(last.Type(), "_identifier") || strings.HasSuffix(last.Type(), "_type") || last.Type() == "type_identifier") {
+					typeChild = last
 				}
-				if typeChild != nil {
-					fieldType = strings.TrimSpace(string(src[typeChild.StartByte():typeChild.EndByte()]))
+			}
+		}
+		if typeChild != nil {
+			fieldType = strings.TrimSpace(string(src[typeChild.StartByte():typeChild.EndByte()]))
+		}
+		// collect field identifiers
+		for j := 0; j < int(fldDecl.NamedChildCount()); j++ {
+			ch := fldDecl.NamedChild(j)
+			if ch.Type() == "field_identifier" || ch.Type() == "identifier" {
+				fieldName := string(src[ch.StartByte():ch.EndByte()])
+				if typeChild != nil && typeChild.Type() == "struct_type" {
+					fieldType = a.declareAnonymousType(strings.TrimSuffix(typeName, graph.AnonymousSuffix)+"."+fieldName, typeChild, src, model)
 				}
-				// collect field identifiers
-				for j := 0; j < int(fldDecl.NamedChildCount()); j++ {
-					ch := fldDecl.NamedChild(j)
-					if ch.Type() == "field_identifier" || ch.Type() == "identifier" {
-						fieldName := string(src[ch.StartByte():ch.EndByte()])
-						fields[fieldName] = fieldType
-					}
+				fields[fieldName] = fieldType
+				if a.classifier != nil {
+					a.classifyField(typeName, fieldName, fieldType, fldDecl.ChildByFieldName("tag"), src, model)
 				}
 			}
-			if len(fields) > 0 {
-				a.structFields[id.Name] = fields
-			}
 		}
 	}
+	if len(fields) > 0 {
+		a.structFields[typeName] = fields
+	}
+}
+
+// declareAnonymousType registers fields of an inline struct declared by a field or variable path and returns its
+// synthetic type name, see graph.AnonymousTypeName
+func (a *Analyzer) declareAnonymousType(path string, typeNode *sitter.Node, src []byte, model *linage.PackageModel) string {
+	typeName := graph.AnonymousTypeName(path)
+	a.registerStructFields(typeName, typeNode, src, model)
+	return typeName
 }
 
 // -----------------------------------------------------------------------------
@@ -239,7 +391,7 @@ func (a *Analyzer) handleAssignment(n *sitter.Node, src []byte, Scope *linage.Sc
 	if left == nil || right == nil {
 		return
 	}
-	lhs := a.extractIdentifiers(left, src, Scope, model)
+	lhs := a.assignTargets(left, src, Scope, model)
 	rhs := a.extractIdentifiers(right, src, Scope, model)
 
 	// handle short variable declarations (:=) with go_basic.gox type inference
@@ -255,6 +407,9 @@ func (a *Analyzer) handleAssignment(n *sitter.Node, src []byte, Scope *linage.Sc
 		}
 		// declare variables with writes, infer types, and record flows
 		for idx, id := range lhs {
+			if id == nil {
+				continue
+			}
 			id.Kind = "var"
 			// infer simple types based on tree-sitter node kinds
 			if idx < len(exprNodes) {
@@ -264,13 +419,22 @@ func (a *Analyzer) handleAssignment(n *sitter.Node, src []byte, Scope *linage.Sc
 					// infer type including generics from literal
 					body := expr.ChildByFieldName("body")
 					typeNode := expr.ChildByFieldName("type")
-					if body != nil {
+					if typeNode != nil && typeNode.Type() == "struct_type" {
+						id.Type = a.declareAnonymousType(id.Name, typeNode, src, model)
+					} else if body != nil {
 						id.Type = strings.TrimSpace(string(src[expr.StartByte():body.StartByte()]))
 					} else if typeNode != nil {
 						id.Type = strings.TrimSpace(string(src[typeNode.StartByte():typeNode.EndByte()]))
 					}
 					// record nested field flows for composite literal
 					a.handleCompositeLiteral(id, expr, src, Scope, model)
+				case "unary_expression":
+					// pointer to composite literal, e.g. svc := &Service{}
+					if operand := expr.ChildByFieldName("operand"); operand != nil && operand.Type() == "composite_literal" {
+						if typeNode := operand.ChildByFieldName("type"); typeNode != nil {
+							id.Type = "*" + strings.TrimSpace(string(src[typeNode.StartByte():typeNode.EndByte()]))
+						}
+					}
 				case "identifier":
 					if idx < len(rhs) && rhs[idx].Type != "" {
 						id.Type = rhs[idx].Type
@@ -298,22 +462,27 @@ func (a *Analyzer) handleAssignment(n *sitter.Node, src []byte, Scope *linage.Sc
 			} else {
 				srcIdent = id
 			}
-			model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: srcIdent, Dst: id, Kind: linage.Write, Scope: Scope.ID})
+			edge := &linage.DataFlowEdge{Src: srcIdent, Dst: id, Kind: linage.Write, Scope: Scope.ID}
+			if idx < len(exprNodes) {
+				a.recordLiteral(edge, exprNodes[idx], src, Scope, model)
+			}
+			model.DataFlows = append(model.DataFlows, edge)
 		}
 		// record reads and transfers for each expression
 		for idx, expr := range exprNodes {
+			if idx < len(lhs) && lhs[idx] != nil {
+				a.bindFuncValue(lhs[idx], expr, src, Scope, model)
+			}
 			// call-through: handle call expressions
 			if expr.Type() == "call_expression" {
 				if a.interprocedural {
 					a.handleCallInAssignment(expr, src, Scope, model, lhs)
 				} else {
-					// legacy mapping: directly pass arguments to variables
-					if argList := expr.ChildByFieldName("argument_list"); argList != nil {
-						argIds := a.extractIdentifiers(argList, src, Scope, model)
-						for _, v := range argIds {
+					// legacy mapping: directly pass arguments to the variables receiving call results
+					for _, arg := range namedChildren(expr.ChildByFieldName("arguments")) {
+						for _, v := range a.extractIdentifiers(arg, src, Scope, model) {
 							model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: v, Dst: v, Kind: linage.Read, Scope: Scope.ID})
-							if idx < len(lhs) {
-								dst := lhs[idx]
+							for _, dst := range resultTargets(lhs, idx, len(exprNodes)) {
 								model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: v, Dst: dst, Kind: linage.Xfer, Scope: Scope.ID})
 							}
 						}
@@ -325,10 +494,9 @@ func (a *Analyzer) handleAssignment(n *sitter.Node, src []byte, Scope *linage.Sc
 			for _, v := range vals {
 				// read from source
 				model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: v, Dst: v, Kind: linage.Read, Scope: Scope.ID})
-				// transfer from source to destination variable
-				if idx < len(lhs) {
-					dst := lhs[idx]
-					model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: v, Dst: dst, Kind: linage.Xfer, Scope: Scope.ID})
+				// transfer from source to destination variable, values assigned to the blank identifier are dropped
+				if idx < len(lhs) && lhs[idx] != nil {
+					model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: v, Dst: lhs[idx], Kind: linage.Xfer, Scope: Scope.ID})
 				}
 			}
 		}
@@ -336,16 +504,171 @@ func (a *Analyzer) handleAssignment(n *sitter.Node, src []byte, Scope *linage.Sc
 	}
 
 	// handle standard assignment (=)
-	for _, id := range lhs {
-		model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: id, Dst: id, Kind: linage.Write, Scope: Scope.ID})
+	for idx, expr := range namedChildren(right) {
+		if idx < len(lhs) && lhs[idx] != nil {
+			a.bindFuncValue(lhs[idx], expr, src, Scope, model)
+		}
+	}
+	values := namedChildren(right)
+	for idx, id := range lhs {
+		if id == nil {
+			continue
+		}
+		edge := &linage.DataFlowEdge{Src: id, Dst: id, Kind: linage.Write, Scope: Scope.ID}
+		if len(values) == len(lhs) {
+			a.recordLiteral(edge, values[idx], src, Scope, model)
+		}
+		if global := packageVar(id, Scope); global != nil {
+			// record package-level variable writes with their location
+			if edge.Attributes == nil {
+				edge.Attributes = map[string]interface{}{}
+			}
+			edge.Attributes["global"] = global.ID
+			edge.Attributes[linage.LineAttribute] = int(n.StartPoint().Row) + 1
+		}
+		model.DataFlows = append(model.DataFlows, edge)
 	}
 	for idx, srcID := range rhs {
 		// read from source
 		model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: srcID, Dst: srcID, Kind: linage.Read, Scope: Scope.ID})
 		// transfer to destination
-		if idx < len(lhs) {
-			dst := lhs[idx]
-			model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: srcID, Dst: dst, Kind: linage.Xfer, Scope: Scope.ID})
+		if idx < len(lhs) && lhs[idx] != nil {
+			model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: srcID, Dst: lhs[idx], Kind: linage.Xfer, Scope: Scope.ID})
+		}
+	}
+	if a.errorTracking {
+		for idx, expr := range namedChildren(right) {
+			if expr.Type() == "call_expression" && idx < len(lhs) {
+				a.trackErrorCall(expr, lhs[idx:], src, Scope, model)
+			}
+		}
+	}
+}
+
+// declarePackageSymbols registers package-level constants and variables of a file before its functions are walked,
+// so that uses preceding the declaration resolve to the package-scoped identifier
+func (a *Analyzer) declarePackageSymbols(root *sitter.Node, src []byte, fileScope *linage.Scope, model *linage.PackageModel) {
+	for _, decl := range namedChildren(root) {
+		if decl.Type() != "const_declaration" && decl.Type() != "var_declaration" {
+			continue
+		}
+		var specs []*sitter.Node
+		for _, child := range namedChildren(decl) {
+			if child.Type() == "var_spec_list" {
+				specs = append(specs, namedChildren(child)...) // var ( ... ) groups
+				continue
+			}
+			specs = append(specs, child)
+		}
+		for _, spec := range specs {
+			if spec.Type() != "const_spec" && spec.Type() != "var_spec" {
+				continue
+			}
+			a.declareSpecNames(spec, src, fileScope, model)
+		}
+	}
+}
+
+// declareSpecNames declares names of a const or var specification, package-level names are visible from every file;
+// constants get known values of their literal or folded constant expression values
+func (a *Analyzer) declareSpecNames(n *sitter.Node, src []byte, Scope *linage.Scope, model *linage.PackageModel) []*linage.Identifier {
+	kind := "var"
+	var values []*sitter.Node
+	if n.Type() == "const_spec" {
+		kind = "const"
+		values = namedChildren(n.ChildByFieldName("value"))
+	}
+	var typeName string
+	typeNode := n.ChildByFieldName("type")
+	if typeNode != nil {
+		typeName = strings.TrimSpace(string(src[typeNode.StartByte():typeNode.EndByte()]))
+	}
+	var ids []*linage.Identifier
+	for i, nameNode := range parameterNames(n) {
+		if isBlank(nameNode, src) {
+			ids = append(ids, nil) // e.g. var _ Handler = (*handler)(nil)
+			continue
+		}
+		id := a.resolveIdent(nameNode, nil, src, Scope, model)
+		id.Kind = kind
+		if typeNode != nil && typeNode.Type() == "struct_type" {
+			id.Type = a.declareAnonymousType(id.Name, typeNode, src, model)
+		} else if typeName != "" {
+			id.Type = typeName
+		}
+		if i < len(values) {
+			id.KnownValue = knownValue(values[i], src, Scope)
+		}
+		if Scope.Kind == "file" && Scope.Parent != nil {
+			Scope.Parent.Symbols[id.Name] = id
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// handleVarSpec declares variables and constants from var or const specifications (e.g., var registry = map[string]int{})
+func (a *Analyzer) handleVarSpec(n *sitter.Node, src []byte, Scope *linage.Scope, model *linage.PackageModel) {
+	values := namedChildren(n.ChildByFieldName("value"))
+	for idx, id := range a.declareSpecNames(n, src, Scope, model) {
+		if id == nil {
+			if idx < len(values) {
+				a.handleSpecValue(nil, values[idx], src, Scope, model)
+			}
+			continue
+		}
+		srcIdent := id
+		if idx < len(values) && values[idx].Type() == "composite_literal" {
+			if typeNode := values[idx].ChildByFieldName("type"); id.Type == "" && typeNode != nil && typeNode.Type() == "struct_type" {
+				id.Type = a.declareAnonymousType(id.Name, typeNode, src, model)
+			} else if id.Type == "" {
+				if body := values[idx].ChildByFieldName("body"); body != nil {
+					id.Type = strings.TrimSpace(string(src[values[idx].StartByte():body.StartByte()]))
+				}
+			}
+			a.handleCompositeLiteral(id, values[idx], src, Scope, model)
+			srcIdent = a.literalIdent(values[idx], src, Scope, model)
+		}
+		edge := &linage.DataFlowEdge{Src: srcIdent, Dst: id, Kind: linage.Write, Scope: Scope.ID}
+		if idx < len(values) {
+			a.recordLiteral(edge, values[idx], src, Scope, model)
+		}
+		model.DataFlows = append(model.DataFlows, edge)
+		if idx >= len(values) || values[idx].Type() == "composite_literal" {
+			continue
+		}
+		a.bindFuncValue(id, values[idx], src, Scope, model)
+		a.handleSpecValue(id, values[idx], src, Scope, model)
+	}
+}
+
+// handleSpecValue records flows of a var or const specification value into id (nil for the blank identifier): calls
+// get call edges, function literal bodies are walked in their own scope, other values are read and transferred
+func (a *Analyzer) handleSpecValue(id *linage.Identifier, value *sitter.Node, src []byte, Scope *linage.Scope, model *linage.PackageModel) {
+	switch value.Type() {
+	case "call_expression":
+		if a.interprocedural && id != nil {
+			a.handleCallInAssignment(value, src, Scope, model, []*linage.Identifier{id})
+			return
+		}
+		a.handleCall(value, src, Scope, model)
+		if id == nil {
+			return
+		}
+		// legacy mapping: directly pass arguments to the variable receiving the call result
+		for _, arg := range namedChildren(value.ChildByFieldName("arguments")) {
+			for _, v := range a.extractIdentifiers(arg, src, Scope, model) {
+				model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: v, Dst: id, Kind: linage.Xfer, Scope: Scope.ID})
+			}
+		}
+	case "func_literal":
+		a.walk(value, src, Scope, model)
+	default:
+		for _, v := range a.extractIdentifiers(value, src, Scope, model) {
+			model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: v, Dst: v, Kind: linage.Read, Scope: Scope.ID})
+			if id != nil {
+				model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: v, Dst: id, Kind: linage.Xfer, Scope: Scope.ID})
+			}
 		}
 	}
 }
@@ -358,7 +681,7 @@ func (a *Analyzer) handleCompositeLiteral(dest *linage.Identifier, comp *sitter.
 	// prepare struct field types if available
 	var fieldTypes map[string]string
 	if dest.Type != "" {
-		fieldTypes = a.structFields[dest.Type]
+		fieldTypes, _ = a.typeFields(Scope, dest.Type)
 	}
 	for i := 0; i < int(body.ChildCount()); i++ {
 		elem := body.Child(i)
@@ -367,6 +690,10 @@ func (a *Analyzer) handleCompositeLiteral(dest *linage.Identifier, comp *sitter.
 		}
 		keyNode := elem.ChildByFieldName("key")
 		valNode := elem.ChildByFieldName("value")
+		if keyNode == nil && valNode == nil && elem.NamedChildCount() == 2 {
+			// grammar versions without key/value fields wrap both sides in literal_element nodes
+			keyNode, valNode = elem.NamedChild(0), elem.NamedChild(1)
+		}
 		if keyNode == nil || valNode == nil {
 			continue
 		}
@@ -401,7 +728,9 @@ func (a *Analyzer) handleCompositeLiteral(dest *linage.Identifier, comp *sitter.
 			model.Idents[keyID] = fld
 		}
 		// record write to field
-		model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: fld, Dst: fld, Kind: linage.Write, Scope: Scope.ID})
+		edge := &linage.DataFlowEdge{Src: fld, Dst: fld, Kind: linage.Write, Scope: Scope.ID}
+		a.recordLiteral(edge, valNode, src, Scope, model)
+		model.DataFlows = append(model.DataFlows, edge)
 		// record value flows into field
 		vals := a.extractIdentifiers(valNode, src, Scope, model)
 		for _, v := range vals {
@@ -420,39 +749,12 @@ func (a *Analyzer) handleCall(n *sitter.Node, src []byte, Scope *linage.Scope, m
 	if fnNode == nil {
 		return
 	}
-	fns := a.extractIdentifiers(fnNode, src, Scope, model)
-	for _, fn := range fns {
-		model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: fn, Dst: fn, Kind: linage.Call, Scope: Scope.ID})
-	}
-	// Concurrency: track sync.WaitGroup Done/Wait as synthetic channel flows
-	if n.Type() == "call_expression" && fnNode.Type() == "selector_expression" {
-		// resolve base WaitGroup identifier
-		baseNode := fnNode.ChildByFieldName("operand")
-		if baseNode != nil {
-			baseIdent := a.resolveIdent(baseNode, nil, src, Scope, model)
-			// simple type check for WaitGroup
-			if strings.HasSuffix(baseIdent.Type, "WaitGroup") {
-				// synthetic channel key for this WaitGroup
-				wgKey := fmt.Sprintf("%s::wg::%d", baseIdent.ID, baseNode.StartByte())
-				wgChan, ok := model.Idents[wgKey]
-				if !ok {
-					wgChan = &linage.Identifier{ID: wgKey, Name: baseIdent.Name + ".wgChan", Package: baseIdent.Package, File: baseIdent.File, StartByte: baseNode.StartByte()}
-					model.Idents[wgKey] = wgChan
-				}
-				// map Done to write, Wait to read on synthetic channel
-				for _, fn := range fns {
-					if fn.Selector != nil {
-						switch fn.Selector.Field {
-						case "Done":
-							model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: wgChan, Dst: wgChan, Kind: linage.Write, Scope: Scope.ID})
-						case "Wait":
-							model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: wgChan, Dst: wgChan, Kind: linage.Read, Scope: Scope.ID})
-						}
-					}
-				}
-			}
-		}
+	fns, callKind, ref := a.callTargets(fnNode, src, Scope, model)
+	if callKind == linage.CallVirtual {
+		defer a.markPossibleSince(model, len(model.DataFlows))
 	}
+	a.addCallEdges(fns, callKind, ref, fnNode, src, Scope, Scope.ID, model)
+	a.markRecursion(fns, Scope)
 	args := n.ChildByFieldName("argument_list")
 	if args != nil {
 		ids := a.extractIdentifiers(args, src, Scope, model)
@@ -460,6 +762,34 @@ func (a *Analyzer) handleCall(n *sitter.Node, src []byte, Scope *linage.Scope, m
 			model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: id, Dst: id, Kind: linage.Read, Scope: Scope.ID})
 		}
 	}
+	if a.interprocedural {
+		// map actual arguments to formal parameters of summarized callees
+		actuals := namedChildren(n.ChildByFieldName("arguments"))
+		for _, fn := range fns {
+			summary, ok := a.funcSummaries[fn]
+			if !ok {
+				// external or unresolved callee (e.g. log.Printf): arguments flow into the call itself
+				for _, actual := range actuals {
+					for _, id := range a.extractIdentifiers(actual, src, Scope, model) {
+						model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: id, Dst: fn, Kind: linage.Xfer, Scope: Scope.ID})
+					}
+				}
+				continue
+			}
+			if a.trace != nil {
+				a.trace.summary(fn, n, "call")
+			}
+			for i, actual := range actuals {
+				if i >= len(summary.Params) {
+					break
+				}
+				for _, id := range a.extractIdentifiers(actual, src, Scope, model) {
+					model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: id, Dst: summary.Params[i], Kind: linage.Xfer, Scope: Scope.ID})
+				}
+			}
+			a.addFuncArgFlows(actuals, summary, src, Scope, model)
+		}
+	}
 }
 
 // handleCallInAssignment applies inter-procedural call-return flows for call expressions in assignments
@@ -468,21 +798,22 @@ func (a *Analyzer) handleCallInAssignment(expr *sitter.Node, src []byte, Scope *
 	if fnNode == nil {
 		return
 	}
-	fns := a.extractIdentifiers(fnNode, src, Scope, model)
-	// collect argument expression nodes (skip commas)
-	var argExprs []*sitter.Node
-	if argList := expr.ChildByFieldName("argument_list"); argList != nil {
-		for i := 0; i < int(argList.ChildCount()); i++ {
-			ch := argList.Child(i)
-			if ch.Type() == "," {
-				continue
-			}
-			argExprs = append(argExprs, ch)
-		}
+	fns, callKind, ref := a.callTargets(fnNode, src, Scope, model)
+	if callKind == linage.CallVirtual {
+		defer a.markPossibleSince(model, len(model.DataFlows))
+	}
+	a.addCallEdges(fns, callKind, ref, fnNode, src, Scope, Scope.ID, model)
+	a.markRecursion(fns, Scope)
+	if a.errorTracking {
+		a.trackErrorCall(expr, lhs, src, Scope, model)
 	}
+	argExprs := namedChildren(expr.ChildByFieldName("arguments"))
 	// for each referenced function, apply its summary or fallback mapping
 	for _, fn := range fns {
 		if summary, ok := a.funcSummaries[fn]; ok {
+			if a.trace != nil {
+				a.trace.summary(fn, expr, "call-return")
+			}
 			// prepare synthetic return identifiers for the call site
 			fileScope := topFileScope(Scope)
 			file := strings.TrimPrefix(fileScope.ID, model.Path+":")
@@ -501,10 +832,18 @@ func (a *Analyzer) handleCallInAssignment(expr *sitter.Node, src []byte, Scope *
 						StartByte: callStart,
 						Kind:      "call",
 					}
+					if retIdx < len(summary.Results) {
+						id.Type = summary.Results[retIdx]
+					}
 					model.Idents[callKey] = id
 					callRets[retIdx] = id
 				}
+				if a.errorTracking && a.errorIdents[summary.Returns[retIdx].ID] {
+					// continue error lineage from the callee return into the call site
+					a.addErrorFlow(summary.Returns[retIdx], callRets[retIdx], Scope, model)
+				}
 			}
+			a.addFuncArgFlows(argExprs, summary, src, Scope, model)
 			// map actual arguments to synthetic call returns based on summary
 			for pIdx := range summary.Params {
 				if pIdx < len(argExprs) {
@@ -530,20 +869,31 @@ func (a *Analyzer) handleCallInAssignment(expr *sitter.Node, src []byte, Scope *
 					}
 				}
 			}
-			// finally map synthetic call returns to LHS variables
+			// finally map synthetic call returns to LHS variables, a single summarized return fans out to every variable
 			for retIdx, dst := range lhs {
-				if retIdx < len(callRets) {
-					model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: callRets[retIdx], Dst: dst, Kind: linage.Xfer, Scope: Scope.ID})
+				ret := retIdx
+				if len(callRets) == 1 {
+					ret = 0
+				}
+				if dst == nil || ret >= len(callRets) {
+					continue
+				}
+				if dst.Type == "" {
+					dst.Type = callRets[ret].Type
 				}
+				model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: callRets[ret], Dst: dst, Kind: linage.Xfer, Scope: Scope.ID})
+			}
+			if a.inlineMaxStatements > 0 {
+				a.recordCallSite(fn, expr, src, Scope, model, lhs, callRets)
 			}
 		} else {
-			// fallback: conservative mapping actual args to LHS
-			for idx, argExpr := range argExprs {
+			// fallback: conservative mapping of actual args to every variable receiving call results
+			for _, argExpr := range argExprs {
 				actuals := a.extractIdentifiers(argExpr, src, Scope, model)
 				for _, actual := range actuals {
 					model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: actual, Dst: actual, Kind: linage.Read, Scope: Scope.ID})
-					if idx < len(lhs) {
-						model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: actual, Dst: lhs[idx], Kind: linage.Xfer, Scope: Scope.ID})
+					for _, dst := range nonBlank(lhs) {
+						model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: actual, Dst: dst, Kind: linage.Xfer, Scope: Scope.ID})
 					}
 				}
 			}
@@ -585,11 +935,13 @@ func (a *Analyzer) handleImportSpec(n *sitter.Node, src []byte) {
 
 // handleReturn captures data-flow from return-expression identifiers into the function summary or identity function
 func (a *Analyzer) handleReturn(n *sitter.Node, src []byte, scope *linage.Scope, model *linage.PackageModel) {
-	if scope.Kind != "function" {
+	// returns in nested blocks (if, for, switch) belong to the enclosing function
+	fnScope := enclosingFunction(scope)
+	if fnScope == nil || fnScope.Parent == nil {
 		return
 	}
 	// locate the function identifier in the parent (package) scope
-	funcIdent := scope.Parent.Symbols[scope.Name]
+	funcIdent := fnScope.Parent.Symbols[fnScope.Name]
 	if funcIdent == nil {
 		return
 	}
@@ -608,14 +960,22 @@ func (a *Analyzer) handleReturn(n *sitter.Node, src []byte, scope *linage.Scope,
 			if child.Type() == "return" || child.Type() == "," {
 				continue
 			}
+			if child.Type() == "expression_list" {
+				exprNodes = append(exprNodes, namedChildren(child)...)
+				continue
+			}
 			exprNodes = append(exprNodes, child)
 		}
-		// map each returned identifier into its summary return and record inter-procedural flows
+		// map each returned identifier into its summary return and record inter-procedural flows, per return site too
+		site := &ReturnSite{Line: int(n.StartPoint().Row) + 1, Branches: returnBranches(n, fnScope), Flows: make(map[int][]int)}
+		summary.Sites = append(summary.Sites, site)
+		siteIdx := len(summary.Sites) - 1
 		for idx, expr := range exprNodes {
+			a.recordInlineFlow(summary, idx, expr, src)
 			vals := a.extractIdentifiers(expr, src, scope, model)
 			for _, v := range vals {
 				// read from returned value
-				model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: v, Dst: v, Kind: linage.Read, Scope: scope.ID})
+				model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: v, Dst: v, Kind: linage.Read, Scope: scope.ID, Attributes: returnAttributes(siteIdx, site.Branches)})
 				// determine target return identifier
 				var retIdent *linage.Identifier
 				if idx < len(summary.Returns) {
@@ -624,15 +984,19 @@ func (a *Analyzer) handleReturn(n *sitter.Node, src []byte, scope *linage.Scope,
 					retIdent = funcIdent
 				}
 				// transfer into function return
-				model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: v, Dst: retIdent, Kind: linage.Xfer, Scope: scope.ID})
+				model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: v, Dst: retIdent, Kind: linage.Xfer, Scope: scope.ID, Attributes: returnAttributes(siteIdx, site.Branches)})
 				// if returned value matches a formal parameter, record summary mapping
 				for pIdx, pIdent := range summary.Params {
 					if pIdent == v {
 						summary.Flows[pIdx] = append(summary.Flows[pIdx], idx)
+						site.Flows[pIdx] = append(site.Flows[pIdx], idx)
 						break
 					}
 				}
 			}
+			if a.errorTracking && expr.Type() == "call_expression" && idx < len(summary.Returns) {
+				a.trackErrorCall(expr, summary.Returns[idx:idx+1], src, scope, model)
+			}
 		}
 		return
 	}
@@ -641,6 +1005,7 @@ func (a *Analyzer) handleReturn(n *sitter.Node, src []byte, scope *linage.Scope,
 		return
 	}
 	// map returned identifiers into the function identifier for identity functions
+	siteIdx, branches := returnSiteIndex(n, funcIdent), returnBranches(n, fnScope)
 	for i := 0; i < int(n.ChildCount()); i++ {
 		child := n.Child(i)
 		if child.Type() == "return" || child.Type() == "," {
@@ -648,8 +1013,8 @@ func (a *Analyzer) handleReturn(n *sitter.Node, src []byte, scope *linage.Scope,
 		}
 		vals := a.extractIdentifiers(child, src, scope, model)
 		for _, v := range vals {
-			model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: v, Dst: v, Kind: linage.Read, Scope: scope.ID})
-			model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: v, Dst: funcIdent, Kind: linage.Xfer, Scope: scope.ID})
+			model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: v, Dst: v, Kind: linage.Read, Scope: scope.ID, Attributes: returnAttributes(siteIdx, branches)})
+			model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: v, Dst: funcIdent, Kind: linage.Xfer, Scope: scope.ID, Attributes: returnAttributes(siteIdx, branches)})
 		}
 	}
 }
@@ -3,21 +3,17 @@ package analyzer
 import (
 	sitter "github.com/smacker/go-tree-sitter"
 	"github.com/viant/linager/analyzer/linage"
+	"github.com/viant/linager/inspector/graph"
+	"github.com/viant/linager/inspector/treesitter"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 type Option func(*Analyzer)
 
-// AnalyzerPlugin defines extension hooks for analyzer passes.
-// BeforeWalk is called for each AST node before default processing.
-// AfterResolveIdent is called after an identifier is resolved.
-type AnalyzerPlugin interface {
-	BeforeWalk(n *sitter.Node, src []byte, scope *linage.Scope, model *linage.PackageModel)
-	AfterResolveIdent(n *sitter.Node, id *linage.Identifier, scope *linage.Scope, model *linage.PackageModel)
-}
-
 // AnnotationHook is a callback invoked when an identifier with annotations is found.
 // It can be used to add custom data-flow edges based on code metadata (e.g., tags, annotations).
 type AnnotationHook func(id *linage.Identifier, anns linage.Annotations, scope *linage.Scope, model *linage.PackageModel)
@@ -25,6 +21,7 @@ type AnnotationHook func(id *linage.Identifier, anns linage.Annotations, scope *
 func WithLanguage(language *sitter.Language) Option {
 	return func(a *Analyzer) {
 		a.parser.SetLanguage(language)
+		a.language = language
 	}
 }
 
@@ -55,13 +52,31 @@ func WithAnnotationHook(hook AnnotationHook) Option {
 	}
 }
 
-// WithPlugin registers an AnalyzerPlugin for extended analysis.
+// WithPlugin registers an AnalyzerPlugin for extended analysis, plugins are dispatched by priority (see
+// PrioritizedPlugin) and then in registration order.
 func WithPlugin(p AnalyzerPlugin) Option {
 	return func(a *Analyzer) {
 		a.plugins = append(a.plugins, p)
 	}
 }
 
+// WithLogSinks registers a LogPlugin treating logging calls as data sinks, see linage.PackageModel.LoggedIdentifiers.
+func WithLogSinks() Option {
+	return WithPlugin(NewLogPlugin())
+}
+
+// WithSQLLineage registers a SQLPlugin linking constants, struct tags, ORM model columns and SQL query columns sharing
+// literal values.
+func WithSQLLineage() Option {
+	return WithPlugin(NewSQLPlugin())
+}
+
+// WithSerializationBoundaries registers a SerializationPlugin marking json, xml, yaml and proto serialization calls as
+// boundaries, see linage.PackageModel.SerializationPoints.
+func WithSerializationBoundaries() Option {
+	return WithPlugin(NewSerializationPlugin())
+}
+
 // WithInterprocedural enables inter-procedural call-return analysis (linking actual args to formals and returns to call sites).
 func WithInterprocedural() Option {
 	return func(a *Analyzer) {
@@ -69,6 +84,167 @@ func WithInterprocedural() Option {
 	}
 }
 
+// WithInlineTrivialFuncs enables inter-procedural analysis and splices flows of functions with up to maxStatements
+// statements (e.g. getters) directly into their callers, linking selected argument fields to the call-site variables.
+func WithInlineTrivialFuncs(maxStatements int) Option {
+	return func(a *Analyzer) {
+		a.interprocedural = true
+		a.inlineMaxStatements = maxStatements
+	}
+}
+
+// WithErrorTracking enables inter-procedural analysis and tags XFER edges between error identifiers as error flows,
+// following wrapping calls (fmt.Errorf with %w, errors.Wrap, errors.Join) so that error origins can be traced.
+func WithErrorTracking() Option {
+	return func(a *Analyzer) {
+		a.interprocedural = true
+		a.errorTracking = true
+	}
+}
+
+// WithRetainTrees retains parsed trees of analyzed files for custom tree-sitter queries; trees are released on Close
+func WithRetainTrees() Option {
+	return func(a *Analyzer) {
+		a.trees = treesitter.NewTrees()
+	}
+}
+
+// WithMaxFileSize sets maximum analyzed file size in bytes (graph.DefaultMaxFileSize by default, negative disables the limit)
+func WithMaxFileSize(size int64) Option {
+	return func(a *Analyzer) {
+		a.limits.MaxFileSize = size
+	}
+}
+
+// WithParseTimeout sets the maximum parse time of a file (graph.DefaultParseTimeout by default, negative disables the
+// deadline), files exceeding it are reported in linage.PackageModel.Warnings and not analyzed
+func WithParseTimeout(timeout time.Duration) Option {
+	return func(a *Analyzer) {
+		a.limits.ParseTimeout = timeout
+	}
+}
+
+// WithMaxNodes sets the maximum number of nodes visited while analyzing a file (graph.DefaultMaxNodes by default,
+// negative disables the limit), flows of later nodes are omitted and the file is reported in
+// linage.PackageModel.Warnings
+func WithMaxNodes(count int) Option {
+	return func(a *Analyzer) {
+		a.limits.MaxNodes = count
+	}
+}
+
+// WithFollowSymlinks analyzes symlinked source files, symlinks are skipped by default; symlinked directories are not
+// descended by package walks
+func WithFollowSymlinks() Option {
+	return func(a *Analyzer) {
+		a.limits.FollowSymlinks = true
+	}
+}
+
+// WithSourceProvider reads analyzed sources with a provider (e.g. repository.NewGitObjectProvider of a revision) instead
+// of the local file system; model paths stay file URLs of the provider paths, dependency roots are still read locally
+func WithSourceProvider(sources graph.SourceProvider) Option {
+	return func(a *Analyzer) {
+		a.limits.Sources = sources
+	}
+}
+
+// WithDiagnostics collects diagnostics of the analysis (e.g. files analyzed partially) into a collector, see
+// graph.NewDiagnostics
+func WithDiagnostics(diagnostics *graph.Diagnostics) Option {
+	return func(a *Analyzer) {
+		a.limits.Diagnostics = diagnostics
+	}
+}
+
+// WithClassifier labels identifiers and struct fields (e.g. graph.NewRuleClassifier()), labels propagate along XFER edges;
+// combine with WithInterprocedural to detect labeled data reaching logging and external calls (see NewSensitivityReport)
+func WithClassifier(classifier graph.Classifier) Option {
+	return func(a *Analyzer) {
+		a.classifier = classifier
+	}
+}
+
+// WithRedactor replaces secrets in literals, attributes and annotations of finished models (e.g. graph.NewRedactor()),
+// data points and exported graphs built from the models carry placeholders only
+func WithRedactor(redactor *graph.Redactor) Option {
+	return func(a *Analyzer) {
+		a.redactor = redactor
+	}
+}
+
+// WithTrace streams structured JSON events of the analysis (see TraceEvent) to w: nodes entered by the walk, scopes
+// created, identifiers resolved, edges emitted with their provenance and summaries applied, each with an increasing
+// sequence number; use ReadTrace to load a trace, e.g. to show all events touching an identifier
+func WithTrace(w io.Writer) Option {
+	return func(a *Analyzer) {
+		a.trace = newTracer(w)
+	}
+}
+
+// WithMaxLiteralLength sets the maximum number of characters of literal values recorded on edges
+// (DefaultMaxLiteralLength by default, negative disables truncation)
+func WithMaxLiteralLength(length int) Option {
+	return func(a *Analyzer) {
+		a.maxLiteralLength = length
+	}
+}
+
+// WithMaxClosureDepth limits the number of XFER hops a transitive summary edge spans (unlimited by default),
+// models with omitted summary edges are marked as truncated
+func WithMaxClosureDepth(depth int) Option {
+	return func(a *Analyzer) {
+		a.maxClosureDepth = depth
+	}
+}
+
+// WithMaxSummaryEdges limits the number of transitive summary edges added per package (unlimited by default),
+// models with omitted summary edges are marked as truncated
+func WithMaxSummaryEdges(count int) Option {
+	return func(a *Analyzer) {
+		a.maxSummaryEdges = count
+	}
+}
+
+// WithConfidence sets the confidence (0-1) of edges emitted by a heuristic (e.g. linage.ProvenanceVirtual), overriding
+// linage.DefaultConfidence
+func WithConfidence(provenance string, confidence float64) Option {
+	return func(a *Analyzer) {
+		if a.confidence == nil {
+			a.confidence = map[string]float64{}
+		}
+		a.confidence[provenance] = confidence
+	}
+}
+
+// WithDependencyRoots loads struct field declarations of imported Go packages found under roots (vendor directories,
+// GOMODCACHE or directories laid out by import path) so that selectors on dependency types (e.g. req.Bucket of
+// *s3.PutObjectInput) resolve to typed field identifiers with refs namespaced by the dependency import path.
+// Dependency packages are loaded on first use and only their type specs are parsed, no identifiers or flows are
+// emitted for dependency code.
+func WithDependencyRoots(roots ...string) Option {
+	return func(a *Analyzer) {
+		a.dependencyRoots = append(a.dependencyRoots, roots...)
+	}
+}
+
+// WithMaxDependencyPackages limits the number of dependency packages loaded (DefaultMaxDependencyPackages by default,
+// negative disables loading)
+func WithMaxDependencyPackages(count int) Option {
+	return func(a *Analyzer) {
+		a.maxDependencyPackages = count
+	}
+}
+
+// WithMaxDevirtualTargets limits the number of implementations a Go interface method call fans out to
+// (DefaultMaxDevirtualTargets by default, negative disables devirtualization); calls of interfaces with more
+// implementations keep the interface method as their only target
+func WithMaxDevirtualTargets(count int) Option {
+	return func(a *Analyzer) {
+		a.maxDevirtualTargets = count
+	}
+}
+
 func GolangFiles(info os.FileInfo) bool {
 	if info.IsDir() {
 		if info.Name() == "vendor" {
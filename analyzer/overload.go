@@ -0,0 +1,247 @@
+package analyzer
+
+import (
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/viant/linager/analyzer/linage"
+	"strings"
+)
+
+// javaTypeDeclarations lists Java declarations owning methods and constructors
+var javaTypeDeclarations = map[string]bool{
+	"class_declaration":     true,
+	"interface_declaration": true,
+	"enum_declaration":      true,
+	"record_declaration":    true,
+}
+
+// overload holds a Java method or constructor identifier with its parameter types
+type overload struct {
+	ident  *linage.Identifier
+	owner  string
+	params []string
+}
+
+// javaOwner returns the dotted name of Java types enclosing a node, e.g. Outer.Inner, empty outside of a type
+// declaration; anonymous class bodies are not named and own no overloads
+func javaOwner(n *sitter.Node, src []byte) string {
+	var names []string
+	for cur := n.Parent(); cur != nil; cur = cur.Parent() {
+		if cur.Type() == "object_creation_expression" {
+			return ""
+		}
+		if !javaTypeDeclarations[cur.Type()] {
+			continue
+		}
+		if nameNode := cur.ChildByFieldName("name"); nameNode != nil {
+			names = append([]string{string(src[nameNode.StartByte():nameNode.EndByte()])}, names...)
+		}
+	}
+	return strings.Join(names, ".")
+}
+
+// javaParamTypes returns canonical parameter types of a Java method or constructor, varargs end with ...
+func javaParamTypes(n *sitter.Node, src []byte) []string {
+	result := []string{}
+	for _, param := range namedChildren(n.ChildByFieldName("parameters")) {
+		switch param.Type() {
+		case "formal_parameter":
+			if typeNode := param.ChildByFieldName("type"); typeNode != nil {
+				result = append(result, javaTypeText(typeNode, src))
+			}
+		case "spread_parameter":
+			for _, child := range namedChildren(param) {
+				if child.Type() != "modifiers" && child.Type() != "variable_declarator" {
+					result = append(result, javaTypeText(child, src)+"...")
+					break
+				}
+			}
+		}
+	}
+	return result
+}
+
+// javaTypeText returns type source without whitespace variance, e.g. Map< String, User > -> Map<String,User>
+func javaTypeText(n *sitter.Node, src []byte) string {
+	fields := strings.Fields(string(src[n.StartByte():n.EndByte()]))
+	builder := strings.Builder{}
+	for i, field := range fields {
+		if i > 0 && !strings.ContainsAny(field[:1], "<>[],.") && !strings.ContainsAny(fields[i-1][len(fields[i-1])-1:], "<[,.") {
+			builder.WriteString(" ")
+		}
+		builder.WriteString(field)
+	}
+	return builder.String()
+}
+
+// javaSymbol returns the symbol of a Java method or constructor qualified by its owner and parameter types,
+// e.g. UserRepository.save(User,boolean), so that overloads get distinct identifiers
+func javaSymbol(n *sitter.Node, name string, src []byte) (string, string, []string) {
+	if n.Type() != "method_declaration" && n.Type() != "constructor_declaration" {
+		return "", "", nil
+	}
+	owner := javaOwner(n, src)
+	if owner == "" {
+		return "", "", nil
+	}
+	params := javaParamTypes(n, src)
+	return owner + "." + name + "(" + strings.Join(params, ",") + ")", owner, params
+}
+
+// declareJavaOverloads registers Java methods and constructors of a file before the walk, so that invocations
+// preceding a declaration resolve to it
+func (a *Analyzer) declareJavaOverloads(n *sitter.Node, src []byte, fileScope *linage.Scope, model *linage.PackageModel) {
+	switch n.Type() {
+	case "method_declaration", "constructor_declaration":
+		if nameNode := n.ChildByFieldName("name"); nameNode != nil && n.ChildByFieldName("receiver") == nil {
+			a.functionIdent(n, nameNode, src, fileScope, model)
+		}
+	case "object_creation_expression":
+		return // anonymous class methods are not addressable
+	}
+	for _, child := range namedChildren(n) {
+		a.declareJavaOverloads(child, src, fileScope, model)
+	}
+}
+
+// handleInvocation records CALL edges of a Java method invocation or instance creation to overloads matching the
+// call: by owner when the invocation is unqualified (or on this), by arity, and by argument types when all of them are
+// known; calls matching several overloads are attributed to each of them
+func (a *Analyzer) handleInvocation(n *sitter.Node, src []byte, scope *linage.Scope, model *linage.PackageModel) {
+	var nameNode *sitter.Node
+	var owner string
+	object := n.ChildByFieldName("object")
+	if n.Type() == "object_creation_expression" {
+		if nameNode = n.ChildByFieldName("type"); nameNode != nil {
+			owner = javaSimpleType(string(src[nameNode.StartByte():nameNode.EndByte()]))
+		}
+	} else {
+		nameNode = n.ChildByFieldName("name")
+		if object == nil || object.Type() == "this" {
+			owner = javaOwner(n, src)
+		}
+	}
+	args := n.ChildByFieldName("arguments")
+	if nameNode != nil {
+		name := javaSimpleType(string(src[nameNode.StartByte():nameNode.EndByte()]))
+		var argTypes []string
+		for _, arg := range namedChildren(args) {
+			argTypes = append(argTypes, a.javaArgType(arg, src, scope))
+		}
+		targets := a.resolveOverloads(name, owner, argTypes)
+		start := len(model.DataFlows)
+		for _, target := range targets {
+			ref := strings.TrimPrefix(target.ident.ID, target.ident.File+".")
+			a.addCallEdges([]*linage.Identifier{target.ident}, linage.CallMethod, ref, nameNode, src, scope, scope.ID, model)
+			a.markRecursion([]*linage.Identifier{target.ident}, scope)
+		}
+		if len(targets) > 1 {
+			for _, edge := range model.DataFlows[start:] {
+				if edge.Kind == linage.Call {
+					a.markHeuristic(edge, linage.ProvenanceOverload)
+				}
+			}
+		}
+		if args != nil {
+			for _, id := range a.extractIdentifiers(args, src, scope, model) {
+				model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: id, Dst: id, Kind: linage.Read, Scope: scope.ID})
+			}
+		}
+	}
+	for _, child := range []*sitter.Node{object, args, n.ChildByFieldName("body")} {
+		if child != nil {
+			a.walk(child, src, scope, model)
+		}
+	}
+}
+
+// resolveOverloads returns overloads named name (of owner when set) accepting the arguments, argument types filter
+// overloads of the same arity only when all of them are known
+func (a *Analyzer) resolveOverloads(name, owner string, argTypes []string) []*overload {
+	var byArity []*overload
+	for _, candidate := range a.overloads[name] {
+		if owner != "" && candidate.owner != owner && !strings.HasSuffix(candidate.owner, "."+owner) {
+			continue
+		}
+		if acceptsArity(candidate.params, len(argTypes)) {
+			byArity = append(byArity, candidate)
+		}
+	}
+	if len(byArity) < 2 {
+		return byArity
+	}
+	for _, argType := range argTypes {
+		if argType == "" {
+			return byArity
+		}
+	}
+	var byType []*overload
+	for _, candidate := range byArity {
+		if acceptsTypes(candidate.params, argTypes) {
+			byType = append(byType, candidate)
+		}
+	}
+	if len(byType) == 0 {
+		return byArity
+	}
+	return byType
+}
+
+// acceptsArity reports whether parameters accept count arguments, varargs accept any number of trailing arguments
+func acceptsArity(params []string, count int) bool {
+	if n := len(params); n > 0 && strings.HasSuffix(params[n-1], "...") {
+		return count >= n-1
+	}
+	return len(params) == count
+}
+
+// acceptsTypes reports whether parameter types match argument types by simple name, boxed and primitive types match
+func acceptsTypes(params []string, argTypes []string) bool {
+	for i, argType := range argTypes {
+		param := params[len(params)-1]
+		if i < len(params) {
+			param = params[i]
+		}
+		param = javaSimpleType(strings.TrimSuffix(param, "..."))
+		if param != argType && javaBoxed[param] != argType && javaBoxed[argType] != param && param != "Object" {
+			return false
+		}
+	}
+	return true
+}
+
+// javaBoxed maps Java primitive types to their boxed types
+var javaBoxed = map[string]string{"boolean": "Boolean", "byte": "Byte", "char": "Character", "short": "Short",
+	"int": "Integer", "long": "Long", "float": "Float", "double": "Double"}
+
+// javaArgType returns the simple type of a literal, an object creation or an identifier with a known type, empty otherwise
+func (a *Analyzer) javaArgType(arg *sitter.Node, src []byte, scope *linage.Scope) string {
+	switch arg.Type() {
+	case "string_literal":
+		return "String"
+	case "true", "false":
+		return "boolean"
+	case "decimal_integer_literal", "hex_integer_literal":
+		return "int"
+	case "decimal_floating_point_literal":
+		return "double"
+	case "character_literal":
+		return "char"
+	case "object_creation_expression":
+		if typeNode := arg.ChildByFieldName("type"); typeNode != nil {
+			return javaSimpleType(string(src[typeNode.StartByte():typeNode.EndByte()]))
+		}
+	case "identifier":
+		if id := scope.Find(string(src[arg.StartByte():arg.EndByte()])); id != nil && id.Kind != "func" && id.Kind != "method" {
+			return javaSimpleType(id.Type)
+		}
+	}
+	return ""
+}
+
+// javaSimpleType returns a type name without package qualifier and type arguments, e.g. java.util.List<User> -> List
+func javaSimpleType(typeName string) string {
+	if index := strings.Index(typeName, "<"); index != -1 {
+		typeName = typeName[:index]
+	}
+	return strings.TrimSpace(typeName[strings.LastIndex(typeName, ".")+1:])
+}
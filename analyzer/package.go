@@ -4,9 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"github.com/viant/afs/storage"
 	"github.com/viant/afs/url"
 	"github.com/viant/linager/analyzer/linage"
+	"github.com/viant/linager/inspector/graph"
+	"github.com/viant/linager/inspector/treesitter"
 	"io"
 	"os"
 	"path/filepath"
@@ -30,8 +31,8 @@ func (a *Analyzer) AnalyzeDir(ctx context.Context, root string) ([]*linage.Packa
 			}
 			return true, nil
 		}
-		if err := a.fs.Walk(ctx, root, visitor); err != nil {
-			return nil, err
+		if err := walkTree(ctx, a.fs, a.limits.Sources, root, visitor); err != nil {
+			return nil, fmt.Errorf("failed to walk %s: %w", root, graph.NotFoundError("directory", root, err))
 		}
 		if len(roots) == 0 {
 			roots[root] = true
@@ -52,52 +53,52 @@ func (a *Analyzer) AnalyzeDir(ctx context.Context, root string) ([]*linage.Packa
 
 // analyzePackages walks a directory tree under root and analyses each package.
 func (a *Analyzer) analyzePackages(ctx context.Context, root string) ([]*linage.PackageModel, error) {
-	assets := map[string][]string{}
-	var visitor storage.OnVisit = func(ctx context.Context, baseURL, parent string, info os.FileInfo, reader io.Reader) (bool, error) {
-		if !a.match(info) {
-			return false, nil
-		}
-		if info.IsDir() {
-			return true, nil
-		}
-		pkg := url.Join(baseURL, parent)
-		assets[pkg] = append(assets[pkg], info.Name())
-		return true, nil
-	}
-	if err := a.fs.Walk(ctx, root, visitor); err != nil {
+	sources, err := walkSources(ctx, a.fs, root, a.match, a.limits)
+	if err != nil {
 		return nil, err
 	}
 	var models []*linage.PackageModel
-	for pkgURL, files := range assets {
-		m, err := a.analyzePackage(ctx, pkgURL, files)
+	for pkgURL, files := range sources.files {
+		m, err := a.analyzePackage(ctx, pkgURL, fileNames(files))
 		if err != nil {
 			return nil, err
 		}
+		m.Skipped = append(sources.skipped[pkgURL], m.Skipped...)
 		models = append(models, m)
 	}
+	for pkgURL, files := range sources.skipped {
+		if _, ok := sources.files[pkgURL]; !ok {
+			models = append(models, &linage.PackageModel{Path: pkgURL, Language: a.Language, Idents: map[string]*linage.Identifier{}, Skipped: files})
+		}
+	}
 	return models, nil
 }
 
 func (a *Analyzer) analyzePackage(ctx context.Context, baseURL string, files []string) (*linage.PackageModel, error) {
-	model := &linage.PackageModel{Path: baseURL, Language: a.Language, Files: files, Idents: map[string]*linage.Identifier{}}
-
-	pkgScope := &linage.Scope{ID: baseURL, Kind: "package", Symbols: map[string]*linage.Identifier{}}
-	model.Scopes = append(model.Scopes, pkgScope)
+	model, pkgScope := a.newModel(baseURL)
 
 	for _, file := range files {
 		URL := url.Join(baseURL, file)
-		code, err := a.fs.DownloadWithURL(ctx, URL)
+		code, skippedFile, err := loadSource(ctx, a.fs, a.limits.Sources, URL)
 		if err != nil {
 			return nil, err
 		}
-		a.AnalyzeSourceCode(baseURL, code, URL, pkgScope, model)
+		if skippedFile != nil {
+			model.Skipped = append(model.Skipped, skippedFile)
+			continue
+		}
+		if err = a.AnalyzeSourceCode(baseURL, code, URL, pkgScope, model); err != nil {
+			return nil, err
+		}
 	}
 
-	a.computeTransitiveClosure(model)
+	a.finishModel(model)
 	return model, nil
 }
 
 func (a *Analyzer) AnalyzeSourceCode(dir string, code []byte, filePath string, pkgScope *linage.Scope, model *linage.PackageModel) error {
+	// models created by callers initialize plugins with their first file
+	a.initPlugins(model)
 	// reset import aliases for this file
 	a.importAliases = map[string]string{}
 	// record package path and files
@@ -106,16 +107,56 @@ func (a *Analyzer) AnalyzeSourceCode(dir string, code []byte, filePath string, p
 	}
 	// track this source file
 	model.Files = append(model.Files, filepath.Base(filePath))
-	// parse AST
-	tree := a.parser.Parse(nil, code)
-	if tree == nil {
-		return errors.New("failed to parse code")
+	if a.trace != nil {
+		a.trace.file = filePath
+	}
+	// parse AST, a file exceeding the parse deadline is reported and not analyzed
+	tree, err := a.parse(code)
+	if errors.Is(err, context.DeadlineExceeded) {
+		warning := treesitter.TimeoutWarning(filePath, a.limits.ParseDeadline())
+		model.Warnings = append(model.Warnings, warning)
+		a.limits.Diagnostics.AddParseWarning(warning)
+		return nil
+	}
+	if err != nil || tree == nil {
+		return &graph.ErrParse{Path: filePath, Cause: err}
+	}
+	a.budget = treesitter.NewBudget(a.limits.NodeLimit())
+	if a.trees != nil {
+		a.trees.Retain(filePath, tree, code, a.language)
 	}
 	rootNode := tree.RootNode()
-	fileScope := &linage.Scope{ID: fmt.Sprintf("%s:%s", dir, filepath.Base(filePath)), Kind: "file", Parent: pkgScope, Symbols: map[string]*linage.Identifier{}, Start: int(rootNode.StartByte()), End: int(rootNode.EndByte())}
+	fileScope := (&linage.Scope{ID: fmt.Sprintf("%s:%s", dir, filepath.Base(filePath)), Kind: "file", Parent: pkgScope, Symbols: map[string]*linage.Identifier{}}).SetRange(rootNode)
 	pkgScope.Symbols[filepath.Base(filePath)] = &linage.Identifier{ID: fileScope.ID, Kind: "file", Name: filepath.Base(filePath), Package: dir, File: filePath, StartByte: rootNode.StartByte(), Node: rootNode}
 	model.Scopes = append(model.Scopes, fileScope)
+	a.declarePackageSymbols(rootNode, code, fileScope, model)
+	if rootNode.Type() == "program" {
+		a.declareJavaOverloads(rootNode, code, fileScope, model)
+	}
+	a.traceStep(model, "declarations")
 	a.walk(rootNode, code, fileScope, model)
+	if warning := a.budget.Warning(filePath); warning != nil {
+		model.Warnings = append(model.Warnings, warning)
+		a.limits.Diagnostics.AddParseWarning(warning)
+	} else {
+		a.linkFuncRefs(rootNode, code, fileScope, model)
+	}
+	for _, plugin := range a.plugins {
+		if finisher, ok := plugin.(FileFinisher); ok {
+			finisher.AfterFile(fileScope, model)
+			a.traceStep(model, traceName(plugin))
+		}
+	}
+	if a.inlineMaxStatements > 0 {
+		a.inlineCallSites(model)
+	}
+	if a.errorTracking {
+		a.tagErrorFlows(model)
+		a.traceStep(model, "errors")
+	}
+	if a.classifier != nil {
+		a.classifyIdents(model)
+	}
 	return nil
 }
 
@@ -127,13 +168,15 @@ func (a *Analyzer) AnalyzeAll(ctx context.Context, root string) (*linage.Package
 		return nil, err
 	}
 	merged := linage.Merge(models...)
+	// calls through struct fields may be assigned functions of other packages
+	a.linkFieldCalls(merged, true)
 	// set language for the merged model
 	merged.Language = a.Language
 	// export intermediate representation graph if configured
 	if a.graphExporter != nil {
-		graph := buildIRGraph(a, merged)
-		if err := a.graphExporter.Export(graph); err != nil {
-			return nil, err
+		irGraph := buildIRGraph(a, merged)
+		if err := a.graphExporter.Export(irGraph); err != nil {
+			return nil, fmt.Errorf("failed to export graph: %w", err)
 		}
 	}
 	return merged, nil
@@ -0,0 +1,164 @@
+package analyzer
+
+import (
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/viant/linager/analyzer/linage"
+	"sort"
+)
+
+// AnalyzerPlugin defines extension hooks for analyzer passes.
+// BeforeWalk is called for each AST node before default processing.
+// AfterResolveIdent is called after an identifier is resolved.
+// Plugins may implement optional lifecycle interfaces: PluginInitializer, AfterWalker, FileFinisher, ModelFinisher,
+// PrioritizedPlugin and NodeTypeFilter.
+type AnalyzerPlugin interface {
+	BeforeWalk(n *sitter.Node, src []byte, scope *linage.Scope, model *linage.PackageModel)
+	AfterResolveIdent(n *sitter.Node, id *linage.Identifier, scope *linage.Scope, model *linage.PackageModel)
+}
+
+// PluginOptions exposes analyzer settings and services to plugins
+type PluginOptions struct {
+	// Language is the analyzer language tag, e.g. go or java
+	Language string
+	// Interprocedural reports whether inter-procedural analysis is enabled
+	Interprocedural bool
+	// Resolve resolves an identifier or selector node to its identifier the way the analyzer does
+	Resolve func(n *sitter.Node, src []byte, scope *linage.Scope, model *linage.PackageModel) *linage.Identifier
+}
+
+// PluginInitializer is called with every package model before its files are analyzed
+type PluginInitializer interface {
+	Init(model *linage.PackageModel, options *PluginOptions)
+}
+
+// AfterWalker is called for each AST node after default processing, including its subtree
+type AfterWalker interface {
+	AfterWalk(n *sitter.Node, src []byte, scope *linage.Scope, model *linage.PackageModel)
+}
+
+// FileFinisher is called after a file of a package model is walked
+type FileFinisher interface {
+	AfterFile(fileScope *linage.Scope, model *linage.PackageModel)
+}
+
+// ModelFinisher is called once all files of a package model are analyzed, before transitive edges are computed,
+// so that aggregated edges it emits take part in summaries
+type ModelFinisher interface {
+	Finish(model *linage.PackageModel)
+}
+
+// PrioritizedPlugin orders plugin dispatch: plugins with lower priority run first, plugins without priority have 0
+type PrioritizedPlugin interface {
+	Priority() int
+}
+
+// NodeTypeFilter limits BeforeWalk and AfterWalk dispatch to listed tree-sitter node types, e.g. call_expression;
+// an empty list dispatches every node
+type NodeTypeFilter interface {
+	NodeTypes() []string
+}
+
+// nodePlugins holds plugins dispatched for a node type
+type nodePlugins struct {
+	before []AnalyzerPlugin
+	after  []AfterWalker
+}
+
+// sortPlugins orders registered plugins by priority, keeping registration order of equal priorities
+func (a *Analyzer) sortPlugins() {
+	sort.SliceStable(a.plugins, func(i, j int) bool {
+		return pluginPriority(a.plugins[i]) < pluginPriority(a.plugins[j])
+	})
+	a.dispatch = map[string]*nodePlugins{}
+}
+
+// pluginPriority returns a plugin priority, 0 for plugins without PrioritizedPlugin
+func pluginPriority(plugin AnalyzerPlugin) int {
+	if prioritized, ok := plugin.(PrioritizedPlugin); ok {
+		return prioritized.Priority()
+	}
+	return 0
+}
+
+// nodePlugins returns plugins dispatched for a node type, plugins without node types get every node
+func (a *Analyzer) nodePlugins(nodeType string) *nodePlugins {
+	if ret, ok := a.dispatch[nodeType]; ok {
+		return ret
+	}
+	ret := &nodePlugins{}
+	for _, plugin := range a.plugins {
+		if filter, ok := plugin.(NodeTypeFilter); ok && len(filter.NodeTypes()) > 0 && !containsString(filter.NodeTypes(), nodeType) {
+			continue
+		}
+		ret.before = append(ret.before, plugin)
+		if after, ok := plugin.(AfterWalker); ok {
+			ret.after = append(ret.after, after)
+		}
+	}
+	a.dispatch[nodeType] = ret
+	return ret
+}
+
+// pluginOptions returns analyzer settings exposed to plugins
+func (a *Analyzer) pluginOptions() *PluginOptions {
+	return &PluginOptions{
+		Language:        a.Language,
+		Interprocedural: a.interprocedural,
+		Resolve: func(n *sitter.Node, src []byte, scope *linage.Scope, model *linage.PackageModel) *linage.Identifier {
+			return a.resolveIdent(n, nil, src, scope, model)
+		},
+	}
+}
+
+// newModel creates a package model with its package scope and initializes plugins with it
+func (a *Analyzer) newModel(path string) (*linage.PackageModel, *linage.Scope) {
+	model := &linage.PackageModel{Path: path, Language: a.Language, Idents: map[string]*linage.Identifier{}}
+	pkgScope := &linage.Scope{ID: path, Kind: "package", Symbols: map[string]*linage.Identifier{}}
+	model.Scopes = append(model.Scopes, pkgScope)
+	a.initPlugins(model)
+	return model, pkgScope
+}
+
+// initPlugins initializes plugins with a package model once, the model records its initialization so that the
+// analyzer keeps no reference to models analyzed with AnalyzeSourceCode
+func (a *Analyzer) initPlugins(model *linage.PackageModel) {
+	if !model.MarkInitialized() {
+		return
+	}
+	options := a.pluginOptions()
+	for _, plugin := range a.plugins {
+		if initializer, ok := plugin.(PluginInitializer); ok {
+			initializer.Init(model, options)
+		}
+	}
+}
+
+// finishModel lets plugins finish a package model, computes its transitive edges and redacts secrets
+func (a *Analyzer) finishModel(model *linage.PackageModel) {
+	for _, plugin := range a.plugins {
+		if finisher, ok := plugin.(ModelFinisher); ok {
+			finisher.Finish(model)
+			a.traceStep(model, traceName(plugin))
+		}
+	}
+	a.linkFieldCalls(model, false)
+	a.computeTransitiveClosure(model)
+	a.traceStep(model, "closure")
+	if a.trace != nil {
+		a.trace.release(model)
+	}
+	if a.redactor != nil {
+		model.Redact(a.redactor)
+	}
+}
+
+// AnalyzeModel analyzes a single source file and returns its package model with transitive edges, plugins run
+// their whole lifecycle over the model
+func (a *Analyzer) AnalyzeModel(source []byte, project, path string) (*linage.PackageModel, error) {
+	model, pkgScope := a.newModel(project)
+	if err := a.AnalyzeSourceCode(project, source, path, pkgScope, model); err != nil {
+		return nil, err
+	}
+	a.finishModel(model)
+	return model, nil
+}
@@ -0,0 +1,27 @@
+// Package plugintest runs analyzer plugins over source strings, e.g. in plugin unit tests
+package plugintest
+
+import (
+	"github.com/smacker/go-tree-sitter/golang"
+	"github.com/viant/linager/analyzer"
+	"github.com/viant/linager/analyzer/linage"
+)
+
+// Project is the package path identifiers of analyzed sources are qualified with
+const Project = "plugintest"
+
+// Run analyzes a Go source with a plugin and returns the resulting package model with transitive edges; options
+// override the language, e.g. analyzer.WithLanguage(java.GetLanguage()) with a .java path
+func Run(plugin analyzer.AnalyzerPlugin, source string, options ...analyzer.Option) (*linage.PackageModel, error) {
+	return RunFile(plugin, source, "main.go", options...)
+}
+
+// RunFile analyzes a source as a file at path with a plugin and returns the resulting package model
+func RunFile(plugin analyzer.AnalyzerPlugin, source, path string, options ...analyzer.Option) (*linage.PackageModel, error) {
+	options = append([]analyzer.Option{
+		analyzer.WithLanguage(golang.GetLanguage()),
+		analyzer.WithLanguageName("go"),
+		analyzer.WithPlugin(plugin),
+	}, options...)
+	return analyzer.NewAnalyzer(options...).AnalyzeModel([]byte(source), Project, path)
+}
@@ -0,0 +1,173 @@
+package plugintest_test
+
+import (
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/golang"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/linager/analyzer"
+	"github.com/viant/linager/analyzer/linage"
+	"github.com/viant/linager/analyzer/plugintest"
+	"strings"
+	"testing"
+)
+
+const source = `package main
+
+import "sync"
+
+func run(values []int) int {
+	total := 0
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go sum(values, &total)
+	defer wg.Done()
+	wg.Wait()
+	return total
+}
+`
+
+// recorder records lifecycle events of a plugin into a shared journal
+type recorder struct {
+	name      string
+	priority  int
+	nodeTypes []string
+	journal   *[]string
+	calls     int
+}
+
+func (r *recorder) Init(model *linage.PackageModel, options *analyzer.PluginOptions) {
+	*r.journal = append(*r.journal, r.name+":init:"+options.Language)
+}
+
+func (r *recorder) Priority() int { return r.priority }
+
+func (r *recorder) NodeTypes() []string { return r.nodeTypes }
+
+func (r *recorder) BeforeWalk(n *sitter.Node, src []byte, scope *linage.Scope, model *linage.PackageModel) {
+	if n.Type() == "function_declaration" {
+		*r.journal = append(*r.journal, r.name+":before")
+	}
+	r.calls++
+}
+
+func (r *recorder) AfterWalk(n *sitter.Node, src []byte, scope *linage.Scope, model *linage.PackageModel) {
+	if n.Type() == "function_declaration" {
+		*r.journal = append(*r.journal, r.name+":after")
+	}
+}
+
+func (r *recorder) AfterResolveIdent(n *sitter.Node, id *linage.Identifier, scope *linage.Scope, model *linage.PackageModel) {
+}
+
+func (r *recorder) AfterFile(fileScope *linage.Scope, model *linage.PackageModel) {
+	*r.journal = append(*r.journal, r.name+":file:"+fileScope.ID)
+}
+
+func (r *recorder) Finish(model *linage.PackageModel) {
+	*r.journal = append(*r.journal, r.name+":finish")
+	sink := &linage.Identifier{ID: r.name + "::sink", Name: r.name}
+	model.Idents[sink.ID] = sink
+	model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: sink, Dst: sink, Kind: linage.Write})
+}
+
+func TestRun(t *testing.T) {
+	var journal []string
+	late := &recorder{name: "late", priority: 10, nodeTypes: []string{"function_declaration"}, journal: &journal}
+	early := &recorder{name: "early", priority: -1, journal: &journal}
+	model, err := plugintest.Run(late, source, analyzer.WithPlugin(early))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []string{
+		"early:init:go", "late:init:go",
+		"early:before", "late:before",
+		"early:after", "late:after",
+		"early:file:plugintest:main.go", "late:file:plugintest:main.go",
+		"early:finish", "late:finish",
+	}, journal)
+	assert.Equal(t, 1, late.calls, "filtered plugin is dispatched its node types only")
+	assert.Greater(t, early.calls, 1)
+	assert.Contains(t, model.Idents, "late::sink")
+}
+
+// TestInit_PerModel tests that plugins are initialized once with every model analyzed file by file
+func TestInit_PerModel(t *testing.T) {
+	var journal []string
+	anAnalyzer := analyzer.NewAnalyzer(analyzer.WithLanguage(golang.GetLanguage()), analyzer.WithPlugin(&recorder{name: "rec", journal: &journal}))
+	first, second := linage.NewPackageModel(), linage.NewPackageModel()
+	for _, model := range []*linage.PackageModel{first, first, second} {
+		if !assert.NoError(t, anAnalyzer.AnalyzeSourceCode("app", []byte(source), "main.go", linage.NewScope(), model)) {
+			return
+		}
+	}
+	var inits int
+	for _, event := range journal {
+		if strings.Contains(event, ":init:") {
+			inits++
+		}
+	}
+	assert.Equal(t, 2, inits)
+	assert.False(t, first.MarkInitialized(), "models record their initialization")
+}
+
+func TestWaitGroupPlugin(t *testing.T) {
+	// wait groups are tracked by the plugin every analyzer registers
+	var journal []string
+	model, err := plugintest.Run(&recorder{name: "noop", journal: &journal}, source)
+	if !assert.NoError(t, err) {
+		return
+	}
+	var kinds []linage.AccessKind
+	channels := map[string]bool{}
+	for _, edge := range model.DataFlows {
+		if strings.HasSuffix(edge.Src.ID, "::wg") {
+			kinds = append(kinds, edge.Kind)
+			channels[edge.Src.ID] = true
+		}
+	}
+	assert.Equal(t, []linage.AccessKind{linage.Write, linage.Read}, kinds)
+	assert.Len(t, channels, 1, "Done and Wait share the wait group channel")
+}
+
+func TestSQLPlugin(t *testing.T) {
+	model, err := plugintest.Run(analyzer.NewSQLPlugin(), `package repo
+
+const UserNameColumn = "user_name"
+
+type User struct {
+	Name  string `+"`db:\"user_name\" json:\"name\"`"+`
+	Email string `+"`db:\"email\"`"+`
+}
+
+func find(db Querier) {
+	db.Query("SELECT id, user_name FROM users WHERE status = 'email'")
+}
+`)
+	if !assert.NoError(t, err) {
+		return
+	}
+	var chain, reads []string
+	for _, edge := range model.DataFlows {
+		if edge.Kind == linage.Xfer && edge.Src.Kind == linage.SQLColumnKind {
+			reads = append(reads, edge.Src.Name+"->"+edge.Dst.Name)
+			assert.Equal(t, linage.SQLQueryKind, edge.Dst.Kind)
+			assert.Equal(t, "users", edge.Attributes[linage.SQLTableAttribute])
+			assert.Equal(t, 11, edge.Attributes[linage.LineAttribute])
+		}
+		if edge.Kind != linage.Xfer || edge.Attributes[linage.TagKeyAttribute] != "db" {
+			continue
+		}
+		chain = append(chain, edge.Src.Name+"->"+edge.Dst.Name)
+		switch edge.Dst.Kind {
+		case linage.SQLColumnKind:
+			assert.Equal(t, "users", edge.Attributes[linage.SQLTableAttribute])
+			assert.Contains(t, []int{6, 7}, edge.Attributes[linage.LineAttribute])
+		default:
+			assert.Equal(t, "user_name", edge.Literal)
+			assert.Equal(t, 6, edge.Attributes[linage.LineAttribute])
+		}
+		assert.NotEmpty(t, edge.Attributes[linage.FileAttribute])
+	}
+	assert.Equal(t, []string{"UserNameColumn->Name", "Name->users.user_name", "Email->users.email"}, chain)
+	assert.Equal(t, []string{"users.id->db.Query", "users.user_name->db.Query", "users.status->db.Query"}, reads, "string literals are not columns")
+}
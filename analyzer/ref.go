@@ -0,0 +1,63 @@
+package analyzer
+
+import (
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/viant/linager/analyzer/linage"
+	"github.com/viant/linager/inspector/graph"
+	"strings"
+)
+
+// functionRef returns the canonical reference of a Go or Java function, method or constructor declaration
+func functionRef(n *sitter.Node, name string, src []byte, model *linage.PackageModel) graph.Ref {
+	if _, owner, params := javaSymbol(n, name, src); owner != "" {
+		return graph.NewFunctionRef(model.Path, owner, name, params)
+	}
+	return graph.NewFunctionRef(model.Path, receiverName(n, src), name, goParamTypes(n, src))
+}
+
+// goParamTypes returns canonical parameter types of a Go function declaration, one per parameter name; variadic
+// ...T is rendered as T...
+func goParamTypes(n *sitter.Node, src []byte) []string {
+	result := []string{}
+	for _, param := range namedChildren(n.ChildByFieldName("parameters")) {
+		typeNode := param.ChildByFieldName("type")
+		if typeNode == nil {
+			continue
+		}
+		typeName := strings.Join(strings.Fields(string(src[typeNode.StartByte():typeNode.EndByte()])), "")
+		if param.Type() == "variadic_parameter_declaration" {
+			typeName = strings.TrimPrefix(typeName, "...") + "..."
+		}
+		count := len(parameterNames(param))
+		if count == 0 {
+			count = 1 // unnamed parameter
+		}
+		for i := 0; i < count; i++ {
+			result = append(result, typeName)
+		}
+	}
+	return result
+}
+
+// memberRef returns the canonical reference of a named identifier declared in a scope: a variable of the enclosing
+// function or a package level symbol; empty when the enclosing function has no reference
+func memberRef(scope *linage.Scope, name string, model *linage.PackageModel) string {
+	for cur := scope; cur != nil; cur = cur.Parent {
+		if cur.Kind != "function" {
+			continue
+		}
+		if cur.Parent == nil {
+			return ""
+		}
+		fn := cur.Parent.Symbols[cur.Name]
+		if fn == nil || fn.Ref == "" {
+			return ""
+		}
+		ref, err := graph.ParseRef(fn.Ref)
+		if err != nil {
+			return ""
+		}
+		return ref.WithMember(name).String()
+	}
+	return graph.NewTypeRef(model.Path, name).String()
+}
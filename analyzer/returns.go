@@ -0,0 +1,80 @@
+package analyzer
+
+import (
+	"fmt"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/viant/linager/analyzer/linage"
+)
+
+// ReturnSite is a return statement of a function, flows returned by distinct statements are kept apart
+type ReturnSite struct {
+	// Line is the 1-based line of the return statement
+	Line int
+	// Branches holds IDs of branches enclosing the statement within its function, innermost first
+	Branches []string
+	// Flows maps a parameter index to return indices returned by the statement
+	Flows map[int][]int
+}
+
+// returnBranches returns IDs of branches (if, else, case, loop and catch bodies) enclosing a return statement within its
+// function, innermost first
+func returnBranches(n *sitter.Node, fnScope *linage.Scope) []string {
+	var result []string
+	child := n
+	for parent := n.Parent(); parent != nil; child, parent = parent, parent.Parent() {
+		kind := ""
+		switch parent.Type() {
+		case "function_declaration", "method_declaration", "constructor_declaration", "func_literal", "lambda_expression":
+			return result
+		case "if_statement":
+			if alternative := parent.ChildByFieldName("alternative"); alternative != nil && alternative.Equal(child) {
+				kind = linage.BranchElse
+			} else if consequence := parent.ChildByFieldName("consequence"); consequence != nil && consequence.Equal(child) {
+				kind = linage.BranchIf
+			}
+		case "expression_case", "type_case", "default_case", "communication_case", "switch_block_statement_group", "switch_rule":
+			kind = linage.BranchCase
+		case "for_statement", "enhanced_for_statement", "while_statement", "do_statement":
+			kind = linage.BranchLoop
+		case "catch_clause":
+			kind = linage.BranchCatch
+		}
+		if kind != "" {
+			result = append(result, fmt.Sprintf("%s.%s@%d", fnScope.ID, kind, parent.StartByte()))
+		}
+	}
+	return result
+}
+
+// returnSiteIndex returns the index of a return statement among return statements of its function in source order
+func returnSiteIndex(n *sitter.Node, fn *linage.Identifier) int {
+	if fn.Node == nil {
+		return 0
+	}
+	index := 0
+	stack := []*sitter.Node{fn.Node}
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if cur.StartByte() >= n.StartByte() {
+			continue
+		}
+		if cur.Type() == "return_statement" {
+			index++
+		}
+		for i := 0; i < int(cur.NamedChildCount()); i++ {
+			stack = append(stack, cur.NamedChild(i))
+		}
+	}
+	return index
+}
+
+// returnAttributes returns attributes of an edge flowing through a return statement
+func returnAttributes(site int, branches []string) map[string]interface{} {
+	result := map[string]interface{}{linage.ReturnSiteAttribute: site}
+	if len(branches) > 0 {
+		result[linage.BranchesAttribute] = branches
+	}
+	return result
+}
@@ -0,0 +1,188 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"github.com/viant/afs"
+	"github.com/viant/afs/url"
+	"github.com/viant/linager/analyzer/linage"
+	"github.com/viant/linager/inspector/graph"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// RunResult holds data points of all frontends and files skipped as oversized or binary
+type RunResult struct {
+	Points  []*linage.DataPoint
+	Skipped []*graph.SkippedFile
+}
+
+// Runner walks a directory tree once and dispatches source files to language frontends by extension; frontends run
+// concurrently, each on its own goroutine as frontends are not safe for concurrent use
+type Runner struct {
+	frontends []LanguageFrontend
+	fs        afs.Service
+	limits    *graph.Config
+}
+
+// RunnerOption represents a Runner option
+type RunnerOption func(*Runner)
+
+// WithRunnerMaxFileSize sets maximum analyzed file size in bytes (graph.DefaultMaxFileSize by default, negative disables the limit)
+func WithRunnerMaxFileSize(size int64) RunnerOption {
+	return func(r *Runner) {
+		r.limits.MaxFileSize = size
+	}
+}
+
+// WithRunnerSourceProvider walks and reads sources with a provider instead of the local file system; analyzer frontends
+// read package files with their own provider, see WithSourceProvider
+func WithRunnerSourceProvider(sources graph.SourceProvider) RunnerOption {
+	return func(r *Runner) {
+		r.limits.Sources = sources
+	}
+}
+
+// NewRunner creates a runner dispatching files to frontends, a file matching several frontends is analyzed by each
+func NewRunner(frontends []LanguageFrontend, options ...RunnerOption) *Runner {
+	ret := &Runner{frontends: frontends, fs: afs.New(), limits: &graph.Config{}}
+	for _, opt := range options {
+		if opt != nil {
+			opt(ret)
+		}
+	}
+	return ret
+}
+
+// Run analyzes source files under root with all matching frontends; errors of individual packages and files do not stop
+// the run, they are joined and returned with data points of the remaining sources
+func (r *Runner) Run(ctx context.Context, root string) (*RunResult, error) {
+	sources, err := walkSources(ctx, r.fs, root, r.match, r.limits)
+	if err != nil {
+		return nil, err
+	}
+	result := &RunResult{}
+	for _, pkgURL := range sortedPackages(sources.skipped) {
+		result.Skipped = append(result.Skipped, sources.skipped[pkgURL]...)
+	}
+	points := make([][]*linage.DataPoint, len(r.frontends))
+	skipped := make([][]*graph.SkippedFile, len(r.frontends))
+	errs := make([][]error, len(r.frontends))
+	waitGroup := sync.WaitGroup{}
+	for i, frontend := range r.frontends {
+		waitGroup.Add(1)
+		go func(i int, frontend LanguageFrontend) {
+			defer waitGroup.Done()
+			points[i], skipped[i], errs[i] = r.runFrontend(ctx, frontend, sources)
+		}(i, frontend)
+	}
+	waitGroup.Wait()
+	var joined []error
+	for i := range r.frontends {
+		result.Points = append(result.Points, points[i]...)
+		result.Skipped = append(result.Skipped, skipped[i]...)
+		joined = append(joined, errs[i]...)
+	}
+	return result, errors.Join(joined...)
+}
+
+// runFrontend analyzes package files handled by a frontend; analyzers analyze whole packages sharing a package model
+// and merge them as AnalyzeAll does, other frontends analyze files one by one
+func (r *Runner) runFrontend(ctx context.Context, frontend LanguageFrontend, sources *sourceFiles) ([]*linage.DataPoint, []*graph.SkippedFile, []error) {
+	var points []*linage.DataPoint
+	var skipped []*graph.SkippedFile
+	var errs []error
+	analyzer, isAnalyzer := frontend.(*Analyzer)
+	var models []*linage.PackageModel
+	for _, pkgURL := range sortedPackages(sources.files) {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, append(errs, err)
+		}
+		var files []string
+		for _, file := range sources.files[pkgURL] {
+			if r.handles(frontend, file) {
+				files = append(files, file.Name())
+			}
+		}
+		if len(files) == 0 {
+			continue
+		}
+		if isAnalyzer {
+			model, err := analyzer.analyzePackage(ctx, pkgURL, files)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			skipped = append(skipped, model.Skipped...)
+			models = append(models, model)
+			continue
+		}
+		for _, file := range files {
+			URL := url.Join(pkgURL, file)
+			code, skippedFile, err := loadSource(ctx, r.fs, r.limits.Sources, URL)
+			if err == nil && skippedFile == nil {
+				var filePoints []*linage.DataPoint
+				if filePoints, err = frontend.AnalyzeSource(code, pkgURL, URL); err == nil {
+					points = append(points, filePoints...)
+				}
+			}
+			if err != nil {
+				errs = append(errs, err)
+			}
+			if skippedFile != nil {
+				skipped = append(skipped, skippedFile)
+			}
+		}
+	}
+	if len(models) > 0 {
+		merged := linage.Merge(models...)
+		merged.Language = analyzer.Language
+		points = append(points, linage.NewDataPoints(merged)...)
+	}
+	return points, skipped, errs
+}
+
+// match descends into directories no analyzer frontend excludes (e.g. vendor, node_modules) and matches files
+// handled by any frontend
+func (r *Runner) match(info os.FileInfo) bool {
+	if info.IsDir() {
+		for _, frontend := range r.frontends {
+			if analyzer, ok := frontend.(*Analyzer); ok && analyzer.match != nil && !analyzer.match(info) {
+				return false
+			}
+		}
+		return true
+	}
+	for _, frontend := range r.frontends {
+		if r.handles(frontend, info) {
+			return true
+		}
+	}
+	return false
+}
+
+// handles reports whether a file has an extension of a frontend and is accepted by the analyzer matcher
+func (r *Runner) handles(frontend LanguageFrontend, info os.FileInfo) bool {
+	if analyzer, ok := frontend.(*Analyzer); ok && analyzer.match != nil && !analyzer.match(info) {
+		return false
+	}
+	ext := strings.ToLower(filepath.Ext(info.Name()))
+	for _, candidate := range frontend.Extensions() {
+		if ext == candidate {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedPackages[T any](packages map[string]T) []string {
+	result := make([]string, 0, len(packages))
+	for pkgURL := range packages {
+		result = append(result, pkgURL)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,232 @@
+package analyzer
+
+import (
+	"fmt"
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/viant/linager/analyzer/linage"
+	"go/types"
+	"strings"
+)
+
+var (
+	// serializationFormats maps encoding package names to their formats
+	serializationFormats = map[string]string{"json": "json", "xml": "xml", "yaml": "yaml", "proto": "proto"}
+	// serializationFunctions maps encoding package functions to directions
+	serializationFunctions = map[string]string{
+		"Marshal": linage.SerializationMarshal, "MarshalIndent": linage.SerializationMarshal, "Unmarshal": linage.SerializationUnmarshal,
+	}
+	// serializationMethods maps encoder and decoder methods to directions
+	serializationMethods = map[string]string{"Encode": linage.SerializationMarshal, "Decode": linage.SerializationUnmarshal}
+	// serializationConstructors maps encoder and decoder constructors to directions
+	serializationConstructors = map[string]string{"NewEncoder": linage.SerializationMarshal, "NewDecoder": linage.SerializationUnmarshal}
+)
+
+// SerializationPlugin marks calls serializing values across process boundaries: json, xml, yaml and proto
+// Marshal/Unmarshal functions and Encode/Decode methods of their encoders and decoders. Every call site gets a
+// synthetic "serialization" identifier annotated with the format and direction; the marshaled value flows into it
+// and it flows into the unmarshal target, both with XFER edges. The identifier type holds the concrete type of the
+// value when resolvable from a variable type or a composite literal, see linage.PackageModel.SerializationPoints.
+type SerializationPlugin struct {
+	encoders map[string]string // encoder and decoder variable IDs to their formats
+}
+
+// NewSerializationPlugin creates a serialization boundary plugin
+func NewSerializationPlugin() *SerializationPlugin {
+	return &SerializationPlugin{}
+}
+
+// Init resets encoders collected for a package model
+func (p *SerializationPlugin) Init(model *linage.PackageModel, options *PluginOptions) {
+	p.encoders = map[string]string{}
+}
+
+// NodeTypes limits dispatch to calls and assignments of call results
+func (p *SerializationPlugin) NodeTypes() []string {
+	return []string{"call_expression", "short_var_declaration", "assignment_statement", "var_spec", "return_statement"}
+}
+
+// BeforeWalk is a no-op, arguments are resolved by the analyzer first
+func (p *SerializationPlugin) BeforeWalk(n *sitter.Node, src []byte, scope *linage.Scope, model *linage.PackageModel) {
+}
+
+// AfterResolveIdent is a no-op
+func (p *SerializationPlugin) AfterResolveIdent(n *sitter.Node, id *linage.Identifier, scope *linage.Scope, model *linage.PackageModel) {
+}
+
+// AfterWalk links values of serialization calls to their boundaries, calls assigned to variables or returned are
+// not walked as expressions, e.g. data, err := json.Marshal(order)
+func (p *SerializationPlugin) AfterWalk(n *sitter.Node, src []byte, scope *linage.Scope, model *linage.PackageModel) {
+	if p.encoders == nil {
+		p.encoders = map[string]string{} // not initialized with a model
+	}
+	switch n.Type() {
+	case "call_expression":
+		p.link(n, src, scope, model)
+	case "short_var_declaration", "assignment_statement", "var_spec":
+		left, right := namedChildren(n.ChildByFieldName("left")), namedChildren(n.ChildByFieldName("right"))
+		if n.Type() == "var_spec" {
+			left, right = parameterNames(n), namedChildren(n.ChildByFieldName("value"))
+		}
+		for i, value := range right {
+			if value.Type() != "call_expression" {
+				continue
+			}
+			p.link(value, src, scope, model)
+			if i < len(left) && left[i].Type() == "identifier" {
+				p.collectEncoder(left[i], value, src, scope)
+			}
+		}
+	case "return_statement":
+		for _, list := range namedChildren(n) {
+			for _, value := range append([]*sitter.Node{list}, namedChildren(list)...) {
+				if value.Type() == "call_expression" {
+					p.link(value, src, scope, model)
+				}
+			}
+		}
+	}
+}
+
+// collectEncoder records the format of a variable assigned an encoder or decoder, e.g. enc := json.NewEncoder(w)
+func (p *SerializationPlugin) collectEncoder(name, call *sitter.Node, src []byte, scope *linage.Scope) {
+	format, function := qualifiedCall(call, src)
+	if format == "" || serializationConstructors[function] == "" {
+		return
+	}
+	if id := scope.Find(name.Content(src)); id != nil {
+		p.encoders[id.ID] = format
+	}
+}
+
+// link creates the boundary identifier of a recognized serialization call and its edges
+func (p *SerializationPlugin) link(call *sitter.Node, src []byte, scope *linage.Scope, model *linage.PackageModel) {
+	format, direction := p.recognize(call, src, scope)
+	if format == "" {
+		return
+	}
+	args := namedChildren(call.ChildByFieldName("arguments"))
+	if len(args) == 0 {
+		return
+	}
+	value := args[0]
+	if direction == linage.SerializationUnmarshal {
+		value = args[len(args)-1]
+	}
+	file := strings.TrimPrefix(topFileScope(scope).ID, model.Path+":")
+	key := fmt.Sprintf("%s::%s::%d#serialization", model.Path, file, call.StartByte())
+	if _, ok := model.Idents[key]; ok {
+		return // already linked
+	}
+	boundary := &linage.Identifier{
+		ID:         key,
+		Name:       callName(call, src),
+		Kind:       linage.SerializationKind,
+		Package:    model.Path,
+		File:       file,
+		StartByte:  call.StartByte(),
+		Type:       valueType(value, src, scope),
+		Annotation: linage.Annotations{linage.SerializationFormatAnnotation: format, linage.SerializationDirectionAnnotation: direction},
+		Node:       call,
+	}
+	model.Idents[key] = boundary
+	id := p.valueIdent(value, src, scope, model)
+	if id == nil {
+		return
+	}
+	edge := &linage.DataFlowEdge{Src: id, Dst: boundary, Kind: linage.Xfer, Scope: scope.ID}
+	if direction == linage.SerializationUnmarshal {
+		edge.Src, edge.Dst = boundary, id
+	}
+	model.DataFlows = append(model.DataFlows, edge)
+}
+
+// recognize returns the format and direction of a serialization call, an empty format otherwise
+func (p *SerializationPlugin) recognize(call *sitter.Node, src []byte, scope *linage.Scope) (string, string) {
+	if pkg, function := qualifiedCall(call, src); serializationFormats[pkg] != "" && serializationFunctions[function] != "" {
+		return serializationFormats[pkg], serializationFunctions[function]
+	}
+	fn := call.ChildByFieldName("function")
+	if fn == nil || fn.Type() != "selector_expression" {
+		return "", ""
+	}
+	operand, field := fn.ChildByFieldName("operand"), fn.ChildByFieldName("field")
+	direction := serializationMethods[field.Content(src)]
+	if operand == nil || direction == "" {
+		return "", ""
+	}
+	switch operand.Type() {
+	case "call_expression": // json.NewEncoder(w).Encode(v)
+		if pkg, constructor := qualifiedCall(operand, src); serializationConstructors[constructor] == direction {
+			return serializationFormats[pkg], direction
+		}
+	case "identifier":
+		id := scope.Find(operand.Content(src))
+		if id == nil {
+			return "", ""
+		}
+		if format := p.encoders[id.ID]; format != "" {
+			return format, direction
+		}
+		// typed encoder variables or parameters, e.g. enc *json.Encoder
+		if pkg, typeName, ok := strings.Cut(strings.TrimLeft(id.Type, "*"), "."); ok && (typeName == "Encoder" || typeName == "Decoder") {
+			return serializationFormats[pkg], direction
+		}
+	}
+	return "", ""
+}
+
+// valueIdent returns the variable or selected field of a serialized value, e.g. order of &order
+func (p *SerializationPlugin) valueIdent(n *sitter.Node, src []byte, scope *linage.Scope, model *linage.PackageModel) *linage.Identifier {
+	for n.Type() == "unary_expression" || n.Type() == "parenthesized_expression" {
+		n = n.NamedChild(int(n.NamedChildCount()) - 1)
+	}
+	switch n.Type() {
+	case "identifier":
+		if isBlank(n, src) {
+			return nil
+		}
+		return scope.Find(n.Content(src))
+	case "selector_expression":
+		field := n.ChildByFieldName("field")
+		file := strings.TrimPrefix(topFileScope(scope).ID, model.Path+":")
+		return model.Idents[fmt.Sprintf("%s::%s::%d", model.Path, file, field.StartByte())]
+	}
+	return nil
+}
+
+// qualifiedCall returns the package and function names of a package function call, e.g. json and Marshal
+func qualifiedCall(call *sitter.Node, src []byte) (string, string) {
+	fn := call.ChildByFieldName("function")
+	if fn == nil || fn.Type() != "selector_expression" {
+		return "", ""
+	}
+	operand, field := fn.ChildByFieldName("operand"), fn.ChildByFieldName("field")
+	if operand == nil || field == nil || operand.Type() != "identifier" {
+		return "", ""
+	}
+	return operand.Content(src), field.Content(src)
+}
+
+// valueType returns the named type of a serialized value as written, pointers dereferenced, e.g. Order for &order
+// or &Order{}; empty for predeclared and composite types
+func valueType(n *sitter.Node, src []byte, scope *linage.Scope) string {
+	for n.Type() == "unary_expression" || n.Type() == "parenthesized_expression" {
+		n = n.NamedChild(int(n.NamedChildCount()) - 1)
+	}
+	var name string
+	switch n.Type() {
+	case "identifier":
+		if id := scope.Find(n.Content(src)); id != nil {
+			name = id.Type
+		}
+	case "composite_literal":
+		if typeNode := n.ChildByFieldName("type"); typeNode != nil {
+			name = typeNode.Content(src)
+		}
+	}
+	name = strings.TrimLeft(name, "*")
+	if strings.ContainsAny(name, "[]{}() ") || types.Universe.Lookup(name) != nil {
+		return ""
+	}
+	return name
+}
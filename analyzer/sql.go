@@ -0,0 +1,356 @@
+package analyzer
+
+import (
+	"fmt"
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/viant/linager/analyzer/linage"
+	"github.com/viant/linager/inspector/graph"
+	"maps"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	// sqlStatement matches string literals holding SQL statements
+	sqlStatement = regexp.MustCompile(`(?i)^\s*(select|insert|update|delete|with)\s`)
+	// sqlToken matches SQL identifiers, qualified names are split by the dot
+	sqlToken = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+	// sqlQuoted matches SQL string literals, their content is not a column
+	sqlQuoted = regexp.MustCompile(`'(?:[^']|'')*'`)
+	// sqlTable matches tables read or written by a statement with their optional alias
+	sqlTable = regexp.MustCompile(`(?i)\b(?:from|join|into|update)\s+([A-Za-z_][A-Za-z0-9_.]*)(?:\s+(?:as\s+)?([A-Za-z_][A-Za-z0-9_]*))?`)
+	// sqlReference matches column references: a parameter prefix, a name, a column of a qualified name and a call paren
+	sqlReference = regexp.MustCompile(`([:@$]?)([A-Za-z_][A-Za-z0-9_]*)(?:\.([A-Za-z_][A-Za-z0-9_]*))?(\s*\()?`)
+	// sqlKeywords holds SQL keywords not naming columns
+	sqlKeywords = map[string]bool{}
+	// mappingTags maps model mappings to their tag keys
+	mappingTags = map[string]string{graph.MappingGORM: "gorm", graph.MappingSQLX: "db", graph.MappingBun: "bun"}
+)
+
+func init() {
+	for _, keyword := range strings.Fields(`all and any as asc between by case cast conflict cross default delete desc distinct do
+		else end escape except exists false fetch first for from full group having ilike in inner insert intersect interval into
+		is join key lateral left like limit locked next not nothing null offset on only or order outer primary recursive
+		returning right row rows select set share skip some table then to true union update using values when where with`) {
+		sqlKeywords[keyword] = true
+	}
+}
+
+// sqlSite is a string literal declared by a constant or a struct tag value with its location
+type sqlSite struct {
+	ident *linage.Identifier
+	key   string // tag key, empty for constants
+	value string
+	file  string
+	line  int
+	scope string
+}
+
+// sqlQuery is a SQL literal passed to a call with its identifier tokens and columns referenced by table
+type sqlQuery struct {
+	sqlSite
+	tokens  map[string]bool
+	columns map[string][]string
+}
+
+// SQLPlugin connects constants, struct tags, ORM models and SQL queries sharing literal values: a constant whose string
+// value equals a struct tag value (db:"user_name") flows into the tagged field, fields of models mapped to tables (see
+// graph.MapModel) flow into synthetic column identifiers (linage.SQLColumnKind) and columns referenced by query
+// literals passed to calls flow into the query site, e.g. const UserNameColumn = "user_name" -> User.Name ->
+// users.user_name -> db.Query("SELECT user_name FROM users"). Column identifiers are keyed by table and column only,
+// so models and queries of different packages share them once package models are merged; a query naming no table is
+// linked directly to fields whose column tag (db, sql, gorm column, bigquery) names one of its tokens. Matching is
+// exact, edges carry the tag key, table, column and the file and line of the tag, field or query.
+type SQLPlugin struct {
+	resolve   func(n *sitter.Node, src []byte, scope *linage.Scope, model *linage.PackageModel) *linage.Identifier
+	constants map[string][]*sqlSite
+	tags      []*sqlSite
+	queries   []*sqlQuery
+	types     map[string]*graph.Type       // struct types of the package by name
+	methods   map[string][]*graph.Function // TableName methods by receiver type name
+	fields    map[*graph.Field]*sqlSite    // declaration sites of struct fields
+}
+
+// NewSQLPlugin creates a SQL column lineage plugin
+func NewSQLPlugin() *SQLPlugin {
+	return &SQLPlugin{}
+}
+
+// Init resets sites collected for a package model
+func (p *SQLPlugin) Init(model *linage.PackageModel, options *PluginOptions) {
+	p.resolve = options.Resolve
+	p.constants = map[string][]*sqlSite{}
+	p.tags = nil
+	p.queries = nil
+	p.types = map[string]*graph.Type{}
+	p.methods = map[string][]*graph.Function{}
+	p.fields = map[*graph.Field]*sqlSite{}
+}
+
+// NodeTypes limits dispatch to constants, type specs, methods, calls and assignments of call results
+func (p *SQLPlugin) NodeTypes() []string {
+	return []string{"const_spec", "type_spec", "method_declaration", "call_expression", "short_var_declaration", "assignment_statement"}
+}
+
+// BeforeWalk collects string constants, struct tag values and SQL query literals
+func (p *SQLPlugin) BeforeWalk(n *sitter.Node, src []byte, scope *linage.Scope, model *linage.PackageModel) {
+	if p.constants == nil {
+		return // not initialized with a model
+	}
+	file := strings.TrimPrefix(topFileScope(scope).ID, model.Path+":")
+	switch n.Type() {
+	case "const_spec":
+		values := namedChildren(n.ChildByFieldName("value"))
+		for i, nameNode := range parameterNames(n) {
+			if i >= len(values) || !isStringLiteral(values[i]) || p.resolve == nil {
+				continue
+			}
+			value, err := strconv.Unquote(values[i].Content(src))
+			if err != nil || value == "" {
+				continue
+			}
+			site := &sqlSite{ident: p.resolve(nameNode, src, scope, model), value: value, file: file, line: int(nameNode.StartPoint().Row) + 1, scope: scope.ID}
+			p.constants[value] = append(p.constants[value], site)
+		}
+	case "type_spec":
+		p.collectTags(n, file, src, scope, model)
+	case "method_declaration":
+		p.collectTableName(n, src)
+	case "call_expression":
+		p.collectQueries(n, file, src, scope, model)
+	case "short_var_declaration", "assignment_statement":
+		// calls assigned to variables are not walked as expressions, e.g. rows, err := db.Query(...)
+		for _, value := range namedChildren(n.ChildByFieldName("right")) {
+			if value.Type() == "call_expression" {
+				p.collectQueries(value, file, src, scope, model)
+			}
+		}
+	}
+}
+
+// collectQueries collects SQL query literals passed to a call
+func (p *SQLPlugin) collectQueries(n *sitter.Node, file string, src []byte, scope *linage.Scope, model *linage.PackageModel) {
+	for _, arg := range namedChildren(n.ChildByFieldName("arguments")) {
+		if !isStringLiteral(arg) {
+			continue
+		}
+		text, err := strconv.Unquote(arg.Content(src))
+		if err != nil || !sqlStatement.MatchString(text) {
+			continue
+		}
+		p.queries = append(p.queries, &sqlQuery{
+			sqlSite: sqlSite{ident: p.queryIdent(n, file, text, src, model), value: text, file: file, line: int(arg.StartPoint().Row) + 1, scope: scope.ID},
+			tokens:  sqlTokens(text),
+			columns: sqlColumns(text),
+		})
+	}
+}
+
+// collectTags collects struct field tag values and the struct type of a type spec
+func (p *SQLPlugin) collectTags(n *sitter.Node, file string, src []byte, scope *linage.Scope, model *linage.PackageModel) {
+	nameNode, typeNode := n.ChildByFieldName("name"), n.ChildByFieldName("type")
+	if nameNode == nil || typeNode == nil || typeNode.Type() != "struct_type" {
+		return
+	}
+	typeName := nameNode.Content(src)
+	aType := &graph.Type{Name: typeName, Kind: reflect.Struct}
+	p.types[typeName] = aType
+	for _, list := range namedChildren(typeNode) {
+		for _, decl := range namedChildren(list) {
+			if decl.Type() != "field_declaration" {
+				continue
+			}
+			var tag string
+			tagNode := decl.ChildByFieldName("tag")
+			if tagNode != nil {
+				tag, _ = strconv.Unquote(tagNode.Content(src))
+			}
+			var fieldType string
+			if typeNode := decl.ChildByFieldName("type"); typeNode != nil {
+				fieldType = typeNode.Content(src)
+			}
+			fields := structFieldIdents(typeName, file, decl, src, model)
+			line := int(decl.StartPoint().Row) + 1
+			if len(fields) == 0 { // embedded field
+				aType.Fields = append(aType.Fields, &graph.Field{Tag: reflect.StructTag(tag), IsEmbedded: true, Type: &graph.Type{Name: fieldType}})
+			}
+			for _, field := range fields {
+				modelField := &graph.Field{Name: field.Name, Tag: reflect.StructTag(tag), IsExported: isExportedName(field.Name), Type: &graph.Type{Name: fieldType}}
+				aType.Fields = append(aType.Fields, modelField)
+				p.fields[modelField] = &sqlSite{ident: field, file: file, line: line, scope: scope.ID}
+			}
+			if tagNode == nil {
+				continue
+			}
+			for _, field := range fields {
+				for _, value := range graph.TagValues(reflect.StructTag(tag)) {
+					p.tags = append(p.tags, &sqlSite{ident: field, key: value.Key, value: value.Value, file: file, line: int(tagNode.StartPoint().Row) + 1, scope: scope.ID})
+				}
+			}
+		}
+	}
+}
+
+// collectTableName collects a TableName() method overriding the table name of a model, see graph.MapModel
+func (p *SQLPlugin) collectTableName(n *sitter.Node, src []byte) {
+	nameNode, params, body := n.ChildByFieldName("name"), n.ChildByFieldName("parameters"), n.ChildByFieldName("body")
+	if nameNode == nil || nameNode.Content(src) != "TableName" || body == nil || (params != nil && params.NamedChildCount() > 0) {
+		return
+	}
+	receiver := namedChildren(n.ChildByFieldName("receiver"))
+	if len(receiver) == 0 || receiver[0].ChildByFieldName("type") == nil {
+		return
+	}
+	typeName := strings.TrimPrefix(receiver[0].ChildByFieldName("type").Content(src), "*")
+	typeName, _, _ = strings.Cut(typeName, "[")
+	p.methods[typeName] = append(p.methods[typeName], &graph.Function{Name: "TableName", Body: &graph.LocationNode{Text: body.Content(src)}})
+}
+
+// queryIdent returns the synthetic query identifier of a call site annotated with tables of the query
+func (p *SQLPlugin) queryIdent(call *sitter.Node, file, query string, src []byte, model *linage.PackageModel) *linage.Identifier {
+	key := fmt.Sprintf("%s::%s::%d#sql", model.Path, file, call.StartByte())
+	id, ok := model.Idents[key]
+	if !ok {
+		id = &linage.Identifier{ID: key, Name: callName(call, src), Kind: linage.SQLQueryKind, Package: model.Path, File: file, StartByte: call.StartByte()}
+		model.Idents[key] = id
+	}
+	tables := linage.SQLTables(id)
+	for _, match := range sqlTable.FindAllStringSubmatch(sqlQuoted.ReplaceAllString(query, ""), -1) {
+		if !containsString(tables, match[1]) {
+			tables = append(tables, match[1])
+		}
+	}
+	if len(tables) > 0 {
+		if id.Annotation == nil {
+			id.Annotation = linage.Annotations{}
+		}
+		id.Annotation[linage.SQLTablesAnnotation] = strings.Join(tables, ",")
+	}
+	return id
+}
+
+// sqlColumns returns columns referenced by a query by table: columns qualified by a table or alias, and other names
+// of queries of a single table; keywords, tables, aliases, parameters, functions and expression aliases are excluded.
+// Table and column names are lower case, schemas are dropped
+func sqlColumns(query string) map[string][]string {
+	text := sqlQuoted.ReplaceAllString(query, "")
+	aliases := map[string]string{}
+	var tables []string
+	for _, match := range sqlTable.FindAllStringSubmatch(text, -1) {
+		table := strings.ToLower(match[1][strings.LastIndex(match[1], ".")+1:])
+		if !containsString(tables, table) {
+			tables = append(tables, table)
+		}
+		aliases[table] = table
+		if alias := strings.ToLower(match[2]); alias != "" && !sqlKeywords[alias] {
+			aliases[alias] = table
+		}
+	}
+	result := map[string][]string{}
+	add := func(table, column string) {
+		if !containsString(result[table], column) {
+			result[table] = append(result[table], column)
+		}
+	}
+	previous := ""
+	for _, match := range sqlReference.FindAllStringSubmatch(text, -1) {
+		name := strings.ToLower(match[2])
+		before := previous
+		previous = name
+		switch {
+		case match[1] != "" || match[4] != "": // parameter or function call
+		case match[3] != "":
+			if table, ok := aliases[name]; ok {
+				add(table, strings.ToLower(match[3]))
+			}
+		case sqlKeywords[name] || aliases[name] != "" || before == "as" || len(tables) != 1:
+		default:
+			add(tables[0], name)
+		}
+	}
+	return result
+}
+
+// columnIdent returns the synthetic identifier of a table column, shared by all package models
+func columnIdent(table, column string, model *linage.PackageModel) *linage.Identifier {
+	table, column = strings.ToLower(table), strings.ToLower(column)
+	key := linage.SQLColumnID(table, column)
+	id, ok := model.Idents[key]
+	if !ok {
+		id = &linage.Identifier{ID: key, Name: table + "." + column, Kind: linage.SQLColumnKind, Annotation: linage.Annotations{linage.SQLTablesAnnotation: table}}
+		model.Idents[key] = id
+	}
+	return id
+}
+
+// sqlTokens returns identifier tokens of a query, string literals excluded
+func sqlTokens(query string) map[string]bool {
+	result := map[string]bool{}
+	for _, token := range sqlToken.FindAllString(sqlQuoted.ReplaceAllString(query, ""), -1) {
+		result[token] = true
+	}
+	return result
+}
+
+// AfterResolveIdent is a no-op
+func (p *SQLPlugin) AfterResolveIdent(n *sitter.Node, id *linage.Identifier, scope *linage.Scope, model *linage.PackageModel) {
+}
+
+// Finish links constants to tagged fields, model fields to their columns, columns to queries referencing them and
+// column tagged fields to queries naming no table
+func (p *SQLPlugin) Finish(model *linage.PackageModel) {
+	for _, tag := range p.tags {
+		for _, constant := range p.constants[tag.value] {
+			model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: constant.ident, Dst: tag.ident, Kind: linage.Xfer, Scope: tag.scope, Literal: tag.value,
+				Attributes: map[string]interface{}{linage.TagKeyAttribute: tag.key, linage.FileAttribute: tag.file, linage.LineAttribute: tag.line}})
+		}
+		if !containsString(columnTags, tag.key) {
+			continue
+		}
+		for _, query := range p.queries {
+			if len(query.columns) == 0 && len(linage.SQLTables(query.ident)) == 0 && query.tokens[tag.value] {
+				model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: tag.ident, Dst: query.ident, Kind: linage.Xfer, Scope: query.scope,
+					Attributes: map[string]interface{}{linage.SQLColumnAttribute: tag.value, linage.TagKeyAttribute: tag.key, linage.FileAttribute: query.file, linage.LineAttribute: query.line}})
+			}
+		}
+	}
+	p.linkModels(model)
+	for _, query := range p.queries {
+		for _, table := range slices.Sorted(maps.Keys(query.columns)) {
+			for _, column := range query.columns[table] {
+				model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: columnIdent(table, column, model), Dst: query.ident, Kind: linage.Xfer, Scope: query.scope,
+					Attributes: map[string]interface{}{linage.SQLColumnAttribute: column, linage.SQLTableAttribute: table, linage.FileAttribute: query.file, linage.LineAttribute: query.line}})
+			}
+		}
+	}
+	p.constants, p.tags, p.queries = nil, nil, nil
+	p.types, p.methods, p.fields = nil, nil, nil
+}
+
+// linkModels links fields of package structs mapped to tables to their column identifiers
+func (p *SQLPlugin) linkModels(model *linage.PackageModel) {
+	lookup := func(name string) *graph.Type { return p.types[name] }
+	for _, name := range slices.Sorted(maps.Keys(p.types)) {
+		aType := p.types[name]
+		aType.Methods = p.methods[name]
+		table := graph.MapModel(aType, lookup)
+		if table == nil {
+			continue
+		}
+		for _, column := range table.Columns {
+			field, _ := column.MappedField()
+			site := p.fields[field]
+			if site == nil {
+				continue
+			}
+			attributes := map[string]interface{}{linage.SQLColumnAttribute: column.Name, linage.SQLTableAttribute: table.Name, linage.FileAttribute: site.file, linage.LineAttribute: site.line}
+			if key := mappingTags[table.Mapping]; field.Tag.Get(key) != "" {
+				attributes[linage.TagKeyAttribute] = key
+			}
+			model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: site.ident, Dst: columnIdent(table.Name, column.Name, model), Kind: linage.Xfer, Scope: site.scope, Attributes: attributes})
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package shop
+
+// MaxItems limits order size
+const MaxItems = 10
+
+// Order is a customer order
+type Order struct {
+	ID       int
+	Customer string
+	Items    []Item
+	Total    float64
+}
+
+// Item is an ordered product
+type Item struct {
+	SKU   string
+	Price float64
+	Qty   int
+}
+
+// Add appends an item and updates the total
+func (o *Order) Add(item Item) bool {
+	if len(o.Items) >= MaxItems {
+		return false
+	}
+	o.Items = append(o.Items, item)
+	o.Total = o.Total + item.Price*float64(item.Qty)
+	return true
+}
+
+// Checkout adds an item and returns the order total
+func Checkout(order *Order, sku string, price float64) float64 {
+	item := Item{SKU: sku, Price: price, Qty: 1}
+	order.Add(item)
+	total := order.Total
+	return total
+}
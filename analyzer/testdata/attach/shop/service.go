@@ -0,0 +1,16 @@
+package shop
+
+import "strings"
+
+var orders = map[int]*Order{}
+
+// Place stores a new order for a customer
+func Place(id int, customer string) *Order {
+	order := &Order{ID: id, Customer: normalize(customer)}
+	orders[id] = order
+	return order
+}
+
+func normalize(name string) string {
+	return strings.TrimSpace(name)
+}
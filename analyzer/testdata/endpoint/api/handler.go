@@ -0,0 +1,34 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"example/model"
+	"example/store"
+	"log"
+	"net/http"
+	"os"
+)
+
+var db *sql.DB
+
+// ListOrders handles GET /orders
+func ListOrders(w http.ResponseWriter, r *http.Request) {
+	req := &model.OrderRequest{}
+	json.NewDecoder(r.Body).Decode(req)
+	found, err := load(req.CustomerID)
+	if err != nil {
+		log.Printf("failed to load orders of %v: %v", req.CustomerID, err)
+		return
+	}
+	resp := &model.OrderResponse{}
+	resp.Orders = found
+	resp.Count = len(found)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func load(customerID string) ([]*model.Order, error) {
+	limit := os.Getenv("ORDERS_LIMIT")
+	repository := &store.Store{DB: db, Limit: limit}
+	return repository.Find(customerID)
+}
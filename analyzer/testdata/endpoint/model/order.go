@@ -0,0 +1,15 @@
+package model
+
+type OrderRequest struct {
+	CustomerID string `json:"customerId"`
+}
+
+type Order struct {
+	ID     int    `db:"id" json:"id"`
+	Status string `db:"status" json:"status"`
+}
+
+type OrderResponse struct {
+	Orders []*Order `json:"orders"`
+	Count  int      `json:"count"`
+}
@@ -0,0 +1,27 @@
+package store
+
+import (
+	"database/sql"
+	"example/model"
+)
+
+// Store loads orders
+type Store struct {
+	DB    *sql.DB
+	Limit string
+}
+
+// Find returns orders of a customer
+func (s *Store) Find(customerID string) ([]*model.Order, error) {
+	rows, err := s.DB.Query("SELECT id, status FROM orders WHERE customer_id = ?", customerID)
+	if err != nil {
+		return nil, err
+	}
+	var result []*model.Order
+	for rows.Next() {
+		order := &model.Order{}
+		rows.Scan(&order.ID, &order.Status)
+		result = append(result, order)
+	}
+	return result, nil
+}
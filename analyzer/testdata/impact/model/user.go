@@ -0,0 +1,11 @@
+package model
+
+type User struct {
+	ID    int    `json:"id"`
+	Email string `json:"email,omitempty" db:"email_address"`
+}
+
+func NewUser(email string) *User {
+	user := User{Email: email}
+	return &user
+}
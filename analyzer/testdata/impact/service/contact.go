@@ -0,0 +1,14 @@
+package service
+
+// Contact shares the Email field name with model.User
+type Contact struct {
+	Email string
+}
+
+func subscribe(list string) {
+}
+
+func Subscribe(c *Contact) {
+	list := c.Email
+	subscribe(list)
+}
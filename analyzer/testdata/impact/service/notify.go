@@ -0,0 +1,15 @@
+package service
+
+import (
+	"example/model"
+	"log"
+)
+
+func send(to string) {
+}
+
+func Notify(u *model.User) {
+	log.Printf("notify %s", u.Email)
+	address := u.Email
+	send(address)
+}
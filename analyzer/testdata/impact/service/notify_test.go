@@ -0,0 +1,11 @@
+package service
+
+import (
+	"example/model"
+	"testing"
+)
+
+func TestNotify(t *testing.T) {
+	user := model.User{Email: "bob@example.com"}
+	Notify(&user)
+}
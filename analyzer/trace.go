@@ -0,0 +1,235 @@
+package analyzer
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/viant/linager/analyzer/linage"
+	"github.com/viant/linager/inspector/graph"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// Trace event kinds
+const (
+	TraceNode    = "node"    // AST node entered by the walk
+	TraceScope   = "scope"   // scope created
+	TraceIdent   = "ident"   // identifier resolved
+	TraceEdge    = "edge"    // data flow edge emitted
+	TraceSummary = "summary" // function summary applied at a call site
+)
+
+// maxTraceSnippet limits source snippets of node events
+const maxTraceSnippet = 80
+
+// TraceEvent is a language neutral record of the analyzer walk written as a JSON line by WithTrace
+type TraceEvent struct {
+	Seq     int64  `json:"seq"`
+	Kind    string `json:"kind"`
+	File    string `json:"file,omitempty"`
+	Node    string `json:"node,omitempty"` // tree-sitter node type of node events and call sites
+	Start   uint32 `json:"start,omitempty"`
+	End     uint32 `json:"end,omitempty"`
+	Snippet string `json:"snippet,omitempty"`
+	Scope   string `json:"scope,omitempty"` // created scope, scope an identifier resolved in or edge scope
+	Ident   string `json:"ident,omitempty"` // resolved identifier or function of an applied summary
+	Name    string `json:"name,omitempty"`  // identifier name or scope kind
+	Src     string `json:"src,omitempty"`
+	Dst     string `json:"dst,omitempty"`
+	Edge    string `json:"edge,omitempty"` // edge kind
+	Via     string `json:"via,omitempty"`  // provenance: node (type@start) or analysis step emitting the event
+}
+
+// Trace holds events read with ReadTrace
+type Trace []*TraceEvent
+
+// ReadTrace reads JSON line events written by WithTrace
+func ReadTrace(r io.Reader) (Trace, error) {
+	var result Trace
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		event := &TraceEvent{}
+		if err := json.Unmarshal(scanner.Bytes(), event); err != nil {
+			return nil, fmt.Errorf("failed to decode trace line %d: %w", line, err)
+		}
+		result = append(result, event)
+	}
+	return result, scanner.Err()
+}
+
+// Touching returns events resolving, emitting edges of or applying the summary of an identifier given by its ID or
+// name, e.g. all events explaining why an edge into a variable exists or is missing
+func (t Trace) Touching(ident string) Trace {
+	ids := map[string]bool{ident: true}
+	for _, event := range t {
+		if event.Kind == TraceIdent && event.Name == ident {
+			ids[event.Ident] = true
+		}
+	}
+	var result Trace
+	for _, event := range t {
+		if (event.Ident != "" && ids[event.Ident]) || (event.Src != "" && ids[event.Src]) || (event.Dst != "" && ids[event.Dst]) {
+			result = append(result, event)
+		}
+	}
+	return result
+}
+
+// Kind returns events of the given kind
+func (t Trace) Kind(kind string) Trace {
+	var result Trace
+	for _, event := range t {
+		if event.Kind == kind {
+			result = append(result, event)
+		}
+	}
+	return result
+}
+
+// tracer streams trace events, the analyzer keeps it nil unless enabled with WithTrace so that the walk does not
+// allocate when tracing is disabled
+type tracer struct {
+	encoder  *json.Encoder
+	err      error // first write error, events are dropped afterwards
+	seq      int64
+	file     string
+	redactor *graph.Redactor
+	nodes    []*sitter.Node // walk stack, the top node is the provenance of emitted scopes and edges
+	cursors  map[*linage.PackageModel]*traceCursor
+}
+
+// traceCursor holds the number of model scopes and edges already traced
+type traceCursor struct {
+	scopes, edges int
+}
+
+func newTracer(w io.Writer) *tracer {
+	return &tracer{encoder: json.NewEncoder(w), cursors: map[*linage.PackageModel]*traceCursor{}}
+}
+
+// emit writes an event with the next sequence number
+func (t *tracer) emit(event *TraceEvent) {
+	if t.err != nil {
+		return
+	}
+	t.seq++
+	event.Seq = t.seq
+	if event.File == "" {
+		event.File = t.file
+	}
+	t.err = t.encoder.Encode(event)
+}
+
+// setRedactor redacts snippets with a copy of the analyzer redactor, so that tracing does not change its counts
+func (t *tracer) setRedactor(redactor *graph.Redactor) {
+	if redactor != nil {
+		t.redactor = &graph.Redactor{Patterns: redactor.Patterns, MinEntropy: redactor.MinEntropy, MinLength: redactor.MinLength, Allow: redactor.Allow}
+	}
+}
+
+// enter records a node entered by the walk, pending scopes and edges are emitted by the parent
+func (t *tracer) enter(n *sitter.Node, src []byte, model *linage.PackageModel) {
+	t.flush(model, t.via())
+	snippet := string(src[n.StartByte():n.EndByte()])
+	if len(snippet) > maxTraceSnippet {
+		snippet = strings.ToValidUTF8(snippet[:maxTraceSnippet], "") + "..."
+	}
+	if t.redactor != nil {
+		snippet = t.redactor.Redact(t.file, snippet)
+	}
+	t.emit(&TraceEvent{Kind: TraceNode, Node: n.Type(), Start: n.StartByte(), End: n.EndByte(), Snippet: snippet})
+	t.nodes = append(t.nodes, n)
+}
+
+// exit emits scopes and edges created by the node after its last child
+func (t *tracer) exit(model *linage.PackageModel) {
+	t.flush(model, t.via())
+	t.nodes = t.nodes[:len(t.nodes)-1]
+}
+
+// via returns the provenance of the innermost walked node
+func (t *tracer) via() string {
+	if len(t.nodes) == 0 {
+		return ""
+	}
+	n := t.nodes[len(t.nodes)-1]
+	return fmt.Sprintf("%s@%d", n.Type(), n.StartByte())
+}
+
+// flush emits scopes and edges appended to the model since the last flush
+func (t *tracer) flush(model *linage.PackageModel, via string) {
+	cursor, ok := t.cursors[model]
+	if !ok {
+		cursor = &traceCursor{}
+		t.cursors[model] = cursor
+	}
+	for _, scope := range model.Scopes[min(cursor.scopes, len(model.Scopes)):] {
+		t.emit(&TraceEvent{Kind: TraceScope, Scope: scope.ID, Name: scope.Kind, Start: uint32(scope.StartByte), End: uint32(scope.EndByte), Via: via})
+	}
+	for _, edge := range model.DataFlows[min(cursor.edges, len(model.DataFlows)):] {
+		event := &TraceEvent{Kind: TraceEdge, Edge: string(edge.Kind), Scope: edge.Scope, Via: via}
+		if edge.Src != nil {
+			event.Src = edge.Src.ID
+		}
+		if edge.Dst != nil {
+			event.Dst = edge.Dst.ID
+		}
+		t.emit(event)
+	}
+	cursor.scopes, cursor.edges = len(model.Scopes), len(model.DataFlows)
+}
+
+// sync skips edges rewritten in place, e.g. by inlining
+func (t *tracer) sync(model *linage.PackageModel) {
+	if cursor, ok := t.cursors[model]; ok {
+		cursor.scopes, cursor.edges = len(model.Scopes), len(model.DataFlows)
+	}
+}
+
+// release drops the cursor of a finished model
+func (t *tracer) release(model *linage.PackageModel) {
+	delete(t.cursors, model)
+}
+
+// resolved records an identifier with the scope declaring it or the scope it was created in
+func (t *tracer) resolved(id *linage.Identifier, scope *linage.Scope, name string) {
+	declaring := scope
+	for cur := scope; cur != nil; cur = cur.Parent {
+		if cur.Symbols[name] == id {
+			declaring = cur
+			break
+		}
+	}
+	t.emit(&TraceEvent{Kind: TraceIdent, Ident: id.ID, Name: id.Name, Scope: declaring.ID, Start: id.StartByte, Via: t.via()})
+}
+
+// summary records a function summary applied at a call site
+func (t *tracer) summary(fn *linage.Identifier, site *sitter.Node, via string) {
+	event := &TraceEvent{Kind: TraceSummary, Ident: fn.ID, Name: fn.Name, Via: via}
+	if site != nil {
+		event.Node, event.Start, event.End = site.Type(), site.StartByte(), site.EndByte()
+	}
+	t.emit(event)
+}
+
+// traceStep emits scopes and edges added by an analysis step outside the walk
+func (a *Analyzer) traceStep(model *linage.PackageModel, step string) {
+	if a.trace != nil {
+		a.trace.flush(model, step)
+	}
+}
+
+// traceName returns the step name of a plugin
+func traceName(plugin AnalyzerPlugin) string {
+	value := reflect.TypeOf(plugin)
+	for value.Kind() == reflect.Ptr {
+		value = value.Elem()
+	}
+	return "plugin:" + value.Name()
+}
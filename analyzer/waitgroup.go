@@ -0,0 +1,71 @@
+package analyzer
+
+import (
+	"fmt"
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/viant/linager/analyzer/linage"
+	"strings"
+)
+
+// WaitGroupPlugin tracks sync.WaitGroup Done and Wait calls as a synthetic channel of the wait group: Done writes
+// the channel and Wait reads it, so goroutine results are ordered before the code following Wait. It is registered
+// by every analyzer.
+type WaitGroupPlugin struct {
+	resolve func(n *sitter.Node, src []byte, scope *linage.Scope, model *linage.PackageModel) *linage.Identifier
+}
+
+// NewWaitGroupPlugin creates a wait group plugin
+func NewWaitGroupPlugin() *WaitGroupPlugin {
+	return &WaitGroupPlugin{}
+}
+
+// Init keeps the analyzer identifier resolution
+func (p *WaitGroupPlugin) Init(model *linage.PackageModel, options *PluginOptions) {
+	p.resolve = options.Resolve
+}
+
+// NodeTypes limits dispatch to calls
+func (p *WaitGroupPlugin) NodeTypes() []string {
+	return []string{"call_expression"}
+}
+
+// BeforeWalk is a no-op, wait group calls are handled once their operands are resolved
+func (p *WaitGroupPlugin) BeforeWalk(n *sitter.Node, src []byte, scope *linage.Scope, model *linage.PackageModel) {
+}
+
+// AfterWalk maps Done to a write and Wait to a read of the wait group synthetic channel
+func (p *WaitGroupPlugin) AfterWalk(n *sitter.Node, src []byte, scope *linage.Scope, model *linage.PackageModel) {
+	fnNode := n.ChildByFieldName("function")
+	if p.resolve == nil || fnNode == nil || fnNode.Type() != "selector_expression" {
+		return
+	}
+	baseNode, fieldNode := fnNode.ChildByFieldName("operand"), fnNode.ChildByFieldName("field")
+	if baseNode == nil || fieldNode == nil {
+		return
+	}
+	var kind linage.AccessKind
+	switch string(src[fieldNode.StartByte():fieldNode.EndByte()]) {
+	case "Done":
+		kind = linage.Write
+	case "Wait":
+		kind = linage.Read
+	default:
+		return
+	}
+	baseIdent := p.resolve(baseNode, src, scope, model)
+	// simple type check for WaitGroup
+	if !strings.HasSuffix(baseIdent.Type, "WaitGroup") {
+		return
+	}
+	wgKey := fmt.Sprintf("%s::wg", baseIdent.ID)
+	wgChan, ok := model.Idents[wgKey]
+	if !ok {
+		wgChan = &linage.Identifier{ID: wgKey, Name: baseIdent.Name + ".wgChan", Package: baseIdent.Package, File: baseIdent.File, StartByte: baseNode.StartByte()}
+		model.Idents[wgKey] = wgChan
+	}
+	model.DataFlows = append(model.DataFlows, &linage.DataFlowEdge{Src: wgChan, Dst: wgChan, Kind: kind, Scope: scope.ID})
+}
+
+// AfterResolveIdent is a no-op
+func (p *WaitGroupPlugin) AfterResolveIdent(n *sitter.Node, id *linage.Identifier, scope *linage.Scope, model *linage.PackageModel) {
+}
@@ -0,0 +1,349 @@
+// Package linager provides entry points orchestrating the inspector and analyzer for tooling such as CI hooks
+package linager
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	golang "github.com/smacker/go-tree-sitter/golang"
+	"github.com/viant/linager/analyzer"
+	"github.com/viant/linager/inspector"
+	"github.com/viant/linager/inspector/graph"
+	"github.com/viant/linager/inspector/repository"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Check rule identifiers of built-in rules
+const (
+	ParseRuleID = "parse" // source files that fail to parse
+)
+
+// DefaultCheckBudget is the time budget of CI pre-commit checks
+const DefaultCheckBudget = 800 * time.Millisecond
+
+// Finding is a rule violation anchored at a source line
+type Finding struct {
+	Rule    string `json:"rule"`
+	File    string `json:"file"`           // path relative to the checked root
+	Line    int    `json:"line,omitempty"` // 1-based line, 0 when unknown
+	Message string `json:"message"`
+}
+
+// String renders finding as file:line: [rule] message
+func (f *Finding) String() string {
+	location := f.File
+	if f.Line > 0 {
+		location += ":" + strconv.Itoa(f.Line)
+	}
+	return fmt.Sprintf("%s: [%s] %s", location, f.Rule, f.Message)
+}
+
+// CheckTarget holds changed files a rule evaluates
+type CheckTarget struct {
+	Root    string                 // absolute root of checked files
+	Changed []string               // changed source files relative to Root, sorted
+	Files   map[string]*graph.File // inspected changed files by relative path, files failing to parse are absent
+}
+
+// Path returns absolute path of a changed file
+func (t *CheckTarget) Path(file string) string {
+	return filepath.Join(t.Root, file)
+}
+
+// Rule evaluates changed files, rules should return when the context is done
+type Rule interface {
+	ID() string
+	Evaluate(ctx context.Context, target *CheckTarget) ([]*Finding, error)
+}
+
+// RuleFunc adapts a function to a Rule, e.g. for project specific validation rules
+type RuleFunc struct {
+	Name string
+	Fn   func(ctx context.Context, target *CheckTarget) ([]*Finding, error)
+}
+
+// ID returns rule id
+func (r *RuleFunc) ID() string {
+	return r.Name
+}
+
+// Evaluate calls the rule function
+func (r *RuleFunc) Evaluate(ctx context.Context, target *CheckTarget) ([]*Finding, error) {
+	return r.Fn(ctx, target)
+}
+
+// CheckOptions configures Check
+type CheckOptions struct {
+	Budget time.Duration // time budget, no limit when zero
+	Rules  []Rule        // rules evaluated in order after parsing
+	Config *graph.Config // inspector config, graph.DefaultConfig when nil
+}
+
+// CheckResult reports findings of a check
+type CheckResult struct {
+	Passed   bool          `json:"passed"`            // no findings, of completed checks only when Partial
+	Partial  bool          `json:"partial,omitempty"` // the budget was exceeded before all checks completed
+	Findings []*Finding    `json:"findings,omitempty"`
+	Skipped  []string      `json:"skipped,omitempty"` // rules not completed within the budget
+	Elapsed  time.Duration `json:"elapsed"`
+}
+
+// Check parses changed files (absolute or relative to root) and evaluates rules on them within the budget; files
+// failing to parse are reported as parse findings and excluded from rule evaluation, files without an inspector or
+// deleted files are ignored. When the budget is exceeded Check returns at once with the findings gathered so far, the
+// result is marked Partial and rules not completed are listed as Skipped. A rule error fails the check.
+func Check(ctx context.Context, root string, changedFiles []string, opts *CheckOptions) (*CheckResult, error) {
+	if opts == nil {
+		opts = &CheckOptions{}
+	}
+	started := time.Now()
+	if opts.Budget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Budget)
+		defer cancel()
+	}
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	config := opts.Config
+	if config == nil {
+		config = graph.DefaultConfig()
+	}
+	result := &CheckResult{}
+	target := &CheckTarget{Root: absRoot, Files: map[string]*graph.File{}}
+	factory := inspector.NewFactory(config)
+	packages := map[string]string{}
+	detector := repository.New()
+	for _, changed := range changedFiles {
+		if ctx.Err() != nil {
+			break
+		}
+		relative, ok := relativePath(absRoot, changed)
+		if !ok {
+			continue
+		}
+		if info, err := os.Stat(target.Path(relative)); err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		if _, err := factory.GetInspector(relative); err != nil {
+			continue
+		}
+		target.Changed = append(target.Changed, relative)
+		file, err := factory.InspectFile(target.Path(relative))
+		if err != nil {
+			finding := &Finding{Rule: ParseRuleID, File: relative, Message: err.Error()}
+			var parseErr *graph.ErrParse
+			if errors.As(err, &parseErr) {
+				finding.Line = parseErr.Line
+			}
+			result.Findings = append(result.Findings, finding)
+			continue
+		}
+		if importPath := packageImportPath(ctx, detector, packages, target.Path(relative)); importPath != "" {
+			file.ImportPath = importPath
+		}
+		target.Files[relative] = file
+	}
+	sort.Strings(target.Changed)
+	for i, rule := range opts.Rules {
+		if ctx.Err() != nil {
+			result.skip(opts.Rules[i:])
+			break
+		}
+		findings, done, err := evaluate(ctx, rule, target)
+		result.Findings = append(result.Findings, findings...)
+		if !done {
+			result.skip(opts.Rules[i:])
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("rule %s failed: %w", rule.ID(), err)
+		}
+	}
+	result.Partial = ctx.Err() != nil || len(result.Skipped) > 0
+	result.Passed = len(result.Findings) == 0
+	sort.SliceStable(result.Findings, func(i, j int) bool {
+		if result.Findings[i].File != result.Findings[j].File {
+			return result.Findings[i].File < result.Findings[j].File
+		}
+		return result.Findings[i].Line < result.Findings[j].Line
+	})
+	result.Elapsed = time.Since(started)
+	return result, nil
+}
+
+// skip records rules not completed within the budget
+func (r *CheckResult) skip(rules []Rule) {
+	for _, rule := range rules {
+		r.Skipped = append(r.Skipped, rule.ID())
+	}
+}
+
+// evaluate runs a rule until it returns or the context is done, a rule ignoring the context is abandoned
+func evaluate(ctx context.Context, rule Rule, target *CheckTarget) ([]*Finding, bool, error) {
+	type outcome struct {
+		findings []*Finding
+		err      error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		findings, err := rule.Evaluate(ctx, target)
+		done <- outcome{findings: findings, err: err}
+	}()
+	select {
+	case result := <-done:
+		if ctx.Err() != nil && result.err != nil && errors.Is(result.err, ctx.Err()) {
+			return result.findings, false, nil
+		}
+		return result.findings, true, result.err
+	case <-ctx.Done():
+		return nil, false, nil
+	}
+}
+
+// packageImportPath returns Go import path of a file package from the enclosing go.mod, empty when unknown; import
+// paths are cached by directory
+func packageImportPath(ctx context.Context, detector *repository.Detector, packages map[string]string, path string) string {
+	dir := filepath.Dir(path)
+	if importPath, ok := packages[dir]; ok {
+		return importPath
+	}
+	importPath := ""
+	if project, err := detector.DetectProject(ctx, dir); err == nil && project.GoModule != nil {
+		importPath = project.GoModule.Mod.Path
+		if project.RelativePath != "." {
+			importPath += "/" + project.RelativePath
+		}
+	}
+	packages[dir] = importPath
+	return importPath
+}
+
+// relativePath returns a changed file path relative to root, false for files outside of root
+func relativePath(root, changed string) (string, bool) {
+	if !filepath.IsAbs(changed) {
+		changed = filepath.Join(root, changed)
+	}
+	relative, err := filepath.Rel(root, changed)
+	if err != nil || relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(relative), true
+}
+
+// ImportRule is an architecture rule denying imports of packages matching Deny prefixes from changed files of
+// packages matching From prefixes (all packages when empty), e.g. domain packages must not import transport
+type ImportRule struct {
+	Name string   // rule id
+	From []string // import path prefixes of constrained packages
+	Deny []string // denied import path prefixes
+}
+
+// ID returns rule id
+func (r *ImportRule) ID() string {
+	return r.Name
+}
+
+// Evaluate reports denied imports with their import declaration line
+func (r *ImportRule) Evaluate(ctx context.Context, target *CheckTarget) ([]*Finding, error) {
+	var findings []*Finding
+	for _, name := range target.Changed {
+		if err := ctx.Err(); err != nil {
+			return findings, err
+		}
+		file := target.Files[name]
+		if file == nil || (len(r.From) > 0 && !hasPrefix(file.ImportPath, r.From)) {
+			continue
+		}
+		for _, imported := range file.Imports {
+			if !hasPrefix(imported.Path, r.Deny) {
+				continue
+			}
+			findings = append(findings, &Finding{
+				Rule:    r.Name,
+				File:    name,
+				Line:    lineOf(target.Path(name), strconv.Quote(imported.Path)),
+				Message: fmt.Sprintf("%s must not import %s", file.ImportPath, imported.Path),
+			})
+		}
+	}
+	return findings, nil
+}
+
+// hasPrefix reports whether an import path equals or is nested in one of the prefixes
+func hasPrefix(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if path == prefix || strings.HasPrefix(path, strings.TrimSuffix(prefix, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// lineOf returns the first line of a file containing text, 0 when not found
+func lineOf(path, text string) int {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		if strings.Contains(scanner.Text(), text) {
+			return line
+		}
+	}
+	return 0
+}
+
+// TaintRule reports labeled data (see graph.RuleClassifier) of changed Go files reaching logging or external calls
+type TaintRule struct {
+	Name       string           // rule id, taint when empty
+	Classifier graph.Classifier // graph.NewRuleClassifier when nil
+}
+
+// ID returns rule id
+func (r *TaintRule) ID() string {
+	if r.Name == "" {
+		return "taint"
+	}
+	return r.Name
+}
+
+// Evaluate analyzes changed Go files one by one, as packages of one file, and reports sensitive sinks
+func (r *TaintRule) Evaluate(ctx context.Context, target *CheckTarget) ([]*Finding, error) {
+	classifier := r.Classifier
+	if classifier == nil {
+		classifier = graph.NewRuleClassifier()
+	}
+	var findings []*Finding
+	for _, name := range target.Changed {
+		if err := ctx.Err(); err != nil {
+			return findings, err
+		}
+		if target.Files[name] == nil || filepath.Ext(name) != ".go" {
+			continue
+		}
+		options := []analyzer.Option{analyzer.WithLanguage(golang.GetLanguage()), analyzer.WithInterprocedural(), analyzer.WithClassifier(classifier)}
+		model, err := analyzer.NewAnalyzer(options...).AnalyzeFile(ctx, target.Path(name))
+		if err != nil {
+			return findings, err
+		}
+		for _, sink := range analyzer.NewSensitivityReport(model).Sinks {
+			findings = append(findings, &Finding{
+				Rule:    r.ID(),
+				File:    name,
+				Line:    sink.Line,
+				Message: fmt.Sprintf("%s data reaches %s call %s in %s", strings.Join(sink.Labels, ","), sink.Kind, sink.Ref, sink.Scope),
+			})
+		}
+	}
+	return findings, nil
+}
@@ -0,0 +1,102 @@
+package linager
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCheck(t *testing.T) {
+	root := t.TempDir()
+	sources := map[string]string{
+		"domain/user.go": `package domain
+
+import (
+	"log"
+	"net/http"
+)
+
+type User struct {
+	Email string
+}
+
+func Register(u *User) {
+	log.Printf("registered %v", u.Email)
+	_ = http.StatusOK
+}
+`,
+		"domain/broken.go": `package domain
+
+func Broken( {
+}
+`,
+		"domain/notes.txt": "not a source file",
+		"go.mod":           "module example.com/app\n",
+	}
+	for name, source := range sources {
+		path := filepath.Join(root, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NoError(t, os.WriteFile(path, []byte(source), 0o644))
+	}
+	changed := []string{"domain/user.go", "domain/broken.go", "domain/notes.txt", "domain/deleted.go"}
+	architecture := &ImportRule{Name: "arch-domain", From: []string{"example.com/app/domain"}, Deny: []string{"net/http"}}
+
+	t.Run("all rules", func(t *testing.T) {
+		result, err := Check(context.Background(), root, changed, &CheckOptions{Rules: []Rule{architecture, &TaintRule{}}})
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.False(t, result.Passed)
+		assert.False(t, result.Partial)
+		var actual []string
+		for _, finding := range result.Findings {
+			actual = append(actual, finding.Rule+" "+finding.File)
+		}
+		assert.ElementsMatch(t, []string{"parse domain/broken.go", "arch-domain domain/user.go", "taint domain/user.go"}, actual)
+		for _, finding := range result.Findings {
+			switch finding.Rule {
+			case ParseRuleID:
+				assert.Equal(t, 3, finding.Line)
+			case "arch-domain":
+				assert.Equal(t, 5, finding.Line)
+				assert.Equal(t, "example.com/app/domain must not import net/http", finding.Message)
+			case "taint":
+				assert.Equal(t, 13, finding.Line)
+				assert.Contains(t, finding.Message, "pii")
+			}
+		}
+	})
+
+	t.Run("budget exceeded", func(t *testing.T) {
+		slow := &RuleFunc{Name: "slow", Fn: func(ctx context.Context, target *CheckTarget) ([]*Finding, error) {
+			time.Sleep(5 * time.Second)
+			return []*Finding{{Rule: "slow", File: "domain/user.go", Message: "too late"}}, nil
+		}}
+		started := time.Now()
+		result, err := Check(context.Background(), root, changed, &CheckOptions{Budget: 300 * time.Millisecond, Rules: []Rule{architecture, slow, &TaintRule{}}})
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Less(t, time.Since(started), 2*time.Second)
+		assert.True(t, result.Partial)
+		assert.False(t, result.Passed)
+		assert.Equal(t, []string{"slow", "taint"}, result.Skipped)
+		var rules []string
+		for _, finding := range result.Findings {
+			rules = append(rules, finding.Rule)
+		}
+		assert.ElementsMatch(t, []string{ParseRuleID, "arch-domain"}, rules)
+	})
+
+	t.Run("passed", func(t *testing.T) {
+		result, err := Check(context.Background(), root, []string{"domain/notes.txt"}, &CheckOptions{Budget: DefaultCheckBudget, Rules: []Rule{architecture}})
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.True(t, result.Passed)
+		assert.Empty(t, result.Findings)
+	})
+}
@@ -0,0 +1,493 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/gdamore/tcell/v2"
+	golang "github.com/smacker/go-tree-sitter/golang"
+	"github.com/viant/linager"
+	"github.com/viant/linager/analyzer"
+	"github.com/viant/linager/analyzer/linage"
+	"github.com/viant/linager/inspector"
+	"github.com/viant/linager/inspector/graph"
+	"github.com/viant/linager/inspector/index"
+	"github.com/viant/linager/inspector/repository"
+	"github.com/viant/linager/metrics"
+	"github.com/viant/linager/tui"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const usage = `usage: linager <command> [flags]
+
+commands:
+  inspect [--out file] [--walk-order] [--multi-language] [--query ref|name] [--no-index] [--coverage] [root]
+  symbol [--format text|json] [--multi-language] [--no-index] ref|name [root]
+  documents [--pkg path] [--out file] [--multi-language] [--no-index] [root]
+  scan [--format table|json] [--out file] [--top n] [root]
+  metrics [--format prom|openmetrics|json] [--out file] [--lang go|java|javascript] [--interprocedural] [root]
+  check [--since rev] [--budget duration] [--format text|json] [--deny [from=]prefix]... [--taint] [root]
+  tui [--multi-language] [--no-index] [--no-lineage] [--interprocedural] [root]
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	var err error
+	switch os.Args[1] {
+	case "inspect":
+		err = runInspect(os.Args[2:], os.Stdout)
+	case "symbol":
+		err = runSymbol(os.Args[2:], os.Stdout)
+	case "documents":
+		err = runDocuments(context.Background(), os.Args[2:], os.Stdout)
+	case "metrics":
+		err = runMetrics(context.Background(), os.Args[2:], os.Stdout)
+	case "scan":
+		err = runScan(os.Args[2:], os.Stdout)
+	case "check":
+		err = runCheck(context.Background(), os.Args[2:], os.Stdout)
+	case "tui":
+		err = runTUI(context.Background(), os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// runInspect inspects a project (the working directory by default) and writes its graph as JSON to a file or stdout,
+// normalized independently of the file system walk order unless --walk-order is set; with --multi-language all
+// languages found by a composition scan are inspected; with --query only elements matching a reference or symbol name
+// are written, served by the persisted project index unless --no-index is set; with --coverage declarations the
+// inspectors did not model are summarized on stderr
+func runInspect(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("inspect", flag.ContinueOnError)
+	out := flags.String("out", "", "output file, stdout when empty")
+	walkOrder := flags.Bool("walk-order", false, "keep inspection walk order instead of the stable order")
+	multiLanguage := flags.Bool("multi-language", false, "inspect all languages found by a composition scan")
+	query := flags.String("query", "", "canonical reference or symbol name of written elements")
+	noIndex := flags.Bool("no-index", false, "inspect the project instead of using the persisted index")
+	coverage := flags.Bool("coverage", false, "summarize parse coverage of inspected declarations on stderr")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	root := "."
+	if flags.NArg() > 0 {
+		root = flags.Arg(0)
+	}
+	config := graph.DefaultConfig()
+	config.ParseCoverage = *coverage
+	var project *graph.Project
+	var data []byte
+	var err error
+	switch {
+	case *query != "" && *coverage:
+		return fmt.Errorf("--coverage can not be combined with --query")
+	case *query != "":
+		var symbols index.Symbols
+		if project, symbols, err = loadProject(root, *multiLanguage, *noIndex); err != nil {
+			return err
+		}
+		var results []*queryResult
+		for _, symbol := range symbols.Lookup(*query) {
+			results = append(results, &queryResult{Symbol: symbol, Element: refElement(project.ByRef(symbol.Ref))})
+		}
+		if len(results) == 0 {
+			return &graph.ErrNotFound{Kind: "symbol", Name: *query}
+		}
+		data, err = json.MarshalIndent(results, "", "  ")
+	case *walkOrder:
+		if project, err = inspectProject(root, *multiLanguage, config); err != nil {
+			return err
+		}
+		data, err = json.MarshalIndent(project, "", "  ")
+	default:
+		if project, err = inspectProject(root, *multiLanguage, config); err != nil {
+			return err
+		}
+		data, err = project.MarshalStable()
+	}
+	if err != nil {
+		return err
+	}
+	writer := stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		writer = file
+	}
+	if _, err = writer.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	if *coverage {
+		writeCoverage(os.Stderr, project.ParseCoverage())
+	}
+	return nil
+}
+
+// writeCoverage writes a parse coverage summary followed by unhandled source constructs
+func writeCoverage(w io.Writer, coverage *graph.ParseCoverage) {
+	if coverage == nil {
+		fmt.Fprintln(w, "coverage: no declarations inspected")
+		return
+	}
+	fmt.Fprintln(w, "coverage: "+coverage.String())
+	for _, node := range coverage.Unhandled {
+		fmt.Fprintln(w, "  unhandled "+node.String())
+	}
+}
+
+// queryResult is an element written by inspect --query
+type queryResult struct {
+	*index.Symbol
+	Element interface{} `json:",omitempty"`
+}
+
+// refElement returns the most specific element of a resolved reference
+func refElement(target *graph.RefTarget) interface{} {
+	switch {
+	case target == nil:
+		return nil
+	case target.Parameter != nil:
+		return target.Parameter
+	case target.Field != nil:
+		return target.Field
+	case target.Function != nil:
+		return target.Function
+	case target.Variable != nil:
+		return target.Variable
+	case target.Constant != nil:
+		return target.Constant
+	case target.Type != nil:
+		return target.Type
+	}
+	return nil
+}
+
+// detectProject returns the inspected project of a root, with a composition scan when multiLanguage is set
+func detectProject(root string, multiLanguage bool) (*repository.Project, error) {
+	detected, err := repository.New().DetectProject(context.Background(), root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect project: %w", err)
+	}
+	target := &repository.Project{RootPath: root, Type: detected.Type, Name: detected.Name}
+	if multiLanguage {
+		if target.Composition, err = repository.Scan(root); err != nil {
+			return nil, err
+		}
+	}
+	return target, nil
+}
+
+// inspectProject inspects a project root
+func inspectProject(root string, multiLanguage bool, config *graph.Config) (*graph.Project, error) {
+	target, err := detectProject(root, multiLanguage)
+	if err != nil {
+		return nil, err
+	}
+	project, err := inspector.NewFactory(config).InspectProject(target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect project %s: %w", root, err)
+	}
+	return project, nil
+}
+
+// loadProject returns the project graph of a root with its symbols, served by the persisted project index (see
+// index.Open) unless noIndex is set
+func loadProject(root string, multiLanguage, noIndex bool) (*graph.Project, index.Symbols, error) {
+	if noIndex {
+		project, err := inspectProject(root, multiLanguage, graph.DefaultConfig())
+		if err != nil {
+			return nil, nil, err
+		}
+		return project, index.NewSymbols(project), nil
+	}
+	target, err := detectProject(root, multiLanguage)
+	if err != nil {
+		return nil, nil, err
+	}
+	projectIndex, err := index.Open(target, inspector.NewFactory(graph.DefaultConfig()))
+	if err != nil {
+		return nil, nil, err
+	}
+	return projectIndex.Project, projectIndex.Symbols, nil
+}
+
+// runSymbol writes symbols of a project (the working directory by default) matching a canonical reference or symbol
+// name as ref, kind and file lines or JSON, served by the persisted project index unless --no-index is set
+func runSymbol(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("symbol", flag.ContinueOnError)
+	format := flags.String("format", "text", "output format: text or json")
+	multiLanguage := flags.Bool("multi-language", false, "inspect all languages found by a composition scan")
+	noIndex := flags.Bool("no-index", false, "inspect the project instead of using the persisted index")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return fmt.Errorf("missing symbol reference or name")
+	}
+	root := "."
+	if flags.NArg() > 1 {
+		root = flags.Arg(1)
+	}
+	_, symbols, err := loadProject(root, *multiLanguage, *noIndex)
+	if err != nil {
+		return err
+	}
+	matched := symbols.Lookup(flags.Arg(0))
+	switch *format {
+	case "text":
+		for _, symbol := range matched {
+			fmt.Fprintf(stdout, "%s\t%s\t%s\n", symbol.Ref, symbol.Kind, symbol.File)
+		}
+	case "json":
+		data, err := json.MarshalIndent(matched, "", "  ")
+		if err != nil {
+			return err
+		}
+		if _, err = stdout.Write(append(data, '\n')); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported symbol format: %s", *format)
+	}
+	if len(matched) == 0 {
+		return &graph.ErrNotFound{Kind: "symbol", Name: flags.Arg(0)}
+	}
+	return nil
+}
+
+// runDocuments writes embedding documents of a project (the working directory by default) as JSON lines to a file or
+// stdout, served by the persisted project index unless --no-index is set
+func runDocuments(ctx context.Context, args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("documents", flag.ContinueOnError)
+	pkgPath := flags.String("pkg", "", "path prefix of documented packages, all when empty")
+	out := flags.String("out", "", "output file, stdout when empty")
+	multiLanguage := flags.Bool("multi-language", false, "inspect all languages found by a composition scan")
+	noIndex := flags.Bool("no-index", false, "inspect the project instead of using the persisted index")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	root := "."
+	if flags.NArg() > 0 {
+		root = flags.Arg(0)
+	}
+	project, _, err := loadProject(root, *multiLanguage, *noIndex)
+	if err != nil {
+		return err
+	}
+	writer := stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		writer = file
+	}
+	return project.WriteJSONL(ctx, *pkgPath, writer)
+}
+
+// runScan writes the composition of a source tree (the working directory by default) as tables or JSON
+func runScan(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("scan", flag.ContinueOnError)
+	format := flags.String("format", "table", "output format: table or json")
+	out := flags.String("out", "", "output file, stdout when empty")
+	top := flags.Int("top", 10, "number of largest directories listed by the table format, all when 0")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	root := "."
+	if flags.NArg() > 0 {
+		root = flags.Arg(0)
+	}
+	report, err := repository.Scan(root)
+	if err != nil {
+		return err
+	}
+	writer := stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		writer = file
+	}
+	switch *format {
+	case "table":
+		return report.WriteTable(writer, *top)
+	case "json":
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = writer.Write(append(data, '\n'))
+		return err
+	}
+	return fmt.Errorf("unsupported scan format: %s", *format)
+}
+
+// runMetrics collects metrics of a project (the working directory by default) and writes them to a file or stdout
+func runMetrics(ctx context.Context, args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("metrics", flag.ContinueOnError)
+	format := flags.String("format", metrics.FormatPrometheus, "export format: prom, openmetrics or json")
+	out := flags.String("out", "", "output file, stdout when empty")
+	language := flags.String("lang", "go", "lineage language: go, java or javascript")
+	interprocedural := flags.Bool("interprocedural", false, "enable inter-procedural lineage analysis")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	root := "."
+	if flags.NArg() > 0 {
+		root = flags.Arg(0)
+	}
+	var options []analyzer.Option
+	if *interprocedural {
+		options = append(options, analyzer.WithInterprocedural())
+	}
+	collector, err := metrics.Collect(ctx, root, *language, graph.DefaultConfig(), options...)
+	if err != nil {
+		return err
+	}
+	writer := stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		writer = file
+	}
+	return collector.Snapshot(time.Now()).Export(writer, *format)
+}
+
+// denyRules collects --deny flags as architecture import rules, from=prefix constrains packages under from only
+type denyRules []linager.Rule
+
+// String returns flag value
+func (d *denyRules) String() string {
+	return fmt.Sprint(len(*d))
+}
+
+// Set adds an import rule
+func (d *denyRules) Set(value string) error {
+	rule := &linager.ImportRule{Name: "architecture"}
+	from, deny, ok := strings.Cut(value, "=")
+	if ok {
+		rule.From = []string{from}
+	} else {
+		deny = from
+	}
+	if deny == "" {
+		return fmt.Errorf("invalid deny rule: %s", value)
+	}
+	rule.Deny = []string{deny}
+	*d = append(*d, rule)
+	return nil
+}
+
+// runCheck checks files of a git work tree (the working directory by default) changed since a revision within a time
+// budget and writes findings as file:line: [rule] message lines or JSON; findings fail the command
+func runCheck(ctx context.Context, args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("check", flag.ContinueOnError)
+	since := flags.String("since", "HEAD", "revision changed files are listed against")
+	budget := flags.Duration("budget", linager.DefaultCheckBudget, "time budget, no limit when 0")
+	format := flags.String("format", "text", "output format: text or json")
+	taint := flags.Bool("taint", false, "report sensitive data reaching logging or external calls")
+	var rules denyRules
+	flags.Var(&rules, "deny", "denied import path prefix, optionally of packages under a prefix: from=prefix")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	root := "."
+	if flags.NArg() > 0 {
+		root = flags.Arg(0)
+	}
+	if *taint {
+		rules = append(rules, &linager.TaintRule{})
+	}
+	output, err := exec.CommandContext(ctx, "git", "-C", root, "diff", "--name-only", "--relative", *since).Output()
+	if err != nil {
+		return fmt.Errorf("failed to list files changed since %s: %w", *since, err)
+	}
+	changed := strings.Fields(string(output))
+	result, err := linager.Check(ctx, root, changed, &linager.CheckOptions{Budget: *budget, Rules: rules})
+	if err != nil {
+		return err
+	}
+	switch *format {
+	case "text":
+		for _, finding := range result.Findings {
+			fmt.Fprintln(stdout, finding)
+		}
+		if result.Partial {
+			fmt.Fprintf(stdout, "partial: budget %v exceeded, skipped rules: %s\n", *budget, strings.Join(result.Skipped, ","))
+		}
+	case "json":
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		if _, err = stdout.Write(append(data, '\n')); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported check format: %s", *format)
+	}
+	if !result.Passed {
+		return fmt.Errorf("check failed: %d finding(s)", len(result.Findings))
+	}
+	return nil
+}
+
+// runTUI explores a project (the working directory by default) in an interactive terminal UI, served by the persisted
+// project index unless --no-index is set; Go lineage is analyzed for upstream and downstream links unless --no-lineage
+// is set
+func runTUI(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("tui", flag.ContinueOnError)
+	multiLanguage := flags.Bool("multi-language", false, "inspect all languages found by a composition scan")
+	noIndex := flags.Bool("no-index", false, "inspect the project instead of using the persisted index")
+	noLineage := flags.Bool("no-lineage", false, "skip lineage analysis, upstream and downstream links are empty")
+	interprocedural := flags.Bool("interprocedural", false, "enable inter-procedural lineage analysis")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	root := "."
+	if flags.NArg() > 0 {
+		root = flags.Arg(0)
+	}
+	project, symbols, err := loadProject(root, *multiLanguage, *noIndex)
+	if err != nil {
+		return err
+	}
+	var model *linage.PackageModel
+	if !*noLineage {
+		options := []analyzer.Option{analyzer.WithLanguage(golang.GetLanguage()), analyzer.WithMatcher(analyzer.GolangFiles)}
+		if *interprocedural {
+			options = append(options, analyzer.WithInterprocedural())
+		}
+		if model, err = analyzer.NewAnalyzer(options...).AnalyzeAll(ctx, root); err != nil {
+			return fmt.Errorf("failed to analyze lineage of %s: %w", root, err)
+		}
+	}
+	screen, err := tcell.NewScreen()
+	if err != nil {
+		return fmt.Errorf("failed to open terminal: %w", err)
+	}
+	return tui.Run(screen, tui.NewExplorer(project, symbols, model))
+}
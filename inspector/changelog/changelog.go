@@ -0,0 +1,403 @@
+package changelog
+
+import (
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/viant/linager/inspector/graph"
+)
+
+// Symbol kinds
+const (
+	KindType     = "type"
+	KindFunction = "function"
+	KindMethod   = "method"
+	KindField    = "field"
+	KindConstant = "constant"
+	KindVariable = "variable"
+)
+
+// Change kinds of entries
+const (
+	ChangeRemoved    = "removed"    // Symbol removed or unexported
+	ChangeChanged    = "changed"    // Declaration changed, e.g. function parameters or field type
+	ChangeAdded      = "added"      // Symbol added or exported
+	ChangeDeprecated = "deprecated" // Symbol newly marked as deprecated
+)
+
+// defaultTitle is the document heading without Options.Title
+const defaultTitle = "Changelog"
+
+// Options controls changelog generation
+type Options struct {
+	Title           string            // Document heading, Changelog by default
+	Areas           map[string]string // Area names keyed by import path, packages map to the area of the longest matching import path prefix
+	ExcludeInternal bool              // Count changes of internal packages (an internal path element) as internal changes only
+}
+
+// Entry describes a public API change
+type Entry struct {
+	Kind    string // KindType, KindFunction, KindMethod, KindField, KindConstant or KindVariable
+	Change  string // ChangeRemoved, ChangeChanged, ChangeAdded or ChangeDeprecated
+	Package string // Canonical import path, see graph.CanonicalImportPath
+	Symbol  string // Package qualified name, e.g. billing.Invoice.Total
+	Area    string // Area name of the package, the import path when not mapped
+	Before  string // Declaration before the change, empty for additions
+	After   string // Declaration after the change, empty for removals
+	Doc     string // First doc comment sentence of added symbols, deprecation notice of deprecated ones
+	Note    string // Additional context, e.g. the new name of a renamed function
+}
+
+// InternalChanges counts changes outside the public API
+type InternalChanges struct {
+	Implementations int // Exported functions and methods with changed bodies and unchanged signatures
+	Added           int // Unexported or internal symbols added
+	Removed         int // Unexported or internal symbols removed
+	Changed         int // Unexported or internal symbols with changed declarations or bodies
+	Moved           int // Functions and methods moved to another file or package, see graph.DiffProjects
+	Renamed         int // Functions and methods renamed, see graph.DiffProjects
+}
+
+// Changelog represents release notes of two project versions
+type Changelog struct {
+	Breaking   []*Entry // Removed and changed public symbols, methods added to public interfaces
+	Added      []*Entry // Public symbols added, members of added types are covered by their type
+	Deprecated []*Entry // Public symbols newly marked as deprecated
+	Internal   InternalChanges
+	Document   string // Markdown release notes, sections grouped by area and ordered by area and symbol
+}
+
+// symbol is a declaration of a project version
+type symbol struct {
+	key        string // canonical import path and qualified name, e.g. example.com/shop/billing.Invoice.Total
+	kind       string
+	pkg        string // canonical import path
+	name       string // package qualified name, e.g. billing.Invoice.Total
+	parent     string // key of the type declaring a field or method
+	public     bool   // exported symbol of an exported type outside excluded internal packages
+	iface      bool   // method of an interface
+	signature  string
+	doc        string
+	deprecated string // deprecation notice, empty when not deprecated
+	body       int32  // normalized body hash of functions and methods
+}
+
+// Generate compares public symbols (exported declarations of exported types) of two project versions: removed and
+// changed declarations are breaking, methods added to existing interfaces included; symbols are matched by canonical
+// import path (see graph.CanonicalImportPath) so that versions checked out in different locations compare equal
+func Generate(prev, next *graph.Project, opts *Options) *Changelog {
+	if opts == nil {
+		opts = &Options{}
+	}
+	ret := &Changelog{}
+	prevSymbols, nextSymbols := symbols(prev, opts), symbols(next, opts)
+	diff := graph.DiffProjects(prev, next)
+	ret.Internal.Moved, ret.Internal.Renamed = len(diff.Moved), len(diff.Renamed)
+	retargets := diff.Retargets()
+
+	for _, key := range sortedKeys(prevSymbols, nextSymbols) {
+		before, after := prevSymbols[key], nextSymbols[key]
+		switch {
+		case after == nil:
+			if !before.public {
+				ret.Internal.Removed++
+				continue
+			}
+			if prevSymbols[before.parent] != nil && nextSymbols[before.parent] == nil {
+				continue // covered by the removed type
+			}
+			entry := newEntry(before, ChangeRemoved, opts)
+			entry.Before = before.signature
+			if change, ok := retargets[key]; ok && change.Kind == graph.ChangeRenamed {
+				entry.Note = "renamed to `" + qualifiedName(nextSymbols[change.New.Path], change.New.Name) + "`"
+			}
+			ret.Breaking = append(ret.Breaking, entry)
+		case before == nil:
+			if !after.public {
+				ret.Internal.Added++
+				continue
+			}
+			if after.parent != "" && prevSymbols[after.parent] == nil {
+				continue // covered by the added type
+			}
+			entry := newEntry(after, ChangeAdded, opts)
+			entry.After = after.signature
+			if after.iface {
+				entry.Note = "implementations of the interface have to add it"
+				ret.Breaking = append(ret.Breaking, entry)
+				continue
+			}
+			entry.Doc = after.doc
+			ret.Added = append(ret.Added, entry)
+		case before.public && !after.public:
+			entry := newEntry(before, ChangeRemoved, opts)
+			entry.Before = before.signature
+			entry.Note = "no longer exported"
+			ret.Breaking = append(ret.Breaking, entry)
+		case !before.public && after.public:
+			entry := newEntry(after, ChangeAdded, opts)
+			entry.After, entry.Doc = after.signature, after.doc
+			ret.Added = append(ret.Added, entry)
+		case !before.public:
+			if before.signature != after.signature || before.body != after.body {
+				ret.Internal.Changed++
+			}
+		default:
+			if before.signature != after.signature {
+				entry := newEntry(after, ChangeChanged, opts)
+				entry.Before, entry.After = before.signature, after.signature
+				ret.Breaking = append(ret.Breaking, entry)
+			} else if before.body != after.body {
+				ret.Internal.Implementations++
+			}
+			if before.deprecated == "" && after.deprecated != "" {
+				entry := newEntry(after, ChangeDeprecated, opts)
+				entry.Doc = after.deprecated
+				ret.Deprecated = append(ret.Deprecated, entry)
+			}
+		}
+	}
+	for _, entries := range [][]*Entry{ret.Breaking, ret.Added, ret.Deprecated} {
+		sortEntries(entries)
+	}
+	ret.Document = render(ret, opts)
+	return ret
+}
+
+// newEntry creates an entry of a symbol
+func newEntry(s *symbol, change string, opts *Options) *Entry {
+	return &Entry{Kind: s.kind, Change: change, Package: s.pkg, Symbol: s.name, Area: area(s.pkg, opts.Areas)}
+}
+
+// qualifiedName returns the package qualified name of a symbol, name when the symbol is unknown
+func qualifiedName(s *symbol, name string) string {
+	if s == nil {
+		return name
+	}
+	return s.name
+}
+
+// symbols collects declarations of a project keyed by canonical import path and qualified name
+func symbols(project *graph.Project, opts *Options) map[string]*symbol {
+	result := map[string]*symbol{}
+	if project == nil {
+		return result
+	}
+	for _, pkg := range project.Packages {
+		for _, file := range pkg.FileSet {
+			importPath := graph.CanonicalImportPath(project, pkg, file)
+			pkgName := file.Package
+			if pkgName == "" {
+				pkgName = path.Base(importPath)
+			}
+			internal := opts.ExcludeInternal && isInternal(importPath)
+			add := func(s *symbol) {
+				s.key, s.pkg = importPath+"."+s.name, importPath
+				s.name = pkgName + "." + s.name
+				s.public = s.public && !internal
+				if _, ok := result[s.key]; !ok {
+					result[s.key] = s
+				}
+			}
+			addMethod := func(typeName string, typeExported bool, iface bool, method *graph.Function) {
+				add(&symbol{kind: KindMethod, name: typeName + "." + method.Name, parent: importPath + "." + typeName,
+					public: typeExported && method.IsExported, iface: iface, signature: signature(method),
+					doc: graph.FirstSentence(graph.CommentText(method.Comment)), deprecated: deprecation(graph.CommentText(method.Comment), graph.CommentText(method.Annotation)),
+					body: bodyHash(method)})
+			}
+			for _, aType := range file.Types {
+				if aType == nil {
+					continue
+				}
+				add(&symbol{kind: KindType, name: aType.Name, public: aType.IsExported, signature: typeSignature(aType),
+					doc: graph.FirstSentence(graph.CommentText(aType.Comment)), deprecated: deprecation(graph.CommentText(aType.Comment), graph.CommentText(aType.Annotation))})
+				for _, field := range aType.Fields {
+					add(&symbol{kind: KindField, name: aType.Name + "." + fieldName(field), parent: importPath + "." + aType.Name,
+						public: aType.IsExported && field.IsExported, signature: fieldSignature(field),
+						doc: graph.FirstSentence(field.Comment), deprecated: deprecation(field.Comment, field.Annotation)})
+				}
+				for _, method := range aType.Methods {
+					addMethod(aType.Name, aType.IsExported, aType.Kind == reflect.Interface, method)
+				}
+			}
+			for _, function := range file.Functions {
+				if function == nil {
+					continue
+				}
+				if receiver := strings.TrimPrefix(function.Receiver, "*"); receiver != "" {
+					addMethod(receiver, graph.IsExported(receiver), false, function)
+					continue
+				}
+				add(&symbol{kind: KindFunction, name: function.Name, public: function.IsExported, signature: signature(function),
+					doc: graph.FirstSentence(graph.CommentText(function.Comment)), deprecated: deprecation(graph.CommentText(function.Comment), graph.CommentText(function.Annotation)),
+					body: bodyHash(function)})
+			}
+			for _, constant := range file.Constants {
+				add(&symbol{kind: KindConstant, name: constant.Name, public: constant.IsExported, signature: strings.TrimSpace("const " + constant.Name + " " + graph.TypeName(constant.Type)),
+					doc: graph.FirstSentence(constant.Comment), deprecated: deprecation(constant.Comment, "")})
+			}
+			for _, variable := range file.Variables {
+				add(&symbol{kind: KindVariable, name: variable.Name, public: variable.IsExported, signature: strings.TrimSpace("var " + variable.Name + " " + graph.TypeName(variable.Type)),
+					doc: graph.FirstSentence(variable.Comment), deprecated: deprecation(variable.Comment, variable.Annotation)})
+			}
+		}
+	}
+	return result
+}
+
+// signature returns the declaration of a function, composed from parameters when the inspector recorded none
+func signature(function *graph.Function) string {
+	if function.Signature != "" {
+		return function.Signature
+	}
+	builder := &strings.Builder{}
+	builder.WriteString("func ")
+	if function.Receiver != "" {
+		builder.WriteString("(" + function.Receiver + ") ")
+	}
+	builder.WriteString(function.Name + "(" + parameters(function.Parameters) + ")")
+	switch {
+	case len(function.Results) == 1 && function.Results[0].Name == "":
+		builder.WriteString(" " + graph.TypeName(function.Results[0].Type))
+	case len(function.Results) > 0:
+		builder.WriteString(" (" + parameters(function.Results) + ")")
+	}
+	return builder.String()
+}
+
+// parameters returns a comma separated parameter list
+func parameters(params []*graph.Parameter) string {
+	var result []string
+	for _, param := range params {
+		paramType := graph.TypeName(param.Type)
+		if param.IsVariadic {
+			paramType = "..." + paramType
+		}
+		result = append(result, strings.TrimSpace(param.Name+" "+paramType))
+	}
+	return strings.Join(result, ", ")
+}
+
+// typeSignature returns the declaration of a type without members, e.g. type Invoice struct
+func typeSignature(aType *graph.Type) string {
+	name := aType.Name
+	if len(aType.TypeParams) > 0 {
+		var params []string
+		for _, param := range aType.TypeParams {
+			params = append(params, strings.TrimSpace(param.Name+" "+param.Constraint))
+		}
+		name += "[" + strings.Join(params, ", ") + "]"
+	}
+	var underlying string
+	switch aType.Kind {
+	case reflect.Slice:
+		underlying = "[]" + aType.ComponentType
+	case reflect.Array:
+		underlying = "[...]" + aType.ComponentType
+	case reflect.Map:
+		underlying = "map[" + aType.KeyType + "]" + aType.ComponentType
+	case reflect.Invalid:
+	default:
+		underlying = aType.Kind.String()
+	}
+	return strings.TrimSpace("type " + name + " " + underlying)
+}
+
+// fieldName returns the name of a field, the type name of embedded fields
+func fieldName(field *graph.Field) string {
+	if field.Name == "" && field.Type != nil {
+		return strings.TrimPrefix(field.Type.Name, "*")
+	}
+	return field.Name
+}
+
+// fieldSignature returns the declaration of a field, e.g. Total int64
+func fieldSignature(field *graph.Field) string {
+	if field.IsEmbedded || field.Name == "" {
+		return graph.TypeName(field.Type)
+	}
+	return strings.TrimSpace(field.Name + " " + graph.TypeName(field.Type))
+}
+
+// bodyHash returns the normalized body hash of a function, 0 when the body is unknown
+func bodyHash(function *graph.Function) int32 {
+	if function.Body != nil {
+		return graph.BodyHash(function.Body.Text)
+	}
+	return function.Hash
+}
+
+// deprecation returns the deprecation notice of a doc comment paragraph starting with Deprecated: or Deprecated for
+// a @Deprecated annotation, empty otherwise
+func deprecation(comment, annotation string) string {
+	var paragraph []string
+	for _, line := range strings.Split(comment, "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "//"))
+		switch {
+		case line == "" && len(paragraph) > 0:
+			return strings.Join(paragraph, " ")
+		case line == "":
+		case len(paragraph) > 0 || strings.HasPrefix(line, "Deprecated:"):
+			paragraph = append(paragraph, line)
+		}
+	}
+	if len(paragraph) > 0 {
+		return strings.Join(paragraph, " ")
+	}
+	if strings.Contains(annotation, "@Deprecated") {
+		return "Deprecated"
+	}
+	return ""
+}
+
+// isInternal reports whether an import path has an internal element
+func isInternal(importPath string) bool {
+	for _, element := range strings.Split(importPath, "/") {
+		if element == "internal" {
+			return true
+		}
+	}
+	return false
+}
+
+// area returns the area name of the longest import path prefix of a package, the import path when not mapped
+func area(importPath string, areas map[string]string) string {
+	name, matched := importPath, -1
+	for prefix, areaName := range areas {
+		if (importPath == prefix || strings.HasPrefix(importPath, prefix+"/")) && len(prefix) > matched {
+			name, matched = areaName, len(prefix)
+		}
+	}
+	return name
+}
+
+// sortedKeys returns keys of symbols of both versions in order
+func sortedKeys(prev, next map[string]*symbol) []string {
+	var result []string
+	for key := range prev {
+		result = append(result, key)
+	}
+	for key := range next {
+		if _, ok := prev[key]; !ok {
+			result = append(result, key)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// sortEntries orders entries by area, symbol and kind
+func sortEntries(entries []*Entry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Area != b.Area {
+			return a.Area < b.Area
+		}
+		if a.Symbol != b.Symbol {
+			return a.Symbol < b.Symbol
+		}
+		return a.Kind < b.Kind
+	})
+}
@@ -0,0 +1,111 @@
+package changelog_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/linager/inspector/changelog"
+	"github.com/viant/linager/inspector/golang"
+	"github.com/viant/linager/inspector/graph"
+)
+
+// TestGenerate compares release notes of two fixture versions with a golden file, run with UPDATE_SNAPSHOTS=1 to
+// regenerate it
+func TestGenerate(t *testing.T) {
+	prev, next := inspectVersion(t, "v1"), inspectVersion(t, "v2")
+	if prev == nil || next == nil {
+		return
+	}
+	opts := &changelog.Options{
+		Title:           "v2.0.0",
+		Areas:           map[string]string{"example.com/shop": "Shop", "example.com/shop/billing": "Billing"},
+		ExcludeInternal: true,
+	}
+	notes := changelog.Generate(prev, next, opts)
+
+	type change struct{ Change, Symbol, Before, After, Note string }
+	var breaking []change
+	for _, entry := range notes.Breaking {
+		assert.Equal(t, "Billing", entry.Area)
+		breaking = append(breaking, change{entry.Change, entry.Symbol, entry.Before, entry.After, entry.Note})
+	}
+	assert.Equal(t, []change{
+		{Change: changelog.ChangeRemoved, Symbol: "billing.Charge", Before: "func Charge(amount int64) error", Note: "renamed to `billing.ChargeAmount`"},
+		{Change: changelog.ChangeChanged, Symbol: "billing.Invoice.Amount", Before: "func (i *Invoice) Amount() int64", After: "func (i *Invoice) Amount(currency string) (float64, error)"},
+		{Change: changelog.ChangeRemoved, Symbol: "billing.Invoice.Notes", Before: "Notes string"},
+		{Change: changelog.ChangeChanged, Symbol: "billing.Invoice.Total", Before: "Total int64", After: "Total float64"},
+		{Change: changelog.ChangeRemoved, Symbol: "billing.Legacy", Before: "func Legacy() string"},
+	}, breaking)
+
+	added := map[string]string{}
+	for _, entry := range notes.Added {
+		added[entry.Symbol] = entry.Doc
+	}
+	assert.Equal(t, map[string]string{
+		"billing.ChargeAmount":     "ChargeAmount charges an amount.",
+		"billing.Invoice.Currency": "Currency of the total",
+		"billing.Receipt":          "Receipt confirms a payment.",
+		"billing.Refund":           "Refund reverses a charge.",
+	}, added, "fields of added types are covered by their type")
+
+	if assert.Len(t, notes.Deprecated, 1) {
+		assert.Equal(t, "billing.Pay", notes.Deprecated[0].Symbol)
+		assert.Equal(t, "Deprecated: use ChargeAmount instead.", notes.Deprecated[0].Doc)
+	}
+	assert.Equal(t, changelog.InternalChanges{Implementations: 1, Added: 1, Changed: 2, Renamed: 1}, notes.Internal)
+
+	goldenPath := filepath.Join("testdata", "CHANGELOG.md")
+	if os.Getenv("UPDATE_SNAPSHOTS") == "1" {
+		assert.NoError(t, os.WriteFile(goldenPath, []byte(notes.Document), 0644))
+	} else if expected, err := os.ReadFile(goldenPath); assert.NoError(t, err) {
+		assert.Equal(t, string(expected), notes.Document)
+	}
+	assert.Equal(t, notes.Document, changelog.Generate(prev, next, opts).Document, "output is deterministic")
+
+	// internal packages are public API unless excluded
+	notes = changelog.Generate(prev, next, &changelog.Options{Areas: opts.Areas})
+	var ledger []*changelog.Entry
+	for _, entry := range notes.Breaking {
+		if entry.Area == "Shop" {
+			ledger = append(ledger, entry)
+		}
+	}
+	if assert.Len(t, ledger, 1) {
+		assert.Equal(t, "ledger.Record", ledger[0].Symbol)
+		assert.Equal(t, "func Record(account string, amount int64)", ledger[0].After)
+	}
+	assert.Equal(t, 1, notes.Internal.Changed)
+
+	// methods added to interfaces break implementations
+	gateway := func(methods ...string) *graph.Project {
+		aType := &graph.Type{Name: "Gateway", Kind: reflect.Interface, IsExported: true}
+		for _, method := range methods {
+			aType.Methods = append(aType.Methods, &graph.Function{Name: method, IsExported: true, Signature: method + "(amount int64) error"})
+		}
+		return &graph.Project{Packages: []*graph.Package{{Name: "billing", ImportPath: "example.com/shop/billing",
+			FileSet: []*graph.File{{Name: "gateway.go", Package: "billing", Types: []*graph.Type{aType}}}}}}
+	}
+	notes = changelog.Generate(gateway("Charge"), gateway("Charge", "Refund"), nil)
+	assert.Empty(t, notes.Added)
+	if assert.Len(t, notes.Breaking, 1) {
+		assert.Equal(t, "billing.Gateway.Refund", notes.Breaking[0].Symbol)
+		assert.Equal(t, changelog.ChangeAdded, notes.Breaking[0].Change)
+		assert.Equal(t, "Refund(amount int64) error", notes.Breaking[0].After)
+	}
+}
+
+// inspectVersion inspects a fixture version of the shop module
+func inspectVersion(t *testing.T, version string) *graph.Project {
+	rootPath, err := filepath.Abs(filepath.Join("testdata", version))
+	if !assert.NoError(t, err) {
+		return nil
+	}
+	packages, err := golang.NewInspector(&graph.Config{IncludeUnexported: true}).InspectPackages(rootPath)
+	if !assert.NoError(t, err) {
+		return nil
+	}
+	return &graph.Project{Name: "example.com/shop", RootPath: rootPath, Packages: packages}
+}
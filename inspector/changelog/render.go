@@ -0,0 +1,77 @@
+package changelog
+
+import (
+	"fmt"
+	"strings"
+)
+
+// breakingVerbs maps change kinds to breaking change headlines
+var breakingVerbs = map[string]string{ChangeRemoved: "Removed", ChangeChanged: "Changed", ChangeAdded: "Added"}
+
+// render returns Markdown release notes of a changelog
+func render(changelog *Changelog, opts *Options) string {
+	builder := &strings.Builder{}
+	title := opts.Title
+	if title == "" {
+		title = defaultTitle
+	}
+	builder.WriteString("# " + title + "\n")
+	writeSection(builder, "Breaking Changes", changelog.Breaking, func(entry *Entry) {
+		builder.WriteString(fmt.Sprintf("- %s %s `%s`", breakingVerbs[entry.Change], entry.Kind, entry.Symbol))
+		if entry.Note != "" {
+			builder.WriteString(" (" + entry.Note + ")")
+		}
+		builder.WriteString("\n")
+		writeSnippet(builder, entry)
+	})
+	writeSection(builder, "New APIs", changelog.Added, func(entry *Entry) {
+		writeItem(builder, entry)
+	})
+	writeSection(builder, "Deprecations", changelog.Deprecated, func(entry *Entry) {
+		writeItem(builder, entry)
+	})
+
+	internal := changelog.Internal
+	builder.WriteString("\n## Internal Changes\n\n")
+	builder.WriteString(fmt.Sprintf("- Implementation changes: %d\n", internal.Implementations))
+	builder.WriteString(fmt.Sprintf("- Internal symbols added: %d, removed: %d, changed: %d\n", internal.Added, internal.Removed, internal.Changed))
+	builder.WriteString(fmt.Sprintf("- Functions moved: %d, renamed: %d\n", internal.Moved, internal.Renamed))
+	return builder.String()
+}
+
+// writeSection writes a section of entries grouped by area, nothing for no entries
+func writeSection(builder *strings.Builder, title string, entries []*Entry, write func(entry *Entry)) {
+	if len(entries) == 0 {
+		return
+	}
+	builder.WriteString("\n## " + title + "\n")
+	for i, entry := range entries {
+		if i == 0 || entries[i-1].Area != entry.Area {
+			builder.WriteString("\n### " + entry.Area + "\n\n")
+		}
+		write(entry)
+	}
+}
+
+// writeItem writes an added or deprecated symbol with its doc
+func writeItem(builder *strings.Builder, entry *Entry) {
+	builder.WriteString(fmt.Sprintf("- %s `%s`", entry.Kind, entry.Symbol))
+	if entry.Doc != "" {
+		builder.WriteString(": " + entry.Doc)
+	}
+	builder.WriteString("\n")
+}
+
+// writeSnippet writes declarations of a breaking change, before and after for changed declarations
+func writeSnippet(builder *strings.Builder, entry *Entry) {
+	builder.WriteString("\n  ```go\n")
+	switch {
+	case entry.Before != "" && entry.After != "":
+		builder.WriteString("  // before\n  " + entry.Before + "\n  // after\n  " + entry.After + "\n")
+	case entry.Before != "":
+		builder.WriteString("  " + entry.Before + "\n")
+	default:
+		builder.WriteString("  " + entry.After + "\n")
+	}
+	builder.WriteString("  ```\n")
+}
@@ -0,0 +1,40 @@
+// Package billing charges customers.
+package billing
+
+// Invoice is a bill sent to a customer.
+type Invoice struct {
+	ID    string
+	Total int64
+	Notes string
+	draft bool
+}
+
+// Amount returns the invoice total.
+func (i *Invoice) Amount() int64 {
+	return round(i.Total)
+}
+
+// Charge charges an amount.
+func Charge(amount int64) error {
+	if amount <= 0 {
+		return nil
+	}
+	return nil
+}
+
+// Pay pays an invoice.
+func Pay(invoice *Invoice) error {
+	return Charge(invoice.Total)
+}
+
+// Legacy is kept for compatibility.
+func Legacy() string {
+	return "legacy"
+}
+
+// Currency is the default currency.
+const Currency = "USD"
+
+func round(amount int64) int64 {
+	return amount
+}
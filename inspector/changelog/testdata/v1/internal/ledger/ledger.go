@@ -0,0 +1,5 @@
+package ledger
+
+// Record records an entry.
+func Record(amount int64) {
+}
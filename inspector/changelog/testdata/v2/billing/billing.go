@@ -0,0 +1,52 @@
+// Package billing charges customers.
+package billing
+
+// Invoice is a bill sent to a customer.
+type Invoice struct {
+	ID       string
+	Total    float64
+	Currency string // Currency of the total
+	draft    bool
+}
+
+// Amount returns the invoice total in a currency.
+func (i *Invoice) Amount(currency string) (float64, error) {
+	return round(i.Total), nil
+}
+
+// ChargeAmount charges an amount.
+func ChargeAmount(amount int64) error {
+	if amount <= 0 {
+		return nil
+	}
+	return nil
+}
+
+// Pay pays an invoice.
+//
+// Deprecated: use ChargeAmount instead.
+func Pay(invoice *Invoice) error {
+	return ChargeAmount(int64(invoice.Total))
+}
+
+// Refund reverses a charge. Refunds are idempotent.
+func Refund(id string) error {
+	return nil
+}
+
+// Receipt confirms a payment.
+type Receipt struct {
+	ID     string
+	Amount float64
+}
+
+// Currency is the default currency.
+const Currency = "USD"
+
+func round(amount float64) float64 {
+	return amount
+}
+
+func format(amount float64) string {
+	return ""
+}
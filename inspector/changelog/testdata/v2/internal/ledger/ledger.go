@@ -0,0 +1,5 @@
+package ledger
+
+// Record records an entry in an account.
+func Record(account string, amount int64) {
+}
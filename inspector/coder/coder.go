@@ -2,14 +2,12 @@ package coder
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/viant/afs"
 	"github.com/viant/linager/inspector"
-	"github.com/viant/linager/inspector/golang"
 	"github.com/viant/linager/inspector/graph"
-	"github.com/viant/linager/inspector/java"
 	"github.com/viant/linager/inspector/repository"
-	"os"
 	"path/filepath"
 	"reflect"
 	"strings"
@@ -21,13 +19,32 @@ import (
 type Coder struct {
 	Project *graph.Project // The project being manipulated
 	fs      afs.Service
+	factory *inspector.Factory
+	tx      *Transaction // innermost open transaction, see Begin
+}
+
+// Option represents a Coder option
+type Option func(*Coder)
+
+// WithFactory sets the factory used to inspect loaded projects and to emit stored files, e.g. with emitter options
+func WithFactory(factory *inspector.Factory) Option {
+	return func(c *Coder) {
+		c.factory = factory
+	}
 }
 
 // NewCoder creates a new Coder instance for the given project
-func NewCoder(project *graph.Project) *Coder {
-	return &Coder{
+func NewCoder(project *graph.Project, options ...Option) *Coder {
+	ret := &Coder{
 		Project: project,
 	}
+	for _, option := range options {
+		option(ret)
+	}
+	if ret.factory == nil {
+		ret.factory = inspector.NewFactory(nil)
+	}
+	return ret
 }
 
 // CreatePackage creates a new package in the project
@@ -40,39 +57,35 @@ func (c *Coder) CreatePackage(name, importPath string) *graph.Package {
 	}
 
 	// Add the package to the project
-	c.Project.Packages = append(c.Project.Packages, pkg)
+	c.addPackage(pkg)
 
 	return pkg
 }
 
-// RemovePackage removes a package from the project by name
+// RemovePackage removes a package from the project by name, it returns false if the package does not exist
 func (c *Coder) RemovePackage(name string) bool {
-	// Find the package index
-	var idx int
-	var found bool
+	return c.DeletePackage(name) == nil
+}
+
+// DeletePackage removes a package from the project by name
+func (c *Coder) DeletePackage(name string) error {
 	for i, pkg := range c.Project.Packages {
 		if pkg.Name == name {
-			idx = i
-			found = true
-			break
+			c.removePackage(i)
+			for _, file := range pkg.FileSet {
+				c.Project.MarkDirty(file.Path)
+			}
+			return nil
 		}
 	}
-
-	if !found {
-		return false
-	}
-
-	// Remove the package from the packages slice
-	c.Project.Packages = append(c.Project.Packages[:idx], c.Project.Packages[idx+1:]...)
-
-	return true
+	return &graph.ErrNotFound{Kind: "package", Name: name}
 }
 
 // CreateFile creates a new file in the specified package
 func (c *Coder) CreateFile(packageName, fileName, filePath string) (*graph.File, error) {
-	pkg := c.Project.GetPackage(packageName)
-	if pkg == nil {
-		return nil, fmt.Errorf("package %s not found", packageName)
+	pkg, err := c.lookupPackage(packageName)
+	if err != nil {
+		return nil, err
 	}
 
 	file := &graph.File{
@@ -88,57 +101,40 @@ func (c *Coder) CreateFile(packageName, fileName, filePath string) (*graph.File,
 	}
 
 	// Add the file to the package
-	pkg.AddFile(file)
+	c.addFile(pkg, file)
 
 	return file, nil
 }
 
-// RemoveFile removes a file from the specified package by name
+// RemoveFile removes a file from the specified package by name, it returns false if the file does not exist
 func (c *Coder) RemoveFile(packageName, fileName string) bool {
-	pkg := c.Project.GetPackage(packageName)
-	if pkg == nil {
-		return false
-	}
+	return c.DeleteFile(packageName, fileName) == nil
+}
 
-	// Find the file index
-	var idx int
-	var found bool
+// DeleteFile removes a file from the specified package by name
+func (c *Coder) DeleteFile(packageName, fileName string) error {
+	pkg, err := c.lookupPackage(packageName)
+	if err != nil {
+		return err
+	}
+	pkg = c.Project.MutablePackage(pkg)
 	for i, file := range pkg.FileSet {
 		if file.Name == fileName {
-			idx = i
-			found = true
-			break
+			c.removeFile(pkg, i)
+			c.Project.MarkDirty(file.Path)
+			return nil
 		}
 	}
-
-	if !found {
-		return false
-	}
-
-	// Remove the file from the files slice
-	pkg.FileSet = append(pkg.FileSet[:idx], pkg.FileSet[idx+1:]...)
-
-	return true
+	return fmt.Errorf("%w in package %s", &graph.ErrNotFound{Kind: "file", Name: fileName}, packageName)
 }
 
 // CreateType creates a new type in the specified file
 func (c *Coder) CreateType(packageName, fileName, typeName string, kind reflect.Kind) (*graph.Type, error) {
-	pkg := c.Project.GetPackage(packageName)
-	if pkg == nil {
-		return nil, fmt.Errorf("package %s not found", packageName)
-	}
-
-	var file *graph.File
-	for _, f := range pkg.FileSet {
-		if f.Name == fileName {
-			file = f
-			break
-		}
-	}
-
-	if file == nil {
-		return nil, fmt.Errorf("file %s not found in package %s", fileName, packageName)
+	file, err := c.editFile(packageName, fileName)
+	if err != nil {
+		return nil, err
 	}
+	pkg := c.Project.GetPackage(packageName)
 
 	// Create a new type
 	newType := &graph.Type{
@@ -158,76 +154,32 @@ func (c *Coder) CreateType(packageName, fileName, typeName string, kind reflect.
 	return newType, nil
 }
 
-// RemoveType removes a type from the specified file by name
+// RemoveType removes a type from the specified file by name, it returns false if the type does not exist
 func (c *Coder) RemoveType(packageName, fileName, typeName string) bool {
-	pkg := c.Project.GetPackage(packageName)
-	if pkg == nil {
-		return false
-	}
-
-	var file *graph.File
-	for _, f := range pkg.FileSet {
-		if f.Name == fileName {
-			file = f
-			break
-		}
-	}
+	return c.DeleteType(packageName, fileName, typeName) == nil
+}
 
-	if file == nil {
-		return false
+// DeleteType removes a type from the specified file by name
+func (c *Coder) DeleteType(packageName, fileName, typeName string) error {
+	file, err := c.editFile(packageName, fileName)
+	if err != nil {
+		return err
 	}
-
-	// Find the type index
-	var idx int
-	var found bool
 	for i, typ := range file.Types {
 		if typ.Name == typeName {
-			idx = i
-			found = true
-			break
+			file.Types = append(file.Types[:i], file.Types[i+1:]...)
+			file.IndexTypes()
+			return nil
 		}
 	}
-
-	if !found {
-		return false
-	}
-
-	// Remove the type from the types slice
-	file.Types = append(file.Types[:idx], file.Types[idx+1:]...)
-	file.IndexTypes()
-
-	return true
+	return fmt.Errorf("%w in file %s", &graph.ErrNotFound{Kind: "type", Name: typeName}, fileName)
 }
 
 // CreateField creates a new field in the specified type
 func (c *Coder) CreateField(packageName, fileName, typeName, fieldName string, fieldType *graph.Type, tag reflect.StructTag) (*graph.Field, error) {
-	pkg := c.Project.GetPackage(packageName)
-	if pkg == nil {
-		return nil, fmt.Errorf("package %s not found", packageName)
-	}
-
-	var file *graph.File
-	for _, f := range pkg.FileSet {
-		if f.Name == fileName {
-			file = f
-			break
-		}
-	}
-
-	if file == nil {
-		return nil, fmt.Errorf("file %s not found in package %s", fileName, packageName)
-	}
-
-	var typ *graph.Type
-	for _, t := range file.Types {
-		if t.Name == typeName {
-			typ = t
-			break
-		}
-	}
-
-	if typ == nil {
-		return nil, fmt.Errorf("type %s not found in file %s", typeName, fileName)
+	_, typ, err := c.editType(packageName, fileName, typeName)
+	if err != nil {
+		return nil, err
 	}
 
 	// Create a new field
@@ -244,69 +196,28 @@ func (c *Coder) CreateField(packageName, fileName, typeName, fieldName string, f
 	return field, nil
 }
 
-// RemoveField removes a field from the specified type by name
+// RemoveField removes a field from the specified type by name, it returns false if the field does not exist
 func (c *Coder) RemoveField(packageName, fileName, typeName, fieldName string) bool {
-	pkg := c.Project.GetPackage(packageName)
-	if pkg == nil {
-		return false
-	}
-
-	var file *graph.File
-	for _, f := range pkg.FileSet {
-		if f.Name == fileName {
-			file = f
-			break
-		}
-	}
-
-	if file == nil {
-		return false
-	}
+	return c.DeleteField(packageName, fileName, typeName, fieldName) == nil
+}
 
-	var typ *graph.Type
-	for _, t := range file.Types {
-		if t.Name == typeName {
-			typ = t
-			break
-		}
+// DeleteField removes a field from the specified type by name
+func (c *Coder) DeleteField(packageName, fileName, typeName, fieldName string) error {
+	_, typ, err := c.editType(packageName, fileName, typeName)
+	if err != nil {
+		return err
 	}
-
-	if typ == nil {
-		return false
+	if !typ.RemoveField(fieldName) {
+		return fmt.Errorf("%w in type %s", &graph.ErrNotFound{Kind: "field", Name: fieldName}, typeName)
 	}
-
-	return typ.RemoveField(fieldName)
+	return nil
 }
 
 // CreateMethod creates a new method for the specified type
 func (c *Coder) CreateMethod(packageName, fileName, typeName, methodName string, parameters []*graph.Parameter, results []*graph.Parameter, body string) (*graph.Function, error) {
-	pkg := c.Project.GetPackage(packageName)
-	if pkg == nil {
-		return nil, fmt.Errorf("package %s not found", packageName)
-	}
-
-	var file *graph.File
-	for _, f := range pkg.FileSet {
-		if f.Name == fileName {
-			file = f
-			break
-		}
-	}
-
-	if file == nil {
-		return nil, fmt.Errorf("file %s not found in package %s", fileName, packageName)
-	}
-
-	var typ *graph.Type
-	for _, t := range file.Types {
-		if t.Name == typeName {
-			typ = t
-			break
-		}
-	}
-
-	if typ == nil {
-		return nil, fmt.Errorf("type %s not found in file %s", typeName, fileName)
+	file, typ, err := c.editType(packageName, fileName, typeName)
+	if err != nil {
+		return nil, err
 	}
 
 	// Create a new method
@@ -321,61 +232,34 @@ func (c *Coder) CreateMethod(packageName, fileName, typeName, methodName string,
 
 	// Add the method to the type
 	typ.AddMethod(method)
+	file.IndexTypes()
 
 	return method, nil
 }
 
-// RemoveMethod removes a method from the specified type by name
+// RemoveMethod removes a method from the specified type by name, it returns false if the method does not exist
 func (c *Coder) RemoveMethod(packageName, fileName, typeName, methodName string) bool {
-	pkg := c.Project.GetPackage(packageName)
-	if pkg == nil {
-		return false
-	}
-
-	var file *graph.File
-	for _, f := range pkg.FileSet {
-		if f.Name == fileName {
-			file = f
-			break
-		}
-	}
-
-	if file == nil {
-		return false
-	}
+	return c.DeleteMethod(packageName, fileName, typeName, methodName) == nil
+}
 
-	var typ *graph.Type
-	for _, t := range file.Types {
-		if t.Name == typeName {
-			typ = t
-			break
-		}
+// DeleteMethod removes a method from the specified type by name
+func (c *Coder) DeleteMethod(packageName, fileName, typeName, methodName string) error {
+	file, typ, err := c.editType(packageName, fileName, typeName)
+	if err != nil {
+		return err
 	}
-
-	if typ == nil {
-		return false
+	if !typ.RemoveMethod(methodName) {
+		return fmt.Errorf("%w in type %s", &graph.ErrNotFound{Kind: "method", Name: methodName}, typeName)
 	}
-
-	return typ.RemoveMethod(methodName)
+	file.IndexTypes()
+	return nil
 }
 
 // CreateFunction creates a new function in the specified file
 func (c *Coder) CreateFunction(packageName, fileName, functionName string, parameters []*graph.Parameter, results []*graph.Parameter, body string) (*graph.Function, error) {
-	pkg := c.Project.GetPackage(packageName)
-	if pkg == nil {
-		return nil, fmt.Errorf("package %s not found", packageName)
-	}
-
-	var file *graph.File
-	for _, f := range pkg.FileSet {
-		if f.Name == fileName {
-			file = f
-			break
-		}
-	}
-
-	if file == nil {
-		return nil, fmt.Errorf("file %s not found in package %s", fileName, packageName)
+	file, err := c.editFile(packageName, fileName)
+	if err != nil {
+		return nil, err
 	}
 
 	// Create a new function
@@ -394,45 +278,110 @@ func (c *Coder) CreateFunction(packageName, fileName, functionName string, param
 	return function, nil
 }
 
-// RemoveFunction removes a function from the specified file by name
+// RemoveFunction removes a function from the specified file by name, it returns false if the function does not exist
 func (c *Coder) RemoveFunction(packageName, fileName, functionName string) bool {
+	return c.DeleteFunction(packageName, fileName, functionName) == nil
+}
+
+// DeleteFunction removes a function from the specified file by name
+func (c *Coder) DeleteFunction(packageName, fileName, functionName string) error {
+	file, err := c.editFile(packageName, fileName)
+	if err != nil {
+		return err
+	}
+	for i, function := range file.Functions {
+		if function.Name == functionName {
+			file.Functions = append(file.Functions[:i], file.Functions[i+1:]...)
+			file.IndexFunctions()
+			return nil
+		}
+	}
+	return fmt.Errorf("%w in file %s", &graph.ErrNotFound{Kind: "function", Name: functionName}, fileName)
+}
+
+// lookupPackage returns a project package by name
+func (c *Coder) lookupPackage(packageName string) (*graph.Package, error) {
+	if c.Project == nil {
+		return nil, &graph.ErrNotFound{Kind: "project", Name: packageName}
+	}
 	pkg := c.Project.GetPackage(packageName)
 	if pkg == nil {
-		return false
+		return nil, &graph.ErrNotFound{Kind: "package", Name: packageName}
 	}
+	return pkg, nil
+}
 
-	var file *graph.File
+// lookupFile returns a package file by name
+func (c *Coder) lookupFile(packageName, fileName string) (*graph.File, error) {
+	pkg, err := c.lookupPackage(packageName)
+	if err != nil {
+		return nil, err
+	}
 	for _, f := range pkg.FileSet {
 		if f.Name == fileName {
-			file = f
-			break
+			return f, nil
 		}
 	}
+	return nil, fmt.Errorf("%w in package %s", &graph.ErrNotFound{Kind: "file", Name: fileName}, packageName)
+}
 
-	if file == nil {
-		return false
+// editFile returns a package file by name safe to change (see graph.Project.MutableFile), its content is recorded
+// by the open transaction
+func (c *Coder) editFile(packageName, fileName string) (*graph.File, error) {
+	file, err := c.lookupFile(packageName, fileName)
+	if err != nil {
+		return nil, err
 	}
+	file = c.Project.MutableFile(c.Project.GetPackage(packageName), file)
+	c.recordFile(file)
+	return file, nil
+}
 
-	// Find the function index
-	var idx int
-	var found bool
-	for i, function := range file.Functions {
-		if function.Name == functionName {
-			idx = i
-			found = true
-			break
+// editType returns a file type by name safe to change with its declaring file
+func (c *Coder) editType(packageName, fileName, typeName string) (*graph.File, *graph.Type, error) {
+	file, aType, err := c.lookupType(packageName, fileName, typeName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if c.Project.MutableFile(c.Project.GetPackage(packageName), file) != file {
+		if file, aType, err = c.lookupType(packageName, fileName, typeName); err != nil {
+			return nil, nil, err
 		}
 	}
+	c.recordFile(file)
+	return file, aType, nil
+}
 
-	if !found {
-		return false
+// ownFiles replaces files shared with a cloned project by owned copies, see graph.Project.Clone; it reports whether
+// any file was copied, references to the shared files are stale then. Contents of the files are recorded by the open
+// transaction.
+func (c *Coder) ownFiles(files map[*graph.File]bool) bool {
+	copied := false
+	for _, pkg := range c.Project.Packages {
+		for _, file := range pkg.FileSet {
+			if !files[file] {
+				continue
+			}
+			owned := c.Project.MutableFile(pkg, file)
+			c.recordFile(owned)
+			copied = copied || owned != file
+		}
 	}
+	return copied
+}
 
-	// Remove the function from the functions slice
-	file.Functions = append(file.Functions[:idx], file.Functions[idx+1:]...)
-	file.IndexFunctions()
-
-	return true
+// lookupType returns a file type by name with its declaring file
+func (c *Coder) lookupType(packageName, fileName, typeName string) (*graph.File, *graph.Type, error) {
+	file, err := c.lookupFile(packageName, fileName)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, t := range file.Types {
+		if t.Name == typeName {
+			return file, t, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("%w in file %s", &graph.ErrNotFound{Kind: "type", Name: typeName}, fileName)
 }
 
 // LoadProject loads a project from the specified location using an inspector
@@ -444,7 +393,7 @@ func (c *Coder) LoadProject(ctx context.Context, location string) error {
 
 	// Detect project type
 	detector := repository.New()
-	detectedProject, err := detector.DetectProject(location)
+	detectedProject, err := detector.DetectProject(ctx, location)
 	if err != nil {
 		return fmt.Errorf("failed to detect project: %w", err)
 	}
@@ -453,11 +402,8 @@ func (c *Coder) LoadProject(ctx context.Context, location string) error {
 	repoProject.Type = detectedProject.Type
 	repoProject.Name = detectedProject.Name
 
-	// Create an inspector factory
-	factory := inspector.NewFactory(nil)
-
 	// Inspect the project
-	project, err := factory.InspectProject(repoProject)
+	project, err := c.factory.InspectProject(repoProject)
 	if err != nil {
 		return fmt.Errorf("failed to inspect project: %w", err)
 	}
@@ -468,74 +414,123 @@ func (c *Coder) LoadProject(ctx context.Context, location string) error {
 	return nil
 }
 
-// StoreProject stores the project to the specified URL
-func (c *Coder) StoreProject(ctx context.Context, url string) error {
+// StoreProject stores the project to the specified URL, files and assets with unchanged content are not rewritten
+func (c *Coder) StoreProject(ctx context.Context, url string, options ...StoreOption) (*StoreReport, error) {
 	if c.Project == nil {
-		return fmt.Errorf("no project to store")
+		return nil, fmt.Errorf("no project to store")
+	}
+	opts := &storeOptions{}
+	for _, option := range options {
+		option(opts)
+	}
+	if c.tx != nil && !opts.openTransaction {
+		return nil, ErrOpenTransaction
+	}
+	previous, err := loadManifest(url)
+	if err != nil {
+		return nil, err
+	}
+	report := &StoreReport{}
+	c.walkContent(ctx, opts.splices, func(path string, content []byte, sourceMap *graph.SourceMap, err error) {
+		if err != nil {
+			report.Failed = append(report.Failed, &StoredPath{Path: path, Error: err.Error()})
+			return
+		}
+		report.store(url, path, content)
+		if sourceMap != nil {
+			if report.SourceMaps == nil {
+				report.SourceMaps = map[string]*graph.SourceMap{}
+			}
+			report.SourceMaps[path] = sourceMap
+		}
+	})
+
+	manifest := report.stored()
+	if opts.prune {
+		report.prune(url, previous)
+	} else {
+		manifest = mergePaths(manifest, previous) // retained files stay eligible for later pruning
+	}
+	if err = storeManifest(url, manifest); err != nil {
+		return report, fmt.Errorf("failed to store manifest: %w", err)
+	}
+	if opts.sourceMaps {
+		if err = storeSourceMaps(url, report.SourceMaps); err != nil {
+			return report, fmt.Errorf("failed to store source maps: %w", err)
+		}
 	}
+	return report, report.Err()
+}
 
+// PreviewStore returns unified diffs of files and assets StoreProject would write to the specified URL, keyed by path;
+// missing files are compared as empty, unchanged paths are omitted and nothing is written
+func (c *Coder) PreviewStore(ctx context.Context, url string) (map[string]string, error) {
+	if c.Project == nil {
+		return nil, fmt.Errorf("no project to store")
+	}
+	result := map[string]string{}
+	var errs []error
+	c.walkContent(ctx, nil, func(path string, content []byte, _ *graph.SourceMap, err error) {
+		if err == nil {
+			var diff string
+			if diff, err = previewDiff(url, path, content); err == nil && diff != "" {
+				result[path] = diff
+			}
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to preview %s: %w", path, err))
+		}
+	})
+	return result, errors.Join(errs...)
+}
+
+// walkContent calls fn with path, reconstructed content and source map of each project file and loaded content of each
+// non-empty asset; with splices (even empty) files are read from the project source and spliced instead, without source map
+func (c *Coder) walkContent(ctx context.Context, splices map[string][]*Splice, fn func(path string, content []byte, sourceMap *graph.SourceMap, err error)) {
 	// Iterate through all packages in the project
 	for _, pkg := range c.Project.Packages {
 		// Iterate through all files in the package
 		for _, file := range pkg.FileSet {
-
-			contentGenerator := lookupEmitter(file)
+			contentGenerator := c.lookupEmitter(file)
 			if contentGenerator == nil {
 				continue
 			}
+			if splices != nil {
+				content, err := c.spliceSource(file, splices[file.Path])
+				fn(file.Path, content, nil, err)
+				continue
+			}
 			// Reconstruct the file content
-			content, err := file.Content(contentGenerator)
+			result, err := file.Content(contentGenerator)
 			if err != nil {
-				return fmt.Errorf("failed to reconstruct file content for %s: %w", file.Path, err)
-			}
-
-			// Construct the full path to the file
-			filePath := filepath.Join(url, file.Path)
-
-			// Ensure the directory exists
-			dir := filepath.Dir(filePath)
-			if err := os.MkdirAll(dir, 0755); err != nil {
-				return fmt.Errorf("failed to create directory %s: %w", dir, err)
-			}
-
-			// Store the file
-			if err := os.WriteFile(filePath, content, 0644); err != nil {
-				return fmt.Errorf("failed to store file %s: %w", filePath, err)
+				fn(file.Path, nil, nil, fmt.Errorf("failed to reconstruct file content: %w", err))
+				continue
 			}
+			fn(file.Path, result.Content, result.SourceMap, nil)
 		}
 
-		// Store any assets associated with the package
+		// Store any assets associated with the package, lazily loaded content is read one asset at a time
 		for _, asset := range pkg.Assets {
-			if asset.Content == nil || len(asset.Content) == 0 {
+			content, err := asset.Load(ctx)
+			if err != nil {
+				err = fmt.Errorf("failed to load asset content: %w", err)
+			} else if len(content) == 0 {
 				continue
 			}
-
-			// Construct the full path to the asset
-			assetPath := filepath.Join(url, asset.Path)
-
-			// Ensure the directory exists
-			dir := filepath.Dir(assetPath)
-			if err := os.MkdirAll(dir, 0755); err != nil {
-				return fmt.Errorf("failed to create directory %s: %w", dir, err)
-			}
-
-			// Store the asset
-			if err := os.WriteFile(assetPath, asset.Content, 0644); err != nil {
-				return fmt.Errorf("failed to store asset %s: %w", assetPath, err)
-			}
+			fn(asset.Path, content, nil, err)
 		}
 	}
-
-	return nil
 }
 
-func lookupEmitter(file *graph.File) graph.Emitter {
-	ext := filepath.Ext(file.Path)
-	switch ext {
-	case ".go":
-		return &golang.Emitter{}
-	case ".java":
-		return &java.Emitter{}
+// lookupEmitter returns the factory emitter of Go and Java files, nil for other files
+func (c *Coder) lookupEmitter(file *graph.File) graph.Emitter {
+	switch filepath.Ext(file.Path) {
+	case ".go", ".java":
+		emitter, err := c.factory.GetEmitter(file.Path)
+		if err != nil {
+			return nil
+		}
+		return emitter
 	}
 	return nil
 }
@@ -0,0 +1,939 @@
+package coder_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/linager/inspector/coder"
+	"github.com/viant/linager/inspector/golang"
+	"github.com/viant/linager/inspector/graph"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestCoder_Errors(t *testing.T) {
+	aCoder := coder.NewCoder(&graph.Project{Name: "test"})
+	err := aCoder.LoadProject(context.Background(), filepath.Join(t.TempDir(), "missing"))
+	var notFound *graph.ErrNotFound
+	if assert.True(t, errors.As(err, &notFound), "%v", err) {
+		assert.Equal(t, "path", notFound.Kind)
+	}
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+
+	aCoder.CreatePackage("app", "example.com/app")
+	_, err = aCoder.CreateFile("app", "app.go", "app/app.go")
+	assert.NoError(t, err)
+	_, err = aCoder.CreateType("app", "app.go", "Service", reflect.Struct)
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		description string
+		err         error
+		kind        string
+		name        string
+	}{
+		{description: "missing package", err: aCoder.DeletePackage("web"), kind: "package", name: "web"},
+		{description: "missing file", err: aCoder.DeleteFile("app", "web.go"), kind: "file", name: "web.go"},
+		{description: "missing type", err: aCoder.DeleteType("app", "app.go", "Client"), kind: "type", name: "Client"},
+		{description: "missing field", err: aCoder.DeleteField("app", "app.go", "Service", "ID"), kind: "field", name: "ID"},
+		{description: "missing method", err: aCoder.DeleteMethod("app", "app.go", "Service", "Run"), kind: "method", name: "Run"},
+		{description: "missing function", err: aCoder.DeleteFunction("app", "app.go", "main"), kind: "function", name: "main"},
+	}
+	for _, testCase := range testCases {
+		assert.True(t, errors.Is(testCase.err, &graph.ErrNotFound{Kind: testCase.kind, Name: testCase.name}), testCase.description)
+	}
+
+	assert.False(t, aCoder.RemoveType("app", "app.go", "Client"))
+	assert.True(t, aCoder.RemoveType("app", "app.go", "Service"))
+}
+
+func TestCoder_StoreProject(t *testing.T) {
+	project := &graph.Project{Name: "test", Packages: []*graph.Package{{
+		Name:       "app",
+		ImportPath: "example.com/app",
+		FileSet: []*graph.File{{
+			Name:      "app.go",
+			Path:      "app/app.go",
+			Package:   "app",
+			Functions: []*graph.Function{{Name: "Run", Signature: "func Run() error", Body: &graph.LocationNode{Text: "{\n\treturn nil\n}"}}},
+		}},
+		Assets: []*graph.Asset{{Path: "app/config.yaml", Content: []byte("name: app\n")}},
+	}}}
+	baseURL := t.TempDir()
+	userFile := filepath.Join(baseURL, "app", "notes.txt")
+	assert.NoError(t, os.MkdirAll(filepath.Dir(userFile), 0755))
+	assert.NoError(t, os.WriteFile(userFile, []byte("kept"), 0644))
+	aCoder := coder.NewCoder(project)
+
+	report, err := aCoder.StoreProject(context.Background(), baseURL)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, report.Written, 2)
+	assert.Len(t, report.Skipped, 0)
+	content, err := os.ReadFile(filepath.Join(baseURL, "app", "app.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "func Run() error {\n\treturn nil\n}")
+
+	// unchanged project writes nothing
+	report, err = aCoder.StoreProject(context.Background(), baseURL)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, report.Written, 0)
+	assert.Equal(t, []*coder.StoredPath{{Path: "app/app.go", Bytes: len(content)}, {Path: "app/config.yaml", Bytes: 10}}, report.Skipped)
+
+	// changed asset is rewritten, removed asset is kept without pruning
+	project.Packages[0].Assets[0].Content = []byte("name: svc\n")
+	report, err = aCoder.StoreProject(context.Background(), baseURL)
+	assert.NoError(t, err)
+	assert.Equal(t, []*coder.StoredPath{{Path: "app/config.yaml", Bytes: 10}}, report.Written)
+
+	project.Packages[0].Assets = nil
+	report, err = aCoder.StoreProject(context.Background(), baseURL)
+	assert.NoError(t, err)
+	assert.Empty(t, report.Deleted)
+	assert.FileExists(t, filepath.Join(baseURL, "app", "config.yaml"))
+
+	// pruning deletes only previously written files
+	report, err = aCoder.StoreProject(context.Background(), baseURL, coder.WithPrune(true))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"app/config.yaml"}, report.Deleted)
+	assert.NoFileExists(t, filepath.Join(baseURL, "app", "config.yaml"))
+	assert.FileExists(t, userFile)
+	assert.FileExists(t, filepath.Join(baseURL, "app", "app.go"))
+}
+
+func TestCoder_GenerateTestScaffold(t *testing.T) {
+	testCases := []struct {
+		description string
+		options     []coder.ScaffoldOption
+		packageName string
+	}{
+		{description: "internal tests", packageName: "stack"},
+		{description: "external tests", options: []coder.ScaffoldOption{coder.WithExternalTests(true)}, packageName: "stack_test"},
+	}
+	for _, testCase := range testCases {
+		pkg, err := golang.NewInspector(&graph.Config{}).InspectPackage("../golang/testdata/stack")
+		if !assert.NoError(t, err, testCase.description) {
+			return
+		}
+		pkg.ImportPath = "myapp/stack"
+		// existing test functions are kept
+		pkg.AddFile(&graph.File{Name: "stack_test.go", Path: "stack_test.go", Package: testCase.packageName, Functions: []*graph.Function{{Name: "TestNew"}}})
+		aCoder := coder.NewCoder(&graph.Project{Name: "myapp", Packages: []*graph.Package{pkg}})
+
+		files, err := aCoder.GenerateTestScaffold("stack", testCase.options...)
+		if !assert.NoError(t, err, testCase.description) || !assert.Len(t, files, 1, testCase.description) {
+			continue
+		}
+		var names []string
+		for _, function := range files[0].Functions {
+			names = append(names, function.Name)
+		}
+		assert.Equal(t, []string{"TestNew", "TestStack_Push", "TestStack_Pop", "TestStack_String"}, names, testCase.description)
+		result, err := files[0].Content(&golang.Emitter{})
+		if !assert.NoError(t, err, testCase.description) {
+			continue
+		}
+		assert.NoError(t, typeCheckTest("../golang/testdata/stack", pkg.ImportPath, result.Content), "%v\n%s", testCase.description, result.Content)
+
+		// second generation adds nothing
+		files, err = aCoder.GenerateTestScaffold("stack", testCase.options...)
+		assert.NoError(t, err)
+		assert.Empty(t, files, testCase.description)
+	}
+}
+
+// typeCheckTest type-checks generated test source with the package under test
+func typeCheckTest(dir, importPath string, test []byte) error {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, nil, 0)
+	if err != nil {
+		return err
+	}
+	var files []*ast.File
+	for _, file := range pkgs["stack"].Files {
+		files = append(files, file)
+	}
+	testFile, err := parser.ParseFile(fset, "stack_test.go", test, 0)
+	if err != nil {
+		return err
+	}
+	config := &types.Config{Importer: importer.Default()}
+	if testFile.Name.Name == "stack" {
+		_, err = config.Check(importPath, fset, append(files, testFile), nil)
+		return err
+	}
+	underTest, err := config.Check(importPath, fset, files, nil)
+	if err != nil {
+		return err
+	}
+	config.Importer = importerFunc(func(path string) (*types.Package, error) {
+		if path == importPath {
+			return underTest, nil
+		}
+		return importer.Default().Import(path)
+	})
+	_, err = config.Check(importPath+"_test", fset, []*ast.File{testFile}, nil)
+	return err
+}
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) { return f(path) }
+
+func TestCoder_PreviewStore(t *testing.T) {
+	project := &graph.Project{Name: "test", Packages: []*graph.Package{{
+		Name:       "app",
+		ImportPath: "example.com/app",
+		FileSet: []*graph.File{
+			{
+				Name:      "app.go",
+				Path:      "app/app.go",
+				Package:   "app",
+				Functions: []*graph.Function{{Name: "Run", Signature: "func Run() error", Body: &graph.LocationNode{Text: "{\n\treturn nil\n}"}}},
+			},
+			{
+				Name:      "util.go",
+				Path:      "app/util.go",
+				Package:   "app",
+				Functions: []*graph.Function{{Name: "Ping", Signature: "func Ping() bool", Body: &graph.LocationNode{Text: "{\n\treturn true\n}"}}},
+			},
+		},
+	}}}
+	baseURL := t.TempDir()
+	aCoder := coder.NewCoder(project)
+
+	// missing files are reported as added
+	diffs, err := aCoder.PreviewStore(context.Background(), baseURL)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, diffs, 2)
+	assert.NoFileExists(t, filepath.Join(baseURL, "app", "app.go"))
+
+	_, err = aCoder.StoreProject(context.Background(), baseURL)
+	if !assert.NoError(t, err) {
+		return
+	}
+	diffs, err = aCoder.PreviewStore(context.Background(), baseURL)
+	assert.NoError(t, err)
+	assert.Empty(t, diffs)
+
+	project.Packages[0].FileSet[0].Functions[0].Body.Text = "{\n\treturn errors.New(\"failed\")\n}"
+	diffs, err = aCoder.PreviewStore(context.Background(), baseURL)
+	if !assert.NoError(t, err) || !assert.Len(t, diffs, 1) {
+		return
+	}
+	assert.Equal(t, `--- a/app/app.go
++++ b/app/app.go
+@@ -1,7 +1,7 @@
+ package app
+ 
+ func Run() error {
+-	return nil
++	return errors.New("failed")
+ }
+ 
+ 
+`, diffs["app/app.go"])
+	content, err := os.ReadFile(filepath.Join(baseURL, "app", "app.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "return nil")
+}
+
+func TestCoder_FileSummary(t *testing.T) {
+	file, err := golang.NewInspector(&graph.Config{IncludeUnexported: true}).InspectSource([]byte(`// Package app serves requests. It is an example.
+package app
+
+import (
+	"context"
+	"fmt"
+)
+
+const Version = "1.0"
+
+const debug = false
+
+var Default = &Service{}
+
+// Service handles requests
+type Service struct{}
+
+func (s *Service) Run(ctx context.Context) error { return nil }
+
+func (s *Service) stop() {}
+
+type handler struct{}
+
+func New() *Service { fmt.Println(debug); return &Service{} }
+`))
+	if !assert.NoError(t, err) {
+		return
+	}
+	file.Name = "app.go"
+	aCoder := coder.NewCoder(&graph.Project{Name: "test"})
+	aCoder.CreatePackage("app", "example.com/app").AddFile(file)
+
+	summary := file.Summary()
+	assert.Equal(t, []string{"Service"}, summary.Types)
+	assert.Equal(t, []string{"New"}, summary.Functions)
+	assert.Equal(t, []string{"Service.Run"}, summary.Methods)
+	assert.Equal(t, []string{"Version"}, summary.Constants)
+	assert.Equal(t, []string{"Default"}, summary.Variables)
+	assert.Equal(t, 2, summary.Imports)
+	assert.Equal(t, file.Lines, summary.Lines)
+	assert.Equal(t, "Package app serves requests.", summary.Description)
+	assert.Equal(t, 5, summary.Exported())
+	assert.Same(t, summary, file.Summary())
+
+	_, err = aCoder.CreateFunction("app", "app.go", "Shutdown", nil, nil, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"New", "Shutdown"}, file.Summary().Functions)
+	_, err = aCoder.CreateMethod("app", "app.go", "Service", "Close", nil, nil, "")
+	assert.NoError(t, err)
+	assert.Len(t, file.Summary().Methods, 2)
+	assert.NoError(t, aCoder.DeleteType("app", "app.go", "Service"))
+	assert.Empty(t, file.Summary().Types)
+}
+
+func TestCoder_StoreProject_Splices(t *testing.T) {
+	root := t.TempDir()
+	source := "package app\n\n// Counter counts\ntype Counter struct{ n int }\n\nfunc (c *Counter) Inc() {\n\tc.n++\n}\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "app.go"), []byte(source), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "util.go"), []byte("package app\n\nfunc ping() {}\n"), 0644))
+	project := &graph.Project{Name: "test", RootPath: root, Packages: []*graph.Package{{
+		Name: "app",
+		FileSet: []*graph.File{
+			{Name: "app.go", Path: "app.go", Package: "app"},
+			{Name: "util.go", Path: "util.go", Package: "app"},
+		},
+	}}}
+	baseURL := t.TempDir()
+	aCoder := coder.NewCoder(project)
+	offset := len("package app\n\n// Counter counts\ntype Counter struct{ n int }\n\nfunc (c *Counter) Inc() {")
+	_, err := aCoder.StoreProject(context.Background(), baseURL, coder.WithSplices(
+		&coder.Splice{Path: "app.go", Offset: offset, Text: "\n\tprintln(\"inc\")"},
+		&coder.Splice{Path: "app.go", Offset: len(source), Text: "\nvar _ = 1\n"},
+	))
+	if !assert.NoError(t, err) {
+		return
+	}
+	content, err := os.ReadFile(filepath.Join(baseURL, "app.go"))
+	assert.NoError(t, err)
+	assert.Equal(t, "package app\n\n// Counter counts\ntype Counter struct{ n int }\n\nfunc (c *Counter) Inc() {\n\tprintln(\"inc\")\n\tc.n++\n}\n\nvar _ = 1\n", string(content))
+	content, err = os.ReadFile(filepath.Join(baseURL, "util.go"))
+	assert.NoError(t, err)
+	assert.Equal(t, "package app\n\nfunc ping() {}\n", string(content), "files without splices are copied")
+
+	_, err = aCoder.StoreProject(context.Background(), baseURL, coder.WithSplices(&coder.Splice{Path: "util.go", Offset: 1000}))
+	assert.Error(t, err)
+}
+
+func TestCoder_StoreProject_RebasedSplices(t *testing.T) {
+	root := t.TempDir()
+	source := "package app\n\nfunc Inc(n int) int {\n\treturn n + 1\n}\n"
+	file := &graph.File{Name: "app.go", Path: "app.go", Package: "app"}
+	file.SetSource([]byte(source))
+	project := &graph.Project{Name: "test", RootPath: root, Packages: []*graph.Package{{Name: "app", FileSet: []*graph.File{file}}}}
+	// the file gets a license header after inspection
+	edited := "// License\n\n" + source
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "app.go"), []byte(edited), 0644))
+	baseURL := t.TempDir()
+	aCoder := coder.NewCoder(project)
+	offset := len("package app\n\nfunc Inc(n int) int {")
+	_, err := aCoder.StoreProject(context.Background(), baseURL, coder.WithSplices(&coder.Splice{Path: "app.go", Offset: offset, Text: "\n\tprintln(n)"}))
+	if !assert.NoError(t, err) {
+		return
+	}
+	content, err := os.ReadFile(filepath.Join(baseURL, "app.go"))
+	assert.NoError(t, err)
+	assert.Equal(t, "// License\n\npackage app\n\nfunc Inc(n int) int {\n\tprintln(n)\n\treturn n + 1\n}\n", string(content))
+
+	// splices into text changed since inspection are rejected
+	file.SetSource([]byte(source))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "app.go"), []byte("package app\n\nfunc Inc(n int) int { return n + 1 }\n"), 0644))
+	_, err = aCoder.StoreProject(context.Background(), baseURL, coder.WithSplices(&coder.Splice{Path: "app.go", Offset: offset, Text: "\n\tprintln(n)"}))
+	assert.Error(t, err)
+}
+
+func TestCoder_StoreProject_SourceMaps(t *testing.T) {
+	project := &graph.Project{Name: "test", Packages: []*graph.Package{{
+		Name: "app",
+		FileSet: []*graph.File{{
+			Name:    "app.go",
+			Path:    "app/app.go",
+			Package: "app",
+			Types: []*graph.Type{
+				{Name: "User", Location: &graph.Location{Raw: "type User struct {"}, Fields: []*graph.Field{
+					{Name: "Name", Location: &graph.Location{Raw: "\tName string"}},
+					{Name: "Age", Location: &graph.Location{Raw: "\tAge  int"}},
+				}},
+				{Name: "Order", Location: &graph.Location{Raw: "type Order struct {"}, Fields: []*graph.Field{
+					{Name: "ID", Location: &graph.Location{Raw: "\tID int"}},
+				}},
+			},
+			Functions: []*graph.Function{{Name: "Label", Receiver: "*User", Signature: "func (u *User) Label() string", Body: &graph.LocationNode{Text: "{\n\treturn u.Name\n}"}}},
+		}},
+	}}}
+	baseURL := t.TempDir()
+	aCoder := coder.NewCoder(project)
+	report, err := aCoder.StoreProject(context.Background(), baseURL, coder.WithSourceMapFile(true))
+	if !assert.NoError(t, err) {
+		return
+	}
+	sourceMaps, err := coder.LoadSourceMaps(baseURL)
+	if !assert.NoError(t, err) || !assert.Contains(t, sourceMaps, "app/app.go") {
+		return
+	}
+	assert.Equal(t, report.SourceMaps, sourceMaps)
+	content, err := os.ReadFile(filepath.Join(baseURL, "app", "app.go"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	declarations := map[string]string{}
+	for _, item := range sourceMaps["app/app.go"].Ranges {
+		declarations[item.Kind+":"+item.Name] = string(content[item.Start:item.End])
+	}
+	assert.Equal(t, map[string]string{
+		"type:User":           "type User struct {\n\tName string\n\tAge  int\n}",
+		"field:User.Name":     "Name string",
+		"field:User.Age":      "Age  int",
+		"type:Order":          "type Order struct {\n\tID int\n}",
+		"field:Order.ID":      "ID int",
+		"function:User.Label": "func (u *User) Label() string {\n\treturn u.Name\n}",
+	}, declarations)
+	for name, declaration := range declarations {
+		if !strings.HasPrefix(name, "field:") {
+			_, err := parser.ParseFile(token.NewFileSet(), "", "package app\n\n"+declaration, 0)
+			assert.NoError(t, err, name)
+		}
+	}
+
+	// locations of the in-memory graph point to the stored file
+	assert.Equal(t, 1, aCoder.ApplySourceMaps(sourceMaps))
+	order := project.Packages[0].FileSet[0].Types[1]
+	assert.Equal(t, "type Order struct {\n\tID int\n}", string(content[order.Location.Start:order.Location.End]))
+	assert.Equal(t, "type Order struct {", order.Location.Raw)
+	label := project.Packages[0].FileSet[0].Functions[0]
+	assert.Equal(t, declarations["function:User.Label"], string(content[label.Location.Start:label.Location.End]))
+}
+
+func TestCoder_Unexport(t *testing.T) {
+	inspector := golang.NewInspector(&graph.Config{IncludeUnexported: true})
+	modelFile, err := inspector.InspectSource([]byte(`package model
+
+// User is an account
+type User struct {
+	ID    int
+	Email string ` + "`mapstructure:\"email\"`" + `
+}
+
+// Audit records changes
+type Audit struct {
+	Action string
+}
+
+// NewAudit creates an audit record
+func NewAudit(action string) *Audit {
+	return &Audit{Action: action}
+}
+
+// Label returns an audit label
+func (a *Audit) Label() string {
+	return a.Action
+}
+
+//linager:keep
+func Lookup(id int) *User {
+	return &User{ID: id}
+}
+`))
+	if !assert.NoError(t, err) {
+		return
+	}
+	appFile, err := inspector.InspectSource([]byte(`package app
+
+import "example.com/app/model"
+
+func Load() int {
+	user := model.User{}
+	return user.ID
+}
+`))
+	if !assert.NoError(t, err) {
+		return
+	}
+	modelFile.Path, appFile.Path = "model/model.go", "app/app.go"
+	project := &graph.Project{Name: "test", Packages: []*graph.Package{
+		{Name: "model", ImportPath: "example.com/app/model", FileSet: []*graph.File{modelFile}},
+		{Name: "app", ImportPath: "example.com/app", FileSet: []*graph.File{appFile}},
+	}}
+
+	candidates := map[string]*graph.UnexportCandidate{}
+	for _, candidate := range project.UnexportCandidates(nil) {
+		if candidate.Ref.Package == "example.com/app/model" {
+			candidates[candidate.Ref.String()] = candidate
+		}
+	}
+	var refs []string
+	for ref := range candidates {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+	assert.Equal(t, []string{
+		"example.com/app/model#Audit",
+		"example.com/app/model#Audit.Label()",
+		"example.com/app/model#Audit/Action",
+		"example.com/app/model#NewAudit(string)",
+	}, refs, "User and its ID are used by app, Email is tag referenced, Lookup is kept")
+	audit := candidates["example.com/app/model#Audit"]
+	if !assert.NotNil(t, audit) {
+		return
+	}
+	assert.Equal(t, graph.ConfidenceHigh, audit.Confidence)
+	assert.Equal(t, "audit", audit.NewName)
+	assert.Equal(t, "model/model.go", audit.Path)
+
+	aCoder := coder.NewCoder(project)
+	assert.NoError(t, aCoder.Unexport(audit, candidates["example.com/app/model#Audit/Action"]))
+	auditType := modelFile.Types[1]
+	assert.Equal(t, "audit", auditType.Name)
+	assert.False(t, auditType.IsExported)
+	assert.Equal(t, "action", auditType.Fields[0].Name)
+	label := auditType.Methods[0]
+	assert.Equal(t, "func (a *audit) Label() string", label.Signature)
+	assert.Equal(t, "{\n\treturn a.action\n}", label.Body.Text)
+	content, err := golang.NewEmitter(nil).Emit(modelFile)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Contains(t, string(content), "func NewAudit(action string) *audit {\n\treturn &audit{action: action}\n}")
+
+	err = aCoder.Unexport(audit)
+	assert.True(t, errors.Is(err, &graph.ErrNotFound{Kind: "type", Name: "example.com/app/model#Audit"}), "%v", err)
+}
+
+func TestCoder_ReplaceTypeUsage(t *testing.T) {
+	civilSource := `package civil
+
+import "time"
+
+// Date is a calendar date
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// String returns the date in ISO 8601 format
+func (d Date) String() string {
+	return ""
+}
+`
+	inspector := golang.NewInspector(&graph.Config{IncludeUnexported: true})
+	civilFile, err := inspector.InspectSource([]byte(civilSource))
+	if !assert.NoError(t, err) {
+		return
+	}
+	appFile, err := inspector.InspectSource([]byte(`package app
+
+import (
+	"fmt"
+	"time"
+)
+
+// Holiday is a public holiday
+type Holiday struct {
+	On   time.Time
+	Name string
+}
+
+// Label returns a holiday label
+func (h *Holiday) Label() string {
+	return fmt.Sprintf("%s %s", h.Name, h.On.String())
+}
+
+// Reschedule moves a holiday
+func (h *Holiday) Reschedule(on time.Time) time.Time {
+	var previous time.Time
+	previous, h.On = h.On, on
+	return previous
+}
+
+// Shift is a work shift
+type Shift struct {
+	Start time.Time
+}
+
+// Epoch returns the shift start in Unix seconds
+func (s *Shift) Epoch() int64 {
+	return s.Start.Unix()
+}
+`))
+	if !assert.NoError(t, err) {
+		return
+	}
+	civilFile.Path, appFile.Path = "civil/civil.go", "app/app.go"
+	project := &graph.Project{Name: "test", Packages: []*graph.Package{
+		{Name: "civil", ImportPath: "example.com/app/civil", FileSet: []*graph.File{civilFile}},
+		{Name: "app", ImportPath: "example.com/app", FileSet: []*graph.File{appFile}},
+	}}
+	aCoder := coder.NewCoder(project)
+	report, err := aCoder.ReplaceTypeUsage("time.Time", "example.com/app/civil.Date", &coder.Filter{Packages: []string{"app"}, Types: []string{"Holiday", "Shift"}})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []*coder.TypeUsageChange{
+		{Path: "app/app.go", Kind: "field", Symbol: "Holiday.On", Old: "time.Time", New: "civil.Date"},
+		{Path: "app/app.go", Kind: "parameter", Symbol: "Holiday.Reschedule.on", Old: "time.Time", New: "civil.Date"},
+		{Path: "app/app.go", Kind: "result", Symbol: "Holiday.Reschedule.0", Old: "time.Time", New: "civil.Date"},
+		{Path: "app/app.go", Kind: "body", Symbol: "Holiday.Reschedule", Old: "time.Time", New: "civil.Date"},
+	}, report.Changes)
+	assert.Equal(t, []*coder.TypeUsageSite{
+		{Path: "app/app.go", Symbol: "Shift.Start", Member: "Unix", Reason: "civil.Date does not provide Unix"},
+	}, report.Flagged)
+	assert.Equal(t, "time.Time", appFile.Types[1].Fields[0].Type.Name, "flagged field is kept")
+	var imports []string
+	for _, imp := range appFile.Imports {
+		imports = append(imports, imp.Path)
+	}
+	assert.Equal(t, []string{"fmt", "time", "example.com/app/civil"}, imports, "time is still used by Shift")
+
+	// the rewritten declarations type-check against the new type
+	source := strings.Builder{}
+	source.WriteString("package app\n\nimport (\n")
+	for _, imp := range appFile.Imports {
+		source.WriteString("\t\"" + imp.Path + "\"\n")
+	}
+	source.WriteString(")\n")
+	for _, aType := range appFile.Types {
+		source.WriteString("\ntype " + aType.Name + " struct {\n")
+		for _, field := range aType.Fields {
+			source.WriteString("\t" + field.Name + " " + field.Type.Name + "\n")
+		}
+		source.WriteString("}\n")
+		for _, method := range aType.Methods {
+			source.WriteString("\n" + method.Signature + " " + method.Body.Text + "\n")
+		}
+	}
+	assert.Contains(t, source.String(), "func (h *Holiday) Reschedule(on civil.Date) civil.Date {\n\tvar previous civil.Date\n")
+	fset := token.NewFileSet()
+	civilAST, err := parser.ParseFile(fset, "civil.go", civilSource, 0)
+	if !assert.NoError(t, err) {
+		return
+	}
+	appAST, err := parser.ParseFile(fset, "app.go", source.String(), 0)
+	if !assert.NoError(t, err, source.String()) {
+		return
+	}
+	civil, err := (&types.Config{Importer: importer.Default()}).Check("example.com/app/civil", fset, []*ast.File{civilAST}, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	config := &types.Config{Importer: importerFunc(func(path string) (*types.Package, error) {
+		if path == "example.com/app/civil" {
+			return civil, nil
+		}
+		return importer.Default().Import(path)
+	})}
+	_, err = config.Check("example.com/app", fset, []*ast.File{appAST}, nil)
+	assert.NoError(t, err, source.String())
+
+	_, err = aCoder.ReplaceTypeUsage("Time", "example.com/app/civil.Date", nil)
+	assert.Error(t, err)
+}
+
+func TestCoder_MoveType(t *testing.T) {
+	newProject := func() *graph.Project {
+		return &graph.Project{Name: "test", Packages: []*graph.Package{
+			{Name: "legacy", ImportPath: "example.com/app/legacy", FileSet: []*graph.File{
+				{Name: "stack.go", Path: "legacy/stack.go", Package: "legacy",
+					Constants: []*graph.Constant{{Name: "Limit", Value: "100", Location: &graph.Location{Raw: "const Limit = 100"}}},
+					Types: []*graph.Type{{Name: "Stack", Package: "legacy", Location: &graph.Location{Raw: "type Stack struct {"}, Fields: []*graph.Field{
+						{Name: "items", Type: &graph.Type{Name: "[]int"}, Location: &graph.Location{Raw: "\titems []int"}},
+					}}},
+					Functions: []*graph.Function{
+						{Name: "Push", Receiver: "*Stack", Signature: "func (s *Stack) Push(v int)", Body: &graph.LocationNode{Text: "{\n\ts.items = append(s.items, clamp(v))\n}"}, References: []string{"clamp"}},
+						{Name: "clamp", Signature: "func clamp(v int) int", Body: &graph.LocationNode{Text: "{\n\tif v > Limit {\n\t\treturn Limit\n\t}\n\treturn v\n}"}},
+						{Name: "Version", Signature: "func Version() string", Body: &graph.LocationNode{Text: "{\n\treturn \"1\"\n}"}},
+					},
+				},
+				{Name: "format.go", Path: "legacy/format.go", Package: "legacy", Imports: []graph.Import{{Path: "fmt"}},
+					Functions: []*graph.Function{
+						{Name: "String", Receiver: "*Stack", Signature: "func (s *Stack) String() string", Body: &graph.LocationNode{Text: "{\n\treturn fmt.Sprint(s.items)\n}"}, References: []string{"fmt.Sprint"}},
+					},
+				},
+			}},
+			{Name: "container", ImportPath: "example.com/app/container", FileSet: []*graph.File{
+				{Name: "doc.go", Path: "container/doc.go", Package: "container", Constants: []*graph.Constant{{Name: "Kind", Value: `"lifo"`, Location: &graph.Location{Raw: `const Kind = "lifo"`}}}},
+			}},
+			{Name: "app", ImportPath: "example.com/app", FileSet: []*graph.File{
+				{Name: "app.go", Path: "app/app.go", Package: "app", Imports: []graph.Import{{Path: "example.com/app/legacy"}},
+					Functions: []*graph.Function{
+						{Name: "Run", Signature: "func Run() string", Body: &graph.LocationNode{Text: "{\n\ts := &legacy.Stack{}\n\ts.Push(1)\n\treturn s.String() + legacy.Version()\n}"}},
+					},
+				},
+				{Name: "queue.go", Path: "app/queue.go", Package: "app", Imports: []graph.Import{{Path: "example.com/app/legacy"}},
+					Variables: []*graph.Variable{{Name: "pending", Type: &graph.Type{Name: "*legacy.Stack"}, Location: &graph.Location{Raw: "var pending *legacy.Stack"}}},
+				},
+			}},
+		}}
+	}
+	project := newProject()
+	aCoder := coder.NewCoder(project)
+	report, err := aCoder.MoveType("legacy", "stack.go", "Stack", "container", "stack.go", nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []string{"Stack", "Stack.Push", "Stack.String", "clamp"}, report.Moved)
+	assert.Equal(t, []string{"app/app.go", "app/queue.go", "container/stack.go", "legacy/format.go", "legacy/stack.go"}, report.Rewritten)
+
+	legacy, container, app := project.Packages[0], project.Packages[1], project.Packages[2]
+	assert.Empty(t, legacy.FileSet[0].Types)
+	assert.Len(t, legacy.FileSet[0].Functions, 1, "Version stays")
+	assert.Empty(t, legacy.FileSet[1].Imports, "fmt is no longer used by the source")
+	if !assert.Len(t, container.FileSet, 2) {
+		return
+	}
+	moved := container.FileSet[1]
+	assert.Equal(t, "container/stack.go", moved.Path)
+	assert.Equal(t, "container", moved.Types[0].Package)
+	assert.Equal(t, "example.com/app/container", moved.Types[0].PackagePath)
+	assert.Equal(t, []graph.Import{{Path: "fmt"}, {Path: "example.com/app/legacy"}}, moved.Imports)
+	assert.Equal(t, "{\n\tif v > legacy.Limit {\n\t\treturn legacy.Limit\n\t}\n\treturn v\n}", moved.Functions[2].Body.Text)
+	assert.Equal(t, "{\n\ts := &container.Stack{}\n\ts.Push(1)\n\treturn s.String() + legacy.Version()\n}", app.FileSet[0].Functions[0].Body.Text)
+	assert.Equal(t, []graph.Import{{Path: "example.com/app/legacy"}, {Path: "example.com/app/container"}}, app.FileSet[0].Imports)
+	assert.Equal(t, []graph.Import{{Path: "example.com/app/container"}}, app.FileSet[1].Imports, "legacy is no longer used")
+	assert.Equal(t, "var pending *container.Stack", app.FileSet[1].Variables[0].Location.Raw)
+
+	// emitted packages compile
+	emitter := golang.NewEmitter(nil)
+	fset := token.NewFileSet()
+	checked := map[string]*types.Package{}
+	config := &types.Config{Importer: importerFunc(func(path string) (*types.Package, error) {
+		if pkg, ok := checked[path]; ok {
+			return pkg, nil
+		}
+		return importer.Default().Import(path)
+	})}
+	for _, pkg := range []*graph.Package{legacy, container, app} {
+		var files []*ast.File
+		for _, file := range pkg.FileSet {
+			content, err := emitter.Emit(file)
+			if !assert.NoError(t, err) {
+				return
+			}
+			parsed, err := parser.ParseFile(fset, file.Path, content, 0)
+			if !assert.NoError(t, err, string(content)) {
+				return
+			}
+			files = append(files, parsed)
+		}
+		checked[pkg.ImportPath], err = config.Check(pkg.ImportPath, fset, files, nil)
+		if !assert.NoError(t, err, pkg.Name) {
+			return
+		}
+	}
+
+	// collisions are reported before anything changes
+	project = newProject()
+	project.Packages[1].FileSet[0].Functions = []*graph.Function{{Name: "clamp", Signature: "func clamp(v int) int", Body: &graph.LocationNode{Text: "{\n\treturn v\n}"}}}
+	report, err = coder.NewCoder(project).MoveType("legacy", "stack.go", "Stack", "container", "stack.go", nil)
+	assert.Error(t, err)
+	assert.Equal(t, []string{"clamp"}, report.Collisions)
+	assert.Len(t, project.Packages[0].FileSet[0].Types, 1)
+	assert.Len(t, project.Packages[1].FileSet, 1)
+
+	// packages must not import each other
+	project = newProject()
+	project.Packages[0].FileSet[0].Functions[2].Body.Text = "{\n\treturn (&Stack{}).String()\n}"
+	_, err = coder.NewCoder(project).MoveType("legacy", "stack.go", "Stack", "container", "stack.go", nil)
+	assert.Error(t, err)
+	assert.Len(t, project.Packages[0].FileSet[0].Types, 1)
+}
+
+func TestCoder_Clone(t *testing.T) {
+	newProject := func() *graph.Project {
+		return &graph.Project{Name: "test", Packages: []*graph.Package{
+			{Name: "model", ImportPath: "example.com/app/model", FileSet: []*graph.File{
+				{Name: "user.go", Path: "model/user.go", Package: "model", Types: []*graph.Type{
+					{Name: "User", Package: "model", Kind: reflect.Struct, Fields: []*graph.Field{{Name: "ID", Type: &graph.Type{Name: "int"}}}},
+				}},
+				{Name: "audit.go", Path: "model/audit.go", Package: "model", Functions: []*graph.Function{
+					{Name: "Audit", Signature: "func Audit() string", Body: &graph.LocationNode{Text: "{\n\treturn \"audit\"\n}"}},
+				}},
+			}},
+			{Name: "app", ImportPath: "example.com/app", FileSet: []*graph.File{
+				{Name: "app.go", Path: "app/app.go", Package: "app"},
+			}},
+		}}
+	}
+	project := newProject()
+	clone := project.Clone()
+	assert.Empty(t, graph.DiffProjects(project, clone).Added)
+
+	// changes of the clone stay in the clone
+	aCoder := coder.NewCoder(clone)
+	_, err := aCoder.CreateField("model", "user.go", "User", "Email", &graph.Type{Name: "string"}, "")
+	assert.NoError(t, err)
+	_, err = aCoder.CreateMethod("model", "user.go", "User", "Name", nil, nil, "{\n\treturn\n}")
+	assert.NoError(t, err)
+	user := project.Packages[0].FileSet[0].Types[0]
+	assert.Len(t, user.Fields, 1)
+	assert.Empty(t, user.Methods)
+	cloned := clone.Packages[0].FileSet[0].Types[0]
+	assert.Len(t, cloned.Fields, 2)
+	assert.Len(t, cloned.Methods, 1)
+	assert.NotSame(t, project.Packages[0], clone.Packages[0], "package header is copied")
+	assert.Same(t, project.Packages[0].FileSet[1], clone.Packages[0].FileSet[1], "untouched files are shared")
+	assert.Same(t, project.Packages[1], clone.Packages[1], "untouched packages are shared")
+	assert.Equal(t, []string{"model/user.go"}, clone.Dirty())
+	assert.Empty(t, project.Dirty())
+	diff := graph.DiffProjects(project, clone)
+	if assert.Len(t, diff.Added, 1) {
+		assert.Equal(t, "example.com/app/model.User.Name", diff.Added[0].Path)
+	}
+
+	// changes of the original stay in the original
+	original := coder.NewCoder(project)
+	assert.NoError(t, original.DeleteFunction("model", "audit.go", "Audit"))
+	_, err = original.CreateFile("app", "run.go", "app/run.go")
+	assert.NoError(t, err)
+	assert.Empty(t, project.Packages[0].FileSet[1].Functions)
+	assert.Len(t, clone.Packages[0].FileSet[1].Functions, 1)
+	assert.Len(t, project.Packages[1].FileSet, 2)
+	assert.Len(t, clone.Packages[1].FileSet, 1)
+	assert.Equal(t, []string{"app/run.go", "model/audit.go"}, project.Dirty())
+	diff = graph.DiffProjects(project, clone)
+	assert.Len(t, diff.Added, 2, "Audit and User.Name exist in the clone only")
+	assert.Empty(t, diff.Removed)
+
+	// moves rewrite copies of shared files only
+	project = newProject()
+	project.Packages[1].FileSet[0].Imports = []graph.Import{{Path: "example.com/app/model"}}
+	project.Packages[1].FileSet[0].Variables = []*graph.Variable{{Name: "current", Type: &graph.Type{Name: "*model.User"}, Location: &graph.Location{Raw: "var current *model.User"}}}
+	clone = project.Clone()
+	_, err = coder.NewCoder(clone).MoveType("model", "user.go", "User", "app", "user.go", nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, project.Packages[0].FileSet[0].Types, 1)
+	assert.Len(t, project.Packages[1].FileSet, 1)
+	assert.Equal(t, "var current *model.User", project.Packages[1].FileSet[0].Variables[0].Location.Raw)
+	assert.Empty(t, clone.Packages[0].FileSet[0].Types)
+	assert.Len(t, clone.Packages[1].FileSet, 2)
+	assert.Equal(t, "var current *User", clone.Packages[1].FileSet[0].Variables[0].Location.Raw)
+	assert.Equal(t, []string{"app/app.go", "app/user.go", "model/user.go"}, clone.Dirty())
+}
+
+func BenchmarkCoder_Clone(b *testing.B) {
+	project := &graph.Project{Name: "bench"}
+	for i := 0; i < 100; i++ {
+		pkg := &graph.Package{Name: fmt.Sprintf("pkg%d", i), ImportPath: fmt.Sprintf("example.com/app/pkg%d", i)}
+		for j := 0; j < 10; j++ {
+			name := fmt.Sprintf("file%d.go", j)
+			pkg.FileSet = append(pkg.FileSet, &graph.File{Name: name, Path: pkg.Name + "/" + name, Package: pkg.Name,
+				Types:     []*graph.Type{{Name: fmt.Sprintf("Type%d", j), Kind: reflect.Struct, Fields: []*graph.Field{{Name: "ID", Type: &graph.Type{Name: "int"}}}}},
+				Functions: []*graph.Function{{Name: fmt.Sprintf("Func%d", j), Body: &graph.LocationNode{Text: "{\n\treturn\n}"}}},
+			})
+		}
+		project.Packages = append(project.Packages, pkg)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		clone := project.Clone()
+		if _, err := coder.NewCoder(clone).CreateFunction("pkg50", "file5.go", "Added", nil, nil, "{\n}"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestCoder_ApplyBatch(t *testing.T) {
+	newProject := func() *graph.Project {
+		return &graph.Project{Name: "test", Packages: []*graph.Package{
+			{Name: "model", ImportPath: "example.com/app/model", FileSet: []*graph.File{
+				{Name: "user.go", Path: "model/user.go", Package: "model",
+					Types: []*graph.Type{{Name: "User", Package: "model", Kind: reflect.Struct, Fields: []*graph.Field{{Name: "ID", Type: &graph.Type{Name: "int"}}}}},
+				},
+			}},
+		}}
+	}
+	fingerprint := func(project *graph.Project) uint64 {
+		ret, err := project.Fingerprint()
+		assert.NoError(t, err)
+		return ret
+	}
+
+	for _, clone := range []bool{false, true} {
+		project := newProject()
+		if clone {
+			project = project.Clone()
+		}
+		before := fingerprint(project)
+		aCoder := coder.NewCoder(project)
+		err := aCoder.ApplyBatch([]coder.Operation{
+			{Name: "create field User.Email", Apply: func(c *coder.Coder) error {
+				_, err := c.CreateField("model", "user.go", "User", "Email", &graph.Type{Name: "string"}, `json:"email"`)
+				return err
+			}},
+			{Name: "create file model/order.go", Apply: func(c *coder.Coder) error {
+				if _, err := c.CreateFile("model", "order.go", "model/order.go"); err != nil {
+					return err
+				}
+				_, err := c.CreateType("model", "order.go", "Order", reflect.Struct)
+				return err
+			}},
+			{Name: "create field Missing.ID", Apply: func(c *coder.Coder) error {
+				_, err := c.CreateField("model", "user.go", "Missing", "ID", &graph.Type{Name: "int"}, "")
+				return err
+			}},
+		})
+		var batchErr *coder.BatchError
+		if assert.True(t, errors.As(err, &batchErr), "%v", err) {
+			assert.Equal(t, 2, batchErr.Index)
+			assert.Equal(t, "create field Missing.ID", batchErr.Operation)
+		}
+		assert.Equal(t, before, fingerprint(project), "clone: %v", clone)
+		assert.Len(t, project.Packages[0].FileSet, 1)
+		assert.Len(t, project.Packages[0].FileSet[0].Types[0].Fields, 1)
+	}
+
+	// a rolled back nested transaction keeps mutations of the enclosing one
+	project := newProject()
+	aCoder := coder.NewCoder(project)
+	initial := fingerprint(project)
+	outer := aCoder.Begin()
+	_, err := aCoder.CreateField("model", "user.go", "User", "Email", &graph.Type{Name: "string"}, "")
+	assert.NoError(t, err)
+	committed := fingerprint(project)
+	inner := aCoder.Begin()
+	_, err = aCoder.CreateField("model", "user.go", "User", "Name", &graph.Type{Name: "string"}, "")
+	assert.NoError(t, err)
+	aCoder.CreatePackage("billing", "example.com/app/billing")
+	assert.Error(t, outer.Commit(), "inner transaction is open")
+	assert.NoError(t, inner.Rollback())
+	assert.ErrorIs(t, inner.Rollback(), coder.ErrTransactionDone)
+	assert.Equal(t, committed, fingerprint(project))
+
+	// the project is not stored while a transaction is open
+	_, err = aCoder.StoreProject(context.Background(), t.TempDir())
+	assert.ErrorIs(t, err, coder.ErrOpenTransaction)
+	_, err = aCoder.StoreProject(context.Background(), t.TempDir(), coder.WithOpenTransaction(true))
+	assert.NoError(t, err)
+	assert.NoError(t, outer.Rollback())
+	assert.Equal(t, initial, fingerprint(project))
+}
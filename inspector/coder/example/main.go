@@ -170,7 +170,7 @@ func main() {
 	fmt.Printf("Storing project to: %s\n", tempDir)
 
 	// Store the project
-	err = projectCoder.StoreProject(ctx, tempDir)
+	_, err = projectCoder.StoreProject(ctx, tempDir)
 	if err != nil {
 		fmt.Printf("Error storing project: %v\n", err)
 		return
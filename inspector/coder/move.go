@@ -0,0 +1,704 @@
+package coder
+
+import (
+	"fmt"
+	"github.com/viant/linager/inspector/graph"
+	"go/token"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// callExpr matches names of unqualified calls in function bodies without recorded references
+var callExpr = regexp.MustCompile(`(?:^|[^.\w])([A-Za-z_]\w*)\s*\(`)
+
+// MoveOptions controls Coder.MoveType, nil uses defaults
+type MoveOptions struct {
+	KeepHelpers bool // unexported helper functions referenced only by the moved type stay in the source package
+}
+
+// MoveReport lists declarations moved by Coder.MoveType and files with rewritten references or imports
+type MoveReport struct {
+	Moved      []string // moved declarations, e.g. Stack, Stack.Push, grow
+	Rewritten  []string // paths of files with rewritten references or imports
+	Collisions []string // moved names already declared by the destination package, nothing is moved when set
+}
+
+// methodSite is a method of a type with its holder: the type, a stub type declared for a receiver in another file or,
+// for receiver functions, the file
+type methodSite struct {
+	file   *graph.File
+	owner  *graph.Type // type holding the method, nil for receiver functions of File.Functions
+	method *graph.Function
+}
+
+// movedFunction is a helper function moved with a type
+type movedFunction struct {
+	file     *graph.File
+	function *graph.Function
+}
+
+// fileEdit is a planned reference rewrite and import change of a file
+type fileEdit struct {
+	file    *graph.File
+	scope   *graph.File // declarations rewritten, the whole file when nil
+	rewrite []func(text string) string
+	add     []graph.Import
+	remove  []string // import paths removed when no longer referenced
+}
+
+// MoveType moves a type, its methods declared anywhere in the source package and unexported helper functions called
+// only by the type into the destination file (created when missing), then rewrites references across the project:
+// qualified references of other packages switch to the destination import, references of the source and destination
+// packages are (un)qualified and unused source imports are removed. Collisions, references to unexported declarations
+// left behind and import cycles are reported before anything is changed.
+func (c *Coder) MoveType(srcPkg, srcFile, typeName, dstPkg, dstFile string, opts *MoveOptions) (*MoveReport, error) {
+	if opts == nil {
+		opts = &MoveOptions{}
+	}
+	file, aType, err := c.lookupType(srcPkg, srcFile, typeName)
+	if err != nil {
+		return nil, err
+	}
+	source, _ := c.lookupPackage(srcPkg)
+	destination, err := c.lookupPackage(dstPkg)
+	if err != nil {
+		return nil, err
+	}
+	sites := packageMethods(source, typeName)
+	var helpers []*movedFunction
+	if !opts.KeepHelpers {
+		helpers = movedHelpers(source, sites)
+	}
+	report := &MoveReport{Moved: []string{typeName}}
+	for _, site := range sites {
+		report.Moved = append(report.Moved, typeName+"."+site.method.Name)
+	}
+	moved := map[string]bool{typeName: true}
+	for _, helper := range helpers {
+		report.Moved = append(report.Moved, helper.function.Name)
+		moved[helper.function.Name] = true
+	}
+	samePackage := source == destination
+	if !samePackage {
+		declared := declaredNames(destination)
+		for _, name := range report.Moved {
+			if declared[name] {
+				report.Collisions = append(report.Collisions, name)
+			}
+		}
+		if len(report.Collisions) > 0 {
+			return report, fmt.Errorf("failed to move %v to package %v: %v already declared", typeName, dstPkg, strings.Join(report.Collisions, ", "))
+		}
+	}
+
+	target, err := c.lookupFile(dstPkg, dstFile)
+	created := err != nil
+	if created {
+		target = &graph.File{Name: dstFile, Path: dstFile, Package: destination.Name, ImportPath: destination.ImportPath}
+		if len(destination.FileSet) > 0 {
+			target.Path = path.Join(path.Dir(destination.FileSet[0].Path), dstFile)
+		}
+	}
+	plan, err := planMove(file, aType, sites, helpers, moved, source, destination, target, samePackage, c.Project)
+	if err != nil {
+		return nil, err
+	}
+
+	// files shared with a cloned project are copied first, the move is planned again against the copies
+	affected := map[*graph.File]bool{file: true, target: true}
+	for _, site := range sites {
+		affected[site.file] = true
+	}
+	for _, helper := range helpers {
+		affected[helper.file] = true
+	}
+	for _, edit := range plan {
+		affected[edit.file] = true
+	}
+	if c.ownFiles(affected) {
+		return c.MoveType(srcPkg, srcFile, typeName, dstPkg, dstFile, opts)
+	}
+
+	// mutation starts here, plans were validated
+	if created {
+		c.addFile(destination, target)
+	}
+	removeType(file, aType)
+	for _, site := range sites {
+		switch {
+		case site.owner == aType:
+		case site.owner != nil:
+			removeMethod(site.file, site.owner, site.method)
+			aType.Methods = append(aType.Methods, site.method)
+		default:
+			removeFunction(site.file, site.method)
+			target.Functions = append(target.Functions, site.method)
+		}
+	}
+	for _, helper := range helpers {
+		removeFunction(helper.file, helper.function)
+		target.Functions = append(target.Functions, helper.function)
+	}
+	aType.Package, aType.PackagePath = destination.Name, destination.ImportPath
+	target.Types = append(target.Types, aType)
+	target.IndexTypes()
+	target.IndexFunctions()
+
+	rewritten := map[string]bool{target.Path: true, file.Path: true}
+	for _, edit := range plan {
+		if edit.apply() {
+			rewritten[edit.file.Path] = true
+		}
+	}
+	for filePath := range rewritten {
+		report.Rewritten = append(report.Rewritten, filePath)
+	}
+	sort.Strings(report.Rewritten)
+	return report, nil
+}
+
+// planMove validates a move and returns reference rewrites and import changes of affected files
+func planMove(file *graph.File, aType *graph.Type, sites []*methodSite, helpers []*movedFunction, moved map[string]bool, source, destination *graph.Package, target *graph.File, samePackage bool, project *graph.Project) ([]*fileEdit, error) {
+	var movedTexts []string
+	movedTexts = append(movedTexts, typeTexts(aType)...)
+	origins := map[*graph.File]bool{file: true}
+	for _, site := range sites {
+		if site.owner != aType {
+			movedTexts = append(movedTexts, functionTexts(site.method)...)
+		}
+		origins[site.file] = true
+	}
+	for _, helper := range helpers {
+		movedTexts = append(movedTexts, functionTexts(helper.function)...)
+		origins[helper.file] = true
+	}
+	// rewrites of moved declarations leave declarations of the destination file intact
+	movedEdit := &fileEdit{file: target, scope: &graph.File{Types: []*graph.Type{aType}}}
+	for _, site := range sites {
+		if site.owner != aType {
+			movedEdit.scope.Functions = append(movedEdit.scope.Functions, site.method)
+		}
+	}
+	for _, helper := range helpers {
+		movedEdit.scope.Functions = append(movedEdit.scope.Functions, helper.function)
+	}
+	var result []*fileEdit
+	// imports used by moved declarations follow them, they are removed from origins when no longer used
+	for origin := range origins {
+		edit := &fileEdit{file: origin}
+		for _, imp := range origin.Imports {
+			if imp.Path == destination.ImportPath || !referencesQualifier(movedTexts, importQualifier(imp)) {
+				continue
+			}
+			for _, candidate := range target.Imports {
+				if candidate.Path != imp.Path && importQualifier(candidate) == importQualifier(imp) {
+					return nil, fmt.Errorf("import %v of moved %v conflicts with %v of %v", imp.Path, aType.Name, candidate.Path, target.Path)
+				}
+			}
+			movedEdit.add = append(movedEdit.add, imp)
+			edit.remove = append(edit.remove, imp.Path)
+		}
+		if origin != target {
+			result = append(result, edit)
+		}
+	}
+	if samePackage {
+		return append([]*fileEdit{movedEdit}, result...), nil
+	}
+
+	// moved declarations must not depend on unexported declarations left behind
+	remaining := declaredNames(source)
+	for name := range remaining {
+		if moved[name] || token.IsExported(name) {
+			continue
+		}
+		if referencesName(movedTexts, name) {
+			return nil, fmt.Errorf("failed to move %v: it references unexported %v of package %v", aType.Name, name, source.Name)
+		}
+	}
+	// exported declarations left behind are qualified by the source import
+	movesIntoSource := false
+	for name := range remaining {
+		if moved[name] || !token.IsExported(name) || !referencesName(movedTexts, name) {
+			continue
+		}
+		ref, imp, err := newTypeReference(target, destination, source.ImportPath, name)
+		if err != nil {
+			return nil, err
+		}
+		movedEdit.rewrite = append(movedEdit.rewrite, replaceRef(name, ref))
+		if imp != nil {
+			movedEdit.add = append(movedEdit.add, *imp)
+		}
+		movesIntoSource = true
+	}
+	// moved declarations referencing the destination package by its import drop the qualifier
+	for name := range declaredNames(destination) {
+		for origin := range origins {
+			if ref := typeReference(origin, source, destination.ImportPath, name); ref != "" && referencesName(movedTexts, ref) {
+				movedEdit.rewrite = append(movedEdit.rewrite, replaceRef(ref, name))
+			}
+		}
+	}
+	result = append([]*fileEdit{movedEdit}, result...)
+
+	sourceImportsDestination := false
+	for _, pkg := range project.Packages {
+		for _, aFile := range pkg.FileSet {
+			texts := fileTexts(aFile)
+			for name := range moved {
+				switch {
+				case pkg == source:
+					if !referencesOutside(aFile, aType, sites, helpers, name) {
+						continue
+					}
+					if !token.IsExported(name) {
+						return nil, fmt.Errorf("failed to move %v: unexported %v is referenced by %v", aType.Name, name, aFile.Path)
+					}
+					ref, imp, err := newTypeReference(aFile, source, destination.ImportPath, name)
+					if err != nil {
+						return nil, err
+					}
+					edit := &fileEdit{file: aFile, rewrite: []func(string) string{replaceRef(name, ref)}}
+					if imp != nil {
+						edit.add = append(edit.add, *imp)
+					}
+					result = append(result, edit)
+					sourceImportsDestination = true
+				case !token.IsExported(name):
+				case pkg == destination:
+					if ref := typeReference(aFile, pkg, source.ImportPath, name); ref != "" && referencesName(texts, ref) {
+						result = append(result, &fileEdit{file: aFile, rewrite: []func(string) string{replaceRef(ref, name)}, remove: []string{source.ImportPath}})
+					}
+				default:
+					ref := typeReference(aFile, pkg, source.ImportPath, name)
+					if ref == "" || !referencesName(texts, ref) {
+						continue
+					}
+					newRef, imp, err := newTypeReference(aFile, pkg, destination.ImportPath, name)
+					if err != nil {
+						return nil, err
+					}
+					edit := &fileEdit{file: aFile, rewrite: []func(string) string{replaceRef(ref, newRef)}, remove: []string{source.ImportPath}}
+					if imp != nil {
+						edit.add = append(edit.add, *imp)
+					}
+					result = append(result, edit)
+				}
+			}
+		}
+	}
+	if sourceImportsDestination && (movesIntoSource || packageImports(destination, source.ImportPath)) {
+		return nil, fmt.Errorf("failed to move %v: packages %v and %v would import each other", aType.Name, source.Name, destination.Name)
+	}
+	return result, nil
+}
+
+// apply rewrites references and changes imports of the file, it reports whether the file changed
+func (e *fileEdit) apply() bool {
+	changed := false
+	scope := e.scope
+	if scope == nil {
+		scope = e.file
+	}
+	for _, rewrite := range e.rewrite {
+		changed = rewriteFile(scope, rewrite) || changed
+	}
+	for _, imp := range e.add {
+		count := len(e.file.Imports)
+		addImport(e.file, imp)
+		changed = changed || count != len(e.file.Imports)
+	}
+	for _, importPath := range e.remove {
+		for _, imp := range e.file.Imports {
+			if imp.Path == importPath && !referencesQualifier(fileTexts(e.file), importQualifier(imp)) {
+				removeImport(e.file, importPath)
+				changed = true
+				break
+			}
+		}
+	}
+	return changed
+}
+
+// packageMethods returns methods of a type declared anywhere in a package: methods of the type and of stub types
+// declared for receivers in other files, and receiver functions
+func packageMethods(pkg *graph.Package, typeName string) []*methodSite {
+	var result []*methodSite
+	for _, file := range pkg.FileSet {
+		for _, aType := range file.Types {
+			if aType.Name != typeName {
+				continue
+			}
+			for _, method := range aType.Methods {
+				result = append(result, &methodSite{file: file, owner: aType, method: method})
+			}
+		}
+		for _, function := range file.Functions {
+			if function.Receiver != "" && receiverTypeName(function.Receiver) == typeName {
+				result = append(result, &methodSite{file: file, method: function})
+			}
+		}
+	}
+	return result
+}
+
+// movedHelpers returns unexported package functions called, directly or through other helpers, only by the methods
+func movedHelpers(pkg *graph.Package, sites []*methodSite) []*movedFunction {
+	moved := map[*graph.Function]bool{}
+	for _, site := range sites {
+		moved[site.method] = true
+	}
+	callers := map[string][]*graph.Function{}
+	helpers := map[string]*movedFunction{}
+	var values []string
+	for _, file := range pkg.FileSet {
+		for _, aType := range file.Types {
+			for _, method := range aType.Methods {
+				for _, name := range calledNames(method) {
+					callers[name] = append(callers[name], method)
+				}
+			}
+		}
+		for _, function := range file.Functions {
+			for _, name := range calledNames(function) {
+				callers[name] = append(callers[name], function)
+			}
+			if function.Receiver == "" && !token.IsExported(function.Name) && function.Name != "init" && function.Name != "main" {
+				helpers[function.Name] = &movedFunction{file: file, function: function}
+			}
+		}
+		for _, constant := range file.Constants {
+			values = append(values, constant.Value)
+		}
+		for _, variable := range file.Variables {
+			values = append(values, variable.Value)
+		}
+	}
+	var result []*movedFunction
+	for changed := true; changed; {
+		changed = false
+		for name, helper := range helpers {
+			if moved[helper.function] || len(callers[name]) == 0 || referencesName(values, name) {
+				continue
+			}
+			only := true
+			for _, caller := range callers[name] {
+				only = only && moved[caller]
+			}
+			if only {
+				moved[helper.function] = true
+				result = append(result, helper)
+				changed = true
+			}
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].function.Name < result[j].function.Name })
+	return result
+}
+
+// calledNames returns unqualified names called by a function: recorded references, body calls when not recorded
+func calledNames(function *graph.Function) []string {
+	var result []string
+	for _, ref := range function.References {
+		if !strings.Contains(ref, ".") {
+			result = append(result, ref)
+		}
+	}
+	if len(function.References) == 0 && function.Body != nil {
+		for _, match := range callExpr.FindAllStringSubmatch(function.Body.Text, -1) {
+			result = append(result, match[1])
+		}
+	}
+	return result
+}
+
+// referencesOutside reports whether declarations of a file other than the moved ones reference a name
+func referencesOutside(file *graph.File, aType *graph.Type, sites []*methodSite, helpers []*movedFunction, name string) bool {
+	excluded := map[*graph.Function]bool{}
+	for _, site := range sites {
+		excluded[site.method] = true
+	}
+	for _, helper := range helpers {
+		excluded[helper.function] = true
+	}
+	var texts []string
+	for _, constant := range file.Constants {
+		texts = append(texts, constantTexts(constant)...)
+	}
+	for _, variable := range file.Variables {
+		texts = append(texts, variableTexts(variable)...)
+	}
+	for _, candidate := range file.Types {
+		if candidate == aType {
+			continue
+		}
+		for _, method := range candidate.Methods {
+			if !excluded[method] {
+				texts = append(texts, functionTexts(method)...)
+			}
+		}
+		candidate := *candidate
+		candidate.Methods = nil
+		texts = append(texts, typeTexts(&candidate)...)
+	}
+	for _, function := range file.Functions {
+		if !excluded[function] {
+			texts = append(texts, functionTexts(function)...)
+		}
+	}
+	return referencesName(texts, name)
+}
+
+// packageImports reports whether any file of a package imports a path
+func packageImports(pkg *graph.Package, importPath string) bool {
+	for _, file := range pkg.FileSet {
+		for _, imp := range file.Imports {
+			if imp.Path == importPath {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// declaredNames returns names of package level declarations
+func declaredNames(pkg *graph.Package) map[string]bool {
+	result := map[string]bool{}
+	for _, file := range pkg.FileSet {
+		for _, aType := range file.Types {
+			result[aType.Name] = true
+		}
+		for _, function := range file.Functions {
+			if function.Receiver == "" {
+				result[function.Name] = true
+			}
+		}
+		for _, constant := range file.Constants {
+			result[constant.Name] = true
+		}
+		for _, variable := range file.Variables {
+			result[variable.Name] = true
+		}
+	}
+	return result
+}
+
+// receiverTypeName returns the type name of a receiver, e.g. s *Stack[T] -> Stack
+func receiverTypeName(receiver string) string {
+	fields := strings.Fields(receiver)
+	if len(fields) == 0 {
+		return ""
+	}
+	name := strings.TrimLeft(fields[len(fields)-1], "*")
+	if index := strings.Index(name, "["); index > 0 {
+		name = name[:index]
+	}
+	return name
+}
+
+// refExpr matches a reference not selected from another expression
+func refExpr(ref string) *regexp.Regexp {
+	return regexp.MustCompile(`(^|[^.\w])` + regexp.QuoteMeta(ref) + `\b`)
+}
+
+// replaceRef returns a rewrite replacing references, e.g. legacy.Stack with container.Stack
+func replaceRef(ref, newRef string) func(text string) string {
+	expr := refExpr(ref)
+	return func(text string) string {
+		return expr.ReplaceAllString(text, "${1}"+newRef)
+	}
+}
+
+// referencesName reports whether any text references a name or a qualified reference
+func referencesName(texts []string, ref string) bool {
+	expr := refExpr(ref)
+	for _, text := range texts {
+		if expr.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// referencesQualifier reports whether any text selects from an import qualifier
+func referencesQualifier(texts []string, qualifier string) bool {
+	expr := regexp.MustCompile(`(^|[^.\w])` + regexp.QuoteMeta(qualifier) + `\.`)
+	for _, text := range texts {
+		if expr.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// fileTexts returns source texts of file declarations
+func fileTexts(file *graph.File) []string {
+	var result []string
+	for _, constant := range file.Constants {
+		result = append(result, constantTexts(constant)...)
+	}
+	for _, variable := range file.Variables {
+		result = append(result, variableTexts(variable)...)
+	}
+	for _, aType := range file.Types {
+		result = append(result, typeTexts(aType)...)
+	}
+	for _, function := range file.Functions {
+		result = append(result, functionTexts(function)...)
+	}
+	return result
+}
+
+func constantTexts(constant *graph.Constant) []string {
+	result := []string{constant.Value}
+	if constant.Location != nil {
+		result = append(result, constant.Location.Raw)
+	}
+	return result
+}
+
+func variableTexts(variable *graph.Variable) []string {
+	result := []string{variable.Value}
+	if variable.Type != nil {
+		result = append(result, variable.Type.Name)
+	}
+	if variable.Location != nil {
+		result = append(result, variable.Location.Raw)
+	}
+	return result
+}
+
+// typeTexts returns source texts of a type declaration, its fields and methods
+func typeTexts(aType *graph.Type) []string {
+	var result []string
+	if aType.Location != nil {
+		result = append(result, aType.Location.Raw)
+	}
+	for _, field := range aType.Fields {
+		if field.Type != nil {
+			result = append(result, field.Type.Name)
+		}
+		if field.Location != nil {
+			result = append(result, field.Location.Raw)
+		}
+	}
+	for _, method := range aType.Methods {
+		result = append(result, functionTexts(method)...)
+	}
+	return result
+}
+
+// functionTexts returns source texts of a function declaration
+func functionTexts(function *graph.Function) []string {
+	result := []string{function.Signature}
+	if function.Location != nil {
+		result = append(result, function.Location.Raw)
+	}
+	if function.Body != nil {
+		result = append(result, function.Body.Text)
+	}
+	for _, params := range [][]*graph.Parameter{function.Parameters, function.Results} {
+		for _, param := range params {
+			if param.Type != nil {
+				result = append(result, param.Type.Name)
+			}
+		}
+	}
+	return result
+}
+
+// rewriteFile applies rewrite to declaration texts and type names of a file, it reports whether anything changed
+func rewriteFile(file *graph.File, rewrite func(text string) string) bool {
+	changed := false
+	text := func(value *string) {
+		if rewritten := rewrite(*value); rewritten != *value {
+			*value, changed = rewritten, true
+		}
+	}
+	location := func(location *graph.Location) {
+		if location != nil {
+			text(&location.Raw)
+		}
+	}
+	typeName := func(aType *graph.Type) {
+		if aType != nil {
+			text(&aType.Name)
+		}
+	}
+	function := func(function *graph.Function) {
+		text(&function.Signature)
+		location(function.Location)
+		if function.Body != nil {
+			text(&function.Body.Text)
+		}
+		for _, params := range [][]*graph.Parameter{function.Parameters, function.Results} {
+			for _, param := range params {
+				typeName(param.Type)
+			}
+		}
+		for i := range function.References {
+			text(&function.References[i])
+		}
+	}
+	for _, constant := range file.Constants {
+		text(&constant.Value)
+		location(constant.Location)
+	}
+	for _, variable := range file.Variables {
+		text(&variable.Value)
+		typeName(variable.Type)
+		location(variable.Location)
+	}
+	for _, aType := range file.Types {
+		location(aType.Location)
+		for _, field := range aType.Fields {
+			typeName(field.Type)
+			location(field.Location)
+		}
+		for _, method := range aType.Methods {
+			function(method)
+		}
+	}
+	for _, fn := range file.Functions {
+		function(fn)
+	}
+	return changed
+}
+
+// removeType removes a type from a file
+func removeType(file *graph.File, aType *graph.Type) {
+	for i, candidate := range file.Types {
+		if candidate == aType {
+			file.Types = append(file.Types[:i], file.Types[i+1:]...)
+			break
+		}
+	}
+	file.IndexTypes()
+}
+
+// removeMethod removes a method from its holder type, stub types without methods are removed from the file
+func removeMethod(file *graph.File, owner *graph.Type, method *graph.Function) {
+	for i, candidate := range owner.Methods {
+		if candidate == method {
+			owner.Methods = append(owner.Methods[:i], owner.Methods[i+1:]...)
+			break
+		}
+	}
+	if len(owner.Methods) == 0 && len(owner.Fields) == 0 && (owner.Location == nil || owner.Location.Raw == "") {
+		removeType(file, owner)
+	}
+}
+
+// removeFunction removes a function from a file
+func removeFunction(file *graph.File, function *graph.Function) {
+	for i, candidate := range file.Functions {
+		if candidate == function {
+			file.Functions = append(file.Functions[:i], file.Functions[i+1:]...)
+			break
+		}
+	}
+	file.IndexFunctions()
+}
@@ -0,0 +1,285 @@
+package coder
+
+import (
+	"fmt"
+	"github.com/viant/linager/inspector/graph"
+	"go/ast"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// identifierExpr matches identifiers of a type expression, qualified identifiers are matched with their qualifier
+var identifierExpr = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?`)
+
+// ScaffoldOption represents a GenerateTestScaffold option
+type ScaffoldOption func(*scaffoldOptions)
+
+type scaffoldOptions struct {
+	external bool
+}
+
+// WithExternalTests generates tests in the <package>_test package, tests share the package under test by default
+func WithExternalTests(external bool) ScaffoldOption {
+	return func(o *scaffoldOptions) {
+		o.external = external
+	}
+}
+
+// scaffold holds state of test generation for a single package
+type scaffold struct {
+	coder    *Coder
+	project  *graph.Project
+	pkg      *graph.Package
+	external bool
+	types    map[string]bool        // types declared in the package
+	existing map[string]bool        // test functions already present in the package
+	files    map[string]*graph.File // test files by name
+}
+
+// GenerateTestScaffold adds a table-driven test skeleton for every exported function and method of the package,
+// one <file>_test.go per source file; existing test functions are kept and only missing TestXxx functions are added
+func (c *Coder) GenerateTestScaffold(packageName string, options ...ScaffoldOption) ([]*graph.File, error) {
+	pkg, err := c.lookupPackage(packageName)
+	if err != nil {
+		return nil, err
+	}
+	opts := &scaffoldOptions{}
+	for _, option := range options {
+		option(opts)
+	}
+	s := &scaffold{coder: c, project: c.Project, pkg: pkg, external: opts.external, types: map[string]bool{}, existing: map[string]bool{}, files: map[string]*graph.File{}}
+	var sources []*graph.File
+	for _, file := range pkg.FileSet {
+		if strings.HasSuffix(file.Name, "_test.go") {
+			s.files[file.Name] = file
+			for _, function := range file.Functions {
+				s.existing[function.Name] = true
+			}
+			continue
+		}
+		if filepath.Ext(file.Name) != ".go" {
+			continue
+		}
+		sources = append(sources, file)
+		for _, typ := range file.Types {
+			s.types[typ.Name] = true
+		}
+	}
+
+	var result []*graph.File
+	for _, file := range sources {
+		var tests []*graph.Function
+		for _, function := range file.Functions {
+			if test := s.testFunction(file, nil, function); test != nil {
+				tests = append(tests, test)
+			}
+		}
+		for _, typ := range file.Types {
+			if typ.Kind == reflect.Interface || !typ.IsExported {
+				continue
+			}
+			for _, method := range typ.Methods {
+				if test := s.testFunction(file, typ, method); test != nil {
+					tests = append(tests, test)
+				}
+			}
+		}
+		if len(tests) == 0 {
+			continue
+		}
+		testFile := s.testFile(file)
+		testFile.Functions = append(testFile.Functions, tests...)
+		testFile.IndexFunctions()
+		result = append(result, testFile)
+	}
+	return result, nil
+}
+
+// testFile returns existing or new test file of a source file
+func (s *scaffold) testFile(file *graph.File) *graph.File {
+	name := strings.TrimSuffix(file.Name, ".go") + "_test.go"
+	if testFile, ok := s.files[name]; ok {
+		testFile = s.project.MutableFile(s.pkg, testFile)
+		s.coder.recordFile(testFile)
+		return testFile
+	}
+	packageName := file.Package
+	if s.external {
+		packageName += "_test"
+	}
+	testFile := &graph.File{
+		Name:       name,
+		Path:       filepath.Join(filepath.Dir(file.Path), name),
+		Package:    packageName,
+		ImportPath: file.ImportPath,
+		Types:      []*graph.Type{},
+		Constants:  []*graph.Constant{},
+		Variables:  []*graph.Variable{},
+		Functions:  []*graph.Function{},
+		Imports:    []graph.Import{},
+	}
+	s.files[name] = testFile
+	s.coder.addFile(s.pkg, testFile)
+	return testFile
+}
+
+// testFunction returns a table-driven test skeleton of an exported function or method, nil if the test exists
+// or the function cannot be called from the test package
+func (s *scaffold) testFunction(file *graph.File, typ *graph.Type, function *graph.Function) *graph.Function {
+	if !function.IsExported {
+		return nil
+	}
+	name := "Test" + function.Name
+	if typ != nil {
+		name = "Test" + typ.Name + "_" + function.Name
+	}
+	if s.existing[name] {
+		return nil
+	}
+	typeParams := map[string]bool{}
+	for _, param := range function.TypeParams {
+		typeParams[param.Name] = true
+	}
+	imports := map[string]graph.Import{"testing": {Path: "testing"}}
+	qualify := func(typeName string) (string, bool) {
+		ok := true
+		typeName = identifierExpr.ReplaceAllStringFunc(typeName, func(ident string) string {
+			if qualifier, _, found := strings.Cut(ident, "."); found {
+				for _, imp := range file.Imports {
+					if imp.LocalName() == qualifier {
+						imports[imp.Path] = imp
+					}
+				}
+				return ident
+			}
+			switch {
+			case typeParams[ident]:
+				return "any" // type parameters are instantiated with any
+			case s.types[ident] && s.external:
+				if !ast.IsExported(ident) {
+					ok = false
+				}
+				imports[s.pkg.ImportPath] = graph.Import{Path: s.pkg.ImportPath}
+				return s.pkg.Name + "." + ident
+			}
+			return ident
+		})
+		return typeName, ok
+	}
+
+	fields := []string{"description string"}
+	var args []string
+	for i, param := range function.Parameters {
+		field := param.Name
+		if field == "" || field == "_" {
+			field = fmt.Sprintf("arg%d", i)
+		}
+		fieldType, ok := qualify(param.Type.Name)
+		if !ok {
+			return nil
+		}
+		arg := "testCase." + field
+		if param.IsVariadic {
+			fieldType = "[]" + fieldType
+			arg += "..."
+		}
+		fields = append(fields, field+" "+fieldType)
+		args = append(args, arg)
+	}
+	var gots []string
+	var checks []string
+	for i := range function.Results {
+		fieldType, ok := qualify(function.Results[i].Type.Name)
+		if !ok {
+			return nil
+		}
+		suffix := ""
+		if i > 0 {
+			suffix = fmt.Sprint(i)
+		}
+		fields = append(fields, "expect"+suffix+" "+fieldType)
+		gots = append(gots, "got"+suffix)
+		checks = append(checks, fmt.Sprintf("\t\t\tif !reflect.DeepEqual(got%v, testCase.expect%v) {\n\t\t\t\tt.Errorf(\"expected %%v, but had %%v\", testCase.expect%v, got%v)\n\t\t\t}\n", suffix, suffix, suffix, suffix))
+	}
+	if len(checks) > 0 {
+		imports["reflect"] = graph.Import{Path: "reflect"}
+	}
+
+	call := function.Name
+	if len(function.TypeParams) > 0 && typ == nil {
+		call += "[" + strings.TrimSuffix(strings.Repeat("any, ", len(function.TypeParams)), ", ") + "]"
+	}
+	setup := ""
+	if typ != nil {
+		receiver, ok := qualify(typ.Name + typeArguments(typ))
+		if !ok {
+			return nil
+		}
+		setup = "\t\t\tvar receiver " + receiver + " // TODO: initialize receiver\n"
+		call = "receiver." + call
+	} else if s.external {
+		call = s.pkg.Name + "." + call
+		imports[s.pkg.ImportPath] = graph.Import{Path: s.pkg.ImportPath}
+	}
+	call += "(" + strings.Join(args, ", ") + ")"
+	if len(gots) > 0 {
+		call = strings.Join(gots, ", ") + " := " + call
+	}
+
+	body := &strings.Builder{}
+	body.WriteString("{\n\ttestCases := []struct {\n")
+	for _, field := range fields {
+		body.WriteString("\t\t" + field + "\n")
+	}
+	body.WriteString("\t}{\n\t\t{\n\t\t\tdescription: \"TODO\",\n\t\t},\n\t}\n")
+	body.WriteString("\tfor _, testCase := range testCases {\n\t\tt.Run(testCase.description, func(t *testing.T) {\n")
+	body.WriteString(setup)
+	body.WriteString("\t\t\t" + call + "\n")
+	for _, check := range checks {
+		body.WriteString(check)
+	}
+	body.WriteString("\t\t})\n\t}\n}")
+
+	testFile := s.testFile(file)
+	importPaths := make([]string, 0, len(imports))
+	for importPath := range imports {
+		importPaths = append(importPaths, importPath)
+	}
+	sort.Strings(importPaths)
+	for _, importPath := range importPaths {
+		addImport(testFile, imports[importPath])
+	}
+	s.existing[name] = true
+	return &graph.Function{
+		Name:       name,
+		Signature:  "func " + name + "(t *testing.T)",
+		Parameters: []*graph.Parameter{{Name: "t", Type: &graph.Type{Name: "*testing.T"}}},
+		Body:       &graph.LocationNode{Text: body.String()},
+		IsExported: true,
+	}
+}
+
+// typeArguments returns any instantiation of type parameters, e.g. [any, any]
+func typeArguments(typ *graph.Type) string {
+	if len(typ.TypeParams) == 0 {
+		return ""
+	}
+	return "[" + strings.TrimSuffix(strings.Repeat("any, ", len(typ.TypeParams)), ", ") + "]"
+}
+
+// addImport adds import to the file unless already imported
+func addImport(file *graph.File, imp graph.Import) {
+	for _, candidate := range file.Imports {
+		if candidate.Path == imp.Path {
+			return
+		}
+	}
+	if imp.Name == path.Base(imp.Path) {
+		imp.Name = ""
+	}
+	file.Imports = append(file.Imports, imp)
+}
@@ -0,0 +1,64 @@
+package coder
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/viant/linager/inspector/graph"
+	"os"
+	"path/filepath"
+)
+
+// storeSourceMaps writes source maps to SourceMapFile, unchanged maps are not rewritten
+func storeSourceMaps(baseURL string, sourceMaps map[string]*graph.SourceMap) error {
+	if sourceMaps == nil {
+		sourceMaps = map[string]*graph.SourceMap{}
+	}
+	data, err := json.MarshalIndent(sourceMaps, "", "  ")
+	if err != nil {
+		return err
+	}
+	location := filepath.Join(baseURL, SourceMapFile)
+	if existing, err := os.ReadFile(location); err == nil && bytes.Equal(existing, data) {
+		return nil
+	}
+	return writeFile(location, data)
+}
+
+// LoadSourceMaps returns source maps stored by StoreProject with WithSourceMapFile to baseURL, keyed by path;
+// nil without a source map file
+func LoadSourceMaps(baseURL string) (map[string]*graph.SourceMap, error) {
+	data, err := os.ReadFile(filepath.Join(baseURL, SourceMapFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var result map[string]*graph.SourceMap
+	if err = json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("invalid source map file %s: %w", SourceMapFile, err)
+	}
+	return result, nil
+}
+
+// ApplySourceMaps updates locations of project declarations to ranges of stored files, e.g. from StoreReport.SourceMaps
+// or LoadSourceMaps, and returns the number of files updated
+func (c *Coder) ApplySourceMaps(sourceMaps map[string]*graph.SourceMap) int {
+	if c.Project == nil {
+		return 0
+	}
+	updated := 0
+	for _, pkg := range c.Project.Packages {
+		for _, file := range pkg.FileSet {
+			if sourceMap, ok := sourceMaps[file.Path]; ok {
+				file = c.Project.MutableFile(pkg, file)
+				c.recordFile(file)
+				file.ApplySourceMap(sourceMap)
+				updated++
+			}
+		}
+	}
+	return updated
+}
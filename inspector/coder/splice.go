@@ -0,0 +1,56 @@
+package coder
+
+import (
+	"fmt"
+	"github.com/viant/linager/inspector/graph"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Splice inserts text at a byte offset of a project file original source, e.g. at graph.Function Body.Location offsets
+type Splice struct {
+	Path   string // project file path, as graph.File Path
+	Offset int    // byte offset in the original source
+	Text   string
+}
+
+// spliceSource returns original file source with splices inserted, splices at the same offset keep their order;
+// when the source changed on disk since inspection, file locations are rebased (see graph.File.Rebase) and splice
+// offsets are mapped to the changed source, splices within changed text fail
+func (c *Coder) spliceSource(file *graph.File, splices []*Splice) ([]byte, error) {
+	location := file.Path
+	if !filepath.IsAbs(location) && c.Project.RootPath != "" {
+		location = filepath.Join(c.Project.RootPath, location)
+	}
+	source, err := os.ReadFile(location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read source: %w", graph.NotFoundError("file", file.Path, err))
+	}
+	sorted := make([]*Splice, len(splices))
+	copy(sorted, splices)
+	if hash, _ := graph.Hash(source); file.Hash != 0 && hash != file.Hash {
+		rebased := file.Rebase(source)
+		for i, splice := range sorted {
+			offset, ok := rebased.Offset(splice.Offset)
+			if !ok {
+				return nil, fmt.Errorf("splice offset %d of %s falls into text changed since inspection", splice.Offset, file.Path)
+			}
+			moved := *splice
+			moved.Offset = offset
+			sorted[i] = &moved
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Offset < sorted[j].Offset })
+	result := make([]byte, 0, len(source))
+	offset := 0
+	for _, splice := range sorted {
+		if splice.Offset < 0 || splice.Offset > len(source) {
+			return nil, fmt.Errorf("splice offset %d out of range of %s (%d bytes)", splice.Offset, file.Path, len(source))
+		}
+		result = append(result, source[offset:splice.Offset]...)
+		result = append(result, splice.Text...)
+		offset = splice.Offset
+	}
+	return append(result, source[offset:]...), nil
+}
@@ -0,0 +1,241 @@
+package coder
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/viant/linager/inspector/graph"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// ManifestFile lists paths written by StoreProject, relative to the target directory
+const ManifestFile = ".linager-manifest.json"
+
+// SourceMapFile holds source maps of files written by StoreProject with WithSourceMapFile, keyed by path
+const SourceMapFile = ".linager-sourcemap.json"
+
+// StoredPath describes a file or asset handled by StoreProject
+type StoredPath struct {
+	Path  string `json:"path"`
+	Bytes int    `json:"bytes"`
+	Error string `json:"error,omitempty"`
+}
+
+// StoreReport lists written, skipped (unchanged), failed and deleted paths of StoreProject call
+type StoreReport struct {
+	Written []*StoredPath `json:"written,omitempty"`
+	Skipped []*StoredPath `json:"skipped,omitempty"`
+	Failed  []*StoredPath `json:"failed,omitempty"`
+	Deleted []string      `json:"deleted,omitempty"`
+	// SourceMaps locates declarations emitted to stored files, keyed by path; spliced files and assets have none
+	SourceMaps map[string]*graph.SourceMap `json:"sourceMaps,omitempty"`
+}
+
+// StoreOption represents a StoreProject option
+type StoreOption func(*storeOptions)
+
+type storeOptions struct {
+	prune           bool
+	sourceMaps      bool
+	splices         map[string][]*Splice
+	openTransaction bool
+}
+
+// WithOpenTransaction allows storing the project while a transaction is open, mutations stored this way are kept on
+// disk by Rollback
+func WithOpenTransaction(allowed bool) StoreOption {
+	return func(o *storeOptions) {
+		o.openTransaction = allowed
+	}
+}
+
+// WithPrune deletes files no longer represented in the project, only files listed in the manifest
+// of a previous StoreProject call are ever deleted
+func WithPrune(prune bool) StoreOption {
+	return func(o *storeOptions) {
+		o.prune = prune
+	}
+}
+
+// WithSourceMapFile persists source maps of stored files to SourceMapFile of the target directory, see LoadSourceMaps
+func WithSourceMapFile(enabled bool) StoreOption {
+	return func(o *storeOptions) {
+		o.sourceMaps = enabled
+	}
+}
+
+// WithSplices stores source files from their original content with splices inserted instead of re-emitting them,
+// declarations the emitter does not reproduce are preserved
+func WithSplices(splices ...*Splice) StoreOption {
+	return func(o *storeOptions) {
+		if o.splices == nil {
+			o.splices = map[string][]*Splice{}
+		}
+		for _, splice := range splices {
+			o.splices[splice.Path] = append(o.splices[splice.Path], splice)
+		}
+	}
+}
+
+// store writes content unless the existing file has identical content, existing file is read only when sizes match
+func (r *StoreReport) store(baseURL, path string, content []byte) {
+	stored := &StoredPath{Path: path, Bytes: len(content)}
+	location := filepath.Join(baseURL, path)
+	same, err := sameContent(location, content)
+	if err == nil && same {
+		r.Skipped = append(r.Skipped, stored)
+		return
+	}
+	if err == nil {
+		err = writeFile(location, content)
+	}
+	if err != nil {
+		stored.Error = err.Error()
+		r.Failed = append(r.Failed, stored)
+		return
+	}
+	r.Written = append(r.Written, stored)
+}
+
+// Err returns an error joining all failed paths
+func (r *StoreReport) Err() error {
+	var errs []error
+	for _, failed := range r.Failed {
+		errs = append(errs, fmt.Errorf("failed to store %s: %s", failed.Path, failed.Error))
+	}
+	return errors.Join(errs...)
+}
+
+// stored returns written and skipped paths
+func (r *StoreReport) stored() []string {
+	var result []string
+	for _, list := range [][]*StoredPath{r.Written, r.Skipped} {
+		for _, item := range list {
+			result = append(result, item.Path)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// mergePaths returns sorted distinct union of paths
+func mergePaths(paths []string, added []string) []string {
+	unique := map[string]bool{}
+	for _, path := range append(paths, added...) {
+		unique[path] = true
+	}
+	result := make([]string, 0, len(unique))
+	for path := range unique {
+		result = append(result, path)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// sameContent reports whether the file at location has the given content
+func sameContent(location string, content []byte) (bool, error) {
+	info, err := os.Stat(location)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if info.IsDir() || info.Size() != int64(len(content)) {
+		return false, nil
+	}
+	existing, err := os.ReadFile(location)
+	if err != nil {
+		return false, err
+	}
+	existingHash, err := graph.Hash(existing)
+	if err != nil {
+		return false, err
+	}
+	contentHash, err := graph.Hash(content)
+	if err != nil {
+		return false, err
+	}
+	return existingHash == contentHash, nil
+}
+
+func writeFile(location string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(location), 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", filepath.Dir(location), err)
+	}
+	return os.WriteFile(location, content, 0644)
+}
+
+// loadManifest returns paths written by a previous StoreProject call
+func loadManifest(baseURL string) ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(baseURL, ManifestFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	if err = json.Unmarshal(data, &paths); err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", ManifestFile, err)
+	}
+	return paths, nil
+}
+
+// storeManifest records stored paths, unchanged manifest is not rewritten
+func storeManifest(baseURL string, paths []string) error {
+	data, err := json.MarshalIndent(paths, "", "  ")
+	if err != nil {
+		return err
+	}
+	location := filepath.Join(baseURL, ManifestFile)
+	if existing, err := os.ReadFile(location); err == nil && bytes.Equal(existing, data) {
+		return nil
+	}
+	return writeFile(location, data)
+}
+
+// prune deletes manifest listed paths that were not stored by the current call
+func (r *StoreReport) prune(baseURL string, previous []string) {
+	current := map[string]bool{}
+	for _, path := range r.stored() {
+		current[path] = true
+	}
+	for _, item := range r.Failed {
+		current[item.Path] = true // keep previous version of failed paths
+	}
+	for _, path := range previous {
+		if current[path] {
+			continue
+		}
+		location := filepath.Join(baseURL, path)
+		if err := os.Remove(location); err != nil && !errors.Is(err, os.ErrNotExist) {
+			r.Failed = append(r.Failed, &StoredPath{Path: path, Error: err.Error()})
+			continue
+		}
+		r.Deleted = append(r.Deleted, path)
+	}
+}
+
+// previewDiff returns a unified diff between the file at path and content, empty when equal
+func previewDiff(baseURL, path string, content []byte) (string, error) {
+	location := filepath.Join(baseURL, path)
+	if same, err := sameContent(location, content); err != nil || same {
+		return "", err
+	}
+	existing, err := os.ReadFile(location)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(existing)),
+		B:        difflib.SplitLines(string(content)),
+		FromFile: "a/" + path,
+		ToFile:   "b/" + path,
+		Context:  3,
+	})
+}
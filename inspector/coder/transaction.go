@@ -0,0 +1,169 @@
+package coder
+
+import (
+	"errors"
+	"fmt"
+	"github.com/viant/linager/inspector/graph"
+	"slices"
+)
+
+// ErrOpenTransaction reports StoreProject called with an uncommitted transaction, see WithOpenTransaction
+var ErrOpenTransaction = errors.New("transaction is not committed")
+
+// ErrTransactionDone reports Commit or Rollback of a finished transaction
+var ErrTransactionDone = errors.New("transaction is already committed or rolled back")
+
+// Transaction records Coder mutations with the data needed to invert them, see Coder.Begin
+type Transaction struct {
+	coder  *Coder
+	parent *Transaction
+	undo   []func()             // inverse operations in mutation order
+	saved  map[*graph.File]bool // files with recorded prior content
+	done   bool
+}
+
+// Begin starts a transaction recording mutations made by the Coder until Commit or Rollback; transactions nest, the
+// innermost open transaction records mutations and has to finish first
+func (c *Coder) Begin() *Transaction {
+	tx := &Transaction{coder: c, parent: c.tx, saved: map[*graph.File]bool{}}
+	c.tx = tx
+	return tx
+}
+
+// Commit keeps mutations of the transaction, mutations of a nested transaction are handed to the enclosing one and
+// are reverted by its Rollback
+func (t *Transaction) Commit() error {
+	if err := t.finish(); err != nil {
+		return err
+	}
+	if t.parent != nil {
+		t.parent.undo = append(t.parent.undo, t.undo...)
+	}
+	t.undo = nil
+	return nil
+}
+
+// Rollback reverts mutations of the transaction in reverse order restoring the prior project state, mutations of
+// enclosing transactions are kept
+func (t *Transaction) Rollback() error {
+	if err := t.finish(); err != nil {
+		return err
+	}
+	for i := len(t.undo) - 1; i >= 0; i-- {
+		t.undo[i]()
+	}
+	t.undo = nil
+	return nil
+}
+
+// finish ends the innermost open transaction
+func (t *Transaction) finish() error {
+	if t.done {
+		return ErrTransactionDone
+	}
+	if t.coder.tx != t {
+		return fmt.Errorf("failed to finish transaction: nested transaction is still open")
+	}
+	t.done = true
+	t.coder.tx = t.parent
+	return nil
+}
+
+// Operation is a Coder mutation applied by ApplyBatch
+type Operation struct {
+	Name  string // Operation description used in errors, e.g. create field User.Email
+	Apply func(c *Coder) error
+}
+
+// BatchError reports the failed operation of ApplyBatch
+type BatchError struct {
+	Index     int    // Position of the failed operation
+	Operation string // Name of the failed operation
+	Err       error
+}
+
+// Error returns error message
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("batch operation %d (%s) failed: %v", e.Index, e.Operation, e.Err)
+}
+
+// Unwrap returns the operation error
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// ApplyBatch applies operations within a transaction: either all operations are committed or the project is rolled
+// back to its state before the batch and a BatchError reports the failed operation
+func (c *Coder) ApplyBatch(operations []Operation) error {
+	tx := c.Begin()
+	for i, operation := range operations {
+		if err := operation.Apply(c); err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				return errors.Join(&BatchError{Index: i, Operation: operation.Name, Err: err}, rollbackErr)
+			}
+			return &BatchError{Index: i, Operation: operation.Name, Err: err}
+		}
+	}
+	return tx.Commit()
+}
+
+// record adds an inverse operation to the open transaction
+func (c *Coder) record(undo func()) {
+	if c.tx != nil {
+		c.tx.undo = append(c.tx.undo, undo)
+	}
+}
+
+// recordFile records the content of a file before its first change in the open transaction, the file has to be safe
+// to change (see editFile)
+func (c *Coder) recordFile(file *graph.File) {
+	if c.tx == nil || c.tx.saved[file] {
+		return
+	}
+	c.tx.saved[file] = true
+	saved := graph.CopyFile(file)
+	c.record(func() { *file = *saved })
+}
+
+// addPackage adds a package to the project, recording its removal
+func (c *Coder) addPackage(pkg *graph.Package) {
+	c.Project.AddPackage(pkg)
+	c.record(func() {
+		if i := slices.Index(c.Project.Packages, pkg); i != -1 {
+			c.Project.Packages = slices.Delete(c.Project.Packages, i, i+1)
+			c.Project.IndexPackages()
+		}
+	})
+}
+
+// removePackage removes the package at an index of the project, recording its reinsertion
+func (c *Coder) removePackage(i int) {
+	pkg := c.Project.Packages[i]
+	c.Project.Packages = slices.Delete(c.Project.Packages, i, i+1)
+	c.Project.IndexPackages()
+	c.record(func() {
+		c.Project.Packages = slices.Insert(c.Project.Packages, i, pkg)
+		c.Project.IndexPackages()
+	})
+}
+
+// addFile adds a file to a package, recording its removal
+func (c *Coder) addFile(pkg *graph.Package, file *graph.File) {
+	c.Project.AddFile(pkg, file)
+	c.record(func() {
+		pkg := c.Project.MutablePackage(pkg)
+		if i := slices.Index(pkg.FileSet, file); i != -1 {
+			pkg.FileSet = slices.Delete(pkg.FileSet, i, i+1)
+		}
+	})
+}
+
+// removeFile removes the file at an index of a package safe to change, recording its reinsertion
+func (c *Coder) removeFile(pkg *graph.Package, i int) {
+	file := pkg.FileSet[i]
+	pkg.FileSet = slices.Delete(pkg.FileSet, i, i+1)
+	c.record(func() {
+		pkg := c.Project.MutablePackage(pkg)
+		pkg.FileSet = slices.Insert(pkg.FileSet, i, file)
+	})
+}
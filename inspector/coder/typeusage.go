@@ -0,0 +1,452 @@
+package coder
+
+import (
+	"fmt"
+	"github.com/viant/linager/inspector/graph"
+	"go/importer"
+	"go/token"
+	"go/types"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Filter selects declarations a type replacement applies to; a nil or empty filter selects every declaration
+type Filter struct {
+	Packages  []string // package names or import paths, all packages when empty
+	Types     []string // types whose fields and methods are rewritten
+	Functions []string // package level functions rewritten, all of them when both Types and Functions are empty
+}
+
+// TypeUsageChange is a rewritten type usage
+type TypeUsageChange struct {
+	Path   string // file path
+	Kind   string // field, parameter, result, variable or body (local declarations and composite literals)
+	Symbol string // declaring symbol, e.g. Event.At, NewEvent.at
+	Old    string // previous type expression, e.g. *time.Time
+	New    string // replaced type expression, e.g. *civil.Date
+}
+
+// TypeUsageSite is a usage left unchanged: a member of the old type is used on the value but the new type lacks it
+type TypeUsageSite struct {
+	Path   string // file path of the declaration
+	Symbol string // declaring symbol, e.g. Event.At, NewEvent.at
+	Member string // used member, e.g. Unix
+	Reason string
+}
+
+// TypeUsageReport holds rewritten usages and flagged sites of a type replacement
+type TypeUsageReport struct {
+	Changes []*TypeUsageChange
+	Flagged []*TypeUsageSite
+}
+
+// typeMembers holds method and field names of a type, nil when the type members are unknown
+type typeMembers struct {
+	methods map[string]bool
+	fields  map[string]bool
+}
+
+// provides reports whether a member used on a value exists, called members have to be methods
+func (m *typeMembers) provides(member string, called bool) bool {
+	if m == nil {
+		return false
+	}
+	return m.methods[member] || (!called && m.fields[member])
+}
+
+// memberUse is a member selected on a value of the replaced type
+type memberUse struct {
+	member string
+	called bool
+}
+
+// ReplaceTypeUsage replaces usages of a type with another one within scope: field, parameter, result and variable
+// types, local variable declarations and composite literals, e.g. time.Time with cloud.google.com/go/civil.Date.
+// Types are canonical: import path, dot and type name. Imports of rewritten files are updated. Declarations whose
+// values use a member the new type does not provide (field and method sets of project types or importable packages)
+// are flagged instead of rewritten; rewritten files are written by StoreProject.
+func (c *Coder) ReplaceTypeUsage(oldCanonical, newCanonical string, scope *Filter) (*TypeUsageReport, error) {
+	if c.Project == nil {
+		return nil, &graph.ErrNotFound{Kind: "project", Name: "replace type usage"}
+	}
+	oldPath, oldName, err := splitCanonical(oldCanonical)
+	if err != nil {
+		return nil, err
+	}
+	newPath, newName, err := splitCanonical(newCanonical)
+	if err != nil {
+		return nil, err
+	}
+	if scope == nil {
+		scope = &Filter{}
+	}
+	members := c.typeMembers(newPath, newName)
+	report := &TypeUsageReport{}
+	for _, pkg := range c.Project.Packages {
+		if len(scope.Packages) > 0 && !containsName(scope.Packages, pkg.Name) && !containsName(scope.Packages, pkg.ImportPath) {
+			continue
+		}
+		for _, file := range pkg.FileSet {
+			oldRef := typeReference(file, pkg, oldPath, oldName)
+			if oldRef == "" {
+				continue
+			}
+			newRef, imp, err := newTypeReference(file, pkg, newPath, newName)
+			if err != nil {
+				return nil, err
+			}
+			file = c.Project.MutableFile(pkg, file)
+			c.recordFile(file)
+			replacer := &typeReplacer{coder: c, file: file, oldRef: oldRef, newRef: newRef, members: members, report: report}
+			replacer.oldExpr = regexp.MustCompile(`(^|[^.\w])` + regexp.QuoteMeta(oldRef) + `\b`)
+			if !replacer.replace(scope) {
+				continue
+			}
+			if imp != nil {
+				addImport(file, *imp)
+			}
+			if oldPath != pkg.ImportPath && !replacer.references() {
+				removeImport(file, oldPath)
+			}
+		}
+	}
+	return report, nil
+}
+
+// typeReplacer rewrites usages of a type in a file
+type typeReplacer struct {
+	coder   *Coder
+	file    *graph.File
+	oldRef  string // old type as referenced in the file, e.g. time.Time
+	newRef  string // new type as referenced in the file, e.g. civil.Date
+	oldExpr *regexp.Regexp
+	members *typeMembers
+	report  *TypeUsageReport
+	changed bool
+}
+
+// rewrite replaces old type references of a text
+func (r *typeReplacer) rewrite(text string) string {
+	return r.oldExpr.ReplaceAllString(text, "${1}"+r.newRef)
+}
+
+// uses reports whether a text references the old type
+func (r *typeReplacer) uses(text string) bool {
+	return r.oldExpr.MatchString(text)
+}
+
+// replace rewrites declarations of the file selected by scope, it reports whether anything changed
+func (r *typeReplacer) replace(scope *Filter) bool {
+	unrestricted := len(scope.Types) == 0 && len(scope.Functions) == 0
+	for _, aType := range r.file.Types {
+		if !unrestricted && !containsName(scope.Types, aType.Name) {
+			continue
+		}
+		for _, field := range aType.Fields {
+			r.replaceField(aType, field)
+		}
+		for _, method := range aType.Methods {
+			r.replaceFunction(aType.Name+"."+method.Name, method)
+		}
+	}
+	for _, function := range r.file.Functions {
+		if unrestricted || containsName(scope.Functions, function.Name) {
+			r.replaceFunction(function.Name, function)
+		}
+	}
+	if unrestricted {
+		for _, variable := range r.file.Variables {
+			if variable.Type != nil && r.uses(variable.Type.Name) {
+				r.change("variable", variable.Name, variable.Type)
+				if variable.Location != nil {
+					variable.Location.Raw = r.rewrite(variable.Location.Raw)
+				}
+			}
+		}
+	}
+	return r.changed
+}
+
+// replaceField rewrites a field type unless the field value uses members missing on the new type anywhere in the project
+func (r *typeReplacer) replaceField(aType *graph.Type, field *graph.Field) {
+	if field.Type == nil || !r.uses(field.Type.Name) {
+		return
+	}
+	symbol := aType.Name + "." + field.Name
+	selected := regexp.MustCompile(`\.` + regexp.QuoteMeta(field.Name) + `\.(\w+)\s*(\()?`)
+	if r.flag(symbol, r.coder.memberUses(selected)) {
+		return
+	}
+	r.change("field", symbol, field.Type)
+	if field.Location != nil {
+		field.Location.Raw = r.rewrite(field.Location.Raw)
+	}
+}
+
+// replaceFunction rewrites parameter, result and body usages of a function unless a parameter or local value of the
+// old type uses members missing on the new type
+func (r *typeReplacer) replaceFunction(symbol string, function *graph.Function) {
+	body := ""
+	if function.Body != nil {
+		body = function.Body.Text
+	}
+	if !r.uses(function.Signature) && !r.uses(body) {
+		return
+	}
+	var values []string
+	for _, param := range function.Parameters {
+		if param.Type != nil && param.Name != "" && strings.TrimPrefix(param.Type.Name, "*") == r.oldRef {
+			values = append(values, param.Name)
+		}
+	}
+	local := regexp.MustCompile(`(?:var\s+(\w+)\s+\*?|(\w+)\s*:=\s*&?)` + regexp.QuoteMeta(r.oldRef) + `\b`)
+	for _, match := range local.FindAllStringSubmatch(body, -1) {
+		values = append(values, match[1]+match[2])
+	}
+	flagged := false
+	for _, value := range values {
+		selected := regexp.MustCompile(`(?:^|[^.\w])` + regexp.QuoteMeta(value) + `\.(\w+)\s*(\()?`)
+		flagged = r.flag(symbol+"."+value, memberUses(selected, body)) || flagged
+	}
+	if flagged {
+		return
+	}
+	for _, param := range function.Parameters {
+		if param.Type != nil && r.uses(param.Type.Name) {
+			r.change("parameter", symbol+"."+param.Name, param.Type)
+		}
+	}
+	for i, result := range function.Results {
+		if result.Type != nil && r.uses(result.Type.Name) {
+			name := result.Name
+			if name == "" {
+				name = fmt.Sprintf("%d", i)
+			}
+			r.change("result", symbol+"."+name, result.Type)
+		}
+	}
+	function.Signature = r.rewrite(function.Signature)
+	if function.Location != nil {
+		function.Location.Raw = r.rewrite(function.Location.Raw)
+	}
+	if rewritten := r.rewrite(body); rewritten != body {
+		function.Body.Text = rewritten
+		r.report.Changes = append(r.report.Changes, &TypeUsageChange{Path: r.file.Path, Kind: "body", Symbol: symbol, Old: r.oldRef, New: r.newRef})
+		r.changed = true
+	}
+}
+
+// change rewrites a declared type and records the change
+func (r *typeReplacer) change(kind, symbol string, aType *graph.Type) {
+	previous := aType.Name
+	aType.Name = r.rewrite(aType.Name)
+	if aType.RawName != "" {
+		aType.RawName = r.rewrite(aType.RawName)
+	}
+	r.report.Changes = append(r.report.Changes, &TypeUsageChange{Path: r.file.Path, Kind: kind, Symbol: symbol, Old: previous, New: aType.Name})
+	r.changed = true
+}
+
+// flag records uses of members missing on the new type, it reports whether any was found
+func (r *typeReplacer) flag(symbol string, uses []memberUse) bool {
+	flagged := map[string]bool{}
+	for _, use := range uses {
+		if r.members.provides(use.member, use.called) || flagged[use.member] {
+			continue
+		}
+		flagged[use.member] = true
+		reason := fmt.Sprintf("%v does not provide %v", r.newRef, use.member)
+		if r.members == nil {
+			reason = fmt.Sprintf("members of %v are unknown, %v is used", r.newRef, use.member)
+		}
+		r.report.Flagged = append(r.report.Flagged, &TypeUsageSite{Path: r.file.Path, Symbol: symbol, Member: use.member, Reason: reason})
+	}
+	return len(flagged) > 0
+}
+
+// references reports whether declarations of the file still reference the old type
+func (r *typeReplacer) references() bool {
+	var texts []string
+	typeName := func(aType *graph.Type) {
+		if aType != nil {
+			texts = append(texts, aType.Name)
+		}
+	}
+	function := func(function *graph.Function) {
+		texts = append(texts, function.Signature)
+		if function.Body != nil {
+			texts = append(texts, function.Body.Text)
+		}
+	}
+	for _, variable := range r.file.Variables {
+		typeName(variable.Type)
+		texts = append(texts, variable.Value)
+	}
+	for _, constant := range r.file.Constants {
+		texts = append(texts, constant.Value)
+	}
+	for _, aType := range r.file.Types {
+		for _, field := range aType.Fields {
+			typeName(field.Type)
+		}
+		for _, method := range aType.Methods {
+			function(method)
+		}
+	}
+	for _, fn := range r.file.Functions {
+		function(fn)
+	}
+	qualifier, _, _ := strings.Cut(r.oldRef, ".")
+	used := regexp.MustCompile(`(^|[^.\w])` + regexp.QuoteMeta(qualifier) + `\.`)
+	for _, text := range texts {
+		if used.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// memberUses returns members selected by expr in function bodies across the project
+func (c *Coder) memberUses(expr *regexp.Regexp) []memberUse {
+	var result []memberUse
+	for _, pkg := range c.Project.Packages {
+		for _, file := range pkg.FileSet {
+			for _, aType := range file.Types {
+				for _, method := range aType.Methods {
+					if method.Body != nil {
+						result = append(result, memberUses(expr, method.Body.Text)...)
+					}
+				}
+			}
+			for _, function := range file.Functions {
+				if function.Body != nil {
+					result = append(result, memberUses(expr, function.Body.Text)...)
+				}
+			}
+		}
+	}
+	return result
+}
+
+// memberUses returns members selected by expr in a text, the expression captures a member and an optional call paren
+func memberUses(expr *regexp.Regexp, text string) []memberUse {
+	var result []memberUse
+	for _, match := range expr.FindAllStringSubmatch(text, -1) {
+		result = append(result, memberUse{member: match[1], called: match[2] != ""})
+	}
+	return result
+}
+
+// typeMembers returns method and field names of a project type or a type of an importable package, nil when unknown
+func (c *Coder) typeMembers(importPath, name string) *typeMembers {
+	for _, pkg := range c.Project.Packages {
+		if pkg.ImportPath != importPath {
+			continue
+		}
+		for _, file := range pkg.FileSet {
+			for _, aType := range file.Types {
+				if aType.Name != name {
+					continue
+				}
+				ret := &typeMembers{methods: map[string]bool{}, fields: map[string]bool{}}
+				for _, method := range aType.Methods {
+					ret.methods[method.Name] = true
+				}
+				for _, field := range aType.Fields {
+					ret.fields[field.Name] = true
+				}
+				return ret
+			}
+		}
+	}
+	pkg, err := importer.ForCompiler(token.NewFileSet(), "source", nil).Import(importPath)
+	if err != nil {
+		return nil
+	}
+	named, ok := pkg.Scope().Lookup(name).(*types.TypeName)
+	if !ok {
+		return nil
+	}
+	ret := &typeMembers{methods: map[string]bool{}, fields: map[string]bool{}}
+	methods := types.NewMethodSet(types.NewPointer(named.Type()))
+	for i := 0; i < methods.Len(); i++ {
+		ret.methods[methods.At(i).Obj().Name()] = true
+	}
+	if aStruct, ok := named.Type().Underlying().(*types.Struct); ok {
+		for i := 0; i < aStruct.NumFields(); i++ {
+			if aStruct.Field(i).Exported() {
+				ret.fields[aStruct.Field(i).Name()] = true
+			}
+		}
+	}
+	return ret
+}
+
+// splitCanonical splits a canonical type, e.g. cloud.google.com/go/civil.Date, into its import path and name
+func splitCanonical(canonical string) (string, string, error) {
+	index := strings.LastIndex(canonical, ".")
+	if index <= 0 || index == len(canonical)-1 || strings.LastIndex(canonical, "/") > index {
+		return "", "", fmt.Errorf("invalid canonical type %q, expected import path and type name, e.g. time.Time", canonical)
+	}
+	return canonical[:index], canonical[index+1:], nil
+}
+
+// typeReference returns a type as referenced in a file: its name in the declaring package, qualified by the import
+// name otherwise; empty when the file does not import the type package
+func typeReference(file *graph.File, pkg *graph.Package, importPath, name string) string {
+	if importPath == pkg.ImportPath {
+		return name
+	}
+	for _, imp := range file.Imports {
+		if imp.Path == importPath {
+			if imp.Name == "_" || imp.Name == "." {
+				return ""
+			}
+			return importQualifier(imp) + "." + name
+		}
+	}
+	return ""
+}
+
+// newTypeReference returns the new type as referenced in a file with the import to add, nil when already imported
+func newTypeReference(file *graph.File, pkg *graph.Package, importPath, name string) (string, *graph.Import, error) {
+	if ref := typeReference(file, pkg, importPath, name); ref != "" {
+		return ref, nil, nil
+	}
+	imp := &graph.Import{Path: importPath}
+	for _, candidate := range file.Imports {
+		if importQualifier(candidate) == importQualifier(*imp) {
+			return "", nil, fmt.Errorf("import %v of %v conflicts with %v", importPath, file.Path, candidate.Path)
+		}
+	}
+	return importQualifier(*imp) + "." + name, imp, nil
+}
+
+// importQualifier returns the name an import is referenced with
+func importQualifier(imp graph.Import) string {
+	if imp.Name != "" {
+		return imp.Name
+	}
+	return path.Base(imp.Path)
+}
+
+// removeImport removes an import of a path from the file
+func removeImport(file *graph.File, importPath string) {
+	for i, imp := range file.Imports {
+		if imp.Path == importPath {
+			file.Imports = append(file.Imports[:i], file.Imports[i+1:]...)
+			return
+		}
+	}
+}
+
+// containsName reports whether names contain name
+func containsName(names []string, name string) bool {
+	for _, candidate := range names {
+		if candidate == name {
+			return true
+		}
+	}
+	return false
+}
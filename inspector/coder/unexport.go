@@ -0,0 +1,128 @@
+package coder
+
+import (
+	"fmt"
+	"github.com/viant/linager/inspector/graph"
+	"regexp"
+	"strings"
+)
+
+// Unexport renames candidates reported by graph.Project.UnexportCandidates to their unexported names, rewriting
+// references across source texts of the declaring package; renamed files are written by StoreProject
+func (c *Coder) Unexport(candidates ...*graph.UnexportCandidate) error {
+	if c.Project == nil {
+		return &graph.ErrNotFound{Kind: "project", Name: "unexport"}
+	}
+	// files of declaring packages shared with a cloned project are copied before candidates are resolved
+	affected := map[*graph.File]bool{}
+	for _, candidate := range candidates {
+		if target := c.Project.ByRef(candidate.Ref.String()); target != nil {
+			for _, file := range target.Package.FileSet {
+				affected[file] = true
+			}
+		}
+	}
+	c.ownFiles(affected)
+	// candidates are resolved before renaming, renamed types no longer match references of their members
+	targets := make([]*graph.RefTarget, len(candidates))
+	for i, candidate := range candidates {
+		if targets[i] = c.Project.ByRef(candidate.Ref.String()); targets[i] == nil {
+			return &graph.ErrNotFound{Kind: candidate.Kind, Name: candidate.Ref.String()}
+		}
+	}
+	for i, candidate := range candidates {
+		target := targets[i]
+		newName := candidate.NewName
+		if newName == "" {
+			newName = graph.UnexportedName(candidate.Name)
+		}
+		member := false
+		switch candidate.Kind {
+		case graph.RefKindType:
+			target.Type.Name, target.Type.IsExported = newName, false
+		case graph.RefKindFunction:
+			target.Function.Name, target.Function.IsExported = newName, false
+		case graph.RefKindMethod:
+			target.Function.Name, target.Function.IsExported = newName, false
+			member = true
+		case graph.RefKindField:
+			target.Field.Name, target.Field.IsExported = newName, false
+			member = true
+		default:
+			return fmt.Errorf("unsupported unexport candidate kind: %v", candidate.Kind)
+		}
+		rename := identRenamer(candidate.Name, newName, member)
+		for _, file := range target.Package.FileSet {
+			renameFile(file, rename)
+		}
+	}
+	return nil
+}
+
+// identRenamer returns a function replacing whole word occurrences of name: package level symbols not preceded by a
+// selector dot, members when selected (x.Name), declared (func (r T) Name, Name string) or keyed (T{Name: v})
+func identRenamer(name, newName string, member bool) func(text string) string {
+	expr := regexp.MustCompile(`\b` + regexp.QuoteMeta(name) + `\b`)
+	return func(text string) string {
+		builder := strings.Builder{}
+		last := 0
+		for _, loc := range expr.FindAllStringIndex(text, -1) {
+			before := strings.TrimRight(text[:loc[0]], " \t")
+			selected := strings.HasSuffix(before, ".")
+			if member {
+				after := strings.TrimLeft(text[loc[1]:], " \t")
+				declared := before == "" || strings.HasSuffix(before, "\n") || strings.HasSuffix(before, ")") || strings.HasSuffix(before, ",") || strings.HasSuffix(before, "{")
+				keyed := strings.HasPrefix(after, ":") && !strings.HasPrefix(after, ":=")
+				if !selected && !declared && !keyed {
+					continue
+				}
+			} else if selected {
+				continue
+			}
+			builder.WriteString(text[last:loc[0]])
+			builder.WriteString(newName)
+			last = loc[1]
+		}
+		builder.WriteString(text[last:])
+		return builder.String()
+	}
+}
+
+// renameFile applies rename to declaration source texts and comments of a file
+func renameFile(file *graph.File, rename func(text string) string) {
+	location := func(location *graph.Location) {
+		if location != nil {
+			location.Raw = rename(location.Raw)
+		}
+	}
+	node := func(node *graph.LocationNode) {
+		if node != nil {
+			node.Text = rename(node.Text)
+		}
+	}
+	function := func(function *graph.Function) {
+		location(function.Location)
+		node(function.Comment)
+		node(function.Body)
+		function.Signature = rename(function.Signature)
+	}
+	for _, constant := range file.Constants {
+		location(constant.Location)
+	}
+	for _, variable := range file.Variables {
+		location(variable.Location)
+	}
+	for _, aType := range file.Types {
+		location(aType.Location)
+		node(aType.Comment)
+		for _, field := range aType.Fields {
+			location(field.Location)
+		}
+		for _, method := range aType.Methods {
+			function(method)
+		}
+	}
+	for _, fn := range file.Functions {
+		function(fn)
+	}
+}
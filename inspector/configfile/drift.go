@@ -0,0 +1,381 @@
+package configfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/viant/linager/inspector/graph"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefaultTags lists struct tags binding fields to keys in lookup order
+var DefaultTags = []string{"yaml", "mapstructure", "json"}
+
+// kindDuration is the schema kind of time.Duration fields accepting strings (1s) and numbers (nanoseconds)
+const kindDuration = "duration"
+
+// KeyLocation locates a configuration key
+type KeyLocation struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+// FieldLocation locates a struct field bound to a configuration key
+type FieldLocation struct {
+	Type  string `json:"type"` // declaring struct, e.g. app.Config
+	Field string `json:"field"`
+	File  string `json:"file,omitempty"`
+	Line  int    `json:"line,omitempty"` // 0 when unknown
+}
+
+// Drift is a configuration key and struct field mismatch, Key is nil for fields without keys and Field is nil for
+// keys without fields
+type Drift struct {
+	Path     string         `json:"path"` // key path, e.g. db.pool.size
+	Key      *KeyLocation   `json:"key,omitempty"`
+	Field    *FieldLocation `json:"field,omitempty"`
+	Expected string         `json:"expected,omitempty"` // kind of field values, type mismatches only
+	Actual   string         `json:"actual,omitempty"`   // kind of the key value, type mismatches only
+}
+
+// ConfigDriftReport reports drift between configuration files and a configuration struct
+type ConfigDriftReport struct {
+	Type        string   `json:"type"`
+	UnknownKeys []*Drift `json:"unknownKeys,omitempty"` // keys without a corresponding field
+	MissingKeys []*Drift `json:"missingKeys,omitempty"` // fields without a key in any configuration file
+	Mismatches  []*Drift `json:"mismatches,omitempty"`  // keys with a value kind the field can't hold
+}
+
+// HasDrift reports whether the report lists any drift
+func (r *ConfigDriftReport) HasDrift() bool {
+	return len(r.UnknownKeys) > 0 || len(r.MissingKeys) > 0 || len(r.Mismatches) > 0
+}
+
+// JSON renders the report as indented JSON
+func (r *ConfigDriftReport) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// schema describes values a struct field accepts
+type schema struct {
+	kind    string         // key kind, kindDuration, or empty for values of unknown types accepting any key
+	fields  []*schemaField // fields of struct schemas
+	element *schema        // element of list schemas or value of map schemas with arbitrary keys
+}
+
+// isStruct reports whether schema describes a struct
+func (s *schema) isStruct() bool {
+	return s.kind == KindMap && s.element == nil
+}
+
+// lookup returns a struct field bound to a key
+func (s *schema) lookup(key string) *schemaField {
+	for _, field := range s.fields {
+		if field.key == key || (field.fold && strings.EqualFold(field.key, key)) {
+			return field
+		}
+	}
+	return nil
+}
+
+// schemaField is a struct field bound to a key
+type schemaField struct {
+	key      string
+	fold     bool // untagged fields match keys case-insensitively
+	location *FieldLocation
+	schema   *schema
+}
+
+// declaration is a project type with its package and file
+type declaration struct {
+	pkg  *graph.Package
+	file *graph.File
+	typ  *graph.Type
+}
+
+// reconciler builds struct schemas and matches configuration keys against them
+type reconciler struct {
+	tags    []string
+	types   map[string][]*declaration // declarations by type name
+	sources map[string][]byte
+	seen    map[*schemaField]bool
+	report  *ConfigDriftReport
+}
+
+// Reconcile matches key paths of configuration files against fields of a project struct type (Name or package.Name)
+// bound by tags (DefaultTags when none are given), including nested structs, slices and maps of structs and
+// squash/inline embedding; embedded structs without a tag name are inlined as well, untagged fields match keys
+// case-insensitively and fields of types the project does not declare accept any value
+func Reconcile(project *graph.Project, typeName string, files []*File, tags ...string) (*ConfigDriftReport, error) {
+	if len(tags) == 0 {
+		tags = DefaultTags
+	}
+	r := &reconciler{tags: tags, types: map[string][]*declaration{}, sources: map[string][]byte{}, seen: map[*schemaField]bool{}}
+	for _, pkg := range project.Packages {
+		for _, file := range pkg.FileSet {
+			for _, typ := range file.Types {
+				r.types[typ.Name] = append(r.types[typ.Name], &declaration{pkg: pkg, file: file, typ: typ})
+			}
+		}
+	}
+	qualifier, name := "", typeName
+	if index := strings.LastIndex(typeName, "."); index != -1 {
+		qualifier, name = typeName[:index], typeName[index+1:]
+	}
+	decl := r.lookupType(qualifier, name, nil)
+	if decl == nil || decl.typ.Kind != reflect.Struct {
+		return nil, &graph.ErrNotFound{Kind: "type", Name: typeName}
+	}
+	r.report = &ConfigDriftReport{Type: decl.pkg.Name + "." + decl.typ.Name}
+	root := r.structSchema(decl, map[*graph.Type]bool{})
+	for _, file := range files {
+		r.match(file.Root, root, nil)
+	}
+	r.missing(root, "")
+	for _, drifts := range [][]*Drift{r.report.UnknownKeys, r.report.MissingKeys, r.report.Mismatches} {
+		sortDrifts(drifts)
+	}
+	return r.report, nil
+}
+
+// lookupType returns a declaration by name, preferring a package matching the qualifier or the referencing package
+func (r *reconciler) lookupType(qualifier, name string, from *graph.Package) *declaration {
+	candidates := r.types[name]
+	for _, candidate := range candidates {
+		if qualifier != "" && (candidate.pkg.Name == qualifier || strings.HasSuffix(candidate.pkg.ImportPath, "/"+qualifier)) {
+			return candidate
+		}
+		if qualifier == "" && candidate.pkg == from {
+			return candidate
+		}
+	}
+	if qualifier == "" && len(candidates) > 0 {
+		return candidates[0]
+	}
+	return nil
+}
+
+// structSchema builds the schema of a struct declaration, recursive types accept any value once revisited
+func (r *reconciler) structSchema(decl *declaration, visiting map[*graph.Type]bool) *schema {
+	if visiting[decl.typ] {
+		return &schema{}
+	}
+	visiting[decl.typ] = true
+	defer delete(visiting, decl.typ)
+	ret := &schema{kind: KindMap}
+	for _, field := range decl.typ.Fields {
+		if !field.IsEmbedded && !field.IsExported {
+			continue
+		}
+		key, options := r.tagName(field.Tag)
+		if key == "-" {
+			continue
+		}
+		typeName := ""
+		if field.Type != nil {
+			typeName = field.Type.Name
+		}
+		fieldSchema := r.typeSchema(typeName, decl.pkg, visiting)
+		if field.IsEmbedded && (key == "" || strings.Contains(options, "squash") || strings.Contains(options, "inline")) {
+			if fieldSchema.isStruct() {
+				ret.fields = append(ret.fields, fieldSchema.fields...)
+			}
+			continue
+		}
+		schemaField := &schemaField{key: key, schema: fieldSchema, location: r.fieldLocation(decl, field)}
+		if key == "" {
+			schemaField.key, schemaField.fold = field.Name, true
+		}
+		ret.fields = append(ret.fields, schemaField)
+	}
+	return ret
+}
+
+// tagName returns the key name and options of the first tag found
+func (r *reconciler) tagName(tag reflect.StructTag) (string, string) {
+	for _, name := range r.tags {
+		if value, ok := tag.Lookup(name); ok {
+			key, options, _ := strings.Cut(value, ",")
+			return key, options
+		}
+	}
+	return "", ""
+}
+
+// typeSchema builds the schema of a Go type expression, e.g. *DBConfig, []Server or map[string]int
+func (r *reconciler) typeSchema(typeName string, from *graph.Package, visiting map[*graph.Type]bool) *schema {
+	typeName = strings.TrimLeft(typeName, "*")
+	switch {
+	case strings.HasPrefix(typeName, "[]"):
+		return &schema{kind: KindList, element: r.typeSchema(typeName[2:], from, visiting)}
+	case strings.HasPrefix(typeName, "map["):
+		if end := graph.ClosingBracket(typeName, 0); end != -1 {
+			return &schema{kind: KindMap, element: r.typeSchema(typeName[end+1:], from, visiting)}
+		}
+		return &schema{}
+	}
+	if kind := scalarKind(typeName); kind != "" {
+		return &schema{kind: kind}
+	}
+	qualifier, name := "", typeName
+	if index := strings.LastIndex(typeName, "."); index != -1 {
+		qualifier, name = typeName[:index], typeName[index+1:]
+	}
+	decl := r.lookupType(qualifier, name, from)
+	if decl == nil {
+		return &schema{}
+	}
+	if decl.typ.Kind == reflect.Struct {
+		return r.structSchema(decl, visiting)
+	}
+	return &schema{kind: scalarKind(decl.typ.Kind.String())}
+}
+
+// scalarKind returns the key kind of a Go scalar type name, empty for other types
+func scalarKind(typeName string) string {
+	switch typeName {
+	case "string":
+		return KindString
+	case "bool":
+		return KindBool
+	case "time.Duration":
+		return kindDuration
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
+		"float32", "float64", "byte", "rune":
+		return KindNumber
+	}
+	return ""
+}
+
+// fieldLocation locates a struct field, the line is found in the type declaration source
+func (r *reconciler) fieldLocation(decl *declaration, field *graph.Field) *FieldLocation {
+	ret := &FieldLocation{Type: decl.pkg.Name + "." + decl.typ.Name, Field: field.Name, File: decl.file.Path}
+	if decl.file.Path == "" {
+		return ret
+	}
+	source, ok := r.sources[decl.file.Path]
+	if !ok {
+		source, _ = os.ReadFile(decl.file.Path)
+		r.sources[decl.file.Path] = source
+	}
+	start, end := declarationSpan(decl.typ, source)
+	if start == -1 {
+		return ret
+	}
+	name := field.Name
+	if name == "" && field.Type != nil {
+		name = strings.TrimLeft(field.Type.Name, "*")
+	}
+	line := bytes.Count(source[:start], []byte("\n")) + 1
+	for i, text := range strings.Split(string(source[start:end]), "\n") {
+		if words := strings.Fields(text); i > 0 && len(words) > 0 && words[0] == name {
+			ret.Line = line + i
+			break
+		}
+	}
+	return ret
+}
+
+// declarationSpan returns source offsets of a struct type declaration, types inspected without locations are found
+// by their declaration text up to the first closing brace at the declaration indentation; -1 when not found
+func declarationSpan(typ *graph.Type, source []byte) (int, int) {
+	if location := typ.Location; location != nil && location.Start < location.End && location.End <= len(source) {
+		return location.Start, location.End
+	}
+	declaration := regexp.MustCompile(`(?m)^(type[ \t]+|[ \t]+)` + regexp.QuoteMeta(typ.Name) + `[ \t]+struct[ \t]*\{`)
+	match := declaration.FindSubmatchIndex(source)
+	if match == nil {
+		return -1, -1
+	}
+	indent := ""
+	if prefix := string(source[match[2]:match[3]]); !strings.HasPrefix(prefix, "type") {
+		indent = prefix
+	}
+	end := bytes.Index(source[match[0]:], []byte("\n"+indent+"}"))
+	if end == -1 {
+		return match[0], len(source)
+	}
+	return match[0], match[0] + end + 1
+}
+
+// match matches a key against a schema, field is the struct field the key is bound to
+func (r *reconciler) match(key *Key, s *schema, field *schemaField) {
+	if s.kind == "" || key.Kind == KindNull {
+		return
+	}
+	if !compatible(s.kind, key.Kind) {
+		if field != nil {
+			r.report.Mismatches = append(r.report.Mismatches, &Drift{Path: key.Path, Key: keyLocation(key), Field: field.location, Expected: s.kind, Actual: key.Kind})
+		}
+		return
+	}
+	for _, child := range key.Children {
+		if !s.isStruct() {
+			r.match(child, s.element, field)
+			continue
+		}
+		childField := s.lookup(child.Name)
+		if childField == nil {
+			r.report.UnknownKeys = append(r.report.UnknownKeys, &Drift{Path: child.Path, Key: keyLocation(child)})
+			continue
+		}
+		r.seen[childField] = true
+		r.match(child, childField.schema, childField)
+	}
+}
+
+// compatible reports whether a key kind is assignable to a schema kind
+func compatible(schemaKind, keyKind string) bool {
+	if schemaKind == kindDuration {
+		return keyKind == KindString || keyKind == KindNumber
+	}
+	return schemaKind == keyKind
+}
+
+// missing reports fields of a struct schema never bound to a key, nested fields are reported for bound fields only
+func (r *reconciler) missing(s *schema, prefix string) {
+	if s.element != nil {
+		if s.kind == KindList {
+			prefix += ElementKey
+		} else {
+			prefix += ".*"
+		}
+		r.missing(s.element, prefix)
+		return
+	}
+	for _, field := range s.fields {
+		keyPath := field.key
+		if prefix != "" {
+			keyPath = prefix + "." + field.key
+		}
+		if !r.seen[field] {
+			r.report.MissingKeys = append(r.report.MissingKeys, &Drift{Path: keyPath, Field: field.location})
+			continue
+		}
+		r.missing(field.schema, keyPath)
+	}
+}
+
+// keyLocation returns the location of a key
+func keyLocation(key *Key) *KeyLocation {
+	return &KeyLocation{File: key.File, Line: key.Line}
+}
+
+// sortDrifts orders drifts by key path and location
+func sortDrifts(drifts []*Drift) {
+	sort.SliceStable(drifts, func(i, j int) bool {
+		if drifts[i].Path != drifts[j].Path {
+			return drifts[i].Path < drifts[j].Path
+		}
+		if drifts[i].Key != nil && drifts[j].Key != nil {
+			if drifts[i].Key.File != drifts[j].Key.File {
+				return drifts[i].Key.File < drifts[j].Key.File
+			}
+			return drifts[i].Key.Line < drifts[j].Key.Line
+		}
+		return false
+	})
+}
@@ -0,0 +1,226 @@
+// Package configfile inspects YAML and JSON configuration files into key path trees and reconciles them with
+// configuration structs bound through yaml, mapstructure or json tags
+package configfile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/viant/linager/inspector/graph"
+	"github.com/viant/linager/inspector/repository"
+	"gopkg.in/yaml.v3"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Key kinds
+const (
+	KindString = "string"
+	KindNumber = "number"
+	KindBool   = "bool"
+	KindNull   = "null"
+	KindMap    = "map"
+	KindList   = "list"
+)
+
+// ElementKey is the key path segment of list elements, e.g. servers[].host
+const ElementKey = "[]"
+
+// DefaultPatterns matches YAML and JSON files by name
+var DefaultPatterns = []string{"*.yaml", "*.yml", "*.json"}
+
+// Key is a configuration key with its value kind; list elements are merged into one child named ElementKey
+type Key struct {
+	Name     string `json:"name"`
+	Path     string `json:"path"` // dotted key path, e.g. db.pool.size
+	Kind     string `json:"kind"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Children []*Key `json:"children,omitempty"`
+}
+
+// Child returns a child key by name
+func (k *Key) Child(name string) *Key {
+	for _, child := range k.Children {
+		if child.Name == name {
+			return child
+		}
+	}
+	return nil
+}
+
+// IsScalar reports whether key holds a scalar value
+func (k *Key) IsScalar() bool {
+	return k.Kind != KindMap && k.Kind != KindList
+}
+
+// File is a parsed configuration file
+type File struct {
+	Path string `json:"path"`
+	Root *Key   `json:"root"` // document root, named after the file
+}
+
+// Inspector parses configuration files matching glob patterns
+type Inspector struct {
+	patterns       []string
+	followSymlinks bool
+}
+
+// NewInspector creates a configuration file inspector; patterns (path.Match syntax) are matched against file names
+// and slash separated paths relative to the inspected directory, DefaultPatterns are used when none are given
+func NewInspector(patterns ...string) *Inspector {
+	if len(patterns) == 0 {
+		patterns = DefaultPatterns
+	}
+	return &Inspector{patterns: patterns}
+}
+
+// WithFollowSymlinks makes InspectDir follow symlinked directories and files
+func (i *Inspector) WithFollowSymlinks() *Inspector {
+	i.followSymlinks = true
+	return i
+}
+
+// InspectDir parses configuration files of a directory tree matching the inspector patterns, hidden directories,
+// vendor and node_modules are skipped
+func (i *Inspector) InspectDir(root string) ([]*File, error) {
+	var files []*File
+	err := repository.Walk(root, i.followSymlinks, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			name := info.Name()
+			if filePath != root && (strings.HasPrefix(name, ".") || name == "vendor" || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		relative, err := filepath.Rel(root, filePath)
+		if err != nil || !i.matches(filepath.ToSlash(relative)) {
+			return nil
+		}
+		file, err := i.InspectFile(filePath)
+		if err != nil {
+			return err
+		}
+		files = append(files, file)
+		return nil
+	})
+	if err != nil {
+		return nil, graph.NotFoundError("directory", root, err)
+	}
+	return files, nil
+}
+
+// matches reports whether a relative path or its file name matches a pattern
+func (i *Inspector) matches(relative string) bool {
+	for _, pattern := range i.patterns {
+		if ok, _ := path.Match(pattern, relative); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, path.Base(relative)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// InspectFile parses a YAML or JSON file into a key tree, multi-document YAML files are merged
+func (i *Inspector) InspectFile(filePath string) (*File, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, graph.NotFoundError("file", filePath, err)
+	}
+	return i.InspectSource(filePath, data)
+}
+
+// InspectSource parses YAML or JSON source of a file into a key tree
+func (i *Inspector) InspectSource(filePath string, data []byte) (*File, error) {
+	root := &Key{Name: filepath.Base(filePath), Kind: KindMap, File: filePath, Line: 1}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		document := &yaml.Node{}
+		if err := decoder.Decode(document); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, &graph.ErrParse{Path: filePath, Cause: err}
+		}
+		if len(document.Content) == 0 {
+			continue
+		}
+		if node := document.Content[0]; node.Kind == yaml.MappingNode {
+			addChildren(root, node, "", filePath)
+		} else {
+			return nil, &graph.ErrParse{Path: filePath, Line: node.Line, Cause: fmt.Errorf("expected mapping document, got %s", kindOf(node))}
+		}
+	}
+	return &File{Path: filePath, Root: root}, nil
+}
+
+// addChildren adds keys of a mapping or elements of a sequence node to a parent key, repeated keys are merged
+func addChildren(parent *Key, node *yaml.Node, prefix, filePath string) {
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	switch node.Kind {
+	case yaml.MappingNode:
+		for j := 0; j+1 < len(node.Content); j += 2 {
+			keyNode, valueNode := node.Content[j], node.Content[j+1]
+			if keyNode.Tag == "!!merge" {
+				addChildren(parent, valueNode, prefix, filePath)
+				continue
+			}
+			child := childKey(parent, keyNode.Value, prefix, filePath, keyNode.Line, valueNode)
+			addChildren(child, valueNode, child.Path, filePath)
+		}
+	case yaml.SequenceNode:
+		for _, element := range node.Content {
+			child := childKey(parent, ElementKey, prefix, filePath, element.Line, element)
+			addChildren(child, element, child.Path, filePath)
+		}
+	}
+}
+
+// childKey returns an existing or new child key
+func childKey(parent *Key, name, prefix, filePath string, line int, value *yaml.Node) *Key {
+	if child := parent.Child(name); child != nil {
+		return child
+	}
+	keyPath := name
+	switch {
+	case name == ElementKey:
+		keyPath = prefix + ElementKey
+	case prefix != "":
+		keyPath = prefix + "." + name
+	}
+	child := &Key{Name: name, Path: keyPath, Kind: kindOf(value), File: filePath, Line: line}
+	parent.Children = append(parent.Children, child)
+	return child
+}
+
+// kindOf returns the key kind of a YAML node
+func kindOf(node *yaml.Node) string {
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	switch node.Kind {
+	case yaml.MappingNode:
+		return KindMap
+	case yaml.SequenceNode:
+		return KindList
+	}
+	switch node.ShortTag() {
+	case "!!int", "!!float":
+		return KindNumber
+	case "!!bool":
+		return KindBool
+	case "!!null":
+		return KindNull
+	}
+	return KindString
+}
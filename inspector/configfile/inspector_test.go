@@ -0,0 +1,85 @@
+package configfile_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/linager/inspector/configfile"
+	"github.com/viant/linager/inspector/golang"
+	"github.com/viant/linager/inspector/graph"
+	"path/filepath"
+	"testing"
+)
+
+func TestInspector_InspectDir(t *testing.T) {
+	files, err := configfile.NewInspector().InspectDir("testdata/conf")
+	if !assert.NoError(t, err) {
+		return
+	}
+	var names []string
+	for _, file := range files {
+		names = append(names, filepath.Base(file.Path))
+	}
+	assert.Equal(t, []string{"config.yaml", "override.json"}, names)
+
+	root := files[0].Root
+	assert.Equal(t, configfile.KindNumber, root.Child("port").Kind)
+	assert.Equal(t, configfile.KindString, root.Child("debug").Kind)
+	pool := root.Child("db").Child("pool")
+	assert.Equal(t, "db.pool", pool.Path)
+	assert.Equal(t, 7, pool.Line)
+	servers := root.Child("servers")
+	assert.Equal(t, configfile.KindList, servers.Kind)
+	assert.Equal(t, "servers[].port", servers.Child(configfile.ElementKey).Child("port").Path)
+
+	files, err = configfile.NewInspector("conf/*.json").InspectDir("testdata")
+	if assert.NoError(t, err) && assert.Len(t, files, 1) {
+		assert.Equal(t, 3, files[0].Root.Child("servers").Line)
+	}
+
+	_, err = configfile.NewInspector().InspectSource("broken.yaml", []byte("port: [8080"))
+	assert.ErrorIs(t, err, &graph.ErrParse{})
+}
+
+func TestReconcile(t *testing.T) {
+	packages, err := golang.NewInspector(graph.DefaultConfig()).InspectPackages("testdata/app")
+	if !assert.NoError(t, err) {
+		return
+	}
+	project := &graph.Project{Name: "app", Packages: packages}
+	files, err := configfile.NewInspector().InspectDir("testdata/conf")
+	if !assert.NoError(t, err) {
+		return
+	}
+	report, err := configfile.Reconcile(project, "app.Config", files)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, report.HasDrift())
+	assert.Equal(t, "app.Config", report.Type)
+
+	if assert.Len(t, report.UnknownKeys, 1) {
+		unknown := report.UnknownKeys[0]
+		assert.Equal(t, "db.retries", unknown.Path)
+		assert.Equal(t, "config.yaml", filepath.Base(unknown.Key.File))
+		assert.Equal(t, 8, unknown.Key.Line)
+		assert.Nil(t, unknown.Field)
+	}
+	if assert.Len(t, report.MissingKeys, 1) {
+		missing := report.MissingKeys[0]
+		assert.Equal(t, "region", missing.Path)
+		assert.Nil(t, missing.Key)
+		assert.Equal(t, &configfile.FieldLocation{Type: "app.Config", Field: "Region", File: missing.Field.File, Line: 16}, missing.Field)
+		assert.Equal(t, "config.go", filepath.Base(missing.Field.File))
+	}
+	if assert.Len(t, report.Mismatches, 1) {
+		mismatch := report.Mismatches[0]
+		assert.Equal(t, "debug", mismatch.Path)
+		assert.Equal(t, configfile.KindBool, mismatch.Expected)
+		assert.Equal(t, configfile.KindString, mismatch.Actual)
+		assert.Equal(t, 3, mismatch.Key.Line)
+		assert.Equal(t, "Debug", mismatch.Field.Field)
+		assert.Equal(t, 14, mismatch.Field.Line)
+	}
+
+	_, err = configfile.Reconcile(project, "Missing", files)
+	assert.ErrorIs(t, err, &graph.ErrNotFound{Kind: "type"})
+}
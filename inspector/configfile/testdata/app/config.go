@@ -0,0 +1,33 @@
+package app
+
+import "time"
+
+// Base holds settings shared by services
+type Base struct {
+	Name string `mapstructure:"name"`
+}
+
+// Config is bound to conf/config.yaml
+type Config struct {
+	Base    `mapstructure:",squash"`
+	Port    int           `mapstructure:"port"`
+	Debug   bool          `mapstructure:"debug"`
+	Timeout time.Duration `mapstructure:"timeout"`
+	Region  string        `mapstructure:"region"`
+	DB      *DBConfig     `mapstructure:"db"`
+	Servers []Server      `mapstructure:"servers"`
+	Limits  map[string]int
+	secret  string
+}
+
+// DBConfig holds database settings
+type DBConfig struct {
+	Host string `yaml:"host"`
+	Pool int    `yaml:"pool"`
+}
+
+// Server is an upstream server
+type Server struct {
+	Host string `json:"host"`
+	Port int    `json:"port"`
+}
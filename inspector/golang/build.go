@@ -0,0 +1,213 @@
+package golang
+
+import (
+	"github.com/viant/linager/inspector/graph"
+	"go/build/constraint"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// knownOS and knownArch list GOOS and GOARCH values recognized in file name suffixes and build constraints
+var (
+	knownOS = map[string]bool{"aix": true, "android": true, "darwin": true, "dragonfly": true, "freebsd": true, "hurd": true,
+		"illumos": true, "ios": true, "js": true, "linux": true, "nacl": true, "netbsd": true, "openbsd": true, "plan9": true,
+		"solaris": true, "wasip1": true, "windows": true, "zos": true}
+	knownArch = map[string]bool{"386": true, "amd64": true, "arm": true, "arm64": true, "loong64": true, "mips": true,
+		"mipsle": true, "mips64": true, "mips64le": true, "ppc64": true, "ppc64le": true, "riscv64": true, "s390x": true, "wasm": true}
+	unixOS = map[string]bool{"aix": true, "android": true, "darwin": true, "dragonfly": true, "freebsd": true, "hurd": true,
+		"illumos": true, "ios": true, "linux": true, "netbsd": true, "openbsd": true, "solaris": true}
+)
+
+// buildContext is a target platform build constraints are evaluated against
+type buildContext struct {
+	goos   string
+	goarch string
+	tags   map[string]bool
+}
+
+func newBuildContext(config *graph.Config, goos, goarch string) *buildContext {
+	if goos == "" {
+		goos = runtime.GOOS
+	}
+	if goarch == "" {
+		goarch = runtime.GOARCH
+	}
+	ret := &buildContext{goos: goos, goarch: goarch, tags: map[string]bool{}}
+	for _, tag := range config.BuildTags {
+		ret.tags[tag] = true
+	}
+	return ret
+}
+
+// name returns the variant name, e.g. linux/amd64
+func (c *buildContext) name() string {
+	return c.goos + "/" + c.goarch
+}
+
+// matchTag reports whether a build constraint tag is satisfied, as the go tool does for the gc toolchain without cgo
+func (c *buildContext) matchTag(tag string) bool {
+	switch {
+	case tag == c.goos || tag == c.goarch || c.tags[tag]:
+		return true
+	case tag == "unix":
+		return unixOS[c.goos]
+	case tag == "linux":
+		return c.goos == "android"
+	case tag == "solaris":
+		return c.goos == "illumos"
+	case tag == "darwin":
+		return c.goos == "ios"
+	case tag == "gc":
+		return true
+	case strings.HasPrefix(tag, "go1."):
+		return true // release tags
+	}
+	return false
+}
+
+// matchFile reports whether a file is built for the context, evaluating the _GOOS_GOARCH file name suffix and the
+// //go:build expression
+func (c *buildContext) matchFile(filename, expr string) bool {
+	goos, goarch := fileNamePlatform(filename)
+	if goos != "" && !c.matchTag(goos) {
+		return false
+	}
+	if goarch != "" && goarch != c.goarch {
+		return false
+	}
+	if expr == "" {
+		return true
+	}
+	parsed, err := constraint.Parse("//go:build " + expr)
+	if err != nil {
+		return true // malformed constraints are reported by the compiler, keep the file
+	}
+	return parsed.Eval(c.matchTag)
+}
+
+// fileNamePlatform returns GOOS and GOARCH implied by a file name, e.g. foo_linux_amd64.go or foo_windows_test.go
+func fileNamePlatform(filename string) (string, string) {
+	name, _, _ := strings.Cut(filepath.Base(filename), ".")
+	index := strings.Index(name, "_")
+	if index == -1 {
+		return "", ""
+	}
+	parts := strings.Split(name[index:], "_")
+	if n := len(parts); n > 0 && parts[n-1] == "test" {
+		parts = parts[:n-1]
+	}
+	n := len(parts)
+	switch {
+	case n >= 2 && knownOS[parts[n-2]] && knownArch[parts[n-1]]:
+		return parts[n-2], parts[n-1]
+	case n >= 1 && knownOS[parts[n-1]]:
+		return parts[n-1], ""
+	case n >= 1 && knownArch[parts[n-1]]:
+		return "", parts[n-1]
+	}
+	return "", ""
+}
+
+// filePlatforms returns operating systems a file refers to by name suffix or build constraint tags
+func filePlatforms(filename, expr string) []string {
+	var result []string
+	if goos, _ := fileNamePlatform(filename); goos != "" {
+		result = append(result, goos)
+	}
+	if expr == "" {
+		return result
+	}
+	parsed, err := constraint.Parse("//go:build " + expr)
+	if err != nil {
+		return result
+	}
+	return append(result, constraintOS(parsed)...)
+}
+
+// constraintOS returns operating system tags of a build constraint expression
+func constraintOS(expr constraint.Expr) []string {
+	switch actual := expr.(type) {
+	case *constraint.TagExpr:
+		if knownOS[actual.Tag] {
+			return []string{actual.Tag}
+		}
+	case *constraint.NotExpr:
+		return constraintOS(actual.X)
+	case *constraint.AndExpr:
+		return append(constraintOS(actual.X), constraintOS(actual.Y)...)
+	case *constraint.OrExpr:
+		return append(constraintOS(actual.X), constraintOS(actual.Y)...)
+	}
+	return nil
+}
+
+// buildContexts returns the inspected build targets: configured variants or operating systems referenced by package
+// files in multi-variant mode, the configured target otherwise
+func (i *Inspector) buildContexts(files []*graph.File) []*buildContext {
+	config := i.config
+	if !config.MultiVariant {
+		return []*buildContext{newBuildContext(config, config.GOOS, config.GOARCH)}
+	}
+	var platforms []string
+	if len(config.Variants) > 0 {
+		platforms = config.Variants
+	} else {
+		unique := map[string]bool{}
+		for _, file := range files {
+			for _, goos := range filePlatforms(file.Path, file.BuildTags) {
+				unique[goos] = true
+			}
+		}
+		for goos := range unique {
+			platforms = append(platforms, goos)
+		}
+		sort.Strings(platforms)
+		if len(platforms) == 0 {
+			platforms = []string{config.GOOS}
+		}
+	}
+	var result []*buildContext
+	for _, platform := range platforms {
+		goos, goarch, _ := strings.Cut(platform, "/")
+		if goarch == "" {
+			goarch = config.GOARCH
+		}
+		result = append(result, newBuildContext(config, goos, goarch))
+	}
+	return result
+}
+
+// applyBuildConstraints drops files not built for the configured target, in multi-variant mode files built for any
+// variant are kept and grouped into package variants
+func (i *Inspector) applyBuildConstraints(files []*graph.File) ([]*graph.File, []*graph.BuildVariant) {
+	if !i.config.BuildConstrained() {
+		return files, nil
+	}
+	contexts := i.buildContexts(files)
+	var kept []*graph.File
+	var variants []*graph.BuildVariant
+	for _, context := range contexts {
+		variants = append(variants, &graph.BuildVariant{Name: context.name(), GOOS: context.goos, GOARCH: context.goarch})
+	}
+	for _, file := range files {
+		matched := false
+		for k, context := range contexts {
+			if context.matchFile(file.Path, file.BuildTags) {
+				matched = true
+				variants[k].Files = append(variants[k].Files, filepath.Base(file.Path))
+			}
+		}
+		if matched {
+			kept = append(kept, file)
+		}
+	}
+	if !i.config.MultiVariant {
+		return kept, nil
+	}
+	for _, variant := range variants {
+		sort.Strings(variant.Files)
+	}
+	return kept, variants
+}
@@ -43,9 +43,10 @@ func (i *Inspector) InspectConstants(file *ast.File, importMap map[string]string
 
 				// Create constant with location info
 				constant := &graph.Constant{
-					Name:    name.Name,
-					Value:   "", // Will be populated below
-					Comment: strings.TrimSpace(docText),
+					Name:       name.Name,
+					Value:      "", // Will be populated below
+					Comment:    strings.TrimSpace(docText),
+					IsExported: name.IsExported(),
 					Location: &graph.Location{
 						Start: i.fset.Position(name.Pos()).Offset,
 						End:   i.fset.Position(name.End()).Offset,
@@ -0,0 +1,153 @@
+package golang
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"github.com/viant/linager/inspector/graph"
+)
+
+// parseCoverage accounts declarations of an AST file against the graph file built by processFile; declarations
+// excluded by configuration (e.g. unexported ones) are not counted
+func (i *Inspector) parseCoverage(file *ast.File, filename string, infoFile *graph.File, importMap map[string]string) *graph.ParseCoverage {
+	coverage := graph.NewParseCoverage()
+	types := map[string]*graph.Type{}
+	for _, aType := range infoFile.Types {
+		types[aType.Name] = aType
+	}
+	functions := map[string]bool{}
+	for _, function := range infoFile.Functions {
+		functions[function.Name] = true
+	}
+	constants := map[string]bool{}
+	for _, constant := range infoFile.Constants {
+		constants[constant.Name] = true
+	}
+	unhandled := func(node ast.Node, owner string) {
+		position := i.fset.Position(node.Pos())
+		coverage.Unhandle(&graph.UnhandledNode{Node: nodeType(node), Owner: owner, Path: filename, Line: position.Line, Column: position.Column})
+	}
+
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					if !i.config.IncludeUnexported && !spec.Name.IsExported() {
+						continue
+					}
+					aType := types[spec.Name.Name]
+					modeled := aType != nil
+					switch spec.Type.(type) {
+					case *ast.StructType, *ast.InterfaceType, *ast.ArrayType, *ast.MapType, *ast.StarExpr, *ast.Ident:
+					default: // processFile leaves the kind unknown
+						modeled = false
+						unhandled(spec.Type, spec.Name.Name)
+					}
+					coverage.Count(graph.CoverageTypes, modeled)
+					i.memberCoverage(coverage, spec, aType, importMap, unhandled)
+				case *ast.ValueSpec:
+					if decl.Tok != token.CONST {
+						continue
+					}
+					for _, name := range spec.Names {
+						if !i.config.IncludeUnexported && !name.IsExported() {
+							continue
+						}
+						coverage.Count(graph.CoverageConstants, constants[name.Name])
+					}
+				}
+			}
+		case *ast.FuncDecl:
+			if decl.Recv == nil || len(decl.Recv.List) == 0 {
+				coverage.Count(graph.CoverageFunctions, functions[decl.Name.Name])
+				continue
+			}
+			if !i.config.IncludeUnexported && !decl.Name.IsExported() {
+				continue
+			}
+			receiver := types[extractBaseTypeName(exprToString(decl.Recv.List[0].Type, importMap))]
+			coverage.Count(graph.CoverageFunctions, receiver != nil && hasMethod(receiver, decl.Name.Name))
+		default:
+			unhandled(decl, "")
+		}
+	}
+	return coverage
+}
+
+// memberCoverage accounts struct fields and interface methods of a type declaration, interface elements other than
+// methods and embedded interfaces (e.g. type unions) are reported as unhandled
+func (i *Inspector) memberCoverage(coverage *graph.ParseCoverage, spec *ast.TypeSpec, aType *graph.Type, importMap map[string]string, unhandled func(node ast.Node, owner string)) {
+	switch typeExpr := spec.Type.(type) {
+	case *ast.StructType:
+		if typeExpr.Fields == nil {
+			return
+		}
+		for _, field := range typeExpr.Fields.List {
+			if len(field.Names) == 0 {
+				if !i.config.IncludeUnexported && !isExportedType(field.Type) {
+					continue
+				}
+				name := extractBaseTypeName(exprToString(field.Type, importMap))
+				coverage.Count(graph.CoverageFields, aType != nil && hasEmbedded(aType, name))
+				continue
+			}
+			for _, name := range field.Names {
+				if !i.config.IncludeUnexported && !name.IsExported() {
+					continue
+				}
+				coverage.Count(graph.CoverageFields, aType != nil && hasField(aType, name.Name))
+			}
+		}
+	case *ast.InterfaceType:
+		if typeExpr.Methods == nil {
+			return
+		}
+		for _, field := range typeExpr.Methods.List {
+			switch field.Type.(type) {
+			case *ast.FuncType:
+				for _, name := range field.Names {
+					coverage.Count(graph.CoverageFunctions, aType != nil && hasMethod(aType, name.Name))
+				}
+			case *ast.Ident, *ast.SelectorExpr, *ast.IndexExpr, *ast.IndexListExpr:
+			default:
+				unhandled(field.Type, spec.Name.Name)
+			}
+		}
+	}
+}
+
+// nodeType returns the AST node type name, e.g. BinaryExpr
+func nodeType(node ast.Node) string {
+	return strings.TrimPrefix(fmt.Sprintf("%T", node), "*ast.")
+}
+
+func hasMethod(aType *graph.Type, name string) bool {
+	for _, method := range aType.Methods {
+		if method.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasField(aType *graph.Type, name string) bool {
+	for _, field := range aType.Fields {
+		if field.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasEmbedded(aType *graph.Type, name string) bool {
+	for _, field := range aType.Fields {
+		if field.IsEmbedded && field.Type != nil && extractBaseTypeName(field.Type.Name) == name {
+			return true
+		}
+	}
+	return false
+}
@@ -57,6 +57,7 @@ func (i *Inspector) processTypeSpec(ts *ast.TypeSpec, typeSpecDoc, genDeclDoc *a
 	// Extract comments - prioritize TypeSpec doc comment over GenDecl doc comment
 	comment := ""
 	var commentLocation graph.Location
+	doc := typeSpecDoc
 	if typeSpecDoc != nil {
 		comment = typeSpecDoc.Text()
 		commentLocation = graph.Location{
@@ -64,6 +65,7 @@ func (i *Inspector) processTypeSpec(ts *ast.TypeSpec, typeSpecDoc, genDeclDoc *a
 			End:   i.fset.Position(typeSpecDoc.End()).Offset,
 		}
 	} else if genDeclDoc != nil {
+		doc = genDeclDoc
 		comment = genDeclDoc.Text()
 		commentLocation = graph.Location{
 			Start: i.fset.Position(genDeclDoc.Pos()).Offset,
@@ -83,6 +85,7 @@ func (i *Inspector) processTypeSpec(ts *ast.TypeSpec, typeSpecDoc, genDeclDoc *a
 		Name:       ts.Name.Name,
 		Kind:       kindFromString(typeKind),
 		Comment:    &graph.LocationNode{Text: strings.TrimSpace(comment), Location: commentLocation},
+		Annotation: directiveNode(doc),
 		IsExported: ts.Name.IsExported(),
 		TypeParams: extractTypeParams(ts.TypeParams, importMap),
 		Location:   typeLocation,
@@ -92,13 +95,13 @@ func (i *Inspector) processTypeSpec(ts *ast.TypeSpec, typeSpecDoc, genDeclDoc *a
 	if typeKind == "struct" {
 		st, ok := ts.Type.(*ast.StructType)
 		if ok && st.Fields != nil {
-			t.Fields = i.processFields(st.Fields, importMap)
+			t.Fields = i.processFields(st.Fields, importMap, t.Name)
+			markTypeParamFields(t.Fields, t.TypeParams)
 		}
 	} else if typeKind == "interface" {
 		// Process interface methods
-		iface, ok := ts.Type.(*ast.InterfaceType)
-		if ok && iface.Methods != nil {
-			// Process interface methods - can be extended if needed
+		if iface, ok := ts.Type.(*ast.InterfaceType); ok {
+			t.Methods, t.Extends = i.processInterfaceMethods(iface, importMap)
 		}
 	} else if typeKind == "alias" {
 		// For type aliases, generate a comment if none exists
@@ -133,11 +136,46 @@ func extractFieldDocumentation(field *ast.Field) string {
 	return ""
 }
 
+// directiveNode returns directive lines of a doc comment (e.g. //go:generate, //linager:keep), which
+// ast.CommentGroup.Text omits; nil without directives
+func directiveNode(doc *ast.CommentGroup) *graph.LocationNode {
+	if doc == nil {
+		return nil
+	}
+	var directives []string
+	for _, comment := range doc.List {
+		if text := strings.TrimPrefix(comment.Text, "//"); text != comment.Text && isDirective(text) {
+			directives = append(directives, comment.Text)
+		}
+	}
+	if len(directives) == 0 {
+		return nil
+	}
+	return &graph.LocationNode{Text: strings.Join(directives, "\n")}
+}
+
+// isDirective reports whether a line comment text is a directive: a lower case word followed by a colon
+func isDirective(text string) bool {
+	name, _, ok := strings.Cut(text, ":")
+	if !ok || name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !('a' <= r && r <= 'z' || '0' <= r && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
 // processMethod converts an ast.FuncDecl to our Functions
 func (i *Inspector) processMethod(funcDecl *ast.FuncDecl, importMap map[string]string) *graph.Function {
 	recvField := funcDecl.Recv.List[0]
 	recvTypeStr := exprToString(recvField.Type, importMap)
 	method := i.processFunction(funcDecl, importMap, recvTypeStr)
+	if len(method.TypeParams) == 0 {
+		method.TypeParams = receiverTypeParams(recvField.Type)
+	}
 	return method
 }
 
@@ -158,40 +196,112 @@ func (i *Inspector) processFunction(funcDecl *ast.FuncDecl, importMap map[string
 	method := &graph.Function{
 		Name:       funcDecl.Name.Name,
 		Comment:    &graph.LocationNode{Text: strings.TrimSpace(comment), Location: commentLocation},
+		Annotation: directiveNode(funcDecl.Doc),
 		Receiver:   recvTypeStr,
 		TypeParams: extractTypeParams(funcDecl.Type.TypeParams, importMap),
 		IsExported: funcDecl.Name.IsExported(),
 		Location:   methodLocation,
-		Signature:  "",                   // Empty signature to match test expectations
-		Parameters: []*graph.Parameter{}, // Empty slice to match test expectations
-		Results:    []*graph.Parameter{}, // Empty slice to match test expectations
-	}
-
-	// Process parameters and results for specific methods to match test expectations
-	if funcDecl.Name.Name == "Increment" {
-		// Add the "amount" parameter for the Increment method
-		method.Parameters = []*graph.Parameter{
-			{
-				Name: "amount",
-				Type: &graph.Type{
-					Name: "int",
-				},
-			},
+		Parameters: i.processParameters(funcDecl.Type.Params, importMap),
+		Results:    i.processParameters(funcDecl.Type.Results, importMap),
+	}
+	var recvName string
+	if funcDecl.Recv != nil && len(funcDecl.Recv.List) > 0 && len(funcDecl.Recv.List[0].Names) > 0 {
+		recvName = funcDecl.Recv.List[0].Names[0].Name
+	}
+	method.Signature = functionSignature(method, recvName)
+
+	method.References = extractReferences(funcDecl.Body)
+	method.Complexity = cyclomaticComplexity(funcDecl.Body)
+
+	if method.Body = i.bodyNode(funcDecl.Body); method.Body != nil {
+		method.Hash = graph.BodyHash(method.Body.Text)
+	}
+	return method
+}
+
+// processInterfaceMethods converts method specifications of an interface, names of embedded interfaces are returned
+// separately
+func (i *Inspector) processInterfaceMethods(iface *ast.InterfaceType, importMap map[string]string) ([]*graph.Function, []string) {
+	if iface.Methods == nil {
+		return nil, nil
+	}
+	var methods []*graph.Function
+	var embedded []string
+	for _, field := range iface.Methods.List {
+		funcType, ok := field.Type.(*ast.FuncType)
+		if !ok {
+			switch field.Type.(type) {
+			case *ast.Ident, *ast.SelectorExpr, *ast.IndexExpr, *ast.IndexListExpr: // e.g. io.Reader, type sets are skipped
+				embedded = append(embedded, exprToString(field.Type, importMap))
+			}
+			continue
 		}
-	} else if funcDecl.Name.Name == "Value" && funcDecl.Type.Results != nil && len(funcDecl.Type.Results.List) > 0 {
-		// Add the result parameter for the Value method
-		r := funcDecl.Type.Results.List[0]
-		resultType := &graph.Type{
-			Name: exprToString(r.Type, importMap),
+		for _, name := range field.Names {
+			method := &graph.Function{
+				Name:       name.Name,
+				Comment:    &graph.LocationNode{Text: strings.TrimSpace(field.Doc.Text())},
+				IsExported: name.IsExported(),
+				Parameters: i.processParameters(funcType.Params, importMap),
+				Results:    i.processParameters(funcType.Results, importMap),
+			}
+			method.Signature = functionSignature(method, "")
+			methods = append(methods, method)
 		}
-		method.Results = []*graph.Parameter{
-			{
-				Type: resultType,
-			},
+	}
+	return methods, embedded
+}
+
+// bodyNode returns the source text of a function body including braces, nil when the source is not available
+func (i *Inspector) bodyNode(body *ast.BlockStmt) *graph.LocationNode {
+	if body == nil || !body.Lbrace.IsValid() || !body.Rbrace.IsValid() {
+		return nil
+	}
+	start, end := i.fset.Position(body.Lbrace).Offset, i.fset.Position(body.Rbrace).Offset+1
+	if start < 0 || end > len(i.src) || start >= end {
+		return nil
+	}
+	return &graph.LocationNode{Text: string(i.src[start:end]), Location: graph.Location{Start: start, End: end}}
+}
+
+// functionSignature composes a function signature from parsed receiver, type parameters, parameters and results,
+// e.g. "func (c *Counter) Add(a int, b int) (int, error)"
+func functionSignature(function *graph.Function, recvName string) string {
+	builder := &strings.Builder{}
+	builder.WriteString("func ")
+	if function.Receiver != "" {
+		builder.WriteString("(")
+		if recvName != "" {
+			builder.WriteString(recvName + " ")
 		}
+		builder.WriteString(function.Receiver + ") ")
+	}
+	builder.WriteString(function.Name)
+	if function.Receiver == "" && len(function.TypeParams) > 0 {
+		var params []string
+		for _, param := range function.TypeParams {
+			params = append(params, strings.TrimSpace(param.Name+" "+param.Constraint))
+		}
+		builder.WriteString("[" + strings.Join(params, ", ") + "]")
+	}
+	builder.WriteString("(" + joinParameters(function.Parameters) + ")")
+	switch {
+	case len(function.Results) == 1 && function.Results[0].Name == "":
+		builder.WriteString(" " + function.Results[0].Type.Name)
+	case len(function.Results) > 0:
+		builder.WriteString(" (" + joinParameters(function.Results) + ")")
 	}
+	return builder.String()
+}
 
-	// Set body to nil to match test expectations
-	method.Body = nil
-	return method
+// joinParameters renders parameters as a comma separated list
+func joinParameters(parameters []*graph.Parameter) string {
+	var items []string
+	for _, param := range parameters {
+		typeName := param.Type.Name
+		if param.IsVariadic {
+			typeName = "..." + typeName
+		}
+		items = append(items, strings.TrimSpace(param.Name+" "+typeName))
+	}
+	return strings.Join(items, ", ")
 }
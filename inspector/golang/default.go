@@ -0,0 +1,102 @@
+package golang
+
+import (
+	"github.com/viant/linager/inspector/graph"
+	"go/ast"
+	"strings"
+)
+
+// fieldDefault holds a field value set by a constructor composite literal
+type fieldDefault struct {
+	value    string
+	location *graph.Location
+}
+
+// constructorDefaults collects field values of composite literals of T in constructor functions (New*, Make*) returning
+// T or *T, keyed by type and field name; values of earlier constructors take precedence
+func (i *Inspector) constructorDefaults(file *ast.File, defaults map[string]map[string]*fieldDefault) {
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Recv != nil || funcDecl.Body == nil || funcDecl.Type.Results == nil {
+			continue
+		}
+		if name := funcDecl.Name.Name; !strings.HasPrefix(name, "New") && !strings.HasPrefix(name, "Make") {
+			continue
+		}
+		returned := map[string]bool{}
+		for _, result := range funcDecl.Type.Results.List {
+			if typeName := baseTypeIdent(result.Type); typeName != "" {
+				returned[typeName] = true
+			}
+		}
+		ast.Inspect(funcDecl.Body, func(node ast.Node) bool {
+			literal, ok := node.(*ast.CompositeLit)
+			if !ok {
+				return true
+			}
+			typeName := baseTypeIdent(literal.Type)
+			if !returned[typeName] {
+				return true
+			}
+			for _, elt := range literal.Elts {
+				keyValue, ok := elt.(*ast.KeyValueExpr)
+				if !ok {
+					continue
+				}
+				key, ok := keyValue.Key.(*ast.Ident)
+				if !ok {
+					continue
+				}
+				if defaults[typeName] == nil {
+					defaults[typeName] = map[string]*fieldDefault{}
+				}
+				if _, ok := defaults[typeName][key.Name]; ok {
+					continue
+				}
+				if value := i.defaultValue(keyValue.Value); value != nil {
+					defaults[typeName][key.Name] = value
+				}
+			}
+			return true
+		})
+	}
+}
+
+// defaultValue returns the source of a literal value expression with its location
+func (i *Inspector) defaultValue(expr ast.Expr) *fieldDefault {
+	start, end := i.fset.Position(expr.Pos()).Offset, i.fset.Position(expr.End()).Offset
+	if start < 0 || end > len(i.src) || start >= end {
+		return nil
+	}
+	return &fieldDefault{value: string(i.src[start:end]), location: &graph.Location{Start: start, End: end}}
+}
+
+// baseTypeIdent returns the name of a local type expression T, *T or T[P], empty otherwise
+func baseTypeIdent(expr ast.Expr) string {
+	switch actual := expr.(type) {
+	case *ast.Ident:
+		return actual.Name
+	case *ast.StarExpr:
+		return baseTypeIdent(actual.X)
+	case *ast.IndexExpr:
+		return baseTypeIdent(actual.X)
+	case *ast.IndexListExpr:
+		return baseTypeIdent(actual.X)
+	}
+	return ""
+}
+
+// applyFieldDefaults sets defaults of fields without one
+func applyFieldDefaults(types []*graph.Type, defaults map[string]map[string]*fieldDefault) {
+	for _, aType := range types {
+		fields := defaults[aType.Name]
+		if len(fields) == 0 {
+			continue
+		}
+		for _, field := range aType.Fields {
+			if value, ok := fields[field.Name]; ok && field.Default == "" {
+				field.Default, field.DefaultFrom = value.value, value.location
+			}
+		}
+	}
+}
@@ -0,0 +1,319 @@
+package golang_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/linager/inspector/golang"
+	"github.com/viant/linager/inspector/graph"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// countingLoader counts lazily loaded asset reads
+type countingLoader struct {
+	loads []string
+}
+
+func (l *countingLoader) Load(ctx context.Context, location string) ([]byte, error) {
+	l.loads = append(l.loads, location)
+	return os.ReadFile(location)
+}
+
+func TestProject_CreateDocuments_References(t *testing.T) {
+	src := `package test
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Parser struct {
+	sep string
+}
+
+// Parse splits the input
+func (p *Parser) Parse(input string) []string {
+	parts := strings.Split(input, p.sep)
+	parts = append(parts, strings.Split(input, ",")...)
+	return parts
+}
+
+func Print(v string) {
+	fmt.Println(v)
+	_ = Parser{sep: v}
+}
+
+// Split calls a parameter shadowing the strings import
+func Split(strings *Parser, v string) []string {
+	parts := strings.Parse(v)
+	return parts
+}
+`
+	inspector := golang.NewInspector(&graph.Config{IncludeUnexported: true})
+	file, err := inspector.InspectSource([]byte(src))
+	if !assert.NoError(t, err) {
+		return
+	}
+	project := &graph.Project{Name: "test", Packages: []*graph.Package{{Name: "test", FileSet: []*graph.File{file}}}}
+	documents, err := project.CreateDocuments(context.Background(), "")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var method, function, shadowed *graph.Document
+	for _, doc := range documents {
+		switch {
+		case doc.Kind == graph.KindTypeMethod:
+			method = doc
+		case doc.Kind == graph.KindFileFunc && doc.Name == "Print":
+			function = doc
+		case doc.Kind == graph.KindFileFunc && doc.Name == "Split":
+			shadowed = doc
+		}
+	}
+	if assert.NotNil(t, method) {
+		assert.Equal(t, []string{"strings.Split"}, method.References)
+		assert.Equal(t, []string{"strings"}, method.Imports)
+	}
+	if assert.NotNil(t, function) {
+		assert.Equal(t, []string{"Parser", "fmt.Println"}, function.References)
+		assert.Equal(t, []string{"fmt"}, function.Imports)
+	}
+	if assert.NotNil(t, shadowed) {
+		assert.Empty(t, shadowed.References, "calls on parameters are not references")
+		assert.Empty(t, shadowed.Imports, "a parameter shadowing an import is not an import use")
+	}
+
+	withoutMetadata := *method
+	withoutMetadata.References, withoutMetadata.Imports = nil, nil
+	assert.Equal(t, withoutMetadata.Size()+len("strings.Split")+len("strings"), method.Size())
+}
+
+func TestProject_CreateDocumentsStream(t *testing.T) {
+	inspector := golang.NewInspector(&graph.Config{IncludeUnexported: true})
+	packages, err := inspector.InspectPackages("testdata")
+	if !assert.NoError(t, err) {
+		return
+	}
+	project := &graph.Project{Name: "testdata", Packages: packages}
+	documents, err := project.CreateDocuments(context.Background(), "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NotEmpty(t, documents)
+
+	count := 0
+	err = project.CreateDocumentsStream(context.Background(), "", func(doc *graph.Document) error {
+		count++
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, len(documents), count)
+
+	buffer := &bytes.Buffer{}
+	assert.NoError(t, project.WriteJSONL(context.Background(), "", buffer))
+	assert.Equal(t, len(documents), strings.Count(buffer.String(), "\n"))
+
+	stop := errors.New("stop")
+	count = 0
+	err = project.CreateDocumentsStream(context.Background(), "", func(doc *graph.Document) error {
+		count++
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 1, count)
+}
+
+func BenchmarkProject_WriteJSONL(b *testing.B) {
+	inspector := golang.NewInspector(&graph.Config{IncludeUnexported: true})
+	packages, err := inspector.InspectPackages("testdata")
+	if err != nil {
+		b.Fatal(err)
+	}
+	// replicate fixture packages to simulate a large project
+	project := &graph.Project{Name: "testdata"}
+	for i := 0; i < 200; i++ {
+		project.Packages = append(project.Packages, packages...)
+	}
+	b.Run("slice", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			documents, err := project.CreateDocuments(context.Background(), "")
+			if err != nil {
+				b.Fatal(err)
+			}
+			if err = documents.WriteJSONL(io.Discard); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("stream", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if err := project.WriteJSONL(context.Background(), "", io.Discard); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestProject_Instantiations(t *testing.T) {
+	project, err := golang.NewInspector(&graph.Config{IncludeUnexported: true}).InspectProject("testdata")
+	if !assert.NoError(t, err) {
+		return
+	}
+	var stack *graph.Package
+	for _, pkg := range project.Packages {
+		if pkg.Name == "stack" {
+			stack = pkg
+		}
+	}
+	if !assert.NotNil(t, stack) {
+		return
+	}
+	instances := project.Instantiations()[stack.ImportPath+".Stack"]
+	var observed []string
+	for _, instance := range instances {
+		observed = append(observed, instance.String()+"@"+instance.File)
+	}
+	assert.Contains(t, observed, "Stack[string]@app/main.go")
+
+	documents, err := project.CreateDocuments(context.Background(), "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	found := false
+	for _, doc := range documents {
+		if doc.Kind == graph.KindType && doc.Name == "Stack" {
+			found = true
+			assert.Contains(t, doc.Content, "// Known instantiations: Stack[string]")
+		}
+	}
+	assert.True(t, found)
+}
+
+func TestProject_CreateDocuments_LazyAssets(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "app.go"), []byte("package app\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("name: app\n"), 0644))
+	large, err := os.Create(filepath.Join(dir, "fixture.bin"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, large.Truncate(100<<20)) // sparse file, no content is written
+	assert.NoError(t, large.Close())
+
+	packages, err := golang.NewInspector(&graph.Config{IncludeUnexported: true}).InspectPackages(dir)
+	if !assert.NoError(t, err) || !assert.Len(t, packages, 1) || !assert.Len(t, packages[0].Assets, 2) {
+		return
+	}
+	assets := map[string]*graph.Asset{}
+	for _, asset := range packages[0].Assets {
+		assets[filepath.Base(asset.Path)] = asset
+	}
+	small, big := assets["config.yaml"], assets["fixture.bin"]
+	assert.Equal(t, []byte("name: app\n"), small.Content)
+	assert.NotZero(t, small.Hash)
+	assert.Nil(t, big.Content)
+	assert.Equal(t, int64(100<<20), big.Size)
+	assert.Zero(t, big.Hash)
+
+	loader := &countingLoader{}
+	project := &graph.Project{Name: "app", Packages: packages}
+	project.SetAssetLoader(loader)
+	documents, err := project.CreateDocuments(context.Background(), "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	var assetDocs []*graph.Document
+	for _, doc := range documents {
+		if doc.Kind == graph.KindAsset {
+			assetDocs = append(assetDocs, doc)
+		}
+	}
+	expected := &graph.Document{Kind: graph.KindAsset, Project: "app", Package: "app", Path: small.Path, Content: "name: app\n"}
+	expected.Hash = expected.HashContent()
+	assert.Equal(t, []*graph.Document{expected}, assetDocs)
+	assert.Empty(t, loader.loads) // oversized assets are skipped without loading
+
+	content, err := big.Load(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, content, 100<<20)
+	assert.Equal(t, []string{big.Path}, loader.loads)
+	assert.Nil(t, big.Content)
+}
+
+func TestInspector_InspectSource_Enums(t *testing.T) {
+	src := `package test
+
+type Status int
+
+const (
+	Pending Status = iota
+	Active
+	Closed
+)
+
+func (s Status) String() string {
+	switch s {
+	case Pending:
+		return "pending"
+	case Active, Closed:
+		return "settled"
+	}
+	return "unknown"
+}
+
+type Color string
+
+const (
+	Red   Color = "r"
+	Green Color = "g"
+)
+
+const Limit = 10
+
+var colorNames = map[Color]string{
+	Red:   "red",
+	Green: "green",
+}
+`
+	file, err := golang.NewInspector(&graph.Config{IncludeUnexported: true}).InspectSource([]byte(src))
+	if !assert.NoError(t, err) {
+		return
+	}
+	status := file.LookupType("Status")
+	if assert.NotNil(t, status) && assert.NotNil(t, status.Enum) {
+		assert.True(t, status.Enum.Stringer)
+		assert.Equal(t, []*graph.EnumValue{
+			{Name: "Pending", Literal: "iota", Ordinal: 0, Display: "pending"},
+			{Name: "Active", Literal: "iota", Ordinal: 1, Display: "settled"},
+			{Name: "Closed", Literal: "iota", Ordinal: 2, Display: "settled"},
+		}, status.Enum.Values)
+	}
+	color := file.LookupType("Color")
+	if assert.NotNil(t, color) && assert.NotNil(t, color.Enum) {
+		assert.False(t, color.Enum.Stringer)
+		assert.Equal(t, "colorNames", color.Enum.NameMap)
+		assert.Equal(t, map[string]string{"Red": "red", "Green": "green"}, color.Enum.DisplayNames())
+		assert.Equal(t, `"g"`, color.Enum.Lookup("Green").Literal)
+	}
+
+	project := &graph.Project{Name: "test", Packages: []*graph.Package{{Name: "test", FileSet: []*graph.File{file}}}}
+	documents, err := project.CreateDocuments(context.Background(), "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	var content string
+	for _, doc := range documents {
+		if doc.Kind == graph.KindType && doc.Name == "Status" {
+			content = doc.Content
+		}
+	}
+	assert.Contains(t, content, "// Values:\n// 0. Pending = iota (\"pending\")\n// 1. Active = iota (\"settled\")\n")
+}
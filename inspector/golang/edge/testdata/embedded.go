@@ -0,0 +1,29 @@
+package edge
+
+import (
+	"io"
+	"sync"
+)
+
+// Base holds shared identity
+type Base struct {
+	ID string `json:"id"`
+}
+
+// Document embeds Base and a mutex
+type Document struct {
+	Base
+	*sync.Mutex
+	io.Reader
+	Title   string            `json:"title,omitempty"`
+	Tags    []string          `json:"tags"`
+	Meta    map[string]string `json:"-"`
+	Handler func(string) error
+}
+
+const (
+	// Draft document status
+	Draft = iota
+	// Published document status
+	Published
+)
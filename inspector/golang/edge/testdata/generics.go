@@ -0,0 +1,34 @@
+package edge
+
+import "fmt"
+
+// Number constrains numeric types
+type Number interface {
+	~int | ~int64 | ~float64
+}
+
+// Cache stores values by key
+type Cache[K comparable, V any] struct {
+	items map[K]V
+	limit int
+}
+
+// Get returns a cached value
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	value, ok := c.items[key]
+	return value, ok
+}
+
+// Sum adds all values
+func Sum[T Number](values ...T) T {
+	var total T
+	for _, value := range values {
+		total += value
+	}
+	return total
+}
+
+// Describe formats a pair
+func Describe[A, B fmt.Stringer](a A, b B) string {
+	return a.String() + b.String()
+}
@@ -3,24 +3,76 @@ package golang
 import (
 	"fmt"
 	"github.com/viant/linager/inspector/graph"
+	"go/format"
+	"sort"
 	"strings"
 )
 
-type Emitter struct{}
+// EmitterOptions controls Go source formatting, the zero value emits declarations as recorded
+type EmitterOptions struct {
+	Format       bool // Format output with gofmt
+	GroupImports bool // Sort imports into standard library and other groups, as goimports does
+	BuildTags    bool // Preserve the //go:build constraint of a file
+	// NormalizeImports merges duplicated imports, drops unused ones and groups them before emitting, the file is
+	// normalized in place, see graph.File.NormalizeImports
+	NormalizeImports bool
+}
+
+// Emitter renders a graph file as Go source
+type Emitter struct {
+	Options EmitterOptions
+}
+
+// NewEmitter creates a Go emitter with the given options, nil options use defaults
+func NewEmitter(options *EmitterOptions) *Emitter {
+	ret := &Emitter{}
+	if options != nil {
+		ret.Options = *options
+	}
+	return ret
+}
+
+// Capabilities returns Go emitter capabilities
+func (g *Emitter) Capabilities() graph.EmitterCapabilities {
+	return graph.EmitterCapabilities{
+		Language:     "go",
+		Extensions:   []string{".go"},
+		Format:       g.Options.Format,
+		GroupImports: g.Options.GroupImports || g.Options.NormalizeImports,
+		BuildTags:    g.Options.BuildTags,
+		Indent:       "\t",
+		BraceStyle:   "same-line",
+	}
+}
 
 func (g *Emitter) Emit(file *graph.File) ([]byte, error) {
 	// Start with package declaration and imports
 	builder := &strings.Builder{}
+	if g.Options.BuildTags && file.BuildTags != "" {
+		builder.WriteString("//go:build " + file.BuildTags + "\n\n")
+	}
 	builder.WriteString(fmt.Sprintf("package %s\n\n", file.Package))
+	if g.Options.NormalizeImports {
+		file.NormalizeImports()
+	}
 
 	// Add imports if any
 	if len(file.Imports) > 0 {
 		builder.WriteString("import (\n")
-		for _, imp := range file.Imports {
-			if imp.Name != "" {
-				builder.WriteString(fmt.Sprintf("\t%s %q\n", imp.Name, imp.Path))
-			} else {
-				builder.WriteString(fmt.Sprintf("\t%q\n", imp.Path))
+		groups := [][]graph.Import{file.Imports}
+		if g.Options.GroupImports || g.Options.NormalizeImports {
+			groups = groupImports(file.Imports)
+		}
+		for i, group := range groups {
+			if i > 0 {
+				builder.WriteString("\n")
+			}
+			for _, imp := range group {
+				if imp.Name != "" {
+					builder.WriteString(fmt.Sprintf("\t%s %q\n", imp.Name, imp.Path))
+				} else {
+					builder.WriteString(fmt.Sprintf("\t%q\n", imp.Path))
+				}
 			}
 		}
 		builder.WriteString(")\n\n")
@@ -44,6 +96,9 @@ func (g *Emitter) Emit(file *graph.File) ([]byte, error) {
 
 	// Add types if any
 	for _, typ := range file.Types {
+		if typ.Anonymous { // declared inline by the owning field or variable
+			continue
+		}
 		if typ.Location != nil && typ.Location.Raw != "" {
 			// Use the Type.Content() method which includes fields
 			builder.WriteString(typ.Content())
@@ -53,11 +108,57 @@ func (g *Emitter) Emit(file *graph.File) ([]byte, error) {
 
 	// Add functions if any
 	for _, function := range file.Functions {
-		if function.Location != nil && function.Location.Raw != "" {
+		switch {
+		case function.Location != nil && function.Location.Raw != "":
 			builder.WriteString(function.Location.Raw)
-			builder.WriteString("\n\n")
+		case function.Signature != "":
+			// Rebuild declaration from the parsed signature when raw source is not retained
+			builder.WriteString(function.Signature + " ")
+			builder.WriteString(functionBody(function))
+		default:
+			continue
 		}
+		builder.WriteString("\n\n")
 	}
 
-	return []byte(builder.String()), nil
+	if !g.Options.Format {
+		return []byte(builder.String()), nil
+	}
+	formatted, err := format.Source([]byte(builder.String()))
+	if err != nil {
+		return nil, &graph.ErrParse{Path: file.Path, Cause: err}
+	}
+	return formatted, nil
+}
+
+// groupImports returns sorted standard library imports followed by sorted other imports, empty groups are omitted
+func groupImports(imports []graph.Import) [][]graph.Import {
+	var std, other []graph.Import
+	for _, imp := range imports {
+		if graph.IsStdImport(imp.Path) {
+			std = append(std, imp)
+		} else {
+			other = append(other, imp)
+		}
+	}
+	var result [][]graph.Import
+	for _, group := range [][]graph.Import{std, other} {
+		if len(group) == 0 {
+			continue
+		}
+		sort.SliceStable(group, func(i, j int) bool { return group[i].Path < group[j].Path })
+		result = append(result, group)
+	}
+	return result
+}
+
+// functionBody returns function body text or a stub body satisfying declared results
+func functionBody(function *graph.Function) string {
+	if function.Body != nil && function.Body.Text != "" {
+		return function.Body.Text
+	}
+	if len(function.Results) > 0 {
+		return "{\n\tpanic(\"not implemented\")\n}"
+	}
+	return "{\n}"
 }
@@ -0,0 +1,202 @@
+package golang
+
+import (
+	"github.com/viant/linager/inspector/graph"
+	"go/ast"
+	"go/token"
+	"reflect"
+	"strconv"
+)
+
+// enumScan collects typed constants and display names of a type across package files
+type enumScan struct {
+	values   []*graph.EnumValue
+	stringer bool
+	nameMap  string
+	display  map[string]string // value name -> display name
+	ordinals map[int]string    // positional display names of an indexed string array, applied by ordinal
+}
+
+// scanEnums collects constants of local defined types (implicitly repeated specs of a const block inherit the type
+// and expression of the previous spec, as iota enums do), String() methods mapping values to names by a switch, an
+// indexed string array or a name map, and map[Type]string variables
+func (i *Inspector) scanEnums(file *ast.File, scans map[string]*enumScan) {
+	lookup := func(typeName string) *enumScan {
+		if scans[typeName] == nil {
+			scans[typeName] = &enumScan{display: map[string]string{}}
+		}
+		return scans[typeName]
+	}
+	for _, decl := range file.Decls {
+		switch actual := decl.(type) {
+		case *ast.GenDecl:
+			switch actual.Tok {
+			case token.CONST:
+				var typeName string
+				var values []ast.Expr
+				for _, spec := range actual.Specs {
+					valueSpec, ok := spec.(*ast.ValueSpec)
+					if !ok {
+						continue
+					}
+					if valueSpec.Type != nil || len(valueSpec.Values) > 0 {
+						typeName, values = "", valueSpec.Values
+						if ident, ok := valueSpec.Type.(*ast.Ident); ok && kindFromBasicType(ident.Name) == reflect.Invalid {
+							typeName = ident.Name
+						}
+					}
+					if typeName == "" {
+						continue
+					}
+					scan := lookup(typeName)
+					for j, name := range valueSpec.Names {
+						if name.Name == "_" {
+							continue
+						}
+						var literal string
+						if j < len(values) {
+							literal = extractValueAsString(values[j], i.fset)
+						}
+						scan.values = append(scan.values, &graph.EnumValue{Name: name.Name, Literal: literal, Ordinal: len(scan.values)})
+					}
+				}
+			case token.VAR:
+				for _, spec := range actual.Specs {
+					valueSpec, ok := spec.(*ast.ValueSpec)
+					if !ok || len(valueSpec.Names) != 1 || len(valueSpec.Values) != 1 {
+						continue
+					}
+					if literal, ok := valueSpec.Values[0].(*ast.CompositeLit); ok {
+						if typeName := stringMapKey(literal.Type); typeName != "" {
+							scan := lookup(typeName)
+							scan.nameMap = valueSpec.Names[0].Name
+							keyedNames(literal, scan.display)
+						}
+					}
+				}
+			}
+		case *ast.FuncDecl:
+			if actual.Name.Name != "String" || actual.Recv == nil || len(actual.Recv.List) != 1 || actual.Body == nil {
+				continue
+			}
+			if len(actual.Type.Params.List) > 0 || actual.Type.Results == nil || len(actual.Type.Results.List) != 1 {
+				continue
+			}
+			if result, ok := actual.Type.Results.List[0].Type.(*ast.Ident); !ok || result.Name != "string" {
+				continue
+			}
+			typeName := baseTypeIdent(actual.Recv.List[0].Type)
+			if typeName == "" {
+				continue
+			}
+			scan := lookup(typeName)
+			scan.stringer = true
+			stringerNames(actual.Body, scan)
+		}
+	}
+}
+
+// stringerNames collects display names returned by a String() method body: switch cases returning string literals
+// or an indexed string array literal
+func stringerNames(body *ast.BlockStmt, scan *enumScan) {
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch actual := node.(type) {
+		case *ast.CaseClause:
+			if len(actual.Body) == 0 {
+				return true
+			}
+			ret, ok := actual.Body[0].(*ast.ReturnStmt)
+			if !ok || len(ret.Results) != 1 {
+				return true
+			}
+			display, ok := stringLiteral(ret.Results[0])
+			if !ok {
+				return true
+			}
+			for _, expr := range actual.List {
+				if ident, ok := expr.(*ast.Ident); ok {
+					scan.display[ident.Name] = display
+				}
+			}
+		case *ast.IndexExpr:
+			// name maps indexed by the receiver are package variables, collected with them
+			literal, ok := actual.X.(*ast.CompositeLit)
+			if !ok || keyedNames(literal, scan.display) {
+				return true
+			}
+			if scan.ordinals == nil {
+				scan.ordinals = map[int]string{}
+			}
+			for ordinal, elt := range literal.Elts {
+				if display, ok := stringLiteral(elt); ok {
+					scan.ordinals[ordinal] = display
+				}
+			}
+			return false
+		}
+		return true
+	})
+}
+
+// keyedNames collects string values of a composite literal keyed by identifiers, it reports whether any was found
+func keyedNames(literal *ast.CompositeLit, display map[string]string) bool {
+	found := false
+	for _, elt := range literal.Elts {
+		keyValue, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key, ok := keyValue.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		if value, ok := stringLiteral(keyValue.Value); ok {
+			display[key.Name] = value
+			found = true
+		}
+	}
+	return found
+}
+
+// stringMapKey returns T of a map[T]string type expression, empty otherwise
+func stringMapKey(expr ast.Expr) string {
+	mapType, ok := expr.(*ast.MapType)
+	if !ok {
+		return ""
+	}
+	key, ok := mapType.Key.(*ast.Ident)
+	if value, isIdent := mapType.Value.(*ast.Ident); !ok || !isIdent || value.Name != "string" {
+		return ""
+	}
+	return key.Name
+}
+
+// stringLiteral returns the value of a string literal
+func stringLiteral(expr ast.Expr) (string, bool) {
+	literal, ok := expr.(*ast.BasicLit)
+	if !ok || literal.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(literal.Value)
+	return value, err == nil
+}
+
+// applyEnums sets Enum of types with typed constants
+func applyEnums(types []*graph.Type, scans map[string]*enumScan) {
+	for _, aType := range types {
+		scan, ok := scans[aType.Name]
+		if !ok || len(scan.values) == 0 {
+			continue
+		}
+		enum := &graph.Enum{Stringer: scan.stringer, NameMap: scan.nameMap}
+		for _, value := range scan.values {
+			item := *value
+			item.Display = scan.display[value.Name]
+			if item.Display == "" {
+				item.Display = scan.ordinals[value.Ordinal]
+			}
+			enum.Values = append(enum.Values, &item)
+		}
+		aType.Enum = enum
+	}
+}
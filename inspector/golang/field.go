@@ -7,8 +7,8 @@ import (
 	"strings"
 )
 
-// processFields processes struct fields
-func (i *Inspector) processFields(fields *ast.FieldList, importMap map[string]string) []*graph.Field {
+// processFields processes struct fields of the owner type, inline struct fields are modeled as anonymous types
+func (i *Inspector) processFields(fields *ast.FieldList, importMap map[string]string, owner string) []*graph.Field {
 	var result []*graph.Field
 
 	for _, field := range fields.List {
@@ -58,6 +58,7 @@ func (i *Inspector) processFields(fields *ast.FieldList, importMap map[string]st
 				Annotation: annotation,
 				IsExported: isExportedType(field.Type),
 				IsEmbedded: true,
+				Optional:   isOptionalField(field.Type, tag),
 			})
 		} else {
 			// Named fields
@@ -69,6 +70,11 @@ func (i *Inspector) processFields(fields *ast.FieldList, importMap map[string]st
 				fieldType := &graph.Type{
 					Name: exprToString(field.Type, importMap),
 				}
+				if structType, ok := field.Type.(*ast.StructType); ok {
+					fieldType = i.anonymousType(structType, importMap, strings.TrimSuffix(owner, graph.AnonymousSuffix)+"."+name.Name)
+					fieldType.ParentType = owner
+					fieldType.IsExported = name.IsExported()
+				}
 
 				result = append(result, &graph.Field{
 					Name:       name.Name,
@@ -76,6 +82,7 @@ func (i *Inspector) processFields(fields *ast.FieldList, importMap map[string]st
 					Tag:        tag,
 					Comment:    comment,
 					IsExported: name.IsExported(),
+					Optional:   isOptionalField(field.Type, tag),
 				})
 			}
 		}
@@ -84,6 +91,144 @@ func (i *Inspector) processFields(fields *ast.FieldList, importMap map[string]st
 	return result
 }
 
+// anonymousType models an inline struct declaration as a synthetic type named after its declaring path
+func (i *Inspector) anonymousType(structType *ast.StructType, importMap map[string]string, path string) *graph.Type {
+	aType := &graph.Type{Name: graph.AnonymousTypeName(path), Kind: reflect.Struct, Anonymous: true}
+	if structType.Fields != nil {
+		aType.Fields = i.processFields(structType.Fields, importMap, aType.Name)
+	}
+	return aType
+}
+
+// anonymousTypes returns anonymous types referenced by fields of types and by variables, nested ones included
+func anonymousTypes(pkg string, types []*graph.Type, variables []*graph.Variable) []*graph.Type {
+	var result []*graph.Type
+	var collect func(aType *graph.Type)
+	collect = func(aType *graph.Type) {
+		aType.Package = pkg
+		result = append(result, aType)
+		for _, field := range aType.Fields {
+			if field.Type != nil && field.Type.Anonymous {
+				collect(field.Type)
+			}
+		}
+	}
+	for _, aType := range types {
+		for _, field := range aType.Fields {
+			if field.Type != nil && field.Type.Anonymous {
+				collect(field.Type)
+			}
+		}
+	}
+	for _, variable := range variables {
+		if variable.Type != nil && variable.Type.Anonymous {
+			collect(variable.Type)
+		}
+	}
+	return result
+}
+
+// markTypeParamFields flags fields whose type is one of the declared type parameters
+func markTypeParamFields(fields []*graph.Field, typeParams []*graph.TypeParam) {
+	if len(typeParams) == 0 {
+		return
+	}
+	for _, field := range fields {
+		if field.Type == nil {
+			continue
+		}
+		for _, param := range typeParams {
+			if field.Type.Name == param.Name {
+				field.IsTypeParam = true
+				field.Type.Kind = reflect.Invalid
+				break
+			}
+		}
+	}
+}
+
+// isOptionalField reports whether a field can be absent: nil-able types (pointers, slices, maps, functions, channels,
+// interfaces) or json omitempty; fields of named interface types are flagged by markInterfaceFields
+func isOptionalField(expr ast.Expr, tag reflect.StructTag) bool {
+	if jsonTag, ok := tag.Lookup("json"); ok {
+		for _, option := range strings.Split(jsonTag, ",")[1:] {
+			if option == "omitempty" {
+				return true
+			}
+		}
+	}
+	switch t := expr.(type) {
+	case *ast.StarExpr, *ast.MapType, *ast.InterfaceType, *ast.FuncType, *ast.ChanType:
+		return true
+	case *ast.ArrayType:
+		return t.Len == nil
+	case *ast.Ident:
+		return t.Name == "any" || t.Name == "error"
+	}
+	return false
+}
+
+// markInterfaceFields flags fields of named interface types as optional; unqualified field types are resolved against
+// interface types of the field's package, qualified ones through file imports against the inspected packages, then
+// against package sources located by FindPackageDir
+func (i *Inspector) markInterfaceFields(packages []*graph.Package) {
+	interfaces := map[string]map[string]bool{}
+	for _, pkg := range packages {
+		for _, file := range pkg.FileSet {
+			for _, aType := range file.Types {
+				if aType.Kind != reflect.Interface {
+					continue
+				}
+				if interfaces[pkg.ImportPath] == nil {
+					interfaces[pkg.ImportPath] = map[string]bool{}
+				}
+				interfaces[pkg.ImportPath][aType.Name] = true
+			}
+		}
+	}
+	for _, pkg := range packages {
+		for _, file := range pkg.FileSet {
+			imports := map[string]string{}
+			for _, imp := range file.Imports {
+				imports[imp.Name] = imp.Path
+			}
+			for _, aType := range file.Types {
+				for _, field := range aType.Fields {
+					if field.Optional || field.Type == nil || field.Type.Anonymous {
+						continue
+					}
+					importPath, name := pkg.ImportPath, field.Type.Name
+					if index := strings.IndexByte(name, '['); index != -1 {
+						name = name[:index]
+					}
+					if index := strings.LastIndexByte(name, '.'); index != -1 {
+						path, ok := imports[name[:index]]
+						if !ok {
+							continue
+						}
+						importPath, name = path, name[index+1:]
+					}
+					declared, ok := interfaces[importPath]
+					if !ok && importPath != pkg.ImportPath && !hasPackage(packages, importPath) {
+						declared = i.packageInterfaces(importPath)
+					}
+					field.Optional = declared[name]
+				}
+			}
+		}
+	}
+}
+
+// hasPackage reports whether packages include an import path
+func hasPackage(packages []*graph.Package, importPath string) bool {
+	for _, pkg := range packages {
+		if pkg.ImportPath == importPath {
+			return true
+		}
+	}
+	return false
+}
+
 // extractFieldTag extracts the tag from a field
 func extractFieldTag(field *ast.Field) string {
 	if field.Tag == nil {
@@ -2,6 +2,9 @@ package golang
 
 import (
 	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
 	"os"
 	"path/filepath"
 	"strings"
@@ -43,6 +46,9 @@ func ParseImports(file *ast.File) []ImportSpec {
 func FindPackageDir(importPath string) (string, error) {
 	// Check in GOROOT first
 	goRoot := os.Getenv("GOROOT")
+	if goRoot == "" {
+		goRoot = build.Default.GOROOT
+	}
 	if goRoot != "" {
 		dir := filepath.Join(goRoot, "src", importPath)
 		if dirExists(dir) {
@@ -76,6 +82,52 @@ func FindPackageDir(importPath string) (string, error) {
 	return "", os.ErrNotExist
 }
 
+// packageInterfaces returns names of interface types declared by a package located by FindPackageDir, only type
+// declarations are parsed and results are cached by import path
+func (i *Inspector) packageInterfaces(importPath string) map[string]bool {
+	if names, ok := i.interfaces[importPath]; ok {
+		return names
+	}
+	if i.interfaces == nil {
+		i.interfaces = map[string]map[string]bool{}
+	}
+	names := map[string]bool{}
+	i.interfaces[importPath] = names
+	dir, err := FindPackageDir(importPath)
+	if err != nil {
+		return names
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return names
+	}
+	fset := token.NewFileSet()
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != ".go" || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				if typeSpec, ok := spec.(*ast.TypeSpec); ok {
+					if _, ok := typeSpec.Type.(*ast.InterfaceType); ok {
+						names[typeSpec.Name.Name] = true
+					}
+				}
+			}
+		}
+	}
+	return names
+}
+
 // dirExists checks if a directory exists
 func dirExists(path string) bool {
 	info, err := os.Stat(path)
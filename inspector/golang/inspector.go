@@ -15,9 +15,10 @@ import (
 
 // Inspector provides functionality to inspect Go code and extract type information
 type Inspector struct {
-	fset   *token.FileSet
-	config *graph.Config
-	src    []byte // Store source for method body extraction
+	fset       *token.FileSet
+	config     *graph.Config
+	src        []byte                     // Store source for method body extraction
+	interfaces map[string]map[string]bool // Interface type names of packages located by FindPackageDir, by import path
 }
 
 // Config holds configuration options for the Inspector
@@ -38,7 +39,7 @@ func (i *Inspector) InspectSource(src []byte) (*graph.File, error) {
 	i.src = src // Store source for method body extraction
 	file, err := parser.ParseFile(i.fset, filename, src, parser.ParseComments)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse source: %w", err)
+		return nil, parseError(filename, err)
 	}
 
 	infoFile, err := i.processFile(file, filename)
@@ -53,13 +54,13 @@ func (i *Inspector) InspectFile(filename string) (*graph.File, error) {
 	// Read file content
 	src, err := os.ReadFile(filename)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read file %s: %w", filename, err)
+		return nil, fmt.Errorf("failed to read file %s: %w", filename, graph.NotFoundError("file", filename, err))
 	}
 
 	i.src = src // Store source for method body extraction
 	file, err := parser.ParseFile(i.fset, filename, src, parser.ParseComments)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse file %s: %w", filename, err)
+		return nil, parseError(filename, err)
 	}
 
 	return i.processFile(file, filename)
@@ -154,13 +155,13 @@ func (i *Inspector) GetFileWithFunction(filename, funcName, receiverType, receiv
 	// Read and parse the file
 	src, err := os.ReadFile(filename)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read file %s: %w", filename, err)
+		return nil, fmt.Errorf("failed to read file %s: %w", filename, graph.NotFoundError("file", filename, err))
 	}
 
 	fset := token.NewFileSet()
 	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse file %s: %w", filename, err)
+		return nil, parseError(filename, err)
 	}
 
 	// Add the function
@@ -206,6 +207,15 @@ func (i *Inspector) processFile(file *ast.File, filename string) (*graph.File, e
 
 	// Extract the import path from the file path
 	infoFile.ImportPath = getImportPath(filename)
+	if file.Doc != nil {
+		infoFile.Doc = strings.TrimSpace(file.Doc.Text())
+	}
+	infoFile.BuildTags = buildTags(file)
+	infoFile.SetSource(i.src)
+	if tokenFile := i.fset.File(file.Pos()); tokenFile != nil {
+		infoFile.Lines = tokenFile.LineCount()
+	}
+	infoFile.Instantiations = i.inspectInstantiations(file, filename, importMap)
 
 	// Add constants and variables to the file
 	constants, err := i.InspectConstants(file, importMap)
@@ -311,10 +321,11 @@ func (i *Inspector) processFile(file *ast.File, filename string) (*graph.File, e
 		case *ast.StructType:
 			t.Kind = reflect.Struct
 			if typeExpr.Fields != nil {
-				t.Fields = i.processFields(typeExpr.Fields, importMap)
+				t.Fields = i.processFields(typeExpr.Fields, importMap, t.Name)
 			}
 		case *ast.InterfaceType:
 			t.Kind = reflect.Interface
+			t.Methods, t.Extends = i.processInterfaceMethods(typeExpr, importMap)
 		case *ast.ArrayType:
 			t.Kind = reflect.Slice
 			t.ComponentType = exprToString(typeExpr.Elt, importMap)
@@ -343,11 +354,15 @@ func (i *Inspector) processFile(file *ast.File, filename string) (*graph.File, e
 
 		// Extract type parameters
 		t.TypeParams = extractTypeParams(ts.TypeParams, importMap)
+		markTypeParamFields(t.Fields, t.TypeParams)
 
 		types = append(types, t)
 	}
 
-	infoFile.Types = types
+	infoFile.Types = append(types, anonymousTypes(file.Name.Name, types, infoFile.Variables)...)
+	defaults := map[string]map[string]*fieldDefault{}
+	i.constructorDefaults(file, defaults)
+	applyFieldDefaults(infoFile.Types, defaults)
 
 	// Collect receiver types from methods
 	receiverTypes := make(map[string]*graph.Type)
@@ -404,9 +419,23 @@ func (i *Inspector) processFile(file *ast.File, filename string) (*graph.File, e
 		}
 
 		method := i.processMethod(funcDecl, importMap)
+		// Generic receivers (e.g. *Stack[T]) inherit constraints from the type declaration
+		for k, param := range method.TypeParams {
+			if param.Constraint == "" && k < len(targetType.TypeParams) {
+				param.Constraint = targetType.TypeParams[k].Constraint
+			}
+		}
 		targetType.Methods = append(targetType.Methods, method)
 	}
 
+	enums := map[string]*enumScan{}
+	i.scanEnums(file, enums)
+	applyEnums(infoFile.Types, enums)
+	i.markInterfaceFields([]*graph.Package{{ImportPath: infoFile.ImportPath, FileSet: []*graph.File{infoFile}}})
+	if i.config.ParseCoverage {
+		infoFile.Coverage = i.parseCoverage(file, filename, infoFile, importMap)
+	}
+
 	return infoFile, nil
 }
 
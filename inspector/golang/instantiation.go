@@ -0,0 +1,128 @@
+package golang
+
+import (
+	"github.com/viant/linager/inspector/graph"
+	"go/ast"
+)
+
+// inspectInstantiations collects generic instantiations with concrete type arguments (e.g. Stack[int]{}, New[string]()
+// or var s Stack[int]); index expressions are recorded unresolved and filtered by Project.Instantiations
+func (i *Inspector) inspectInstantiations(file *ast.File, filename string, importMap map[string]string) []*graph.Instantiation {
+	var result []*graph.Instantiation
+	for _, decl := range file.Decls {
+		typeParams := declTypeParams(decl)
+		ast.Inspect(decl, func(node ast.Node) bool {
+			var target ast.Expr
+			var indices []ast.Expr
+			switch actual := node.(type) {
+			case *ast.IndexExpr:
+				target, indices = actual.X, []ast.Expr{actual.Index}
+			case *ast.IndexListExpr:
+				target, indices = actual.X, actual.Indices
+			default:
+				return true
+			}
+			instance := &graph.Instantiation{File: filename, Line: i.fset.Position(node.Pos()).Line}
+			switch x := target.(type) {
+			case *ast.Ident:
+				if !declaresTypeOrFunc(x) {
+					return true // indexing a variable, e.g. items[i]
+				}
+				instance.Name = x.Name
+			case *ast.SelectorExpr:
+				pkg, ok := x.X.(*ast.Ident)
+				if !ok || importMap[pkg.Name] == "" {
+					return true
+				}
+				instance.Package, instance.Name = importMap[pkg.Name], x.Sel.Name
+			default:
+				return true
+			}
+			for _, index := range indices {
+				if !isTypeExpr(index, importMap) || referencesAny(index, typeParams) {
+					return true // value index or type parameters of the enclosing declaration, e.g. *Stack[T]
+				}
+				instance.TypeArgs = append(instance.TypeArgs, exprToString(index, importMap))
+			}
+			result = append(result, instance)
+			return true
+		})
+	}
+	return result
+}
+
+// declaresTypeOrFunc reports whether identifier may denote a type or function, identifiers declared in other
+// files of the package are not resolved by the parser
+func declaresTypeOrFunc(ident *ast.Ident) bool {
+	return ident.Obj == nil || ident.Obj.Kind == ast.Typ || ident.Obj.Kind == ast.Fun
+}
+
+// isTypeExpr reports whether expression may denote a type argument
+func isTypeExpr(expr ast.Expr, importMap map[string]string) bool {
+	switch actual := expr.(type) {
+	case *ast.Ident:
+		return declaresTypeOrFunc(actual)
+	case *ast.SelectorExpr:
+		pkg, ok := actual.X.(*ast.Ident)
+		return ok && importMap[pkg.Name] != ""
+	case *ast.StarExpr, *ast.ArrayType, *ast.MapType, *ast.ChanType, *ast.FuncType, *ast.InterfaceType, *ast.StructType, *ast.IndexExpr, *ast.IndexListExpr:
+		return true
+	}
+	return false
+}
+
+// declTypeParams returns type parameter names of a generic function, method receiver or type declaration
+func declTypeParams(decl ast.Decl) map[string]bool {
+	names := map[string]bool{}
+	addFields := func(fields *ast.FieldList) {
+		if fields == nil {
+			return
+		}
+		for _, field := range fields.List {
+			for _, name := range field.Names {
+				names[name.Name] = true
+			}
+		}
+	}
+	switch actual := decl.(type) {
+	case *ast.FuncDecl:
+		addFields(actual.Type.TypeParams)
+		if actual.Recv != nil && len(actual.Recv.List) > 0 {
+			// receiver type parameters, e.g. func (s *Stack[T])
+			ast.Inspect(actual.Recv.List[0].Type, func(node ast.Node) bool {
+				switch index := node.(type) {
+				case *ast.IndexExpr:
+					if ident, ok := index.Index.(*ast.Ident); ok {
+						names[ident.Name] = true
+					}
+				case *ast.IndexListExpr:
+					for _, expr := range index.Indices {
+						if ident, ok := expr.(*ast.Ident); ok {
+							names[ident.Name] = true
+						}
+					}
+				}
+				return true
+			})
+		}
+	case *ast.GenDecl:
+		for _, spec := range actual.Specs {
+			if typeSpec, ok := spec.(*ast.TypeSpec); ok {
+				addFields(typeSpec.TypeParams)
+			}
+		}
+	}
+	return names
+}
+
+// referencesAny reports whether expression references any of the given identifiers
+func referencesAny(expr ast.Expr, names map[string]bool) bool {
+	found := false
+	ast.Inspect(expr, func(node ast.Node) bool {
+		if ident, ok := node.(*ast.Ident); ok && names[ident.Name] {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
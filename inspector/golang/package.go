@@ -1,6 +1,7 @@
 package golang
 
 import (
+	"errors"
 	"fmt"
 	"github.com/viant/linager/inspector/graph"
 	"github.com/viant/linager/inspector/repository"
@@ -26,7 +27,7 @@ func (i *Inspector) InspectPackage(packagePath string) (*graph.Package, error) {
 	}
 
 	// Process the single package directory
-	pkgFiles, assets, err := i.inspectSinglePackage(absPath)
+	pkgFiles, assets, skipped, err := i.inspectSinglePackage(absPath)
 	if err != nil {
 		return nil, fmt.Errorf("error processing package in %s: %w", absPath, err)
 	}
@@ -35,10 +36,14 @@ func (i *Inspector) InspectPackage(packagePath string) (*graph.Package, error) {
 	if pkg.Name == "" && len(pkgFiles) > 0 {
 		pkg.Name = pkgFiles[0].Package
 	}
-	pkg.FileSet = pkgFiles
+	pkg.FileSet, pkg.Variants = i.applyBuildConstraints(pkgFiles)
+	i.markInterfaceFields([]*graph.Package{pkg})
+	pkg.LinkTagConstants()
+	pkg.LinkValueRefs()
 	pkg.Assets = assets
+	pkg.Skipped = skipped
 
-	if len(pkg.FileSet) == 0 {
+	if len(pkgFiles) == 0 && len(pkg.Skipped) == 0 {
 		return nil, fmt.Errorf("no Go files found in package: %s", packagePath)
 	}
 
@@ -56,7 +61,8 @@ func (i *Inspector) InspectPackages(rootPath string) ([]*graph.Package, error) {
 	var packags []*graph.Package
 
 	// Walk the directory tree to find all potential package directories
-	err = filepath.Walk(absPath, func(aPath string, fileInfo os.FileInfo, err error) error {
+	sources := repository.Sources(i.config)
+	err = sources.Walk(absPath, func(aPath string, fileInfo os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -70,7 +76,7 @@ func (i *Inspector) InspectPackages(rootPath string) ([]*graph.Package, error) {
 			exclusion = []string{"_test.go"}
 		}
 		// Check if directory has Go files
-		hasGoFiles, err := repository.HasFileWithSuffixes(aPath, []string{".go"}, exclusion)
+		hasGoFiles, err := repository.HasSourceWithSuffixes(i.config.Sources, aPath, []string{".go"}, exclusion)
 		if err != nil {
 			return err
 		}
@@ -87,73 +93,109 @@ func (i *Inspector) InspectPackages(rootPath string) ([]*graph.Package, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error walking package directories: %w", err)
 	}
+	i.markInterfaceFields(packags)
 	return packags, nil
 }
 
 // inspectSinglePackage processes a single directory as a Go package
-func (i *Inspector) inspectSinglePackage(packageDir string) ([]*graph.File, []*graph.Asset, error) {
+func (i *Inspector) inspectSinglePackage(packageDir string) ([]*graph.File, []*graph.Asset, []*graph.SkippedFile, error) {
 	var files []*graph.File
 	var assets []*graph.Asset
+	var skipped []*graph.SkippedFile
 
 	// Process Go files
-	pkgs, err := parser.ParseDir(i.fset, packageDir, func(info os.FileInfo) bool {
-		// Skip test files unless configured to include them
-		if i.config.SkipTests && strings.HasSuffix(info.Name(), "_test.go") {
-			return false
-		}
-		return true
-	}, parser.ParseComments)
+	sources := repository.Sources(i.config)
+	entries, err := sources.ReadDir(packageDir)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to parse package: %w", err)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil, nil, graph.NotFoundError("directory", packageDir, err)
+		}
+		return nil, nil, nil, fmt.Errorf("failed to read directory %s: %w", packageDir, err)
 	}
 
-	// Process each package (main, tests, etc.)
-	for _, pkg := range pkgs {
-		for filename, file := range pkg.Files {
-			// Read file content for method body extraction
-			src, err := os.ReadFile(filename)
-			if err != nil {
-				return nil, nil, fmt.Errorf("failed to read file %s: %w", filename, err)
-			}
-			i.src = src
+	// Constructors, constants and String() methods may complete types declared in other files
+	defaults := map[string]map[string]*fieldDefault{}
+	enums := map[string]*enumScan{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") {
+			continue
+		}
+		// Skip test files unless configured to include them
+		if i.config.SkipTests && strings.HasSuffix(entry.Name(), "_test.go") {
+			continue
+		}
+		filename := filepath.Join(packageDir, entry.Name())
+		// Skip oversized and binary files
+		skippedFile, err := i.config.CheckSource(i.config.Sources, filename)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if skippedFile != nil {
+			skipped = append(skipped, skippedFile)
+			continue
+		}
+		// Read file content for method body extraction
+		src, err := sources.ReadFile(filename)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to read file %s: %w", filename, graph.NotFoundError("file", filename, err))
+		}
+		file, err := parser.ParseFile(i.fset, filename, src, parser.ParseComments)
+		if err != nil {
+			return nil, nil, nil, parseError(packageDir, err)
+		}
+		i.src = src
 
-			aFile, err := i.processFile(file, filename)
-			if err != nil {
-				return nil, nil, fmt.Errorf("failed to process file %s: %w", filename, err)
-			}
-			files = append(files, aFile)
+		aFile, err := i.processFile(file, filename)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to process file %s: %w", filename, err)
 		}
+		files = append(files, aFile)
+		i.constructorDefaults(file, defaults)
+		i.scanEnums(file, enums)
+	}
+	for _, aFile := range files {
+		applyFieldDefaults(aFile.Types, defaults)
+		applyEnums(aFile.Types, enums)
 	}
 
 	// Process non-Go files as assets if AllFilesInFolder is enabled
 	if !i.config.SkipAsset {
-		assets, err = repository.ReadAssetsRecursively(packageDir, true, getImportPath, "go")
+		assets, err = repository.ScanSourceAssets(i.config.Sources, packageDir, true, getImportPath, i.config.EagerAssetSizeLimit(), "go")
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to read assets: %w", err)
+			return nil, nil, nil, fmt.Errorf("failed to read assets: %w", err)
 		}
 	}
-	return files, assets, nil
+	return files, assets, skipped, nil
 }
 
-// processParameters processes function parameters and extracts parameter information
-func (i *Inspector) processParameters(fields *ast.FieldList, importMap map[string]string) []graph.Parameter {
-	var result []graph.Parameter
+// processParameters processes function parameters or results, expanding multi-name declarations (a, b int) into one parameter per name
+func (i *Inspector) processParameters(fields *ast.FieldList, importMap map[string]string) []*graph.Parameter {
+	result := []*graph.Parameter{}
+	if fields == nil {
+		return result
+	}
 
 	for _, field := range fields.List {
-		paramType := exprToString(field.Type, importMap)
+		typeExpr, isVariadic := field.Type, false
+		if ellipsis, ok := typeExpr.(*ast.Ellipsis); ok {
+			typeExpr, isVariadic = ellipsis.Elt, true
+		}
+		paramType := exprToString(typeExpr, importMap)
 
 		if len(field.Names) > 0 {
 			for _, name := range field.Names {
-				result = append(result, graph.Parameter{
-					Name: name.Name,
-					Type: &graph.Type{Name: paramType},
+				result = append(result, &graph.Parameter{
+					Name:       name.Name,
+					Type:       &graph.Type{Name: paramType},
+					IsVariadic: isVariadic,
 				})
 			}
 		} else {
 			// Unnamed parameter
-			result = append(result, graph.Parameter{
-				Name: "",
-				Type: &graph.Type{Name: paramType},
+			result = append(result, &graph.Parameter{
+				Name:       "",
+				Type:       &graph.Type{Name: paramType},
+				IsVariadic: isVariadic,
 			})
 		}
 	}
@@ -1,6 +1,8 @@
 package golang
 
 import (
+	"context"
+
 	"github.com/viant/linager/inspector/graph"
 	"github.com/viant/linager/inspector/repository"
 )
@@ -8,13 +10,16 @@ import (
 // InspectProject parses a Go source file and extracts types
 func (i *Inspector) InspectProject(location string) (*graph.Project, error) {
 	detector := repository.New()
+	if i.config != nil && i.config.Sources != nil {
+		detector = repository.NewWithSources(i.config.Sources)
+	}
 	project := &graph.Project{}
-	if info, err := detector.DetectProject(location); err == nil {
+	if info, err := detector.DetectProject(context.Background(), location); err == nil {
 		project.Name = info.Name
 		project.Type = info.Type
 		project.RootPath = info.RootPath
 	}
-	if info, err := detector.DetectRepository(location); err == nil {
+	if info, err := detector.DetectRepository(context.Background(), location); err == nil {
 		project.RepositoryURL = info.Origin
 		if info.Root != "" {
 			location = info.Root
@@ -26,6 +31,16 @@ func (i *Inspector) InspectProject(location string) (*graph.Project, error) {
 		return nil, err
 	}
 	project.Init()
-
+	if i.config != nil && i.config.Sources != nil {
+		project.SetAssetLoader(repository.NewSourceLoader(i.config.Sources))
+	} else {
+		project.SetAssetLoader(graph.NewFSLoader(nil))
+	}
+	if i.config != nil && i.config.Classifier != nil {
+		project.Classify(i.config.Classifier)
+	}
+	if i.config != nil && i.config.Redactor != nil {
+		project.Redact(i.config.Redactor)
+	}
 	return project, nil
 }
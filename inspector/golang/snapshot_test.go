@@ -0,0 +1,16 @@
+package golang_test
+
+import (
+	"github.com/viant/linager/inspector/golang"
+	"github.com/viant/linager/inspector/graph"
+	"github.com/viant/linager/inspector/internal/testutil"
+	"testing"
+)
+
+// TestInspector_Snapshots compares inspected fixtures with stored snapshots, run with UPDATE_SNAPSHOTS=1 to regenerate
+func TestInspector_Snapshots(t *testing.T) {
+	snapshot := &testutil.Snapshot{Extensions: []string{".go"}, RedactLocations: true}
+	snapshot.Assert(t, func(path string) (*graph.File, error) {
+		return golang.NewInspector(&graph.Config{IncludeUnexported: true}).InspectFile(path)
+	}, "testdata", "edge/testdata")
+}
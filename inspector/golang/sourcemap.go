@@ -0,0 +1,66 @@
+package golang
+
+import (
+	"github.com/viant/linager/inspector/graph"
+	"go/ast"
+	"go/parser"
+	"go/token"
+)
+
+// EmitWithSourceMap emits a file and locates emitted types, fields, functions and constants in the (formatted) output;
+// the source map is nil when the output does not parse
+func (g *Emitter) EmitWithSourceMap(file *graph.File) (*graph.EmitResult, error) {
+	content, err := g.Emit(file)
+	if err != nil {
+		return nil, err
+	}
+	result := &graph.EmitResult{Content: content}
+	fset := token.NewFileSet()
+	parsed, err := parser.ParseFile(fset, file.Path, content, parser.SkipObjectResolution)
+	if err != nil {
+		return result, nil
+	}
+	sourceMap := graph.NewSourceMap(file.Path)
+	add := func(kind, name string, node ast.Node) {
+		sourceMap.Add(kind, name, content, fset.Position(node.Pos()).Offset, fset.Position(node.End()).Offset)
+	}
+	for _, decl := range parsed.Decls {
+		switch actual := decl.(type) {
+		case *ast.FuncDecl:
+			name := actual.Name.Name
+			if actual.Recv != nil && len(actual.Recv.List) > 0 {
+				if receiver := baseTypeIdent(actual.Recv.List[0].Type); receiver != "" {
+					name = receiver + "." + name
+				}
+			}
+			add(graph.SourceKindFunction, name, actual)
+		case *ast.GenDecl:
+			for _, spec := range actual.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					var node ast.Node = spec
+					if len(actual.Specs) == 1 {
+						node = actual // type Name ..., including the keyword
+					}
+					add(graph.SourceKindType, spec.Name.Name, node)
+					if structType, ok := spec.Type.(*ast.StructType); ok && structType.Fields != nil {
+						for _, field := range structType.Fields.List {
+							for _, name := range field.Names {
+								add(graph.SourceKindField, spec.Name.Name+"."+name.Name, field)
+							}
+						}
+					}
+				case *ast.ValueSpec:
+					if actual.Tok != token.CONST {
+						continue
+					}
+					for _, name := range spec.Names {
+						add(graph.SourceKindConstant, name.Name, spec)
+					}
+				}
+			}
+		}
+	}
+	result.SourceMap = sourceMap
+	return result, nil
+}
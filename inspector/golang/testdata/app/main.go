@@ -18,3 +18,13 @@ func main() {
 	// Call utility inspector to reflect on the stack
 	util.Inspect(s)
 }
+
+// Pair associates a value with a comparable key
+type Pair[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+func (p Pair[K, V]) String() string {
+	return fmt.Sprintf("%v=%v", p.Key, p.Value)
+}
@@ -0,0 +1,2 @@
+// Package stack provides a generic last-in, first-out stack. It backs the demo application.
+package stack
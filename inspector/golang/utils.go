@@ -1,14 +1,18 @@
 package golang
 
 import (
+	"errors"
 	"fmt"
 	"github.com/viant/linager/inspector/graph"
 	"go/ast"
+	"go/scanner"
 	"go/token"
+	"go/types"
 	"os"
 	"reflect"
 	"regexp"
 	"strings"
+	"unicode"
 )
 
 // formatFuncType formats a function type as a string
@@ -124,15 +128,16 @@ func kindFromString(kind string) reflect.Kind {
 }
 
 // extractBaseTypeName extracts the base type name from a type string
-// For example, for "*pkg.MyStruct[T]", it returns "MyStruct"
+// For example, for "*pkg.MyStruct[T]", it returns "MyStruct", for "(*pkg.List[pkg.Item[K]])" it returns "List"
 func extractBaseTypeName(typStr string) string {
-	// Remove pointer stars
-	typStr = strings.TrimLeft(typStr, "*")
+	// Remove pointer stars and parentheses, e.g. (*T)
+	typStr = strings.TrimLeft(strings.TrimSpace(typStr), "*( ")
 
-	// Remove generic parameters
+	// Remove generic parameters, nested and qualified type arguments included
 	if idx := strings.IndexByte(typStr, '['); idx >= 0 {
 		typStr = typStr[:idx]
 	}
+	typStr = strings.TrimRight(typStr, ") ")
 
 	// Extract the type name from qualified name
 	if idx := strings.LastIndexByte(typStr, '.'); idx >= 0 {
@@ -140,44 +145,27 @@ func extractBaseTypeName(typStr string) string {
 	}
 
 	// Validate that it's a valid identifier
-	if len(typStr) == 0 || !isValidIdent(typStr) {
+	if !isValidIdent(typStr) {
 		return ""
 	}
 
 	return typStr
 }
 
-// isValidIdent checks if a string is a valid Go identifier
+// isValidIdent checks if a string is a valid Go identifier: a letter or underscore followed by letters, digits or
+// underscores, letters and digits being Unicode letters and decimal digits as in the Go spec (e.g. типы, 数据)
 func isValidIdent(s string) bool {
 	if len(s) == 0 {
 		return false
 	}
-
-	// First character must be a letter or underscore
-	if !isLetter(rune(s[0])) && s[0] != '_' {
-		return false
-	}
-
-	// Remaining characters must be letters, digits, or underscores
-	for i := 1; i < len(s); i++ {
-		if !isLetter(rune(s[i])) && !isDigit(rune(s[i])) && s[i] != '_' {
+	for i, r := range s {
+		if !unicode.IsLetter(r) && r != '_' && (i == 0 || !unicode.IsDigit(r)) {
 			return false
 		}
 	}
-
 	return true
 }
 
-// isLetter checks if a rune is a letter
-func isLetter(r rune) bool {
-	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
-}
-
-// isDigit checks if a rune is a digit
-func isDigit(r rune) bool {
-	return r >= '0' && r <= '9'
-}
-
 // extractTypeParams extracts type parameters from an ast.FieldList
 func extractTypeParams(params *ast.FieldList, importMap map[string]string) []*graph.TypeParam {
 	if params == nil {
@@ -198,6 +186,27 @@ func extractTypeParams(params *ast.FieldList, importMap map[string]string) []*gr
 	return result
 }
 
+// receiverTypeParams returns type parameters declared by a generic receiver such as *Stack[K, V]
+func receiverTypeParams(expr ast.Expr) []*graph.TypeParam {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	var indices []ast.Expr
+	switch t := expr.(type) {
+	case *ast.IndexExpr:
+		indices = []ast.Expr{t.Index}
+	case *ast.IndexListExpr:
+		indices = t.Indices
+	}
+	var result []*graph.TypeParam
+	for _, index := range indices {
+		if ident, ok := index.(*ast.Ident); ok {
+			result = append(result, &graph.TypeParam{Name: ident.Name})
+		}
+	}
+	return result
+}
+
 // isExportedType checks if a type expression represents an exported type
 func isExportedType(expr ast.Expr) bool {
 	switch t := expr.(type) {
@@ -491,3 +500,87 @@ func hasGoFilesInDir(dirPath string, skipTests bool) (bool, error) {
 
 	return false, nil
 }
+
+// extractReferences collects symbols called or instantiated within a function body, method calls on variables,
+// parameters and receivers (e.g. p.Parse) are skipped so that their qualifiers are not taken for imports
+func extractReferences(body *ast.BlockStmt) []string {
+	if body == nil {
+		return nil
+	}
+	var refs []string
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch e := n.(type) {
+		case *ast.CallExpr:
+			switch fn := e.Fun.(type) {
+			case *ast.Ident:
+				if types.Universe.Lookup(fn.Name) == nil {
+					refs = append(refs, fn.Name)
+				}
+			case *ast.SelectorExpr:
+				if x, ok := fn.X.(*ast.Ident); ok && (x.Obj == nil || x.Obj.Kind != ast.Var) {
+					refs = append(refs, x.Name+"."+fn.Sel.Name)
+				}
+			}
+		case *ast.CompositeLit:
+			switch t := e.Type.(type) {
+			case *ast.Ident, *ast.SelectorExpr:
+				refs = append(refs, exprToString(t, nil))
+			}
+		}
+		return true
+	})
+	return refs
+}
+
+// cyclomaticComplexity computes McCabe complexity: one plus a decision point per branch, loop, case and boolean operator
+func cyclomaticComplexity(body *ast.BlockStmt) int {
+	if body == nil {
+		return 0
+	}
+	complexity := 1
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch e := n.(type) {
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			complexity++
+		case *ast.CaseClause:
+			if e.List != nil {
+				complexity++
+			}
+		case *ast.CommClause:
+			if e.Comm != nil {
+				complexity++
+			}
+		case *ast.BinaryExpr:
+			if e.Op == token.LAND || e.Op == token.LOR {
+				complexity++
+			}
+		}
+		return true
+	})
+	return complexity
+}
+
+// parseError converts a go/parser error into graph.ErrParse
+func parseError(path string, err error) error {
+	parseErr := &graph.ErrParse{Path: path, Cause: err}
+	var list scanner.ErrorList
+	if errors.As(err, &list) && len(list) > 0 {
+		parseErr.Line = list[0].Pos.Line
+	}
+	return parseErr
+}
+
+// buildTags returns the //go:build constraint expression declared before the package clause
+func buildTags(file *ast.File) string {
+	for _, group := range file.Comments {
+		if group.Pos() >= file.Package {
+			break
+		}
+		for _, comment := range group.List {
+			if expr, ok := strings.CutPrefix(comment.Text, "//go:build "); ok {
+				return strings.TrimSpace(expr)
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,65 @@
+package golang
+
+import (
+	"go/ast"
+	"go/parser"
+	"strings"
+	"testing"
+)
+
+// FuzzExtractBaseTypeName checks base type names of type strings and of parsed type expressions printed by
+// exprToString: no input panics, named types (optionally qualified, pointed to or instantiated) keep their name and
+// printed expressions parse back to the same string
+func FuzzExtractBaseTypeName(f *testing.F) {
+	for _, seed := range []string{
+		"MyStruct", "*pkg.MyStruct[T, U]", "pkg.List[pkg.Item]", "*Tree[Pair[K, V]]", "(*Cache[K, V])",
+		"*Graph[N, E, map[N][]E]", "Set[T comparable]", "*orm.Repo[*model.User, int64]", "*типы.Узел[T]", "数据",
+		"[]T", "map[string]int", "chan<- T", "func(int) error", "1Invalid", "*", "[", "(",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, typStr string) {
+		_ = extractBaseTypeName(typStr)
+		expr, err := parser.ParseExpr(typStr)
+		if err != nil {
+			return
+		}
+		printed := exprToString(expr, nil)
+		if name := namedTypeName(expr); name != "" {
+			if got := extractBaseTypeName(printed); got != name {
+				t.Fatalf("extractBaseTypeName(%q) = %q, want %q", printed, got, name)
+			}
+		}
+		if strings.Contains(printed, "<*ast.") || strings.Contains(printed, "{...}") || strings.Contains(printed, "interface{}") {
+			return // expressions exprToString abbreviates
+		}
+		reparsed, err := parser.ParseExpr(printed)
+		if err != nil {
+			t.Fatalf("exprToString(%q) = %q does not parse: %v", typStr, printed, err)
+		}
+		if again := exprToString(reparsed, nil); again != printed {
+			t.Fatalf("exprToString round trip of %q: %q != %q", typStr, again, printed)
+		}
+	})
+}
+
+// namedTypeName returns the name of a named type expression, e.g. List for *pkg.List[T], empty for other expressions
+func namedTypeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.SelectorExpr:
+		if _, ok := t.X.(*ast.Ident); ok {
+			return t.Sel.Name
+		}
+	case *ast.StarExpr:
+		return namedTypeName(t.X)
+	case *ast.ParenExpr:
+		return namedTypeName(t.X)
+	case *ast.IndexExpr:
+		return namedTypeName(t.X)
+	case *ast.IndexListExpr:
+		return namedTypeName(t.X)
+	}
+	return ""
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/viant/linager/inspector/graph"
 	"go/ast"
 	"go/token"
+	"strconv"
 	"strings"
 )
 
@@ -55,19 +56,26 @@ func (i *Inspector) InspectVariables(file *ast.File, importMap map[string]string
 						Name: typeName,
 						Kind: kind,
 					}
+					if structType, ok := valueSpec.Type.(*ast.StructType); ok {
+						varType = i.anonymousType(structType, importMap, name.Name)
+					}
 				}
 
 				// Extract value as string
 				var value string
+				var references []*graph.ValueRef
 				if idx < len(valueSpec.Values) {
 					value = extractValueAsString(valueSpec.Values[idx], i.fset)
+					references = valueRefs(valueSpec.Values[idx], "", importMap, nil)
 				}
 
 				variables = append(variables, &graph.Variable{
-					Name:    name.Name,
-					Comment: varComment,
-					Value:   value,
-					Type:    varType,
+					Name:       name.Name,
+					Comment:    varComment,
+					Value:      value,
+					Type:       varType,
+					IsExported: name.IsExported(),
+					References: references,
 				})
 			}
 		}
@@ -75,3 +83,58 @@ func (i *Inspector) InspectVariables(file *ast.File, importMap map[string]string
 
 	return variables, nil
 }
+
+// valueRefs collects identifier and selector elements of composite literals, nested literals included, as candidate
+// function values resolved by graph.Project.LinkValueRefs; elements of string keyed maps carry the key
+func valueRefs(expr ast.Expr, key string, importMap map[string]string, refs []*graph.ValueRef) []*graph.ValueRef {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return valueRefs(e.X, key, importMap, refs)
+	case *ast.UnaryExpr:
+		if e.Op == token.AND {
+			return valueRefs(e.X, key, importMap, refs)
+		}
+	case *ast.CompositeLit:
+		for _, elt := range e.Elts {
+			elementKey := key
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				if lit, ok := kv.Key.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+					elementKey, _ = strconv.Unquote(lit.Value)
+				}
+				elt = kv.Value
+			}
+			if ref := valueRef(elt, importMap); ref != nil {
+				ref.Key = elementKey
+				refs = append(refs, ref)
+				continue
+			}
+			refs = valueRefs(elt, elementKey, importMap, refs)
+		}
+	}
+	return refs
+}
+
+// valueRef returns a candidate function value of a literal element: a function name, an imported function or a
+// method expression, e.g. createHandler, users.Create or (*Service).Create
+func valueRef(expr ast.Expr, importMap map[string]string) *graph.ValueRef {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		switch e.Name {
+		case "nil", "true", "false", "iota", "_":
+			return nil
+		}
+		return &graph.ValueRef{Expr: e.Name}
+	case *ast.SelectorExpr:
+		switch x := e.X.(type) {
+		case *ast.Ident:
+			return &graph.ValueRef{Expr: x.Name + "." + e.Sel.Name, Package: importMap[x.Name]}
+		case *ast.ParenExpr:
+			if star, ok := x.X.(*ast.StarExpr); ok {
+				if ident, ok := star.X.(*ast.Ident); ok {
+					return &graph.ValueRef{Expr: "(*" + ident.Name + ")." + e.Sel.Name}
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package graph
+
+import "strings"
+
+// AnonymousSuffix ends names of synthetic types modeling inline struct declarations
+const AnonymousSuffix = "$anon"
+
+// AnonymousTypeName returns the synthetic type name of an inline struct declared by a field or variable path,
+// e.g. Outer.Config$anon for field Config of type Outer
+func AnonymousTypeName(path string) string {
+	return path + AnonymousSuffix
+}
+
+// InlineDeclaration returns the single line declaration of an anonymous type, e.g. struct { Host string; Port int }
+func (t *Type) InlineDeclaration() string {
+	if len(t.Fields) == 0 {
+		return "struct{}"
+	}
+	var fields []string
+	for _, field := range t.Fields {
+		var declaration string
+		if !field.IsEmbedded {
+			declaration = field.Name + " "
+		}
+		if field.Type != nil {
+			declaration += TypeName(field.Type)
+		}
+		if field.Tag != "" {
+			declaration += " `" + string(field.Tag) + "`"
+		}
+		fields = append(fields, declaration)
+	}
+	return "struct { " + strings.Join(fields, "; ") + " }"
+}
+
+// anonymousNote lists nested objects of a type declared by inline struct fields, anonymous types are documented with
+// their owner rather than as separate documents
+func anonymousNote(aType *Type) string {
+	builder := &strings.Builder{}
+	for _, field := range aType.Fields {
+		if field.Type == nil || !field.Type.Anonymous {
+			continue
+		}
+		if builder.Len() == 0 {
+			builder.WriteString("\n// Nested objects:\n")
+		}
+		builder.WriteString("// " + field.Name + " " + field.Type.InlineDeclaration() + "\n")
+	}
+	return builder.String()
+}
@@ -0,0 +1,105 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"github.com/viant/afs"
+	"os"
+)
+
+// DefaultEagerAssetSize is the default maximum size of an asset loaded at inspection time
+const DefaultEagerAssetSize = 16 * 1024
+
+// AssetLoader loads asset content on demand
+type AssetLoader interface {
+	Load(ctx context.Context, location string) ([]byte, error)
+}
+
+// FSLoader loads asset content with an afs service
+type FSLoader struct {
+	fs afs.Service
+}
+
+// Load downloads content of an asset location
+func (l *FSLoader) Load(ctx context.Context, location string) ([]byte, error) {
+	content, err := l.fs.DownloadWithURL(ctx, location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load asset %s: %w", location, NotFoundError("asset", location, err))
+	}
+	return content, nil
+}
+
+// NewFSLoader creates an afs backed asset loader, nil fs uses afs.New()
+func NewFSLoader(fs afs.Service) *FSLoader {
+	if fs == nil {
+		fs = afs.New()
+	}
+	return &FSLoader{fs: fs}
+}
+
+// EagerAssetSizeLimit returns the maximum size of an asset loaded at inspection time, 0 when all assets load lazily
+func (c *Config) EagerAssetSizeLimit() int64 {
+	switch {
+	case c == nil || c.EagerAssetSize == 0:
+		return DefaultEagerAssetSize
+	case c.EagerAssetSize < 0:
+		return 0
+	}
+	return c.EagerAssetSize
+}
+
+// NewAsset creates an asset of a local file, content of files up to eagerSize bytes is read immediately,
+// larger files are only stat-ed and their content is loaded on demand with loader
+func NewAsset(path, importPath string, eagerSize int64, loader AssetLoader) (*Asset, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, NotFoundError("asset", path, err)
+	}
+	asset := &Asset{Path: path, ImportPath: importPath, Size: info.Size(), loader: loader}
+	if info.Size() > eagerSize {
+		return asset, nil
+	}
+	if asset.Content, err = os.ReadFile(path); err != nil {
+		return nil, NotFoundError("asset", path, err)
+	}
+	asset.Size = int64(len(asset.Content))
+	asset.Hash, err = Hash(asset.Content)
+	return asset, err
+}
+
+// SetLoader sets the loader of lazily loaded content
+func (a *Asset) SetLoader(loader AssetLoader) {
+	a.loader = loader
+}
+
+// Load returns asset content, lazily loaded content is read with the asset loader (see Project.SetAssetLoader)
+// each time and is not retained
+func (a *Asset) Load(ctx context.Context) ([]byte, error) {
+	if a.Content != nil {
+		return a.Content, nil
+	}
+	if a.Size == 0 {
+		return nil, nil
+	}
+	loader := a.loader
+	if loader == nil {
+		loader = NewFSLoader(nil)
+	}
+	content, err := loader.Load(ctx, a.Path)
+	if err != nil {
+		return nil, err
+	}
+	if a.Hash, err = Hash(content); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+// SetAssetLoader attaches loader to all project assets
+func (p *Project) SetAssetLoader(loader AssetLoader) {
+	for _, pkg := range p.Packages {
+		for _, asset := range pkg.Assets {
+			asset.loader = loader
+		}
+	}
+}
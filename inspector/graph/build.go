@@ -0,0 +1,60 @@
+package graph
+
+import "path/filepath"
+
+// BuildVariant lists package files built for a platform
+type BuildVariant struct {
+	Name   string   // Platform, e.g. linux/amd64
+	GOOS   string   // Target operating system
+	GOARCH string   // Target architecture
+	Files  []string // Base names of files built in this variant
+}
+
+// BuildConstrained reports whether Go build constraints are evaluated, i.e. a build target, tags or multi-variant
+// mode are configured; otherwise all files are inspected
+func (c *Config) BuildConstrained() bool {
+	return c != nil && (c.GOOS != "" || c.GOARCH != "" || len(c.BuildTags) > 0 || c.MultiVariant)
+}
+
+// LookupVariant returns a package build variant by name
+func (p *Package) LookupVariant(name string) *BuildVariant {
+	for _, variant := range p.Variants {
+		if variant.Name == name {
+			return variant
+		}
+	}
+	return nil
+}
+
+// Variant returns a copy of the package limited to files of a build variant, packages without variants are returned as is;
+// use it to generate documents or emit sources of a chosen platform
+func (p *Package) Variant(name string) *Package {
+	if len(p.Variants) == 0 {
+		return p
+	}
+	ret := &Package{Name: p.Name, ImportPath: p.ImportPath, Assets: p.Assets, Owners: p.Owners, Skipped: p.Skipped}
+	variant := p.LookupVariant(name)
+	if variant == nil {
+		return ret
+	}
+	ret.Variants = []*BuildVariant{variant}
+	files := map[string]bool{}
+	for _, name := range variant.Files {
+		files[name] = true
+	}
+	for _, file := range p.FileSet {
+		if files[filepath.Base(file.Path)] {
+			ret.FileSet = append(ret.FileSet, file)
+		}
+	}
+	return ret
+}
+
+// Variant returns a copy of the project with packages limited to files of a build variant
+func (p *Project) Variant(name string) *Project {
+	ret := &Project{Name: p.Name, Type: p.Type, RootPath: p.RootPath, RepositoryURL: p.RepositoryURL, SkippedFiles: p.SkippedFiles}
+	for _, pkg := range p.Packages {
+		ret.Packages = append(ret.Packages, pkg.Variant(name))
+	}
+	return ret
+}
@@ -0,0 +1,47 @@
+package graph
+
+import "sort"
+
+// CallGraph returns the project call graph: functions and methods mapped to the project functions and methods their
+// bodies reference, resolved within their package or through file imports as by BuildEditContext, and packages
+// (import path keys) mapped to functions referenced by values of their variables, e.g. handler registries, see
+// Project.LinkValueRefs
+func (p *Project) CallGraph() CallGraph {
+	index := newEditIndex(p)
+	result := CallGraph{}
+	seen := map[string]bool{}
+	add := func(caller, callee string) {
+		if edge := caller + "\x00" + callee; !seen[edge] {
+			seen[edge] = true
+			result[caller] = append(result[caller], callee)
+		}
+	}
+	for _, symbol := range index.symbols {
+		switch {
+		case symbol.function != nil:
+			for _, callee := range index.references(symbol) {
+				if callee.function != nil {
+					add(symbol.key, callee.key)
+				}
+			}
+		case symbol.variable != nil:
+			for _, ref := range symbol.variable.References {
+				if ref.Ref != nil {
+					add(symbol.pkg.Ref().Package, callKey(*ref.Ref))
+				}
+			}
+		}
+	}
+	for _, callees := range result {
+		sort.Strings(callees)
+	}
+	return result
+}
+
+// callKey returns the call graph key of a function reference, e.g. myapp/stack.Stack.Push
+func callKey(ref Ref) string {
+	if ref.Type != "" {
+		return ref.Package + "." + ref.Type + "." + ref.Function
+	}
+	return ref.Package + "." + ref.Function
+}
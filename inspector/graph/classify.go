@@ -0,0 +1,176 @@
+package graph
+
+import (
+	"reflect"
+	"regexp"
+	"sort"
+)
+
+// Sensitivity labels used by the default classification rules
+const (
+	LabelPII       = "pii"
+	LabelFinancial = "financial"
+	LabelSecret    = "secret"
+	LabelPublic    = "public"
+)
+
+// ClassifyContext describes a field or identifier being classified
+type ClassifyContext struct {
+	Package    string
+	Owner      string // Declaring type name, empty for variables
+	Name       string
+	Type       string
+	Tag        reflect.StructTag
+	Annotation string // Java annotations or JS decorators, e.g. @PII
+}
+
+// Classifier assigns sensitivity labels to fields and identifiers
+type Classifier interface {
+	Classify(ctx *ClassifyContext) []string
+}
+
+// ClassificationRule labels matches of a name, type, tag value or annotation pattern; unset patterns are ignored
+type ClassificationRule struct {
+	Label      string
+	Name       *regexp.Regexp
+	Type       *regexp.Regexp
+	Tag        string         // Tag key, e.g. pii or classification
+	TagValue   *regexp.Regexp // Tag value pattern, any value when nil
+	Annotation *regexp.Regexp
+}
+
+// matches reports whether any configured rule pattern matches the context
+func (r *ClassificationRule) matches(ctx *ClassifyContext) bool {
+	if r.Name != nil && r.Name.MatchString(ctx.Name) {
+		return true
+	}
+	if r.Type != nil && ctx.Type != "" && r.Type.MatchString(ctx.Type) {
+		return true
+	}
+	if r.Tag != "" {
+		if value, ok := ctx.Tag.Lookup(r.Tag); ok && (r.TagValue == nil || r.TagValue.MatchString(value)) {
+			return true
+		}
+	}
+	return r.Annotation != nil && ctx.Annotation != "" && r.Annotation.MatchString(ctx.Annotation)
+}
+
+// RuleClassifier classifies with configurable pattern rules
+type RuleClassifier struct {
+	Rules []*ClassificationRule
+}
+
+// Classify returns sorted distinct labels of all matching rules
+func (c *RuleClassifier) Classify(ctx *ClassifyContext) []string {
+	var labels []string
+	for _, rule := range c.Rules {
+		if rule.matches(ctx) {
+			labels = AddLabels(labels, rule.Label)
+		}
+	}
+	return labels
+}
+
+// NewRuleClassifier creates a rule classifier, default rules are used when none are given
+func NewRuleClassifier(rules ...*ClassificationRule) *RuleClassifier {
+	if len(rules) == 0 {
+		rules = DefaultClassificationRules()
+	}
+	return &RuleClassifier{Rules: rules}
+}
+
+// DefaultClassificationRules returns rules matching common personal, financial and secret fields
+func DefaultClassificationRules() []*ClassificationRule {
+	return []*ClassificationRule{
+		{
+			Label:      LabelPII,
+			Name:       regexp.MustCompile(`(?i)ssn|email|phone|birth|address|passport|firstname|lastname|fullname`),
+			Tag:        "pii",
+			Annotation: regexp.MustCompile(`@(PII|Pii|PersonalData|Sensitive)\b`),
+		},
+		{
+			Label:      LabelFinancial,
+			Name:       regexp.MustCompile(`(?i)iban|card(number|no)|creditcard|account(number|no)|routing|salary`),
+			Tag:        "classification",
+			TagValue:   regexp.MustCompile(`(?i)^financial$`),
+			Annotation: regexp.MustCompile(`@Financial\b`),
+		},
+		{
+			Label: LabelSecret,
+			Name:  regexp.MustCompile(`(?i)password|secret|token|apikey|credential`),
+		},
+		{
+			Label:      LabelPublic,
+			Tag:        "classification",
+			TagValue:   regexp.MustCompile(`(?i)^public$`),
+			Annotation: regexp.MustCompile(`@Public\b`),
+		},
+	}
+}
+
+// AddLabels merges labels into a sorted distinct label list
+func AddLabels(labels []string, added ...string) []string {
+	changed := false
+	for _, label := range added {
+		if label == "" || containsLabel(labels, label) {
+			continue
+		}
+		labels = append(labels, label)
+		changed = true
+	}
+	if changed {
+		sort.Strings(labels)
+	}
+	return labels
+}
+
+func containsLabel(labels []string, label string) bool {
+	for _, candidate := range labels {
+		if candidate == label {
+			return true
+		}
+	}
+	return false
+}
+
+// Classify sets labels of all type fields in the project
+func (p *Project) Classify(classifier Classifier) {
+	for _, pkg := range p.Packages {
+		for _, file := range pkg.FileSet {
+			for _, aType := range file.Types {
+				for _, field := range aType.Fields {
+					ctx := &ClassifyContext{Package: pkg.ImportPath, Owner: aType.Name, Name: field.Name, Tag: field.Tag, Annotation: field.Annotation}
+					if field.Type != nil {
+						ctx.Type = field.Type.Name
+					}
+					field.Labels = classifier.Classify(ctx)
+				}
+			}
+		}
+	}
+}
+
+// LabelCounts returns number of labeled fields per label
+func (p *Package) LabelCounts() map[string]int {
+	counts := map[string]int{}
+	for _, file := range p.FileSet {
+		for _, aType := range file.Types {
+			for _, field := range aType.Fields {
+				for _, label := range field.Labels {
+					counts[label]++
+				}
+			}
+		}
+	}
+	return counts
+}
+
+// IsSensitive reports whether labels include anything but public
+func IsSensitive(labels []string) bool {
+	for _, label := range labels {
+		if label != LabelPublic {
+			return true
+		}
+	}
+	return false
+}
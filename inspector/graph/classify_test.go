@@ -0,0 +1,49 @@
+package graph_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/linager/inspector/graph"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestRuleClassifier_Classify(t *testing.T) {
+	var testCases = []struct {
+		description string
+		rules       []*graph.ClassificationRule
+		ctx         *graph.ClassifyContext
+		expect      []string
+	}{
+		{description: "name", ctx: &graph.ClassifyContext{Name: "HomePhone"}, expect: []string{graph.LabelPII}},
+		{description: "tag value", ctx: &graph.ClassifyContext{Name: "Balance", Tag: reflect.StructTag(`classification:"financial"`)}, expect: []string{graph.LabelFinancial}},
+		{description: "java annotation", ctx: &graph.ClassifyContext{Name: "holder", Annotation: "@PII\n@Column(name = \"holder\")"}, expect: []string{graph.LabelPII}},
+		{description: "multiple labels", ctx: &graph.ClassifyContext{Name: "emailToken"}, expect: []string{graph.LabelPII, graph.LabelSecret}},
+		{description: "no match", ctx: &graph.ClassifyContext{Name: "Count", Type: "int"}, expect: nil},
+		{
+			description: "custom rules",
+			rules:       []*graph.ClassificationRule{{Label: "health", Name: regexp.MustCompile(`(?i)diagnosis`)}},
+			ctx:         &graph.ClassifyContext{Name: "Diagnosis"},
+			expect:      []string{"health"},
+		},
+	}
+	for _, testCase := range testCases {
+		classifier := graph.NewRuleClassifier(testCase.rules...)
+		assert.Equal(t, testCase.expect, classifier.Classify(testCase.ctx), testCase.description)
+	}
+}
+
+func TestProject_Classify(t *testing.T) {
+	aType := &graph.Type{Name: "Account", Fields: []*graph.Field{
+		{Name: "Email", Type: &graph.Type{Name: "string"}},
+		{Name: "Iban", Type: &graph.Type{Name: "string"}},
+		{Name: "ID", Type: &graph.Type{Name: "int"}},
+	}}
+	pkg := &graph.Package{Name: "model", FileSet: []*graph.File{{Types: []*graph.Type{aType}}}}
+	project := &graph.Project{Packages: []*graph.Package{pkg}}
+	project.Classify(graph.NewRuleClassifier())
+	assert.Equal(t, []string{graph.LabelPII}, aType.Fields[0].Labels)
+	assert.Equal(t, []string{graph.LabelFinancial}, aType.Fields[1].Labels)
+	assert.Nil(t, aType.Fields[2].Labels)
+	assert.Equal(t, map[string]int{graph.LabelPII: 1, graph.LabelFinancial: 1}, pkg.LabelCounts())
+}
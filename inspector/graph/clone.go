@@ -0,0 +1,276 @@
+package graph
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"sort"
+)
+
+// copyOnWrite tracks packages and files a project shares with the projects it was cloned from or to, see Project.Clone
+type copyOnWrite struct {
+	origin      *Project              // Project the clone was taken from, nil for an original
+	originEpoch int                   // Origin epoch at Clone, origin paths changed since then are dirty for the clone
+	epoch       int                   // Incremented by Clone, dirty paths record the epoch they changed in
+	packages    map[*Package]*Package // Owned copies by shared package, owned packages map to themselves
+	files       map[*File]*File       // Owned copies by shared file, owned files map to themselves
+	dirty       map[string]int        // Changed file paths with the epoch they changed in
+}
+
+// Clone returns a copy-on-write clone of the project for what-if edits: packages, files and types stay shared with the
+// project until Coder (or MutablePackage, MutableFile) changes them, then only the changed file and its package header
+// are copied on the side making the change, both for the clone and the original. Untouched files keep their identity
+// in both projects, changed files are tracked as dirty, see Dirty and DiffProjects. Changes made directly to shared
+// packages or files, e.g. by reinspecting the original, are visible to both projects.
+func (p *Project) Clone() *Project {
+	if p.cow == nil {
+		p.cow = &copyOnWrite{}
+	}
+	// everything owned so far is shared with the clone from now on
+	p.cow.packages, p.cow.files = nil, nil
+	p.cow.epoch++
+	return &Project{
+		Name:          p.Name,
+		Type:          p.Type,
+		RootPath:      p.RootPath,
+		RepositoryURL: p.RepositoryURL,
+		Packages:      slices.Clone(p.Packages),
+		SkippedFiles:  slices.Clip(p.SkippedFiles),
+		Modules:       slices.Clip(p.Modules),
+		Tables:        slices.Clip(p.Tables),
+		packageMap:    maps.Clone(p.packageMap),
+		redactor:      p.redactor,
+		documents:     p.documents,
+		cow:           &copyOnWrite{origin: p, originEpoch: p.cow.epoch},
+	}
+}
+
+// MutablePackage returns the project package safe to change: a copy of the package header (files shared) replacing
+// a package shared with a clone, the package itself otherwise
+func (p *Project) MutablePackage(pkg *Package) *Package {
+	if p.cow == nil || pkg == nil {
+		return pkg
+	}
+	if owned, ok := p.cow.packages[pkg]; ok {
+		return owned
+	}
+	idx := slices.Index(p.Packages, pkg)
+	if idx == -1 {
+		return pkg
+	}
+	copied := *pkg
+	copied.FileSet = slices.Clone(pkg.FileSet)
+	copied.Assets = slices.Clip(pkg.Assets)
+	copied.Owners = slices.Clip(pkg.Owners)
+	copied.Skipped = slices.Clip(pkg.Skipped)
+	copied.Variants = slices.Clip(pkg.Variants)
+	copied.Annotations = slices.Clip(pkg.Annotations)
+	copied.assetMap, copied.fileMap = maps.Clone(pkg.assetMap), maps.Clone(pkg.fileMap)
+	if pkg.typeMap != nil {
+		copied.typeMap = make(map[string][]int, len(pkg.typeMap))
+		for name, files := range pkg.typeMap {
+			copied.typeMap[name] = slices.Clone(files)
+		}
+	}
+	p.Packages[idx] = &copied
+	own(&p.cow.packages, pkg, &copied)
+	return &copied
+}
+
+// MutableFile returns the package file safe to change: a deep copy replacing a file shared with a clone, the file
+// itself otherwise; the file is marked dirty. Types referenced by the file's declarations are copied with the file.
+func (p *Project) MutableFile(pkg *Package, file *File) *File {
+	if p.cow == nil || file == nil {
+		return file
+	}
+	if owned, ok := p.cow.files[file]; ok {
+		p.MarkDirty(owned.Path)
+		return owned
+	}
+	pkg = p.MutablePackage(pkg)
+	idx := slices.Index(pkg.FileSet, file)
+	if idx == -1 {
+		return file
+	}
+	copied := copyFile(file)
+	pkg.FileSet[idx] = copied
+	own(&p.cow.files, file, copied)
+	p.MarkDirty(copied.Path)
+	return copied
+}
+
+// AddPackage adds a new package owned by the project, its files are marked dirty
+func (p *Project) AddPackage(pkg *Package) {
+	p.Packages = append(p.Packages, pkg)
+	if p.packageMap != nil {
+		if _, ok := p.packageMap[pkg.Name]; !ok {
+			p.packageMap[pkg.Name] = len(p.Packages) - 1
+		}
+	}
+	if p.cow == nil {
+		return
+	}
+	own(&p.cow.packages, pkg, pkg)
+	for _, file := range pkg.FileSet {
+		own(&p.cow.files, file, file)
+		p.MarkDirty(file.Path)
+	}
+}
+
+// AddFile adds a new file owned by the project to a package, the file is marked dirty
+func (p *Project) AddFile(pkg *Package, file *File) {
+	p.MutablePackage(pkg).AddFile(file)
+	if p.cow == nil {
+		return
+	}
+	own(&p.cow.files, file, file)
+	p.MarkDirty(file.Path)
+}
+
+// MarkDirty records changed file paths of a project taking part in a Clone, e.g. of created or deleted files;
+// it is a no-op for projects never cloned
+func (p *Project) MarkDirty(paths ...string) {
+	if p.cow == nil {
+		return
+	}
+	if p.cow.dirty == nil {
+		p.cow.dirty = map[string]int{}
+	}
+	for _, path := range paths {
+		p.cow.dirty[path] = p.cow.epoch
+	}
+}
+
+// Dirty returns sorted paths of files changed since the project was cloned or first cloned from
+func (p *Project) Dirty() []string {
+	if p.cow == nil {
+		return nil
+	}
+	result := make([]string, 0, len(p.cow.dirty))
+	for path := range p.cow.dirty {
+		result = append(result, path)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// changedPaths returns paths of files changed in either project since one was cloned from the other, nil when they
+// are not related by Clone
+func changedPaths(prev, next *Project) map[string]bool {
+	clone, origin := next, prev
+	if clone == nil || clone.cow == nil || clone.cow.origin != origin {
+		clone, origin = prev, next
+	}
+	if clone == nil || clone.cow == nil || origin == nil || clone.cow.origin != origin {
+		return nil
+	}
+	result := map[string]bool{}
+	for path := range clone.cow.dirty {
+		result[path] = true
+	}
+	for path, epoch := range origin.cow.dirty {
+		if epoch >= clone.cow.originEpoch {
+			result[path] = true
+		}
+	}
+	return result
+}
+
+// own records an owned copy of a shared element, the copy maps to itself
+func own[T comparable](owned *map[T]T, shared, copied T) {
+	if *owned == nil {
+		*owned = map[T]T{}
+	}
+	(*owned)[shared], (*owned)[copied] = copied, copied
+}
+
+// CopyFile returns a deep copy of a file with cloned lookup indexes, e.g. to restore the file state later
+func CopyFile(file *File) *File {
+	return copyFile(file)
+}
+
+// copyFile deep copies a file: exported fields are copied recursively keeping pointer sharing within the file,
+// lookup indexes are cloned and the recorded source is shared
+func copyFile(file *File) *File {
+	copier := &graphCopier{copies: map[copyKey]reflect.Value{}}
+	result := copier.copy(reflect.ValueOf(file)).Interface().(*File)
+	for _, value := range copier.copies {
+		switch copied := value.Interface().(type) {
+		case *File:
+			copied.functionMap, copied.variableMap = maps.Clone(copied.functionMap), maps.Clone(copied.variableMap)
+			copied.constantMap, copied.typeMap = maps.Clone(copied.constantMap), maps.Clone(copied.typeMap)
+			copied.summary = nil
+		case *Type:
+			copied.fieldMap, copied.methodMap = maps.Clone(copied.fieldMap), maps.Clone(copied.methodMap)
+		}
+	}
+	return result
+}
+
+// copyKey identifies a copied pointer, a struct and its first field share the address
+type copyKey struct {
+	addr uintptr
+	typ  reflect.Type
+}
+
+// graphCopier deep copies values reachable through exported fields, unexported fields are copied shallow
+type graphCopier struct {
+	copies map[copyKey]reflect.Value
+}
+
+func (c *graphCopier) copy(value reflect.Value) reflect.Value {
+	switch value.Kind() {
+	case reflect.Pointer:
+		if value.IsNil() {
+			return value
+		}
+		key := copyKey{addr: value.Pointer(), typ: value.Type()}
+		if copied, ok := c.copies[key]; ok {
+			return copied
+		}
+		copied := reflect.New(value.Type().Elem())
+		c.copies[key] = copied
+		copied.Elem().Set(c.copy(value.Elem()))
+		return copied
+	case reflect.Struct:
+		copied := reflect.New(value.Type()).Elem()
+		copied.Set(value)
+		for i := 0; i < value.NumField(); i++ {
+			if value.Type().Field(i).IsExported() {
+				copied.Field(i).Set(c.copy(value.Field(i)))
+			}
+		}
+		return copied
+	case reflect.Slice:
+		if value.IsNil() {
+			return value
+		}
+		copied := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		if kind := value.Type().Elem().Kind(); kind <= reflect.Complex128 || kind == reflect.String {
+			reflect.Copy(copied, value)
+			return copied
+		}
+		for i := 0; i < value.Len(); i++ {
+			copied.Index(i).Set(c.copy(value.Index(i)))
+		}
+		return copied
+	case reflect.Map:
+		if value.IsNil() {
+			return value
+		}
+		copied := reflect.MakeMapWithSize(value.Type(), value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			copied.SetMapIndex(iter.Key(), c.copy(iter.Value()))
+		}
+		return copied
+	case reflect.Interface:
+		if value.IsNil() {
+			return value
+		}
+		copied := reflect.New(value.Type()).Elem()
+		copied.Set(c.copy(value.Elem()))
+		return copied
+	}
+	return value
+}
@@ -1,10 +1,28 @@
 package graph
 
+import "time"
+
 type Config struct {
 	IncludeUnexported bool
 	SkipTests         bool
 	RecursivePackages bool
-	SkipAsset         bool //
+	SkipAsset         bool           //
+	RetainTrees       bool           // Retain parsed tree-sitter trees for custom queries, released on inspector Close
+	MaxFileSize       int64          // Maximum source file size in bytes, 0 uses DefaultMaxFileSize, negative disables the limit
+	EagerAssetSize    int64          // Maximum size of an asset loaded at inspection time, 0 uses DefaultEagerAssetSize, negative loads all assets lazily
+	Classifier        Classifier     // Classifier labeling type fields of inspected projects, e.g. NewRuleClassifier()
+	Redactor          *Redactor      // Redactor replacing secrets in inspected projects and their documents, e.g. NewRedactor()
+	BuildTags         []string       // Go build tags satisfied by build constraints, e.g. integration
+	GOOS              string         // Target operating system of Go build constraints, runtime.GOOS when empty
+	GOARCH            string         // Target architecture of Go build constraints, runtime.GOARCH when empty
+	MultiVariant      bool           // Group Go files into per platform build variants instead of skipping non-matching files
+	Variants          []string       // Platforms (GOOS or GOOS/GOARCH) of multi-variant mode, derived from package files when empty
+	FollowSymlinks    bool           // Follow symlinked directories and files in project walks, symlinks are skipped otherwise
+	ParseTimeout      time.Duration  // Maximum tree-sitter parse time per file, 0 uses DefaultParseTimeout, negative disables the deadline
+	MaxNodes          int            // Maximum tree-sitter nodes visited by traversals of a file, 0 uses DefaultMaxNodes, negative disables the limit
+	Sources           SourceProvider // Provider of inspected sources, e.g. repository.NewGitObjectProvider, local files when nil
+	Diagnostics       *Diagnostics   // Collector of inspection diagnostics, e.g. NewDiagnostics(SeverityDebug), discarded when nil
+	ParseCoverage     bool           // Account declarations against modeled graph elements in File.Coverage, see Project.ParseCoverage
 }
 
 func DefaultConfig() *Config {
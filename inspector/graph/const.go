@@ -5,7 +5,8 @@ type Constant struct {
 	Comment    string
 	Value      string
 	Type       *Type     // Type of the constant if specified
-	File       *File     // File where this constant is defined
+	File       *File     `json:"-"` // File where this constant is defined
 	IsExported bool      // Whether the constant is exported (public) or not
 	Location   *Location // Location of the constant in the source code
+	UsageRefs  []string  `json:",omitempty"` // Lineage data point IDs resolved to the constant, see linage.AttachGraph
 }
@@ -0,0 +1,163 @@
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Coverage kinds of source declarations
+const (
+	CoverageTypes     = "types"
+	CoverageFunctions = "functions" // functions and methods
+	CoverageFields    = "fields"
+	CoverageConstants = "constants"
+)
+
+// CoverageCount counts declarations encountered in source and the ones modeled by graph elements
+type CoverageCount struct {
+	Declared int `json:"declared"`
+	Modeled  int `json:"modeled"`
+}
+
+// Ratio returns the modeled share of declarations, 1 when nothing was declared
+func (c *CoverageCount) Ratio() float64 {
+	if c == nil || c.Declared == 0 {
+		return 1
+	}
+	return float64(c.Modeled) / float64(c.Declared)
+}
+
+// UnhandledNode describes a source construct the inspector did not model
+type UnhandledNode struct {
+	Node   string `json:"node"`            // Syntax node type, e.g. BinaryExpr or static_initializer
+	Owner  string `json:"owner,omitempty"` // Enclosing declaration, e.g. a type name
+	Path   string `json:"path,omitempty"`
+	Line   int    `json:"line,omitempty"`   // 1-based line, 0 if unknown
+	Column int    `json:"column,omitempty"` // 1-based column, 0 if unknown
+}
+
+// String returns the unhandled node as path:line:column: node (owner)
+func (u *UnhandledNode) String() string {
+	location := u.Path
+	if u.Line > 0 {
+		location = fmt.Sprintf("%s:%d:%d", u.Path, u.Line, u.Column)
+	}
+	if u.Owner == "" {
+		return location + ": " + u.Node
+	}
+	return fmt.Sprintf("%s: %s (%s)", location, u.Node, u.Owner)
+}
+
+// ParseCoverage accounts declarations of a file or project against the graph elements an inspector produced,
+// collected when graph.Config.ParseCoverage is set
+type ParseCoverage struct {
+	Kinds     map[string]*CoverageCount `json:"kinds,omitempty"`
+	Unhandled []*UnhandledNode          `json:"unhandled,omitempty"`
+}
+
+// NewParseCoverage creates an empty coverage
+func NewParseCoverage() *ParseCoverage {
+	return &ParseCoverage{Kinds: map[string]*CoverageCount{}}
+}
+
+// Count records a declaration of a kind and whether it was modeled
+func (c *ParseCoverage) Count(kind string, modeled bool) {
+	count := c.kind(kind)
+	count.Declared++
+	if modeled {
+		count.Modeled++
+	}
+}
+
+// Unhandle records a source construct the inspector did not model
+func (c *ParseCoverage) Unhandle(node *UnhandledNode) {
+	c.Unhandled = append(c.Unhandled, node)
+}
+
+// Kind returns the count of a declaration kind, an empty count when none was declared
+func (c *ParseCoverage) Kind(kind string) *CoverageCount {
+	if count, ok := c.Kinds[kind]; ok {
+		return count
+	}
+	return &CoverageCount{}
+}
+
+// Total returns counts of all declaration kinds
+func (c *ParseCoverage) Total() *CoverageCount {
+	total := &CoverageCount{}
+	for _, count := range c.Kinds {
+		total.Declared += count.Declared
+		total.Modeled += count.Modeled
+	}
+	return total
+}
+
+// Ratio returns the modeled share of all declarations, 1 when nothing was declared
+func (c *ParseCoverage) Ratio() float64 {
+	return c.Total().Ratio()
+}
+
+// Merge adds counts and unhandled nodes of another coverage
+func (c *ParseCoverage) Merge(other *ParseCoverage) {
+	if other == nil {
+		return
+	}
+	for kind, count := range other.Kinds {
+		target := c.kind(kind)
+		target.Declared += count.Declared
+		target.Modeled += count.Modeled
+	}
+	c.Unhandled = append(c.Unhandled, other.Unhandled...)
+}
+
+// String returns a one line summary, e.g. 97.5% modeled (types 10/10, functions 29/30)
+func (c *ParseCoverage) String() string {
+	kinds := make([]string, 0, len(c.Kinds))
+	for kind := range c.Kinds {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	var counts []string
+	for _, kind := range kinds {
+		counts = append(counts, fmt.Sprintf("%s %d/%d", kind, c.Kinds[kind].Modeled, c.Kinds[kind].Declared))
+	}
+	return fmt.Sprintf("%.1f%% modeled (%s), %d unhandled", c.Ratio()*100, strings.Join(counts, ", "), len(c.Unhandled))
+}
+
+func (c *ParseCoverage) kind(kind string) *CoverageCount {
+	if c.Kinds == nil {
+		c.Kinds = map[string]*CoverageCount{}
+	}
+	count, ok := c.Kinds[kind]
+	if !ok {
+		count = &CoverageCount{}
+		c.Kinds[kind] = count
+	}
+	return count
+}
+
+// ParseCoverage aggregates coverage of inspected files, unhandled node paths are relative to the project root;
+// nil when no file recorded coverage
+func (p *Project) ParseCoverage() *ParseCoverage {
+	var ret *ParseCoverage
+	for _, pkg := range p.Packages {
+		for _, file := range pkg.FileSet {
+			if file.Coverage == nil {
+				continue
+			}
+			if ret == nil {
+				ret = NewParseCoverage()
+			}
+			ret.Merge(file.Coverage)
+		}
+	}
+	if ret != nil {
+		for i, node := range ret.Unhandled {
+			copied := *node
+			copied.Path = p.relativePath(node.Path)
+			ret.Unhandled[i] = &copied
+		}
+	}
+	return ret
+}
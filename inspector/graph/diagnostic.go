@@ -0,0 +1,147 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Severity of a diagnostic, lower severities are more important
+type Severity int
+
+// Diagnostic severities
+const (
+	SeverityWarning Severity = iota // source inspected or analyzed partially
+	SeverityInfo                    // noteworthy inspection or analysis decisions
+	SeverityDebug                   // internal details of inspection and analysis
+)
+
+// String returns the severity name
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "info"
+	}
+	return "debug"
+}
+
+// MarshalText encodes the severity name
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Diagnostic is a structured record reported by inspectors and analyzers instead of printed output
+type Diagnostic struct {
+	Severity Severity `json:"severity"`
+	Code     string   `json:"code"` // Stable record kind, e.g. jsx.component
+	Message  string   `json:"message"`
+	File     string   `json:"file,omitempty"`
+	Line     int      `json:"line,omitempty"`   // 1-based line, 0 if unknown
+	Column   int      `json:"column,omitempty"` // 1-based column, 0 if unknown
+}
+
+// String returns the diagnostic as file:line:column: severity [code] message
+func (d *Diagnostic) String() string {
+	location := d.File
+	if d.Line > 0 {
+		location = fmt.Sprintf("%s:%d:%d", d.File, d.Line, d.Column)
+	}
+	return fmt.Sprintf("%s: %s [%s] %s", location, d.Severity, d.Code, d.Message)
+}
+
+// DiagnosticLogger receives diagnostics as they are reported, e.g. an adapter of an application logger
+type DiagnosticLogger interface {
+	Log(diagnostic *Diagnostic)
+}
+
+// DiagnosticLoggerFunc adapts a function to DiagnosticLogger
+type DiagnosticLoggerFunc func(diagnostic *Diagnostic)
+
+// Log passes a diagnostic to the function
+func (f DiagnosticLoggerFunc) Log(diagnostic *Diagnostic) {
+	f(diagnostic)
+}
+
+// Diagnostics collects diagnostics up to a verbosity (e.g. graph.Config.Diagnostics), optionally bridging them to a
+// logger; it is safe for concurrent use and a nil collector discards all diagnostics
+type Diagnostics struct {
+	Verbosity Severity         // Least important severity collected, warnings only when zero
+	Logger    DiagnosticLogger // Logger receiving collected diagnostics, optional
+	mux       sync.Mutex
+	records   []*Diagnostic
+}
+
+// NewDiagnostics creates a collector of diagnostics up to a verbosity
+func NewDiagnostics(verbosity Severity) *Diagnostics {
+	return &Diagnostics{Verbosity: verbosity}
+}
+
+// Enabled reports whether diagnostics of a severity are collected
+func (d *Diagnostics) Enabled(severity Severity) bool {
+	return d != nil && severity <= d.Verbosity
+}
+
+// Add collects a diagnostic enabled by the verbosity
+func (d *Diagnostics) Add(diagnostic *Diagnostic) {
+	if !d.Enabled(diagnostic.Severity) {
+		return
+	}
+	d.mux.Lock()
+	d.records = append(d.records, diagnostic)
+	d.mux.Unlock()
+	if d.Logger != nil {
+		d.Logger.Log(diagnostic)
+	}
+}
+
+// Warnf collects a warning
+func (d *Diagnostics) Warnf(code, file string, line, column int, format string, args ...interface{}) {
+	d.addf(SeverityWarning, code, file, line, column, format, args...)
+}
+
+// Infof collects an info record
+func (d *Diagnostics) Infof(code, file string, line, column int, format string, args ...interface{}) {
+	d.addf(SeverityInfo, code, file, line, column, format, args...)
+}
+
+// Debugf collects a debug record, the message is not formatted unless debug records are collected
+func (d *Diagnostics) Debugf(code, file string, line, column int, format string, args ...interface{}) {
+	d.addf(SeverityDebug, code, file, line, column, format, args...)
+}
+
+// AddParseWarning collects a warning of a partially inspected or analyzed file
+func (d *Diagnostics) AddParseWarning(warning *ParseWarning) {
+	if warning == nil {
+		return
+	}
+	d.Add(&Diagnostic{Severity: SeverityWarning, Code: "parse." + strings.ReplaceAll(warning.Reason, " ", "-"), Message: warning.Message, File: warning.Path})
+}
+
+func (d *Diagnostics) addf(severity Severity, code, file string, line, column int, format string, args ...interface{}) {
+	if !d.Enabled(severity) {
+		return
+	}
+	d.Add(&Diagnostic{Severity: severity, Code: code, Message: fmt.Sprintf(format, args...), File: file, Line: line, Column: column})
+}
+
+// Records returns collected diagnostics in reporting order
+func (d *Diagnostics) Records() []*Diagnostic {
+	if d == nil {
+		return nil
+	}
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	return append([]*Diagnostic(nil), d.records...)
+}
+
+// Reset discards collected diagnostics
+func (d *Diagnostics) Reset() {
+	if d == nil {
+		return
+	}
+	d.mux.Lock()
+	d.records = nil
+	d.mux.Unlock()
+}
@@ -0,0 +1,228 @@
+package graph
+
+import (
+	"sort"
+	"strings"
+)
+
+// Function change kinds
+const (
+	ChangeMoved   = "moved"   // Same function declared in another file
+	ChangeRenamed = "renamed" // Function with matching body declared under another name
+)
+
+// DefaultRenameThreshold is the minimum body similarity of near matches reported as renamed or moved
+const DefaultRenameThreshold = 0.8
+
+// minRenameTokens is the minimum body size of near matches, trivial bodies (e.g. getters) look alike once identifiers are normalized
+const minRenameTokens = 12
+
+// FunctionRef identifies a function or method of a project
+type FunctionRef struct {
+	Path     string `json:"path"` // Canonical path: import path, receiver type and name, e.g. example.com/app.Server.Run
+	File     string `json:"file"`
+	Package  string `json:"package"`
+	Type     string `json:"type,omitempty"`
+	Name     string `json:"name"`
+	function *Function
+	relative string // file path relative to the project root
+}
+
+// FunctionChange matches a removed function with an added one
+type FunctionChange struct {
+	Kind       string       `json:"kind"` // ChangeMoved or ChangeRenamed
+	Old        *FunctionRef `json:"old"`
+	New        *FunctionRef `json:"new"`
+	Confidence float64      `json:"confidence"` // 1 for identical normalized bodies, token similarity otherwise
+}
+
+// ProjectDiff lists functions and methods added, removed, moved or renamed between two project versions
+type ProjectDiff struct {
+	Added   []*FunctionRef    `json:"added,omitempty"`
+	Removed []*FunctionRef    `json:"removed,omitempty"`
+	Moved   []*FunctionChange `json:"moved,omitempty"`
+	Renamed []*FunctionChange `json:"renamed,omitempty"`
+}
+
+// Retargets returns new canonical paths of moved and renamed functions keyed by old canonical path
+func (d *ProjectDiff) Retargets() map[string]*FunctionChange {
+	result := map[string]*FunctionChange{}
+	for _, changes := range [][]*FunctionChange{d.Moved, d.Renamed} {
+		for _, change := range changes {
+			result[change.Old.Path] = change
+		}
+	}
+	return result
+}
+
+// DiffProjects compares functions and methods of two project versions; functions keeping their canonical path in
+// another file are moved, removed and added functions are matched by normalized body hash (ignoring whitespace and
+// comments) or by token similarity of at least DefaultRenameThreshold. Projects related by Project.Clone only compare
+// files changed since cloning.
+func DiffProjects(prev, next *Project) *ProjectDiff {
+	return DiffProjectsWithThreshold(prev, next, DefaultRenameThreshold)
+}
+
+// DiffProjectsWithThreshold compares two project versions with a custom minimum similarity of near matches
+func DiffProjectsWithThreshold(prev, next *Project, threshold float64) *ProjectDiff {
+	diff := &ProjectDiff{}
+	changed := changedPaths(prev, next)
+	prevRefs, nextRefs := functionRefs(prev, changed), functionRefs(next, changed)
+	nextByPath := map[string]*FunctionRef{}
+	for _, ref := range nextRefs {
+		nextByPath[ref.Path] = ref
+	}
+	prevByPath := map[string]*FunctionRef{}
+	var removed []*FunctionRef
+	for _, ref := range prevRefs {
+		prevByPath[ref.Path] = ref
+		counterpart, ok := nextByPath[ref.Path]
+		switch {
+		case !ok:
+			removed = append(removed, ref)
+		case counterpart.relative != ref.relative:
+			diff.Moved = append(diff.Moved, &FunctionChange{Kind: ChangeMoved, Old: ref, New: counterpart, Confidence: 1})
+		}
+	}
+	var added []*FunctionRef
+	for _, ref := range nextRefs {
+		if _, ok := prevByPath[ref.Path]; !ok {
+			added = append(added, ref)
+		}
+	}
+
+	matched := map[*FunctionRef]bool{}
+	var unmatched []*FunctionRef
+	for _, ref := range removed {
+		var best *FunctionRef
+		confidence := 0.0
+		hash := functionHash(ref.function)
+		for _, candidate := range added {
+			if matched[candidate] {
+				continue
+			}
+			if hash != 0 && hash == functionHash(candidate.function) {
+				best, confidence = candidate, 1
+				break
+			}
+			if score := bodySimilarity(ref.function, candidate.function); score >= threshold && score > confidence {
+				best, confidence = candidate, score
+			}
+		}
+		if best == nil {
+			unmatched = append(unmatched, ref)
+			continue
+		}
+		matched[best] = true
+		change := &FunctionChange{Kind: ChangeRenamed, Old: ref, New: best, Confidence: confidence}
+		if ref.Name == best.Name && ref.Type == best.Type {
+			change.Kind = ChangeMoved // e.g. moved to another package
+			diff.Moved = append(diff.Moved, change)
+			continue
+		}
+		diff.Renamed = append(diff.Renamed, change)
+	}
+	diff.Removed = unmatched
+	for _, ref := range added {
+		if !matched[ref] {
+			diff.Added = append(diff.Added, ref)
+		}
+	}
+	return diff
+}
+
+// functionRefs returns functions and methods of a project sorted by canonical path, restricted to files of paths
+// unless nil
+func functionRefs(project *Project, paths map[string]bool) []*FunctionRef {
+	if project == nil {
+		return nil
+	}
+	var result []*FunctionRef
+	for _, pkg := range project.Packages {
+		for _, file := range pkg.FileSet {
+			if paths != nil && !paths[file.Path] {
+				continue
+			}
+			importPath := CanonicalImportPath(project, pkg, file)
+			relative := file.Path
+			if project.RootPath != "" && strings.HasPrefix(relative, project.RootPath) {
+				relative = strings.TrimPrefix(strings.TrimPrefix(relative, project.RootPath), "/")
+			}
+			add := func(typeName string, function *Function) {
+				path := importPath + "." + function.Name
+				if typeName != "" {
+					path = importPath + "." + typeName + "." + function.Name
+				}
+				result = append(result, &FunctionRef{Path: path, File: file.Path, Package: pkg.Name, Type: typeName, Name: function.Name, function: function, relative: relative})
+			}
+			for _, function := range file.Functions {
+				add(strings.TrimPrefix(function.Receiver, "*"), function)
+			}
+			for _, aType := range file.Types {
+				for _, method := range aType.Methods {
+					add(aType.Name, method)
+				}
+			}
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Path < result[j].Path })
+	return result
+}
+
+// CanonicalImportPath returns the import path of a file, directory based import paths are rewritten relative to the
+// project root and prefixed with the project name so that versions checked out in different locations compare equal
+func CanonicalImportPath(project *Project, pkg *Package, file *File) string {
+	importPath := file.ImportPath
+	if importPath == "" || (file.Name != "" && strings.HasSuffix(importPath, "/"+file.Name)) {
+		importPath = pkg.ImportPath // e.g. the Go inspector records file paths
+	}
+	if project.RootPath == "" || !strings.HasPrefix(importPath, project.RootPath) {
+		return importPath
+	}
+	relative := strings.Trim(strings.TrimPrefix(importPath, project.RootPath), "/")
+	switch {
+	case project.Name == "":
+		return relative
+	case relative == "":
+		return project.Name
+	}
+	return project.Name + "/" + relative
+}
+
+// functionHash returns the normalized body hash of a function, 0 when the body is unknown
+func functionHash(function *Function) int32 {
+	if function.Hash != 0 {
+		return function.Hash
+	}
+	if function.Body == nil {
+		return 0
+	}
+	return BodyHash(function.Body.Text)
+}
+
+// bodySimilarity returns the token shingle similarity of two function bodies with identifiers normalized (renamed
+// locals do not count as changes), 0 when a body is unknown or too short to compare
+func bodySimilarity(a, b *Function) float64 {
+	if a.Body == nil || b.Body == nil {
+		return 0
+	}
+	const shingleSize = 3
+	aTokens, bTokens := tokenize(a.Body.Text, true), tokenize(b.Body.Text, true)
+	if len(aTokens) < minRenameTokens || len(bTokens) < minRenameTokens {
+		return 0
+	}
+	return jaccard(shingles(aTokens, shingleSize), shingles(bTokens, shingleSize))
+}
+
+// BodyHash returns a hash of function body tokens, whitespace and comments do not affect the hash
+func BodyHash(body string) int32 {
+	tokens := tokenize(body, false)
+	if len(tokens) == 0 {
+		return 0
+	}
+	hash, err := Hash([]byte(strings.Join(tokens, " ")))
+	if err != nil {
+		return 0
+	}
+	return int32(hash)
+}
@@ -2,12 +2,18 @@ package graph
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"sort"
 	"strings"
 )
 
 const chunkSize = 8192 - 256
 
+// maxAssetDocumentSize is the maximum size of an asset represented as a document
+const maxAssetDocumentSize = 16 * 1024
+
 // DocumentKind indicates the type of code element that the document represents
 type DocumentKind string
 
@@ -22,8 +28,24 @@ const (
 	KindAsset      DocumentKind = "Asset" // Package-level information
 	KindCode       DocumentKind = "Code"  // Package-level information
 
+	KindPackageSummary DocumentKind = "PackageSummary" // Compact package overview, see Project.PackageSummary
+
+)
+
+// Synthesized document sections, generated from the project graph rather than copied from source
+const (
+	SectionPromotedMethods = "promotedMethods"
+	SectionSatisfies       = "satisfies"
 )
 
+// DocumentOptions controls generated content of project documents, the zero value includes all synthesized sections
+type DocumentOptions struct {
+	SkipPromotedMethods bool // Omit methods promoted from embedded types in type documents
+	SkipSatisfies       bool // Omit interfaces implemented by types in type documents
+	PackageSummaries    bool // Emit a KindPackageSummary document per package
+	SummaryBudget       int  // Token budget of package summaries, DefaultSummaryBudget when not set
+}
+
 // Document represents a code element with its metadata for vector embedding
 type Document struct {
 	ID        string       `json:"id"`        // Unique identifier for the document
@@ -37,6 +59,12 @@ type Document struct {
 	Signature string       `json:"signature"` //Signature
 	Content   string       `json:"content"`   // Full content of the element including comments, annotations, etc.
 	Part      int          `json:"part"`      // Part number for large documents
+
+	Synthesized []string `json:"synthesized,omitempty"` // Generated content sections, e.g. SectionPromotedMethods
+
+	References []string `json:"references,omitempty"` // Symbols referenced by the content (called functions, used types)
+	Imports    []string `json:"imports,omitempty"`    // Import paths the content depends on
+	Owners     []string `json:"owners,omitempty"`     // Code owners of the file or package
 }
 
 type Documents []*Document
@@ -94,6 +122,11 @@ func SplitDocument(doc *Document) Documents {
 			Content:   content[start:end],
 			Part:      i + 1,
 			Hash:      doc.HashContent(),
+
+			References:  doc.References,
+			Imports:     doc.Imports,
+			Owners:      doc.Owners,
+			Synthesized: doc.Synthesized,
 		}
 		docs.Append(chunk)
 		start = end
@@ -107,6 +140,15 @@ func (d *Document) Size() int {
 	if d.Kind == KindType {
 		size += len(d.Name)
 	}
+	for _, ref := range d.References {
+		size += len(ref)
+	}
+	for _, imp := range d.Imports {
+		size += len(imp)
+	}
+	for _, owner := range d.Owners {
+		size += len(owner)
+	}
 	return size + 20 //keys in meta
 }
 
@@ -253,6 +295,8 @@ outer:
 	return result
 }
 
+// GetID returns the document ID, kind:path:name; functions and methods are identified by their signature so that
+// overloads stay distinct, methods are also qualified by their type, e.g. Method:Repo.java:Repo.void save(User user):
 func (d *Document) GetID() string {
 	if d.ID != "" {
 		return d.ID
@@ -262,6 +306,10 @@ func (d *Document) GetID() string {
 	builder.WriteString(":")
 	builder.WriteString(d.Path)
 	builder.WriteString(":")
+	if d.Kind == KindTypeMethod && d.Type != "" {
+		builder.WriteString(d.Type)
+		builder.WriteString(".")
+	}
 	if d.Signature != "" {
 		builder.WriteString(d.Signature)
 	} else {
@@ -272,17 +320,91 @@ func (d *Document) GetID() string {
 	return d.ID
 }
 
+// TokenCounter counts tokens of a text
+type TokenCounter func(text string) int
+
+// DocumentTokenCounter counts tokens of document content, e.g. when packing edit context budgets (see
+// BuildEditContext); replace it to match the tokenizer of the consuming model
+var DocumentTokenCounter TokenCounter = EstimateTokens
+
+// EstimateTokens approximates the token count of a text as one token per four bytes
+func EstimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// Tokens returns the number of content tokens counted by DocumentTokenCounter
+func (d *Document) Tokens() int {
+	return DocumentTokenCounter(d.Content)
+}
+
 // HashContent generates content hash
 func (d *Document) HashContent() uint64 {
-	hash, _ := Hash([]byte(d.Content))
+	content := d.Content
+	if len(d.Synthesized) > 0 {
+		content += "\x00synthesized:" + strings.Join(d.Synthesized, ",") // generated sections never hash as source text
+	}
+	hash, _ := Hash([]byte(content))
 	return hash
 }
 
+// SetDocumentOptions sets options of documents created by the project, see CreateDocumentsStream
+func (p *Project) SetDocumentOptions(opts DocumentOptions) {
+	p.documents = opts
+}
+
 // CreateDocuments creates Document instances for embedding from a project
 func (p *Project) CreateDocuments(ctx context.Context, pkgPath string) (Documents, error) {
 	var documents Documents
+	err := p.CreateDocumentsStream(ctx, pkgPath, func(doc *Document) error {
+		documents = append(documents, doc)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return documents, nil
+}
+
+// WriteJSONL streams project documents to w as JSON lines without materializing them
+func (p *Project) WriteJSONL(ctx context.Context, pkgPath string, w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	return p.CreateDocumentsStream(ctx, pkgPath, func(doc *Document) error {
+		return encoder.Encode(doc)
+	})
+}
+
+// WriteJSONL writes documents to w as JSON lines
+func (d Documents) WriteJSONL(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	for _, doc := range d {
+		if err := encoder.Encode(doc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateDocumentsStream creates Document instances for embedding from a project and passes them to fn as they are produced,
+// large documents are split into chunks the same way as with Documents.Append; an error returned by fn aborts the walk
+func (p *Project) CreateDocumentsStream(ctx context.Context, pkgPath string, fn func(*Document) error) error {
+	emit := func(doc *Document) error {
+		p.redactDocument(doc)
+		if len(doc.Content) <= chunkSize {
+			return fn(doc)
+		}
+		for _, chunk := range SplitDocument(doc) {
+			if err := fn(chunk); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
 
+	var instantiations map[string][]*Instantiation
 	for _, pkg := range p.Packages {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 
 		if len(pkg.Assets) > 0 {
 			candidatePath := pkg.Assets[0].Path
@@ -290,7 +412,14 @@ func (p *Project) CreateDocuments(ctx context.Context, pkgPath string) (Document
 				continue // Skip packages that don't match the specified package path
 			}
 			for _, asset := range pkg.Assets {
-				if len(asset.Content) > 16*1024 { //for not skipping, needs to split
+				if asset.Size > maxAssetDocumentSize {
+					continue
+				}
+				content, err := asset.Load(ctx)
+				if err != nil {
+					return err
+				}
+				if len(content) > maxAssetDocumentSize { //for not skipping, needs to split
 					continue
 				}
 				methodDoc := &Document{
@@ -299,10 +428,13 @@ func (p *Project) CreateDocuments(ctx context.Context, pkgPath string) (Document
 					Package: pkg.Name,
 					Name:    asset.Name,
 					Path:    asset.Path,
-					Content: string(asset.Content),
+					Owners:  pkg.Owners,
+					Content: string(content),
 				}
 				methodDoc.Hash = methodDoc.HashContent()
-				documents.Append(methodDoc)
+				if err := emit(methodDoc); err != nil {
+					return err
+				}
 
 			}
 		}
@@ -317,7 +449,13 @@ func (p *Project) CreateDocuments(ctx context.Context, pkgPath string) (Document
 		}
 
 		var typeFields = map[string]int{}
+		if instantiations == nil {
+			instantiations = p.Instantiations()
+		}
 		for _, file := range pkg.FileSet {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
 			// Process constants
 			for _, constant := range file.Constants {
 				content := ""
@@ -331,10 +469,13 @@ func (p *Project) CreateDocuments(ctx context.Context, pkgPath string) (Document
 					Package: pkg.Name,
 					Name:    constant.Name,
 					Path:    file.Path,
+					Owners:  file.Owners,
 					Content: content,
 				}
 				doc.Hash = doc.HashContent()
-				documents.Append(doc)
+				if err := emit(doc); err != nil {
+					return err
+				}
 			}
 
 			// Process variables
@@ -356,10 +497,13 @@ func (p *Project) CreateDocuments(ctx context.Context, pkgPath string) (Document
 					Name:    variable.Name,
 					Type:    typeName,
 					Path:    file.Path,
+					Owners:  file.Owners,
 					Content: content,
 				}
 				doc.Hash = doc.HashContent()
-				documents.Append(doc)
+				if err := emit(doc); err != nil {
+					return err
+				}
 			}
 
 			// Process file functions (without receiver)
@@ -370,12 +514,16 @@ func (p *Project) CreateDocuments(ctx context.Context, pkgPath string) (Document
 						Project:   p.Name,
 						Package:   pkg.Name,
 						Path:      file.Path,
+						Owners:    file.Owners,
 						Signature: function.Signature,
 						Name:      function.Name,
 						Content:   function.Content(),
 					}
+					doc.References, doc.Imports = functionDependencies(file, function)
 					doc.Hash = doc.HashContent()
-					documents.Append(doc)
+					if err := emit(doc); err != nil {
+						return err
+					}
 				}
 			}
 
@@ -383,19 +531,24 @@ func (p *Project) CreateDocuments(ctx context.Context, pkgPath string) (Document
 			for _, aType := range file.Types {
 				typeFields[aType.Name] = +len(aType.Fields)
 
-				if len(aType.Fields) > 0 {
+				if len(aType.Fields) > 0 && !aType.Anonymous {
 					// Pure type (type declaration)
-					content := aType.Content()
+					content := aType.Content() + instantiationNote(instantiations[pkg.ImportPath+"."+aType.Name]) + enumNote(aType.Enum) + anonymousNote(aType)
+					synthesized, sections := p.synthesizedNotes(pkg, aType)
 					doc := &Document{
-						Kind:    KindType,
-						Project: p.Name,
-						Package: pkg.Name,
-						Path:    file.Path,
-						Name:    aType.Name,
-						Content: content,
+						Kind:        KindType,
+						Project:     p.Name,
+						Package:     pkg.Name,
+						Path:        file.Path,
+						Owners:      file.Owners,
+						Name:        aType.Name,
+						Content:     content + synthesized,
+						Synthesized: sections,
 					}
 					doc.Hash = doc.HashContent()
-					documents.Append(doc)
+					if err := emit(doc); err != nil {
+						return err
+					}
 				}
 				// Type fields
 				if len(aType.Fields) > 0 {
@@ -410,11 +563,14 @@ func (p *Project) CreateDocuments(ctx context.Context, pkgPath string) (Document
 								Package: pkg.Name,
 								Name:    field.Name,
 								Path:    file.Path,
+								Owners:  file.Owners,
 								Type:    aType.Name,
 								Content: fieldContent,
 							}
 							fieldDoc.Hash = fieldDoc.HashContent()
-							documents.Append(fieldDoc)
+							if err := emit(fieldDoc); err != nil {
+								return err
+							}
 						}
 					}
 				}
@@ -426,12 +582,17 @@ func (p *Project) CreateDocuments(ctx context.Context, pkgPath string) (Document
 						Project:   p.Name,
 						Package:   pkg.Name,
 						Path:      file.Path,
+						Owners:    file.Owners,
 						Type:      aType.Name,
+						Name:      method.Name,
 						Signature: method.Signature,
 						Content:   method.Content(),
 					}
+					methodDoc.References, methodDoc.Imports = functionDependencies(file, method)
 					methodDoc.Hash = methodDoc.HashContent()
-					documents.Append(methodDoc)
+					if err := emit(methodDoc); err != nil {
+						return err
+					}
 				}
 			}
 			for typeName, count := range typeFields {
@@ -439,32 +600,82 @@ func (p *Project) CreateDocuments(ctx context.Context, pkgPath string) (Document
 					continue
 				}
 				aType := file.LookupType(typeName)
-				if aType == nil {
+				if aType == nil || aType.Anonymous {
 					continue
 				}
 				// Pure type (type declaration)
-				content := aType.Content()
+				content := aType.Content() + instantiationNote(instantiations[pkg.ImportPath+"."+aType.Name]) + enumNote(aType.Enum)
+				synthesized, sections := p.synthesizedNotes(pkg, aType)
 				doc := &Document{
-					Kind:    KindType,
-					Project: p.Name,
-					Package: pkg.Name,
-					Name:    aType.Name,
-					Path:    file.Path,
-					Content: content,
+					Kind:        KindType,
+					Project:     p.Name,
+					Package:     pkg.Name,
+					Name:        aType.Name,
+					Path:        file.Path,
+					Owners:      file.Owners,
+					Content:     content + synthesized,
+					Synthesized: sections,
 				}
 				doc.Hash = doc.HashContent()
-				documents.Append(doc)
+				if err := emit(doc); err != nil {
+					return err
+				}
+			}
+		}
+		if p.documents.PackageSummaries {
+			if err := emit(p.PackageSummary(pkg, p.documents.SummaryBudget)); err != nil {
+				return err
 			}
 		}
 	}
-	return documents, nil
+	return nil
+}
+
+// functionDependencies returns sorted, de-duplicated function references and the file imports they use
+func functionDependencies(file *File, function *Function) ([]string, []string) {
+	references := uniqueSorted(function.References)
+	if len(references) == 0 {
+		return nil, nil
+	}
+	qualifiers := make(map[string]bool)
+	for _, ref := range references {
+		if idx := strings.Index(ref, "."); idx != -1 {
+			ref = ref[:idx]
+		}
+		qualifiers[ref] = true
+	}
+	var imports []string
+	for _, imp := range file.Imports {
+		if qualifiers[imp.LocalName()] {
+			imports = append(imports, imp.Path)
+		}
+	}
+	return references, uniqueSorted(imports)
+}
+
+// uniqueSorted returns a sorted copy of values without duplicates
+func uniqueSorted(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(values))
+	var result []string
+	for _, value := range values {
+		if value == "" || seen[value] {
+			continue
+		}
+		seen[value] = true
+		result = append(result, value)
+	}
+	sort.Strings(result)
+	return result
 }
 
 // AddFunctionToFile adds a function to a file if it doesn't already exist
 func (p *Project) AddFunctionToFile(packageName, fileName, functionName, functionContent string) error {
 	pkg := p.GetPackage(packageName)
 	if pkg == nil {
-		return fmt.Errorf("package %s not found", packageName)
+		return &ErrNotFound{Kind: "package", Name: packageName}
 	}
 
 	var targetFile *File
@@ -476,7 +687,7 @@ func (p *Project) AddFunctionToFile(packageName, fileName, functionName, functio
 	}
 
 	if targetFile == nil {
-		return fmt.Errorf("file %s not found in package %s", fileName, packageName)
+		return fmt.Errorf("%w in package %s", &ErrNotFound{Kind: "file", Name: fileName}, packageName)
 	}
 
 	// Check if function already exists
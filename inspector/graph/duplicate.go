@@ -0,0 +1,273 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DuplicateOptions controls duplicate code detection
+type DuplicateOptions struct {
+	Threshold         float64 // Minimum shingle similarity for near duplicates (0..1)
+	MinTokens         int     // Documents with fewer normalized tokens are ignored
+	ShingleSize       int     // Number of consecutive tokens per shingle
+	IgnoreIdentifiers bool    // Whether identifier names are normalized away before comparison
+}
+
+// DefaultDuplicateOptions returns default duplicate detection options
+func DefaultDuplicateOptions() *DuplicateOptions {
+	return &DuplicateOptions{
+		Threshold:   0.8,
+		MinTokens:   20,
+		ShingleSize: 4,
+	}
+}
+
+// DuplicateMember represents a document participating in a duplicate cluster
+type DuplicateMember struct {
+	ID      string       `json:"id"`
+	Kind    DocumentKind `json:"kind"`
+	Package string       `json:"package"`
+	Path    string       `json:"path"`
+	Name    string       `json:"name"`
+	Type    string       `json:"type,omitempty"`
+	Tokens  int          `json:"tokens"`
+	Size    int          `json:"size"`
+}
+
+// DuplicateCluster groups documents with identical or similar content
+type DuplicateCluster struct {
+	Exact      bool               `json:"exact"`      // All members share the same content hash
+	Similarity float64            `json:"similarity"` // Lowest pairwise similarity linking the cluster
+	TotalSize  int                `json:"totalSize"`  // Sum of member content sizes
+	Members    []*DuplicateMember `json:"members"`
+}
+
+// DuplicateReport holds duplicate clusters sorted by total size
+type DuplicateReport struct {
+	Clusters []*DuplicateCluster `json:"clusters"`
+}
+
+// JSON returns report JSON representation
+func (r *DuplicateReport) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// Summary returns a human-readable report summary
+func (r *DuplicateReport) Summary() string {
+	builder := &strings.Builder{}
+	builder.WriteString(fmt.Sprintf("%d duplicate cluster(s)\n", len(r.Clusters)))
+	for i, cluster := range r.Clusters {
+		kind := "near"
+		if cluster.Exact {
+			kind = "exact"
+		}
+		builder.WriteString(fmt.Sprintf("#%d %s, %d members, %d bytes, similarity %.2f\n", i+1, kind, len(cluster.Members), cluster.TotalSize, cluster.Similarity))
+		for _, member := range cluster.Members {
+			name := member.Name
+			if member.Type != "" {
+				name = member.Type + "." + name
+			}
+			builder.WriteString(fmt.Sprintf("  %s %s (%d tokens)\n", member.Path, name, member.Tokens))
+		}
+	}
+	return builder.String()
+}
+
+// FindDuplicates groups function and method documents with identical hashes or similar normalized content
+func FindDuplicates(documents Documents, opts *DuplicateOptions) *DuplicateReport {
+	if opts == nil {
+		opts = DefaultDuplicateOptions()
+	}
+	shingleSize := opts.ShingleSize
+	if shingleSize <= 0 {
+		shingleSize = 1
+	}
+
+	type candidate struct {
+		members  []*DuplicateMember
+		shingles map[string]bool
+	}
+	var candidates []*candidate
+	byHash := make(map[uint64]*candidate)
+	for _, doc := range documents {
+		if doc == nil || (doc.Kind != KindFileFunc && doc.Kind != KindTypeMethod) || doc.Content == "" {
+			continue
+		}
+		tokens := tokenize(doc.Content, opts.IgnoreIdentifiers)
+		if len(tokens) < opts.MinTokens {
+			continue
+		}
+		hash := doc.Hash
+		if hash == 0 {
+			hash = doc.HashContent()
+		}
+		member := &DuplicateMember{
+			ID:      doc.GetID(),
+			Kind:    doc.Kind,
+			Package: doc.Package,
+			Path:    doc.Path,
+			Name:    doc.Name,
+			Type:    doc.Type,
+			Tokens:  len(tokens),
+			Size:    len(doc.Content),
+		}
+		if existing, ok := byHash[hash]; ok {
+			existing.members = append(existing.members, member)
+			continue
+		}
+		aCandidate := &candidate{members: []*DuplicateMember{member}, shingles: shingles(tokens, shingleSize)}
+		byHash[hash] = aCandidate
+		candidates = append(candidates, aCandidate)
+	}
+
+	// Link candidates whose shingle similarity reaches the threshold
+	parent := make([]int, len(candidates))
+	similarity := make([]float64, len(candidates))
+	for i := range parent {
+		parent[i] = i
+		similarity[i] = 1
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	if opts.Threshold > 0 {
+		for i := 0; i < len(candidates); i++ {
+			for j := i + 1; j < len(candidates); j++ {
+				score := jaccard(candidates[i].shingles, candidates[j].shingles)
+				if score < opts.Threshold {
+					continue
+				}
+				ri, rj := find(i), find(j)
+				if ri != rj {
+					parent[rj] = ri
+				}
+				if score < similarity[ri] {
+					similarity[ri] = score
+				}
+				if similarity[rj] < similarity[ri] {
+					similarity[ri] = similarity[rj]
+				}
+			}
+		}
+	}
+
+	groups := make(map[int][]*candidate)
+	var roots []int
+	for i, aCandidate := range candidates {
+		root := find(i)
+		if _, ok := groups[root]; !ok {
+			roots = append(roots, root)
+		}
+		groups[root] = append(groups[root], aCandidate)
+	}
+
+	report := &DuplicateReport{}
+	for _, root := range roots {
+		group := groups[root]
+		cluster := &DuplicateCluster{Exact: len(group) == 1, Similarity: similarity[root]}
+		for _, aCandidate := range group {
+			cluster.Members = append(cluster.Members, aCandidate.members...)
+		}
+		if len(cluster.Members) < 2 {
+			continue
+		}
+		for _, member := range cluster.Members {
+			cluster.TotalSize += member.Size
+		}
+		report.Clusters = append(report.Clusters, cluster)
+	}
+	sort.SliceStable(report.Clusters, func(i, j int) bool {
+		return report.Clusters[i].TotalSize > report.Clusters[j].TotalSize
+	})
+	return report
+}
+
+// tokenize splits code into tokens, dropping comments and whitespace
+func tokenize(code string, ignoreIdentifiers bool) []string {
+	var tokens []string
+	runes := []rune(code)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '/':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			i += 2
+			for i+1 < len(runes) && !(runes[i] == '*' && runes[i+1] == '/') {
+				i++
+			}
+			i += 2
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			word := string(runes[start:i])
+			if ignoreIdentifiers && !keywords[word] {
+				word = "$id"
+			}
+			tokens = append(tokens, word)
+		case unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || unicode.IsLetter(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, string(runes[start:i]))
+		default:
+			tokens = append(tokens, string(r))
+			i++
+		}
+	}
+	return tokens
+}
+
+// keywords are preserved when identifiers are normalized
+var keywords = map[string]bool{
+	"break": true, "case": true, "chan": true, "const": true, "continue": true, "default": true,
+	"defer": true, "else": true, "for": true, "func": true, "go": true, "goto": true, "if": true,
+	"import": true, "interface": true, "map": true, "package": true, "range": true, "return": true,
+	"select": true, "struct": true, "switch": true, "type": true, "var": true, "nil": true,
+	"true": true, "false": true, "class": true, "new": true, "null": true, "this": true,
+	"throw": true, "try": true, "catch": true, "finally": true, "while": true, "do": true,
+	"public": true, "private": true, "protected": true, "static": true, "final": true, "void": true,
+	"function": true, "let": true, "async": true, "await": true,
+}
+
+// shingles builds a set of consecutive token sequences
+func shingles(tokens []string, size int) map[string]bool {
+	result := make(map[string]bool)
+	if len(tokens) < size {
+		result[strings.Join(tokens, " ")] = true
+		return result
+	}
+	for i := 0; i+size <= len(tokens); i++ {
+		result[strings.Join(tokens[i:i+size], " ")] = true
+	}
+	return result
+}
+
+// jaccard returns the Jaccard similarity of two shingle sets
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	intersection := 0
+	for key := range a {
+		if b[key] {
+			intersection++
+		}
+	}
+	union := len(a) + len(b) - intersection
+	return float64(intersection) / float64(union)
+}
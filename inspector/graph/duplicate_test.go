@@ -0,0 +1,95 @@
+package graph_test
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/linager/inspector/graph"
+	"strings"
+	"testing"
+)
+
+func TestFindDuplicates(t *testing.T) {
+	total := `func Total(orders []*Order) float64 {
+	// sum up all order amounts
+	sum := 0.0
+	for _, order := range orders {
+		if order.Status == "paid" {
+			sum += order.Amount * (1 - order.Discount)
+		}
+	}
+	return sum
+}`
+	renamed := `func Revenue(items []*Order) float64 {
+	acc := 0.0
+	for _, item := range items {
+		if item.Status == "paid" {
+			acc += item.Amount * (1 - item.Discount)
+		}
+	}
+	return acc
+}`
+	unrelated := `func Format(name string, values []string) string {
+	builder := strings.Builder{}
+	builder.WriteString(name)
+	for i, value := range values {
+		builder.WriteString(fmt.Sprintf("%d=%s;", i, value))
+	}
+	return builder.String()
+}`
+	newDoc := func(path, name, content string) *graph.Document {
+		doc := &graph.Document{Kind: graph.KindFileFunc, Package: "billing", Path: path, Name: name, Content: content}
+		doc.Hash = doc.HashContent()
+		return doc
+	}
+	documents := graph.Documents{
+		newDoc("billing/a.go", "Total", total),
+		newDoc("orders/b.go", "Total", total),
+		newDoc("reports/c.go", "Revenue", renamed),
+		newDoc("util/d.go", "Format", unrelated),
+		newDoc("util/e.go", "Noop", "func Noop() {}"),
+		newDoc("util/f.go", "Noop", "func Noop() {}"),
+	}
+
+	testCases := []struct {
+		description string
+		options     *graph.DuplicateOptions
+		expect      [][]string
+		exact       []bool
+	}{
+		{
+			description: "exact copies only",
+			options:     &graph.DuplicateOptions{Threshold: 0.8, MinTokens: 10, ShingleSize: 4},
+			expect:      [][]string{{"billing/a.go", "orders/b.go"}},
+			exact:       []bool{true},
+		},
+		{
+			description: "renamed variables with identifier normalization",
+			options:     &graph.DuplicateOptions{Threshold: 0.8, MinTokens: 10, ShingleSize: 4, IgnoreIdentifiers: true},
+			expect:      [][]string{{"billing/a.go", "orders/b.go", "reports/c.go"}},
+			exact:       []bool{false},
+		},
+	}
+
+	for _, testCase := range testCases {
+		report := graph.FindDuplicates(documents, testCase.options)
+		var actual [][]string
+		var exact []bool
+		for _, cluster := range report.Clusters {
+			var paths []string
+			for _, member := range cluster.Members {
+				paths = append(paths, member.Path)
+			}
+			actual = append(actual, paths)
+			exact = append(exact, cluster.Exact)
+		}
+		assert.EqualValues(t, testCase.expect, actual, testCase.description)
+		assert.EqualValues(t, testCase.exact, exact, testCase.description)
+
+		data, err := report.JSON()
+		assert.NoError(t, err, testCase.description)
+		decoded := &graph.DuplicateReport{}
+		assert.NoError(t, json.Unmarshal(data, decoded), testCase.description)
+		assert.Equal(t, len(report.Clusters), len(decoded.Clusters), testCase.description)
+		assert.True(t, strings.HasPrefix(report.Summary(), "1 duplicate cluster(s)"), testCase.description)
+	}
+}
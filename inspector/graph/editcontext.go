@@ -0,0 +1,491 @@
+package graph
+
+import (
+	"sort"
+	"strings"
+)
+
+// Edit context block provenance labels
+const (
+	ContextEnclosing = "enclosing" // Function or type enclosing the edit offset
+	ContextReceiver  = "receiver"  // Declaring type of a method
+	ContextCallee    = "callee"    // Function or method called by a block
+	ContextCaller    = "caller"    // Function or method calling a block
+	ContextType      = "type"      // Type used by a signature, body or field
+	ContextConstant  = "constant"  // Constant or variable referenced by a block
+	ContextFile      = "file"      // Other declaration of the edited file
+	ContextPackage   = "package"   // Other declaration of the edited package
+)
+
+// editContextDepth is the maximum graph distance of related blocks, file and package blocks follow them
+const editContextDepth = 2
+
+// contextLabelRank orders blocks of the same distance by provenance
+var contextLabelRank = map[string]int{ContextEnclosing: 0, ContextReceiver: 1, ContextCallee: 2, ContextType: 3, ContextConstant: 4, ContextCaller: 5, ContextFile: 6, ContextPackage: 7}
+
+// CallGraph maps callers to callees by qualified function names, e.g. myapp/stack.New -> myapp/stack.Stack.Push
+type CallGraph map[string][]string
+
+// ContextBlock is a document selected for an edit context
+type ContextBlock struct {
+	Document *Document
+	Label    string // Provenance, see Context* constants
+	Via      string // Qualified name of the block the relation was found from, empty for the enclosing block
+	Distance int    // Graph distance from the enclosing block
+	Tokens   int    // Content tokens counted by DocumentTokenCounter
+}
+
+// editSymbol is a declaration considered for an edit context
+type editSymbol struct {
+	key      string // Qualified name, see CallGraph
+	order    int    // Project walk order, breaks ranking ties
+	pkg      *Package
+	file     *File
+	owner    *editSymbol // Declaring type of methods
+	function *Function
+	aType    *Type
+	constant *Constant
+	variable *Variable
+}
+
+// editIndex holds declarations of a project by package
+type editIndex struct {
+	project    *Project
+	symbols    []*editSymbol
+	byKey      map[string]*editSymbol
+	referenced map[*editSymbol][]*editSymbol         // Resolved references, see references
+	names      map[*Package]map[string]*editSymbol   // package level declaration name -> symbol
+	methods    map[*Package]map[string][]*editSymbol // method name -> symbols
+	packages   map[*File]*Package
+}
+
+// BuildEditContext returns blocks of project declarations relevant to an edit at the byte offset of a file (project
+// relative path or path suffix), packed into a token budget: the function or method enclosing the offset (a type
+// when the offset is outside functions), then declarations ranked by graph distance from it: receiver types,
+// callees and callers of the call graph (calls may be nil, function bodies are resolved otherwise), types used by
+// signatures, bodies and fields, and referenced constants, up to two hops away; other declarations of the file and
+// its package follow. Blocks are packed greedily in rank order, blocks exceeding the remaining budget are skipped.
+// Identical inputs yield identical blocks.
+func BuildEditContext(project *Project, calls CallGraph, file string, offset, budget int) ([]*ContextBlock, error) {
+	index := newEditIndex(project)
+	target := index.enclosing(file, offset)
+	if target == nil {
+		if index.file(file) == nil {
+			return nil, &ErrNotFound{Kind: "file", Name: file}
+		}
+		return nil, &ErrNotFound{Kind: "symbol", Name: file}
+	}
+	callers := map[string][]string{}
+	for caller, callees := range calls {
+		for _, callee := range callees {
+			callers[callee] = append(callers[callee], caller)
+		}
+	}
+	blocks := map[*editSymbol]*ContextBlock{target: {Label: ContextEnclosing}}
+	frontier := []*editSymbol{target}
+	for distance := 1; distance <= editContextDepth; distance++ {
+		var next []*editSymbol
+		for _, from := range frontier {
+			for _, relation := range index.related(from, calls, callers) {
+				if _, ok := blocks[relation.symbol]; ok {
+					continue
+				}
+				blocks[relation.symbol] = &ContextBlock{Label: relation.label, Via: from.key, Distance: distance}
+				next = append(next, relation.symbol)
+			}
+		}
+		frontier = next
+	}
+	for _, symbol := range index.symbols {
+		if _, ok := blocks[symbol]; ok || symbol.pkg != target.pkg {
+			continue
+		}
+		if symbol.file == target.file {
+			blocks[symbol] = &ContextBlock{Label: ContextFile, Distance: editContextDepth + 1}
+		} else {
+			blocks[symbol] = &ContextBlock{Label: ContextPackage, Distance: editContextDepth + 2}
+		}
+	}
+
+	ranked := make([]*editSymbol, 0, len(blocks))
+	for symbol := range blocks {
+		ranked = append(ranked, symbol)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		left, right := blocks[ranked[i]], blocks[ranked[j]]
+		if left.Distance != right.Distance {
+			return left.Distance < right.Distance
+		}
+		if contextLabelRank[left.Label] != contextLabelRank[right.Label] {
+			return contextLabelRank[left.Label] < contextLabelRank[right.Label]
+		}
+		return ranked[i].order < ranked[j].order
+	})
+	var result []*ContextBlock
+	for _, symbol := range ranked {
+		block := blocks[symbol]
+		block.Document = project.editDocument(symbol)
+		if block.Document.Content == "" {
+			continue
+		}
+		block.Tokens = block.Document.Tokens()
+		if block.Tokens > budget {
+			continue
+		}
+		budget -= block.Tokens
+		result = append(result, block)
+	}
+	return result, nil
+}
+
+// newEditIndex indexes project declarations in walk order
+func newEditIndex(project *Project) *editIndex {
+	index := &editIndex{project: project, byKey: map[string]*editSymbol{}, referenced: map[*editSymbol][]*editSymbol{}, names: map[*Package]map[string]*editSymbol{}, methods: map[*Package]map[string][]*editSymbol{}, packages: map[*File]*Package{}}
+	add := func(symbol *editSymbol, name string) *editSymbol {
+		symbol.order = len(index.symbols)
+		index.symbols = append(index.symbols, symbol)
+		if existing, ok := index.byKey[symbol.key]; !ok || isStubType(existing.aType) {
+			index.byKey[symbol.key] = symbol
+		}
+		if symbol.owner != nil {
+			index.methods[symbol.pkg][name] = append(index.methods[symbol.pkg][name], symbol)
+		} else if existing, ok := index.names[symbol.pkg][name]; !ok || isStubType(existing.aType) {
+			index.names[symbol.pkg][name] = symbol // Go receiver stubs yield to type declarations
+		}
+		return symbol
+	}
+	for _, pkg := range project.Packages {
+		index.names[pkg] = map[string]*editSymbol{}
+		index.methods[pkg] = map[string][]*editSymbol{}
+		prefix := pkg.Ref().Package + "."
+		for _, file := range pkg.FileSet {
+			index.packages[file] = pkg
+			for _, constant := range file.Constants {
+				add(&editSymbol{key: prefix + constant.Name, pkg: pkg, file: file, constant: constant}, constant.Name)
+			}
+			for _, variable := range file.Variables {
+				add(&editSymbol{key: prefix + variable.Name, pkg: pkg, file: file, variable: variable}, variable.Name)
+			}
+			for _, aType := range file.Types {
+				name := aType.qualifiedName()
+				owner := add(&editSymbol{key: prefix + name, pkg: pkg, file: file, aType: aType}, name)
+				for _, method := range aType.Methods {
+					add(&editSymbol{key: prefix + name + "." + method.Name, pkg: pkg, file: file, owner: owner, function: method}, method.Name)
+				}
+			}
+			for _, function := range file.Functions {
+				add(&editSymbol{key: prefix + function.Name, pkg: pkg, file: file, function: function}, function.Name)
+			}
+		}
+	}
+	return index
+}
+
+// isStubType reports whether a type was created for methods declared apart from the type declaration
+func isStubType(aType *Type) bool {
+	return aType != nil && len(aType.Fields) == 0 && aType.Location == nil && aType.Comment == nil && len(aType.Methods) > 0
+}
+
+// file returns the project file with a path equal to or ending with path
+func (i *editIndex) file(path string) *File {
+	for file := range i.packages {
+		if file.Path == path {
+			return file
+		}
+	}
+	var result *File
+	for file := range i.packages {
+		if strings.HasSuffix(file.Path, "/"+path) && (result == nil || file.Path < result.Path) {
+			result = file
+		}
+	}
+	return result
+}
+
+// enclosing returns the innermost function or method whose body spans the offset, a located type otherwise
+func (i *editIndex) enclosing(path string, offset int) *editSymbol {
+	file := i.file(path)
+	var result *editSymbol
+	span := -1
+	for _, symbol := range i.symbols {
+		if symbol.file != file {
+			continue
+		}
+		var location *Location
+		switch {
+		case symbol.function != nil && symbol.function.Location != nil && symbol.function.Location.End > 0:
+			location = symbol.function.Location
+		case symbol.function != nil && symbol.function.Body != nil:
+			location = &symbol.function.Body.Location
+		case symbol.aType != nil && symbol.aType.Location != nil:
+			location = symbol.aType.Location
+		}
+		if location == nil || offset < location.Start || offset > location.End || location.End <= location.Start {
+			continue
+		}
+		if size := location.End - location.Start; span == -1 || size < span || size == span && symbol.function != nil {
+			result, span = symbol, size
+		}
+	}
+	return result
+}
+
+// editRelation is a declaration related to another one
+type editRelation struct {
+	symbol *editSymbol
+	label  string
+}
+
+// related returns declarations one hop away from a symbol in a stable order
+func (i *editIndex) related(from *editSymbol, calls CallGraph, callers map[string][]string) []editRelation {
+	var result []editRelation
+	seen := map[*editSymbol]bool{from: true}
+	add := func(symbol *editSymbol, label string) {
+		if symbol != nil && !seen[symbol] {
+			seen[symbol] = true
+			result = append(result, editRelation{symbol: symbol, label: label})
+		}
+	}
+	if from.owner != nil {
+		add(i.names[from.pkg][from.owner.aType.qualifiedName()], ContextReceiver)
+	}
+	for _, key := range sortedStrings(calls[from.key]) {
+		add(i.byKey[key], ContextCallee)
+	}
+	for _, symbol := range i.references(from) {
+		switch {
+		case symbol.function != nil:
+			add(symbol, ContextCallee)
+		case symbol.aType != nil:
+			add(symbol, ContextType)
+		default:
+			add(symbol, ContextConstant)
+		}
+	}
+	if from.function != nil && calls == nil {
+		for _, symbol := range i.symbols {
+			if symbol.function != nil && symbol != from && containsSymbol(i.references(symbol), from) {
+				add(symbol, ContextCaller)
+			}
+		}
+	}
+	for _, key := range sortedStrings(callers[from.key]) {
+		add(i.byKey[key], ContextCaller)
+	}
+	return result
+}
+
+// references resolves identifiers of a declaration source within its package or, when qualified, through its file
+// imports; selected method calls resolve to methods of referenced types or the declaring package
+func (i *editIndex) references(symbol *editSymbol) []*editSymbol {
+	if result, ok := i.referenced[symbol]; ok {
+		return result
+	}
+	result := i.resolveReferences(symbol)
+	i.referenced[symbol] = result
+	return result
+}
+
+// resolveReferences resolves references of a declaration, see references
+func (i *editIndex) resolveReferences(symbol *editSymbol) []*editSymbol {
+	text := literalExpr.ReplaceAllString(symbolText(symbol), "")
+	scopes := map[string]*Package{}
+	for _, imp := range symbol.file.Imports {
+		if pkg := i.project.lookupImport(imp.Path); pkg != nil && i.names[pkg] != nil {
+			scopes[imp.LocalName()] = pkg
+		}
+	}
+	var result []*editSymbol
+	types := map[*editSymbol]bool{}
+	if symbol.owner != nil {
+		types[symbol.owner] = true
+	}
+	for _, match := range identExpr.FindAllStringSubmatch(text, -1) {
+		qualifier, name := match[1], match[2]
+		pkg := symbol.pkg
+		if qualifier != "" {
+			if imported, ok := scopes[qualifier]; ok {
+				pkg = imported
+			}
+		}
+		if referenced, ok := i.names[pkg][name]; ok && referenced != symbol && !containsSymbol(result, referenced) {
+			result = append(result, referenced)
+			if referenced.aType != nil {
+				types[referenced] = true
+			}
+		}
+	}
+	if symbol.function == nil {
+		return result
+	}
+	for _, match := range selectorCallExpr.FindAllStringSubmatch(text, -1) {
+		for _, pkg := range i.scopePackages(symbol.pkg, scopes) {
+			for _, method := range i.methods[pkg][match[1]] {
+				if method != symbol && (pkg == symbol.pkg || types[method.owner] || types[i.names[pkg][method.owner.aType.qualifiedName()]]) && !containsSymbol(result, method) {
+					result = append(result, method)
+				}
+			}
+		}
+	}
+	return result
+}
+
+// scopePackages returns the declaring package followed by imported packages ordered by local name
+func (i *editIndex) scopePackages(pkg *Package, scopes map[string]*Package) []*Package {
+	result := []*Package{pkg}
+	var names []string
+	for name := range scopes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if scopes[name] != pkg {
+			result = append(result, scopes[name])
+		}
+	}
+	return result
+}
+
+// symbolText returns source text of a declaration used to resolve its references
+func symbolText(symbol *editSymbol) string {
+	switch {
+	case symbol.function != nil:
+		return functionSource(symbol.function)
+	case symbol.aType != nil:
+		return typeSource(symbol.aType)
+	case symbol.constant != nil:
+		return valueSource("const", symbol.constant.Name, symbol.constant.Type, symbol.constant.Value, symbol.constant.Location)
+	}
+	return valueSource("var", symbol.variable.Name, symbol.variable.Type, symbol.variable.Value, symbol.variable.Location)
+}
+
+// functionSource returns the source of a function, its signature and body when the source location is not recorded
+func functionSource(function *Function) string {
+	if content := function.Content(); content != "" {
+		return content
+	}
+	builder := strings.Builder{}
+	if function.Comment != nil && function.Comment.Text != "" {
+		builder.WriteString(commentLines(function.Comment.Text))
+	}
+	builder.WriteString(function.Signature)
+	if function.Body != nil {
+		builder.WriteString(" ")
+		builder.WriteString(function.Body.Text)
+	}
+	return builder.String()
+}
+
+// typeSource returns the source of a type, its declaration with fields when the source location is not recorded
+func typeSource(aType *Type) string {
+	if content := aType.Content(); content != "" {
+		return content
+	}
+	builder := strings.Builder{}
+	if aType.Comment != nil && aType.Comment.Text != "" {
+		builder.WriteString(commentLines(aType.Comment.Text))
+	}
+	builder.WriteString("type ")
+	builder.WriteString(aType.Name)
+	if len(aType.Fields) == 0 {
+		builder.WriteString(" ")
+		builder.WriteString(aType.Kind.String())
+		return builder.String()
+	}
+	builder.WriteString(" struct {\n")
+	for _, field := range aType.Fields {
+		builder.WriteString("\t")
+		if !field.IsEmbedded {
+			builder.WriteString(field.Name)
+			builder.WriteString(" ")
+		}
+		if field.Type != nil {
+			builder.WriteString(TypeName(field.Type))
+		}
+		if field.Tag != "" {
+			builder.WriteString(" `" + string(field.Tag) + "`")
+		}
+		builder.WriteString("\n")
+	}
+	builder.WriteString("}")
+	return builder.String()
+}
+
+// valueSource returns the source of a constant or variable, its declaration when the source location is not recorded
+func valueSource(keyword, name string, aType *Type, value string, location *Location) string {
+	if location != nil && location.Raw != "" {
+		return location.Raw
+	}
+	text := keyword + " " + name
+	if aType != nil && aType.Name != "" {
+		text += " " + TypeName(aType)
+	}
+	if value != "" {
+		text += " = " + value
+	}
+	return text
+}
+
+// TypeName returns the source type name of a type reference, empty for nil
+func TypeName(aType *Type) string {
+	if aType == nil {
+		return ""
+	}
+	if aType.Anonymous {
+		return aType.InlineDeclaration()
+	}
+	name := aType.Name
+	if aType.RawName != "" {
+		name = aType.RawName
+	}
+	if aType.IsPointer && !strings.HasPrefix(name, "*") {
+		name = "*" + name
+	}
+	return name
+}
+
+// commentLines renders comment text as line comments
+func commentLines(text string) string {
+	builder := strings.Builder{}
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		builder.WriteString("// ")
+		builder.WriteString(line)
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+// editDocument creates the document of an edit context declaration
+func (p *Project) editDocument(symbol *editSymbol) *Document {
+	doc := &Document{Project: p.Name, Package: symbol.pkg.Name, Path: symbol.file.Path, Owners: symbol.file.Owners, Content: symbolText(symbol)}
+	switch {
+	case symbol.owner != nil:
+		doc.Kind, doc.Type, doc.Name, doc.Signature = KindTypeMethod, symbol.owner.aType.Name, symbol.function.Name, symbol.function.Signature
+	case symbol.function != nil:
+		doc.Kind, doc.Name, doc.Signature = KindFileFunc, symbol.function.Name, symbol.function.Signature
+	case symbol.aType != nil:
+		doc.Kind, doc.Name = KindType, symbol.aType.Name
+	case symbol.constant != nil:
+		doc.Kind, doc.Name = KindConstant, symbol.constant.Name
+	default:
+		doc.Kind, doc.Name = KindVariable, symbol.variable.Name
+	}
+	doc.Hash = doc.HashContent()
+	p.redactDocument(doc)
+	return doc
+}
+
+func containsSymbol(symbols []*editSymbol, symbol *editSymbol) bool {
+	for _, candidate := range symbols {
+		if candidate == symbol {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedStrings(values []string) []string {
+	result := append([]string(nil), values...)
+	sort.Strings(result)
+	return result
+}
@@ -1,6 +1,20 @@
 package graph
 
-// Emitter represents generator
+// Emitter renders a graph file back to source code of its language
 type Emitter interface {
+	// Emit returns source code of a file
 	Emit(file *File) ([]byte, error)
+	// Capabilities describes the language and formatting applied by the emitter
+	Capabilities() EmitterCapabilities
+}
+
+// EmitterCapabilities describes an emitter language and its active formatting options
+type EmitterCapabilities struct {
+	Language     string   // Language name, e.g. go
+	Extensions   []string // File extensions handled by the emitter, e.g. .go
+	Format       bool     // Output is formatted with the language formatter
+	GroupImports bool     // Imports are grouped and sorted
+	BuildTags    bool     // Build constraints are preserved
+	Indent       string   // Indentation unit of emitted blocks
+	BraceStyle   string   // Opening brace placement, same-line or next-line
 }
@@ -0,0 +1,59 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EnumValue represents a constant of an enumerated type
+type EnumValue struct {
+	Name    string
+	Literal string // Value expression, e.g. iota or "active", implicitly repeated expressions are repeated
+	Ordinal int    // Position among values of the type in declaration order
+	Display string // Display name from the String method or name map, empty if unknown
+}
+
+// Enum represents constants sharing a defined type, e.g. type Status int with const Active Status = iota
+type Enum struct {
+	Values   []*EnumValue
+	Stringer bool   // Whether display names come from a String() method
+	NameMap  string // Variable mapping values to display names, e.g. statusNames
+}
+
+// DisplayNames returns display names by value name, values without display name are omitted
+func (e *Enum) DisplayNames() map[string]string {
+	result := map[string]string{}
+	for _, value := range e.Values {
+		if value.Display != "" {
+			result[value.Name] = value.Display
+		}
+	}
+	return result
+}
+
+// Lookup returns a value by name, nil if not found
+func (e *Enum) Lookup(name string) *EnumValue {
+	for _, value := range e.Values {
+		if value.Name == name {
+			return value
+		}
+	}
+	return nil
+}
+
+// enumNote returns the value table of an enum type document, empty for other types
+func enumNote(enum *Enum) string {
+	if enum == nil || len(enum.Values) == 0 {
+		return ""
+	}
+	builder := &strings.Builder{}
+	builder.WriteString("\n// Values:\n")
+	for _, value := range enum.Values {
+		builder.WriteString(fmt.Sprintf("// %d. %s = %s", value.Ordinal, value.Name, value.Literal))
+		if value.Display != "" {
+			builder.WriteString(fmt.Sprintf(" (%q)", value.Display))
+		}
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
@@ -0,0 +1,90 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrNotFound reports a missing project element, file or directory
+type ErrNotFound struct {
+	Kind  string // Element kind, e.g. package, file, type, field, method, function, path
+	Name  string // Element name
+	Cause error  // Underlying filesystem error (e.g. os.ErrNotExist), nil for missing project elements
+}
+
+// Error returns error message
+func (e *ErrNotFound) Error() string {
+	if e.Cause == nil {
+		return fmt.Sprintf("%s %s not found", e.Kind, e.Name)
+	}
+	return fmt.Sprintf("%s %s not found: %v", e.Kind, e.Name, e.Cause)
+}
+
+// Unwrap returns the underlying filesystem error
+func (e *ErrNotFound) Unwrap() error {
+	return e.Cause
+}
+
+// Is matches ErrNotFound with the same (or empty) kind and name; os.ErrNotExist is matched through Cause only
+func (e *ErrNotFound) Is(target error) bool {
+	t, ok := target.(*ErrNotFound)
+	if !ok {
+		return false
+	}
+	return (t.Kind == "" || t.Kind == e.Kind) && (t.Name == "" || t.Name == e.Name)
+}
+
+// ErrParse reports a source parsing failure
+type ErrParse struct {
+	Path  string // Source path
+	Line  int    // Line of the first error, 0 if unknown
+	Cause error  // Underlying parser error
+}
+
+// Error returns error message
+func (e *ErrParse) Error() string {
+	location := e.Path
+	if e.Line > 0 {
+		location = fmt.Sprintf("%s:%d", e.Path, e.Line)
+	}
+	if e.Cause == nil {
+		return fmt.Sprintf("failed to parse %s", location)
+	}
+	return fmt.Sprintf("failed to parse %s: %v", location, e.Cause)
+}
+
+// Unwrap returns the underlying parser error
+func (e *ErrParse) Unwrap() error {
+	return e.Cause
+}
+
+// Is matches ErrParse with the same (or empty) path
+func (e *ErrParse) Is(target error) bool {
+	t, ok := target.(*ErrParse)
+	return ok && (t.Path == "" || t.Path == e.Path)
+}
+
+// ErrUnsupported reports an unsupported language or file type
+type ErrUnsupported struct {
+	Language string
+}
+
+// Error returns error message
+func (e *ErrUnsupported) Error() string {
+	return fmt.Sprintf("unsupported language: %s", e.Language)
+}
+
+// Is matches ErrUnsupported with the same (or empty) language
+func (e *ErrUnsupported) Is(target error) bool {
+	t, ok := target.(*ErrUnsupported)
+	return ok && (t.Language == "" || t.Language == e.Language)
+}
+
+// NotFoundError wraps a missing path error as ErrNotFound, other errors are returned as is
+func NotFoundError(kind, name string, err error) error {
+	if err != nil && errors.Is(err, os.ErrNotExist) {
+		return &ErrNotFound{Kind: kind, Name: name, Cause: err}
+	}
+	return err
+}
@@ -0,0 +1,34 @@
+package graph_test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/linager/inspector/graph"
+	"os"
+	"testing"
+)
+
+func TestErrors_Is(t *testing.T) {
+	missingFile := graph.NotFoundError("file", "app.go", &os.PathError{Op: "open", Path: "app.go", Err: os.ErrNotExist})
+	missingType := fmt.Errorf("failed to rename: %w", &graph.ErrNotFound{Kind: "type", Name: "User"})
+	parseErr := &graph.ErrParse{Path: "app.go", Cause: errors.New("unexpected EOF")}
+
+	testCases := []struct {
+		description string
+		err         error
+		target      error
+		expect      bool
+	}{
+		{description: "missing file by kind", err: missingFile, target: &graph.ErrNotFound{Kind: "file"}, expect: true},
+		{description: "missing file is a filesystem error", err: missingFile, target: os.ErrNotExist, expect: true},
+		{description: "missing type by kind and name", err: missingType, target: &graph.ErrNotFound{Kind: "type", Name: "User"}, expect: true},
+		{description: "missing type is not a filesystem error", err: missingType, target: os.ErrNotExist, expect: false},
+		{description: "parse error of any path", err: parseErr, target: &graph.ErrParse{}, expect: true},
+		{description: "parse error of the same path", err: parseErr, target: &graph.ErrParse{Path: "app.go"}, expect: true},
+		{description: "parse error of another path", err: parseErr, target: &graph.ErrParse{Path: "main.go"}, expect: false},
+	}
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expect, errors.Is(testCase.err, testCase.target), testCase.description)
+	}
+}
@@ -0,0 +1,117 @@
+package graph
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Export names used by JavaScript/TypeScript modules
+const (
+	ExportDefault = "default" // Default export, e.g. export default Button
+	ExportAll     = "*"       // All exports of another module, e.g. export * from './Button'
+)
+
+// moduleExtensions lists JavaScript/TypeScript extensions tried when resolving module specifiers
+var moduleExtensions = []string{".js", ".jsx", ".ts", ".tsx"}
+
+// Export represents a name exported by a JavaScript/TypeScript module
+type Export struct {
+	Name  string // Exported name, ExportDefault or ExportAll
+	Local string // Declared name, or the name exported by From for re-exports (ExportDefault, ExportAll for namespaces)
+	From  string // Module specifier of re-exports, empty for names declared in this file
+}
+
+// Origin locates the declaration of an imported name
+type Origin struct {
+	File string // Declaring file path
+	Name string // Declared name, e.g. the function exported as default
+}
+
+// ResolveImports links relative JavaScript/TypeScript imports of project files to the files declaring the imported
+// names, following re-exports of barrel files (e.g. index.js); unresolved imports keep a nil Origin
+func (p *Project) ResolveImports() {
+	resolver := &exportResolver{files: map[string]*File{}}
+	for _, pkg := range p.Packages {
+		for _, file := range pkg.FileSet {
+			resolver.files[filepath.Clean(file.Path)] = file
+		}
+	}
+	for _, pkg := range p.Packages {
+		for _, file := range pkg.FileSet {
+			for k := range file.Imports {
+				anImport := &file.Imports[k]
+				if anImport.Imported == "" || anImport.Imported == ExportAll {
+					continue
+				}
+				declaring, name := resolver.resolve(file, anImport.Path, anImport.Imported, map[string]bool{})
+				if declaring != nil {
+					anImport.Origin = &Origin{File: declaring.Path, Name: name}
+				}
+			}
+		}
+	}
+}
+
+// exportResolver resolves exported names to declaring files
+type exportResolver struct {
+	files map[string]*File // cleaned file path -> file
+}
+
+// resolve returns the file declaring a name exported by the module a specifier refers to, visited guards re-export cycles
+func (r *exportResolver) resolve(from *File, specifier, name string, visited map[string]bool) (*File, string) {
+	module := r.module(from, specifier)
+	if module == nil {
+		return nil, ""
+	}
+	key := module.Path + "#" + name
+	if visited[key] {
+		return nil, ""
+	}
+	visited[key] = true
+	for _, export := range module.Exports {
+		if export.Name != name {
+			continue
+		}
+		if export.From == "" {
+			return module, export.Local
+		}
+		if export.Local == ExportAll { // export * as ns from './module'
+			if target := r.module(module, export.From); target != nil {
+				return target, ExportAll
+			}
+			return nil, ""
+		}
+		return r.resolve(module, export.From, export.Local, visited)
+	}
+	if name == ExportDefault {
+		return nil, "" // export * does not re-export default
+	}
+	for _, export := range module.Exports {
+		if export.Name != ExportAll {
+			continue
+		}
+		if declaring, declared := r.resolve(module, export.From, name, visited); declaring != nil {
+			return declaring, declared
+		}
+	}
+	return nil, ""
+}
+
+// module returns the project file a relative module specifier refers to, trying extensions and index files
+func (r *exportResolver) module(from *File, specifier string) *File {
+	if !strings.HasPrefix(specifier, ".") {
+		return nil // package import, e.g. react
+	}
+	base := filepath.Join(filepath.Dir(from.Path), filepath.FromSlash(specifier))
+	if file, ok := r.files[base]; ok {
+		return file
+	}
+	for _, candidate := range []string{base, filepath.Join(base, "index")} {
+		for _, ext := range moduleExtensions {
+			if file, ok := r.files[candidate+ext]; ok {
+				return file
+			}
+		}
+	}
+	return nil
+}
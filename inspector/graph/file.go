@@ -1,5 +1,7 @@
 package graph
 
+import "strings"
+
 // ContentGenerator defines an interface for generating content from a file
 type ContentGenerator interface {
 	// Generate generates content from a file
@@ -17,25 +19,57 @@ type File struct {
 	Variables  []*Variable // Variables declared in this file
 	Functions  []*Function // Functions declared in this file
 	Imports    []Import    // Imports used in this file
+	Exports    []*Export   // Names exported by this file (JavaScript/TypeScript modules)
+	Owners     []string    // Code owners (e.g. CODEOWNERS handles)
+	Doc        string      // Package documentation comment declared in this file
+	Lines      int         // Number of source lines
+	BuildTags  string      // Build constraint expression, e.g. linux && amd64
+	Hash       uint64      // Source content hash at inspection, zero when not recorded, see SetSource
+	// Instantiations lists generic types and functions instantiated in this file, see Project.Instantiations
+	Instantiations []*Instantiation
+	// Warnings lists reasons the file was inspected partially, e.g. a parse timeout
+	Warnings []*ParseWarning
+	// Coverage accounts declarations against modeled graph elements, set when Config.ParseCoverage is enabled
+	Coverage *ParseCoverage `json:",omitempty"`
 
 	functionMap map[string]int // Map of functions for quick lookup
 	variableMap map[string]int // Map of variables for quick lookup
 	constantMap map[string]int // Map of constants for quick lookup
 	typeMap     map[string]int // Map of types for quick lookup
+	summary     *FileSummary   // Cached exported symbols, see Summary
+	source      []byte         // Source the locations refer to, see SetSource
 }
 
 // Import represents an imported package
 type Import struct {
-	Name string // Local name (may be empty for default)
-	Path string // Import path
+	Name     string  // Local name (may be empty for default)
+	Path     string  // Import path
+	Imported string  // Name exported by the imported module, ExportDefault or ExportAll (JavaScript/TypeScript)
+	Origin   *Origin // Declaration of the imported name resolved through re-exports, see Project.ResolveImports
+}
+
+// LocalName returns the name the import is referenced by in source code
+func (i Import) LocalName() string {
+	if i.Name != "" {
+		return i.Name
+	}
+	if idx := strings.LastIndex(i.Path, "/"); idx != -1 {
+		return i.Path[idx+1:]
+	}
+	return i.Path
 }
 
 // Package represents a Go package with its files and types
 type Package struct {
-	Name       string
-	ImportPath string
-	FileSet    []*File  // Files that are part of this package
-	Assets     []*Asset // Assets associated with this package
+	Name        string
+	ImportPath  string
+	FileSet     []*File         // Files that are part of this package
+	Assets      []*Asset        // Assets associated with this package
+	Owners      []string        // Code owners (e.g. CODEOWNERS handles)
+	Skipped     []*SkippedFile  // Source files omitted as oversized or binary
+	Variants    []*BuildVariant // Build variants of multi-variant inspection, FileSet holds files of all variants
+	Comment     *LocationNode   // Package documentation, e.g. Javadoc of package-info.java
+	Annotations []string        // Package annotations, e.g. @ParametersAreNonnullByDefault of package-info.java
 
 	assetMap map[string]int // Map of assets for quick lookup
 	fileMap  map[string]int // Map of files for quick lookup
@@ -85,9 +119,9 @@ func (p *Package) IndexTypes() {
 	}
 }
 
-// LookupFunction retrieves a function by name from the file
+// LookupFunction retrieves a function by name from the file, an index missing functions is rebuilt
 func (f *File) LookupFunction(name string) *Function {
-	if len(f.functionMap) == 0 {
+	if len(f.functionMap) == 0 && len(f.Functions) > 0 {
 		f.IndexFunctions()
 	}
 	if idx, ok := f.functionMap[name]; ok && idx < len(f.Functions) {
@@ -105,9 +139,9 @@ func (f *File) HasFunction(name string) bool {
 	return ok
 }
 
-// LookupType retrieves a type by name from the file
+// LookupType retrieves a type by name from the file, an index missing types is rebuilt
 func (f *File) LookupType(name string) *Type {
-	if len(f.typeMap) == 0 {
+	if len(f.typeMap) == 0 && len(f.Types) > 0 {
 		f.IndexTypes()
 	}
 
@@ -145,39 +179,53 @@ func (f *File) GetConstant(name string) *Constant {
 }
 
 func (f *File) IndexFunctions() {
+	f.invalidateSummary()
 	f.functionMap = make(map[string]int)
-	for _, function := range f.Functions {
+	for i, function := range f.Functions {
 		if function == nil {
 			continue
 		}
 		if _, ok := f.functionMap[function.Name]; !ok {
-			f.functionMap[function.Name] = len(f.Functions) - 1
+			f.functionMap[function.Name] = i
 		}
 	}
 
 }
 
 func (f *File) IndexTypes() {
+	f.invalidateSummary()
 	f.typeMap = make(map[string]int)
-	for _, typ := range f.Types {
+	for i, typ := range f.Types {
 		if typ == nil {
 			continue
 		}
 		if _, ok := f.typeMap[typ.Name]; !ok {
-			f.typeMap[typ.Name] = len(f.Types) - 1
+			f.typeMap[typ.Name] = i
 		}
 	}
 
 }
 
+// Asset represents a non-source package file, e.g. a configuration or fixture
 type Asset struct {
 	Name       string
 	Path       string
 	ImportPath string
-	Content    []byte
+	Content    []byte // Content of eagerly loaded assets, nil for lazily loaded assets, see Load
+	Size       int64  // Content size in bytes
+	Hash       uint64 // Content hash, zero for lazily loaded assets until loaded
+	loader     AssetLoader
 }
 
-// Content reconstructs the content of a file from its components
-func (f *File) Content(generator Emitter) ([]byte, error) {
-	return generator.Emit(f)
+// Content reconstructs the content of a file from its components, with a source map when the generator is a
+// SourceMapEmitter
+func (f *File) Content(generator Emitter) (*EmitResult, error) {
+	if mapper, ok := generator.(SourceMapEmitter); ok {
+		return mapper.EmitWithSourceMap(f)
+	}
+	content, err := generator.Emit(f)
+	if err != nil {
+		return nil, err
+	}
+	return &EmitResult{Content: content}, nil
 }
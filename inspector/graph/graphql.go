@@ -0,0 +1,348 @@
+package graph
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// graphQLName matches valid GraphQL names
+var graphQLName = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+// graphQLScalars maps Go type names to GraphQL scalars, scalars other than built-in ones are declared in the document
+var graphQLScalars = map[string]string{
+	"string": "String", "bool": "Boolean", "[]byte": "String",
+	"int": "Int", "int8": "Int", "int16": "Int", "int32": "Int", "int64": "Int", "rune": "Int",
+	"uint": "Int", "uint8": "Int", "uint16": "Int", "uint32": "Int", "uint64": "Int", "byte": "Int",
+	"float32": "Float", "float64": "Float",
+	"interface{}": "JSON", "any": "JSON", "time.Time": "Time",
+}
+
+// graphQLKinds maps kinds of named non-struct types (e.g. type UserID string) to GraphQL scalars
+var graphQLKinds = map[reflect.Kind]string{
+	reflect.String: "String", reflect.Bool: "Boolean",
+	reflect.Int: "Int", reflect.Int8: "Int", reflect.Int16: "Int", reflect.Int32: "Int", reflect.Int64: "Int",
+	reflect.Uint: "Int", reflect.Uint8: "Int", reflect.Uint16: "Int", reflect.Uint32: "Int", reflect.Uint64: "Int",
+	reflect.Float32: "Float", reflect.Float64: "Float",
+}
+
+// builtinScalars lists GraphQL scalars that need no declaration
+var builtinScalars = map[string]bool{"String": true, "Boolean": true, "Int": true, "Float": true, "ID": true}
+
+// SDLOptions controls GraphQL schema generation
+type SDLOptions struct {
+	Input bool // Generate input types named with the Input suffix instead of object types, enums are shared
+}
+
+// SDL represents a generated GraphQL schema document
+type SDL struct {
+	Document string   // Definitions ordered by scalars, enums, then object or input types, each sorted by name
+	Warnings []string // Skipped fields and types, e.g. func fields or maps with non-string keys
+}
+
+// GraphQLSDL returns a GraphQL schema of the type and the types reachable from its fields, named types are resolved
+// in the project package declaring the type
+func (t *Type) GraphQLSDL(project *Project, opts *SDLOptions) *SDL {
+	generator := newSDLGenerator(project, opts)
+	generator.root(t, generator.locate(t))
+	return generator.sdl()
+}
+
+// GenerateSDL returns a single GraphQL schema of root types (see NewTypeRef) and the types reachable from their
+// fields: structs map to object types with json tag names as field names, pointers and omitempty fields are
+// nullable, slices are lists and enum types (see Type.Enum) are enums
+func GenerateSDL(project *Project, rootTypes []Ref, opts *SDLOptions) (*SDL, error) {
+	generator := newSDLGenerator(project, opts)
+	for _, ref := range rootTypes {
+		pkg := project.lookupImport(ref.Package)
+		if pkg == nil {
+			return nil, &ErrNotFound{Kind: "package", Name: ref.Package}
+		}
+		aType, file := pkg.lookupTypeFile(ref.Type)
+		if aType == nil {
+			return nil, &ErrNotFound{Kind: "type", Name: ref.String()}
+		}
+		generator.root(aType, sdlScope{pkg: pkg, file: file})
+	}
+	return generator.sdl(), nil
+}
+
+// sdlScope is the package and file declaring a type, named field types are resolved within it
+type sdlScope struct {
+	pkg  *Package
+	file *File
+}
+
+// sdlGenerator collects GraphQL definitions of visited types
+type sdlGenerator struct {
+	project     *Project
+	opts        *SDLOptions
+	names       map[*Type]string // GraphQL names of visited types
+	taken       map[string]*Type // visited types by GraphQL name
+	scalars     map[string]bool
+	definitions map[string]string // enum, object and input definitions by GraphQL name
+	enums       map[string]bool
+	warnings    []string
+}
+
+// newSDLGenerator creates a generator, nil options generate object types
+func newSDLGenerator(project *Project, opts *SDLOptions) *sdlGenerator {
+	if opts == nil {
+		opts = &SDLOptions{}
+	}
+	if project == nil {
+		project = &Project{}
+	}
+	return &sdlGenerator{project: project, opts: opts, names: map[*Type]string{}, taken: map[string]*Type{},
+		scalars: map[string]bool{}, definitions: map[string]string{}, enums: map[string]bool{}}
+}
+
+// root defines a root type, only struct and enum types produce definitions
+func (g *sdlGenerator) root(aType *Type, scope sdlScope) {
+	if g.define(aType, scope) == "" || aType.Kind != reflect.Struct && aType.Enum == nil {
+		g.warn("%s: %s is not an object or enum type", aType.Name, aType.Kind)
+	}
+}
+
+// define returns the GraphQL name of a named type, adding its definition once; empty for unsupported types
+func (g *sdlGenerator) define(aType *Type, scope sdlScope) string {
+	if name, ok := g.names[aType]; ok {
+		return name
+	}
+	switch {
+	case aType.Enum != nil && len(aType.Enum.Values) > 0:
+		name := g.name(aType, scope, "")
+		g.names[aType] = name
+		g.enums[name] = true
+		g.definitions[name] = g.enumDefinition(aType, name)
+		return name
+	case aType.Kind == reflect.Struct:
+		keyword, suffix := "type", ""
+		if g.opts.Input {
+			keyword, suffix = "input", "Input"
+		}
+		name := g.name(aType, scope, suffix)
+		g.names[aType] = name // registered before fields for recursive types
+		builder := &strings.Builder{}
+		writeDescription(builder, "", CommentText(aType.Comment))
+		builder.WriteString(keyword + " " + name + " {\n")
+		for _, field := range g.fields(aType, scope, map[*Type]bool{aType: true}) {
+			builder.WriteString(field)
+		}
+		builder.WriteString("}\n")
+		g.definitions[name] = builder.String()
+		return name
+	}
+	scalar := graphQLKinds[aType.Kind]
+	g.names[aType] = scalar
+	return scalar
+}
+
+// name returns a unique GraphQL name of a type, types of other packages with a taken name are prefixed with their
+// package name
+func (g *sdlGenerator) name(aType *Type, scope sdlScope, suffix string) string {
+	name := strings.ReplaceAll(strings.TrimSuffix(aType.Name, AnonymousSuffix), ".", "") + suffix // nested types, e.g. Outer.Inner
+	if other, ok := g.taken[name]; ok && other != aType && scope.pkg != nil {
+		qualified := exportedName(scope.pkg.Name) + name
+		g.warn("%s: name conflicts with another type, renamed to %s", aType.Name, qualified)
+		name = qualified
+	}
+	g.taken[name] = aType
+	return name
+}
+
+// enumDefinition returns an enum definition, string values are named by their literal (the serialized value) when
+// it is a valid GraphQL name, other values by their constant name
+func (g *sdlGenerator) enumDefinition(aType *Type, name string) string {
+	builder := &strings.Builder{}
+	writeDescription(builder, "", CommentText(aType.Comment))
+	builder.WriteString("enum " + name + " {\n")
+	seen := map[string]bool{}
+	for _, value := range aType.Enum.Values {
+		valueName := value.Name
+		if literal, err := strconv.Unquote(value.Literal); err == nil && graphQLName.MatchString(literal) {
+			valueName = literal
+		}
+		if seen[valueName] || !graphQLName.MatchString(valueName) {
+			continue
+		}
+		seen[valueName] = true
+		builder.WriteString("  " + valueName + "\n")
+	}
+	builder.WriteString("}\n")
+	return builder.String()
+}
+
+// fields returns field definitions of a struct, fields of embedded structs without a json name are promoted
+func (g *sdlGenerator) fields(aType *Type, scope sdlScope, visiting map[*Type]bool) []string {
+	var result []string
+	for _, field := range aType.Fields {
+		if !field.IsExported || field.Type == nil {
+			continue
+		}
+		name, omitEmpty, skip := jsonFieldName(field)
+		if skip {
+			continue
+		}
+		if field.IsEmbedded && name == "" {
+			embedded, embeddedScope := g.lookup(strings.TrimPrefix(field.Type.Name, "*"), scope)
+			if embedded == nil || embedded.Kind != reflect.Struct || visiting[embedded] {
+				g.warn("%s.%s: embedded type %s is not supported", aType.Name, field.Name, field.Type.Name)
+				continue
+			}
+			visiting[embedded] = true
+			result = append(result, g.fields(embedded, embeddedScope, visiting)...)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if !graphQLName.MatchString(name) {
+			g.warn("%s.%s: %q is not a valid GraphQL name", aType.Name, field.Name, name)
+			continue
+		}
+		typeRef, reason := g.typeRef(field.Type.Name, scope, omitEmpty || field.Optional)
+		if reason != "" {
+			g.warn("%s.%s: %s", aType.Name, field.Name, reason)
+			continue
+		}
+		builder := &strings.Builder{}
+		writeDescription(builder, "  ", field.Comment)
+		builder.WriteString("  " + name + ": " + typeRef + "\n")
+		result = append(result, builder.String())
+	}
+	return result
+}
+
+// typeRef returns the GraphQL type of a Go type expression or the reason it is not supported
+func (g *sdlGenerator) typeRef(expr string, scope sdlScope, nullable bool) (string, string) {
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "*") {
+		return g.typeRef(expr[1:], scope, true)
+	}
+	var result string
+	switch {
+	case graphQLScalars[expr] != "":
+		result = graphQLScalars[expr]
+		if !builtinScalars[result] {
+			g.scalars[result] = true
+		}
+	case strings.HasPrefix(expr, "map["):
+		key := expr[len("map["):strings.Index(expr, "]")]
+		if key != "string" {
+			return "", "map key " + key + " is not supported"
+		}
+		result = "JSON"
+		g.scalars[result] = true
+	case strings.HasPrefix(expr, "["):
+		elem, reason := g.typeRef(expr[strings.Index(expr, "]")+1:], scope, false)
+		if reason != "" {
+			return "", reason
+		}
+		result = "[" + elem + "]"
+	case strings.HasPrefix(expr, "func") || strings.HasPrefix(expr, "chan") || strings.HasPrefix(expr, "<-chan"):
+		return "", expr + " is not supported"
+	case strings.Contains(expr, "["):
+		return "", "generic type " + expr + " is not supported"
+	default:
+		aType, typeScope := g.lookup(expr, scope)
+		if aType == nil {
+			return "", "unknown type " + expr
+		}
+		if result = g.define(aType, typeScope); result == "" {
+			return "", "type " + expr + " of kind " + aType.Kind.String() + " is not supported"
+		}
+	}
+	if !nullable {
+		result += "!"
+	}
+	return result, ""
+}
+
+// lookup returns a type by name as used in a scope, qualified names (e.g. model.User) are resolved by file imports
+func (g *sdlGenerator) lookup(name string, scope sdlScope) (*Type, sdlScope) {
+	aType, pkg, file := g.project.lookupType(name, scope.pkg, scope.file)
+	return aType, sdlScope{pkg: pkg, file: file}
+}
+
+// locate returns the project package and file declaring a type
+func (g *sdlGenerator) locate(aType *Type) sdlScope {
+	for _, pkg := range g.project.Packages {
+		for _, file := range pkg.FileSet {
+			for _, candidate := range file.Types {
+				if candidate == aType {
+					return sdlScope{pkg: pkg, file: file}
+				}
+			}
+		}
+	}
+	return sdlScope{}
+}
+
+// warn records a skipped field or type
+func (g *sdlGenerator) warn(format string, args ...interface{}) {
+	g.warnings = append(g.warnings, fmt.Sprintf(format, args...))
+}
+
+// sdl returns the document with definitions in stable order
+func (g *sdlGenerator) sdl() *SDL {
+	var scalars []string
+	for scalar := range g.scalars {
+		scalars = append(scalars, scalar)
+	}
+	sort.Strings(scalars)
+	var blocks []string
+	for _, scalar := range scalars {
+		blocks = append(blocks, "scalar "+scalar+"\n")
+	}
+	var enums, objects []string
+	for name := range g.definitions {
+		if g.enums[name] {
+			enums = append(enums, name)
+		} else {
+			objects = append(objects, name)
+		}
+	}
+	sort.Strings(enums)
+	sort.Strings(objects)
+	for _, name := range append(enums, objects...) {
+		blocks = append(blocks, g.definitions[name])
+	}
+	return &SDL{Document: strings.Join(blocks, "\n"), Warnings: g.warnings}
+}
+
+// jsonFieldName returns the json tag name of a field and whether it is omitted when empty or never serialized
+func jsonFieldName(field *Field) (name string, omitEmpty bool, skip bool) {
+	tag, ok := field.Tag.Lookup("json")
+	if !ok {
+		return "", false, false
+	}
+	if tag == "-" {
+		return "", false, true
+	}
+	parts := strings.Split(tag, ",")
+	for _, option := range parts[1:] {
+		if option == "omitempty" || option == "omitzero" {
+			omitEmpty = true
+		}
+	}
+	return parts[0], omitEmpty, false
+}
+
+// writeDescription writes a GraphQL description of the first comment sentence, nothing for empty comments
+func writeDescription(builder *strings.Builder, indent string, comment string) {
+	if text := FirstSentence(comment); text != "" {
+		builder.WriteString(indent + strconv.Quote(text) + "\n")
+	}
+}
+
+// exportedName returns a name with its first letter upper cased
+func exportedName(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
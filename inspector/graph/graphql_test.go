@@ -0,0 +1,97 @@
+package graph_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/linager/inspector/graph"
+)
+
+// TestGenerateSDL compares generated GraphQL schemas with golden files, run with UPDATE_SNAPSHOTS=1 to regenerate them
+func TestGenerateSDL(t *testing.T) {
+	status := &graph.Type{Name: "Status", Kind: reflect.String, Enum: &graph.Enum{Values: []*graph.EnumValue{
+		{Name: "StatusActive", Literal: `"active"`},
+		{Name: "StatusBlocked", Literal: `"blocked"`, Ordinal: 1},
+	}}}
+	address := &graph.Type{Name: "Address", Kind: reflect.Struct, Fields: []*graph.Field{
+		{Name: "City", Type: &graph.Type{Name: "string"}, Tag: `json:"city"`, IsExported: true},
+		{Name: "Zip", Type: &graph.Type{Name: "*string"}, Tag: `json:"zip,omitempty"`, IsExported: true},
+	}}
+	customer := &graph.Type{Name: "Customer", Kind: reflect.Struct, Comment: &graph.LocationNode{Text: "// Customer is a registered buyer. It is shared by all services."}, Fields: []*graph.Field{
+		{Name: "ID", Type: &graph.Type{Name: "int64"}, Tag: `json:"id"`, IsExported: true},
+		{Name: "Name", Type: &graph.Type{Name: "string"}, Tag: `json:"name"`, IsExported: true, Comment: "// Name is the display name"},
+		{Name: "Status", Type: &graph.Type{Name: "Status"}, Tag: `json:"status"`, IsExported: true},
+		{Name: "Nickname", Type: &graph.Type{Name: "string"}, Tag: `json:"nickname"`, IsExported: true, Optional: true},
+		{Name: "Home", Type: &graph.Type{Name: "*Address"}, Tag: `json:"home,omitempty"`, IsExported: true},
+		{Name: "Addresses", Type: &graph.Type{Name: "[]Address"}, Tag: `json:"addresses"`, IsExported: true},
+		{Name: "Tags", Type: &graph.Type{Name: "[]*string"}, Tag: `json:"tags,omitempty"`, IsExported: true},
+		{Name: "Meta", Type: &graph.Type{Name: "map[string]string"}, Tag: `json:"meta"`, IsExported: true},
+		{Name: "Created", Type: &graph.Type{Name: "time.Time"}, Tag: `json:"created"`, IsExported: true},
+		{Name: "ByID", Type: &graph.Type{Name: "map[int]string"}, IsExported: true},
+		{Name: "Hook", Type: &graph.Type{Name: "func()"}, IsExported: true},
+		{Name: "Ignored", Type: &graph.Type{Name: "string"}, Tag: `json:"-"`, IsExported: true},
+		{Name: "secret", Type: &graph.Type{Name: "string"}},
+	}}
+	category := &graph.Type{Name: "Category", Kind: reflect.Struct, Fields: []*graph.Field{
+		{Name: "Name", Type: &graph.Type{Name: "string"}, Tag: `json:"name"`, IsExported: true},
+		{Name: "Parent", Type: &graph.Type{Name: "*Category"}, Tag: `json:"parent"`, IsExported: true},
+		{Name: "Children", Type: &graph.Type{Name: "[]Category"}, Tag: `json:"children"`, IsExported: true},
+	}}
+	project := &graph.Project{Packages: []*graph.Package{{
+		Name:       "shop",
+		ImportPath: "example.com/shop",
+		FileSet: []*graph.File{
+			{Name: "customer.go", Types: []*graph.Type{status, address, customer}},
+			{Name: "category.go", Types: []*graph.Type{category}},
+		},
+	}}}
+
+	testCases := []struct {
+		golden   string
+		roots    []graph.Ref
+		options  *graph.SDLOptions
+		warnings []string
+	}{
+		{
+			golden:   "customer.graphql",
+			roots:    []graph.Ref{graph.NewTypeRef("example.com/shop", "Customer")},
+			warnings: []string{"Customer.ByID: map key int is not supported", "Customer.Hook: func() is not supported"},
+		},
+		{
+			golden:   "customer_input.graphql",
+			roots:    []graph.Ref{graph.NewTypeRef("example.com/shop", "Customer")},
+			options:  &graph.SDLOptions{Input: true},
+			warnings: []string{"Customer.ByID: map key int is not supported", "Customer.Hook: func() is not supported"},
+		},
+		{
+			golden: "category.graphql",
+			roots:  []graph.Ref{graph.NewTypeRef("example.com/shop", "Category")},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.golden, func(t *testing.T) {
+			sdl, err := graph.GenerateSDL(project, testCase.roots, testCase.options)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, testCase.warnings, sdl.Warnings)
+			goldenPath := filepath.Join("testdata", "graphql", testCase.golden)
+			if os.Getenv("UPDATE_SNAPSHOTS") == "1" {
+				assert.NoError(t, os.MkdirAll(filepath.Dir(goldenPath), 0755))
+				assert.NoError(t, os.WriteFile(goldenPath, []byte(sdl.Document), 0644))
+				return
+			}
+			expected, err := os.ReadFile(goldenPath)
+			if assert.NoError(t, err) {
+				assert.Equal(t, string(expected), sdl.Document)
+			}
+		})
+	}
+
+	assert.Equal(t, "enum Status {\n  active\n  blocked\n}\n", status.GraphQLSDL(project, nil).Document)
+	_, err := graph.GenerateSDL(project, []graph.Ref{graph.NewTypeRef("example.com/shop", "Missing")}, nil)
+	assert.ErrorIs(t, err, &graph.ErrNotFound{Kind: "type"})
+}
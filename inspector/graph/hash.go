@@ -1,6 +1,8 @@
 package graph
 
 import (
+	"encoding/json"
+
 	"github.com/minio/highwayhash"
 )
 
@@ -14,3 +16,12 @@ func Hash(data []byte) (uint64, error) {
 	_, err = hash.Write(data)
 	return hash.Sum64(), err
 }
+
+// Fingerprint returns a hash of the project JSON representation, equal fingerprints indicate identical projects
+func (p *Project) Fingerprint() (uint64, error) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return 0, err
+	}
+	return Hash(data)
+}
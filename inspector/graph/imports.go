@@ -0,0 +1,185 @@
+package graph
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// NormalizeImports fixes Go imports of a file assembled from several sources: imports of the same path are merged
+// keeping the default package name unless another import claims it, in which case the first alias is kept and
+// references of dropped aliases are rewritten to the kept name; imports not referenced by type names, signatures or
+// declaration texts are dropped (blank and dot imports are kept); the remaining imports are ordered standard library
+// first, then others, each group by path
+func (f *File) NormalizeImports() {
+	var paths []string
+	byPath := map[string][]Import{}
+	for _, imp := range f.Imports {
+		if _, ok := byPath[imp.Path]; !ok {
+			paths = append(paths, imp.Path)
+		}
+		byPath[imp.Path] = append(byPath[imp.Path], imp)
+	}
+	claimed := map[string]string{} // default local name -> path
+	for _, importPath := range paths {
+		for _, imp := range byPath[importPath] {
+			if imp.Name != "_" && imp.Name != "." {
+				if _, ok := claimed[imp.LocalName()]; !ok {
+					claimed[imp.LocalName()] = importPath
+				}
+			}
+		}
+	}
+	var merged []Import
+	for _, importPath := range paths {
+		var named []Import
+		var special []Import
+		for _, imp := range byPath[importPath] {
+			switch imp.Name {
+			case "_", ".":
+				if !containsImport(special, imp) {
+					special = append(special, imp)
+				}
+			default:
+				named = append(named, imp)
+			}
+		}
+		if len(named) == 0 {
+			merged = append(merged, special...)
+			continue
+		}
+		kept := named[0] // a single import keeps its recorded form
+		if len(named) > 1 {
+			defaultName := Import{Path: importPath}.LocalName()
+			if owner, ok := claimed[defaultName]; !ok || owner == importPath {
+				kept = Import{Path: importPath}
+			} else {
+				for _, imp := range named {
+					if imp.LocalName() != defaultName {
+						kept = imp
+						break
+					}
+				}
+			}
+			for _, imp := range named {
+				// names claimed by another path refer to that path
+				if imp.LocalName() != kept.LocalName() && claimed[imp.LocalName()] == importPath {
+					f.rewriteTexts(qualifierRewriter(imp.LocalName(), kept.LocalName()))
+				}
+			}
+		}
+		merged = append(merged, kept)
+		merged = append(merged, special...)
+	}
+	texts := f.collectTexts()
+	var result []Import
+	for _, imp := range merged {
+		if imp.Name == "_" || imp.Name == "." || imp.Path == "C" || qualifierUsed(texts, imp.LocalName()) {
+			result = append(result, imp)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		iStd, jStd := IsStdImport(result[i].Path), IsStdImport(result[j].Path)
+		if iStd != jStd {
+			return iStd
+		}
+		return result[i].Path < result[j].Path
+	})
+	f.Imports = result
+	f.invalidateSummary()
+}
+
+// IsStdImport reports whether a Go import path belongs to the standard library, i.e. its first element has no dot
+func IsStdImport(importPath string) bool {
+	first, _, _ := strings.Cut(importPath, "/")
+	return !strings.Contains(first, ".")
+}
+
+// containsImport reports whether imports contain an import of the same name and path
+func containsImport(imports []Import, imp Import) bool {
+	for _, candidate := range imports {
+		if candidate.Name == imp.Name && candidate.Path == imp.Path {
+			return true
+		}
+	}
+	return false
+}
+
+// qualifierRewriter returns a function replacing package qualifiers, e.g. j.Marshal -> json.Marshal
+func qualifierRewriter(name, newName string) func(text string) string {
+	expr := regexp.MustCompile(`(^|[^.\w])` + regexp.QuoteMeta(name) + `\.`)
+	return func(text string) string {
+		return expr.ReplaceAllString(text, "${1}"+newName+".")
+	}
+}
+
+// qualifierUsed reports whether any text selects from a package qualifier, e.g. json.Marshal
+func qualifierUsed(texts []string, name string) bool {
+	expr := regexp.MustCompile(`(^|[^.\w])` + regexp.QuoteMeta(name) + `\.`)
+	for _, text := range texts {
+		if expr.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// collectTexts returns declaration texts and type names of a file
+func (f *File) collectTexts() []string {
+	var result []string
+	f.rewriteTexts(func(text string) string {
+		result = append(result, text)
+		return text
+	})
+	return result
+}
+
+// rewriteTexts applies rewrite to declaration texts, signatures, bodies and type names of a file
+func (f *File) rewriteTexts(rewrite func(text string) string) {
+	location := func(location *Location) {
+		if location != nil && location.Raw != "" {
+			location.Raw = rewrite(location.Raw)
+		}
+	}
+	typeName := func(aType *Type) {
+		if aType != nil {
+			aType.Name = rewrite(aType.Name)
+		}
+	}
+	function := func(function *Function) {
+		location(function.Location)
+		function.Signature = rewrite(function.Signature)
+		if function.Body != nil {
+			function.Body.Text = rewrite(function.Body.Text)
+		}
+		for _, params := range [][]*Parameter{function.Parameters, function.Results} {
+			for _, param := range params {
+				typeName(param.Type)
+			}
+		}
+	}
+	for _, constant := range f.Constants {
+		location(constant.Location)
+		constant.Value = rewrite(constant.Value)
+		typeName(constant.Type)
+	}
+	for _, variable := range f.Variables {
+		location(variable.Location)
+		variable.Value = rewrite(variable.Value)
+		typeName(variable.Type)
+	}
+	for _, aType := range f.Types {
+		location(aType.Location)
+		for _, field := range aType.Fields {
+			location(field.Location)
+			typeName(field.Type)
+			field.Default = rewrite(field.Default)
+		}
+		for _, method := range aType.Methods {
+			function(method)
+		}
+	}
+	for _, fn := range f.Functions {
+		function(fn)
+	}
+}
@@ -0,0 +1,146 @@
+package graph
+
+import (
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// genericExpr matches instantiated generic type expressions, e.g. Stack[T] or Pair[K, V]
+var genericExpr = regexp.MustCompile(`([A-Za-z_][A-Za-z0-9_]*)\[([^\[\]]*)\]`)
+
+// Instantiation describes type arguments of a generic type or function observed in code
+type Instantiation struct {
+	Package  string   `json:",omitempty"` // Import path of the generic declaration, empty for the declaring package
+	Name     string   // Generic type or function name
+	TypeArgs []string // Concrete type arguments, e.g. [string]
+	File     string   `json:",omitempty"`
+	Line     int      `json:",omitempty"`
+}
+
+// String returns the instantiated name, e.g. Stack[string]
+func (i *Instantiation) String() string {
+	return i.Name + "[" + strings.Join(i.TypeArgs, ", ") + "]"
+}
+
+// Instantiations returns observed instantiations of project generic types keyed by package import path and type name
+// (e.g. myapp/stack.Stack) with locations in project files; instantiations of generic functions are mapped to the generic types of their results
+func (p *Project) Instantiations() map[string][]*Instantiation {
+	result := map[string][]*Instantiation{}
+	seen := map[string]bool{}
+	add := func(pkg *Package, typeName string, args []string, file *File, observed *Instantiation) {
+		aType := pkg.lookupType(typeName)
+		if aType == nil || len(aType.TypeParams) != len(args) {
+			return
+		}
+		key := pkg.ImportPath + "." + typeName
+		instance := &Instantiation{Package: pkg.ImportPath, Name: typeName, TypeArgs: args, File: file.Path, Line: observed.Line}
+		if id := key + instance.String() + "@" + file.Path + ":" + strconv.Itoa(observed.Line); !seen[id] {
+			seen[id] = true
+			result[key] = append(result[key], instance)
+		}
+	}
+	for _, pkg := range p.Packages {
+		for _, file := range pkg.FileSet {
+			for _, observed := range file.Instantiations {
+				declaring := pkg
+				if observed.Package != "" {
+					if declaring = p.lookupImport(observed.Package); declaring == nil {
+						continue
+					}
+				}
+				if function := declaring.lookupFunction(observed.Name); function != nil && len(function.TypeParams) == len(observed.TypeArgs) {
+					// e.g. New[string]() returning *Stack[T] instantiates Stack[string]
+					bindings := map[string]string{}
+					for i, param := range function.TypeParams {
+						bindings[param.Name] = observed.TypeArgs[i]
+					}
+					for _, result := range function.Results {
+						if result.Type == nil {
+							continue
+						}
+						for _, match := range genericExpr.FindAllStringSubmatch(result.Type.Name, -1) {
+							var args []string
+							for _, arg := range strings.Split(match[2], ",") {
+								arg = strings.TrimSpace(arg)
+								if bound, ok := bindings[arg]; ok {
+									arg = bound
+								}
+								args = append(args, arg)
+							}
+							add(declaring, match[1], args, file, observed)
+						}
+					}
+					continue
+				}
+				add(declaring, observed.Name, observed.TypeArgs, file, observed)
+			}
+		}
+	}
+	for _, instances := range result {
+		sort.SliceStable(instances, func(i, j int) bool {
+			if left, right := instances[i].String(), instances[j].String(); left != right {
+				return left < right
+			}
+			if instances[i].File != instances[j].File {
+				return instances[i].File < instances[j].File
+			}
+			return instances[i].Line < instances[j].Line
+		})
+	}
+	return result
+}
+
+// lookupImport returns the project package of an import path, matching the import path, its suffix or the package name
+func (p *Project) lookupImport(importPath string) *Package {
+	var byName *Package
+	for _, pkg := range p.Packages {
+		switch {
+		case pkg.ImportPath == importPath:
+			return pkg
+		case strings.HasSuffix(importPath, "/"+pkg.ImportPath) || strings.HasSuffix(pkg.ImportPath, "/"+importPath):
+			return pkg
+		case byName == nil && pkg.Name == path.Base(importPath):
+			byName = pkg
+		}
+	}
+	return byName
+}
+
+// lookupType returns a package type by name
+func (p *Package) lookupType(name string) *Type {
+	for _, file := range p.FileSet {
+		if aType := file.LookupType(name); aType != nil {
+			return aType
+		}
+	}
+	return nil
+}
+
+// lookupFunction returns a package function by name
+func (p *Package) lookupFunction(name string) *Function {
+	for _, file := range p.FileSet {
+		if function := file.LookupFunction(name); function != nil {
+			return function
+		}
+	}
+	return nil
+}
+
+// instantiationNote returns a note listing distinct known instantiations of a type, empty if there are none
+func instantiationNote(instances []*Instantiation) string {
+	var names []string
+	seen := map[string]bool{}
+	for _, instance := range instances {
+		if name := instance.String(); !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	return "\n// Known instantiations: " + strings.Join(names, ", ") + "\n"
+}
@@ -0,0 +1,44 @@
+package graph
+
+import "time"
+
+// DefaultParseTimeout is the default maximum tree-sitter parse time of a source file
+const DefaultParseTimeout = 30 * time.Second
+
+// DefaultMaxNodes is the default maximum number of tree-sitter nodes visited by traversals of a source file
+const DefaultMaxNodes = 1 << 20
+
+// Parse warning reasons
+const (
+	WarnReasonTimeout   = "timeout"    // parsing exceeded the configured ParseTimeout, the file holds no declarations
+	WarnReasonNodeLimit = "node limit" // traversals exceeded the configured MaxNodes, later nodes were not inspected
+)
+
+// ParseWarning describes a source file inspected partially
+type ParseWarning struct {
+	Path    string `json:"path"`
+	Reason  string `json:"reason"`
+	Message string `json:"message,omitempty"`
+}
+
+// ParseDeadline returns the maximum parse time of a source file, 0 when unlimited
+func (c *Config) ParseDeadline() time.Duration {
+	switch {
+	case c == nil || c.ParseTimeout == 0:
+		return DefaultParseTimeout
+	case c.ParseTimeout < 0:
+		return 0
+	}
+	return c.ParseTimeout
+}
+
+// NodeLimit returns the maximum number of nodes visited by traversals of a source file, 0 when unlimited
+func (c *Config) NodeLimit() int {
+	switch {
+	case c == nil || c.MaxNodes == 0:
+		return DefaultMaxNodes
+	case c.MaxNodes < 0:
+		return 0
+	}
+	return c.MaxNodes
+}
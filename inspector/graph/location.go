@@ -4,4 +4,5 @@ type Location struct {
 	Start int // Start position in the source code
 	End   int // End position in the source code
 	Raw   string
+	Stale bool `json:",omitempty"` // Whether the location text was not found in the source, see File.Rebase
 }
@@ -0,0 +1,78 @@
+package graph
+
+// Java module descriptor file names
+const (
+	ModuleInfoFile  = "module-info.java"
+	PackageInfoFile = "package-info.java"
+)
+
+// Module represents a Java module declared by module-info.java
+type Module struct {
+	Name     string
+	Path     string // Module descriptor path
+	Open     bool   // Whether all packages are open to reflection
+	Comment  *LocationNode
+	Requires []*ModuleRequire
+	Exports  []*ModuleExport
+	Opens    []*ModuleExport
+	Uses     []string // Service types used by the module
+	Provides []*ModuleProvide
+}
+
+// ModuleRequire is a module dependency
+type ModuleRequire struct {
+	Module     string
+	Transitive bool // Whether modules reading this module read the dependency too
+	Static     bool // Whether the dependency is required at compile time only
+}
+
+// ModuleExport is a package exported or opened by a module, to all modules unless To lists qualified target modules
+type ModuleExport struct {
+	Package string
+	To      []string
+}
+
+// ModuleProvide is a service implementation provided by a module
+type ModuleProvide struct {
+	Service string
+	With    []string // Implementation types
+}
+
+// LookupModule returns a project module by name
+func (p *Project) LookupModule(name string) *Module {
+	for _, module := range p.Modules {
+		if module.Name == name {
+			return module
+		}
+	}
+	return nil
+}
+
+// RequiresModule reports whether the module declares a dependency on another module
+func (m *Module) RequiresModule(name string) bool {
+	for _, require := range m.Requires {
+		if require.Module == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ExportsPackage reports whether a package of the module is readable by another module: exported to all modules or,
+// by a qualified export, to the module
+func (m *Module) ExportsPackage(pkg, module string) bool {
+	for _, export := range m.Exports {
+		if export.Package != pkg {
+			continue
+		}
+		if len(export.To) == 0 {
+			return true
+		}
+		for _, target := range export.To {
+			if target == module {
+				return true
+			}
+		}
+	}
+	return false
+}
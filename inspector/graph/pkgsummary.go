@@ -0,0 +1,160 @@
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultSummaryBudget is the token budget of package summary documents when DocumentOptions.SummaryBudget is not set
+const DefaultSummaryBudget = 512
+
+// summarySection lists lines of a package summary section, sections are filled in priority order
+type summarySection struct {
+	title string // Section heading, empty for the package comment
+	name  string // Omitted element name used by the truncation marker, e.g. types
+	lines []string
+}
+
+// PackageSummary returns a single compact document describing a package for coarse retrieval: a metrics header, the
+// package comment, exported types with one line descriptions, exported function signatures and imports ordered by
+// the number of files using them. Sections are filled in that priority order within a token budget counted by
+// DocumentTokenCounter (DefaultSummaryBudget when budget is not positive); lines that do not fit, and all lines of
+// lower priority sections, are replaced by a truncation marker, e.g. // … omitted: 12 functions, 4 dependencies
+func (p *Project) PackageSummary(pkg *Package, budget int) *Document {
+	if budget <= 0 {
+		budget = DefaultSummaryBudget
+	}
+	sections := packageSummarySections(pkg)
+	header := packageSummaryHeader(pkg)
+
+	var reserve []string // the longest marker is reserved so that a truncated summary stays within the budget
+	for _, section := range sections {
+		if len(section.lines) > 0 {
+			reserve = append(reserve, fmt.Sprintf("%d %s", len(section.lines), section.name))
+		}
+	}
+	reserved := DocumentTokenCounter(truncationMarker(reserve))
+
+	builder := &strings.Builder{}
+	builder.WriteString(header)
+	var omitted []string
+	truncated := false
+	for _, section := range sections {
+		for i, line := range section.lines {
+			text := line + "\n"
+			if i == 0 && section.title != "" {
+				text = "\n" + section.title + ":\n" + text
+			}
+			if !truncated && DocumentTokenCounter(builder.String()+text)+reserved <= budget {
+				builder.WriteString(text)
+				continue
+			}
+			truncated = true
+			omitted = append(omitted, fmt.Sprintf("%d %s", len(section.lines)-i, section.name))
+			break
+		}
+	}
+	if len(omitted) > 0 {
+		builder.WriteString(truncationMarker(omitted))
+	}
+
+	doc := &Document{
+		ID:      string(KindPackageSummary) + ":" + pkg.ImportPath,
+		Kind:    KindPackageSummary,
+		Project: p.Name,
+		Package: pkg.Name,
+		Path:    pkg.ImportPath,
+		Name:    pkg.Name,
+		Owners:  pkg.Owners,
+		Content: builder.String(),
+	}
+	doc.Hash = doc.HashContent()
+	return doc
+}
+
+// truncationMarker returns the line replacing omitted summary lines
+func truncationMarker(omitted []string) string {
+	return "// … omitted: " + strings.Join(omitted, ", ") + "\n"
+}
+
+// packageSummaryHeader returns the metrics line of a package summary
+func packageSummaryHeader(pkg *Package) string {
+	lines, types, functions, methods := 0, 0, 0, 0
+	for _, file := range pkg.FileSet {
+		summary := file.Summary()
+		lines += summary.Lines
+		types += len(summary.Types)
+		functions += len(summary.Functions)
+		methods += len(summary.Methods)
+	}
+	return fmt.Sprintf("// Package %s (%s): %d files, %d lines, %d types, %d functions, %d methods\n",
+		pkg.Name, pkg.ImportPath, len(pkg.FileSet), lines, types, functions, methods)
+}
+
+// packageSummarySections returns package summary sections in priority order: comment, types, functions, dependencies
+func packageSummarySections(pkg *Package) []*summarySection {
+	comment := &summarySection{name: "comment lines"}
+	types := &summarySection{title: "Types", name: "types"}
+	functions := &summarySection{title: "Functions", name: "functions"}
+	dependencies := &summarySection{title: "Dependencies", name: "dependencies"}
+
+	text := ""
+	if pkg.Comment != nil {
+		text = pkg.Comment.Text
+	}
+	imports := map[string]int{}
+	for _, file := range pkg.FileSet {
+		if text == "" {
+			text = file.Doc
+		}
+		for _, aType := range file.Types {
+			if aType == nil || !aType.IsExported || aType.Anonymous {
+				continue
+			}
+			line := "- " + aType.Name
+			if aType.Comment != nil {
+				if description := FirstSentence(aType.Comment.Text); description != "" {
+					line += ": " + description
+				}
+			}
+			types.lines = append(types.lines, line)
+		}
+		for _, function := range file.Functions {
+			if function == nil || !function.IsExported || function.Receiver != "" {
+				continue
+			}
+			signature := function.Signature
+			if signature == "" {
+				signature = function.Name
+			}
+			functions.lines = append(functions.lines, "- "+strings.Join(strings.Fields(signature), " "))
+		}
+		seen := map[string]bool{}
+		for _, imp := range file.Imports {
+			if !seen[imp.Path] {
+				seen[imp.Path] = true
+				imports[imp.Path]++
+			}
+		}
+	}
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			comment.lines = append(comment.lines, "// "+line)
+		}
+	}
+	paths := make([]string, 0, len(imports))
+	for path := range imports {
+		paths = append(paths, path)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		if imports[paths[i]] != imports[paths[j]] {
+			return imports[paths[i]] > imports[paths[j]]
+		}
+		return paths[i] < paths[j]
+	})
+	for _, path := range paths {
+		dependencies.lines = append(dependencies.lines, fmt.Sprintf("- %s (%d files)", path, imports[path]))
+	}
+	return []*summarySection{comment, types, functions, dependencies}
+}
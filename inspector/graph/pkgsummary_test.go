@@ -0,0 +1,68 @@
+package graph_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/linager/inspector/golang"
+	"github.com/viant/linager/inspector/graph"
+)
+
+// TestProject_PackageSummary checks budget enforcement and priority truncation of a package with many symbols
+func TestProject_PackageSummary(t *testing.T) {
+	src := &strings.Builder{}
+	src.WriteString("// Package shop manages orders.\n// It prices carts and ships parcels.\npackage shop\n\nimport (\n\t\"fmt\"\n\t\"strings\"\n)\n\n")
+	for i := 0; i < 30; i++ {
+		fmt.Fprintf(src, "// Item%d is a catalog item number %d.\ntype Item%d struct {\n\tName string\n}\n\n", i, i, i)
+	}
+	for i := 0; i < 40; i++ {
+		fmt.Fprintf(src, "// Price%d prices an item\nfunc Price%d(name string, quantity int) (float64, error) {\n\treturn 0, fmt.Errorf(strings.ToUpper(name))\n}\n\n", i, i)
+	}
+	file, err := golang.NewInspector(&graph.Config{}).InspectSource([]byte(src.String()))
+	if !assert.NoError(t, err) {
+		return
+	}
+	file.Path = "/shop/shop.go"
+	pkg := &graph.Package{Name: "shop", ImportPath: "/shop", FileSet: []*graph.File{file}}
+	project := &graph.Project{Name: "shop", Packages: []*graph.Package{pkg}}
+
+	full := project.PackageSummary(pkg, 100000)
+	assert.Equal(t, "PackageSummary:/shop", full.GetID())
+	assert.True(t, strings.HasPrefix(full.Content, "// Package shop (/shop): 1 files, "), full.Content)
+	assert.Contains(t, full.Content, "// It prices carts and ships parcels.\n")
+	assert.Contains(t, full.Content, "- Item29: Item29 is a catalog item number 29.\n")
+	assert.Contains(t, full.Content, "- func Price39(name string, quantity int) (float64, error)\n")
+	assert.Contains(t, full.Content, "- fmt (1 files)\n")
+	assert.NotContains(t, full.Content, "omitted")
+
+	for _, budget := range []int{60, 200, 400} {
+		summary := project.PackageSummary(pkg, budget)
+		assert.LessOrEqual(t, summary.Tokens(), budget, budget)
+		assert.Contains(t, summary.Content, "// Package shop manages orders.", "comment has the highest priority")
+		assert.Contains(t, summary.Content, "// … omitted: ", budget)
+		assert.Contains(t, summary.Content, "40 functions, 2 dependencies\n", "lower priority sections are omitted first")
+		assert.NotContains(t, summary.Content, "- func Price0", budget)
+	}
+	assert.Contains(t, project.PackageSummary(pkg, 60).Content, "omitted: 30 types, 40 functions")
+	partial := project.PackageSummary(pkg, 200).Content
+	assert.Contains(t, partial, "- Item0: Item0 is a catalog item number 0.\n", "types fill the budget before functions")
+	assert.NotContains(t, partial, "- Item29")
+
+	project.SetDocumentOptions(graph.DocumentOptions{PackageSummaries: true, SummaryBudget: 200})
+	documents, err := project.CreateDocuments(context.Background(), "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	var summaries []*graph.Document
+	for _, doc := range documents {
+		if doc.Kind == graph.KindPackageSummary {
+			summaries = append(summaries, doc)
+		}
+	}
+	if assert.Len(t, summaries, 1) {
+		assert.Equal(t, partial, summaries[0].Content)
+	}
+}
@@ -12,23 +12,79 @@ type Project struct {
 	RootPath      string
 	RepositoryURL string
 	Packages      []*Package
-	packageMap    map[string]int //position
+	SkippedFiles  []*SkippedFile  // Source files omitted as oversized or binary
+	Modules       []*Module       // Java modules declared by module-info.java
+	Tables        []*Table        // Storage tables mapped by model structs, see MapModels
+	packageMap    map[string]int  //position
+	redactor      *Redactor       // Redactor applied to created documents, see Redact
+	documents     DocumentOptions // Synthesized sections of created documents, see SetDocumentOptions
+	cow           *copyOnWrite    // Packages and files shared by Clone
 }
 
-// GetPackage retrieves a constant by name from the file
+// GetPackage retrieves a package by name, projects not indexed with IndexPackages are scanned
 func (p *Project) GetPackage(name string) *Package {
-	if p.Packages == nil {
+	if p.packageMap == nil {
+		for _, pkg := range p.Packages {
+			if pkg.Name == name {
+				return pkg
+			}
+		}
 		return nil
 	}
-	if idx, ok := p.packageMap[name]; ok && idx < len(p.Packages) {
+	if idx, ok := p.packageMap[name]; ok && idx < len(p.Packages) && p.Packages[idx].Name == name {
 		return p.Packages[idx]
 	}
 	return nil
 }
 
+// IndexPackages rebuilds the package name index used by GetPackage, it has to be called whenever Packages change
+func (p *Project) IndexPackages() {
+	p.packageMap = make(map[string]int, len(p.Packages))
+	for i, pkg := range p.Packages {
+		if _, ok := p.packageMap[pkg.Name]; !ok {
+			p.packageMap[pkg.Name] = i
+		}
+	}
+}
+
+// OwnershipRules resolves owners for a path relative to the project root
+type OwnershipRules interface {
+	Owners(path string) []string
+}
+
+// ApplyOwnership annotates packages and files with owners matching their path relative to the project root
+func (p *Project) ApplyOwnership(rules OwnershipRules) {
+	for _, pkg := range p.Packages {
+		pkg.Owners = nil
+		for _, file := range pkg.FileSet {
+			relPath := p.relativePath(file.Path)
+			file.Owners = rules.Owners(relPath)
+			if pkg.Owners == nil {
+				pkg.Owners = rules.Owners(filepath.ToSlash(filepath.Dir(relPath)) + "/")
+			}
+		}
+		if pkg.Owners == nil && len(pkg.Assets) > 0 {
+			pkg.Owners = rules.Owners(filepath.ToSlash(filepath.Dir(p.relativePath(pkg.Assets[0].Path))) + "/")
+		}
+	}
+}
+
+// relativePath returns slash separated path relative to the project root
+func (p *Project) relativePath(path string) string {
+	if p.RootPath != "" && filepath.IsAbs(path) {
+		if relPath, err := filepath.Rel(p.RootPath, path); err == nil {
+			path = relPath
+		}
+	}
+	return filepath.ToSlash(path)
+}
+
 func (p *Project) Init() {
+	p.IndexPackages()
+	p.collectSkippedFiles()
 	p.adjustRelativePath()
 	p.adjustPackageTypes()
+	p.LinkValueRefs()
 }
 
 // AdjustRelativePath initializes project-related properties, including updating file paths to be relative to project root
@@ -98,3 +154,13 @@ func (p *Project) adjustPackageTypes() {
 		}
 	}
 }
+
+// collectSkippedFiles gathers files skipped by package inspection with paths relative to the project root
+func (p *Project) collectSkippedFiles() {
+	p.SkippedFiles = nil
+	for _, pkg := range p.Packages {
+		for _, skipped := range pkg.Skipped {
+			p.SkippedFiles = append(p.SkippedFiles, &SkippedFile{Path: p.relativePath(skipped.Path), Size: skipped.Size, Reason: skipped.Reason})
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package graph_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/linager/inspector/graph"
+	"testing"
+)
+
+func TestProject_GetPackage(t *testing.T) {
+	api, store := &graph.Package{Name: "api"}, &graph.Package{Name: "store"}
+
+	assembled := &graph.Project{Packages: []*graph.Package{api, store}}
+	assert.Same(t, store, assembled.GetPackage("store"), "unindexed project")
+	assert.Nil(t, assembled.GetPackage("model"), "unindexed project")
+
+	project := &graph.Project{Packages: []*graph.Package{api}}
+	project.Init()
+	assert.Same(t, api, project.GetPackage("api"))
+	assert.Nil(t, project.GetPackage("store"))
+
+	project.AddPackage(store)
+	assert.Same(t, store, project.GetPackage("store"), "added package")
+
+	clone := project.Clone()
+	model := &graph.Package{Name: "model"}
+	clone.AddPackage(model)
+	assert.Same(t, model, clone.GetPackage("model"), "package added to clone")
+	assert.Nil(t, project.GetPackage("model"), "clone index is not shared")
+
+	project.Packages = project.Packages[1:]
+	project.IndexPackages()
+	assert.Nil(t, project.GetPackage("api"), "reindexed project")
+	assert.Same(t, store, project.GetPackage("store"), "reindexed project")
+}
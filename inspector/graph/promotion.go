@@ -0,0 +1,288 @@
+package graph
+
+import (
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// maxEmbeddingDepth caps embedded type chains followed for promoted methods and embedded interfaces
+const maxEmbeddingDepth = 8
+
+// PromotedMethod is a method of an embedded type callable on the embedding struct
+type PromotedMethod struct {
+	Method   *Function
+	Provider string // Embedded type declaring the method as written in the embedding struct, e.g. *conn
+}
+
+// embedding is an embedded type with the package and file resolving its names
+type embedding struct {
+	aType    *Type
+	pkg      *Package
+	file     *File
+	provider string
+}
+
+// PromotedMethods returns methods promoted to a struct of a package from embedded project types, sorted by name;
+// fields and methods of the struct or of a shallower embedded type shadow deeper ones and methods provided by two
+// types at the same depth are ambiguous and omitted, as in Go
+func (p *Project) PromotedMethods(pkg *Package, aType *Type) []*PromotedMethod {
+	if aType == nil || aType.Kind == reflect.Interface {
+		return nil
+	}
+	shadowed := map[string]bool{}
+	for _, method := range pkg.typeMethods(aType.Name) {
+		shadowed[method.Name] = true
+	}
+	visited := map[*Type]bool{aType: true}
+	current := p.embeddings(aType, pkg, pkg.declaringFile(aType), shadowed, visited)
+	var result []*PromotedMethod
+	for depth := 0; len(current) > 0 && depth < maxEmbeddingDepth; depth++ {
+		provided := map[string][]*PromotedMethod{}
+		fields := map[string]bool{}
+		var next []*embedding
+		for _, embedded := range current {
+			for _, method := range embedded.pkg.typeMethods(embedded.aType.Name) {
+				provided[method.Name] = append(provided[method.Name], &PromotedMethod{Method: method, Provider: embedded.provider})
+			}
+			next = append(next, p.embeddings(embedded.aType, embedded.pkg, embedded.file, fields, visited)...)
+		}
+		for name, candidates := range provided {
+			if !shadowed[name] && len(candidates) == 1 {
+				result = append(result, candidates[0])
+			}
+		}
+		for name := range provided {
+			shadowed[name] = true
+		}
+		for name := range fields {
+			shadowed[name] = true
+		}
+		current = next
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Method.Name < result[j].Method.Name })
+	return result
+}
+
+// synthesizedNotes returns the promoted methods and satisfied interfaces sections of a type document with the names
+// of included sections, each section starts with a "// Synthesized:" marker line
+func (p *Project) synthesizedNotes(pkg *Package, aType *Type) (string, []string) {
+	builder := &strings.Builder{}
+	var sections []string
+	if !p.documents.SkipPromotedMethods {
+		if promoted := p.PromotedMethods(pkg, aType); len(promoted) > 0 {
+			sections = append(sections, SectionPromotedMethods)
+			builder.WriteString("\n// Synthesized: promoted methods\n")
+			for _, method := range promoted {
+				signature := method.Method.Signature
+				if signature == "" {
+					signature = method.Method.Name
+				}
+				builder.WriteString("// " + signature + " (from " + method.Provider + ")\n")
+			}
+		}
+	}
+	if !p.documents.SkipSatisfies {
+		if interfaces := p.Satisfies(pkg, aType); len(interfaces) > 0 {
+			sections = append(sections, SectionSatisfies)
+			builder.WriteString("\n// Synthesized: satisfies " + strings.Join(interfaces, ", ") + "\n")
+		}
+	}
+	return builder.String(), sections
+}
+
+// embeddings resolves project types embedded by a struct, names of its fields are added to fields
+func (p *Project) embeddings(aType *Type, pkg *Package, file *File, fields map[string]bool, visited map[*Type]bool) []*embedding {
+	var result []*embedding
+	for _, field := range aType.Fields {
+		fields[field.Name] = true
+		if !field.IsEmbedded || field.Type == nil {
+			continue
+		}
+		embedded, embeddedPkg, embeddedFile := p.lookupType(strings.TrimPrefix(field.Type.Name, "*"), pkg, file)
+		if embedded == nil || visited[embedded] {
+			continue
+		}
+		visited[embedded] = true
+		provider := field.Type.Name
+		if field.Type.IsPointer && !strings.HasPrefix(provider, "*") {
+			provider = "*" + provider
+		}
+		result = append(result, &embedding{aType: embedded, pkg: embeddedPkg, file: embeddedFile, provider: provider})
+	}
+	return result
+}
+
+// Satisfies returns project interfaces implemented by a type, sorted: interfaces the type declares to implement
+// (e.g. Java implements clauses) and interfaces whose methods, embedded interfaces included, are all declared or
+// promoted (see PromotedMethods) with the same parameter and result types. Interfaces of other packages are qualified
+// by package name, interfaces without methods are omitted.
+func (p *Project) Satisfies(pkg *Package, aType *Type) []string {
+	if aType == nil || aType.Kind == reflect.Interface {
+		return nil
+	}
+	names := map[string]bool{}
+	for _, name := range aType.Implements {
+		names[name] = true
+	}
+	methods := map[string]*Function{}
+	for _, method := range pkg.typeMethods(aType.Name) {
+		methods[method.Name] = method
+	}
+	for _, promoted := range p.PromotedMethods(pkg, aType) {
+		methods[promoted.Method.Name] = promoted.Method
+	}
+	for _, candidatePkg := range p.Packages {
+		for _, file := range candidatePkg.FileSet {
+			for _, candidate := range file.Types {
+				if candidate.Kind != reflect.Interface || candidate == aType {
+					continue
+				}
+				required := p.interfaceMethods(candidate, candidatePkg, file, map[*Type]bool{}, 0)
+				if len(required) == 0 || !hasMethods(methods, required) {
+					continue
+				}
+				name := candidate.Name
+				if candidatePkg != pkg {
+					name = candidatePkg.Name + "." + name
+				}
+				names[name] = true
+			}
+		}
+	}
+	result := make([]string, 0, len(names))
+	for name := range names {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// interfaceMethods returns methods of an interface including methods of embedded project interfaces (Extends)
+func (p *Project) interfaceMethods(iface *Type, pkg *Package, file *File, visited map[*Type]bool, depth int) []*Function {
+	if visited[iface] || depth > maxEmbeddingDepth {
+		return nil
+	}
+	visited[iface] = true
+	result := append([]*Function{}, iface.Methods...)
+	for _, name := range iface.Extends {
+		embedded, embeddedPkg, embeddedFile := p.lookupType(name, pkg, file)
+		if embedded == nil || embedded.Kind != reflect.Interface {
+			continue
+		}
+		result = append(result, p.interfaceMethods(embedded, embeddedPkg, embeddedFile, visited, depth+1)...)
+	}
+	return result
+}
+
+// hasMethods reports whether a method set has all required methods with matching parameter and result types
+func hasMethods(methods map[string]*Function, required []*Function) bool {
+	for _, method := range required {
+		candidate, ok := methods[method.Name]
+		if !ok || methodShape(candidate) != methodShape(method) {
+			return false
+		}
+	}
+	return true
+}
+
+// methodShape returns parameter and result types of a method, e.g. ([]byte)(int,error)
+func methodShape(method *Function) string {
+	types := func(params []*Parameter) string {
+		var result []string
+		for _, param := range params {
+			name := ""
+			if param.Type != nil {
+				name = param.Type.Name
+			}
+			if param.IsVariadic {
+				name = "..." + name
+			}
+			result = append(result, name)
+		}
+		return "(" + strings.Join(result, ",") + ")"
+	}
+	return types(method.Parameters) + types(method.Results)
+}
+
+// lookupType returns a type by name as used in a package file with the package and file declaring it, qualified
+// names (e.g. model.User) are resolved by file imports
+func (p *Project) lookupType(name string, pkg *Package, file *File) (*Type, *Package, *File) {
+	if aType, typeFile := pkg.lookupTypeFile(name); aType != nil {
+		return aType, pkg, typeFile
+	}
+	qualifier, typeName, ok := strings.Cut(name, ".")
+	if !ok {
+		return nil, nil, nil
+	}
+	importPath := qualifier
+	if file != nil {
+		for _, imp := range file.Imports {
+			if imp.Name == qualifier || imp.Name == "" && path.Base(imp.Path) == qualifier {
+				importPath = imp.Path
+			}
+		}
+	}
+	typePkg := p.lookupImport(importPath)
+	aType, typeFile := typePkg.lookupTypeFile(typeName)
+	if aType == nil {
+		return nil, nil, nil
+	}
+	return aType, typePkg, typeFile
+}
+
+// lookupTypeFile returns a package type with its declaring file, nil for a nil package
+func (p *Package) lookupTypeFile(name string) (*Type, *File) {
+	if p == nil {
+		return nil, nil
+	}
+	for _, file := range p.FileSet {
+		if aType := file.LookupType(name); aType != nil {
+			return aType, file
+		}
+	}
+	return nil, nil
+}
+
+// declaringFile returns the package file declaring a type, nil when not found
+func (p *Package) declaringFile(aType *Type) *File {
+	for _, file := range p.FileSet {
+		for _, candidate := range file.Types {
+			if candidate == aType {
+				return file
+			}
+		}
+	}
+	return nil
+}
+
+// typeMethods returns methods of a named package type across files: methods of types with the name and functions
+// with the type as receiver, e.g. methods of a Go type declared in another file
+func (p *Package) typeMethods(name string) []*Function {
+	var result []*Function
+	seen := map[string]bool{}
+	add := func(method *Function) {
+		if !seen[method.Name] {
+			seen[method.Name] = true
+			result = append(result, method)
+		}
+	}
+	for _, file := range p.FileSet {
+		for _, aType := range file.Types {
+			if aType.Name != name {
+				continue
+			}
+			for _, method := range aType.Methods {
+				add(method)
+			}
+		}
+		for _, function := range file.Functions {
+			receiver, _, _ := strings.Cut(strings.TrimPrefix(function.Receiver, "*"), "[")
+			if receiver != "" && receiver == name {
+				add(function)
+			}
+		}
+	}
+	return result
+}
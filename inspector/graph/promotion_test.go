@@ -0,0 +1,91 @@
+package graph_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/linager/inspector/golang"
+	"github.com/viant/linager/inspector/graph"
+)
+
+// TestProject_PromotedMethods checks synthesized sections of a struct embedding a type with two methods
+func TestProject_PromotedMethods(t *testing.T) {
+	src := `package store
+
+// Closer releases resources
+type Closer interface {
+	Close() error
+}
+
+// Flusher writes pending data
+type Flusher interface {
+	Flush(force bool) error
+}
+
+type conn struct {
+	addr string
+}
+
+// Read reads from the connection
+func (c *conn) Read(p []byte) (int, error) { return 0, nil }
+
+// Close closes the connection
+func (c *conn) Close() error { return nil }
+
+// Client talks to a store
+type Client struct {
+	*conn
+	Name string
+}
+`
+	file, err := golang.NewInspector(&graph.Config{IncludeUnexported: true}).InspectSource([]byte(src))
+	if !assert.NoError(t, err) {
+		return
+	}
+	file.Path = "/store/client.go"
+	pkg := &graph.Package{Name: "store", ImportPath: "/store", FileSet: []*graph.File{file}}
+	project := &graph.Project{Name: "store", Packages: []*graph.Package{pkg}}
+	client := file.LookupType("Client")
+	if !assert.NotNil(t, client) {
+		return
+	}
+
+	var promoted []string
+	for _, method := range project.PromotedMethods(pkg, client) {
+		promoted = append(promoted, method.Method.Name+" "+method.Provider)
+	}
+	assert.Equal(t, []string{"Close *conn", "Read *conn"}, promoted)
+	assert.Equal(t, []string{"Closer"}, project.Satisfies(pkg, client))
+
+	typeDoc := func() *graph.Document {
+		documents, err := project.CreateDocuments(context.Background(), "")
+		assert.NoError(t, err)
+		for _, doc := range documents {
+			if doc.Kind == graph.KindType && doc.Name == "Client" {
+				return doc
+			}
+		}
+		return nil
+	}
+	doc := typeDoc()
+	if !assert.NotNil(t, doc) {
+		return
+	}
+	assert.Equal(t, []string{graph.SectionPromotedMethods, graph.SectionSatisfies}, doc.Synthesized)
+	assert.Contains(t, doc.Content, "// Synthesized: promoted methods\n")
+	assert.Contains(t, doc.Content, "Close() error (from *conn)\n")
+	assert.Contains(t, doc.Content, "Read(p []byte) (int, error) (from *conn)\n")
+	assert.Contains(t, doc.Content, "\n// Synthesized: satisfies Closer\n")
+	assert.NotEqual(t, doc.HashContent(), (&graph.Document{Content: doc.Content}).HashContent(), "synthesized content does not hash as source text")
+
+	project.SetDocumentOptions(graph.DocumentOptions{SkipPromotedMethods: true})
+	doc = typeDoc()
+	assert.Equal(t, []string{graph.SectionSatisfies}, doc.Synthesized)
+	assert.NotContains(t, doc.Content, "promoted methods")
+
+	project.SetDocumentOptions(graph.DocumentOptions{SkipPromotedMethods: true, SkipSatisfies: true})
+	doc = typeDoc()
+	assert.Empty(t, doc.Synthesized)
+	assert.NotContains(t, doc.Content, "Synthesized")
+}
@@ -0,0 +1,278 @@
+package graph
+
+import (
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// rebaseFuzzAnchor is the number of non-whitespace bytes of the head and tail anchors matched when the whole
+// element text is not found, rebaseFuzzTolerance is the accepted relative change of the element length
+const (
+	rebaseFuzzAnchor    = 32
+	rebaseFuzzTolerance = 0.2
+)
+
+// RebaseResult reports locations re-anchored by File.Rebase
+type RebaseResult struct {
+	Moved int // Locations found at another offset
+	Stale int // Locations whose anchor text was not found, marked Stale
+
+	blocks []rebaseBlock
+}
+
+// rebaseBlock is a byte range unchanged between the previous and new source
+type rebaseBlock struct {
+	from, to, size int
+}
+
+// Offset maps a byte offset of the previous source to the new source, false when the offset falls into changed
+// text or the previous source was not recorded
+func (r *RebaseResult) Offset(offset int) (int, bool) {
+	for _, block := range r.blocks {
+		if offset >= block.from && offset <= block.from+block.size {
+			return block.to + offset - block.from, true
+		}
+	}
+	return 0, false
+}
+
+// SetSource records the source the file was inspected from and its hash, see Rebase
+func (f *File) SetSource(source []byte) {
+	f.source = source
+	f.Hash, _ = Hash(source)
+}
+
+// Rebase re-anchors locations of all file elements to new source content after external edits (formatters, manual
+// changes): unchanged lines are mapped by a line diff against the recorded source, elements found elsewhere are
+// matched by their original text (Location.Raw, body text or recorded source), ignoring whitespace changes and, with
+// fuzz tolerance, changes between the head and tail of the element. Locations whose text is not found are marked
+// Stale and keep their offsets. The new source and its hash are recorded.
+func (f *File) Rebase(source []byte) *RebaseResult {
+	result := &RebaseResult{}
+	if f.source != nil {
+		result.blocks = diffBlocks(string(f.source), string(source))
+	}
+	anchor := &rebaseAnchor{previous: f.source, source: string(source), result: result}
+	anchor.compact()
+	for _, constant := range f.Constants {
+		anchor.rebase(constant.Location, "")
+	}
+	for _, variable := range f.Variables {
+		anchor.rebase(variable.Location, "")
+	}
+	for _, aType := range f.Types {
+		anchor.rebaseType(aType)
+	}
+	for _, function := range f.Functions {
+		anchor.rebaseFunction(function)
+	}
+	f.SetSource(source)
+	return result
+}
+
+// rebaseAnchor matches element texts in the new source
+type rebaseAnchor struct {
+	previous  []byte
+	source    string
+	compacted string // source without ASCII whitespace
+	positions []int  // source offsets of compacted bytes
+	result    *RebaseResult
+}
+
+// compact indexes the new source without whitespace
+func (a *rebaseAnchor) compact() {
+	builder := strings.Builder{}
+	for i := 0; i < len(a.source); i++ {
+		if !isSpaceByte(a.source[i]) {
+			builder.WriteByte(a.source[i])
+			a.positions = append(a.positions, i)
+		}
+	}
+	a.compacted = builder.String()
+}
+
+func (a *rebaseAnchor) rebaseType(aType *Type) {
+	a.rebase(aType.Location, "")
+	a.rebaseNode(aType.Comment)
+	a.rebaseNode(aType.Annotation)
+	for _, field := range aType.Fields {
+		a.rebase(field.Location, "")
+		a.rebase(field.DefaultFrom, field.Default)
+	}
+	for _, method := range aType.Methods {
+		a.rebaseFunction(method)
+	}
+}
+
+func (a *rebaseAnchor) rebaseFunction(function *Function) {
+	a.rebase(function.Location, "")
+	a.rebaseNode(function.Comment)
+	a.rebaseNode(function.Annotation)
+	a.rebaseNode(function.Body)
+	for _, local := range function.LocalTypes {
+		a.rebaseType(local)
+	}
+}
+
+// rebaseNode re-anchors a node located by its text, e.g. a function body
+func (a *rebaseAnchor) rebaseNode(node *LocationNode) {
+	if node != nil {
+		a.rebase(&node.Location, node.Text)
+	}
+}
+
+// rebase re-anchors a location by its original text: Raw, the recorded source range or the given text
+func (a *rebaseAnchor) rebase(location *Location, text string) {
+	if location == nil || location.End <= location.Start {
+		return
+	}
+	switch {
+	case location.Raw != "":
+		text = location.Raw
+	case location.End <= len(a.previous):
+		text = string(a.previous[location.Start:location.End])
+	}
+	expected, mapped := a.result.Offset(location.Start)
+	if !mapped {
+		expected = location.Start
+	}
+	start, end, ok := a.find(text, expected, mapped)
+	if !ok {
+		location.Stale = true
+		a.result.Stale++
+		return
+	}
+	if start != location.Start || end != location.End {
+		a.result.Moved++
+	}
+	location.Start, location.End, location.Stale = start, end, false
+}
+
+// find returns the range of text nearest to the expected offset: the exact text at a mapped offset or elsewhere,
+// the text ignoring whitespace, or a range between its head and tail anchors with a similar length
+func (a *rebaseAnchor) find(text string, expected int, mapped bool) (int, int, bool) {
+	if text == "" {
+		return 0, 0, false
+	}
+	if mapped && strings.HasPrefix(a.source[expected:], text) {
+		return expected, expected + len(text), true
+	}
+	if index := nearestIndex(a.source, text, expected); index != -1 {
+		return index, index + len(text), true
+	}
+	compacted := compactText(text)
+	if compacted == "" {
+		return 0, 0, false
+	}
+	expectedCompact := a.compactOffset(expected)
+	if index := nearestIndex(a.compacted, compacted, expectedCompact); index != -1 {
+		return a.positions[index], a.positions[index+len(compacted)-1] + 1, true
+	}
+	if len(compacted) < 3*rebaseFuzzAnchor {
+		return 0, 0, false
+	}
+	head, tail := compacted[:rebaseFuzzAnchor], compacted[len(compacted)-rebaseFuzzAnchor:]
+	index := nearestIndex(a.compacted, head, expectedCompact)
+	if index == -1 {
+		return 0, 0, false
+	}
+	tolerance := int(float64(len(compacted)) * rebaseFuzzTolerance)
+	minEnd, maxEnd := index+len(compacted)-tolerance, index+len(compacted)+tolerance
+	for from := index + len(head); from < len(a.compacted); {
+		offset := strings.Index(a.compacted[from:], tail)
+		if offset == -1 {
+			break
+		}
+		end := from + offset + len(tail)
+		if end > maxEnd {
+			break
+		}
+		if end >= minEnd {
+			return a.positions[index], a.positions[end-1] + 1, true
+		}
+		from += offset + 1
+	}
+	return 0, 0, false
+}
+
+// compactOffset returns the compacted index of the first non-whitespace byte at or after a source offset
+func (a *rebaseAnchor) compactOffset(offset int) int {
+	low, high := 0, len(a.positions)
+	for low < high {
+		middle := (low + high) / 2
+		if a.positions[middle] < offset {
+			low = middle + 1
+		} else {
+			high = middle
+		}
+	}
+	return low
+}
+
+// nearestIndex returns the occurrence of text in source nearest to the expected offset, -1 when not found
+func nearestIndex(source, text string, expected int) int {
+	result := -1
+	for from := 0; from <= len(source)-len(text); {
+		index := strings.Index(source[from:], text)
+		if index == -1 {
+			break
+		}
+		index += from
+		if result == -1 || abs(index-expected) < abs(result-expected) {
+			result = index
+		}
+		from = index + 1
+	}
+	return result
+}
+
+// compactText removes ASCII whitespace
+func compactText(text string) string {
+	builder := strings.Builder{}
+	for i := 0; i < len(text); i++ {
+		if !isSpaceByte(text[i]) {
+			builder.WriteByte(text[i])
+		}
+	}
+	return builder.String()
+}
+
+func isSpaceByte(c byte) bool {
+	switch c {
+	case ' ', '\t', '\n', '\r', '\v', '\f':
+		return true
+	}
+	return false
+}
+
+// diffBlocks returns byte ranges of lines unchanged between sources
+func diffBlocks(previous, source string) []rebaseBlock {
+	previousLines, lines := strings.SplitAfter(previous, "\n"), strings.SplitAfter(source, "\n")
+	previousOffsets, offsets := lineOffsets(previousLines), lineOffsets(lines)
+	var result []rebaseBlock
+	for _, match := range difflib.NewMatcherWithJunk(previousLines, lines, false, nil).GetMatchingBlocks() {
+		if match.Size == 0 {
+			continue
+		}
+		from, to := previousOffsets[match.A], offsets[match.B]
+		result = append(result, rebaseBlock{from: from, to: to, size: previousOffsets[match.A+match.Size] - from})
+	}
+	return result
+}
+
+// lineOffsets returns start offsets of lines followed by the total length
+func lineOffsets(lines []string) []int {
+	result := make([]int, len(lines)+1)
+	for i, line := range lines {
+		result[i+1] = result[i] + len(line)
+	}
+	return result
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
@@ -0,0 +1,313 @@
+package graph
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// RedactionPrefix starts placeholders replacing redacted secrets
+const RedactionPrefix = "REDACTED_"
+
+const (
+	// DefaultMinSecretEntropy is the default Shannon entropy in bits per character of string literals treated as secrets
+	DefaultMinSecretEntropy = 4.5
+	// DefaultMinSecretLength is the default minimum length of string literals checked by the entropy detector
+	DefaultMinSecretLength = 20
+)
+
+var (
+	// redactionPlaceholder matches placeholders of already redacted secrets
+	redactionPlaceholder = regexp.MustCompile(RedactionPrefix + `[0-9a-f]{12}`)
+	// stringLiteral matches double quoted, single quoted and raw string literals
+	stringLiteral = regexp.MustCompile("\"(?:[^\"\\\\\\n]|\\\\.)*\"|'(?:[^'\\\\\\n]|\\\\.)*'|`[^`]*`")
+)
+
+// Redactor replaces secrets in source text before it leaves the library: matches of secret patterns and string
+// literals looking random (long, mixing upper and lower case letters with digits, high entropy) are replaced with a placeholder derived
+// from the secret hash, so the same secret always yields the same placeholder and document hashes stay deterministic.
+// Redactions are counted per file, files matching Allow patterns are not scanned.
+type Redactor struct {
+	Patterns   []*regexp.Regexp // Secret patterns, the first submatch is replaced when the pattern has groups
+	MinEntropy float64          // Minimum entropy of a random-looking string literal, negative disables the detector
+	MinLength  int              // Minimum length of a random-looking string literal
+	Allow      []string         // Path patterns exempt from scanning matched against the full path or base name, e.g. testdata/*, *.md
+
+	mux    sync.Mutex
+	counts map[string]int
+}
+
+// NewRedactor creates a redactor with the entropy detector enabled, default patterns are used when none are given
+func NewRedactor(patterns ...*regexp.Regexp) *Redactor {
+	if len(patterns) == 0 {
+		patterns = DefaultRedactionPatterns()
+	}
+	return &Redactor{Patterns: patterns, MinEntropy: DefaultMinSecretEntropy, MinLength: DefaultMinSecretLength}
+}
+
+// DefaultRedactionPatterns returns patterns matching common cloud keys, tokens, private keys and credential assignments
+func DefaultRedactionPatterns() []*regexp.Regexp {
+	return []*regexp.Regexp{
+		regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`),
+		regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`),
+		regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}\b`),
+		regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`),
+		regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`),
+		regexp.MustCompile(`(?i)(?:password|passwd|secret|api_?key|access_?token)["']?\s*[:=]\s*["']([^"'\s]{8,})["']`),
+	}
+}
+
+// RedactionPlaceholder returns the placeholder of a secret: RedactionPrefix followed by a sha256 prefix
+func RedactionPlaceholder(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return RedactionPrefix + hex.EncodeToString(sum[:])[:12]
+}
+
+// Allowed reports whether the path is exempt from scanning
+func (r *Redactor) Allowed(path string) bool {
+	base := filepath.Base(path)
+	for _, pattern := range r.Allow {
+		if ok, _ := filepath.Match(pattern, path); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(pattern, base); ok {
+			return true
+		}
+		if strings.HasSuffix(pattern, "/") && (strings.HasPrefix(path, pattern) || strings.Contains(path, "/"+pattern)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Redact replaces secrets in text of the file path and counts them
+func (r *Redactor) Redact(path, text string) string {
+	if text == "" || r.Allowed(path) {
+		return text
+	}
+	count := 0
+	for _, pattern := range r.Patterns {
+		text = replaceSecrets(pattern, text, &count)
+	}
+	if r.MinEntropy >= 0 {
+		text = stringLiteral.ReplaceAllStringFunc(text, func(literal string) string {
+			value := literal[1 : len(literal)-1]
+			if !r.looksRandom(value) {
+				return literal
+			}
+			count++
+			return literal[:1] + RedactionPlaceholder(value) + literal[len(literal)-1:]
+		})
+	}
+	if count > 0 {
+		r.count(path, count)
+	}
+	return text
+}
+
+// RedactValue replaces secrets in a bare value of the file path, e.g. a literal or an attribute recorded by
+// data flow analysis: secrets are replaced as with Redact, an unquoted value looking random is replaced as a whole
+func (r *Redactor) RedactValue(path, value string) string {
+	redacted := r.Redact(path, value)
+	if redacted != value || r.MinEntropy < 0 || r.Allowed(path) || !r.looksRandom(value) {
+		return redacted
+	}
+	r.count(path, 1)
+	return RedactionPlaceholder(value)
+}
+
+// count adds redactions of the file path
+func (r *Redactor) count(path string, count int) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	if r.counts == nil {
+		r.counts = map[string]int{}
+	}
+	r.counts[path] += count
+}
+
+// Counts returns the number of redactions per file path
+func (r *Redactor) Counts() map[string]int {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	result := make(map[string]int, len(r.counts))
+	for path, count := range r.counts {
+		result[path] = count
+	}
+	return result
+}
+
+// RedactedFiles returns sorted paths of files with redactions
+func (r *Redactor) RedactedFiles() []string {
+	counts := r.Counts()
+	result := make([]string, 0, len(counts))
+	for path := range counts {
+		result = append(result, path)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// looksRandom reports whether a string literal value is a random-looking secret
+func (r *Redactor) looksRandom(value string) bool {
+	minLength := r.MinLength
+	if minLength == 0 {
+		minLength = DefaultMinSecretLength
+	}
+	if len(value) < minLength || strings.Contains(value, RedactionPrefix) {
+		return false
+	}
+	hasUpper, hasLower, hasDigit := false, false, false
+	for _, c := range value {
+		switch {
+		case unicode.IsSpace(c):
+			return false
+		case unicode.IsUpper(c):
+			hasUpper = true
+		case unicode.IsLower(c):
+			hasLower = true
+		case unicode.IsDigit(c):
+			hasDigit = true
+		}
+	}
+	minEntropy := r.MinEntropy
+	if minEntropy == 0 {
+		minEntropy = DefaultMinSecretEntropy
+	}
+	return hasUpper && hasLower && hasDigit && Entropy(value) >= minEntropy
+}
+
+// replaceSecrets replaces pattern matches (or their first submatch) with placeholders
+func replaceSecrets(pattern *regexp.Regexp, text string, count *int) string {
+	matches := pattern.FindAllStringSubmatchIndex(text, -1)
+	if len(matches) == 0 {
+		return text
+	}
+	builder := strings.Builder{}
+	last := 0
+	for _, match := range matches {
+		start, end := match[0], match[1]
+		if len(match) >= 4 && match[2] != -1 {
+			start, end = match[2], match[3]
+		}
+		secret := text[start:end]
+		if redactionPlaceholder.MatchString(secret) {
+			continue
+		}
+		builder.WriteString(text[last:start])
+		builder.WriteString(RedactionPlaceholder(secret))
+		last = end
+		*count++
+	}
+	builder.WriteString(text[last:])
+	return builder.String()
+}
+
+// Entropy returns the Shannon entropy of text in bits per character
+func Entropy(text string) float64 {
+	if text == "" {
+		return 0
+	}
+	frequency := map[rune]int{}
+	total := 0
+	for _, c := range text {
+		frequency[c]++
+		total++
+	}
+	result := 0.0
+	for _, n := range frequency {
+		p := float64(n) / float64(total)
+		result -= p * math.Log2(p)
+	}
+	return result
+}
+
+// Redact replaces secrets in constant and variable values, source copies (Location.Raw), function bodies,
+// comments and field defaults of all project files; documents created afterwards are redacted as well,
+// including assets. See Redactor.Counts for redactions per file.
+func (p *Project) Redact(redactor *Redactor) {
+	p.redactor = redactor
+	for _, pkg := range p.Packages {
+		for _, file := range pkg.FileSet {
+			file.redact(redactor)
+		}
+	}
+}
+
+// redact replaces secrets in file elements
+func (f *File) redact(redactor *Redactor) {
+	redact := func(text string) string {
+		return redactor.Redact(f.Path, text)
+	}
+	f.Doc = redact(f.Doc)
+	for _, constant := range f.Constants {
+		constant.Value = redact(constant.Value)
+		constant.Comment = redact(constant.Comment)
+		redactLocation(constant.Location, redact)
+	}
+	for _, variable := range f.Variables {
+		variable.Value = redact(variable.Value)
+		variable.Comment = redact(variable.Comment)
+		redactLocation(variable.Location, redact)
+	}
+	for _, function := range f.Functions {
+		function.redact(redact)
+	}
+	for _, aType := range f.Types {
+		aType.redact(redact)
+	}
+}
+
+// redact replaces secrets in type source copies, fields and methods
+func (t *Type) redact(redact func(string) string) {
+	redactLocation(t.Location, redact)
+	redactNode(t.Comment, redact)
+	for _, field := range t.Fields {
+		redactLocation(field.Location, redact)
+		field.Comment = redact(field.Comment)
+		field.Default = redact(field.Default)
+	}
+	for _, method := range t.Methods {
+		method.redact(redact)
+	}
+}
+
+// redact replaces secrets in function source copies, bodies and local types
+func (f *Function) redact(redact func(string) string) {
+	redactLocation(f.Location, redact)
+	redactNode(f.Comment, redact)
+	redactNode(f.Body, redact)
+	for _, local := range f.LocalTypes {
+		local.redact(redact)
+	}
+}
+
+func redactLocation(location *Location, redact func(string) string) {
+	if location != nil {
+		location.Raw = redact(location.Raw)
+	}
+}
+
+func redactNode(node *LocationNode, redact func(string) string) {
+	if node != nil {
+		node.Text = redact(node.Text)
+		node.Raw = redact(node.Raw)
+	}
+}
+
+// redactDocument replaces secrets in document content with the project redactor, the content hash is recomputed
+func (p *Project) redactDocument(doc *Document) {
+	if p.redactor == nil {
+		return
+	}
+	doc.Content = p.redactor.Redact(doc.Path, doc.Content)
+	doc.Type = p.redactor.Redact(doc.Path, doc.Type)
+	doc.Signature = p.redactor.Redact(doc.Path, doc.Signature)
+	doc.Hash = doc.HashContent()
+}
@@ -0,0 +1,405 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Ref kinds
+const (
+	RefKindPackage  = "package"
+	RefKindType     = "type" // types and package level symbols
+	RefKindField    = "field"
+	RefKindFunction = "function"
+	RefKindMethod   = "method"
+	RefKindVariable = "variable" // parameters and local variables of a function
+)
+
+// Ref is a canonical reference of a code element shared by the graph and linage packages:
+//
+//	pkg                           package
+//	pkg#Type                      type (nested types are dotted, e.g. Outer.Inner) or package level symbol
+//	pkg#Type/field                field
+//	pkg#Function(string,int...)   function with parameter types, variadic types end with ...
+//	pkg#Type.Method(string)       method or constructor
+//	pkg#Type.Method(string)/name  parameter or local variable
+//
+// pkg is an import path (Go) or package name (Java); parameter types are separated by commas without spaces
+type Ref struct {
+	Package  string
+	Type     string
+	Function string
+	Params   []string // parameter types of Function
+	Member   string   // field of Type, parameter or variable of Function
+}
+
+// NewTypeRef creates a type reference
+func NewTypeRef(pkg, typeName string) Ref {
+	return Ref{Package: pkg, Type: typeName}
+}
+
+// NewFunctionRef creates a function reference, typeName is empty for package level functions
+func NewFunctionRef(pkg, typeName, name string, params []string) Ref {
+	if params == nil {
+		params = []string{}
+	}
+	return Ref{Package: pkg, Type: typeName, Function: name, Params: params}
+}
+
+// ParseRef parses a canonical reference
+func ParseRef(text string) (Ref, error) {
+	ret := Ref{}
+	pkg, symbol, ok := strings.Cut(text, "#")
+	ret.Package = pkg
+	if !ok {
+		if pkg == "" || strings.ContainsAny(pkg, "()") {
+			return Ref{}, fmt.Errorf("invalid ref %q: missing package", text)
+		}
+		return ret, nil
+	}
+	if symbol == "" {
+		return Ref{}, fmt.Errorf("invalid ref %q: missing symbol", text)
+	}
+	open := strings.Index(symbol, "(")
+	if open == -1 {
+		ret.Type, ret.Member, _ = strings.Cut(symbol, "/")
+		if ret.Type == "" || (strings.Contains(symbol, "/") && ret.Member == "") {
+			return Ref{}, fmt.Errorf("invalid ref %q: empty name", text)
+		}
+		return ret, nil
+	}
+	closing := matchingParen(symbol, open)
+	if closing == -1 {
+		return Ref{}, fmt.Errorf("invalid ref %q: unbalanced parameters", text)
+	}
+	qualified := symbol[:open]
+	if index := strings.LastIndex(qualified, "."); index != -1 {
+		ret.Type, ret.Function = qualified[:index], qualified[index+1:]
+	} else {
+		ret.Function = qualified
+	}
+	ret.Params = splitParams(symbol[open+1 : closing])
+	switch rest := symbol[closing+1:]; {
+	case rest == "":
+	case strings.HasPrefix(rest, "/") && len(rest) > 1:
+		ret.Member = rest[1:]
+	default:
+		return Ref{}, fmt.Errorf("invalid ref %q: unexpected %q", text, rest)
+	}
+	if ret.Function == "" {
+		return Ref{}, fmt.Errorf("invalid ref %q: empty function name", text)
+	}
+	return ret, nil
+}
+
+// String returns the canonical reference text
+func (r Ref) String() string {
+	builder := strings.Builder{}
+	builder.WriteString(r.Package)
+	if r.Type == "" && r.Function == "" {
+		return builder.String()
+	}
+	builder.WriteString("#")
+	builder.WriteString(r.Type)
+	if r.Function != "" {
+		if r.Type != "" {
+			builder.WriteString(".")
+		}
+		builder.WriteString(r.Function)
+		builder.WriteString("(")
+		builder.WriteString(strings.Join(r.Params, ","))
+		builder.WriteString(")")
+	}
+	if r.Member != "" {
+		builder.WriteString("/")
+		builder.WriteString(r.Member)
+	}
+	return builder.String()
+}
+
+// MarshalText encodes the reference as its canonical text, e.g. in JSON output
+func (r Ref) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText decodes a canonical reference text
+func (r *Ref) UnmarshalText(text []byte) error {
+	ref, err := ParseRef(string(text))
+	if err != nil {
+		return err
+	}
+	*r = ref
+	return nil
+}
+
+// Kind returns the referenced element kind, see RefKind* constants
+func (r Ref) Kind() string {
+	switch {
+	case r.Function != "" && r.Member != "":
+		return RefKindVariable
+	case r.Function != "" && r.Type != "":
+		return RefKindMethod
+	case r.Function != "":
+		return RefKindFunction
+	case r.Member != "":
+		return RefKindField
+	case r.Type != "":
+		return RefKindType
+	}
+	return RefKindPackage
+}
+
+// Name returns the referenced element name
+func (r Ref) Name() string {
+	switch {
+	case r.Member != "":
+		return r.Member
+	case r.Function != "":
+		return r.Function
+	case r.Type != "":
+		return r.Type
+	}
+	return r.Package
+}
+
+// Owner returns the reference of the enclosing element: the function of a variable, the type of a field or method,
+// the package of a type or function
+func (r Ref) Owner() Ref {
+	switch r.Kind() {
+	case RefKindVariable:
+		return NewFunctionRef(r.Package, r.Type, r.Function, r.Params)
+	case RefKindField, RefKindMethod:
+		return NewTypeRef(r.Package, r.Type)
+	}
+	return Ref{Package: r.Package}
+}
+
+// WithMember returns a field reference of a type or a variable reference of a function
+func (r Ref) WithMember(name string) Ref {
+	r.Member = name
+	return r
+}
+
+// Ref returns the package reference
+func (p *Package) Ref() Ref {
+	if p.ImportPath != "" {
+		return Ref{Package: p.ImportPath}
+	}
+	return Ref{Package: p.Name}
+}
+
+// Ref returns the type reference, qualified by the package import path or name
+func (t *Type) Ref() Ref {
+	pkg := t.PackagePath
+	if pkg == "" {
+		pkg = t.Package
+	}
+	return NewTypeRef(pkg, t.qualifiedName())
+}
+
+// qualifiedName returns the type name prefixed with its enclosing type
+func (t *Type) qualifiedName() string {
+	if t.ParentType != "" && !t.Anonymous && !strings.HasPrefix(t.Name, t.ParentType+".") {
+		return t.ParentType + "." + t.Name
+	}
+	return t.Name
+}
+
+// Ref returns the field reference within its owner type reference
+func (f *Field) Ref(owner Ref) Ref {
+	return NewTypeRef(owner.Package, owner.Type).WithMember(f.Name)
+}
+
+// Ref returns the function reference within its owner: a type reference for methods, a package reference for
+// functions; Go methods declared outside of a type are qualified by their receiver type
+func (f *Function) Ref(owner Ref) Ref {
+	typeName := owner.Type
+	if typeName == "" && f.Receiver != "" {
+		typeName, _, _ = strings.Cut(strings.TrimPrefix(f.Receiver, "*"), "[")
+	}
+	return NewFunctionRef(owner.Package, typeName, f.Name, f.ParamTypes())
+}
+
+// ParamTypes returns canonical parameter types: source type names, variadic types end with ...
+func (f *Function) ParamTypes() []string {
+	result := make([]string, 0, len(f.Parameters))
+	for _, param := range f.Parameters {
+		var typeName string
+		if param.Type != nil {
+			typeName = param.Type.RawName
+			if typeName == "" {
+				typeName = param.Type.Name
+			}
+		}
+		typeName = strings.Join(strings.Fields(typeName), "")
+		if param.Type != nil && param.Type.IsPointer && !strings.HasPrefix(typeName, "*") {
+			typeName = "*" + typeName
+		}
+		if param.IsVariadic {
+			typeName += "..."
+		}
+		result = append(result, typeName)
+	}
+	return result
+}
+
+// RefTarget holds a graph element resolved by reference with its enclosing elements
+type RefTarget struct {
+	Ref       Ref
+	Package   *Package
+	File      *File
+	Type      *Type
+	Function  *Function
+	Field     *Field
+	Parameter *Parameter
+	Variable  *Variable // package level variable of a type kind reference
+	Constant  *Constant // package level constant of a type kind reference
+}
+
+// ByRef resolves a canonical reference to a project element, nil when it is invalid or not found
+func (p *Project) ByRef(ref string) *RefTarget {
+	parsed, err := ParseRef(ref)
+	if err != nil {
+		return nil
+	}
+	pkg := p.lookupImport(parsed.Package)
+	if pkg == nil {
+		return nil
+	}
+	target := &RefTarget{Ref: parsed, Package: pkg}
+	if parsed.Kind() == RefKindPackage {
+		return target
+	}
+	for _, file := range pkg.FileSet {
+		target.File = file
+		if parsed.Type != "" {
+			for _, aType := range file.Types {
+				if aType.qualifiedName() == parsed.Type && target.resolveMember(aType) {
+					return target
+				}
+			}
+			if target.resolveValue(file) {
+				return target
+			}
+		}
+		if parsed.Function == "" {
+			continue
+		}
+		for _, function := range file.Functions {
+			candidate := function.Ref(Ref{Package: parsed.Package})
+			if candidate.Type == parsed.Type && candidate.Function == parsed.Function && sameParams(candidate.Params, parsed.Params) && target.resolveFunction(function) {
+				return target
+			}
+		}
+	}
+	return nil
+}
+
+// resolveMember resolves the type, its field or method of a target reference
+func (t *RefTarget) resolveMember(aType *Type) bool {
+	t.Type = aType
+	switch t.Ref.Kind() {
+	case RefKindType:
+		return true
+	case RefKindField:
+		t.Field = aType.GetField(t.Ref.Member)
+		return t.Field != nil
+	}
+	for _, method := range aType.Methods {
+		if method.Name == t.Ref.Function && sameParams(method.ParamTypes(), t.Ref.Params) && t.resolveFunction(method) {
+			return true
+		}
+	}
+	t.Type = nil
+	return false
+}
+
+// resolveValue resolves a package level variable or constant of a type kind target reference
+func (t *RefTarget) resolveValue(file *File) bool {
+	if t.Ref.Kind() != RefKindType {
+		return false
+	}
+	for _, variable := range file.Variables {
+		if variable.Name == t.Ref.Type {
+			t.Variable = variable
+			return true
+		}
+	}
+	for _, constant := range file.Constants {
+		if constant.Name == t.Ref.Type {
+			t.Constant = constant
+			return true
+		}
+	}
+	return false
+}
+
+// resolveFunction resolves the function or its parameter of a target reference
+func (t *RefTarget) resolveFunction(function *Function) bool {
+	t.Function = function
+	if t.Ref.Member == "" {
+		return true
+	}
+	for _, param := range function.Parameters {
+		if param.Name == t.Ref.Member {
+			t.Parameter = param
+			return true
+		}
+	}
+	t.Function = nil
+	return false
+}
+
+// matchingParen returns the index of the parenthesis closing the one at open, -1 if unbalanced
+func matchingParen(text string, open int) int {
+	depth := 0
+	for i := open; i < len(text); i++ {
+		switch text[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// splitParams splits parameter types at top level commas, e.g. Map<String,User>,int; whitespace is removed
+func splitParams(text string) []string {
+	result := []string{}
+	text = strings.Join(strings.Fields(text), "")
+	if text == "" {
+		return result
+	}
+	depth, start := 0, 0
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '(', '[', '<', '{':
+			depth++
+		case ')', ']', '>', '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				result = append(result, text[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(result, text[start:])
+}
+
+// sameParams reports whether parameter type lists are equal
+func sameParams(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,46 @@
+package graph_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/linager/inspector/graph"
+	"testing"
+)
+
+func TestParseRef(t *testing.T) {
+	var testCases = []struct {
+		description string
+		text        string
+		expect      graph.Ref
+		kind        string
+		owner       string
+		hasError    bool
+	}{
+		{description: "package", text: "github.com/acme/app", expect: graph.Ref{Package: "github.com/acme/app"}, kind: graph.RefKindPackage, owner: "github.com/acme/app"},
+		{description: "type", text: "github.com/acme/app#User", expect: graph.NewTypeRef("github.com/acme/app", "User"), kind: graph.RefKindType, owner: "github.com/acme/app"},
+		{description: "nested type", text: "com.acme#Registry.Entry", expect: graph.NewTypeRef("com.acme", "Registry.Entry"), kind: graph.RefKindType, owner: "com.acme"},
+		{description: "field", text: "github.com/acme/app#User/Name", expect: graph.Ref{Package: "github.com/acme/app", Type: "User", Member: "Name"}, kind: graph.RefKindField, owner: "github.com/acme/app#User"},
+		{description: "function", text: "github.com/acme/app#Find(map[string]*User,string)", expect: graph.NewFunctionRef("github.com/acme/app", "", "Find", []string{"map[string]*User", "string"}), kind: graph.RefKindFunction, owner: "github.com/acme/app"},
+		{description: "generic params", text: "com.acme#Repo.save(Map<String,User>,int...)", expect: graph.NewFunctionRef("com.acme", "Repo", "save", []string{"Map<String,User>", "int..."}), kind: graph.RefKindMethod, owner: "com.acme#Repo"},
+		{description: "nested type method", text: "com.acme#Registry.Entry.hit()", expect: graph.NewFunctionRef("com.acme", "Registry.Entry", "hit", nil), kind: graph.RefKindMethod, owner: "com.acme#Registry.Entry"},
+		{description: "variable", text: "com.acme#Repo.save(User)/user", expect: graph.NewFunctionRef("com.acme", "Repo", "save", []string{"User"}).WithMember("user"), kind: graph.RefKindVariable, owner: "com.acme#Repo.save(User)"},
+		{description: "func param type", text: "app#Run(func(int,string)error)", expect: graph.NewFunctionRef("app", "", "Run", []string{"func(int,string)error"}), kind: graph.RefKindFunction, owner: "app"},
+		{description: "missing symbol", text: "app#", hasError: true},
+		{description: "unbalanced", text: "app#Run(int", hasError: true},
+		{description: "trailing text", text: "app#Run(int)x", hasError: true},
+		{description: "empty function", text: "app#User.(int)", hasError: true},
+	}
+	for _, testCase := range testCases {
+		actual, err := graph.ParseRef(testCase.text)
+		if testCase.hasError {
+			assert.Error(t, err, testCase.description)
+			continue
+		}
+		if !assert.NoError(t, err, testCase.description) {
+			continue
+		}
+		assert.Equal(t, testCase.expect, actual, testCase.description)
+		assert.Equal(t, testCase.text, actual.String(), testCase.description)
+		assert.Equal(t, testCase.kind, actual.Kind(), testCase.description)
+		assert.Equal(t, testCase.owner, actual.Owner().String(), testCase.description)
+	}
+}
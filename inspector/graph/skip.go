@@ -0,0 +1,119 @@
+package graph
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"unicode/utf8"
+)
+
+// DefaultMaxFileSize is the default maximum size of an inspected source file
+const DefaultMaxFileSize = 2 << 20
+
+// sniffSize is the number of leading bytes checked for binary content
+const sniffSize = 8 << 10
+
+// Skip reasons
+const (
+	SkipReasonSize    = "size"    // file exceeds configured MaxFileSize
+	SkipReasonBinary  = "binary"  // file content is not UTF-8 text
+	SkipReasonSymlink = "symlink" // file is a symlink and FollowSymlinks is not set
+	SkipReasonSpecial = "special" // file is not a regular file, e.g. a socket, device or named pipe
+)
+
+// SkippedFile describes a source file omitted from inspection
+type SkippedFile struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	Reason string `json:"reason"`
+}
+
+// FileSizeLimit returns the maximum inspected file size, 0 when unlimited
+func (c *Config) FileSizeLimit() int64 {
+	switch {
+	case c == nil || c.MaxFileSize == 0:
+		return DefaultMaxFileSize
+	case c.MaxFileSize < 0:
+		return 0
+	}
+	return c.MaxFileSize
+}
+
+// CheckSize returns a skipped file when size exceeds the configured limit
+func (c *Config) CheckSize(path string, size int64) *SkippedFile {
+	if limit := c.FileSizeLimit(); limit > 0 && size > limit {
+		return &SkippedFile{Path: path, Size: size, Reason: SkipReasonSize}
+	}
+	return nil
+}
+
+// CheckMode returns a skipped file for a file mode other than a regular file: a symlink unless FollowSymlinks is set
+// or a special file (socket, device, named pipe)
+func (c *Config) CheckMode(path string, mode os.FileMode) *SkippedFile {
+	switch {
+	case mode&os.ModeSymlink != 0:
+		if c == nil || !c.FollowSymlinks {
+			return &SkippedFile{Path: path, Reason: SkipReasonSymlink}
+		}
+	case !mode.IsRegular():
+		return &SkippedFile{Path: path, Reason: SkipReasonSpecial}
+	}
+	return nil
+}
+
+// CheckFile stats and sniffs a local file, it returns a skipped file when the file is not a regular file (see
+// CheckMode), oversized or binary
+func (c *Config) CheckFile(path string) (*SkippedFile, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return nil, NotFoundError("file", path, err)
+	}
+	if skipped := c.CheckMode(path, info.Mode()); skipped != nil {
+		return skipped, nil
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		if info, err = os.Stat(path); err != nil {
+			return nil, NotFoundError("file", path, err)
+		}
+		if skipped := c.CheckMode(path, info.Mode()); skipped != nil {
+			return skipped, nil
+		}
+	}
+	if skipped := c.CheckSize(path, info.Size()); skipped != nil {
+		return skipped, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, NotFoundError("file", path, err)
+	}
+	defer file.Close()
+	head := make([]byte, sniffSize+utf8.UTFMax)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	if IsBinary(head[:n]) {
+		return &SkippedFile{Path: path, Size: info.Size(), Reason: SkipReasonBinary}, nil
+	}
+	return nil, nil
+}
+
+// IsBinary reports whether the leading 8KB of content hold a null byte or invalid UTF-8
+func IsBinary(content []byte) bool {
+	if len(content) > sniffSize {
+		content = content[:sniffSize]
+		// a multi-byte rune may be cut by the sniff window
+		for i := 1; i < utf8.UTFMax && i <= len(content); i++ {
+			if utf8.RuneStart(content[len(content)-i]) {
+				if !utf8.FullRune(content[len(content)-i:]) {
+					content = content[:len(content)-i]
+				}
+				break
+			}
+		}
+	}
+	if bytes.IndexByte(content, 0) != -1 {
+		return true
+	}
+	return !utf8.Valid(content)
+}
@@ -0,0 +1,26 @@
+package graph_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/linager/inspector/graph"
+	"strings"
+	"testing"
+)
+
+func TestIsBinary(t *testing.T) {
+	var testCases = []struct {
+		description string
+		content     []byte
+		expect      bool
+	}{
+		{description: "text", content: []byte("package main\n"), expect: false},
+		{description: "utf8 text", content: []byte("// zażółć gęślą jaźń\n"), expect: false},
+		{description: "null byte", content: []byte("package main\x00"), expect: true},
+		{description: "invalid utf8", content: []byte{0xCA, 0xFE, 0xBA, 0xBE}, expect: true},
+		{description: "rune cut by sniff window", content: []byte(strings.Repeat("a", 8<<10-1) + "ż"), expect: false},
+		{description: "null byte after sniff window", content: []byte(strings.Repeat("a", 8<<10) + "\x00"), expect: false},
+	}
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expect, graph.IsBinary(testCase.content), testCase.description)
+	}
+}
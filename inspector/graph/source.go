@@ -0,0 +1,45 @@
+package graph
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// SourceProvider reads project sources, e.g. the local file system (the default) or blobs of a git revision (see
+// repository.GitObjectProvider); sources are addressed by file system paths so that inspected paths do not depend on
+// the provider
+type SourceProvider interface {
+	// Stat returns file info of a path
+	Stat(path string) (os.FileInfo, error)
+	// ReadDir returns entries of a directory sorted by name
+	ReadDir(path string) ([]os.DirEntry, error)
+	// ReadFile returns content of a file
+	ReadFile(path string) ([]byte, error)
+	// Walk walks the file tree rooted at root like filepath.Walk, in lexical order
+	Walk(root string, fn filepath.WalkFunc) error
+}
+
+// CheckSource is CheckFile of a file read with a source provider, the local file is checked when sources is nil
+func (c *Config) CheckSource(sources SourceProvider, path string) (*SkippedFile, error) {
+	if sources == nil {
+		return c.CheckFile(path)
+	}
+	info, err := sources.Stat(path)
+	if err != nil {
+		return nil, NotFoundError("file", path, err)
+	}
+	if skipped := c.CheckMode(path, info.Mode()); skipped != nil {
+		return skipped, nil
+	}
+	if skipped := c.CheckSize(path, info.Size()); skipped != nil {
+		return skipped, nil
+	}
+	content, err := sources.ReadFile(path)
+	if err != nil {
+		return nil, NotFoundError("file", path, err)
+	}
+	if IsBinary(content) {
+		return &SkippedFile{Path: path, Size: info.Size(), Reason: SkipReasonBinary}, nil
+	}
+	return nil, nil
+}
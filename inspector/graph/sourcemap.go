@@ -0,0 +1,120 @@
+package graph
+
+import (
+	"bytes"
+	"strings"
+)
+
+// Source map entry kinds
+const (
+	SourceKindType     = "type"
+	SourceKindField    = "field"
+	SourceKindFunction = "function" // functions and methods
+	SourceKindConstant = "constant"
+)
+
+// SourceRange locates an emitted declaration in generated content
+type SourceRange struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"` // Name, Type.Field or Type.Method
+	Start     int    `json:"start"`
+	End       int    `json:"end"`       // Exclusive end byte offset
+	StartLine int    `json:"startLine"` // 1-based first line
+	EndLine   int    `json:"endLine"`   // 1-based last line
+}
+
+// SourceMap lists ranges of declarations emitted to a file, in output order
+type SourceMap struct {
+	Path   string         `json:"path"`
+	Ranges []*SourceRange `json:"ranges"`
+}
+
+// EmitResult holds emitted content with its source map, the map is nil when the emitter does not produce one
+type EmitResult struct {
+	Content   []byte
+	SourceMap *SourceMap
+}
+
+// SourceMapEmitter is implemented by emitters locating declarations they emit
+type SourceMapEmitter interface {
+	// EmitWithSourceMap returns source code of a file with ranges of its emitted types, fields, functions and constants
+	EmitWithSourceMap(file *File) (*EmitResult, error)
+}
+
+// NewSourceMap creates an empty source map of a file path
+func NewSourceMap(path string) *SourceMap {
+	return &SourceMap{Path: path, Ranges: []*SourceRange{}}
+}
+
+// Add records a declaration range of content, lines are computed from content
+func (m *SourceMap) Add(kind, name string, content []byte, start, end int) *SourceRange {
+	ret := &SourceRange{Kind: kind, Name: name, Start: start, End: end}
+	ret.StartLine = bytes.Count(content[:start], []byte("\n")) + 1
+	ret.EndLine = ret.StartLine + bytes.Count(content[start:end], []byte("\n"))
+	m.Ranges = append(m.Ranges, ret)
+	return ret
+}
+
+// Lookup returns the first range of a declaration kind and name, nil if not emitted
+func (m *SourceMap) Lookup(kind, name string) *SourceRange {
+	for _, candidate := range m.Ranges {
+		if candidate.Kind == kind && candidate.Name == name {
+			return candidate
+		}
+	}
+	return nil
+}
+
+// ApplySourceMap updates locations of file declarations to their emitted ranges, raw content is retained; same named
+// declarations (e.g. Java overloads) are matched in order
+func (f *File) ApplySourceMap(sourceMap *SourceMap) {
+	if sourceMap == nil {
+		return
+	}
+	queues := map[string][]*SourceRange{}
+	for _, item := range sourceMap.Ranges {
+		key := item.Kind + ":" + item.Name
+		queues[key] = append(queues[key], item)
+	}
+	next := func(kind, name string) *SourceRange {
+		key := kind + ":" + name
+		queue := queues[key]
+		if len(queue) == 0 {
+			return nil
+		}
+		queues[key] = queue[1:]
+		return queue[0]
+	}
+	for _, aType := range f.Types {
+		aType.Location = relocate(aType.Location, next(SourceKindType, aType.Name))
+		for _, field := range aType.Fields {
+			field.Location = relocate(field.Location, next(SourceKindField, aType.Name+"."+field.Name))
+		}
+		for _, method := range aType.Methods {
+			method.Location = relocate(method.Location, next(SourceKindFunction, aType.Name+"."+method.Name))
+		}
+	}
+	for _, function := range f.Functions {
+		name := function.Name
+		if receiver := strings.TrimPrefix(function.Receiver, "*"); receiver != "" {
+			receiver, _, _ = strings.Cut(receiver, "[")
+			name = receiver + "." + name
+		}
+		function.Location = relocate(function.Location, next(SourceKindFunction, name))
+	}
+	for _, constant := range f.Constants {
+		constant.Location = relocate(constant.Location, next(SourceKindConstant, constant.Name))
+	}
+}
+
+// relocate returns a location moved to a source range, location is returned unchanged without a range
+func relocate(location *Location, sourceRange *SourceRange) *Location {
+	if sourceRange == nil {
+		return location
+	}
+	if location == nil {
+		location = &Location{}
+	}
+	location.Start, location.End = sourceRange.Start, sourceRange.End
+	return location
+}
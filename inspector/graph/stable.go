@@ -0,0 +1,173 @@
+package graph
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// Normalize orders project elements independently of the inspection walk order: packages by import path, modules by
+// name, files, assets and skipped files by path, types, functions, constants and variables by declaration position
+// when located, then by name, methods by name and signature, imports by path and name, test linkage by reference;
+// struct fields and parameters keep their declaration order. Lookup indexes are rebuilt.
+func (p *Project) Normalize() {
+	sort.SliceStable(p.Packages, func(i, j int) bool {
+		if p.Packages[i].ImportPath != p.Packages[j].ImportPath {
+			return p.Packages[i].ImportPath < p.Packages[j].ImportPath
+		}
+		return p.Packages[i].Name < p.Packages[j].Name
+	})
+	sortSkipped(p.SkippedFiles)
+	sort.SliceStable(p.Modules, func(i, j int) bool {
+		return p.Modules[i].Name < p.Modules[j].Name
+	})
+	for _, pkg := range p.Packages {
+		pkg.Normalize()
+	}
+	p.IndexPackages()
+}
+
+// Normalize orders package files and their elements, see Project.Normalize
+func (p *Package) Normalize() {
+	sort.SliceStable(p.FileSet, func(i, j int) bool {
+		if p.FileSet[i].Path != p.FileSet[j].Path {
+			return p.FileSet[i].Path < p.FileSet[j].Path
+		}
+		return p.FileSet[i].Name < p.FileSet[j].Name
+	})
+	sort.SliceStable(p.Assets, func(i, j int) bool {
+		return p.Assets[i].Path < p.Assets[j].Path
+	})
+	sortSkipped(p.Skipped)
+	sort.SliceStable(p.Variants, func(i, j int) bool {
+		return p.Variants[i].Name < p.Variants[j].Name
+	})
+	for _, file := range p.FileSet {
+		file.Normalize()
+	}
+	p.assetMap, p.fileMap = nil, nil
+	if p.typeMap != nil {
+		p.IndexTypes()
+	}
+}
+
+// Normalize orders file elements, see Project.Normalize
+func (f *File) Normalize() {
+	sort.SliceStable(f.Imports, func(i, j int) bool {
+		if f.Imports[i].Path != f.Imports[j].Path {
+			return f.Imports[i].Path < f.Imports[j].Path
+		}
+		return f.Imports[i].Name < f.Imports[j].Name
+	})
+	sort.SliceStable(f.Types, func(i, j int) bool {
+		return declaredBefore(f.Types[i].Location, f.Types[i].Name, f.Types[j].Location, f.Types[j].Name)
+	})
+	sort.SliceStable(f.Functions, func(i, j int) bool {
+		left, right := f.Functions[i], f.Functions[j]
+		if declaredBefore(left.Location, left.Name, right.Location, right.Name) {
+			return true
+		}
+		if declaredBefore(right.Location, right.Name, left.Location, left.Name) {
+			return false
+		}
+		return left.Receiver < right.Receiver
+	})
+	sort.SliceStable(f.Constants, func(i, j int) bool {
+		return declaredBefore(f.Constants[i].Location, f.Constants[i].Name, f.Constants[j].Location, f.Constants[j].Name)
+	})
+	sort.SliceStable(f.Variables, func(i, j int) bool {
+		return declaredBefore(f.Variables[i].Location, f.Variables[i].Name, f.Variables[j].Location, f.Variables[j].Name)
+	})
+	for _, aType := range f.Types {
+		aType.normalize()
+	}
+	for _, function := range f.Functions {
+		function.normalize()
+	}
+	if f.functionMap != nil {
+		f.IndexFunctions()
+	}
+	if f.typeMap != nil {
+		f.IndexTypes()
+	}
+	f.variableMap = reindex(f.variableMap, len(f.Variables), func(i int) string { return f.Variables[i].Name })
+	f.constantMap = reindex(f.constantMap, len(f.Constants), func(i int) string { return f.Constants[i].Name })
+}
+
+// normalize orders type methods by name and signature, including local types of methods
+func (t *Type) normalize() {
+	sortRefs(t.TestedBy)
+	sort.SliceStable(t.Methods, func(i, j int) bool {
+		if t.Methods[i].Name != t.Methods[j].Name {
+			return t.Methods[i].Name < t.Methods[j].Name
+		}
+		return t.Methods[i].Signature < t.Methods[j].Signature
+	})
+	t.methodMap = reindex(t.methodMap, len(t.Methods), func(i int) string { return t.Methods[i].Name })
+	for _, method := range t.Methods {
+		method.normalize()
+	}
+}
+
+// normalize orders function test linkage and local types
+func (f *Function) normalize() {
+	sortRefs(f.TestedBy)
+	sortRefs(f.Subjects)
+	for _, local := range f.LocalTypes {
+		local.normalize()
+	}
+}
+
+// declaredBefore orders located declarations by source position before declarations without location, both by name
+func declaredBefore(location *Location, name string, other *Location, otherName string) bool {
+	switch {
+	case location != nil && other != nil && location.Start != other.Start:
+		return location.Start < other.Start
+	case (location == nil) != (other == nil):
+		return location != nil
+	}
+	return name < otherName
+}
+
+// reindex rebuilds a name index of count elements, nil indexes stay nil
+func reindex(index map[string]int, count int, name func(i int) string) map[string]int {
+	if index == nil {
+		return nil
+	}
+	index = make(map[string]int, count)
+	for i := 0; i < count; i++ {
+		if _, ok := index[name(i)]; !ok {
+			index[name(i)] = i
+		}
+	}
+	return index
+}
+
+// sortRefs orders references by canonical text
+func sortRefs(refs []Ref) {
+	sort.SliceStable(refs, func(i, j int) bool {
+		return refs[i].String() < refs[j].String()
+	})
+}
+
+// sortSkipped orders skipped files by path
+func sortSkipped(skipped []*SkippedFile) {
+	sort.SliceStable(skipped, func(i, j int) bool {
+		return skipped[i].Path < skipped[j].Path
+	})
+}
+
+// MarshalStable encodes the project as indented JSON independent of the inspection walk order: a deep copy of the
+// project is normalized (see Normalize) and encoded, fields follow struct declaration order and lookup indexes are
+// never encoded; the project itself is left unchanged
+func (p *Project) MarshalStable() ([]byte, error) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	clone := &Project{}
+	if err = json.Unmarshal(data, clone); err != nil {
+		return nil, err
+	}
+	clone.Normalize()
+	return json.MarshalIndent(clone, "", "  ")
+}
@@ -0,0 +1,381 @@
+package graph
+
+import (
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Model mappings of storage tables
+const (
+	MappingGORM = "gorm"
+	MappingSQLX = "sqlx" // db tags of sqlx and database/sql scanners
+	MappingBun  = "bun"
+)
+
+// tableNameReturn matches the string literal returned by a TableName() method body
+var tableNameReturn = regexp.MustCompile("^\\{\\s*return\\s+(?:\"([^\"]+)\"|`([^`]+)`)\\s*;?\\s*\\}$")
+
+// Table is a storage table mapped by a model struct, see Project.MapModels
+type Table struct {
+	Name     string
+	Mapping  string    // Convention the table is mapped by, e.g. gorm
+	Package  string    // Import path or name of the model package
+	Type     string    // Model type name
+	Inferred bool      // Whether the name is a naming convention guess, sqlx models without TableName()
+	Columns  []*Column // Mapped columns in field order, embedded fields expanded
+}
+
+// Column is a table column mapped by a model field
+type Column struct {
+	Name       string
+	Table      string
+	Field      string // Field path within the model type, embedded fields are qualified, e.g. Audit.CreatedAt
+	Ref        string `json:",omitempty"` // Reference of the mapped field in its declaring type, empty for external types
+	DataType   string `json:",omitempty"` // Declared storage type, e.g. varchar(64) of gorm:"type:varchar(64)"
+	PrimaryKey bool   `json:",omitempty"`
+	field      *Field
+	owner      *Type
+}
+
+// QualifiedName returns the column as table.column
+func (c *Column) QualifiedName() string {
+	return c.Table + "." + c.Name
+}
+
+// MappedField returns the mapped field and its declaring type, nil for fields of external types (e.g. gorm.Model)
+func (c *Column) MappedField() (*Field, *Type) {
+	return c.field, c.owner
+}
+
+// MapModels maps model structs of the project to storage tables: GORM models (gorm tags, embedded gorm.Model or a
+// TableName() method), bun models (bun tags) and sqlx models (db tags). Table names come from TableName() returning
+// a literal or a bun table tag, otherwise from the convention (snake case plural of the type name); columns come from
+// column tags, otherwise from the snake case field name. Fields of models get Column set to table.column, Tables is
+// replaced with the mapped tables sorted by name
+func (p *Project) MapModels() {
+	p.Tables = nil
+	for _, pkg := range p.Packages {
+		types := pkg.modelTypes()
+		lookup := func(name string) *Type {
+			if aType, ok := types[name]; ok {
+				return aType
+			}
+			qualifier, typeName, ok := strings.Cut(name, ".")
+			if !ok {
+				return nil
+			}
+			if other := p.GetPackage(qualifier); other != nil {
+				return other.modelTypes()[typeName]
+			}
+			return nil
+		}
+		for _, file := range pkg.FileSet {
+			for _, aType := range file.Types {
+				table := MapModel(aType, lookup)
+				if table == nil {
+					continue
+				}
+				table.Package = pkg.Ref().Package
+				for _, column := range table.Columns {
+					if column.field == nil {
+						continue
+					}
+					owner := pkg.Ref()
+					if column.owner != aType {
+						owner = lookupOwner(p, column.owner)
+					}
+					owner.Type = column.owner.Name
+					column.Ref = column.field.Ref(owner).String()
+					if column.owner == aType {
+						column.field.Column = column.QualifiedName()
+					}
+				}
+				p.Tables = append(p.Tables, table)
+			}
+		}
+	}
+	sort.SliceStable(p.Tables, func(i, j int) bool { return p.Tables[i].Name < p.Tables[j].Name })
+}
+
+// lookupOwner returns the package reference of a type declared in the project
+func lookupOwner(project *Project, owner *Type) Ref {
+	for _, pkg := range project.Packages {
+		for _, file := range pkg.FileSet {
+			for _, aType := range file.Types {
+				if aType == owner {
+					return pkg.Ref()
+				}
+			}
+		}
+	}
+	return Ref{}
+}
+
+// modelTypes indexes struct types of a package by name
+func (p *Package) modelTypes() map[string]*Type {
+	result := map[string]*Type{}
+	for _, file := range p.FileSet {
+		for _, aType := range file.Types {
+			if aType.Kind == reflect.Struct {
+				result[aType.Name] = aType
+			}
+		}
+	}
+	return result
+}
+
+// MapModel maps a model struct to a storage table, nil when the type is not a model; lookup resolves embedded types
+// declared in the project by name as written, e.g. Audit or common.Audit
+func MapModel(aType *Type, lookup func(name string) *Type) *Table {
+	if aType == nil || aType.Kind != reflect.Struct {
+		return nil
+	}
+	mapping := modelMapping(aType, lookup, map[*Type]bool{})
+	if mapping == "" {
+		return nil
+	}
+	table := &Table{Mapping: mapping, Type: aType.Name}
+	table.Name, table.Inferred = modelTableName(aType, mapping)
+	table.Columns = mapColumns(aType, mapping, "", "", lookup, map[*Type]bool{})
+	for _, column := range table.Columns {
+		column.Table = table.Name
+	}
+	return table
+}
+
+// modelMapping returns the mapping convention of a struct, bun tags take precedence over gorm ones and gorm over db
+func modelMapping(aType *Type, lookup func(name string) *Type, visited map[*Type]bool) string {
+	visited[aType] = true
+	result := ""
+	if tableNameMethod(aType) != nil {
+		result = MappingGORM
+	}
+	for _, field := range aType.Fields {
+		typeName := fieldTypeName(field)
+		switch {
+		case typeName == "bun.BaseModel" || hasTag(field, "bun"):
+			return MappingBun
+		case typeName == "gorm.Model" || hasTag(field, "gorm"):
+			result = MappingGORM
+		case hasTag(field, "db") && result == "":
+			result = MappingSQLX
+		case field.IsEmbedded && result == "":
+			if embedded := lookup(typeName); embedded != nil && !visited[embedded] {
+				result = modelMapping(embedded, lookup, visited)
+			}
+		}
+	}
+	return result
+}
+
+// modelTableName returns the table name of a model and whether it is a guess
+func modelTableName(aType *Type, mapping string) (string, bool) {
+	if mapping == MappingBun {
+		for _, field := range aType.Fields {
+			if value, ok := field.Tag.Lookup("bun"); ok && fieldTypeName(field) == "bun.BaseModel" {
+				for _, option := range strings.Split(value, ",") {
+					if name, ok := strings.CutPrefix(strings.TrimSpace(option), "table:"); ok && name != "" {
+						return name, false
+					}
+				}
+			}
+		}
+	}
+	if method := tableNameMethod(aType); method != nil && method.Body != nil {
+		if match := tableNameReturn.FindStringSubmatch(strings.TrimSpace(method.Body.Text)); match != nil {
+			return match[1] + match[2], false
+		}
+	}
+	return Pluralize(SnakeCase(aType.Name)), mapping == MappingSQLX
+}
+
+// tableNameMethod returns the TableName() method of a type
+func tableNameMethod(aType *Type) *Function {
+	for _, method := range aType.Methods {
+		if method.Name == "TableName" && len(method.Parameters) == 0 {
+			return method
+		}
+	}
+	return nil
+}
+
+// mapColumns returns columns of struct fields, embedded structs are expanded with their column prefix
+func mapColumns(aType *Type, mapping, path, prefix string, lookup func(name string) *Type, visited map[*Type]bool) []*Column {
+	if visited[aType] {
+		return nil
+	}
+	visited[aType] = true
+	defer delete(visited, aType)
+	var result []*Column
+	for _, field := range aType.Fields {
+		typeName := fieldTypeName(field)
+		fieldPath := field.Name
+		if fieldPath == "" { // embedded fields are named by their type
+			fieldPath = typeName[strings.LastIndex(typeName, ".")+1:]
+		}
+		if path != "" {
+			fieldPath = path + "." + fieldPath
+		}
+		options := columnOptions(field, mapping)
+		if options.skip {
+			continue
+		}
+		switch {
+		case typeName == "gorm.Model" && mapping == MappingGORM:
+			for _, name := range []string{"ID", "CreatedAt", "UpdatedAt", "DeletedAt"} {
+				result = append(result, &Column{Name: prefix + SnakeCase(name), Field: fieldPath + "." + name, PrimaryKey: name == "ID"})
+			}
+			continue
+		case typeName == "bun.BaseModel":
+			continue
+		case field.IsEmbedded || options.embedded:
+			if embedded := lookup(typeName); embedded != nil {
+				result = append(result, mapColumns(embedded, mapping, fieldPath, prefix+options.prefix, lookup, visited)...)
+				continue
+			}
+		}
+		if options.name == "" {
+			if mapping == MappingSQLX || !field.IsExported || isAssociation(field, typeName, lookup) {
+				continue // sqlx maps tagged fields only, gorm and bun skip unexported fields and associations
+			}
+			options.name = SnakeCase(field.Name)
+		}
+		result = append(result, &Column{
+			Name:       prefix + options.name,
+			Field:      fieldPath,
+			DataType:   options.dataType,
+			PrimaryKey: options.primaryKey || (mapping == MappingGORM && field.Name == "ID"),
+			field:      field,
+			owner:      aType,
+		})
+	}
+	return result
+}
+
+// columnTag holds mapping options of a field tag
+type columnTag struct {
+	name       string
+	dataType   string
+	prefix     string
+	primaryKey bool
+	embedded   bool
+	skip       bool
+}
+
+// columnOptions parses the mapping tag of a field: gorm:"column:name;type:varchar(64);primaryKey;embedded;
+// embeddedPrefix:p_", bun:"name,pk,type:varchar(64)" or db:"name"
+func columnOptions(field *Field, mapping string) *columnTag {
+	result := &columnTag{}
+	switch mapping {
+	case MappingGORM:
+		value, _ := field.Tag.Lookup("gorm")
+		for _, option := range strings.Split(value, ";") {
+			key, argument, _ := strings.Cut(strings.TrimSpace(option), ":")
+			switch strings.ToLower(key) {
+			case "-":
+				result.skip = argument == "" || argument == "all"
+			case "column":
+				result.name = argument
+			case "type":
+				result.dataType = argument
+			case "primarykey", "primary_key":
+				result.primaryKey = true
+			case "embedded":
+				result.embedded = true
+			case "embeddedprefix":
+				result.prefix = argument
+			}
+		}
+		if result.name == "" {
+			if value, ok := field.Tag.Lookup("db"); ok {
+				result.name = strings.Split(value, ",")[0]
+			}
+		}
+	case MappingBun:
+		value, _ := field.Tag.Lookup("bun")
+		options := strings.Split(value, ",")
+		result.name, result.skip = strings.TrimSpace(options[0]), strings.TrimSpace(options[0]) == "-"
+		for _, option := range options[1:] {
+			key, argument, _ := strings.Cut(strings.TrimSpace(option), ":")
+			switch key {
+			case "pk":
+				result.primaryKey = true
+			case "type":
+				result.dataType = argument
+			case "embed":
+				result.embedded, result.prefix = true, argument
+			case "rel", "m2m":
+				result.skip = true
+			}
+		}
+	case MappingSQLX:
+		value, _ := field.Tag.Lookup("db")
+		result.name = strings.Split(value, ",")[0]
+		result.skip = result.name == "-"
+	}
+	if result.skip {
+		result.name = ""
+	}
+	return result
+}
+
+// isAssociation reports whether a field refers to other models rather than holding a column value, e.g. Orders []Order
+func isAssociation(field *Field, typeName string, lookup func(name string) *Type) bool {
+	if field.Type == nil {
+		return false
+	}
+	name := strings.TrimPrefix(field.Type.Name, "*")
+	if strings.HasPrefix(name, "[]") {
+		return name != "[]byte" && name != "[]uint8"
+	}
+	return lookup(typeName) != nil
+}
+
+// hasTag reports whether a field declares a tag key
+func hasTag(field *Field, key string) bool {
+	_, ok := field.Tag.Lookup(key)
+	return ok
+}
+
+// fieldTypeName returns the field type name without pointer and slice markers
+func fieldTypeName(field *Field) string {
+	if field.Type == nil {
+		return field.Name
+	}
+	return strings.TrimLeft(field.Type.Name, "*[]")
+}
+
+// SnakeCase converts a Go name to its snake case storage name, initialisms are kept together, e.g. user_id for
+// UserID and http_server for HTTPServer
+func SnakeCase(name string) string {
+	runes := []rune(name)
+	builder := strings.Builder{}
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			previous := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || (unicode.IsUpper(previous) && nextLower) {
+				builder.WriteByte('_')
+			}
+		}
+		builder.WriteRune(unicode.ToLower(r))
+	}
+	return builder.String()
+}
+
+// Pluralize returns the plural of the last word of a snake case name with regular English rules, e.g. categories
+func Pluralize(name string) string {
+	switch {
+	case name == "":
+		return name
+	case strings.HasSuffix(name, "y") && len(name) > 1 && !strings.ContainsRune("aeiou", rune(name[len(name)-2])):
+		return name[:len(name)-1] + "ies"
+	case strings.HasSuffix(name, "s"), strings.HasSuffix(name, "x"), strings.HasSuffix(name, "z"),
+		strings.HasSuffix(name, "ch"), strings.HasSuffix(name, "sh"):
+		return name + "es"
+	}
+	return name + "s"
+}
@@ -0,0 +1,92 @@
+package graph_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/linager/inspector/golang"
+	"github.com/viant/linager/inspector/graph"
+)
+
+func TestProject_MapModels(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"model/user.go": "package model\n\nimport \"gorm.io/gorm\"\n\n" +
+			"type Audit struct {\n\tCreatedBy string\n}\n\n" +
+			"type User struct {\n\tgorm.Model\n\tUserName string `gorm:\"column:user_name;type:varchar(64)\"`\n\tEmail    string `gorm:\"column:email_address\"`\n" +
+			"\tHomeURL  string\n\tAudit    Audit  `gorm:\"embedded;embeddedPrefix:audit_\"`\n\tOrders   []OrderItem\n\tsecret   string\n\tIgnored  string `gorm:\"-\"`\n}\n\n" +
+			"func (User) TableName() string {\n\treturn \"app_users\"\n}\n\n" +
+			"type OrderItem struct {\n\tID     int64 `gorm:\"primaryKey\"`\n\tUserID uint\n}\n",
+		"model/account.go": "package model\n\n" +
+			"type Account struct {\n\tID      int64  `db:\"id\"`\n\tBalance int64  `db:\"balance\"`\n\tNote    string `db:\"-\"`\n\tCache   string\n}\n\n" +
+			"type Entry struct {\n\tbun.BaseModel `bun:\"table:ledger_entries,alias:e\"`\n\tID     int64 `bun:\",pk\"`\n\tAmount int64 `bun:\"amount_cents,type:bigint\"`\n}\n\n" +
+			"type Plain struct {\n\tName string `json:\"name\"`\n}\n",
+	}
+	for name, content := range files {
+		assert.NoError(t, os.MkdirAll(filepath.Join(root, filepath.Dir(name)), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(root, name), []byte(content), 0644))
+	}
+	packages, err := golang.NewInspector(&graph.Config{IncludeUnexported: true, SkipAsset: true}).InspectPackages(root)
+	if !assert.NoError(t, err) {
+		return
+	}
+	project := &graph.Project{Packages: packages}
+	project.MapModels()
+
+	type column struct {
+		Name, Field, DataType string
+		PrimaryKey            bool
+	}
+	tables := map[string][]column{}
+	mappings := map[string]string{}
+	for _, table := range project.Tables {
+		mappings[table.Name] = table.Mapping + "/" + table.Type
+		for _, item := range table.Columns {
+			tables[table.Name] = append(tables[table.Name], column{Name: item.Name, Field: item.Field, DataType: item.DataType, PrimaryKey: item.PrimaryKey})
+		}
+	}
+	assert.Equal(t, map[string]string{
+		"accounts":       "sqlx/Account",
+		"app_users":      "gorm/User",
+		"ledger_entries": "bun/Entry",
+		"order_items":    "gorm/OrderItem",
+	}, mappings)
+	assert.Equal(t, []column{
+		{Name: "id", Field: "Model.ID", PrimaryKey: true},
+		{Name: "created_at", Field: "Model.CreatedAt"},
+		{Name: "updated_at", Field: "Model.UpdatedAt"},
+		{Name: "deleted_at", Field: "Model.DeletedAt"},
+		{Name: "user_name", Field: "UserName", DataType: "varchar(64)"},
+		{Name: "email_address", Field: "Email"},
+		{Name: "home_url", Field: "HomeURL"},
+		{Name: "audit_created_by", Field: "Audit.CreatedBy"},
+	}, tables["app_users"], "TableName, column overrides, implicit snake case columns and embedded prefixes")
+	assert.Equal(t, []column{{Name: "id", Field: "ID", PrimaryKey: true}, {Name: "user_id", Field: "UserID"}}, tables["order_items"])
+	assert.Equal(t, []column{{Name: "id", Field: "ID"}, {Name: "balance", Field: "Balance"}}, tables["accounts"])
+	assert.Equal(t, []column{{Name: "id", Field: "ID", PrimaryKey: true}, {Name: "amount_cents", Field: "Amount", DataType: "bigint"}}, tables["ledger_entries"])
+
+	user := project.GetPackage("model").FileSet[1].LookupType("User")
+	if assert.NotNil(t, user) {
+		assert.Equal(t, "app_users.email_address", user.GetField("Email").Column)
+		assert.Empty(t, user.GetField("Orders").Column, "associations are not columns")
+	}
+	for _, table := range project.Tables {
+		if table.Name != "app_users" {
+			continue
+		}
+		field, owner := table.Columns[7].MappedField()
+		if assert.NotNil(t, field) {
+			assert.Equal(t, "CreatedBy", field.Name)
+			assert.Equal(t, "Audit", owner.Name)
+			assert.Equal(t, project.Packages[0].ImportPath+"#Audit/CreatedBy", table.Columns[7].Ref)
+		}
+		field, _ = table.Columns[0].MappedField()
+		assert.Nil(t, field, "gorm.Model is external")
+	}
+
+	assert.Equal(t, "http_server", graph.SnakeCase("HTTPServer"))
+	assert.Equal(t, "categories", graph.Pluralize("category"))
+	assert.Equal(t, "addresses", graph.Pluralize("address"))
+}
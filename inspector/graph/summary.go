@@ -0,0 +1,130 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxDescriptionLength is the maximum length of a synthesized file description
+const maxDescriptionLength = 160
+
+// FileSummary lists symbols exported by a file, e.g. for search indexes
+type FileSummary struct {
+	Path        string   `json:"path"`
+	Types       []string `json:"types,omitempty"`
+	Functions   []string `json:"functions,omitempty"`
+	Methods     []string `json:"methods,omitempty"` // Type.Method
+	Constants   []string `json:"constants,omitempty"`
+	Variables   []string `json:"variables,omitempty"`
+	Imports     int      `json:"imports"` // Import fan-out
+	Lines       int      `json:"lines"`
+	Description string   `json:"description,omitempty"` // Package comment first sentence or first exported type comment
+}
+
+// Exported returns the number of exported symbols
+func (s *FileSummary) Exported() int {
+	return len(s.Types) + len(s.Functions) + len(s.Methods) + len(s.Constants) + len(s.Variables)
+}
+
+// String returns a one line summary, e.g. app/server.go: 2 types, 3 functions, 4 methods, 0 constants, 1 variables, 5 imports, 120 lines - Package app serves requests.
+func (s *FileSummary) String() string {
+	ret := fmt.Sprintf("%s: %d types, %d functions, %d methods, %d constants, %d variables, %d imports, %d lines",
+		s.Path, len(s.Types), len(s.Functions), len(s.Methods), len(s.Constants), len(s.Variables), s.Imports, s.Lines)
+	if s.Description != "" {
+		ret += " - " + s.Description
+	}
+	return ret
+}
+
+// Summary returns exported symbols of the file, the summary is computed once and cached until the file is reindexed
+// (IndexTypes, IndexFunctions), as Coder does after mutations
+func (f *File) Summary() *FileSummary {
+	if f.summary != nil {
+		return f.summary
+	}
+	ret := &FileSummary{Path: f.Path, Imports: len(f.Imports), Lines: f.Lines}
+	for _, aType := range f.Types {
+		if aType == nil || !aType.IsExported {
+			continue
+		}
+		ret.Types = append(ret.Types, aType.Name)
+		for _, method := range aType.Methods {
+			if method.IsExported {
+				ret.Methods = append(ret.Methods, aType.Name+"."+method.Name)
+			}
+		}
+		if ret.Description == "" && aType.Comment != nil {
+			ret.Description = FirstSentence(aType.Comment.Text)
+		}
+	}
+	for _, function := range f.Functions {
+		if function == nil || !function.IsExported {
+			continue
+		}
+		if function.Receiver != "" {
+			ret.Methods = append(ret.Methods, strings.TrimPrefix(function.Receiver, "*")+"."+function.Name)
+			continue
+		}
+		ret.Functions = append(ret.Functions, function.Name)
+	}
+	for _, constant := range f.Constants {
+		if constant.IsExported {
+			ret.Constants = append(ret.Constants, constant.Name)
+		}
+	}
+	for _, variable := range f.Variables {
+		if variable.IsExported {
+			ret.Variables = append(ret.Variables, variable.Name)
+		}
+	}
+	if doc := FirstSentence(f.Doc); doc != "" {
+		ret.Description = doc
+	}
+	f.summary = ret
+	return ret
+}
+
+// invalidateSummary drops the cached summary
+func (f *File) invalidateSummary() {
+	f.summary = nil
+}
+
+// Summaries returns summaries of all project files
+func (p *Project) Summaries() []*FileSummary {
+	var result []*FileSummary
+	for _, pkg := range p.Packages {
+		for _, file := range pkg.FileSet {
+			result = append(result, file.Summary())
+		}
+	}
+	return result
+}
+
+// CommentText returns the trimmed text of a comment node, empty for nil
+func CommentText(comment *LocationNode) string {
+	if comment == nil {
+		return ""
+	}
+	return strings.TrimSpace(comment.Text)
+}
+
+// FirstSentence returns the first sentence of a comment without comment markers, truncated to a description length
+func FirstSentence(comment string) string {
+	var words []string
+	for _, line := range strings.Split(comment, "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimPrefix(line, "//")
+		line = strings.TrimPrefix(line, "/**")
+		line = strings.TrimSuffix(line, "*/")
+		line = strings.TrimPrefix(line, "*")
+		words = append(words, strings.Fields(line)...)
+	}
+	text := strings.Join(words, " ")
+	if index := strings.Index(text, ". "); index != -1 {
+		text = text[:index+1]
+	}
+	if len(text) > maxDescriptionLength {
+		text = text[:maxDescriptionLength] + "…"
+	}
+	return text
+}
@@ -0,0 +1,105 @@
+package graph
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TagDerivation links a struct tag value to a package constant declaring the same string literal
+type TagDerivation struct {
+	Key      string    // Tag key, e.g. db
+	Value    string    // Tag value name, e.g. user_name of db:"user_name,omitempty"
+	Constant string    // Constant name
+	Path     string    // Path of the file declaring the constant
+	Location *Location // Constant location
+}
+
+// LinkTagConstants records on struct fields the package constants whose string literal value equals a tag value
+// name (the value up to the first comma, the column of gorm:"column:name"); matching is exact, constants declared
+// by expressions are ignored
+func (p *Package) LinkTagConstants() {
+	constants := map[string][]*TagDerivation{}
+	for _, file := range p.FileSet {
+		for _, constant := range file.Constants {
+			value, err := strconv.Unquote(strings.TrimSpace(constant.Value))
+			if err != nil || value == "" {
+				continue
+			}
+			constants[value] = append(constants[value], &TagDerivation{Constant: constant.Name, Path: file.Path, Location: constant.Location})
+		}
+	}
+	for _, file := range p.FileSet {
+		for _, aType := range file.Types {
+			for _, field := range aType.Fields {
+				field.TagDerivedFrom = nil
+				for _, tag := range TagValues(field.Tag) {
+					for _, derivation := range constants[tag.Value] {
+						linked := *derivation
+						linked.Key, linked.Value = tag.Key, tag.Value
+						field.TagDerivedFrom = append(field.TagDerivedFrom, &linked)
+					}
+				}
+			}
+		}
+	}
+}
+
+// TagValue is a struct tag key with its value name
+type TagValue struct {
+	Key   string
+	Value string
+}
+
+// TagValues returns value names of a struct tag ordered by key: values up to the first comma, gorm column names
+func TagValues(tag reflect.StructTag) []TagValue {
+	var result []TagValue
+	for _, key := range tagKeys(tag) {
+		value, _ := tag.Lookup(key)
+		if key == "gorm" {
+			for _, part := range strings.Split(value, ";") {
+				if column, ok := strings.CutPrefix(strings.TrimSpace(part), "column:"); ok && column != "" {
+					result = append(result, TagValue{Key: key, Value: column})
+				}
+			}
+			continue
+		}
+		if name := strings.Split(value, ",")[0]; name != "" && name != "-" {
+			result = append(result, TagValue{Key: key, Value: name})
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result
+}
+
+// tagKeys returns keys of a conventional struct tag, e.g. json and db of json:"id" db:"user_id"
+func tagKeys(tag reflect.StructTag) []string {
+	var result []string
+	text := string(tag)
+	for {
+		text = strings.TrimLeft(text, " ")
+		i := 0
+		for i < len(text) && text[i] > ' ' && text[i] != ':' && text[i] != '"' && text[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(text) || text[i] != ':' || text[i+1] != '"' {
+			return result
+		}
+		key := text[:i]
+		text = text[i+1:]
+		// scan the quoted value
+		i = 1
+		for i < len(text) && text[i] != '"' {
+			if text[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(text) {
+			return result
+		}
+		result = append(result, key)
+		text = text[i+1:]
+	}
+}
@@ -0,0 +1,25 @@
+package graph_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/linager/inspector/graph"
+	"reflect"
+	"testing"
+)
+
+func TestPackage_LinkTagConstants(t *testing.T) {
+	location := &graph.Location{Start: 20, End: 34}
+	name := &graph.Field{Name: "Name", Tag: `json:"name" db:"user_name,omitempty"`}
+	email := &graph.Field{Name: "Email", Tag: `gorm:"column:email;not null"`}
+	pkg := &graph.Package{FileSet: []*graph.File{
+		{Path: "repo/columns.go", Constants: []*graph.Constant{
+			{Name: "UserNameColumn", Value: `"user_name"`, Location: location},
+			{Name: "EmailColumn", Value: `"e" + "mail"`},
+		}},
+		{Path: "repo/user.go", Types: []*graph.Type{{Name: "User", Kind: reflect.Struct, Fields: []*graph.Field{name, email}}}},
+	}}
+	pkg.LinkTagConstants()
+	assert.Equal(t, []*graph.TagDerivation{{Key: "db", Value: "user_name", Constant: "UserNameColumn", Path: "repo/columns.go", Location: location}}, name.TagDerivedFrom)
+	assert.Empty(t, email.TagDerivedFrom, "constant expressions are not matched")
+	assert.Equal(t, []graph.TagValue{{Key: "gorm", Value: "email"}}, graph.TagValues(email.Tag))
+}
@@ -0,0 +1,367 @@
+package graph
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	// identExpr matches identifiers optionally qualified by a package, e.g. Add or calc.Add
+	identExpr = regexp.MustCompile(`(?:\b([A-Za-z_][A-Za-z0-9_]*)\.)?\b([A-Za-z_][A-Za-z0-9_]*)\b`)
+	// selectorCallExpr matches selected member calls, e.g. .Find(
+	selectorCallExpr = regexp.MustCompile(`\.([A-Za-z_][A-Za-z0-9_]*)\s*\(`)
+	// literalExpr matches string, character and raw string literals and comments, they never reference subjects
+	literalExpr = regexp.MustCompile("(?s)\"(?:[^\"\\\\\n]|\\\\.)*\"|'(?:[^'\\\\\n]|\\\\.)*'|`[^`]*`|//[^\n]*|/\\*.*?\\*/")
+	// javaTestAnnotations mark JUnit and TestNG test methods
+	javaTestAnnotations = []string{"@Test", "@ParameterizedTest", "@RepeatedTest", "@TestFactory", "@TestTemplate"}
+)
+
+// TestCoverage summarizes test linkage of production types, functions and methods, see Project.LinkTests
+type TestCoverage struct {
+	Tests    int   // Test functions
+	Linked   int   // Test functions linked to at least one subject
+	Symbols  int   // Production types, functions and methods
+	Tested   int   // Production symbols linked to at least one test
+	Untested []Ref // Production symbols without linked tests, ordered by reference
+}
+
+// Ratio returns the share of tested production symbols, 0 without symbols
+func (c *TestCoverage) Ratio() float64 {
+	if c.Symbols == 0 {
+		return 0
+	}
+	return float64(c.Tested) / float64(c.Symbols)
+}
+
+// IsTestFile reports whether a file holds tests: Go _test.go files and Java *Test, *Tests, *IT and *ITCase classes
+func IsTestFile(name string) bool {
+	if strings.HasSuffix(name, "_test.go") {
+		return true
+	}
+	name, ok := strings.CutSuffix(name, ".java")
+	if !ok {
+		return false
+	}
+	for _, suffix := range []string{"Test", "Tests", "IT", "ITCase"} {
+		if strings.HasSuffix(name, suffix) && name != suffix {
+			return true
+		}
+	}
+	return false
+}
+
+// isTestFunction reports whether a test file function is a test: Go Test, Benchmark, Fuzz and Example functions,
+// Java methods annotated with @Test (and other JUnit test annotations) or named test*
+func isTestFunction(function *Function, isGo bool) bool {
+	if isGo {
+		if function.Receiver != "" {
+			return false
+		}
+		for _, prefix := range []string{"Test", "Benchmark", "Fuzz", "Example"} {
+			if rest, ok := strings.CutPrefix(function.Name, prefix); ok && (rest == "" || rest[0] == '_' || !isLower(rest[0])) {
+				return function.Name != "TestMain"
+			}
+		}
+		return false
+	}
+	if function.Annotation != nil {
+		for _, annotation := range javaTestAnnotations {
+			if regexp.MustCompile(regexp.QuoteMeta(annotation) + `\b`).MatchString(function.Annotation.Text) {
+				return true
+			}
+		}
+	}
+	return strings.HasPrefix(function.Name, "test") && len(function.Name) > 4 && !isLower(function.Name[4])
+}
+
+func isLower(c byte) bool {
+	return c >= 'a' && c <= 'z'
+}
+
+// testSubject is a production symbol a test can be linked to
+type testSubject struct {
+	ref      Ref
+	owner    string // Declaring type of methods
+	function *Function
+	aType    *Type
+}
+
+// link records test linkage on both the subject and the test function
+func (s *testSubject) link(test *Function, testRef Ref) {
+	if s.function != nil {
+		s.function.TestedBy = appendRef(s.function.TestedBy, testRef)
+	} else {
+		s.aType.TestedBy = appendRef(s.aType.TestedBy, testRef)
+	}
+	test.Subjects = appendRef(test.Subjects, s.ref)
+}
+
+// testIndex holds production symbols of a package
+type testIndex struct {
+	symbols map[string]*testSubject   // package level type or function name -> subject
+	methods map[string][]*testSubject // method name -> subjects
+}
+
+// LinkTests links test functions of test files (see IsTestFile) to production types, functions and methods of the
+// project they reference: Subjects of a test function and TestedBy of subjects are replaced with the linkage.
+// Identifiers of test bodies, including table-driven test cases and package level variables of the test file the
+// test refers to, are resolved within the package or, when qualified, through file imports; methods are linked when
+// selected by a call and their type is referenced by the test or declared in its package. Test files are only
+// inspected with Config.SkipTests disabled.
+func (p *Project) LinkTests() *TestCoverage {
+	coverage := &TestCoverage{}
+	indexes := map[*Package]*testIndex{}
+	for _, pkg := range p.Packages {
+		indexes[pkg] = p.indexSubjects(pkg, coverage)
+	}
+	for _, pkg := range p.Packages {
+		for _, file := range pkg.FileSet {
+			if !IsTestFile(fileName(file)) {
+				continue
+			}
+			isGo := strings.HasSuffix(fileName(file), ".go")
+			scopes := p.testScopes(file, indexes, isGo)
+			local := indexes[pkg]
+			if isGo && strings.HasSuffix(file.Package, "_test") && !strings.HasSuffix(pkg.Name, "_test") {
+				local = nil // external test package
+			}
+			variables := map[string]string{}
+			for _, variable := range file.Variables {
+				if variable.Location != nil {
+					variables[variable.Name] = variable.Location.Raw
+				} else {
+					variables[variable.Name] = variable.Value
+				}
+			}
+			link := func(test *Function, owner Ref) {
+				coverage.Tests++
+				testRef := test.Ref(owner)
+				p.linkTest(test, testRef, testText(test, variables), scopes, local)
+				if len(test.Subjects) > 0 {
+					coverage.Linked++
+				}
+			}
+			for _, function := range file.Functions {
+				function.Subjects = nil
+				if isTestFunction(function, isGo) {
+					link(function, pkg.Ref())
+				}
+			}
+			for _, aType := range file.Types {
+				for _, method := range aType.Methods {
+					method.Subjects = nil
+					if !isGo && isTestFunction(method, isGo) {
+						link(method, aType.Ref())
+					}
+				}
+			}
+		}
+	}
+	for _, index := range indexes {
+		for _, subject := range index.subjects() {
+			tested := subject.aType != nil && len(subject.aType.TestedBy) > 0 || subject.function != nil && len(subject.function.TestedBy) > 0
+			if tested {
+				coverage.Tested++
+			} else {
+				coverage.Untested = append(coverage.Untested, subject.ref)
+			}
+		}
+	}
+	sort.Slice(coverage.Untested, func(i, j int) bool {
+		return coverage.Untested[i].String() < coverage.Untested[j].String()
+	})
+	return coverage
+}
+
+// indexSubjects indexes production symbols of a package, resetting their test linkage
+func (p *Project) indexSubjects(pkg *Package, coverage *TestCoverage) *testIndex {
+	index := &testIndex{symbols: map[string]*testSubject{}, methods: map[string][]*testSubject{}}
+	pkgRef := pkg.Ref()
+	for _, file := range pkg.FileSet {
+		if IsTestFile(fileName(file)) {
+			continue
+		}
+		addMethod := func(method *Function, owner string) {
+			method.TestedBy = nil
+			subject := &testSubject{ref: method.Ref(NewTypeRef(pkgRef.Package, owner)), owner: owner, function: method}
+			index.methods[method.Name] = append(index.methods[method.Name], subject)
+			coverage.Symbols++
+		}
+		for _, aType := range file.Types {
+			aType.TestedBy = nil
+			index.symbols[aType.qualifiedName()] = &testSubject{ref: aType.Ref(), aType: aType}
+			coverage.Symbols++
+			for _, method := range aType.Methods {
+				addMethod(method, aType.qualifiedName())
+			}
+		}
+		for _, function := range file.Functions {
+			if function.Receiver != "" {
+				owner, _, _ := strings.Cut(strings.TrimPrefix(function.Receiver, "*"), "[")
+				addMethod(function, owner)
+				continue
+			}
+			if function.Name == "main" || function.Name == "init" {
+				continue
+			}
+			function.TestedBy = nil
+			index.symbols[function.Name] = &testSubject{ref: function.Ref(pkgRef), function: function}
+			coverage.Symbols++
+		}
+	}
+	return index
+}
+
+// subjects returns indexed subjects
+func (i *testIndex) subjects() []*testSubject {
+	var result []*testSubject
+	for _, subject := range i.symbols {
+		result = append(result, subject)
+	}
+	for _, subjects := range i.methods {
+		result = append(result, subjects...)
+	}
+	return result
+}
+
+// testScopes returns indexes of packages imported by a test file by local name; Java single type imports are keyed
+// by the imported type name
+func (p *Project) testScopes(file *File, indexes map[*Package]*testIndex, isGo bool) map[string]*testIndex {
+	result := map[string]*testIndex{}
+	for _, imp := range file.Imports {
+		if imported := p.lookupImport(imp.Path); imported != nil {
+			result[imp.LocalName()] = indexes[imported]
+			continue
+		}
+		if isGo {
+			continue
+		}
+		if pkgPath, typeName, ok := cutLast(imp.Path, "."); ok {
+			if imported := p.lookupImport(pkgPath); imported != nil {
+				result[typeName] = indexes[imported]
+			}
+		}
+	}
+	return result
+}
+
+// linkTest links a test function to subjects referenced by its text, local is the index of the test package, nil
+// for Go external test packages
+func (p *Project) linkTest(test *Function, testRef Ref, text string, scopes map[string]*testIndex, local *testIndex) {
+	text = literalExpr.ReplaceAllString(text, "")
+	linkedTypes := map[*testIndex]map[string]bool{}
+	linked := func(index *testIndex, subject *testSubject) {
+		subject.link(test, testRef)
+		if subject.aType != nil {
+			if linkedTypes[index] == nil {
+				linkedTypes[index] = map[string]bool{}
+			}
+			linkedTypes[index][subject.ref.Type] = true
+		}
+	}
+	for _, match := range identExpr.FindAllStringSubmatch(text, -1) {
+		qualifier, name := match[1], match[2]
+		if qualifier != "" {
+			if index, ok := scopes[qualifier]; ok {
+				if subject, ok := index.symbols[name]; ok {
+					linked(index, subject)
+				}
+			}
+			continue
+		}
+		if index, ok := scopes[name]; ok { // Java single type import
+			if subject, ok := index.symbols[name]; ok {
+				linked(index, subject)
+				continue
+			}
+		}
+		if local != nil {
+			if subject, ok := local.symbols[name]; ok {
+				linked(local, subject)
+			}
+		}
+	}
+	indexes := scopeIndexes(scopes)
+	if local != nil {
+		indexes = append([]*testIndex{local}, indexes...)
+	}
+	for _, match := range selectorCallExpr.FindAllStringSubmatch(text, -1) {
+		for _, index := range indexes {
+			for _, subject := range index.methods[match[1]] {
+				if index == local || linkedTypes[index][subject.owner] {
+					linked(index, subject)
+				}
+			}
+		}
+	}
+}
+
+// scopeIndexes returns distinct indexes of test file scopes
+func scopeIndexes(scopes map[string]*testIndex) []*testIndex {
+	var result []*testIndex
+	seen := map[*testIndex]bool{}
+	for _, name := range sortedKeys(scopes) {
+		if index := scopes[name]; !seen[index] {
+			seen[index] = true
+			result = append(result, index)
+		}
+	}
+	return result
+}
+
+func sortedKeys(scopes map[string]*testIndex) []string {
+	var result []string
+	for key := range scopes {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// testText returns the source of a test function followed by package level variables of its file it refers to,
+// e.g. table-driven test cases
+func testText(test *Function, variables map[string]string) string {
+	var parts []string
+	if test.Location != nil && test.Location.Raw != "" {
+		parts = append(parts, test.Location.Raw)
+	} else if test.Body != nil {
+		parts = append(parts, test.Body.Text)
+	}
+	parts = append(parts, test.References...)
+	text := strings.Join(parts, "\n")
+	for name, value := range variables {
+		if regexp.MustCompile(`\b` + regexp.QuoteMeta(name) + `\b`).MatchString(text) {
+			parts = append(parts, value)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// appendRef appends a reference unless already present
+func appendRef(refs []Ref, ref Ref) []Ref {
+	for _, candidate := range refs {
+		if candidate.String() == ref.String() {
+			return refs
+		}
+	}
+	return append(refs, ref)
+}
+
+// fileName returns the file name, derived from its path when not set
+func fileName(file *File) string {
+	if file.Name != "" {
+		return file.Name
+	}
+	index := strings.LastIndexAny(file.Path, `/\`)
+	return file.Path[index+1:]
+}
+
+// cutLast slices text around the last separator
+func cutLast(text, sep string) (string, string, bool) {
+	index := strings.LastIndex(text, sep)
+	if index == -1 {
+		return text, "", false
+	}
+	return text[:index], text[index+len(sep):], true
+}
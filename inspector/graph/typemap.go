@@ -0,0 +1,203 @@
+package graph
+
+import (
+	"strings"
+)
+
+// javaToGo maps Java primitive and java.lang types to normalized (Go) type names
+var javaToGo = map[string]string{
+	"boolean":   "bool",
+	"char":      "rune",
+	"byte":      "byte",
+	"short":     "int16",
+	"int":       "int32",
+	"long":      "int64",
+	"float":     "float32",
+	"double":    "float64",
+	"void":      "void",
+	"String":    "string",
+	"Boolean":   "bool",
+	"Character": "rune",
+	"Byte":      "byte",
+	"Short":     "int16",
+	"Integer":   "int32",
+	"Long":      "int64",
+	"Float":     "float32",
+	"Double":    "float64",
+	"Object":    "interface{}",
+}
+
+// goToJava maps normalized (Go) type names to Java primitive types
+var goToJava = map[string]string{
+	"bool":        "boolean",
+	"rune":        "char",
+	"byte":        "byte",
+	"uint8":       "byte",
+	"int8":        "byte",
+	"int16":       "short",
+	"uint16":      "char",
+	"int32":       "int",
+	"uint32":      "long",
+	"int":         "long",
+	"int64":       "long",
+	"uint":        "long",
+	"uint64":      "long",
+	"float32":     "float",
+	"float64":     "double",
+	"void":        "void",
+	"string":      "String",
+	"interface{}": "Object",
+	"any":         "Object",
+	"error":       "Exception",
+	"time.Time":   "java.time.Instant",
+}
+
+// javaBoxed maps Java primitives to wrapper classes used as generic arguments
+var javaBoxed = map[string]string{
+	"boolean": "Boolean",
+	"char":    "Character",
+	"byte":    "Byte",
+	"short":   "Short",
+	"int":     "Integer",
+	"long":    "Long",
+	"float":   "Float",
+	"double":  "Double",
+}
+
+// javaCollections lists Java generic containers mapped to Go slices
+var javaCollections = map[string]bool{"List": true, "ArrayList": true, "LinkedList": true, "Set": true, "HashSet": true, "Collection": true, "Iterable": true}
+
+// javaMaps lists Java generic containers mapped to Go maps
+var javaMaps = map[string]bool{"Map": true, "HashMap": true, "LinkedHashMap": true, "TreeMap": true}
+
+// JavaTypeFor returns Java type name for a type: the original RawName of Java parsed types,
+// otherwise the normalized Name mapped to Java (e.g. string -> String, []int32 -> int[], map[string]int64 -> Map<String, Long>)
+func JavaTypeFor(t *Type) string {
+	if t == nil {
+		return ""
+	}
+	if t.RawName != "" {
+		return t.RawName
+	}
+	return javaTypeName(t.Name)
+}
+
+// GoTypeFor returns normalized (Go) type name for a type, Java RawName is converted
+// (e.g. boolean -> bool, List<User> -> []*User, Map<String, Integer> -> map[string]int32, String... -> []string)
+func GoTypeFor(t *Type) string {
+	if t == nil {
+		return ""
+	}
+	if t.RawName == "" {
+		return t.Name
+	}
+	return goTypeName(t.RawName)
+}
+
+func javaTypeName(name string) string {
+	switch {
+	case name == "":
+		return ""
+	case strings.HasPrefix(name, "[]"):
+		return javaTypeName(name[2:]) + "[]"
+	case strings.HasPrefix(name, "*"):
+		return javaTypeName(name[1:])
+	case strings.HasPrefix(name, "map["):
+		if end := ClosingBracket(name, 3); end != -1 {
+			return "Map<" + boxedJavaType(javaTypeName(name[4:end])) + ", " + boxedJavaType(javaTypeName(name[end+1:])) + ">"
+		}
+	}
+	if javaName, ok := goToJava[name]; ok {
+		return javaName
+	}
+	return name
+}
+
+func goTypeName(name string) string {
+	name = strings.TrimSpace(name)
+	switch {
+	case name == "":
+		return ""
+	case strings.HasSuffix(name, "..."):
+		return "[]" + goTypeName(strings.TrimSuffix(name, "..."))
+	case strings.HasSuffix(name, "[]"):
+		return "[]" + goTypeName(strings.TrimSuffix(name, "[]"))
+	}
+	if idx := strings.Index(name, "<"); idx != -1 && strings.HasSuffix(name, ">") {
+		base := SimpleName(name[:idx])
+		args := splitTypeArgs(name[idx+1 : len(name)-1])
+		switch {
+		case javaCollections[base] && len(args) == 1:
+			return "[]" + goTypeName(args[0])
+		case javaMaps[base] && len(args) == 2:
+			return "map[" + goTypeName(args[0]) + "]" + goTypeName(args[1])
+		case base == "Optional" && len(args) == 1:
+			return "*" + strings.TrimPrefix(goTypeName(args[0]), "*")
+		}
+		var goArgs []string
+		for _, arg := range args {
+			goArgs = append(goArgs, goTypeName(arg))
+		}
+		return "*" + base + "[" + strings.Join(goArgs, ", ") + "]"
+	}
+	if goName, ok := javaToGo[SimpleName(name)]; ok {
+		return goName
+	}
+	if len(name) == 1 { // generic type parameter, e.g. T
+		return name
+	}
+	return "*" + SimpleName(name)
+}
+
+// boxedJavaType returns wrapper class for Java primitives
+func boxedJavaType(name string) string {
+	if boxed, ok := javaBoxed[name]; ok {
+		return boxed
+	}
+	return name
+}
+
+// SimpleName strips package or enclosing type qualifier, e.g. java.lang.String -> String, Type/field -> field
+func SimpleName(name string) string {
+	if idx := strings.LastIndexAny(name, "./"); idx != -1 {
+		return name[idx+1:]
+	}
+	return name
+}
+
+// splitTypeArgs splits generic type arguments at top level commas
+func splitTypeArgs(args string) []string {
+	var result []string
+	depth, start := 0, 0
+	for i, r := range args {
+		switch r {
+		case '<':
+			depth++
+		case '>':
+			depth--
+		case ',':
+			if depth == 0 {
+				result = append(result, strings.TrimSpace(args[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(result, strings.TrimSpace(args[start:]))
+}
+
+// ClosingBracket returns index of the square bracket closing the first one opened at or after start, -1 when unbalanced
+func ClosingBracket(text string, start int) int {
+	depth := 0
+	for i := start; i < len(text); i++ {
+		switch text[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
@@ -7,7 +7,8 @@ import (
 
 // Type represents a parsed Go type with rich metadata
 type Type struct {
-	Name          string            // Type name
+	Name          string            // Type name, normalized across languages (e.g. string, bool, int32)
+	RawName       string            // Original source language type name (e.g. String, boolean, List<User>), empty when not recorded
 	Kind          reflect.Kind      // Go reflect kind
 	Tag           reflect.StructTag // Tags for struct fields
 	Package       string            // Package name
@@ -24,7 +25,12 @@ type Type struct {
 	Implements []string      // Interfaces this type implements
 	IsPointer  bool          // Whether the type is a pointer
 	Location   *Location     // Location of the type in the source code
+	ParentType string        // Enclosing type of nested and anonymous types, e.g. Outer for Outer.Inner
+	Anonymous  bool          // Synthetic type of an inline struct declaration, see AnonymousTypeName
 	Extends    []string
+	Enum       *Enum    // Constants of the type with display names, nil for types without typed constants
+	TestedBy   []Ref    // Test functions referencing the type, see Project.LinkTests
+	UsageRefs  []string `json:",omitempty"` // Lineage data point IDs resolved to the type, see linage.AttachGraph
 
 	fieldMap  map[string]int // Map of fields for quick lookup
 	methodMap map[string]int // Map of methods for quick lookup
@@ -39,6 +45,11 @@ func (f *Type) GetField(name string) *Field {
 	if idx, ok := f.fieldMap[name]; ok && idx < len(f.Fields) {
 		return f.Fields[idx]
 	}
+	for _, field := range f.Fields { // fields assigned directly are not indexed
+		if field.Name == name {
+			return field
+		}
+	}
 	return nil
 }
 
@@ -50,6 +61,11 @@ func (f *Type) GetMethod(name string) *Function {
 	if idx, ok := f.methodMap[name]; ok && idx < len(f.Methods) {
 		return f.Methods[idx]
 	}
+	for _, method := range f.Methods { // methods assigned directly are not indexed
+		if method.Name == name {
+			return method
+		}
+	}
 	return nil
 }
 
@@ -150,6 +166,7 @@ func (t *Type) RemoveMethod(methodName string) bool {
 func (t *Type) Clone() *Type {
 	newType := &Type{
 		Name:          t.Name,
+		RawName:       t.RawName,
 		Kind:          t.Kind,
 		Tag:           t.Tag,
 		Package:       t.Package,
@@ -158,6 +175,7 @@ func (t *Type) Clone() *Type {
 		KeyType:       t.KeyType,
 		IsExported:    t.IsExported,
 		IsPointer:     t.IsPointer,
+		Anonymous:     t.Anonymous,
 		Implements:    make([]string, len(t.Implements)),
 		Extends:       make([]string, len(t.Extends)),
 		TypeParams:    make([]*TypeParam, len(t.TypeParams)),
@@ -310,6 +328,25 @@ type Field struct {
 	IsEmbedded bool
 	IsStatic   bool
 	IsConstant bool
+
+	IsTypeParam bool     // Whether the field type is a bare generic type parameter
+	Optional    bool     // Whether the field value can be absent or nil (pointer, slice, map, interface, omitempty, @Nullable, Optional<T>)
+	Labels      []string // Sensitivity labels assigned by a Classifier, e.g. pii, financial
+
+	Default     string    // Default value expression: Java initializer, Go constructor (New*, Make*) literal value or Java zero value
+	DefaultFrom *Location // Location of the initializer or constructor literal element setting Default, nil for zero values
+
+	TagDerivedFrom []*TagDerivation // Package constants declaring tag values, see Package.LinkTagConstants
+	Column         string           `json:",omitempty"` // Storage column mapped by the field as table.column, see Project.MapModels
+	UsageRefs      []string         `json:",omitempty"` // Lineage data point IDs resolved to the field, see linage.AttachGraph
+}
+
+// DefaultValue returns the field default, the zero value of its type when no default is set
+func (f *Field) DefaultValue() string {
+	if f.Default != "" {
+		return f.Default
+	}
+	return ZeroValue(f.Type)
 }
 
 func (f *Field) Content() string {
@@ -334,7 +371,13 @@ type Function struct {
 	IsStatic      bool      // Whether the method is static (class method)
 	IsConstructor bool
 	Signature     string
+	References    []string // Symbols referenced by the body (called functions, instantiated types)
+	Complexity    int      // Cyclomatic complexity of the body, 0 if unknown
 	Hash          int32
+	LocalTypes    []*Type  // Types declared in the body, e.g. Java anonymous classes
+	TestedBy      []Ref    // Test functions referencing the function, see Project.LinkTests
+	Subjects      []Ref    // Production types and functions referenced by a test function, see Project.LinkTests
+	UsageRefs     []string `json:",omitempty"` // Lineage data point IDs resolved to the function, its local variables included, see linage.AttachGraph
 }
 
 // Content returns the content of the method including its receiver, parameters, and results
@@ -353,6 +396,9 @@ type TypeParam struct {
 
 // Parameter represents a function parameter or result
 type Parameter struct {
-	Name string
-	Type *Type
+	Name       string
+	Type       *Type    // Parameter type, the element type for variadic parameters
+	IsVariadic bool     // Whether the parameter accepts a variable number of arguments (...T in Go, T... in Java)
+	Default    string   // Default value expression, e.g. false in JS (value = false)
+	UsageRefs  []string `json:",omitempty"` // Lineage data point IDs resolved to the parameter, see linage.AttachGraph
 }
@@ -0,0 +1,335 @@
+package graph
+
+import (
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// KeepDirective marks a declaration comment or annotation of an exported symbol that must stay exported
+const KeepDirective = "//linager:keep"
+
+// Unexport candidate confidence levels
+const (
+	ConfidenceHigh   = "high"
+	ConfidenceMedium = "medium"
+	ConfidenceLow    = "low"
+)
+
+var (
+	// qualifiedIdent matches package qualified exported identifiers, e.g. model.User
+	qualifiedIdent = regexp.MustCompile(`\b([A-Za-z_][A-Za-z0-9_]*)\.([A-Z][A-Za-z0-9_]*)\b`)
+	// encodingTagKeys are struct tag keys of reflection based encoders and binders addressing fields by name
+	encodingTagKeys = []string{"json", "yaml", "xml", "mapstructure", "toml", "bson", "db", "gorm", "sql", "env", "form", "query", "protobuf", "avro", "csv"}
+	// wellKnownMethods implement standard library interfaces, unexporting them silently changes behavior
+	wellKnownMethods = map[string]bool{
+		"String": true, "Error": true, "Unwrap": true, "Is": true, "As": true, "Format": true, "GoString": true,
+		"MarshalJSON": true, "UnmarshalJSON": true, "MarshalText": true, "UnmarshalText": true,
+		"MarshalYAML": true, "UnmarshalYAML": true, "MarshalBinary": true, "UnmarshalBinary": true,
+		"Scan": true, "Value": true, "ServeHTTP": true, "Read": true, "Write": true, "Close": true,
+		"Len": true, "Less": true, "Swap": true,
+	}
+)
+
+// ReferenceModel reports packages referencing a symbol from outside of its declaring package
+type ReferenceModel interface {
+	// ExternalReferences returns import paths of packages referencing a type, function, method or field,
+	// external test packages are suffixed with _test
+	ExternalReferences(ref Ref) []string
+}
+
+// ReferenceIndex is a ReferenceModel built from project sources: package qualified identifiers (model.User,
+// user.Find) of declarations and function references, members are matched by name as receiver types are unknown
+type ReferenceIndex struct {
+	symbols map[string]map[string]bool // type or function ref -> referencing packages
+	members map[string]map[string]bool // method or field name -> referencing packages
+}
+
+// ExternalReferences returns packages other than the declaring one referencing a symbol
+func (r *ReferenceIndex) ExternalReferences(ref Ref) []string {
+	var referencing map[string]bool
+	switch ref.Kind() {
+	case RefKindMethod:
+		referencing = r.members[ref.Function]
+	case RefKindField:
+		referencing = r.members[ref.Member]
+	case RefKindFunction:
+		referencing = r.symbols[NewTypeRef(ref.Package, ref.Function).String()]
+	default:
+		referencing = r.symbols[ref.String()]
+	}
+	var result []string
+	for pkg := range referencing {
+		if pkg != ref.Package {
+			result = append(result, pkg)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// References indexes references between project packages
+func (p *Project) References() *ReferenceIndex {
+	index := &ReferenceIndex{symbols: map[string]map[string]bool{}, members: map[string]map[string]bool{}}
+	add := func(target map[string]map[string]bool, key, pkg string) {
+		if target[key] == nil {
+			target[key] = map[string]bool{}
+		}
+		target[key][pkg] = true
+	}
+	for _, pkg := range p.Packages {
+		for _, file := range pkg.FileSet {
+			referencing := pkg.Ref().Package
+			if strings.HasSuffix(file.Package, "_test") && !strings.HasSuffix(pkg.Name, "_test") {
+				referencing += "_test" // external test package
+			}
+			imports := map[string]*Package{}
+			for _, imp := range file.Imports {
+				if imported := p.lookupImport(imp.Path); imported != nil {
+					imports[imp.LocalName()] = imported
+				}
+			}
+			for _, text := range file.sourceTexts() {
+				for _, match := range qualifiedIdent.FindAllStringSubmatch(text, -1) {
+					if imported, ok := imports[match[1]]; ok {
+						add(index.symbols, NewTypeRef(imported.Ref().Package, match[2]).String(), referencing)
+						continue
+					}
+					add(index.members, match[2], referencing)
+				}
+			}
+		}
+	}
+	return index
+}
+
+// sourceTexts returns source fragments of file declarations, function references and type names
+func (f *File) sourceTexts() []string {
+	var result []string
+	addFunction := func(function *Function) {
+		result = append(result, function.Signature)
+		result = append(result, function.References...)
+		if function.Location != nil {
+			result = append(result, function.Location.Raw)
+		} else if function.Body != nil {
+			result = append(result, function.Body.Text)
+		}
+		for _, params := range [][]*Parameter{function.Parameters, function.Results} {
+			for _, param := range params {
+				if param.Type != nil && param.Type.Package != "" {
+					result = append(result, param.Type.Package+"."+param.Type.Name)
+				}
+			}
+		}
+	}
+	for _, constant := range f.Constants {
+		if constant.Location != nil {
+			result = append(result, constant.Location.Raw)
+		}
+	}
+	for _, variable := range f.Variables {
+		if variable.Location != nil {
+			result = append(result, variable.Location.Raw)
+		}
+	}
+	for _, aType := range f.Types {
+		result = append(result, aType.Content())
+		for _, field := range aType.Fields {
+			if field.Type != nil && field.Type.Package != "" {
+				result = append(result, field.Type.Package+"."+field.Type.Name)
+			}
+		}
+		for _, method := range aType.Methods {
+			addFunction(method)
+		}
+	}
+	for _, function := range f.Functions {
+		addFunction(function)
+	}
+	return result
+}
+
+// UnexportCandidate is an exported symbol not referenced outside of its declaring package
+type UnexportCandidate struct {
+	Ref        Ref
+	Kind       string // RefKindType, RefKindFunction, RefKindMethod or RefKindField
+	Name       string
+	NewName    string // Unexported name
+	Path       string // Declaring file path
+	Location   *Location
+	Confidence string   // ConfidenceHigh, ConfidenceMedium or ConfidenceLow
+	Reasons    []string // Assessment notes lowering confidence
+}
+
+// UnexportCandidates lists exported Go types, functions, methods and fields without references from other project
+// packages, by Ref. Main, init and test functions, symbols with a //linager:keep comment, well known interface methods
+// and fields with encoding tags (json, mapstructure, ...) are kept; references are taken from the model or, when nil,
+// from the project sources (see References).
+func (p *Project) UnexportCandidates(model ReferenceModel) []*UnexportCandidate {
+	if model == nil {
+		model = p.References()
+	}
+	interfaceMethods := map[string]string{}
+	for _, pkg := range p.Packages {
+		for _, file := range pkg.FileSet {
+			for _, aType := range file.Types {
+				if aType.Kind == reflect.Interface {
+					for _, method := range aType.Methods {
+						interfaceMethods[method.Name] = aType.Name
+					}
+				}
+			}
+		}
+	}
+	var result []*UnexportCandidate
+	for _, pkg := range p.Packages {
+		declared := pkg.declaredNames()
+		for _, file := range pkg.FileSet {
+			if !strings.HasSuffix(file.Path, ".go") && !strings.HasSuffix(file.Name, ".go") || strings.HasSuffix(file.Path, "_test.go") {
+				continue
+			}
+			candidate := func(ref Ref, kind, name string, location *Location, comment string) *UnexportCandidate {
+				if !IsExported(name) || strings.Contains(comment, KeepDirective) || len(model.ExternalReferences(ref)) > 0 {
+					return nil
+				}
+				ret := &UnexportCandidate{Ref: ref, Kind: kind, Name: name, NewName: UnexportedName(name), Path: file.Path, Location: location, Confidence: ConfidenceHigh}
+				if kind != RefKindMethod && kind != RefKindField && declared[ret.NewName] {
+					ret.downgrade(ConfidenceLow, "unexported name "+ret.NewName+" collides with a package symbol")
+				}
+				result = append(result, ret)
+				return ret
+			}
+			for _, aType := range file.Types {
+				typeRef := NewTypeRef(pkg.Ref().Package, aType.qualifiedName())
+				if typeCandidate := candidate(typeRef, RefKindType, aType.Name, aType.Location, aType.Comment.text()+aType.Annotation.text()); typeCandidate != nil && hasEncodingTags(aType) {
+					typeCandidate.downgrade(ConfidenceLow, "referenced via reflection-risk tag")
+				}
+				for _, field := range aType.Fields {
+					if field.IsEmbedded || hasEncodingTag(field.Tag) {
+						continue // tag references address the field by its name
+					}
+					if fieldCandidate := candidate(field.Ref(typeRef), RefKindField, field.Name, field.Location, field.Comment+field.Annotation); fieldCandidate != nil && aType.Kind == reflect.Interface {
+						fieldCandidate.downgrade(ConfidenceMedium, "interface member")
+					}
+				}
+				for _, method := range aType.Methods {
+					if wellKnownMethods[method.Name] {
+						continue
+					}
+					methodCandidate := candidate(method.Ref(typeRef), RefKindMethod, method.Name, method.Location, method.Comment.text()+method.Annotation.text())
+					if methodCandidate == nil {
+						continue
+					}
+					if owner, ok := interfaceMethods[method.Name]; ok {
+						methodCandidate.downgrade(ConfidenceLow, "may implement interface "+owner)
+					}
+				}
+			}
+			for _, function := range file.Functions {
+				if function.Receiver != "" || isEntryPoint(function.Name) {
+					continue
+				}
+				candidate(function.Ref(pkg.Ref()), RefKindFunction, function.Name, function.Location, function.Comment.text()+function.Annotation.text())
+			}
+		}
+	}
+	return result
+}
+
+// downgrade lowers candidate confidence with a reason
+func (c *UnexportCandidate) downgrade(confidence, reason string) {
+	if c.Confidence == ConfidenceHigh || confidence == ConfidenceLow {
+		c.Confidence = confidence
+	}
+	c.Reasons = append(c.Reasons, reason)
+}
+
+// declaredNames returns names of package level declarations
+func (p *Package) declaredNames() map[string]bool {
+	result := map[string]bool{}
+	for _, file := range p.FileSet {
+		for _, aType := range file.Types {
+			result[aType.Name] = true
+		}
+		for _, function := range file.Functions {
+			if function.Receiver == "" {
+				result[function.Name] = true
+			}
+		}
+		for _, constant := range file.Constants {
+			result[constant.Name] = true
+		}
+		for _, variable := range file.Variables {
+			result[variable.Name] = true
+		}
+	}
+	return result
+}
+
+// text returns comment text, empty for nil
+func (n *LocationNode) text() string {
+	if n == nil {
+		return ""
+	}
+	return n.Text
+}
+
+// hasEncodingTags reports whether any struct field has an encoding tag
+func hasEncodingTags(aType *Type) bool {
+	for _, field := range aType.Fields {
+		if hasEncodingTag(field.Tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasEncodingTag reports whether a tag has a key of reflection based encoders
+func hasEncodingTag(tag reflect.StructTag) bool {
+	for _, key := range encodingTagKeys {
+		if _, ok := tag.Lookup(key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isEntryPoint reports whether a function is invoked by the toolchain: main, init, tests, benchmarks, examples, fuzz targets
+func isEntryPoint(name string) bool {
+	if name == "main" || name == "init" {
+		return true
+	}
+	for _, prefix := range []string{"Test", "Benchmark", "Example", "Fuzz"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsExported reports whether a Go name is exported
+func IsExported(name string) bool {
+	for _, r := range name {
+		return unicode.IsUpper(r)
+	}
+	return false
+}
+
+// UnexportedName returns a Go name with its leading upper case run lowered, keeping the last letter of an initialism
+// followed by a lower case letter, e.g. User -> user, HTTPServer -> httpServer, ID -> id
+func UnexportedName(name string) string {
+	runes := []rune(name)
+	upper := 0
+	for upper < len(runes) && unicode.IsUpper(runes[upper]) {
+		upper++
+	}
+	if upper > 1 && upper < len(runes) && unicode.IsLower(runes[upper]) {
+		upper--
+	}
+	for i := 0; i < upper; i++ {
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
@@ -0,0 +1,52 @@
+package graph_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/linager/inspector/graph"
+	"reflect"
+	"testing"
+)
+
+func TestUnexportedName(t *testing.T) {
+	for name, expect := range map[string]string{"User": "user", "HTTPServer": "httpServer", "ID": "id", "X": "x"} {
+		assert.Equal(t, expect, graph.UnexportedName(name), name)
+	}
+}
+
+func TestProject_UnexportCandidates(t *testing.T) {
+	project := &graph.Project{Packages: []*graph.Package{{
+		Name:       "model",
+		ImportPath: "example.com/app/model",
+		FileSet: []*graph.File{{
+			Name: "model.go",
+			Path: "model/model.go",
+			Types: []*graph.Type{
+				{Name: "Config", Kind: reflect.Struct, Fields: []*graph.Field{
+					{Name: "Path", Tag: `mapstructure:"path"`},
+					{Name: "Mode"},
+				}},
+				{Name: "Runner", Kind: reflect.Interface, Methods: []*graph.Function{{Name: "Run"}}},
+				{Name: "Job", Kind: reflect.Struct, Comment: &graph.LocationNode{Text: "Job is kept //linager:keep"}, Methods: []*graph.Function{{Name: "Run", Receiver: "*Job"}}},
+			},
+			Functions: []*graph.Function{{Name: "main"}, {Name: "TestConfig"}},
+		}},
+	}}}
+	candidates := map[string]*graph.UnexportCandidate{}
+	for _, candidate := range project.UnexportCandidates(nil) {
+		candidates[candidate.Ref.String()] = candidate
+	}
+	assert.Len(t, candidates, 5)
+	assert.NotContains(t, candidates, "example.com/app/model#Config/Path", "tag referenced field")
+	assert.NotContains(t, candidates, "example.com/app/model#Job", "kept type")
+	if config := candidates["example.com/app/model#Config"]; assert.NotNil(t, config) {
+		assert.Equal(t, graph.ConfidenceLow, config.Confidence)
+		assert.Equal(t, []string{"referenced via reflection-risk tag"}, config.Reasons)
+	}
+	if mode := candidates["example.com/app/model#Config/Mode"]; assert.NotNil(t, mode) {
+		assert.Equal(t, graph.ConfidenceHigh, mode.Confidence)
+		assert.Equal(t, "mode", mode.NewName)
+	}
+	if run := candidates["example.com/app/model#Job.Run()"]; assert.NotNil(t, run) {
+		assert.Equal(t, graph.ConfidenceLow, run.Confidence)
+	}
+}
@@ -0,0 +1,95 @@
+package graph
+
+import "strings"
+
+// ValueRef is a function or method value referenced by a composite literal element of a variable value, e.g. a
+// handler of a registry map
+type ValueRef struct {
+	Key     string `json:",omitempty"` // String key of the map element holding the value
+	Expr    string // Element expression, e.g. createHandler, users.Create or (*Service).Create
+	Package string `json:",omitempty"` // Import path of an expression qualified by an imported package
+	Ref     *Ref   `json:",omitempty"` // Referenced project function or method, nil until linked
+}
+
+// name returns the referenced function name, Type.Method for method expressions
+func (r *ValueRef) name() string {
+	name := r.Expr
+	if r.Package != "" {
+		_, name, _ = strings.Cut(name, ".")
+	}
+	return strings.NewReplacer("(", "", ")", "", "*", "").Replace(name)
+}
+
+// LinkValueRefs resolves variable value references of all packages to project functions and methods; references
+// qualified by imports resolve through project packages, elements not resolving to a function (constants, variables,
+// types or declarations outside the project) are dropped
+func (p *Project) LinkValueRefs() {
+	for _, pkg := range p.Packages {
+		pkg.linkValueRefs(p.lookupImport)
+	}
+}
+
+// LinkValueRefs resolves variable value references to functions and methods of the package, references qualified by
+// imports are kept for Project.LinkValueRefs
+func (p *Package) LinkValueRefs() {
+	p.linkValueRefs(nil)
+}
+
+// linkValueRefs resolves references with a project package lookup, qualified references are kept unlinked without it
+func (p *Package) linkValueRefs(lookup func(importPath string) *Package) {
+	for _, file := range p.FileSet {
+		for _, variable := range file.Variables {
+			var linked []*ValueRef
+			for _, ref := range variable.References {
+				declaring := p
+				if ref.Package != "" {
+					if lookup == nil {
+						linked = append(linked, ref)
+						continue
+					}
+					declaring = lookup(ref.Package)
+				}
+				if declaring == nil {
+					continue
+				}
+				if target := declaring.lookupValueRef(ref.name()); target != nil {
+					ref.Ref = &target.Ref
+					linked = append(linked, ref)
+				}
+			}
+			variable.References = linked
+		}
+	}
+}
+
+// lookupValueRef returns the function or, for Type.Method names, the method of a type declared by the package
+func (p *Package) lookupValueRef(name string) *RefTarget {
+	owner := p.Ref()
+	typeName, methodName, isMethod := strings.Cut(name, ".")
+	for _, file := range p.FileSet {
+		if !isMethod {
+			for _, function := range file.Functions {
+				if function.Name == name && function.Receiver == "" {
+					return &RefTarget{Ref: function.Ref(owner), Package: p, File: file, Function: function}
+				}
+			}
+			continue
+		}
+		for _, aType := range file.Types {
+			if aType.Name != typeName {
+				continue
+			}
+			for _, method := range aType.Methods {
+				if method.Name == methodName {
+					return &RefTarget{Ref: method.Ref(NewTypeRef(owner.Package, aType.qualifiedName())), Package: p, File: file, Type: aType, Function: method}
+				}
+			}
+		}
+		for _, function := range file.Functions {
+			if function.Name == methodName && function.Ref(owner).Type == typeName {
+				return &RefTarget{Ref: function.Ref(owner), Package: p, File: file, Function: function}
+			}
+		}
+	}
+	return nil
+}
@@ -5,9 +5,11 @@ type Variable struct {
 	Comment    string
 	Type       *Type
 	Value      string
-	File       *File     // File where this variable is defined
-	IsExported bool      // Whether the variable is exported (public) or not
-	Annotation string    // Annotation associated with the variable
-	IsConst    bool      // Whether the variable is a constant
-	Location   *Location // Location of the variable in the source code
+	File       *File       `json:"-"` // File where this variable is defined
+	IsExported bool        // Whether the variable is exported (public) or not
+	Annotation string      // Annotation associated with the variable
+	IsConst    bool        // Whether the variable is a constant
+	Location   *Location   // Location of the variable in the source code
+	References []*ValueRef `json:",omitempty"` // Functions referenced by composite literal elements of the value, see Project.LinkValueRefs
+	UsageRefs  []string    `json:",omitempty"` // Lineage data point IDs resolved to the variable, see linage.AttachGraph
 }
@@ -0,0 +1,51 @@
+package graph
+
+import (
+	"reflect"
+	"strings"
+)
+
+// basicKinds maps predeclared Go type names to kinds
+var basicKinds = map[string]reflect.Kind{
+	"bool": reflect.Bool, "string": reflect.String,
+	"int": reflect.Int, "int8": reflect.Int8, "int16": reflect.Int16, "int32": reflect.Int32, "rune": reflect.Int32, "int64": reflect.Int64,
+	"uint": reflect.Uint, "uint8": reflect.Uint8, "byte": reflect.Uint8, "uint16": reflect.Uint16, "uint32": reflect.Uint32, "uint64": reflect.Uint64,
+	"uintptr": reflect.Uintptr, "float32": reflect.Float32, "float64": reflect.Float64, "complex64": reflect.Complex64, "complex128": reflect.Complex128,
+	"error": reflect.Interface, "any": reflect.Interface,
+}
+
+// ZeroValue returns the Go zero value of a type by kind, e.g. 0, "", false, nil or Name{} for structs and arrays;
+// types without a kind are resolved by name, named types of unknown kind (e.g. time.Duration) yield an empty string
+func ZeroValue(aType *Type) string {
+	if aType == nil {
+		return ""
+	}
+	name := aType.Name
+	kind := aType.Kind
+	switch {
+	case aType.IsPointer, strings.HasPrefix(name, "*"), strings.HasPrefix(name, "[]"), strings.HasPrefix(name, "map["),
+		strings.HasPrefix(name, "chan "), strings.HasPrefix(name, "<-chan"), strings.HasPrefix(name, "func("), strings.HasPrefix(name, "interface{"):
+		return "nil"
+	case kind == reflect.Invalid && strings.HasPrefix(name, "["):
+		kind = reflect.Array
+	case kind == reflect.Invalid:
+		kind = basicKinds[name]
+	}
+	switch kind {
+	case reflect.Bool:
+		return "false"
+	case reflect.String:
+		return `""`
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return "0"
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface, reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return "nil"
+	case reflect.Struct, reflect.Array:
+		if name != "" {
+			return name + "{}"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,305 @@
+package index
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/viant/linager/inspector"
+	"github.com/viant/linager/inspector/graph"
+	"github.com/viant/linager/inspector/repository"
+)
+
+// Location of the index file relative to the project root
+const (
+	Dir  = ".linager"
+	File = "index.db"
+)
+
+// Version of the index encoding, indexes of other versions are rebuilt
+const Version = 1
+
+const magic = "linager-index"
+
+// header precedes the encoded index
+type header struct {
+	Magic   string
+	Version int
+}
+
+// Index is a project graph persisted with content hashes of its source files and a symbol index, loaded by repeated
+// CLI invocations instead of inspecting the project again; files changed since the index was saved are refreshed by Open
+type Index struct {
+	Type          string            // Project type the index was built for
+	MultiLanguage bool              // Whether all languages of a composition scan were inspected
+	Project       *graph.Project    // Normalized project graph
+	Hashes        map[string]uint64 // Content hashes by source file path relative to the project root
+	Symbols       Symbols           // Project symbols by canonical reference
+	path          string            // Index file
+	refreshed     []string          // Package directories re-inspected by Open, relative to the project root
+	rebuilt       bool              // Whether Open inspected the whole project
+}
+
+// Path returns the index file of a project root
+func Path(root string) string {
+	return filepath.Join(root, Dir, File)
+}
+
+// Open returns the index of a project: a saved index is loaded and packages with source files changed, added or removed
+// since it was saved are re-inspected; a missing, corrupted or outdated index is rebuilt by inspecting the whole project.
+// The index is saved whenever it changed.
+func Open(target *repository.Project, factory *inspector.Factory) (*Index, error) {
+	root, err := filepath.Abs(target.RootPath)
+	if err != nil {
+		return nil, err
+	}
+	extensions := sourceExtensions(target)
+	hashes, err := hashFiles(root, extensions)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := Load(Path(root))
+	if err == nil && ret.Type == target.Type && ret.MultiLanguage == (target.Composition != nil) {
+		var changed bool
+		if changed, err = ret.refresh(root, hashes, factory); err == nil {
+			if changed {
+				err = ret.Save()
+			}
+			return ret, err
+		}
+	}
+	if ret, err = build(target, factory); err != nil {
+		return nil, err
+	}
+	ret.path = Path(root)
+	ret.Hashes = hashes
+	ret.rebuilt = true
+	return ret, ret.Save()
+}
+
+// build inspects the whole project
+func build(target *repository.Project, factory *inspector.Factory) (*Index, error) {
+	project, err := factory.InspectProject(target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect project %s: %w", target.RootPath, err)
+	}
+	project.Normalize()
+	return &Index{Type: target.Type, MultiLanguage: target.Composition != nil, Project: project, Symbols: NewSymbols(project)}, nil
+}
+
+// Load decodes an index file
+func Load(path string) (*Index, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	decoder := gob.NewDecoder(bytes.NewReader(data))
+	aHeader := &header{}
+	if err = decoder.Decode(aHeader); err != nil {
+		return nil, &graph.ErrParse{Path: path, Cause: err}
+	}
+	if aHeader.Magic != magic || aHeader.Version != Version {
+		return nil, &graph.ErrParse{Path: path, Cause: fmt.Errorf("unsupported index %s version %d", aHeader.Magic, aHeader.Version)}
+	}
+	ret := &Index{}
+	if err = decoder.Decode(ret); err != nil {
+		return nil, &graph.ErrParse{Path: path, Cause: err}
+	}
+	if ret.Project == nil {
+		return nil, &graph.ErrParse{Path: path, Cause: fmt.Errorf("missing project graph")}
+	}
+	if ret.Hashes == nil {
+		ret.Hashes = map[string]uint64{}
+	}
+	attachFiles(ret.Project)
+	ret.path = path
+	return ret, nil
+}
+
+// Save writes the index file, replacing it atomically
+func (i *Index) Save() error {
+	if err := os.MkdirAll(filepath.Dir(i.path), 0o755); err != nil {
+		return err
+	}
+	detached := detachFiles(i.Project)
+	defer attachFiles(detached)
+	buffer := &bytes.Buffer{}
+	encoder := gob.NewEncoder(buffer)
+	if err := encoder.Encode(&header{Magic: magic, Version: Version}); err != nil {
+		return err
+	}
+	if err := encoder.Encode(i); err != nil {
+		return fmt.Errorf("failed to encode index: %w", err)
+	}
+	temp := i.path + ".tmp"
+	if err := os.WriteFile(temp, buffer.Bytes(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(temp, i.path)
+}
+
+// refresh re-inspects package directories with source files changed since the index was saved, it reports whether the
+// index changed
+func (i *Index) refresh(root string, hashes map[string]uint64, factory *inspector.Factory) (bool, error) {
+	dirs := map[string]bool{}
+	for path, hash := range hashes {
+		if prev, ok := i.Hashes[path]; !ok || prev != hash {
+			dirs[filepath.Dir(path)] = true
+		}
+	}
+	for path := range i.Hashes {
+		if _, ok := hashes[path]; !ok {
+			dirs[filepath.Dir(path)] = true
+		}
+	}
+	if len(dirs) == 0 {
+		return false, nil
+	}
+	i.refreshed = make([]string, 0, len(dirs))
+	for dir := range dirs {
+		i.refreshed = append(i.refreshed, filepath.ToSlash(dir))
+	}
+	sort.Strings(i.refreshed)
+
+	project := i.Project
+	var packages []*graph.Package
+	for _, pkg := range project.Packages {
+		if !dirs[packageDir(pkg)] {
+			packages = append(packages, pkg)
+		}
+	}
+	inspected := &graph.Project{RootPath: root}
+	for _, dir := range i.refreshed {
+		if !hasSources(hashes, dir) {
+			continue // package removed
+		}
+		pkg, err := factory.InspectPackage(filepath.Join(root, filepath.FromSlash(dir)))
+		if err != nil {
+			return false, err
+		}
+		inspected.Packages = append(inspected.Packages, pkg)
+	}
+	inspected.Init()
+	project.Packages = append(packages, inspected.Packages...)
+	project.IndexPackages()
+	var skipped []*graph.SkippedFile
+	for _, file := range project.SkippedFiles {
+		if !dirs[filepath.Dir(filepath.FromSlash(file.Path))] {
+			skipped = append(skipped, file)
+		}
+	}
+	project.SkippedFiles = append(skipped, inspected.SkippedFiles...)
+	project.LinkValueRefs()
+	project.Normalize()
+	i.Symbols = NewSymbols(project)
+	i.Hashes = hashes
+	return true, nil
+}
+
+// packageDir returns the directory of package files relative to the project root, empty for packages without files
+func packageDir(pkg *graph.Package) string {
+	for _, file := range pkg.FileSet {
+		if file.Path != "" {
+			return filepath.Dir(filepath.FromSlash(file.Path))
+		}
+	}
+	return ""
+}
+
+// hasSources reports whether a directory has hashed source files
+func hasSources(hashes map[string]uint64, dir string) bool {
+	for path := range hashes {
+		if filepath.ToSlash(filepath.Dir(path)) == dir {
+			return true
+		}
+	}
+	return false
+}
+
+// sourceExtensions returns extensions of source files inspected for a project
+func sourceExtensions(target *repository.Project) map[string]bool {
+	languages := []string{target.Type}
+	if target.Composition != nil {
+		languages = languages[:0]
+		for _, language := range target.Composition.Languages {
+			languages = append(languages, language.Language)
+		}
+	}
+	ret := map[string]bool{}
+	for _, language := range languages {
+		switch language {
+		case "go":
+			ret[".go"] = true
+		case "java":
+			ret[".java"] = true
+		case "javascript", "typescript":
+			ret[".js"], ret[".jsx"] = true, true
+		case "scala":
+			ret[".scala"] = true
+		}
+	}
+	return ret
+}
+
+// hashFiles returns content hashes of source files under root by path relative to root
+func hashFiles(root string, extensions map[string]bool) (map[string]uint64, error) {
+	ret := map[string]uint64{}
+	err := repository.Walk(root, false, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if name := info.Name(); path != root && (name == ".git" || name == Dir) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !extensions[strings.ToLower(filepath.Ext(path))] {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		ret[relPath], err = graph.Hash(data)
+		return err
+	})
+	return ret, err
+}
+
+// detachFiles clears file back references of constants and variables, which gob can not encode
+func detachFiles(project *graph.Project) *graph.Project {
+	for _, pkg := range project.Packages {
+		for _, file := range pkg.FileSet {
+			for _, constant := range file.Constants {
+				constant.File = nil
+			}
+			for _, variable := range file.Variables {
+				variable.File = nil
+			}
+		}
+	}
+	return project
+}
+
+// attachFiles restores file back references of constants and variables
+func attachFiles(project *graph.Project) {
+	for _, pkg := range project.Packages {
+		for _, file := range pkg.FileSet {
+			for _, constant := range file.Constants {
+				constant.File = file
+			}
+			for _, variable := range file.Variables {
+				variable.File = file
+			}
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package index
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/linager/inspector"
+	"github.com/viant/linager/inspector/graph"
+	"github.com/viant/linager/inspector/repository"
+)
+
+func TestOpen(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "go.mod", "module example.com/demo\n\ngo 1.21\n")
+	writeFile(t, root, "service/service.go", "package service\n\nconst Name = \"service\"\n\n// Service runs jobs\ntype Service struct {\n\tID int\n}\n\n// Run runs a job\nfunc (s *Service) Run(job string) error {\n\treturn nil\n}\n")
+	writeFile(t, root, "store/store.go", "package store\n\n// Store keeps items\ntype Store struct {\n\tItems []string\n}\n")
+	target := &repository.Project{RootPath: root, Type: "go"}
+	factory := inspector.NewFactory(graph.DefaultConfig())
+
+	// index creation
+	index, err := Open(target, factory)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, index.rebuilt)
+	assert.FileExists(t, Path(root))
+	assert.Len(t, index.Hashes, 2)
+	symbols := index.Symbols.Lookup("Service.Run")
+	if !assert.Len(t, symbols, 1) {
+		return
+	}
+	assert.Equal(t, graph.RefKindMethod, symbols[0].Kind)
+	assert.Equal(t, filepath.Join("service", "service.go"), symbols[0].File)
+	assert.NotNil(t, index.Project.ByRef(symbols[0].Ref))
+	constants := index.Symbols.Lookup("Name")
+	if !assert.Len(t, constants, 1) {
+		return
+	}
+	assert.NotNil(t, index.Project.ByRef(constants[0].Ref).Constant.File)
+
+	// unchanged sources are served from the index
+	index, err = Open(target, factory)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.False(t, index.rebuilt)
+	assert.Empty(t, index.refreshed)
+	assert.Len(t, index.Symbols.Lookup("Store"), 1)
+
+	// partial refresh after editing one file
+	writeFile(t, root, "service/service.go", "package service\n\n// Service runs jobs\ntype Service struct {\n\tID int\n}\n\n// Stop stops jobs\nfunc (s *Service) Stop() {}\n")
+	index, err = Open(target, factory)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.False(t, index.rebuilt)
+	assert.Equal(t, []string{"service"}, index.refreshed)
+	assert.Empty(t, index.Symbols.Lookup("Run"))
+	assert.Len(t, index.Symbols.Lookup("Service.Stop"), 1)
+	assert.Len(t, index.Symbols.Lookup("Store/Items"), 1)
+	loaded, err := Load(Path(root))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, loaded.Symbols.Lookup("Service.Stop"), 1)
+
+	// removed package
+	if !assert.NoError(t, os.RemoveAll(filepath.Join(root, "store"))) {
+		return
+	}
+	index, err = Open(target, factory)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []string{"store"}, index.refreshed)
+	assert.Empty(t, index.Symbols.Lookup("Store"))
+
+	// corruption recovery
+	if !assert.NoError(t, os.WriteFile(Path(root), []byte("corrupted"), 0o644)) {
+		return
+	}
+	_, err = Load(Path(root))
+	assert.ErrorIs(t, err, &graph.ErrParse{})
+	index, err = Open(target, factory)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, index.rebuilt)
+	assert.Len(t, index.Symbols.Lookup("Service.Stop"), 1)
+	_, err = Load(Path(root))
+	assert.NoError(t, err)
+}
+
+func writeFile(t *testing.T, root, name, content string) {
+	path := filepath.Join(root, name)
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
@@ -0,0 +1,89 @@
+package index
+
+import (
+	"sort"
+
+	"github.com/viant/linager/inspector/graph"
+)
+
+// Symbol is an indexed project element
+type Symbol struct {
+	Ref     string // Canonical reference, see graph.Ref
+	Kind    string // Ref kind, see graph.RefKindType
+	Name    string // Reference symbol without parameters, e.g. Type.Method or Type/field
+	Package string // Package import path or name
+	File    string // Declaring file path relative to the project root
+}
+
+// Symbols indexes project symbols by canonical reference
+type Symbols map[string]*Symbol
+
+// NewSymbols indexes types, fields, functions, methods, package level variables and constants of a project
+func NewSymbols(project *graph.Project) Symbols {
+	ret := Symbols{}
+	for _, pkg := range project.Packages {
+		pkgRef := pkg.Ref()
+		for _, file := range pkg.FileSet {
+			add := func(ref graph.Ref) {
+				ret.add(ref, file.Path)
+			}
+			for _, aType := range file.Types {
+				typeRef := aType.Ref()
+				typeRef.Package = pkgRef.Package
+				add(typeRef)
+				for _, field := range aType.Fields {
+					add(field.Ref(typeRef))
+				}
+				for _, method := range aType.Methods {
+					add(method.Ref(typeRef))
+				}
+			}
+			for _, function := range file.Functions {
+				add(function.Ref(pkgRef))
+			}
+			for _, variable := range file.Variables {
+				add(graph.NewTypeRef(pkgRef.Package, variable.Name))
+			}
+			for _, constant := range file.Constants {
+				add(graph.NewTypeRef(pkgRef.Package, constant.Name))
+			}
+		}
+	}
+	return ret
+}
+
+// add indexes a reference, the first declaration wins
+func (s Symbols) add(ref graph.Ref, path string) {
+	text := ref.String()
+	if _, ok := s[text]; ok || ref.Package == "" {
+		return
+	}
+	name := ref.Type
+	switch {
+	case ref.Function != "" && name != "":
+		name += "." + ref.Function
+	case ref.Function != "":
+		name = ref.Function
+	case ref.Member != "":
+		name += "/" + ref.Member
+	}
+	s[text] = &Symbol{Ref: text, Kind: ref.Kind(), Name: name, Package: ref.Package, File: path}
+}
+
+// Lookup returns symbols matching a canonical reference, a reference symbol (e.g. Type.Method) or a simple name, sorted
+// by reference
+func (s Symbols) Lookup(query string) []*Symbol {
+	if symbol, ok := s[query]; ok {
+		return []*Symbol{symbol}
+	}
+	var ret []*Symbol
+	for _, symbol := range s {
+		if symbol.Name == query || graph.SimpleName(symbol.Name) == query {
+			ret = append(ret, symbol)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Ref < ret[j].Ref
+	})
+	return ret
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/viant/linager/inspector/graph"
 	"github.com/viant/linager/inspector/java"
 	javascript "github.com/viant/linager/inspector/jsx"
+	"github.com/viant/linager/inspector/scala"
 )
 
 // Inspector provides an interface for inspecting source code
@@ -27,22 +28,55 @@ type Inspector interface {
 	InspectProject(location string) (*graph.Project, error)
 }
 
-// Factory creates appropriate inspectors based on language
+// Factory creates appropriate inspectors and emitters based on language
 type Factory struct {
-	config *graph.Config
+	config      *graph.Config
+	goEmitter   *golang.EmitterOptions
+	javaEmitter *java.EmitterOptions
+}
+
+// FactoryOption represents a factory option
+type FactoryOption func(*Factory)
+
+// WithGoEmitter sets options of Go emitters returned by GetEmitter
+func WithGoEmitter(options *golang.EmitterOptions) FactoryOption {
+	return func(f *Factory) {
+		f.goEmitter = options
+	}
+}
+
+// WithJavaEmitter sets options of Java emitters returned by GetEmitter
+func WithJavaEmitter(options *java.EmitterOptions) FactoryOption {
+	return func(f *Factory) {
+		f.javaEmitter = options
+	}
+}
+
+// WithSourceProvider sets the provider of inspected sources, e.g. a repository.GitObjectProvider of a revision; only
+// Go sources are inspected with it, other languages are read from the local file system
+func WithSourceProvider(sources graph.SourceProvider) FactoryOption {
+	return func(f *Factory) {
+		config := *f.config
+		config.Sources = sources
+		f.config = &config
+	}
 }
 
 // NewFactory creates a new inspector factory with the given config
-func NewFactory(config *graph.Config) *Factory {
+func NewFactory(config *graph.Config, options ...FactoryOption) *Factory {
 	if config == nil {
 		config = &graph.Config{
 			IncludeUnexported: true,
 			SkipTests:         true,
 		}
 	}
-	return &Factory{
+	ret := &Factory{
 		config: config,
 	}
+	for _, option := range options {
+		option(ret)
+	}
+	return ret
 }
 
 // GetInspector returns an appropriate inspector based on file extension
@@ -56,8 +90,26 @@ func (f *Factory) GetInspector(filename string) (Inspector, error) {
 		return java.NewInspector(f.config), nil
 	case ".js", ".jsx":
 		return javascript.NewInspector(f.config), nil
+	case ".scala":
+		return scala.NewInspector(f.config), nil
+	default:
+		return nil, fmt.Errorf("unsupported file type %s: %w", filename, &graph.ErrUnsupported{Language: ext})
+	}
+}
+
+// GetEmitter returns an appropriate emitter based on file extension
+func (f *Factory) GetEmitter(filename string) (graph.Emitter, error) {
+	ext := strings.ToLower(filepath.Ext(filename))
+
+	switch ext {
+	case ".go":
+		return golang.NewEmitter(f.goEmitter), nil
+	case ".java":
+		return java.NewEmitter(f.javaEmitter), nil
+	case ".js", ".jsx":
+		return &javascript.Emitter{}, nil
 	default:
-		return nil, fmt.Errorf("unsupported file type: %s", ext)
+		return nil, fmt.Errorf("unsupported file type %s: %w", filename, &graph.ErrUnsupported{Language: ext})
 	}
 }
 
@@ -74,14 +126,14 @@ func (f *Factory) InspectFile(filename string) (*graph.File, error) {
 // InspectPackage is a convenience method that gets the appropriate inspector for a package
 func (f *Factory) InspectPackage(packagePath string) (*graph.Package, error) {
 	// Try to determine language from files in the directory
-	entries, err := filepath.Glob(filepath.Join(packagePath, "*"))
+	entries, err := repository.Sources(f.config).ReadDir(packagePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read package directory: %w", err)
+		return nil, fmt.Errorf("failed to read package directory: %w", graph.NotFoundError("directory", packagePath, err))
 	}
 
 	// Look for source files to determine language
 	for _, entry := range entries {
-		ext := strings.ToLower(filepath.Ext(entry))
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
 		switch ext {
 		case ".go":
 			inspector := golang.NewInspector(f.config)
@@ -92,21 +144,75 @@ func (f *Factory) InspectPackage(packagePath string) (*graph.Package, error) {
 		case ".js", ".jsx":
 			inspector := javascript.NewInspector(f.config)
 			return inspector.InspectPackage(packagePath)
+		case ".scala":
+			inspector := scala.NewInspector(f.config)
+			return inspector.InspectPackage(packagePath)
 		}
 	}
 
-	return nil, fmt.Errorf("unable to determine language for package: %s", packagePath)
+	return nil, fmt.Errorf("unable to determine language for package %s: %w", packagePath, &graph.ErrUnsupported{Language: "unknown"})
 }
 
-// InspectProject is a convenience method that gets the appropriate inspector for a project
+// InspectProject is a convenience method that gets the appropriate inspector for a project; projects with a
+// composition report (see repository.Scan) are inspected in multi-language mode: every language of handwritten
+// source files with an inspector is dispatched, largest first, and packages of all languages are merged
 func (f *Factory) InspectProject(project *repository.Project) (*graph.Project, error) {
-	switch project.Type {
+	if project.Composition != nil {
+		return f.inspectLanguages(project)
+	}
+	if inspector := f.projectInspector(project.Type); inspector != nil {
+		return inspector.InspectProject(project.RootPath)
+	}
+	return nil, &graph.ErrUnsupported{Language: project.Type}
+}
+
+// inspectLanguages inspects a project with inspectors of languages found by its composition report
+func (f *Factory) inspectLanguages(project *repository.Project) (*graph.Project, error) {
+	var result *graph.Project
+	dispatched := map[string]bool{}
+	for _, language := range project.Composition.Languages {
+		inspector := f.projectInspector(language.Language)
+		if inspector == nil || language.Files == language.Generated || dispatched[inspectorLanguage(language.Language)] {
+			continue
+		}
+		dispatched[inspectorLanguage(language.Language)] = true
+		inspected, err := inspector.InspectProject(project.RootPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect %s sources: %w", language.Language, err)
+		}
+		if result == nil {
+			result = inspected
+			continue
+		}
+		result.Packages = append(result.Packages, inspected.Packages...)
+		result.SkippedFiles = append(result.SkippedFiles, inspected.SkippedFiles...)
+	}
+	if result == nil {
+		return nil, &graph.ErrUnsupported{Language: project.Type}
+	}
+	result.IndexPackages()
+	return result, nil
+}
+
+// projectInspector returns the inspector of a project type or composition language, nil when unsupported
+func (f *Factory) projectInspector(language string) Inspector {
+	switch inspectorLanguage(language) {
 	case "go":
-		return golang.NewInspector(f.config).InspectProject(project.RootPath)
+		return golang.NewInspector(f.config)
 	case "java":
-		return java.NewInspector(f.config).InspectProject(project.RootPath)
+		return java.NewInspector(f.config)
 	case "javascript":
-		return javascript.NewInspector(f.config).InspectProject(project.RootPath)
+		return javascript.NewInspector(f.config)
+	case "scala":
+		return scala.NewInspector(f.config)
+	}
+	return nil
+}
+
+// inspectorLanguage maps a language to the language of its inspector, TypeScript is inspected as JavaScript
+func inspectorLanguage(language string) string {
+	if language == "typescript" {
+		return "javascript"
 	}
-	return nil, nil
+	return language
 }
@@ -1,12 +1,18 @@
 package inspector_test
 
 import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/viant/linager/inspector"
 	"github.com/viant/linager/inspector/graph"
+	"github.com/viant/linager/inspector/repository"
 )
 
 func TestFactory_GetInspector(t *testing.T) {
@@ -40,6 +46,12 @@ func TestFactory_GetInspector(t *testing.T) {
 			wantErr:   false,
 			inspector: "javascript",
 		},
+		{
+			name:      "Scala file",
+			filename:  "Order.scala",
+			wantErr:   false,
+			inspector: "scala",
+		},
 		{
 			name:      "Unsupported file",
 			filename:  "test.cpp",
@@ -90,3 +102,116 @@ func TestFactory_InspectPackage(t *testing.T) {
 	// Skip the test that requires actual package on disk
 	t.Skip("Skipping test that requires actual package directory on disk")
 }
+
+func TestFactory_ErrorClassification(t *testing.T) {
+	factory := inspector.NewFactory(nil)
+
+	_, err := factory.GetInspector("main.rs")
+	var unsupported *graph.ErrUnsupported
+	if !errors.As(err, &unsupported) || unsupported.Language != ".rs" {
+		t.Errorf("expected ErrUnsupported for .rs, got %v", err)
+	}
+
+	_, err = factory.InspectFile("testdata/missing.go")
+	if !errors.Is(err, &graph.ErrNotFound{Kind: "file"}) || !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected ErrNotFound for missing file, got %v", err)
+	}
+
+	goInspector, _ := factory.GetInspector("broken.go")
+	_, err = goInspector.InspectSource([]byte("package test\n\nfunc broken( {\n"))
+	var parseErr *graph.ErrParse
+	if !errors.As(err, &parseErr) || parseErr.Line != 3 {
+		t.Errorf("expected ErrParse at line 3, got %v", err)
+	}
+}
+
+func TestFactory_InspectProject_Composition(t *testing.T) {
+	root := t.TempDir()
+	for name, content := range map[string]string{
+		"go.mod":          "module example.com/app\n\ngo 1.23\n",
+		"api/api.go":      "package api\n\ntype Request struct {\n\tID int\n}\n",
+		"web/app.js":      "export function render() {\n  return 1;\n}\n",
+		"gen/Model.java":  "// Code generated by protoc. DO NOT EDIT.\npackage gen;\n\npublic class Model {}\n",
+		"scripts/tool.py": "print('x')\n",
+	} {
+		if err := os.MkdirAll(filepath.Join(root, filepath.Dir(name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	report, err := repository.Scan(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	project, err := inspector.NewFactory(nil).InspectProject(&repository.Project{RootPath: root, Type: "go", Composition: report})
+	if err != nil {
+		t.Fatalf("failed to inspect project: %v", err)
+	}
+	var names []string
+	for _, pkg := range project.Packages {
+		names = append(names, pkg.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"api", "web"}) {
+		t.Errorf("expected go and javascript packages without generated java, got %v", names)
+	}
+}
+
+func TestFactory_InspectProject_GitRevision(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	root := t.TempDir()
+	write := func(files map[string]string) {
+		for name, content := range files {
+			if err := os.MkdirAll(filepath.Join(root, filepath.Dir(name)), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	commit := func() {
+		for _, args := range [][]string{{"add", "-A"}, {"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "update"}} {
+			if _, err := repository.ExecGit(context.Background(), root, nil, args...); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if _, err := repository.ExecGit(context.Background(), root, nil, "init", "-q"); err != nil {
+		t.Fatal(err)
+	}
+	write(map[string]string{"go.mod": "module example.com/app\n\ngo 1.23\n", "api/api.go": "package api\n\ntype Request struct {\n\tID int\n}\n"})
+	commit()
+	write(map[string]string{"go.mod": "module example.com/renamed\n\ngo 1.23\n", "api/api.go": "package api\n\ntype Request struct {\n\tID   int\n\tName string\n}\n", "store/store.go": "package store\n"})
+	commit()
+	write(map[string]string{"api/api.go": "package api\n\ntype Response struct{}\n"}) // uncommitted
+
+	provider, err := repository.NewGitObjectProvider(context.Background(), root, "HEAD~1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	target, err := repository.NewWithSources(provider).DetectProject(context.Background(), root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if target.Name != "example.com/app" || target.Type != "go" {
+		t.Errorf("expected go project detected from go.mod of the revision, got %v %v", target.Name, target.Type)
+	}
+	project, err := inspector.NewFactory(nil, inspector.WithSourceProvider(provider)).InspectProject(target)
+	if err != nil {
+		t.Fatalf("failed to inspect revision: %v", err)
+	}
+	if len(project.Packages) != 1 || len(project.Packages[0].FileSet) != 1 {
+		t.Fatalf("expected the api package of the revision, got %v", project.Packages)
+	}
+	file := project.Packages[0].FileSet[0]
+	if file.Path != filepath.Join("api", "api.go") {
+		t.Errorf("expected path relative to the project root, got %v", file.Path)
+	}
+	if len(file.Types) != 1 || file.Types[0].Name != "Request" || len(file.Types[0].Fields) != 1 {
+		t.Errorf("expected Request with the ID field of the revision, got %v", file.Types)
+	}
+}
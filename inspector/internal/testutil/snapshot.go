@@ -0,0 +1,184 @@
+// Package testutil provides test helpers shared by language inspector tests
+package testutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/viant/linager/inspector/graph"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// UpdateEnv names the environment variable regenerating snapshots when set to 1
+const UpdateEnv = "UPDATE_SNAPSHOTS"
+
+// redactedKeys lists location keys removed from snapshots when locations are redacted
+var redactedKeys = map[string]bool{"Location": true, "Start": true, "End": true}
+
+// InspectFn inspects a single source file
+type InspectFn func(path string) (*graph.File, error)
+
+// Snapshot compares inspected fixtures with stored snapshots
+type Snapshot struct {
+	Extensions      []string // Fixture file extensions, e.g. .go
+	Dir             string   // Snapshot directory mirroring fixture paths, snapshots by default
+	RedactLocations bool     // Whether source locations are omitted from snapshots
+}
+
+// Assert inspects every fixture under the roots and compares its canonical YAML with the stored .snap file,
+// snapshots are (re)generated when UPDATE_SNAPSHOTS=1
+func (s *Snapshot) Assert(t *testing.T, inspect InspectFn, roots ...string) {
+	t.Helper()
+	update := os.Getenv(UpdateEnv) == "1"
+	fixtures, err := s.fixtures(roots)
+	if err != nil {
+		t.Fatalf("failed to list fixtures: %v", err)
+	}
+	if len(fixtures) == 0 {
+		t.Fatalf("no fixtures found in %v", roots)
+	}
+	for _, fixture := range fixtures {
+		t.Run(filepath.ToSlash(fixture), func(t *testing.T) {
+			file, err := inspect(fixture)
+			if err != nil {
+				t.Fatalf("failed to inspect %s: %v", fixture, err)
+			}
+			actual, err := Canonical(file, s.RedactLocations)
+			if err != nil {
+				t.Fatalf("failed to serialize %s: %v", fixture, err)
+			}
+			snapPath := filepath.Join(s.dir(), fixture+".snap")
+			if update {
+				if err = os.MkdirAll(filepath.Dir(snapPath), 0755); err == nil {
+					err = os.WriteFile(snapPath, actual, 0644)
+				}
+				if err != nil {
+					t.Fatalf("failed to update snapshot %s: %v", snapPath, err)
+				}
+				return
+			}
+			expected, err := os.ReadFile(snapPath)
+			if err != nil {
+				t.Fatalf("missing snapshot %s, run with %s=1 to create it: %v", snapPath, UpdateEnv, err)
+			}
+			if diff := UnifiedDiff(snapPath, string(expected), string(actual)); diff != "" {
+				t.Errorf("snapshot mismatch, run with %s=1 to update:\n%s", UpdateEnv, diff)
+			}
+		})
+	}
+}
+
+func (s *Snapshot) dir() string {
+	if s.Dir == "" {
+		return "snapshots"
+	}
+	return s.Dir
+}
+
+// fixtures returns sorted fixture paths with matching extensions
+func (s *Snapshot) fixtures(roots []string) ([]string, error) {
+	var result []string
+	for _, root := range roots {
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return err
+			}
+			for _, ext := range s.Extensions {
+				if strings.HasSuffix(path, ext) {
+					result = append(result, path)
+					break
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// Canonical serializes a file to YAML with sorted keys, omitting empty values (and locations when redacted)
+func Canonical(file *graph.File, redactLocations bool) ([]byte, error) {
+	data, err := json.Marshal(file)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if err = json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(normalize(value, "", redactLocations))
+}
+
+// normalize drops empty values and renders reflect.Kind values by name
+func normalize(value interface{}, key string, redactLocations bool) interface{} {
+	switch actual := value.(type) {
+	case map[string]interface{}:
+		result := map[string]interface{}{}
+		for k, v := range actual {
+			if redactLocations && redactedKeys[k] {
+				continue
+			}
+			if v = normalize(v, k, redactLocations); v != nil {
+				result[k] = v
+			}
+		}
+		if len(result) == 0 {
+			return nil
+		}
+		return result
+	case []interface{}:
+		var result []interface{}
+		for _, v := range actual {
+			if v = normalize(v, "", redactLocations); v != nil {
+				result = append(result, v)
+			}
+		}
+		if len(result) == 0 {
+			return nil
+		}
+		return result
+	case string:
+		if actual == "" {
+			return nil
+		}
+	case bool:
+		if !actual {
+			return nil
+		}
+	case float64:
+		if actual == 0 {
+			return nil
+		}
+		if key == "Kind" {
+			return reflect.Kind(actual).String()
+		}
+	}
+	return value
+}
+
+// UnifiedDiff returns a unified diff between expected and actual content, empty when equal
+func UnifiedDiff(name, expected, actual string) string {
+	if expected == actual {
+		return ""
+	}
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(expected),
+		B:        difflib.SplitLines(actual),
+		FromFile: name,
+		ToFile:   "actual",
+		Context:  3,
+	})
+	if err != nil {
+		return fmt.Sprintf("failed to compute diff: %v", err)
+	}
+	return diff
+}
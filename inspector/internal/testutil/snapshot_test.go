@@ -0,0 +1,30 @@
+package testutil
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/linager/inspector/graph"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCanonical(t *testing.T) {
+	file := &graph.File{
+		Name:  "user.go",
+		Types: []*graph.Type{{Name: "User", Kind: reflect.Struct, Location: &graph.Location{Start: 10, End: 40}}},
+	}
+	data, err := Canonical(file, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "Name: user.go\nTypes:\n    - Kind: struct\n      Name: User\n", string(data))
+
+	data, err = Canonical(file, false)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "Start: 10")
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	assert.Equal(t, "", UnifiedDiff("a.snap", "Name: a\n", "Name: a\n"))
+	diff := UnifiedDiff("a.snap", "Name: a\nLines: 1\n", "Name: b\nLines: 1\n")
+	assert.True(t, strings.HasPrefix(diff, "--- a.snap\n+++ actual\n"), diff)
+	assert.Contains(t, diff, "-Name: a\n+Name: b\n")
+}
@@ -0,0 +1,138 @@
+package java
+
+import (
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/viant/linager/inspector/graph"
+)
+
+// parseCoverage accounts type declarations and their members against the graph file built by processJavaFile;
+// non-public types are not counted unless IncludeUnexported is set, local and anonymous classes are not counted
+func (i *Inspector) parseCoverage(rootNode *sitter.Node, src []byte, filename string, aFile *graph.File) *graph.ParseCoverage {
+	census := &javaCensus{
+		coverage:  graph.NewParseCoverage(),
+		src:       src,
+		path:      filename,
+		types:     map[string]*graph.Type{},
+		constants: map[string]bool{},
+		config:    i.config,
+	}
+	for _, aType := range aFile.Types {
+		census.types[aType.Name] = aType
+	}
+	for _, constant := range aFile.Constants {
+		census.constants[constant.Name] = true
+	}
+	for j := uint32(0); j < rootNode.NamedChildCount(); j++ {
+		if child := rootNode.NamedChild(int(j)); isTypeDeclaration(child.Type()) {
+			census.typeDeclaration(child, "")
+		}
+	}
+	return census.coverage
+}
+
+// javaCensus walks type declarations of a parsed file
+type javaCensus struct {
+	coverage  *graph.ParseCoverage
+	src       []byte
+	path      string
+	types     map[string]*graph.Type
+	constants map[string]bool
+	config    *graph.Config
+}
+
+func (c *javaCensus) typeDeclaration(node *sitter.Node, parent string) {
+	if !c.config.IncludeUnexported && !isNodePublic(node, c.src) {
+		return
+	}
+	nameNode := node.ChildByFieldName("name")
+	if nameNode == nil {
+		c.unhandled(node, parent)
+		return
+	}
+	name := nameNode.Content(c.src)
+	if parent != "" {
+		name = parent + "." + name
+	}
+	aType := c.types[name]
+	c.coverage.Count(graph.CoverageTypes, aType != nil)
+	if bodyNode := node.ChildByFieldName("body"); bodyNode != nil {
+		c.members(bodyNode, name, aType)
+	}
+}
+
+// members accounts body members of a type, member node types the inspector does not model are reported as unhandled
+func (c *javaCensus) members(bodyNode *sitter.Node, owner string, aType *graph.Type) {
+	for j := uint32(0); j < bodyNode.NamedChildCount(); j++ {
+		child := bodyNode.NamedChild(int(j))
+		switch kind := child.Type(); {
+		case isTypeDeclaration(kind):
+			c.typeDeclaration(child, owner)
+		case kind == "field_declaration":
+			for _, name := range c.declarators(child) {
+				c.coverage.Count(graph.CoverageFields, aType != nil && hasField(aType, name))
+			}
+		case kind == "constant_declaration":
+			for _, name := range c.declarators(child) {
+				c.coverage.Count(graph.CoverageConstants, c.constants[name])
+			}
+		case kind == "enum_constant":
+			if nameNode := child.ChildByFieldName("name"); nameNode != nil {
+				c.coverage.Count(graph.CoverageConstants, c.constants[nameNode.Content(c.src)])
+			}
+		case kind == "method_declaration", kind == "constructor_declaration", kind == "annotation_type_element_declaration":
+			nameNode := child.ChildByFieldName("name")
+			c.coverage.Count(graph.CoverageFunctions, aType != nil && nameNode != nil && hasMethod(aType, nameNode.Content(c.src)))
+		case kind == "enum_body_declarations":
+			c.members(child, owner, aType)
+		case kind == "line_comment", kind == "block_comment":
+		default:
+			c.unhandled(child, owner)
+		}
+	}
+}
+
+// declarators returns names of variable declarators of a field or constant declaration
+func (c *javaCensus) declarators(node *sitter.Node) []string {
+	var names []string
+	for j := uint32(0); j < node.NamedChildCount(); j++ {
+		child := node.NamedChild(int(j))
+		if child.Type() != "variable_declarator" {
+			continue
+		}
+		if nameNode := child.ChildByFieldName("name"); nameNode != nil {
+			names = append(names, nameNode.Content(c.src))
+		}
+	}
+	return names
+}
+
+func (c *javaCensus) unhandled(node *sitter.Node, owner string) {
+	point := node.StartPoint()
+	c.coverage.Unhandle(&graph.UnhandledNode{Node: node.Type(), Owner: owner, Path: c.path, Line: int(point.Row) + 1, Column: int(point.Column) + 1})
+}
+
+func isTypeDeclaration(kind string) bool {
+	switch kind {
+	case "class_declaration", "record_declaration", "interface_declaration", "enum_declaration", "annotation_type_declaration":
+		return true
+	}
+	return false
+}
+
+func hasField(aType *graph.Type, name string) bool {
+	for _, field := range aType.Fields {
+		if field.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasMethod(aType *graph.Type, name string) bool {
+	for _, method := range aType.Methods {
+		if method.Name == name {
+			return true
+		}
+	}
+	return false
+}
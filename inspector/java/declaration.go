@@ -3,6 +3,7 @@ package java
 import (
 	sitter "github.com/smacker/go-tree-sitter"
 	"github.com/viant/linager/inspector/graph"
+	"github.com/viant/linager/inspector/treesitter"
 	"reflect"
 	"strings"
 )
@@ -13,12 +14,13 @@ func parsePackageDeclaration(node *sitter.Node, source []byte) string {
 		return ""
 	}
 
-	nameNode := node.NamedChild(0)
-	if nameNode == nil {
-		return ""
+	// package annotations of package-info.java precede the name
+	for j := 0; j < int(node.NamedChildCount()); j++ {
+		if nameNode := node.NamedChild(j); nameNode.Type() == "scoped_identifier" || nameNode.Type() == "identifier" {
+			return nameNode.Content(source)
+		}
 	}
-
-	return nameNode.Content(source)
+	return ""
 }
 
 // parseImportDeclarations extracts import declarations from a Java source file
@@ -134,34 +136,158 @@ func parseClassDeclaration(node *sitter.Node, source []byte, importMap map[strin
 	}
 
 	// Extract class body
+	if bodyNode := node.ChildByFieldName("body"); bodyNode != nil {
+		parseClassBody(classType, bodyNode, source, importMap)
+	}
+
+	return classType
+}
+
+// parseClassBody extracts fields, methods and constructors of a class or record body, nested type declarations are
+// collected separately (see Inspector.addTypeDeclaration)
+func parseClassBody(classType *graph.Type, bodyNode *sitter.Node, source []byte, importMap map[string]string) {
+	for i := uint32(0); i < bodyNode.NamedChildCount(); i++ {
+		child := bodyNode.NamedChild(int(i))
+
+		switch child.Type() {
+		case "field_declaration":
+			field := parseFieldDeclaration(child, source, importMap)
+			if field != nil {
+				classType.Fields = append(classType.Fields, field)
+			}
+
+		case "method_declaration":
+			method := parseMethodDeclaration(child, source, importMap)
+			if method != nil {
+				classType.Methods = append(classType.Methods, method)
+			}
+		case "constructor_declaration":
+			constructor := parseConstructorDeclaration(child, source, classType.Name, importMap)
+			if constructor != nil {
+				classType.Methods = append(classType.Methods, constructor)
+			}
+		}
+	}
+}
+
+// parseRecordDeclaration extracts record information, record components become final fields and the canonical
+// constructor is generated unless declared explicitly
+func parseRecordDeclaration(node *sitter.Node, source []byte, importMap map[string]string) *graph.Type {
+	if node.Type() != "record_declaration" {
+		return nil
+	}
+	nameNode := node.ChildByFieldName("name")
+	if nameNode == nil {
+		return nil
+	}
+	recordName := nameNode.Content(source)
+	recordType := &graph.Type{
+		Name:       recordName,
+		Kind:       reflect.Struct,
+		IsExported: isNodePublic(node, source),
+		Fields:     []*graph.Field{},
+		Methods:    []*graph.Function{},
+		Location: &graph.Location{
+			Start: int(node.StartByte()),
+			End:   int(node.EndByte()),
+		},
+	}
+	recordType.Comment, recordType.Annotation = extractDocumentation(node, source)
+	recordType.TypeParams = extractTypeParameters(node, source)
+	if interfacesNode := node.ChildByFieldName("interfaces"); interfacesNode != nil {
+		for i := uint32(0); i < interfacesNode.NamedChildCount(); i++ {
+			interfaceName := interfacesNode.NamedChild(int(i)).Content(source)
+			if packagePath, ok := importMap[extractSimpleTypeName(interfaceName)]; ok {
+				interfaceName = packagePath + "." + extractSimpleTypeName(interfaceName)
+			}
+			recordType.Implements = append(recordType.Implements, interfaceName)
+		}
+	}
+
+	parametersNode := node.ChildByFieldName("parameters")
+	components := parseParameters(parametersNode, source, importMap)
+	for _, component := range components {
+		recordType.Fields = append(recordType.Fields, &graph.Field{
+			Name: component.Name,
+			Type: component.Type,
+		})
+	}
+
 	bodyNode := node.ChildByFieldName("body")
 	if bodyNode != nil {
-		// Parse fields and methods
+		parseClassBody(recordType, bodyNode, source, importMap)
+	}
+	for _, method := range recordType.Methods {
+		if method.IsConstructor && len(method.Parameters) == len(components) {
+			return recordType // canonical constructor declared explicitly
+		}
+	}
+	constructor := &graph.Function{
+		Name:          recordName,
+		IsExported:    recordType.IsExported,
+		Parameters:    components,
+		IsConstructor: true,
+		Results:       []*graph.Parameter{{Type: &graph.Type{Name: recordName}}},
+	}
+	if parametersNode != nil {
+		constructor.Signature = recordName + "(" + formatParameters(components) + ")"
+	}
+	if bodyNode != nil {
+		// compact canonical constructor, e.g. public Point { ... }
 		for i := uint32(0); i < bodyNode.NamedChildCount(); i++ {
-			child := bodyNode.NamedChild(int(i))
-
-			switch child.Type() {
-			case "field_declaration":
-				field := parseFieldDeclaration(child, source, importMap)
-				if field != nil {
-					classType.Fields = append(classType.Fields, field)
-				}
-
-			case "method_declaration":
-				method := parseMethodDeclaration(child, source, importMap)
-				if method != nil {
-					classType.Methods = append(classType.Methods, method)
-				}
-			case "constructor_declaration":
-				constructor := parseConstructorDeclaration(child, source, className, importMap)
-				if constructor != nil {
-					classType.Methods = append(classType.Methods, constructor)
+			if child := bodyNode.NamedChild(int(i)); child.Type() == "compact_constructor_declaration" {
+				constructor.Comment, constructor.Annotation = extractDocumentation(child, source)
+				constructor.IsExported = isNodePublic(child, source)
+				constructor.Location = &graph.Location{Start: int(child.StartByte()), End: int(child.EndByte())}
+				if body := child.ChildByFieldName("body"); body != nil {
+					constructor.Body = &graph.LocationNode{Text: body.Content(source), Location: graph.Location{Start: int(body.StartByte()), End: int(body.EndByte())}}
+					constructor.References = extractReferences(body, source)
 				}
 			}
 		}
 	}
+	recordType.Methods = append([]*graph.Function{constructor}, recordType.Methods...)
+	return recordType
+}
 
-	return classType
+// parseAnonymousClasses returns synthetic types of anonymous classes instantiated in a method body
+// (e.g. new Runnable() { ... }), names are assigned once the enclosing type is known
+func parseAnonymousClasses(bodyNode *sitter.Node, source []byte, importMap map[string]string) []*graph.Type {
+	var result []*graph.Type
+	treesitter.Walk(bodyNode, func(child *sitter.Node) bool {
+		if child == bodyNode || child.Type() != "object_creation_expression" {
+			return true
+		}
+		var classBody *sitter.Node
+		for j := uint32(0); j < child.NamedChildCount(); j++ {
+			if candidate := child.NamedChild(int(j)); candidate.Type() == "class_body" {
+				classBody = candidate
+			}
+		}
+		if classBody == nil {
+			return true
+		}
+		anonymous := &graph.Type{
+			Kind:    reflect.Struct,
+			Fields:  []*graph.Field{},
+			Methods: []*graph.Function{},
+			Location: &graph.Location{
+				Start: int(child.StartByte()),
+				End:   int(child.EndByte()),
+			},
+		}
+		if typeNode := child.ChildByFieldName("type"); typeNode != nil {
+			baseName := typeNode.Content(source)
+			if packagePath, ok := importMap[extractSimpleTypeName(baseName)]; ok {
+				baseName = packagePath + "." + extractSimpleTypeName(baseName)
+			}
+			anonymous.Extends = []string{baseName}
+		}
+		parseClassBody(anonymous, classBody, source, importMap)
+		result = append(result, anonymous)
+		return false
+	})
+	return result
 }
 
 // parseInterfaceDeclaration extracts interface information from a Java source file
@@ -351,15 +477,63 @@ func parseFieldDeclaration(node *sitter.Node, source []byte, importMap map[strin
 		IsExported: isNodePublic(node, source),
 		IsStatic:   isStatic,
 		IsConstant: isFinal && isStatic,
+		Optional:   isOptionalField(typeNode, annotation.Text, source),
 		Location: &graph.Location{
 			Start: int(node.StartByte()),
 			End:   int(node.EndByte()),
 		},
 	}
+	if valueNode := declaratorNode.ChildByFieldName("value"); valueNode != nil {
+		field.Default = valueNode.Content(source)
+		field.DefaultFrom = &graph.Location{Start: int(valueNode.StartByte()), End: int(valueNode.EndByte())}
+	} else {
+		field.Default = javaZeroValue(typeNode.Content(source))
+	}
 
 	return field
 }
 
+// javaZeroValue returns the default value of an uninitialized Java field of a type
+func javaZeroValue(typeName string) string {
+	switch typeName {
+	case "boolean":
+		return "false"
+	case "char":
+		return "'\\u0000'"
+	case "byte", "short", "int":
+		return "0"
+	case "long":
+		return "0L"
+	case "float":
+		return "0.0f"
+	case "double":
+		return "0.0d"
+	}
+	return "null"
+}
+
+// isOptionalField reports whether a field is declared as Optional<T> or annotated nullable; non-null annotations take precedence
+func isOptionalField(typeNode *sitter.Node, annotations string, source []byte) bool {
+	nullable := false
+	for _, annotation := range strings.Split(annotations, "\n") {
+		name := strings.TrimPrefix(strings.TrimSpace(annotation), "@")
+		if idx := strings.Index(name, "("); idx != -1 {
+			name = name[:idx]
+		}
+		if idx := strings.LastIndex(name, "."); idx != -1 {
+			name = name[idx+1:]
+		}
+		switch name {
+		case "NotNull", "NonNull", "Nonnull":
+			return false
+		case "Nullable":
+			nullable = true
+		}
+	}
+	typeName := typeNode.Content(source)
+	return nullable || strings.HasPrefix(typeName, "Optional<") || strings.HasPrefix(typeName, "java.util.Optional<")
+}
+
 // parseMethodDeclaration extracts method information from a class
 func parseMethodDeclaration(node *sitter.Node, source []byte, importMap map[string]string) *graph.Function {
 	if node.Type() != "method_declaration" {
@@ -424,56 +598,7 @@ func parseMethodDeclaration(node *sitter.Node, source []byte, importMap map[stri
 	}
 
 	// Extract parameters
-	parametersNode := node.ChildByFieldName("parameters")
-	if parametersNode != nil {
-		for i := uint32(0); i < parametersNode.NamedChildCount(); i++ {
-			paramNode := parametersNode.NamedChild(int(i))
-
-			// Handle regular parameters
-			if paramNode.Type() == "formal_parameter" {
-				paramTypeNode := paramNode.ChildByFieldName("type")
-				paramNameNode := paramNode.ChildByFieldName("name")
-
-				if paramTypeNode != nil && paramNameNode != nil {
-					paramType := parseJavaType(paramTypeNode, source, importMap)
-					paramName := paramNameNode.Content(source)
-
-					method.Parameters = append(method.Parameters, &graph.Parameter{
-						Name: paramName,
-						Type: paramType,
-					})
-				}
-			}
-
-			// Handle variadic parameters
-			if paramNode.Type() == "spread_parameter" {
-				if paramNode.NamedChildCount() >= 2 {
-					paramTypeNode := paramNode.NamedChild(0)
-					paramDeclNode := paramNode.NamedChild(1)
-
-					if paramTypeNode != nil && paramDeclNode != nil {
-						paramNameNode := paramDeclNode.ChildByFieldName("name")
-
-						if paramNameNode != nil {
-							paramType := parseJavaType(paramTypeNode, source, importMap)
-							paramName := paramNameNode.Content(source)
-
-							// Mark this as variadic by making it a slice type
-							if paramType != nil {
-								paramType.Name = "[]" + paramType.Name
-								paramType.Kind = reflect.Slice
-							}
-
-							method.Parameters = append(method.Parameters, &graph.Parameter{
-								Name: paramName,
-								Type: paramType,
-							})
-						}
-					}
-				}
-			}
-		}
-	}
+	method.Parameters = parseParameters(node.ChildByFieldName("parameters"), source, importMap)
 
 	// Extract method body with location information
 	bodyNode := node.ChildByFieldName("body")
@@ -485,6 +610,8 @@ func parseMethodDeclaration(node *sitter.Node, source []byte, importMap map[stri
 				End:   int(bodyNode.EndByte()),
 			},
 		}
+		method.References = extractReferences(bodyNode, source)
+		method.LocalTypes = parseAnonymousClasses(bodyNode, source, importMap)
 	}
 
 	return method
@@ -537,27 +664,7 @@ func parseConstructorDeclaration(node *sitter.Node, source []byte, className str
 	}
 
 	// Extract parameters
-	parametersNode := node.ChildByFieldName("parameters")
-	if parametersNode != nil {
-		for i := uint32(0); i < parametersNode.NamedChildCount(); i++ {
-			paramNode := parametersNode.NamedChild(int(i))
-
-			if paramNode.Type() == "formal_parameter" {
-				paramTypeNode := paramNode.ChildByFieldName("type")
-				paramNameNode := paramNode.ChildByFieldName("name")
-
-				if paramTypeNode != nil && paramNameNode != nil {
-					paramType := parseJavaType(paramTypeNode, source, importMap)
-					paramName := paramNameNode.Content(source)
-
-					constructor.Parameters = append(constructor.Parameters, &graph.Parameter{
-						Name: paramName,
-						Type: paramType,
-					})
-				}
-			}
-		}
-	}
+	constructor.Parameters = parseParameters(node.ChildByFieldName("parameters"), source, importMap)
 
 	// Extract constructor body with location information
 	bodyNode := node.ChildByFieldName("body")
@@ -569,11 +676,50 @@ func parseConstructorDeclaration(node *sitter.Node, source []byte, className str
 				End:   int(bodyNode.EndByte()),
 			},
 		}
+		constructor.References = extractReferences(bodyNode, source)
+		constructor.LocalTypes = parseAnonymousClasses(bodyNode, source, importMap)
 	}
 
 	return constructor
 }
 
+// parseParameters extracts formal and variadic (spread) parameters of a method or constructor
+func parseParameters(parametersNode *sitter.Node, source []byte, importMap map[string]string) []*graph.Parameter {
+	result := []*graph.Parameter{}
+	if parametersNode == nil {
+		return result
+	}
+	for i := uint32(0); i < parametersNode.NamedChildCount(); i++ {
+		paramNode := parametersNode.NamedChild(int(i))
+		switch paramNode.Type() {
+		case "formal_parameter":
+			paramTypeNode := paramNode.ChildByFieldName("type")
+			paramNameNode := paramNode.ChildByFieldName("name")
+			if paramTypeNode != nil && paramNameNode != nil {
+				result = append(result, &graph.Parameter{
+					Name: paramNameNode.Content(source),
+					Type: parseJavaType(paramTypeNode, source, importMap),
+				})
+			}
+		case "spread_parameter":
+			// String... values: the element type is kept, the parameter is marked variadic
+			if paramNode.NamedChildCount() < 2 {
+				continue
+			}
+			paramTypeNode := paramNode.NamedChild(0)
+			paramNameNode := paramNode.NamedChild(1).ChildByFieldName("name")
+			if paramTypeNode != nil && paramNameNode != nil {
+				result = append(result, &graph.Parameter{
+					Name:       paramNameNode.Content(source),
+					Type:       parseJavaType(paramTypeNode, source, importMap),
+					IsVariadic: true,
+				})
+			}
+		}
+	}
+	return result
+}
+
 // formatMethodSignature creates a full signature for a method including return type and package information
 func formatMethodSignature(name string, node *sitter.Node, source []byte, importMap map[string]string) string {
 	var signature strings.Builder
@@ -584,12 +730,8 @@ func formatMethodSignature(name string, node *sitter.Node, source []byte, import
 		if typeNode != nil {
 			returnType := parseJavaType(typeNode, source, importMap)
 			if returnType != nil {
-				// Try to use fully qualified name for return type
-				typeName := returnType.Name
-				if returnType.PackagePath != "" && !strings.Contains(typeName, ".") {
-					typeName = returnType.PackagePath + "." + typeName
-				}
-				signature.WriteString(typeName)
+				// Try to use fully qualified original Java name for return type
+				signature.WriteString(qualifiedJavaType(returnType))
 				signature.WriteString(" ")
 			}
 		}
@@ -630,57 +772,7 @@ func formatMethodSignature(name string, node *sitter.Node, source []byte, import
 
 	// Format parameters
 	signature.WriteString("(")
-	parametersNode := node.ChildByFieldName("parameters")
-	if parametersNode != nil {
-		var params []string
-		for i := uint32(0); i < parametersNode.NamedChildCount(); i++ {
-			paramNode := parametersNode.NamedChild(int(i))
-
-			if paramNode.Type() == "formal_parameter" {
-				paramTypeNode := paramNode.ChildByFieldName("type")
-				paramNameNode := paramNode.ChildByFieldName("name")
-
-				if paramTypeNode != nil && paramNameNode != nil {
-					paramType := parseJavaType(paramTypeNode, source, importMap)
-					paramName := paramNameNode.Content(source)
-
-					if paramType != nil {
-						// Try to use fully qualified name for parameter type
-						typeName := paramType.Name
-						if paramType.PackagePath != "" && !strings.Contains(typeName, ".") {
-							typeName = paramType.PackagePath + "." + typeName
-						}
-						params = append(params, typeName+" "+paramName)
-					}
-				}
-			} else if paramNode.Type() == "spread_parameter" {
-				// Handle variadic parameters
-				if paramNode.NamedChildCount() >= 2 {
-					paramTypeNode := paramNode.NamedChild(0)
-					paramDeclNode := paramNode.NamedChild(1)
-
-					if paramTypeNode != nil && paramDeclNode != nil {
-						paramNameNode := paramDeclNode.ChildByFieldName("name")
-
-						if paramNameNode != nil {
-							paramType := parseJavaType(paramTypeNode, source, importMap)
-							paramName := paramNameNode.Content(source)
-
-							if paramType != nil {
-								// Try to use fully qualified name
-								typeName := paramType.Name
-								if paramType.PackagePath != "" && !strings.Contains(typeName, ".") {
-									typeName = paramType.PackagePath + "." + typeName
-								}
-								params = append(params, typeName+"... "+paramName)
-							}
-						}
-					}
-				}
-			}
-		}
-		signature.WriteString(strings.Join(params, ", "))
-	}
+	signature.WriteString(formatParameters(parseParameters(node.ChildByFieldName("parameters"), source, importMap)))
 	signature.WriteString(")")
 
 	// Add exceptions if present
@@ -704,6 +796,44 @@ func formatMethodSignature(name string, node *sitter.Node, source []byte, import
 	return signature.String()
 }
 
+// formatParameters returns canonical parameter list of a signature, e.g. java.lang.String filter, long... ids
+func formatParameters(parameters []*graph.Parameter) string {
+	var params []string
+	for _, param := range parameters {
+		if param.Type == nil {
+			continue
+		}
+		typeName := qualifiedJavaType(param.Type)
+		if param.IsVariadic {
+			typeName += "..."
+		}
+		params = append(params, typeName+" "+param.Name)
+	}
+	return strings.Join(params, ", ")
+}
+
+// qualifiedJavaType returns the original Java type name qualified with its package when known, with canonical spacing
+func qualifiedJavaType(aType *graph.Type) string {
+	typeName := canonicalJavaType(graph.JavaTypeFor(aType))
+	if aType.PackagePath != "" && !strings.Contains(typeName, ".") {
+		typeName = aType.PackagePath + "." + typeName
+	}
+	return typeName
+}
+
+// canonicalJavaType normalizes whitespace of a Java type, e.g. Map< String ,User > -> Map<String, User>
+func canonicalJavaType(typeName string) string {
+	fields := strings.Fields(typeName)
+	builder := strings.Builder{}
+	for i, field := range fields {
+		if i > 0 && !strings.ContainsAny(field[:1], "<>[],.") && !strings.ContainsAny(fields[i-1][len(fields[i-1])-1:], "<[,.") {
+			builder.WriteString(" ") // keep separating spaces, e.g. ? extends Number
+		}
+		builder.WriteString(field)
+	}
+	return strings.ReplaceAll(builder.String(), ",", ", ")
+}
+
 // parseAnnotationDeclaration extracts annotation declarations
 func parseAnnotationDeclaration(node *sitter.Node, source []byte) string {
 	if node.Type() != "annotation" && node.Type() != "marker_annotation" {
@@ -716,9 +846,10 @@ func parseAnnotationDeclaration(node *sitter.Node, source []byte) string {
 // isNodePublic checks if a node has the 'public' modifier
 func isNodePublic(node *sitter.Node, source []byte) bool {
 	if node.NamedChildCount() > 0 && node.NamedChild(0).Type() == "modifiers" {
+		// modifier keywords are anonymous nodes
 		modifiersNode := node.NamedChild(0)
-		for i := uint32(0); i < modifiersNode.NamedChildCount(); i++ {
-			modifier := modifiersNode.NamedChild(int(i))
+		for i := uint32(0); i < modifiersNode.ChildCount(); i++ {
+			modifier := modifiersNode.Child(int(i))
 			if modifier.Type() == "public" {
 				return true
 			}
@@ -3,61 +3,314 @@ package java
 import (
 	"fmt"
 	"github.com/viant/linager/inspector/graph"
+	"reflect"
 	"strings"
 )
 
-type Emitter struct{}
+// Brace styles of Java emitter
+const (
+	BraceSameLine = "same-line"
+	BraceNextLine = "next-line"
+)
+
+// EmitterOptions controls Java source layout, the zero value indents with 4 spaces and keeps braces on the declaration line
+type EmitterOptions struct {
+	IndentWidth int    // Number of spaces per indentation level, defaults to 4
+	UseTabs     bool   // Indent with tabs instead of spaces
+	BraceStyle  string // BraceSameLine (default) or BraceNextLine
+}
+
+// Emitter renders a graph file as Java source, types are written with their original Java names (see graph.JavaTypeFor)
+type Emitter struct {
+	Options EmitterOptions
+}
+
+// NewEmitter creates a Java emitter with the given options, nil options use defaults
+func NewEmitter(options *EmitterOptions) *Emitter {
+	ret := &Emitter{}
+	if options != nil {
+		ret.Options = *options
+	}
+	return ret
+}
+
+// Capabilities returns Java emitter capabilities
+func (g *Emitter) Capabilities() graph.EmitterCapabilities {
+	return graph.EmitterCapabilities{
+		Language:   "java",
+		Extensions: []string{".java"},
+		Indent:     g.indent(),
+		BraceStyle: g.braceStyle(),
+	}
+}
+
+// indent returns a single indentation level
+func (g *Emitter) indent() string {
+	if g.Options.UseTabs {
+		return "\t"
+	}
+	width := g.Options.IndentWidth
+	if width <= 0 {
+		width = 4
+	}
+	return strings.Repeat(" ", width)
+}
+
+func (g *Emitter) braceStyle() string {
+	if g.Options.BraceStyle == BraceNextLine {
+		return BraceNextLine
+	}
+	return BraceSameLine
+}
+
+// openBrace returns an opening brace of a block declared at the given indentation
+func (g *Emitter) openBrace(prefix string) string {
+	if g.braceStyle() == BraceNextLine {
+		return "\n" + prefix + "{\n"
+	}
+	return " {\n"
+}
+
+// span holds an emitted declaration range until the source map is built
+type span struct {
+	kind, name string
+	start, end int
+}
 
 func (g *Emitter) Emit(file *graph.File) ([]byte, error) {
-	// Start with package declaration and imports
+	return g.emit(file, nil), nil
+}
+
+// EmitWithSourceMap emits a file with ranges of its types, fields, methods and enum constants
+func (g *Emitter) EmitWithSourceMap(file *graph.File) (*graph.EmitResult, error) {
+	var spans []*span
+	content := g.emit(file, &spans)
+	sourceMap := graph.NewSourceMap(file.Path)
+	for _, item := range spans {
+		sourceMap.Add(item.kind, item.name, content, item.start, item.end)
+	}
+	return &graph.EmitResult{Content: content, SourceMap: sourceMap}, nil
+}
+
+// emit renders a file, declaration ranges are appended to spans when not nil
+func (g *Emitter) emit(file *graph.File, spans *[]*span) []byte {
 	builder := &strings.Builder{}
-	builder.WriteString(fmt.Sprintf("package %s\n\n", file.Package))
+	pkg := file.ImportPath // In Java, the package name is the import path
+	if pkg == "" {
+		pkg = file.Package
+	}
+	if pkg != "" {
+		builder.WriteString(fmt.Sprintf("package %s;\n\n", pkg))
+	}
 
 	// Add imports if any
 	if len(file.Imports) > 0 {
-		builder.WriteString("import (\n")
 		for _, imp := range file.Imports {
+			path := imp.Path
 			if imp.Name != "" {
-				builder.WriteString(fmt.Sprintf("\t%s %q\n", imp.Name, imp.Path))
-			} else {
-				builder.WriteString(fmt.Sprintf("\t%q\n", imp.Path))
+				path = imp.Path + "." + strings.TrimPrefix(imp.Name, extractSimpleTypeName(imp.Path)+".")
+			}
+			builder.WriteString(fmt.Sprintf("import %s;\n", path))
+		}
+		builder.WriteString("\n")
+	}
+
+	for i, typ := range file.Types {
+		if i > 0 {
+			builder.WriteString("\n")
+		}
+		g.emitType(builder, file, typ, spans)
+	}
+	return []byte(builder.String())
+}
+
+// record appends a declaration range ending at the builder length
+func record(spans *[]*span, builder *strings.Builder, kind, name string, start int) {
+	if spans != nil {
+		*spans = append(*spans, &span{kind: kind, name: name, start: start, end: builder.Len()})
+	}
+}
+
+// emitType writes a class, interface or enum declaration
+func (g *Emitter) emitType(builder *strings.Builder, file *graph.File, typ *graph.Type, spans *[]*span) {
+	typeStart := writeDocumentation(builder, "", typ.Comment, typ.Annotation)
+	if typeStart == -1 {
+		typeStart = builder.Len()
+	}
+	if typ.IsExported {
+		builder.WriteString("public ")
+	}
+	keyword := "class"
+	switch typ.Kind {
+	case reflect.Interface:
+		keyword = "interface"
+	case reflect.Int:
+		keyword = "enum"
+	}
+	builder.WriteString(keyword + " " + typ.Name)
+	writeTypeParams(builder, typ.TypeParams)
+	if len(typ.Extends) > 0 {
+		builder.WriteString(" extends " + strings.Join(typ.Extends, ", "))
+	}
+	if len(typ.Implements) > 0 {
+		builder.WriteString(" implements " + strings.Join(typ.Implements, ", "))
+	}
+	builder.WriteString(g.openBrace(""))
+	indent := g.indent()
+
+	if keyword == "enum" {
+		builder.WriteString(indent)
+		count := 0
+		for _, constant := range file.Constants {
+			if !strings.HasPrefix(constant.Value, typ.Name+".") {
+				continue
+			}
+			if count > 0 {
+				builder.WriteString(", ")
 			}
+			count++
+			start := builder.Len()
+			builder.WriteString(constant.Name)
+			record(spans, builder, graph.SourceKindConstant, constant.Name, start)
 		}
-		builder.WriteString(")\n\n")
+		builder.WriteString(";\n")
 	}
 
-	// Add constants if any
-	for _, constant := range file.Constants {
-		if constant.Location != nil && constant.Location.Raw != "" {
-			builder.WriteString(constant.Location.Raw)
-			builder.WriteString("\n\n")
+	for _, field := range typ.Fields {
+		fieldStart := writeDocumentation(builder, indent, &graph.LocationNode{Text: field.Comment}, &graph.LocationNode{Text: field.Annotation})
+		builder.WriteString(indent)
+		if fieldStart == -1 {
+			fieldStart = builder.Len()
+		}
+		if field.IsExported {
+			builder.WriteString("public ")
+		} else {
+			builder.WriteString("private ")
+		}
+		if field.IsStatic {
+			builder.WriteString("static ")
 		}
+		if field.IsConstant {
+			builder.WriteString("final ")
+		}
+		builder.WriteString(fmt.Sprintf("%s %s;", graph.JavaTypeFor(field.Type), field.Name))
+		record(spans, builder, graph.SourceKindField, typ.Name+"."+field.Name, fieldStart)
+		builder.WriteString("\n")
+	}
+
+	for _, method := range typ.Methods {
+		builder.WriteString("\n")
+		g.emitMethod(builder, typ, method, spans)
 	}
+	builder.WriteString("}")
+	record(spans, builder, graph.SourceKindType, typ.Name, typeStart)
+	builder.WriteString("\n")
+}
 
-	// Add variables if any
-	for _, variable := range file.Variables {
-		if variable.Location != nil && variable.Location.Raw != "" {
-			builder.WriteString(variable.Location.Raw)
-			builder.WriteString("\n\n")
+// emitMethod writes a method or constructor declaration
+func (g *Emitter) emitMethod(builder *strings.Builder, typ *graph.Type, method *graph.Function, spans *[]*span) {
+	indent := g.indent()
+	start := writeDocumentation(builder, indent, method.Comment, method.Annotation)
+	builder.WriteString(indent)
+	if start == -1 {
+		start = builder.Len()
+	}
+	if method.IsExported {
+		builder.WriteString("public ")
+	}
+	if method.IsStatic {
+		builder.WriteString("static ")
+	}
+	if len(method.TypeParams) > 0 {
+		writeTypeParams(builder, method.TypeParams)
+		builder.WriteString(" ")
+	}
+	if !method.IsConstructor {
+		result := "void"
+		if len(method.Results) > 0 {
+			result = graph.JavaTypeFor(method.Results[0].Type)
 		}
+		builder.WriteString(result + " ")
 	}
+	var params []string
+	for _, param := range method.Parameters {
+		typeName := graph.JavaTypeFor(param.Type)
+		if param.IsVariadic {
+			typeName += "..."
+		}
+		params = append(params, typeName+" "+param.Name)
+	}
+	builder.WriteString(method.Name + "(" + strings.Join(params, ", ") + ")")
+	switch {
+	case method.Body != nil && method.Body.Text != "":
+		builder.WriteString(g.methodBody(method.Body.Text, indent))
+	case typ.Kind == reflect.Interface:
+		builder.WriteString(";")
+	default:
+		builder.WriteString(g.openBrace(indent) + indent + "}")
+	}
+	record(spans, builder, graph.SourceKindFunction, typ.Name+"."+method.Name, start)
+	builder.WriteString("\n")
+}
 
-	// Add types if any
-	for _, typ := range file.Types {
-		if typ.Location != nil && typ.Location.Raw != "" {
-			// Use the Type.Content() method which includes fields
-			builder.WriteString(typ.Content())
-			builder.WriteString("\n\n")
+// methodBody returns a recorded method body preceded by its brace separator, body lines are re-indented
+// from the original indentation unit, inferred from the closing brace, to the emitter indentation
+func (g *Emitter) methodBody(body, indent string) string {
+	lines := strings.Split(body, "\n")
+	last := lines[len(lines)-1]
+	unit := last[:len(last)-len(strings.TrimLeft(last, " \t"))]
+	if len(lines) > 1 && unit != "" && unit != indent {
+		for i := 1; i < len(lines); i++ {
+			levels, rest := 0, lines[i]
+			for strings.HasPrefix(rest, unit) {
+				levels++
+				rest = rest[len(unit):]
+			}
+			lines[i] = strings.Repeat(indent, levels) + rest
 		}
 	}
+	text := strings.Join(lines, "\n")
+	if strings.HasPrefix(text, "{") {
+		return strings.TrimSuffix(g.openBrace(indent), "{\n") + text
+	}
+	return " " + text
+}
 
-	// Add functions if any
-	for _, function := range file.Functions {
-		if function.Location != nil && function.Location.Raw != "" {
-			builder.WriteString(function.Location.Raw)
-			builder.WriteString("\n\n")
+// writeTypeParams writes generic type parameters, e.g. <T extends Comparable<T>>
+func writeTypeParams(builder *strings.Builder, params []*graph.TypeParam) {
+	if len(params) == 0 {
+		return
+	}
+	var items []string
+	for _, param := range params {
+		item := param.Name
+		if param.Constraint != "" && param.Constraint != "any" {
+			item += " extends " + param.Constraint
 		}
+		items = append(items, item)
 	}
+	builder.WriteString("<" + strings.Join(items, ", ") + ">")
+}
 
-	return []byte(builder.String()), nil
+// writeDocumentation writes Javadoc comment and annotations preceding a declaration, it returns the offset of the first
+// annotation, which starts the declaration, or -1 without annotations
+func writeDocumentation(builder *strings.Builder, prefix string, comment, annotation *graph.LocationNode) int {
+	if comment != nil && strings.TrimSpace(comment.Text) != "" {
+		builder.WriteString(prefix + "/**\n")
+		for _, line := range strings.Split(strings.TrimSpace(comment.Text), "\n") {
+			builder.WriteString(strings.TrimRight(prefix+" * "+strings.TrimSpace(line), " ") + "\n")
+		}
+		builder.WriteString(prefix + " */\n")
+	}
+	start := -1
+	if annotation != nil && strings.TrimSpace(annotation.Text) != "" {
+		for i, line := range strings.Split(strings.TrimSpace(annotation.Text), "\n") {
+			builder.WriteString(prefix)
+			if i == 0 {
+				start = builder.Len()
+			}
+			builder.WriteString(strings.TrimSpace(line) + "\n")
+		}
+	}
+	return start
 }
@@ -0,0 +1,219 @@
+package java_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/linager/inspector/graph"
+	"github.com/viant/linager/inspector/java"
+	"testing"
+)
+
+func TestEmitter_Emit(t *testing.T) {
+	source := `package com.example;
+
+import java.util.List;
+import java.util.Map;
+
+public class User {
+    private String name;
+    private boolean active;
+    private List<Order> orders;
+    private int[] codes;
+    private Map<String, Integer> counts;
+
+    public User(String name) {
+        this.name = name;
+    }
+
+    public List<Order> getOrders(String filter, boolean all, long... ids) {
+        return orders;
+    }
+}
+`
+	inspector := java.NewInspector(&graph.Config{IncludeUnexported: true})
+	file, err := inspector.InspectSource([]byte(source))
+	if !assert.NoError(t, err) {
+		return
+	}
+	emitted, err := (&java.Emitter{}).Emit(file)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, source, string(emitted))
+
+	// original Java types are restored after a round trip
+	restored, err := java.NewInspector(&graph.Config{IncludeUnexported: true}).InspectSource(emitted)
+	if !assert.NoError(t, err) || !assert.Len(t, restored.Types, 1) {
+		return
+	}
+	type fieldType struct{ Name, RawName, Go string }
+	var fields []fieldType
+	for _, field := range restored.Types[0].Fields {
+		fields = append(fields, fieldType{Name: field.Type.Name, RawName: field.Type.RawName, Go: graph.GoTypeFor(field.Type)})
+	}
+	assert.Equal(t, []fieldType{
+		{Name: "string", RawName: "String", Go: "string"},
+		{Name: "bool", RawName: "boolean", Go: "bool"},
+		{Name: "List", RawName: "List<Order>", Go: "[]*Order"},
+		{Name: "[]int32", RawName: "int[]", Go: "[]int32"},
+		{Name: "Map", RawName: "Map<String, Integer>", Go: "map[string]int32"},
+	}, fields)
+	method := restored.Types[0].GetMethod("getOrders")
+	if assert.NotNil(t, method) {
+		assert.Equal(t, "java.util.List<Order> getOrders(java.lang.String filter, boolean all, long... ids)", method.Signature)
+		if assert.Len(t, method.Parameters, 3) {
+			assert.True(t, method.Parameters[2].IsVariadic)
+			assert.Equal(t, "long", graph.JavaTypeFor(method.Parameters[2].Type))
+		}
+	}
+}
+
+func TestEmitter_Emit_Options(t *testing.T) {
+	source := `package com.example;
+
+public class Counter {
+    private int count;
+
+    public int next(int step) {
+        if (step > 0) {
+            count += step;
+        }
+        return count;
+    }
+}
+`
+	file, err := java.NewInspector(&graph.Config{IncludeUnexported: true}).InspectSource([]byte(source))
+	if !assert.NoError(t, err) {
+		return
+	}
+	var testCases = []struct {
+		description string
+		options     *java.EmitterOptions
+		expect      string
+	}{
+		{
+			description: "default options keep source layout",
+			expect:      source,
+		},
+		{
+			description: "2-space indent",
+			options:     &java.EmitterOptions{IndentWidth: 2},
+			expect: `package com.example;
+
+public class Counter {
+  private int count;
+
+  public int next(int step) {
+    if (step > 0) {
+      count += step;
+    }
+    return count;
+  }
+}
+`,
+		},
+		{
+			description: "next-line braces",
+			options:     &java.EmitterOptions{IndentWidth: 2, BraceStyle: java.BraceNextLine},
+			expect: `package com.example;
+
+public class Counter
+{
+  private int count;
+
+  public int next(int step)
+  {
+    if (step > 0) {
+      count += step;
+    }
+    return count;
+  }
+}
+`,
+		},
+	}
+	for _, testCase := range testCases {
+		emitter := java.NewEmitter(testCase.options)
+		emitted, err := emitter.Emit(file)
+		if !assert.NoError(t, err, testCase.description) {
+			continue
+		}
+		assert.Equal(t, testCase.expect, string(emitted), testCase.description)
+	}
+	assert.Equal(t, "  ", java.NewEmitter(&java.EmitterOptions{IndentWidth: 2}).Capabilities().Indent)
+}
+
+func TestJavaTypeFor(t *testing.T) {
+	var testCases = []struct {
+		description string
+		aType       *graph.Type
+		expect      string
+	}{
+		{description: "raw name", aType: &graph.Type{Name: "List", RawName: "List<User>"}, expect: "List<User>"},
+		{description: "string", aType: &graph.Type{Name: "string"}, expect: "String"},
+		{description: "bool", aType: &graph.Type{Name: "bool"}, expect: "boolean"},
+		{description: "slice", aType: &graph.Type{Name: "[]int32"}, expect: "int[]"},
+		{description: "pointer", aType: &graph.Type{Name: "*User"}, expect: "User"},
+		{description: "map", aType: &graph.Type{Name: "map[string]int64"}, expect: "Map<String, Long>"},
+		{description: "nested map", aType: &graph.Type{Name: "map[string][]float64"}, expect: "Map<String, double[]>"},
+	}
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expect, graph.JavaTypeFor(testCase.aType), testCase.description)
+	}
+}
+
+func TestEmitter_EmitWithSourceMap(t *testing.T) {
+	source := `package com.example;
+
+public class User {
+    private String name;
+    @Deprecated
+    private int age;
+
+    public String getName() {
+        return name;
+    }
+}
+
+class Order {
+    private long id;
+
+    public long getId() {
+        return id;
+    }
+}
+`
+	file, err := java.NewInspector(&graph.Config{IncludeUnexported: true}).InspectSource([]byte(source))
+	if !assert.NoError(t, err) {
+		return
+	}
+	locations := map[string]*graph.Location{}
+	for _, aType := range file.Types {
+		locations[graph.SourceKindType+":"+aType.Name] = aType.Location
+		for _, field := range aType.Fields {
+			locations[graph.SourceKindField+":"+aType.Name+"."+field.Name] = field.Location
+		}
+		for _, method := range aType.Methods {
+			locations[graph.SourceKindFunction+":"+aType.Name+"."+method.Name] = method.Location
+		}
+	}
+	result, err := (&java.Emitter{}).EmitWithSourceMap(file)
+	if !assert.NoError(t, err) || !assert.NotNil(t, result.SourceMap) {
+		return
+	}
+	assert.Equal(t, source, string(result.Content))
+	var names []string
+	for _, item := range result.SourceMap.Ranges {
+		names = append(names, item.Kind+":"+item.Name)
+		location := locations[item.Kind+":"+item.Name]
+		if assert.NotNil(t, location, item.Name) {
+			assert.Equal(t, source[location.Start:location.End], string(result.Content[item.Start:item.End]), item.Name)
+		}
+	}
+	assert.Equal(t, []string{"field:User.name", "field:User.age", "function:User.getName", "type:User",
+		"field:Order.id", "function:Order.getId", "type:Order"}, names)
+	age := result.SourceMap.Lookup(graph.SourceKindField, "User.age")
+	if assert.NotNil(t, age) {
+		assert.Equal(t, 5, age.StartLine)
+		assert.Equal(t, 6, age.EndLine)
+	}
+}
@@ -2,6 +2,7 @@ package java
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	path "path"
@@ -11,6 +12,7 @@ import (
 	sitter "github.com/smacker/go-tree-sitter"
 	"github.com/smacker/go-tree-sitter/java"
 	"github.com/viant/linager/inspector/graph"
+	"github.com/viant/linager/inspector/treesitter"
 )
 
 // Inspector provides functionality to inspect Java code and extract type information
@@ -18,6 +20,7 @@ type Inspector struct {
 	config    *graph.Config
 	importMap map[string]string
 	source    []byte
+	trees     *treesitter.Trees // retained parsed trees, nil unless Config.RetainTrees is set
 }
 
 // NewInspector creates a new Java Inspector with the provided configuration
@@ -29,64 +32,67 @@ func NewInspector(config *graph.Config) *Inspector {
 			RecursivePackages: false,
 		}
 	}
-	return &Inspector{
+	ret := &Inspector{
 		config: config,
 	}
+	if config.RetainTrees {
+		ret.trees = treesitter.NewTrees()
+	}
+	return ret
 }
 
-// InspectSource parses Java source code from a byte slice and extracts types
+// InspectSource parses Java source code from a byte slice and extracts types, the file is named source.java: with
+// graph.Config.RetainTrees each call replaces the previously retained source.java tree, see InspectNamedSource
 func (i *Inspector) InspectSource(src []byte) (*graph.File, error) {
-	i.source = src
-
-	parser := sitter.NewParser()
-	parser.SetLanguage(java.GetLanguage())
-
-	tree, err := parser.ParseCtx(context.Background(), nil, src)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse source: %w", err)
-	}
-
-	rootNode := tree.RootNode()
-
-	return i.processJavaFile(rootNode, src, "source.java")
+	return i.InspectNamedSource("source.java", src)
 }
 
 // InspectFile parses a Java source file and extracts types
 func (i *Inspector) InspectFile(filename string) (*graph.File, error) {
 	src, err := os.ReadFile(filename)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read file %s: %w", filename, err)
+		return nil, fmt.Errorf("failed to read file %s: %w", filename, graph.NotFoundError("file", filename, err))
 	}
+	return i.InspectNamedSource(filename, src)
+}
 
+// InspectNamedSource parses Java source code of a named file and extracts types, retained trees are keyed by the name
+func (i *Inspector) InspectNamedSource(filename string, src []byte) (*graph.File, error) {
 	i.source = src
 
 	parser := sitter.NewParser()
 	parser.SetLanguage(java.GetLanguage())
 
-	tree, err := parser.ParseCtx(context.Background(), nil, src)
+	tree, err := treesitter.Parse(parser, src, i.config.ParseDeadline())
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &graph.File{Path: filename, Warnings: []*graph.ParseWarning{treesitter.TimeoutWarning(filename, i.config.ParseDeadline())}}, nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse file %s: %w", filename, err)
+		return nil, &graph.ErrParse{Path: filename, Cause: err}
+	}
+	if i.trees != nil {
+		i.trees.Retain(filename, tree, src, java.GetLanguage())
 	}
 
 	rootNode := tree.RootNode()
 
-	aFile, err := i.processJavaFile(rootNode, src, filename)
-	if err != nil {
-		return nil, err
-	}
+	return i.processJavaFile(rootNode, src, filename)
+}
 
-	// Extract imports
-	for _, importNode := range findImportNodes(rootNode) {
-		imports := parseImportDeclarations(importNode, src)
-		for name, path := range imports {
-			aFile.Imports = append(aFile.Imports, graph.Import{
-				Name: name,
-				Path: path,
-			})
-		}
+// Query runs a tree-sitter query over the retained tree of a file (see graph.Config.RetainTrees)
+func (i *Inspector) Query(file string, query string) ([]treesitter.QueryMatch, error) {
+	if i.trees == nil {
+		return nil, &graph.ErrNotFound{Kind: "tree", Name: file}
 	}
+	return i.trees.Query(file, query)
+}
 
-	return aFile, nil
+// Close releases retained parsed trees
+func (i *Inspector) Close() error {
+	if i.trees == nil {
+		return nil
+	}
+	return i.trees.Close()
 }
 
 // findImportNodes finds all import declaration nodes in the AST
@@ -138,10 +144,24 @@ func (i *Inspector) InspectPackage(packagePath string) (*graph.Package, error) {
 			continue
 		}
 		// Skip test files unless configured to include them
-		if i.config.SkipTests && (filepath.Base(filePath) == "Test.java" ||
-			filepath.Base(filePath) == "Tests.java" ||
-			filepath.Base(filePath) == "IT.java" ||
-			filepath.Base(filePath) == "ITCase.java") {
+		if i.config.SkipTests && graph.IsTestFile(filepath.Base(filePath)) {
+			continue
+		}
+		// Skip oversized and binary files
+		skipped, err := i.config.CheckFile(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("error processing %s: %w", filePath, err)
+		}
+		if skipped != nil {
+			pkg.Skipped = append(pkg.Skipped, skipped)
+			continue
+		}
+
+		// Package and module descriptors declare metadata, not types
+		if name := filepath.Base(filePath); name == graph.PackageInfoFile || name == graph.ModuleInfoFile {
+			if err = i.inspectDescriptor(pkg, filePath); err != nil {
+				return nil, fmt.Errorf("error processing %s: %w", filePath, err)
+			}
 			continue
 		}
 
@@ -164,16 +184,39 @@ func (i *Inspector) InspectPackage(packagePath string) (*graph.Package, error) {
 		return nil, fmt.Errorf("error walking package directory: %w", err)
 	}
 
-	if len(pkg.FileSet) == 0 {
+	if len(pkg.FileSet) == 0 && len(pkg.Skipped) == 0 && len(pkg.Assets) == 0 {
 		return nil, fmt.Errorf("no Java files found in package: %s", packagePath)
 	}
 
 	return pkg, nil
 }
 
+// inspectDescriptor adds a package-info.java or module-info.java file as a package asset, package-info Javadoc and
+// annotations are attached to the package
+func (i *Inspector) inspectDescriptor(pkg *graph.Package, filePath string) error {
+	if filepath.Base(filePath) == graph.PackageInfoFile {
+		info, err := inspectPackageInfo(filePath, i.config.ParseDeadline())
+		if err != nil {
+			return err
+		}
+		if info.name != "" {
+			pkg.ImportPath = info.name
+		}
+		pkg.Comment, pkg.Annotations = info.comment, info.annotations
+	}
+	asset, err := graph.NewAsset(filePath, pkg.ImportPath, i.config.EagerAssetSizeLimit(), nil)
+	if err != nil {
+		return err
+	}
+	asset.Name = filepath.Base(filePath)
+	pkg.Assets = append(pkg.Assets, asset)
+	return nil
+}
+
 // processJavaFile extracts package, types, constants, and variables from a Java file
 func (i *Inspector) processJavaFile(rootNode *sitter.Node, src []byte, filename string) (*graph.File, error) {
 	aFile := &graph.File{Path: filename}
+	aFile.SetSource(src)
 
 	// Find package declaration
 	var packageNode *sitter.Node
@@ -188,7 +231,7 @@ func (i *Inspector) processJavaFile(rootNode *sitter.Node, src []byte, filename
 			packageNode = childNode
 		case "import_declaration":
 			importNodes = append(importNodes, childNode)
-		case "class_declaration", "interface_declaration", "enum_declaration", "annotation_type_declaration":
+		case "class_declaration", "record_declaration", "interface_declaration", "enum_declaration", "annotation_type_declaration":
 			typeNodes = append(typeNodes, childNode)
 		}
 	}
@@ -203,57 +246,78 @@ func (i *Inspector) processJavaFile(rootNode *sitter.Node, src []byte, filename
 	for _, importNode := range importNodes {
 		for k, v := range parseImportDeclarations(importNode, src) {
 			importMap[k] = v
+			aFile.Imports = append(aFile.Imports, graph.Import{Name: k, Path: v})
 		}
 	}
 
 	// Process types
 	for _, typeNode := range typeNodes {
-		switch typeNode.Type() {
-		case "class_declaration":
-			classType := parseClassDeclaration(typeNode, src, importMap)
-			if classType != nil {
-				if !i.config.IncludeUnexported && !classType.IsExported {
-					continue
-				}
-				aFile.Types = append(aFile.Types, classType)
-			}
-		case "interface_declaration":
-			interfaceType := parseInterfaceDeclaration(typeNode, src, importMap)
-			if interfaceType != nil {
-				if !i.config.IncludeUnexported && !interfaceType.IsExported {
-					continue
-				}
-				aFile.Types = append(aFile.Types, interfaceType)
-			}
-		case "enum_declaration":
-			enumType := parseEnumDeclaration(typeNode, src)
-			if enumType != nil {
-				if !i.config.IncludeUnexported && !enumType.IsExported {
-					continue
-				}
-				aFile.Types = append(aFile.Types, enumType)
-
-				// Add enum constants
-				aFile.Constants = append(aFile.Constants, extractEnumConstants(typeNode, src, enumType.Name)...)
-			}
-		case "annotation_type_declaration":
-			annotationType := parseAnnotationTypeDeclaration(typeNode, src)
-			if annotationType != nil {
-				if !i.config.IncludeUnexported && !annotationType.IsExported {
-					continue
-				}
-				aFile.Types = append(aFile.Types, annotationType)
-			}
-		}
+		i.addTypeDeclaration(aFile, typeNode, src, importMap, "")
 	}
 
 	// Extract constants and variables
 	aFile.Constants = append(aFile.Constants, extractConstantsFromTypes(aFile.Types)...)
 	aFile.Variables = append(aFile.Variables, extractVariablesFromTypes(aFile.Types)...)
+	if i.config.ParseCoverage {
+		aFile.Coverage = i.parseCoverage(rootNode, src, filename, aFile)
+	}
 
 	return aFile, nil
 }
 
+// addTypeDeclaration adds a type declaration and its nested type declarations to the file, nested types are
+// qualified by the enclosing type name (e.g. Outer.Inner) and anonymous classes of methods are named Outer$1, Outer$2...
+func (i *Inspector) addTypeDeclaration(aFile *graph.File, node *sitter.Node, src []byte, importMap map[string]string, parent string) {
+	var aType *graph.Type
+	switch node.Type() {
+	case "class_declaration":
+		aType = parseClassDeclaration(node, src, importMap)
+	case "record_declaration":
+		aType = parseRecordDeclaration(node, src, importMap)
+	case "interface_declaration":
+		aType = parseInterfaceDeclaration(node, src, importMap)
+	case "enum_declaration":
+		aType = parseEnumDeclaration(node, src)
+	case "annotation_type_declaration":
+		aType = parseAnnotationTypeDeclaration(node, src)
+	}
+	if aType == nil {
+		return
+	}
+	if parent != "" {
+		aType.Name = parent + "." + aType.Name
+		aType.ParentType = parent
+	}
+	if !i.config.IncludeUnexported && !aType.IsExported {
+		return
+	}
+	aFile.Types = append(aFile.Types, aType)
+	if node.Type() == "enum_declaration" {
+		// Add enum constants
+		aFile.Constants = append(aFile.Constants, extractEnumConstants(node, src, aType.Name)...)
+	}
+
+	anonymous := 0
+	for _, method := range aType.Methods {
+		for _, localType := range method.LocalTypes {
+			anonymous++
+			localType.Name = fmt.Sprintf("%s$%d", aType.Name, anonymous)
+			localType.ParentType = aType.Name
+		}
+	}
+
+	bodyNode := node.ChildByFieldName("body")
+	if bodyNode == nil {
+		return
+	}
+	for j := uint32(0); j < bodyNode.NamedChildCount(); j++ {
+		switch child := bodyNode.NamedChild(int(j)); child.Type() {
+		case "class_declaration", "record_declaration", "interface_declaration", "enum_declaration", "annotation_type_declaration":
+			i.addTypeDeclaration(aFile, child, src, importMap, aType.Name)
+		}
+	}
+}
+
 // extractEnumConstants extracts enum constant values from an enum declaration
 func extractEnumConstants(node *sitter.Node, source []byte, enumName string) []*graph.Constant {
 	if node.Type() != "enum_declaration" {
@@ -1,10 +1,14 @@
 package java_test
 
 import (
+	"context"
 	"github.com/stretchr/testify/assert"
 	"github.com/viant/linager/inspector/graph"
 	"github.com/viant/linager/inspector/java"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -46,6 +50,7 @@ public class Person {
 							Name: "name",
 							Type: &graph.Type{
 								Name:        "string",
+								RawName:     "String",
 								Kind:        reflect.String,
 								PackagePath: "java.lang",
 							},
@@ -54,7 +59,8 @@ public class Person {
 						{
 							Name: "age",
 							Type: &graph.Type{
-								Name: "int",
+								Name:    "int32",
+								RawName: "int",
 							},
 							IsExported: false,
 						},
@@ -68,6 +74,7 @@ public class Person {
 									Name: "name",
 									Type: &graph.Type{
 										Name:        "string",
+										RawName:     "String",
 										Kind:        reflect.String,
 										PackagePath: "java.lang",
 									},
@@ -75,7 +82,8 @@ public class Person {
 								{
 									Name: "age",
 									Type: &graph.Type{
-										Name: "int",
+										Name:    "int32",
+										RawName: "int",
 									},
 								},
 							},
@@ -94,6 +102,7 @@ public class Person {
 								{
 									Type: &graph.Type{
 										Name:        "string",
+										RawName:     "String",
 										Kind:        reflect.String,
 										PackagePath: "java.lang",
 									},
@@ -106,7 +115,8 @@ public class Person {
 							Results: []*graph.Parameter{
 								{
 									Type: &graph.Type{
-										Name: "int",
+										Name:    "int32",
+										RawName: "int",
 									},
 								},
 							},
@@ -155,7 +165,8 @@ public interface UserService {
 							Results: []*graph.Parameter{
 								{
 									Type: &graph.Type{
-										Name: "List",
+										Name:    "List",
+										RawName: "List<User>",
 									},
 								},
 							},
@@ -269,7 +280,8 @@ public class CustomList<E> implements Collection<E> {
 							Results: []*graph.Parameter{
 								{
 									Type: &graph.Type{
-										Name: "int",
+										Name:    "int32",
+										RawName: "int",
 									},
 								},
 							},
@@ -288,7 +300,8 @@ public class CustomList<E> implements Collection<E> {
 							Results: []*graph.Parameter{
 								{
 									Type: &graph.Type{
-										Name: "bool",
+										Name:    "bool",
+										RawName: "boolean",
 									},
 								},
 							},
@@ -310,6 +323,32 @@ public class CustomList<E> implements Collection<E> {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Optional fields",
+			source: `package com.example;
+import java.util.Optional;
+public class Account {
+    private Optional<String> nickname;
+    @NotNull
+    private String email;
+    @Nullable
+    private String phone;
+    private int id;
+}`,
+			want: []*graph.Type{
+				{
+					Name: "Account",
+					Kind: reflect.Struct,
+					Fields: []*graph.Field{
+						{Name: "nickname", Type: &graph.Type{Name: "Optional", RawName: "Optional<String>"}, Optional: true},
+						{Name: "email", Type: &graph.Type{Name: "string", RawName: "String"}},
+						{Name: "phone", Type: &graph.Type{Name: "string"}, Optional: true},
+						{Name: "id", Type: &graph.Type{Name: "int32", RawName: "int"}},
+					},
+				},
+			},
+			wantErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -346,6 +385,10 @@ public class CustomList<E> implements Collection<E> {
 										assert.Equal(t, wantField.IsExported, gotField.IsExported, "Field %s.IsExported", wantField.Name)
 										assert.Equal(t, wantField.IsConstant, gotField.IsConstant, "Field %s.IsConstant", wantField.Name)
 										assert.Equal(t, wantField.Type.Name, gotField.Type.Name, "Field %s.Type.Name", wantField.Name)
+										if wantField.Type.RawName != "" {
+											assert.Equal(t, wantField.Type.RawName, gotField.Type.RawName, "Field %s.Type.RawName", wantField.Name)
+										}
+										assert.Equal(t, wantField.Optional, gotField.Optional, "Field %s.Optional", wantField.Name)
 										break
 									}
 								}
@@ -369,6 +412,9 @@ public class CustomList<E> implements Collection<E> {
 												if i < len(gotMethod.Parameters) {
 													assert.Equal(t, wantParam.Name, gotMethod.Parameters[i].Name, "Method %s parameter %d name", wantMethod.Name, i)
 													assert.Equal(t, wantParam.Type.Name, gotMethod.Parameters[i].Type.Name, "Method %s parameter %d type", wantMethod.Name, i)
+													if wantParam.Type.RawName != "" {
+														assert.Equal(t, wantParam.Type.RawName, gotMethod.Parameters[i].Type.RawName, "Method %s parameter %d raw type", wantMethod.Name, i)
+													}
 												}
 											}
 										}
@@ -379,6 +425,9 @@ public class CustomList<E> implements Collection<E> {
 											for i, wantResult := range wantMethod.Results {
 												if i < len(gotMethod.Results) {
 													assert.Equal(t, wantResult.Type.Name, gotMethod.Results[i].Type.Name, "Method %s result %d type", wantMethod.Name, i)
+													if wantResult.Type.RawName != "" {
+														assert.Equal(t, wantResult.Type.RawName, gotMethod.Results[i].Type.RawName, "Method %s result %d raw type", wantMethod.Name, i)
+													}
 												}
 											}
 										}
@@ -428,3 +477,535 @@ func TestInspector_InspectPackage(t *testing.T) {
 	// This test requires actual Java packages on disk, so we'll skip it
 	t.Skip("Skipping package-based tests - requires Java packages on disk")
 }
+
+func TestInspector_Query(t *testing.T) {
+	source := `package com.example;
+
+@Entity
+@Table(name = "users")
+public class User {
+    @Id
+    private Long id;
+
+    @Deprecated
+    public String getName() {
+        return null;
+    }
+}
+`
+	inspector := java.NewInspector(&graph.Config{IncludeUnexported: true, RetainTrees: true})
+	_, err := inspector.InspectSource([]byte(source))
+	if !assert.NoError(t, err) {
+		return
+	}
+	matches, err := inspector.Query("source.java", `(marker_annotation name: (identifier) @name) (annotation name: (identifier) @name)`)
+	if !assert.NoError(t, err) {
+		return
+	}
+	var names []string
+	for _, match := range matches {
+		assert.Equal(t, "name", match.Capture)
+		assert.Equal(t, "identifier", match.Type)
+		assert.Equal(t, match.Text, source[match.StartByte:match.EndByte])
+		names = append(names, match.Text)
+	}
+	assert.Equal(t, []string{"Entity", "Table", "Id", "Deprecated"}, names)
+
+	_, err = inspector.Query("source.java", `(missing_node) @x`)
+	assert.Error(t, err)
+
+	// named sources keep their own retained trees
+	_, err = inspector.InspectNamedSource("Audit.java", []byte("@Audited\npublic class Audit {}\n"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	matches, err = inspector.Query("Audit.java", `(marker_annotation name: (identifier) @name)`)
+	if assert.NoError(t, err) && assert.Len(t, matches, 1) {
+		assert.Equal(t, "Audited", matches[0].Text)
+	}
+	matches, err = inspector.Query("source.java", `(marker_annotation name: (identifier) @name)`)
+	if assert.NoError(t, err) {
+		assert.Len(t, matches, 3)
+	}
+
+	assert.NoError(t, inspector.Close())
+	_, err = inspector.Query("source.java", `(identifier) @id`)
+	assert.ErrorIs(t, err, &graph.ErrNotFound{Kind: "tree"})
+}
+
+func TestInspector_InspectProject_SkippedFiles(t *testing.T) {
+	root := t.TempDir()
+	pkgDir := filepath.Join(root, "src", "main", "java", "com", "example")
+	if !assert.NoError(t, os.MkdirAll(pkgDir, 0755)) {
+		return
+	}
+	files := map[string][]byte{
+		"User.java":  []byte("package com.example;\n\npublic class User {\n    private Long id;\n}\n"),
+		"Large.java": []byte("package com.example;\n\npublic class Large {\n" + strings.Repeat("    private int field;\n", 100) + "}\n"),
+		"Blob.java":  {0xCA, 0xFE, 0xBA, 0xBE, 0x00, 0x00, 0x00, 0x34, 0x00, 0x1D},
+	}
+	for name, content := range files {
+		if !assert.NoError(t, os.WriteFile(filepath.Join(pkgDir, name), content, 0644)) {
+			return
+		}
+	}
+
+	inspector := java.NewInspector(&graph.Config{IncludeUnexported: true, MaxFileSize: 1024})
+	project, err := inspector.InspectProject(root)
+	if !assert.NoError(t, err) || !assert.Len(t, project.Packages, 1) {
+		return
+	}
+	pkg := project.Packages[0]
+	assert.Len(t, pkg.FileSet, 1)
+	assert.Equal(t, "User", pkg.FileSet[0].Types[0].Name)
+
+	skipped := map[string]string{}
+	for _, file := range project.SkippedFiles {
+		skipped[filepath.Base(file.Path)] = file.Reason
+		assert.Equal(t, int64(len(files[filepath.Base(file.Path)])), file.Size)
+	}
+	assert.Equal(t, map[string]string{"Large.java": graph.SkipReasonSize, "Blob.java": graph.SkipReasonBinary}, skipped)
+}
+
+func TestInspector_InspectProject_Descriptors(t *testing.T) {
+	root := t.TempDir()
+	srcDir := filepath.Join(root, "src", "main", "java")
+	pkgDir := filepath.Join(srcDir, "com", "acme", "model")
+	if !assert.NoError(t, os.MkdirAll(pkgDir, 0755)) {
+		return
+	}
+	files := map[string]string{
+		filepath.Join(pkgDir, "package-info.java"): "/**\n * Domain model.\n */\n@ParametersAreNonnullByDefault\npackage com.acme.model;\n\nimport javax.annotation.ParametersAreNonnullByDefault;\n",
+		filepath.Join(pkgDir, "User.java"):         "package com.acme.model;\n\npublic class User {\n    private Long id;\n}\n",
+		filepath.Join(srcDir, "module-info.java"): `module com.acme.model {
+    requires transitive java.sql;
+    requires static lombok;
+    exports com.acme.model;
+    exports com.acme.model.internal to com.acme.app;
+    uses com.acme.spi.Codec;
+    provides com.acme.spi.Codec with com.acme.model.JsonCodec;
+}
+`,
+	}
+	for name, content := range files {
+		if !assert.NoError(t, os.WriteFile(name, []byte(content), 0644)) {
+			return
+		}
+	}
+
+	project, err := java.NewInspector(&graph.Config{IncludeUnexported: true}).InspectProject(root)
+	if !assert.NoError(t, err) {
+		return
+	}
+	var pkg *graph.Package
+	for _, candidate := range project.Packages {
+		if candidate.ImportPath == "com.acme.model" {
+			pkg = candidate
+		}
+	}
+	if !assert.NotNil(t, pkg) {
+		return
+	}
+	assert.Len(t, pkg.FileSet, 1)
+	assert.Equal(t, "User", pkg.FileSet[0].Types[0].Name)
+	if assert.NotNil(t, pkg.Comment) {
+		assert.Equal(t, "Domain model.", pkg.Comment.Text)
+	}
+	assert.Equal(t, []string{"@ParametersAreNonnullByDefault"}, pkg.Annotations)
+	if assert.Len(t, pkg.Assets, 1) {
+		assert.Equal(t, "package-info.java", pkg.Assets[0].Name)
+	}
+
+	if !assert.Len(t, project.Modules, 1) {
+		return
+	}
+	module := project.Modules[0]
+	assert.Equal(t, "com.acme.model", module.Name)
+	assert.Equal(t, []*graph.ModuleRequire{{Module: "java.sql", Transitive: true}, {Module: "lombok", Static: true}}, module.Requires)
+	assert.Equal(t, []*graph.ModuleExport{{Package: "com.acme.model"}, {Package: "com.acme.model.internal", To: []string{"com.acme.app"}}}, module.Exports)
+	assert.Equal(t, []string{"com.acme.spi.Codec"}, module.Uses)
+	assert.Equal(t, []*graph.ModuleProvide{{Service: "com.acme.spi.Codec", With: []string{"com.acme.model.JsonCodec"}}}, module.Provides)
+	assert.True(t, module.ExportsPackage("com.acme.model.internal", "com.acme.app"))
+	assert.False(t, module.ExportsPackage("com.acme.model.internal", "com.acme.web"))
+	assert.Equal(t, module, project.LookupModule("com.acme.model"))
+}
+
+func TestInspector_InspectSource_NestedTypes(t *testing.T) {
+	source := `package com.example;
+
+import java.util.List;
+
+public record Point(int x, int y) implements Comparable<Point> {
+    public int sum() {
+        return x + y;
+    }
+}
+
+public class Registry {
+    private List<String> names;
+
+    public static class Entry {
+        private String key;
+        private int hits;
+    }
+
+    class Cursor {
+        int next() {
+            return 0;
+        }
+    }
+
+    public void start() {
+        Runnable task = new Runnable() {
+            public void run() {
+            }
+        };
+        task.run();
+    }
+}
+`
+	file, err := java.NewInspector(&graph.Config{IncludeUnexported: true}).InspectSource([]byte(source))
+	if !assert.NoError(t, err) {
+		return
+	}
+	types := map[string]*graph.Type{}
+	var names []string
+	for _, aType := range file.Types {
+		types[aType.Name] = aType
+		names = append(names, aType.Name)
+	}
+	assert.Equal(t, []string{"Point", "Registry", "Registry.Entry", "Registry.Cursor"}, names)
+
+	point := types["Point"]
+	var fields []string
+	for _, field := range point.Fields {
+		fields = append(fields, field.Name+" "+field.Type.Name)
+	}
+	assert.Equal(t, []string{"x int32", "y int32"}, fields)
+	if assert.Len(t, point.Methods, 2) {
+		constructor := point.Methods[0]
+		assert.True(t, constructor.IsConstructor)
+		assert.Equal(t, "Point", constructor.Name)
+		assert.Equal(t, "Point(int x, int y)", constructor.Signature)
+		assert.Len(t, constructor.Parameters, 2)
+		assert.Equal(t, "sum", point.Methods[1].Name)
+	}
+
+	entry := types["Registry.Entry"]
+	assert.Equal(t, "Registry", entry.ParentType)
+	if assert.Len(t, entry.Fields, 2) {
+		assert.Equal(t, "key", entry.Fields[0].Name)
+		assert.Equal(t, "hits", entry.Fields[1].Name)
+	}
+
+	cursor := types["Registry.Cursor"]
+	assert.Equal(t, "Registry", cursor.ParentType)
+	if assert.Len(t, cursor.Methods, 1) {
+		assert.Equal(t, "next", cursor.Methods[0].Name)
+	}
+
+	registry := types["Registry"]
+	assert.Len(t, registry.Fields, 1)
+	start := registry.GetMethod("start")
+	if assert.NotNil(t, start) && assert.Len(t, start.LocalTypes, 1) {
+		anonymous := start.LocalTypes[0]
+		assert.Equal(t, "Registry$1", anonymous.Name)
+		assert.Equal(t, "Registry", anonymous.ParentType)
+		assert.Equal(t, []string{"Runnable"}, anonymous.Extends)
+		if assert.Len(t, anonymous.Methods, 1) {
+			assert.Equal(t, "run", anonymous.Methods[0].Name)
+		}
+	}
+}
+
+func TestInspector_InspectSource_Overloads(t *testing.T) {
+	source := `package com.example;
+
+import com.example.model.User;
+import java.util.Map;
+
+public class UserRepository {
+    public UserRepository() {
+    }
+
+    public UserRepository(Map< String ,User > cache) {
+    }
+
+    public void save(User user) {
+    }
+
+    public void save(User user,   boolean flush) {
+    }
+}
+`
+	file, err := java.NewInspector(&graph.Config{IncludeUnexported: true}).InspectSource([]byte(source))
+	if !assert.NoError(t, err) || !assert.Len(t, file.Types, 1) {
+		return
+	}
+	var signatures []string
+	for _, method := range file.Types[0].Methods {
+		signatures = append(signatures, method.Signature)
+	}
+	assert.Equal(t, []string{
+		"UserRepository()",
+		"UserRepository(java.util.Map<String, User> cache)",
+		"void save(com.example.model.User user)",
+		"void save(com.example.model.User user, boolean flush)",
+	}, signatures)
+
+	file.Path = "UserRepository.java"
+	project := &graph.Project{Name: "example", Packages: []*graph.Package{{Name: "com.example", FileSet: []*graph.File{file}}}}
+	documents, err := project.CreateDocuments(context.Background(), "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	var ids []string
+	for _, doc := range documents {
+		if doc.Kind == graph.KindTypeMethod {
+			ids = append(ids, doc.GetID())
+		}
+	}
+	assert.Equal(t, []string{
+		"Method:UserRepository.java:UserRepository.UserRepository():",
+		"Method:UserRepository.java:UserRepository.UserRepository(java.util.Map<String, User> cache):",
+		"Method:UserRepository.java:UserRepository.void save(com.example.model.User user):",
+		"Method:UserRepository.java:UserRepository.void save(com.example.model.User user, boolean flush):",
+	}, ids)
+}
+
+func TestInspector_InspectSource_FieldDefaults(t *testing.T) {
+	source := `package com.example;
+
+public class Settings {
+    private int retries = 3;
+    private String region = "us-east-1";
+    private boolean enabled;
+    private String owner;
+}
+`
+	file, err := java.NewInspector(&graph.Config{IncludeUnexported: true}).InspectSource([]byte(source))
+	if !assert.NoError(t, err) || !assert.Len(t, file.Types, 1) {
+		return
+	}
+	defaults := map[string]string{}
+	var initialized []string
+	for _, field := range file.Types[0].Fields {
+		defaults[field.Name] = field.DefaultValue()
+		if field.DefaultFrom != nil {
+			initialized = append(initialized, source[field.DefaultFrom.Start:field.DefaultFrom.End])
+		}
+	}
+	assert.Equal(t, map[string]string{"retries": "3", "region": `"us-east-1"`, "enabled": "false", "owner": "null"}, defaults)
+	assert.Equal(t, []string{"3", `"us-east-1"`}, initialized)
+}
+
+func TestInspector_InspectSource_TypeArguments(t *testing.T) {
+	source := `package com.example;
+
+import com.example.model.Order;
+import com.example.model.User;
+import java.util.List;
+import java.util.Map;
+import java.util.Optional;
+
+public class Registry {
+    private List<User> users;
+    private Map<String, Order> orders;
+    private Optional<List<User>> owners;
+
+    public Optional<List<User>> find(Map<String, Order> filter) {
+        return owners;
+    }
+}
+`
+	file, err := java.NewInspector(&graph.Config{IncludeUnexported: true}).InspectSource([]byte(source))
+	if !assert.NoError(t, err) || !assert.Len(t, file.Types, 1) {
+		return
+	}
+	type typeArgs struct{ Name, RawName, PackagePath, KeyType, ComponentType string }
+	describe := func(aType *graph.Type) typeArgs {
+		return typeArgs{Name: aType.Name, RawName: aType.RawName, PackagePath: aType.PackagePath, KeyType: aType.KeyType, ComponentType: aType.ComponentType}
+	}
+	users := typeArgs{Name: "List", RawName: "List<User>", PackagePath: "java.util", ComponentType: "com.example.model.User"}
+	orders := typeArgs{Name: "Map", RawName: "Map<String, Order>", PackagePath: "java.util", KeyType: "java.lang.String", ComponentType: "com.example.model.Order"}
+	owners := typeArgs{Name: "Optional", RawName: "Optional<List<User>>", PackagePath: "java.util", ComponentType: "java.util.List<com.example.model.User>"}
+
+	registry := file.Types[0]
+	var fields []typeArgs
+	for _, field := range registry.Fields {
+		fields = append(fields, describe(field.Type))
+	}
+	assert.Equal(t, []typeArgs{users, orders, owners}, fields)
+
+	method := registry.GetMethod("find")
+	if !assert.NotNil(t, method) || !assert.Len(t, method.Parameters, 1) || !assert.Len(t, method.Results, 1) {
+		return
+	}
+	assert.Equal(t, orders, describe(method.Parameters[0].Type))
+	assert.Equal(t, owners, describe(method.Results[0].Type))
+	if assert.Len(t, method.Results[0].Type.TypeParams, 1) {
+		assert.Equal(t, "java.util.List<com.example.model.User>", method.Results[0].Type.TypeParams[0].Name)
+	}
+	assert.Equal(t, "java.util.Optional<List<User>> find(java.util.Map<String, Order> filter)", method.Signature)
+}
+
+func TestInspector_InspectSource_Refs(t *testing.T) {
+	source := `package com.example;
+
+public class UserRepository {
+    private String table;
+
+    public UserRepository(String table) {
+    }
+
+    public void save(User user) {
+    }
+
+    public void save(User user, int... shards) {
+    }
+
+    public static class Entry {
+        private String key;
+    }
+}
+`
+	file, err := java.NewInspector(&graph.Config{IncludeUnexported: true}).InspectSource([]byte(source))
+	if !assert.NoError(t, err) || !assert.Len(t, file.Types, 2) {
+		return
+	}
+	pkg := &graph.Package{Name: "com.example", ImportPath: "com.example", FileSet: []*graph.File{file}}
+	project := &graph.Project{Name: "example", Packages: []*graph.Package{pkg}}
+	repository, entry := file.Types[0], file.Types[1]
+	owner := graph.NewTypeRef(pkg.ImportPath, "UserRepository")
+
+	refs := map[string]interface{}{
+		owner.String():                                        repository,
+		repository.Fields[0].Ref(owner).String():              repository.Fields[0],
+		repository.Methods[0].Ref(owner).String():             repository.Methods[0],
+		repository.Methods[1].Ref(owner).String():             repository.Methods[1],
+		repository.Methods[2].Ref(owner).String():             repository.Methods[2],
+		graph.NewTypeRef(pkg.ImportPath, entry.Name).String(): entry,
+	}
+	assert.Equal(t, "com.example#UserRepository/table", repository.Fields[0].Ref(owner).String())
+	assert.Equal(t, "com.example#UserRepository.UserRepository(String)", repository.Methods[0].Ref(owner).String())
+	assert.Equal(t, "com.example#UserRepository.save(User,int...)", repository.Methods[2].Ref(owner).String())
+	assert.Equal(t, "com.example#UserRepository.Entry", graph.NewTypeRef(pkg.ImportPath, entry.Name).String())
+	for ref, expect := range refs {
+		parsed, err := graph.ParseRef(ref)
+		if !assert.NoError(t, err, ref) {
+			continue
+		}
+		target := project.ByRef(parsed.String())
+		if !assert.NotNil(t, target, ref) {
+			continue
+		}
+		switch expect.(type) {
+		case *graph.Type:
+			assert.Same(t, expect, target.Type, ref)
+		case *graph.Field:
+			assert.Same(t, expect, target.Field, ref)
+		case *graph.Function:
+			assert.Same(t, expect, target.Function, ref)
+		}
+	}
+	param := project.ByRef("com.example#UserRepository.save(User,int...)/shards")
+	if assert.NotNil(t, param) {
+		assert.Equal(t, "shards", param.Parameter.Name)
+	}
+	assert.Nil(t, project.ByRef("com.example#UserRepository.save(long)"))
+}
+
+func TestInspector_InspectSource_References(t *testing.T) {
+	source := `package com.example;
+
+import java.util.ArrayList;
+import java.util.HashMap;
+
+public class Registry {
+    public void load() {
+        ArrayList<String> names = new ArrayList<String>();
+        HashMap<String, Integer> counts = new HashMap<>();
+    }
+}
+`
+	file, err := java.NewInspector(&graph.Config{IncludeUnexported: true}).InspectSource([]byte(source))
+	if !assert.NoError(t, err) || !assert.Len(t, file.Types, 1) || !assert.Len(t, file.Types[0].Methods, 1) {
+		return
+	}
+	references := file.Types[0].Methods[0].References
+	assert.Contains(t, references, "ArrayList")
+	assert.Contains(t, references, "HashMap")
+	for _, reference := range references {
+		assert.NotContains(t, reference, "<", reference)
+	}
+}
+
+func TestFile_Rebase(t *testing.T) {
+	source := "package com.example;\n\npublic class Counter {\n    private int count;\n\n    public void inc() {\n        count++;\n    }\n\n    public int get() {\n        return count;\n    }\n\n    public void reset() {\n        count = 0;\n    }\n}\n"
+	file, err := java.NewInspector(&graph.Config{IncludeUnexported: true}).InspectSource([]byte(source))
+	if !assert.NoError(t, err) || !assert.Len(t, file.Types, 1) {
+		return
+	}
+	methods := map[string]*graph.Function{}
+	for _, method := range file.Types[0].Methods {
+		methods[method.Name] = method
+	}
+	assert.Equal(t, "public int get() {\n        return count;\n    }", source[methods["get"].Location.Start:methods["get"].Location.End])
+
+	// lines inserted above, reformatted with tabs and a method removed
+	edited := "// Copyright\n// Example\npackage com.example;\n\npublic class Counter {\n\tprivate int count;\n\n\tpublic void inc() {\n\t\tcount++;\n\t}\n\n\tpublic int get() {\n\t\treturn count;\n\t}\n}\n"
+	result := file.Rebase([]byte(edited))
+	assert.Equal(t, "public int get() {\n\t\treturn count;\n\t}", edited[methods["get"].Location.Start:methods["get"].Location.End])
+	assert.Equal(t, "{\n\t\treturn count;\n\t}", edited[methods["get"].Body.Start:methods["get"].Body.End])
+	assert.Equal(t, "public void inc() {\n\t\tcount++;\n\t}", edited[methods["inc"].Location.Start:methods["inc"].Location.End])
+	assert.False(t, methods["get"].Location.Stale)
+	assert.True(t, methods["reset"].Location.Stale, "removed methods are not guessed")
+	assert.Greater(t, result.Stale, 0)
+	hash, _ := graph.Hash([]byte(edited))
+	assert.Equal(t, hash, file.Hash)
+
+	// unchanged lines map offsets of the previous source
+	prepended := "// License\n" + edited
+	start := methods["get"].Location.Start
+	result = file.Rebase([]byte(prepended))
+	offset, ok := result.Offset(start)
+	assert.True(t, ok)
+	assert.Equal(t, start+len("// License\n"), offset)
+	assert.Equal(t, offset, methods["get"].Location.Start)
+}
+
+func TestInspector_InspectSource_Coverage(t *testing.T) {
+	source := `package com.example;
+
+public class Account {
+    static { System.loadLibrary("native"); }
+    private int balance, limit;
+
+    public Account() {}
+
+    public int getBalance() {
+        return balance;
+    }
+
+    public enum Status {
+        ACTIVE, CLOSED;
+
+        public boolean isOpen() {
+            return this == ACTIVE;
+        }
+    }
+}
+`
+	file, err := java.NewInspector(&graph.Config{IncludeUnexported: true, ParseCoverage: true}).InspectSource([]byte(source))
+	if !assert.NoError(t, err) || !assert.NotNil(t, file.Coverage) {
+		return
+	}
+	coverage := file.Coverage
+	assert.Equal(t, &graph.CoverageCount{Declared: 2, Modeled: 2}, coverage.Kind(graph.CoverageTypes))
+	assert.Equal(t, &graph.CoverageCount{Declared: 2, Modeled: 2}, coverage.Kind(graph.CoverageConstants))
+	assert.Equal(t, &graph.CoverageCount{Declared: 2, Modeled: 1}, coverage.Kind(graph.CoverageFields), "only the first declarator is modeled")
+	assert.Equal(t, &graph.CoverageCount{Declared: 3, Modeled: 2}, coverage.Kind(graph.CoverageFunctions), "enum methods are not modeled")
+	if assert.Len(t, coverage.Unhandled, 1) {
+		assert.Equal(t, &graph.UnhandledNode{Node: "static_initializer", Owner: "Account", Path: "source.java", Line: 4, Column: 5}, coverage.Unhandled[0])
+	}
+}
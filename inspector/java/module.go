@@ -0,0 +1,151 @@
+package java
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/java"
+	"github.com/viant/linager/inspector/graph"
+	"github.com/viant/linager/inspector/treesitter"
+)
+
+// packageInfo holds package metadata declared by package-info.java
+type packageInfo struct {
+	name        string
+	comment     *graph.LocationNode
+	annotations []string
+}
+
+// parseDescriptor parses a package-info.java or module-info.java file within a parse deadline
+func parseDescriptor(filename string, timeout time.Duration) (*sitter.Node, []byte, error) {
+	src, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read file %s: %w", filename, graph.NotFoundError("file", filename, err))
+	}
+	parser := sitter.NewParser()
+	parser.SetLanguage(java.GetLanguage())
+	tree, err := treesitter.Parse(parser, src, timeout)
+	if err != nil {
+		return nil, nil, &graph.ErrParse{Path: filename, Cause: err}
+	}
+	return tree.RootNode(), src, nil
+}
+
+// inspectPackageInfo extracts the package name, Javadoc and annotations of a package-info.java file
+func inspectPackageInfo(filename string, timeout time.Duration) (*packageInfo, error) {
+	root, src, err := parseDescriptor(filename, timeout)
+	if err != nil {
+		return nil, err
+	}
+	info := &packageInfo{}
+	for j := 0; j < int(root.NamedChildCount()); j++ {
+		node := root.NamedChild(j)
+		if node.Type() != "package_declaration" {
+			continue
+		}
+		info.name = parsePackageDeclaration(node, src)
+		info.comment = leadingJavadoc(node, src)
+		for k := 0; k < int(node.NamedChildCount()); k++ {
+			if child := node.NamedChild(k); child.Type() == "marker_annotation" || child.Type() == "annotation" {
+				info.annotations = append(info.annotations, child.Content(src))
+			}
+		}
+	}
+	return info, nil
+}
+
+// InspectModule parses a module-info.java file, nil when it declares no module
+func (i *Inspector) InspectModule(filename string) (*graph.Module, error) {
+	root, src, err := parseDescriptor(filename, i.config.ParseDeadline())
+	if err != nil {
+		return nil, err
+	}
+	for j := 0; j < int(root.NamedChildCount()); j++ {
+		if node := root.NamedChild(j); node.Type() == "module_declaration" {
+			module := parseModuleDeclaration(node, src)
+			module.Path = filename
+			return module, nil
+		}
+	}
+	return nil, nil
+}
+
+// parseModuleDeclaration extracts a module name and its directives
+func parseModuleDeclaration(node *sitter.Node, src []byte) *graph.Module {
+	module := &graph.Module{Comment: leadingJavadoc(node, src)}
+	for j := 0; j < int(node.ChildCount()); j++ {
+		child := node.Child(j)
+		switch child.Type() {
+		case "open":
+			module.Open = true
+		case "identifier", "scoped_identifier":
+			module.Name = child.Content(src)
+		case "module_body":
+			for k := 0; k < int(child.NamedChildCount()); k++ {
+				parseModuleDirective(module, child.NamedChild(k), src)
+			}
+		}
+	}
+	return module
+}
+
+// parseModuleDirective adds a requires, exports, opens, uses or provides directive to a module
+func parseModuleDirective(module *graph.Module, node *sitter.Node, src []byte) {
+	var names []string
+	var modifiers []string
+	for k := 0; k < int(node.NamedChildCount()); k++ {
+		switch child := node.NamedChild(k); child.Type() {
+		case "identifier", "scoped_identifier":
+			names = append(names, child.Content(src))
+		case "requires_modifier":
+			modifiers = append(modifiers, child.Content(src))
+		}
+	}
+	if len(names) == 0 {
+		return
+	}
+	switch node.Type() {
+	case "requires_module_directive":
+		require := &graph.ModuleRequire{Module: names[0]}
+		for _, modifier := range modifiers {
+			require.Transitive = require.Transitive || modifier == "transitive"
+			require.Static = require.Static || modifier == "static"
+		}
+		module.Requires = append(module.Requires, require)
+	case "exports_module_directive":
+		module.Exports = append(module.Exports, &graph.ModuleExport{Package: names[0], To: targets(names)})
+	case "opens_module_directive":
+		module.Opens = append(module.Opens, &graph.ModuleExport{Package: names[0], To: targets(names)})
+	case "uses_module_directive":
+		module.Uses = append(module.Uses, names[0])
+	case "provides_module_directive":
+		module.Provides = append(module.Provides, &graph.ModuleProvide{Service: names[0], With: targets(names)})
+	}
+}
+
+// leadingJavadoc returns the Javadoc comment preceding a declaration, nil without Javadoc
+func leadingJavadoc(node *sitter.Node, src []byte) *graph.LocationNode {
+	prev := node.PrevNamedSibling()
+	if prev == nil || prev.Type() != "block_comment" {
+		return nil
+	}
+	text := prev.Content(src)
+	if !strings.HasPrefix(text, "/**") {
+		return nil
+	}
+	return &graph.LocationNode{
+		Text:     cleanCommentMarkers(text),
+		Location: graph.Location{Start: int(prev.StartByte()), End: int(prev.EndByte())},
+	}
+}
+
+// targets returns directive names following the first one, e.g. modules of a qualified export
+func targets(names []string) []string {
+	if len(names) < 2 {
+		return nil
+	}
+	return names[1:]
+}
@@ -19,7 +19,7 @@ func (i *Inspector) InspectPackages(rootPath string) ([]*graph.Package, error) {
 	var packages []*graph.Package
 
 	// Walk the directory tree to find all potential package directories
-	err = filepath.Walk(absPath, func(aPath string, fileInfo os.FileInfo, err error) error {
+	err = repository.Walk(absPath, i.config.FollowSymlinks, func(aPath string, fileInfo os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
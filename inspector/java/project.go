@@ -1,6 +1,8 @@
 package java
 
 import (
+	"context"
+
 	"github.com/viant/linager/inspector/graph"
 	"github.com/viant/linager/inspector/repository"
 )
@@ -9,7 +11,7 @@ import (
 func (i *Inspector) InspectProject(location string) (*graph.Project, error) {
 	detector := repository.New()
 	project := &graph.Project{}
-	if info, err := detector.DetectProject(location); err == nil {
+	if info, err := detector.DetectProject(context.Background(), location); err == nil {
 		project.Name = info.Name
 		project.Type = info.Type
 		project.RootPath = info.RootPath
@@ -17,7 +19,7 @@ func (i *Inspector) InspectProject(location string) (*graph.Project, error) {
 			location = info.RootPath
 		}
 	}
-	if info, err := detector.DetectRepository(location); err == nil {
+	if info, err := detector.DetectRepository(context.Background(), location); err == nil {
 		project.RepositoryURL = info.Origin
 
 	}
@@ -26,6 +28,26 @@ func (i *Inspector) InspectProject(location string) (*graph.Project, error) {
 		return nil, err
 	}
 
+	for _, pkg := range project.Packages {
+		for _, asset := range pkg.Assets {
+			if asset.Name != graph.ModuleInfoFile {
+				continue
+			}
+			module, err := i.InspectModule(asset.Path)
+			if err != nil {
+				return nil, err
+			}
+			if module != nil {
+				project.Modules = append(project.Modules, module)
+			}
+		}
+	}
 	project.Init()
+	if i.config != nil && i.config.Classifier != nil {
+		project.Classify(i.config.Classifier)
+	}
+	if i.config != nil && i.config.Redactor != nil {
+		project.Redact(i.config.Redactor)
+	}
 	return project, nil
 }
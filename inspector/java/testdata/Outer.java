@@ -0,0 +1,30 @@
+package com.example.model;
+
+import java.util.List;
+import java.util.Map;
+
+/**
+ * Outer holds nested declarations
+ */
+public class Outer<T extends Comparable<T>> {
+    private List<T> items;
+    public static final int LIMIT = 10;
+
+    /**
+     * Inner is a nested static class
+     */
+    public static class Inner {
+        private Map<String, Integer> counts;
+
+        public int count(String key) {
+            return counts.getOrDefault(key, 0);
+        }
+    }
+
+    public <R> R map(java.util.function.Function<T, R> fn, T value) {
+        return fn.apply(value);
+    }
+
+    public void addAll(T... values) {
+    }
+}
@@ -0,0 +1,9 @@
+package com.example.repo;
+
+import java.util.Optional;
+
+public interface Repository<T, ID> {
+    Optional<T> findById(ID id);
+
+    void save(T entity);
+}
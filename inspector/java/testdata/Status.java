@@ -0,0 +1,9 @@
+package com.example.model;
+
+public enum Status {
+    ACTIVE, INACTIVE;
+
+    public boolean isActive() {
+        return this == ACTIVE;
+    }
+}
@@ -3,6 +3,7 @@ package java
 import (
 	sitter "github.com/smacker/go-tree-sitter"
 	"github.com/viant/linager/inspector/graph"
+	"github.com/viant/linager/inspector/treesitter"
 	"reflect"
 	"strings"
 )
@@ -133,11 +134,39 @@ func extractMethodBody(node *sitter.Node, source []byte) string {
 	return ""
 }
 
+// extractReferences collects methods invoked and types instantiated within a body node
+func extractReferences(node *sitter.Node, source []byte) []string {
+	var refs []string
+	treesitter.Walk(node, func(node *sitter.Node) bool {
+		switch node.Type() {
+		case "method_invocation":
+			if nameNode := node.ChildByFieldName("name"); nameNode != nil {
+				name := nameNode.Content(source)
+				if objectNode := node.ChildByFieldName("object"); objectNode != nil {
+					name = objectNode.Content(source) + "." + name
+				}
+				refs = append(refs, name)
+			}
+		case "object_creation_expression":
+			typeNode := node.ChildByFieldName("type")
+			if typeNode != nil && typeNode.Type() == "generic_type" && typeNode.NamedChildCount() > 0 {
+				typeNode = typeNode.NamedChild(0) // ArrayList<String> -> ArrayList, matched against imports
+			}
+			if typeNode != nil {
+				refs = append(refs, typeNode.Content(source))
+			}
+		}
+		return true
+	})
+	return refs
+}
+
 // parseJavaType converts a Java type node to an info.Type
 func parseJavaType(node *sitter.Node, source []byte, importMap map[string]string) *graph.Type {
 	// Create a go_basic.gox type with location information
 	typeInfo := &graph.Type{
-		Name: node.Content(source),
+		Name:    node.Content(source),
+		RawName: node.Content(source),
 		Location: &graph.Location{
 			Start: int(node.StartByte()),
 			End:   int(node.EndByte()),
@@ -145,44 +174,8 @@ func parseJavaType(node *sitter.Node, source []byte, importMap map[string]string
 	}
 
 	switch node.Type() {
-	case "integral_type":
-		if node.NamedChildCount() == 0 {
-			return typeInfo
-		}
-		switch node.NamedChild(0).Type() {
-		case "int":
-			typeInfo.Name = "int32"
-			typeInfo.Kind = reflect.Int32
-		case "short":
-			typeInfo.Name = "int16"
-			typeInfo.Kind = reflect.Int16
-		case "long":
-			typeInfo.Name = "int64"
-			typeInfo.Kind = reflect.Int64
-		case "char":
-			typeInfo.Name = "rune"
-			typeInfo.Kind = reflect.Int32
-		case "byte":
-			typeInfo.Name = "byte"
-			typeInfo.Kind = reflect.Uint8
-		}
-
-	case "floating_point_type":
-		if node.NamedChildCount() == 0 {
-			return typeInfo
-		}
-		switch node.NamedChild(0).Type() {
-		case "float":
-			typeInfo.Name = "float32"
-			typeInfo.Kind = reflect.Float32
-		case "double":
-			typeInfo.Name = "float64"
-			typeInfo.Kind = reflect.Float64
-		}
-
-	case "boolean_type":
-		typeInfo.Name = "bool"
-		typeInfo.Kind = reflect.Bool
+	case "integral_type", "floating_point_type", "boolean_type":
+		typeInfo.Name, typeInfo.Kind = resolveJavaType(typeInfo.RawName)
 
 	case "void_type":
 		typeInfo.Name = "void"
@@ -230,6 +223,7 @@ func parseJavaType(node *sitter.Node, source []byte, importMap map[string]string
 		if node.NamedChildCount() > 0 {
 			baseTypeNode := node.NamedChild(0)
 			baseTypeName := baseTypeNode.Content(source)
+			typeInfo.Name = baseTypeName
 			typeInfo.Kind = reflect.Ptr
 
 			// Try to find package info for the base type
@@ -237,46 +231,72 @@ func parseJavaType(node *sitter.Node, source []byte, importMap map[string]string
 				typeInfo.PackagePath = packagePath
 			}
 
-			var typeParams []*graph.TypeParam
-
-			// Process type parameters
-			typeArgsNode := node.ChildByFieldName("type_arguments")
-			if typeArgsNode != nil {
-				var paramTypes []string
-				for i := uint32(0); i < typeArgsNode.NamedChildCount(); i++ {
-					paramNode := typeArgsNode.NamedChild(int(i))
-					paramType := parseJavaType(paramNode, source, importMap)
-					if paramType != nil {
-						paramName := paramType.Name
-						// Try to fully qualify parameter type
-						if paramType.PackagePath != "" && !strings.Contains(paramName, ".") {
-							paramName = paramType.PackagePath + "." + paramName
-						}
-						typeParams = append(typeParams, &graph.TypeParam{
-							Name:       paramName,
-							Constraint: "any",
-						})
-						paramTypes = append(paramTypes, paramName)
-					}
+			// Type arguments are kept as type parameters qualified through imports, e.g. com.example.User
+			var typeArgs []string
+			for i := uint32(1); i < node.NamedChildCount(); i++ {
+				typeArgsNode := node.NamedChild(int(i))
+				if typeArgsNode.Type() != "type_arguments" {
+					continue
 				}
-
-				// Add type parameters to name
-				if len(paramTypes) > 0 {
-					typeInfo.Name = baseTypeName + "<" + strings.Join(paramTypes, ", ") + ">"
-				} else {
-					typeInfo.Name = baseTypeName
+				for j := uint32(0); j < typeArgsNode.NamedChildCount(); j++ {
+					paramType := parseJavaType(typeArgsNode.NamedChild(int(j)), source, importMap)
+					typeArg := qualifiedTypeArgument(paramType)
+					typeInfo.TypeParams = append(typeInfo.TypeParams, &graph.TypeParam{
+						Name:       typeArg,
+						Constraint: "any",
+					})
+					typeArgs = append(typeArgs, typeArg)
 				}
-			} else {
-				typeInfo.Name = baseTypeName
 			}
-
-			typeInfo.TypeParams = typeParams
+			setContainerTypes(typeInfo, extractSimpleTypeName(baseTypeName), typeArgs)
 		}
 	}
 
 	return typeInfo
 }
 
+// mapTypes are Java map containers holding KeyType and ComponentType type arguments
+var mapTypes = map[string]bool{
+	"Map": true, "HashMap": true, "LinkedHashMap": true, "TreeMap": true, "SortedMap": true, "NavigableMap": true,
+	"ConcurrentMap": true, "ConcurrentHashMap": true, "EnumMap": true, "WeakHashMap": true, "Hashtable": true,
+}
+
+// collectionTypes are Java containers holding their first type argument as ComponentType
+var collectionTypes = map[string]bool{
+	"Collection": true, "Iterable": true, "Iterator": true, "List": true, "ArrayList": true, "LinkedList": true,
+	"Set": true, "HashSet": true, "LinkedHashSet": true, "TreeSet": true, "SortedSet": true, "NavigableSet": true,
+	"Queue": true, "Deque": true, "ArrayDeque": true, "PriorityQueue": true, "Stack": true, "Vector": true,
+	"Optional": true, "Stream": true, "CompletableFuture": true, "Future": true, "Supplier": true,
+}
+
+// setContainerTypes sets ComponentType (and KeyType of maps) of a parameterized container from its type arguments
+func setContainerTypes(typeInfo *graph.Type, baseTypeName string, typeArgs []string) {
+	switch {
+	case mapTypes[baseTypeName] && len(typeArgs) == 2:
+		typeInfo.KeyType = typeArgs[0]
+		typeInfo.ComponentType = typeArgs[1]
+	case collectionTypes[baseTypeName] && len(typeArgs) == 1:
+		typeInfo.ComponentType = typeArgs[0]
+	}
+}
+
+// qualifiedTypeArgument returns a Java type argument qualified with known packages, including nested type arguments,
+// e.g. java.util.List<com.example.User>
+func qualifiedTypeArgument(aType *graph.Type) string {
+	if len(aType.TypeParams) == 0 {
+		return qualifiedJavaType(aType)
+	}
+	typeName := aType.Name
+	if aType.PackagePath != "" && !strings.Contains(typeName, ".") {
+		typeName = aType.PackagePath + "." + typeName
+	}
+	var args []string
+	for _, param := range aType.TypeParams {
+		args = append(args, param.Name)
+	}
+	return typeName + "<" + strings.Join(args, ", ") + ">"
+}
+
 // extractSimpleTypeName extracts the simple name from a possibly qualified name
 // e.g., "java.util.List" -> "List"
 func extractSimpleTypeName(qualifiedName string) string {
@@ -9,6 +9,16 @@ import (
 // Emitter is responsible for converting graph representation back to JSX source code
 type Emitter struct{}
 
+// Capabilities returns JSX emitter capabilities
+func (e *Emitter) Capabilities() graph.EmitterCapabilities {
+	return graph.EmitterCapabilities{
+		Language:   "javascript",
+		Extensions: []string{".js", ".jsx"},
+		Indent:     "  ",
+		BraceStyle: "same-line",
+	}
+}
+
 // Emit converts a graph.File to JSX source code
 func (e *Emitter) Emit(file *graph.File) ([]byte, error) {
 	// Start with imports
@@ -2,16 +2,19 @@ package jsx
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"reflect"
+	"sort"
 	"strings"
 
 	sitter "github.com/smacker/go-tree-sitter"
 	"github.com/smacker/go-tree-sitter/javascript"
 	"github.com/viant/linager/inspector/graph"
 	"github.com/viant/linager/inspector/repository"
+	"github.com/viant/linager/inspector/treesitter"
 )
 
 // Inspector provides functionality to inspect JSX code and extract type information
@@ -19,6 +22,8 @@ type Inspector struct {
 	config    *graph.Config
 	importMap map[string]string
 	source    []byte
+	trees     *treesitter.Trees  // retained parsed trees, nil unless Config.RetainTrees is set
+	budget    *treesitter.Budget // node budget of the inspected file, see graph.Config.NodeLimit
 }
 
 // NewInspector creates a new JSX Inspector with the provided configuration
@@ -30,44 +35,47 @@ func NewInspector(config *graph.Config) *Inspector {
 			RecursivePackages: false,
 		}
 	}
-	return &Inspector{
+	ret := &Inspector{
 		config:    config,
 		importMap: make(map[string]string),
 	}
+	if config.RetainTrees {
+		ret.trees = treesitter.NewTrees()
+	}
+	return ret
 }
 
-// InspectSource parses JSX source code from a byte slice and extracts types
+// InspectSource parses JSX source code from a byte slice and extracts types, the file is named source.jsx: with
+// graph.Config.RetainTrees each call replaces the previously retained source.jsx tree, see InspectNamedSource
 func (i *Inspector) InspectSource(src []byte) (*graph.File, error) {
-	i.source = src
-
-	parser := sitter.NewParser()
-	parser.SetLanguage(javascript.GetLanguage())
-
-	tree, err := parser.ParseCtx(context.Background(), nil, src)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse source: %w", err)
-	}
-
-	rootNode := tree.RootNode()
-
-	return i.processJSXFile(rootNode, src, "source.jsx")
+	return i.InspectNamedSource("source.jsx", src)
 }
 
 // InspectFile parses a JSX source file and extracts types
 func (i *Inspector) InspectFile(filename string) (*graph.File, error) {
 	src, err := os.ReadFile(filename)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read file %s: %w", filename, err)
+		return nil, fmt.Errorf("failed to read file %s: %w", filename, graph.NotFoundError("file", filename, err))
 	}
+	return i.InspectNamedSource(filename, src)
+}
 
+// InspectNamedSource parses JSX source code of a named file and extracts types, retained trees are keyed by the name
+func (i *Inspector) InspectNamedSource(filename string, src []byte) (*graph.File, error) {
 	i.source = src
 
 	parser := sitter.NewParser()
 	parser.SetLanguage(javascript.GetLanguage())
 
-	tree, err := parser.ParseCtx(context.Background(), nil, src)
+	tree, err := treesitter.Parse(parser, src, i.config.ParseDeadline())
+	if errors.Is(err, context.DeadlineExceeded) {
+		return i.timeoutFile(filename), nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse file %s: %w", filename, err)
+		return nil, &graph.ErrParse{Path: filename, Cause: err}
+	}
+	if i.trees != nil {
+		i.trees.Retain(filename, tree, src, javascript.GetLanguage())
 	}
 
 	rootNode := tree.RootNode()
@@ -75,6 +83,22 @@ func (i *Inspector) InspectFile(filename string) (*graph.File, error) {
 	return i.processJSXFile(rootNode, src, filename)
 }
 
+// Query runs a tree-sitter query over the retained tree of a file (see graph.Config.RetainTrees)
+func (i *Inspector) Query(file string, query string) ([]treesitter.QueryMatch, error) {
+	if i.trees == nil {
+		return nil, &graph.ErrNotFound{Kind: "tree", Name: file}
+	}
+	return i.trees.Query(file, query)
+}
+
+// Close releases retained parsed trees
+func (i *Inspector) Close() error {
+	if i.trees == nil {
+		return nil
+	}
+	return i.trees.Close()
+}
+
 // InspectPackage inspects a JSX package directory and extracts all types
 func (i *Inspector) InspectPackage(packagePath string) (*graph.Package, error) {
 	// Get the absolute path of the package
@@ -91,7 +115,7 @@ func (i *Inspector) InspectPackage(packagePath string) (*graph.Package, error) {
 	}
 
 	// Walk through the package directory
-	err = filepath.Walk(absPath, func(path string, info os.FileInfo, err error) error {
+	err = repository.Walk(absPath, i.config.FollowSymlinks, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -101,9 +125,10 @@ func (i *Inspector) InspectPackage(packagePath string) (*graph.Package, error) {
 			return nil
 		}
 
-		// Process only .jsx and .tsx files
-		ext := filepath.Ext(path)
-		if ext != ".jsx" && ext != ".tsx" {
+		// Process JavaScript/TypeScript modules, plain .js/.ts files include barrel (index) files
+		switch filepath.Ext(path) {
+		case ".js", ".jsx", ".ts", ".tsx":
+		default:
 			return nil
 		}
 
@@ -111,6 +136,15 @@ func (i *Inspector) InspectPackage(packagePath string) (*graph.Package, error) {
 		if i.config.SkipTests && strings.Contains(filepath.Base(path), ".test.") {
 			return nil
 		}
+		// Skip oversized and binary files
+		skipped, err := i.config.CheckFile(path)
+		if err != nil {
+			return fmt.Errorf("error processing %s: %w", path, err)
+		}
+		if skipped != nil {
+			pkg.Skipped = append(pkg.Skipped, skipped)
+			return nil
+		}
 
 		file, err := i.InspectFile(path)
 		if err != nil {
@@ -127,7 +161,7 @@ func (i *Inspector) InspectPackage(packagePath string) (*graph.Package, error) {
 		return nil, fmt.Errorf("error walking package directory: %w", err)
 	}
 
-	if len(pkg.FileSet) == 0 {
+	if len(pkg.FileSet) == 0 && len(pkg.Skipped) == 0 {
 		return nil, fmt.Errorf("no JSX files found in package: %s", packagePath)
 	}
 
@@ -136,31 +170,38 @@ func (i *Inspector) InspectPackage(packagePath string) (*graph.Package, error) {
 
 // processJSXFile extracts components, imports, and other elements from a JSX file
 func (i *Inspector) processJSXFile(rootNode *sitter.Node, src []byte, filename string) (*graph.File, error) {
-	aFile := &graph.File{
-		Path:       filename,
-		ImportPath: filepath.Dir(filename),
-		Package:    filepath.Base(filepath.Dir(filename)),
-		Types:      []*graph.Type{},
-		Constants:  []*graph.Constant{},
-		Variables:  []*graph.Variable{},
-		Functions:  []*graph.Function{},
-		Imports:    []graph.Import{},
-	}
+	aFile := newFile(filename)
+	i.budget = treesitter.NewBudget(i.config.NodeLimit())
+	defer func() {
+		if warning := i.budget.Warning(filename); warning != nil {
+			aFile.Warnings = append(aFile.Warnings, warning)
+			i.config.Diagnostics.AddParseWarning(warning)
+		}
+	}()
 
 	// Process imports
 	importNodes := findImportNodes(rootNode)
 	for _, importNode := range importNodes {
 		imports := parseImportDeclarations(importNode, src)
-		for name, path := range imports {
-			aFile.Imports = append(aFile.Imports, graph.Import{
-				Name: name,
-				Path: path,
-			})
+		names := make([]string, 0, len(imports))
+		for name := range imports {
+			names = append(names, name)
+		}
+		sort.Strings(names) // keep import order deterministic
+		for _, name := range names {
+			aFile.Imports = append(aFile.Imports, imports[name])
+		}
+	}
+
+	// Process exports, including re-exports of barrel files
+	for j := uint32(0); j < rootNode.NamedChildCount(); j++ {
+		if childNode := rootNode.NamedChild(int(j)); childNode.Type() == "export_statement" {
+			aFile.Exports = append(aFile.Exports, parseExportStatement(childNode, src)...)
 		}
 	}
 
 	// Process components (function and class declarations)
-	componentTypes, err := i.processJSXComponents(rootNode, src)
+	componentTypes, err := i.processJSXComponents(rootNode, src, filename)
 	if err != nil {
 		return nil, err
 	}
@@ -183,6 +224,36 @@ func (i *Inspector) processJSXFile(rootNode *sitter.Node, src []byte, filename s
 	return aFile, nil
 }
 
+// timeoutFile creates an empty file of a source path whose parsing exceeded the deadline
+func (i *Inspector) timeoutFile(filename string) *graph.File {
+	warning := treesitter.TimeoutWarning(filename, i.config.ParseDeadline())
+	i.config.Diagnostics.AddParseWarning(warning)
+	return newFile(filename, warning)
+}
+
+// debugf collects a debug diagnostic located at a node, see graph.Config.Diagnostics
+func (i *Inspector) debugf(filename string, node *sitter.Node, code, format string, args ...interface{}) {
+	if i.config.Diagnostics.Enabled(graph.SeverityDebug) {
+		point := node.StartPoint()
+		i.config.Diagnostics.Debugf(code, filename, int(point.Row)+1, int(point.Column)+1, format, args...)
+	}
+}
+
+// newFile creates an empty file of a source path with optional parse warnings
+func newFile(filename string, warnings ...*graph.ParseWarning) *graph.File {
+	return &graph.File{
+		Path:       filename,
+		ImportPath: filepath.Dir(filename),
+		Package:    filepath.Base(filepath.Dir(filename)),
+		Types:      []*graph.Type{},
+		Constants:  []*graph.Constant{},
+		Variables:  []*graph.Variable{},
+		Functions:  []*graph.Function{},
+		Imports:    []graph.Import{},
+		Warnings:   warnings,
+	}
+}
+
 // findImportNodes finds all import declaration nodes in the AST
 func findImportNodes(rootNode *sitter.Node) []*sitter.Node {
 	var importNodes []*sitter.Node
@@ -197,9 +268,9 @@ func findImportNodes(rootNode *sitter.Node) []*sitter.Node {
 	return importNodes
 }
 
-// parseImportDeclarations extracts import information from an import node
-func parseImportDeclarations(importNode *sitter.Node, src []byte) map[string]string {
-	imports := make(map[string]string)
+// parseImportDeclarations extracts imports keyed by local name from an import node
+func parseImportDeclarations(importNode *sitter.Node, src []byte) map[string]graph.Import {
+	imports := make(map[string]graph.Import)
 
 	// Extract the import path (string literal)
 	var importPath string
@@ -216,33 +287,46 @@ func parseImportDeclarations(importNode *sitter.Node, src []byte) map[string]str
 	if importPath == "" {
 		return imports
 	}
+	add := func(local, imported string) {
+		imports[local] = graph.Import{Name: local, Path: importPath, Imported: imported}
+	}
 
 	// Extract the import name(s)
-	var defaultImport string
 	for j := uint32(0); j < importNode.NamedChildCount(); j++ {
 		child := importNode.NamedChild(int(j))
 		if child.Type() == "identifier" {
-			defaultImport = child.Content(src)
-			imports[defaultImport] = importPath
+			add(child.Content(src), graph.ExportDefault)
 			break
 		} else if child.Type() == "import_clause" {
 			// Handle named imports
 			for k := uint32(0); k < child.NamedChildCount(); k++ {
 				namedImport := child.NamedChild(int(k))
-				if namedImport.Type() == "identifier" {
-					imports[namedImport.Content(src)] = importPath
-				} else if namedImport.Type() == "named_imports" {
-					// Handle { Component, useState } style imports
+				switch namedImport.Type() {
+				case "identifier":
+					add(namedImport.Content(src), graph.ExportDefault)
+				case "namespace_import":
+					// Handle * as utils style imports
+					for l := uint32(0); l < namedImport.NamedChildCount(); l++ {
+						if name := namedImport.NamedChild(int(l)); name.Type() == "identifier" {
+							add(name.Content(src), graph.ExportAll)
+						}
+					}
+				case "named_imports":
+					// Handle { Component, useState as useLocalState } style imports
 					for l := uint32(0); l < namedImport.NamedChildCount(); l++ {
 						specifier := namedImport.NamedChild(int(l))
-						if specifier.Type() == "import_specifier" {
-							for m := uint32(0); m < specifier.NamedChildCount(); m++ {
-								name := specifier.NamedChild(int(m))
-								if name.Type() == "identifier" {
-									imports[name.Content(src)] = importPath
-								}
-							}
+						if specifier.Type() != "import_specifier" {
+							continue
+						}
+						nameNode := specifier.ChildByFieldName("name")
+						if nameNode == nil {
+							continue
 						}
+						local := nameNode.Content(src)
+						if alias := specifier.ChildByFieldName("alias"); alias != nil {
+							local = alias.Content(src)
+						}
+						add(local, nameNode.Content(src))
 					}
 				}
 			}
@@ -252,8 +336,84 @@ func parseImportDeclarations(importNode *sitter.Node, src []byte) map[string]str
 	return imports
 }
 
+// parseExportStatement extracts exported names from an export statement, e.g. export { Button } from './Button',
+// export * from './forms', export default Page or export function helper() {}
+func parseExportStatement(node *sitter.Node, src []byte) []*graph.Export {
+	var from string
+	if source := node.ChildByFieldName("source"); source != nil {
+		from = strings.Trim(source.Content(src), "'\"`")
+	}
+	isDefault := false
+	var exports []*graph.Export
+	for j := 0; j < int(node.ChildCount()); j++ {
+		child := node.Child(j)
+		switch child.Type() {
+		case "default":
+			isDefault = true
+		case "*":
+			exports = append(exports, &graph.Export{Name: graph.ExportAll, Local: graph.ExportAll, From: from})
+		case "namespace_export":
+			for k := 0; k < int(child.NamedChildCount()); k++ {
+				if name := child.NamedChild(k); name.Type() == "identifier" || name.Type() == "string" {
+					exports = append(exports, &graph.Export{Name: strings.Trim(name.Content(src), "'\""), Local: graph.ExportAll, From: from})
+				}
+			}
+		case "export_clause":
+			for k := 0; k < int(child.NamedChildCount()); k++ {
+				specifier := child.NamedChild(k)
+				nameNode := specifier.ChildByFieldName("name")
+				if specifier.Type() != "export_specifier" || nameNode == nil {
+					continue
+				}
+				export := &graph.Export{Name: nameNode.Content(src), Local: nameNode.Content(src), From: from}
+				if alias := specifier.ChildByFieldName("alias"); alias != nil {
+					export.Name = alias.Content(src)
+				}
+				exports = append(exports, export)
+			}
+		}
+	}
+	if declaration := node.ChildByFieldName("declaration"); declaration != nil {
+		for _, name := range declaredNames(declaration, src) {
+			export := &graph.Export{Name: name, Local: name}
+			if isDefault {
+				export.Name = graph.ExportDefault
+			}
+			exports = append(exports, export)
+		}
+	} else if value := node.ChildByFieldName("value"); value != nil && isDefault {
+		local := graph.ExportDefault // anonymous default export, e.g. export default () => null
+		if value.Type() == "identifier" {
+			local = value.Content(src)
+		}
+		exports = append(exports, &graph.Export{Name: graph.ExportDefault, Local: local})
+	}
+	return exports
+}
+
+// declaredNames returns names declared by a function, class or variable declaration
+func declaredNames(declaration *sitter.Node, src []byte) []string {
+	if name := declaration.ChildByFieldName("name"); name != nil {
+		return []string{name.Content(src)}
+	}
+	var names []string
+	for k := 0; k < int(declaration.NamedChildCount()); k++ {
+		declarator := declaration.NamedChild(k)
+		if declarator.Type() != "variable_declarator" {
+			continue
+		}
+		if name := declarator.ChildByFieldName("name"); name != nil && name.Type() == "identifier" {
+			names = append(names, name.Content(src))
+		}
+	}
+	if len(names) == 0 {
+		names = append(names, graph.ExportDefault) // anonymous default declaration, e.g. export default function () {}
+	}
+	return names
+}
+
 // processJSXComponents extracts component information from JSX code
-func (i *Inspector) processJSXComponents(rootNode *sitter.Node, src []byte) ([]*graph.Type, error) {
+func (i *Inspector) processJSXComponents(rootNode *sitter.Node, src []byte, filename string) ([]*graph.Type, error) {
 	var components []*graph.Type
 
 	// Find function and class declarations
@@ -262,12 +422,12 @@ func (i *Inspector) processJSXComponents(rootNode *sitter.Node, src []byte) ([]*
 
 		// Function components
 		if childNode.Type() == "function_declaration" {
-			component := processFunctionComponent(childNode, src)
+			component := i.processFunctionComponent(childNode, src, filename)
 			if component != nil {
 				components = append(components, component)
 			}
 		} else if childNode.Type() == "class_declaration" {
-			component := processClassComponent(childNode, src)
+			component := i.processClassComponent(childNode, src, filename)
 			if component != nil {
 				components = append(components, component)
 			}
@@ -284,7 +444,7 @@ func (i *Inspector) processJSXComponents(rootNode *sitter.Node, src []byte) ([]*
 }
 
 // processFunctionComponent extracts information from a function component
-func processFunctionComponent(node *sitter.Node, src []byte) *graph.Type {
+func (i *Inspector) processFunctionComponent(node *sitter.Node, src []byte, filename string) *graph.Type {
 	// Get the function name
 	nameNode := node.ChildByFieldName("name")
 	if nameNode == nil {
@@ -293,8 +453,7 @@ func processFunctionComponent(node *sitter.Node, src []byte) *graph.Type {
 
 	name := nameNode.Content(src)
 
-	// Debug print the source to see what we're working with
-	fmt.Printf("Function component source: %s\n", string(src[node.StartByte():node.EndByte()]))
+	i.debugf(filename, node, "jsx.component", "function component source: %s", node.Content(src))
 
 	// Create a new Type for the component
 	component := &graph.Type{
@@ -333,10 +492,9 @@ func processFunctionComponent(node *sitter.Node, src []byte) *graph.Type {
 	// Extract props from parameters
 	paramsNode := node.ChildByFieldName("parameters")
 	if paramsNode != nil {
-		fmt.Printf("Parameters node type: %s\n", paramsNode.Type())
 		for k := uint32(0); k < paramsNode.NamedChildCount(); k++ {
 			paramNode := paramsNode.NamedChild(int(k))
-			fmt.Printf("Parameter node type: %s, content: %s\n", paramNode.Type(), paramNode.Content(src))
+			i.debugf(filename, paramNode, "jsx.prop", "%s parameter: %s", paramNode.Type(), paramNode.Content(src))
 			if paramNode.Type() == "identifier" {
 				propName := paramNode.Content(src)
 				component.Fields = append(component.Fields, &graph.Field{
@@ -346,10 +504,9 @@ func processFunctionComponent(node *sitter.Node, src []byte) *graph.Type {
 				})
 			} else if paramNode.Type() == "object_pattern" {
 				// Destructured props like { name, age }
-				fmt.Printf("Found object_pattern with %d children\n", paramNode.NamedChildCount())
 				for l := uint32(0); l < paramNode.NamedChildCount(); l++ {
 					propNode := paramNode.NamedChild(int(l))
-					fmt.Printf("Prop node type: %s, content: %s\n", propNode.Type(), propNode.Content(src))
+					i.debugf(filename, propNode, "jsx.prop", "%s destructured prop: %s", propNode.Type(), propNode.Content(src))
 					if propNode.Type() == "shorthand_property_identifier" || propNode.Type() == "identifier" {
 						propName := propNode.Content(src)
 						component.Fields = append(component.Fields, &graph.Field{
@@ -370,7 +527,7 @@ func processFunctionComponent(node *sitter.Node, src []byte) *graph.Type {
 }
 
 // processClassComponent extracts information from a class component
-func processClassComponent(node *sitter.Node, src []byte) *graph.Type {
+func (i *Inspector) processClassComponent(node *sitter.Node, src []byte, filename string) *graph.Type {
 	// Get the class name
 	nameNode := node.ChildByFieldName("name")
 	if nameNode == nil {
@@ -393,8 +550,7 @@ func processClassComponent(node *sitter.Node, src []byte) *graph.Type {
 		},
 	}
 
-	// Debug print the source to see what we're working with
-	fmt.Printf("Class component source: %s\n", string(src[node.StartByte():node.EndByte()]))
+	i.debugf(filename, node, "jsx.component", "class component source: %s", node.Content(src))
 
 	// Check if this is the Counter component
 	if name == "Counter" {
@@ -440,14 +596,13 @@ func processClassComponent(node *sitter.Node, src []byte) *graph.Type {
 					fieldName = fieldNameNode.Content(src)
 				}
 
-				// Debug print to see the field name and node type
-				fmt.Printf("Found public field: %s\n", fieldName)
-
 				// Check if this is an arrow function field (like increment = () => {})
 				valueNode := memberNode.ChildByFieldName("value")
+				valueType := ""
 				if valueNode != nil {
-					fmt.Printf("Value node type: %s\n", valueNode.Type())
+					valueType = valueNode.Type()
 				}
+				i.debugf(filename, memberNode, "jsx.field", "public field %s value: %s", fieldName, valueType)
 
 				if fieldName != "" && valueNode != nil && valueNode.Type() == "arrow_function" {
 					// Treat it as a method
@@ -460,9 +615,7 @@ func processClassComponent(node *sitter.Node, src []byte) *graph.Type {
 						},
 					}
 					component.Methods = append(component.Methods, method)
-
-					// Debug print to see if we're detecting the increment method
-					fmt.Printf("Found arrow function field: %s\n", fieldName)
+					i.debugf(filename, memberNode, "jsx.method", "arrow function field %s treated as method", fieldName)
 				} else if fieldName != "" {
 					// Regular field
 					if component.Fields == nil {
@@ -628,7 +781,7 @@ func (i *Inspector) processJSXFunctions(rootNode *sitter.Node, src []byte) ([]*g
 				name := nameNode.Content(src)
 
 				// Skip if this is a component (already processed)
-				if isComponent(childNode, src) {
+				if isComponent(childNode, src, i.budget) {
 					continue
 				}
 
@@ -657,7 +810,7 @@ func (i *Inspector) processJSXFunctions(rootNode *sitter.Node, src []byte) ([]*g
 						name := nameNode.Content(src)
 
 						// Skip if this is a component (already processed)
-						if isArrowFunctionComponent(declaratorNode, src) {
+						if isArrowFunctionComponent(declaratorNode, src, i.budget) {
 							continue
 						}
 
@@ -681,7 +834,7 @@ func (i *Inspector) processJSXFunctions(rootNode *sitter.Node, src []byte) ([]*g
 }
 
 // isComponent checks if a function declaration is a React component
-func isComponent(node *sitter.Node, src []byte) bool {
+func isComponent(node *sitter.Node, src []byte, budget *treesitter.Budget) bool {
 	// Check if the function returns JSX
 	bodyNode := node.ChildByFieldName("body")
 	if bodyNode == nil {
@@ -689,11 +842,11 @@ func isComponent(node *sitter.Node, src []byte) bool {
 	}
 
 	// Look for return statements with JSX
-	return containsJSX(bodyNode, src)
+	return containsJSX(bodyNode, budget)
 }
 
 // isArrowFunctionComponent checks if an arrow function is a React component
-func isArrowFunctionComponent(node *sitter.Node, src []byte) bool {
+func isArrowFunctionComponent(node *sitter.Node, src []byte, budget *treesitter.Budget) bool {
 	valueNode := node.ChildByFieldName("value")
 	if valueNode == nil || valueNode.Type() != "arrow_function" {
 		return false
@@ -705,30 +858,21 @@ func isArrowFunctionComponent(node *sitter.Node, src []byte) bool {
 	}
 
 	// Look for JSX in the body
-	return containsJSX(bodyNode, src)
+	return containsJSX(bodyNode, budget)
 }
 
-// containsJSX checks if a node contains JSX elements
-func containsJSX(node *sitter.Node, src []byte) bool {
-	// Traverse the AST to find JSX elements or self-closing JSX elements
-	if node.Type() == "jsx_element" || node.Type() == "jsx_self_closing_element" {
-		return true
-	}
-	// Recursively check child nodes
-	for i := uint32(0); i < node.NamedChildCount(); i++ {
-		child := node.NamedChild(int(i))
-		if containsJSX(child, src) {
-			return true
-		}
-	}
-	return false
+// containsJSX checks if a node contains JSX elements, nodes beyond the budget are not checked
+func containsJSX(node *sitter.Node, budget *treesitter.Budget) bool {
+	return budget.Find(node, func(node *sitter.Node) bool {
+		return node.Type() == "jsx_element" || node.Type() == "jsx_self_closing_element"
+	}) != nil
 }
 
 // InspectProject inspects a JavaScript/JSX project directory and extracts all type information
 func (i *Inspector) InspectProject(location string) (*graph.Project, error) {
 	detector := repository.New()
 	project := &graph.Project{}
-	if info, err := detector.DetectProject(location); err == nil {
+	if info, err := detector.DetectProject(context.Background(), location); err == nil {
 		project.Name = info.Name
 		project.Type = info.Type
 		project.RootPath = info.RootPath
@@ -736,13 +880,13 @@ func (i *Inspector) InspectProject(location string) (*graph.Project, error) {
 			location = info.RootPath
 		}
 	}
-	if info, err := detector.DetectRepository(location); err == nil {
+	if info, err := detector.DetectRepository(context.Background(), location); err == nil {
 		project.RepositoryURL = info.Origin
 	}
 
 	// Walk through the project directory
 	project.Packages = []*graph.Package{}
-	err := filepath.Walk(location, func(path string, info os.FileInfo, err error) error {
+	err := repository.Walk(location, i.config.FollowSymlinks, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -804,5 +948,12 @@ func (i *Inspector) InspectProject(location string) (*graph.Project, error) {
 	}
 
 	project.Init()
+	project.ResolveImports()
+	if i.config != nil && i.config.Classifier != nil {
+		project.Classify(i.config.Classifier)
+	}
+	if i.config != nil && i.config.Redactor != nil {
+		project.Redact(i.config.Redactor)
+	}
 	return project, nil
 }
@@ -6,8 +6,13 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/viant/linager/inspector/graph"
 	"github.com/viant/linager/inspector/jsx"
+	"io"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 // stripLocations creates a deep copy of the types with all Location fields set to nil
@@ -309,3 +314,135 @@ export default Welcome;`,
 		})
 	}
 }
+
+// TestInspector_InspectProject_Barrels tests imports resolved through barrel file re-exports
+func TestInspector_InspectProject_Barrels(t *testing.T) {
+	files := map[string]string{
+		"components/Button/Button.jsx": "export function Button({ label }) {\n  return <button>{label}</button>;\n}\n",
+		"components/Button/index.js":   "export { Button } from './Button';\n",
+		"components/Panel.jsx":         "export default function Panel({ children }) {\n  return <div>{children}</div>;\n}\n",
+		"components/index.js":          "export * from './Button';\nexport { default as Panel } from './Panel';\nexport * from './cycle';\n",
+		"components/cycle.js":          "export * from './index';\n",
+		"App.jsx": `import React from 'react';
+import { Button, Panel as Card, Missing } from './components';
+
+export default function App() {
+  return <Card><Button label="ok" /></Card>;
+}
+`,
+	}
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if !assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755)) || !assert.NoError(t, os.WriteFile(path, []byte(content), 0644)) {
+			return
+		}
+	}
+	project, err := jsx.NewInspector(nil).InspectProject(root)
+	if !assert.NoError(t, err) {
+		return
+	}
+	origins := map[string]*graph.Origin{}
+	var barrel *graph.File
+	for _, pkg := range project.Packages {
+		for _, file := range pkg.FileSet {
+			switch filepath.Base(file.Path) {
+			case "App.jsx":
+				for _, anImport := range file.Imports {
+					origins[anImport.Name] = anImport.Origin
+				}
+			case "index.js":
+				if filepath.Base(filepath.Dir(file.Path)) == "components" {
+					barrel = file
+				}
+			}
+		}
+	}
+	if !assert.NotNil(t, barrel) || !assert.Contains(t, origins, "Button") {
+		return
+	}
+	assert.Equal(t, []*graph.Export{
+		{Name: graph.ExportAll, Local: graph.ExportAll, From: "./Button"},
+		{Name: "Panel", Local: graph.ExportDefault, From: "./Panel"},
+		{Name: graph.ExportAll, Local: graph.ExportAll, From: "./cycle"},
+	}, barrel.Exports)
+
+	if assert.NotNil(t, origins["Button"]) {
+		assert.True(t, strings.HasSuffix(filepath.ToSlash(origins["Button"].File), "components/Button/Button.jsx"), origins["Button"].File)
+		assert.Equal(t, "Button", origins["Button"].Name)
+	}
+	if assert.NotNil(t, origins["Card"]) {
+		assert.True(t, strings.HasSuffix(filepath.ToSlash(origins["Card"].File), "components/Panel.jsx"), origins["Card"].File)
+		assert.Equal(t, "Panel", origins["Card"].Name)
+	}
+	assert.Nil(t, origins["Missing"])
+	assert.Nil(t, origins["React"])
+}
+
+// TestInspector_InspectSource_NodeLimit tests a deeply nested expression exhausting the node budget is inspected
+// partially with a warning instead of failing
+func TestInspector_InspectSource_NodeLimit(t *testing.T) {
+	depth := 20000
+	source := "function Deep() {\n  return " + strings.Repeat("(", depth) + "<div />" + strings.Repeat(")", depth) + ";\n}\n\nfunction helper() {\n  return 1;\n}\n"
+	inspector := jsx.NewInspector(&graph.Config{IncludeUnexported: true, MaxNodes: 5000})
+	started := time.Now()
+	file, err := inspector.InspectSource([]byte(source))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Less(t, time.Since(started), 5*time.Second)
+	if assert.Len(t, file.Warnings, 1) {
+		assert.Equal(t, graph.WarnReasonNodeLimit, file.Warnings[0].Reason)
+	}
+
+	file, err = jsx.NewInspector(&graph.Config{IncludeUnexported: true}).InspectSource([]byte(source))
+	if assert.NoError(t, err) {
+		assert.Empty(t, file.Warnings)
+	}
+}
+
+func TestInspector_Diagnostics(t *testing.T) {
+	diagnostics := graph.NewDiagnostics(graph.SeverityDebug)
+	logged := 0
+	diagnostics.Logger = graph.DiagnosticLoggerFunc(func(*graph.Diagnostic) { logged++ })
+	inspector := jsx.NewInspector(&graph.Config{IncludeUnexported: true, Diagnostics: diagnostics})
+	reader, writer, err := os.Pipe()
+	if !assert.NoError(t, err) {
+		return
+	}
+	stdout := os.Stdout
+	os.Stdout = writer
+	for _, name := range []string{"Counter.jsx", "Profile.jsx"} {
+		_, err = inspector.InspectFile(filepath.Join("testdata", name))
+		assert.NoError(t, err)
+	}
+	_, err = inspector.InspectSource([]byte("function Button({ text }) {\n  return <button>{text}</button>;\n}\n\nclass Panel extends React.Component {\n  render() {\n    return null;\n  }\n}\n"))
+	assert.NoError(t, err)
+	os.Stdout = stdout
+	writer.Close()
+	printed, _ := io.ReadAll(reader)
+	assert.Empty(t, string(printed))
+
+	var records []string
+	for _, record := range diagnostics.Records() {
+		if record.File == "source.jsx" {
+			records = append(records, fmt.Sprintf("%d:%d %s [%s]", record.Line, record.Column, record.Severity, record.Code))
+		}
+	}
+	assert.Equal(t, []string{
+		"1:1 debug [jsx.component]",
+		"1:17 debug [jsx.prop]",
+		"1:19 debug [jsx.prop]",
+		"5:1 debug [jsx.component]",
+	}, records)
+	assert.Equal(t, len(diagnostics.Records()), logged)
+
+	// warnings only by default
+	diagnostics = graph.NewDiagnostics(graph.SeverityWarning)
+	_, err = jsx.NewInspector(&graph.Config{IncludeUnexported: true, MaxNodes: 50, Diagnostics: diagnostics}).InspectSource([]byte("function Deep() {\n  return " + strings.Repeat("(", 200) + "<div />" + strings.Repeat(")", 200) + ";\n}\n"))
+	assert.NoError(t, err)
+	if records := diagnostics.Records(); assert.Len(t, records, 1) {
+		assert.Equal(t, graph.SeverityWarning, records[0].Severity)
+		assert.Equal(t, "parse.node-limit", records[0].Code)
+	}
+}
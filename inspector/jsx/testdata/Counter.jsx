@@ -0,0 +1,15 @@
+import React, { useState, useEffect } from 'react';
+
+export function Counter({ initial }) {
+  const [count, setCount] = useState(initial);
+  useEffect(() => {
+    document.title = `Count: ${count}`;
+  }, [count]);
+  return <button onClick={() => setCount(count + 1)}>{count}</button>;
+}
+
+export const useToggle = (value = false) => {
+  const [on, setOn] = useState(value);
+  const toggle = () => setOn(!on);
+  return [on, toggle];
+};
@@ -0,0 +1,12 @@
+import React from 'react';
+
+export default class Profile extends React.Component {
+  constructor(props) {
+    super(props);
+    this.state = { editing: false };
+  }
+
+  render() {
+    return <div className="profile">{this.props.name}</div>;
+  }
+}
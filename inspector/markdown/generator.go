@@ -0,0 +1,536 @@
+package markdown
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/viant/afs"
+	"github.com/viant/afs/file"
+	"github.com/viant/afs/url"
+	"github.com/viant/linager/inspector/graph"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultIndexName = "index.md"
+	topLimit         = 3
+)
+
+// Generator renders a Markdown architecture summary of a project: one document per package and an index page
+type Generator struct {
+	fs           afs.Service
+	dependencies map[string][]string
+	calls        map[string][]string
+	indexName    string
+}
+
+// packageInfo holds a package with its resolved import path and dependencies
+type packageInfo struct {
+	pkg      *graph.Package
+	path     string   // import path, e.g. myapp/stack
+	doc      string   // document name
+	outbound []string // project packages imported by this package
+	inbound  []string // project packages importing this package
+	external []string // imports outside the project
+}
+
+// typeInfo holds a type merged across package files
+type typeInfo struct {
+	typ    *graph.Type
+	fields []*graph.Field
+}
+
+// ranked holds a function label with a metric value
+type ranked struct {
+	label string
+	value int
+}
+
+// New creates a Markdown architecture summary generator
+func New(fs afs.Service, options ...Option) *Generator {
+	ret := &Generator{fs: fs, indexName: defaultIndexName}
+	for _, option := range options {
+		option(ret)
+	}
+	if ret.fs == nil {
+		ret.fs = afs.New()
+	}
+	return ret
+}
+
+// Generate renders project documents and writes them to destURL
+func (g *Generator) Generate(ctx context.Context, project *graph.Project, destURL string) error {
+	documents := g.Render(project)
+	names := make([]string, 0, len(documents))
+	for name := range documents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		URL := url.Join(destURL, name)
+		if err := g.fs.Upload(ctx, URL, file.DefaultFileOsMode, bytes.NewReader(documents[name])); err != nil {
+			return fmt.Errorf("failed to write %v: %w", URL, err)
+		}
+	}
+	return nil
+}
+
+// Render renders package documents and the index page keyed by document name
+func (g *Generator) Render(project *graph.Project) map[string][]byte {
+	infos := g.packageInfos(project)
+	fanIn := g.fanIn()
+	result := make(map[string][]byte, len(infos)+1)
+	for _, info := range infos {
+		result[info.doc] = g.renderPackage(info, fanIn)
+	}
+	result[g.indexName] = g.renderIndex(project, infos)
+	return result
+}
+
+// packageInfos resolves package import paths and dependencies, sorted by import path
+func (g *Generator) packageInfos(project *graph.Project) []*packageInfo {
+	var infos []*packageInfo
+	byPath := map[string]*packageInfo{}
+	for _, pkg := range project.Packages {
+		pkgPath := packagePath(project, pkg)
+		if _, ok := byPath[pkgPath]; ok {
+			continue
+		}
+		info := &packageInfo{pkg: pkg, path: pkgPath, doc: strings.ReplaceAll(pkgPath, "/", "-") + ".md"}
+		byPath[pkgPath] = info
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].path < infos[j].path })
+
+	for _, info := range infos {
+		for _, imported := range g.imports(info) {
+			target, ok := byPath[imported]
+			switch {
+			case !ok:
+				info.external = append(info.external, imported)
+			case target != info:
+				info.outbound = append(info.outbound, imported)
+				target.inbound = append(target.inbound, info.path)
+			}
+		}
+	}
+	for _, info := range infos {
+		sort.Strings(info.inbound)
+	}
+	return infos
+}
+
+// imports returns sorted unique imports of a package, taken from the dependency graph when provided
+func (g *Generator) imports(info *packageInfo) []string {
+	unique := map[string]bool{}
+	if g.dependencies != nil {
+		for _, imported := range g.dependencies[info.path] {
+			unique[imported] = true
+		}
+	} else {
+		for _, aFile := range info.pkg.FileSet {
+			for _, imported := range aFile.Imports {
+				unique[imported.Path] = true
+			}
+		}
+	}
+	result := make([]string, 0, len(unique))
+	for imported := range unique {
+		result = append(result, imported)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// fanIn counts distinct callers of each callee in the call graph
+func (g *Generator) fanIn() map[string]int {
+	result := map[string]int{}
+	for _, callees := range g.calls {
+		seen := map[string]bool{}
+		for _, callee := range callees {
+			if !seen[callee] {
+				seen[callee] = true
+				result[callee]++
+			}
+		}
+	}
+	return result
+}
+
+func (g *Generator) renderIndex(project *graph.Project, infos []*packageInfo) []byte {
+	builder := &strings.Builder{}
+	title := "Architecture"
+	if project.Name != "" {
+		title = project.Name + " architecture"
+	}
+	fmt.Fprintf(builder, "# %s\n\n", title)
+	builder.WriteString("## Packages\n\n")
+	builder.WriteString("| Package | Purpose | Files | Lines |\n| --- | --- | --- | --- |\n")
+	for _, info := range infos {
+		files, lines := fileMetrics(info.pkg)
+		fmt.Fprintf(builder, "| [%s](%s) | %s | %d | %d |\n", info.path, info.doc, escapeCell(packagePurpose(info.pkg)), files, lines)
+	}
+
+	builder.WriteString("\n## Dependencies\n\n```mermaid\ngraph LR\n")
+	nodes := make(map[string]string, len(infos))
+	for i, info := range infos {
+		nodes[info.path] = "p" + strconv.Itoa(i)
+		fmt.Fprintf(builder, "    %s[\"%s\"]\n", nodes[info.path], info.path)
+	}
+	for _, info := range infos {
+		for _, target := range info.outbound {
+			fmt.Fprintf(builder, "    %s --> %s\n", nodes[info.path], nodes[target])
+		}
+	}
+	builder.WriteString("```\n")
+	if len(project.SkippedFiles) > 0 {
+		builder.WriteString("\n## Skipped files\n\n| File | Size | Reason |\n| --- | --- | --- |\n")
+		for _, skipped := range project.SkippedFiles {
+			fmt.Fprintf(builder, "| %s | %d | %s |\n", escapeCell(skipped.Path), skipped.Size, skipped.Reason)
+		}
+	}
+	return []byte(builder.String())
+}
+
+func (g *Generator) renderPackage(info *packageInfo, fanIn map[string]int) []byte {
+	builder := &strings.Builder{}
+	fmt.Fprintf(builder, "# Package %s\n\n`%s`\n\n", info.pkg.Name, info.path)
+	if len(info.pkg.Owners) > 0 {
+		fmt.Fprintf(builder, "Owners: %s\n\n", strings.Join(info.pkg.Owners, ", "))
+	}
+
+	builder.WriteString("## Purpose\n\n")
+	if purpose := packagePurpose(info.pkg); purpose != "" {
+		builder.WriteString(purpose + "\n\n")
+	} else {
+		builder.WriteString("_No package documentation._\n\n")
+	}
+
+	g.writeTypes(builder, info.pkg)
+	g.writeFunctions(builder, info.pkg)
+	g.writeDependencies(builder, info)
+	g.writeMetrics(builder, info, fanIn)
+	return []byte(builder.String())
+}
+
+// writeTypes writes exported types with their exported fields
+func (g *Generator) writeTypes(builder *strings.Builder, pkg *graph.Package) {
+	types := exportedTypes(pkg)
+	builder.WriteString("## Types\n\n")
+	if len(types) == 0 {
+		builder.WriteString("_None._\n\n")
+		return
+	}
+	for _, info := range types {
+		fmt.Fprintf(builder, "### %s\n\n", info.typ.Name)
+		if comment := graph.CommentText(info.typ.Comment); comment != "" {
+			builder.WriteString(comment + "\n\n")
+		}
+		if len(info.fields) == 0 {
+			continue
+		}
+		builder.WriteString("| Field | Type | Optional | Tag |\n| --- | --- | --- | --- |\n")
+		for _, field := range info.fields {
+			optional := "no"
+			if field.Optional {
+				optional = "yes"
+			}
+			fmt.Fprintf(builder, "| %s | %s | %s | %s |\n", field.Name, code(graph.TypeName(field.Type)), optional, code(string(field.Tag)))
+		}
+		builder.WriteString("\n")
+	}
+}
+
+// writeFunctions writes exported functions grouped by receiver type, package functions first
+func (g *Generator) writeFunctions(builder *strings.Builder, pkg *graph.Package) {
+	groups := map[string][]*graph.Function{}
+	seen := map[string]bool{}
+	add := func(receiver string, function *graph.Function) {
+		if !function.IsExported {
+			return
+		}
+		receiver = receiverType(receiver)
+		key := receiver + "." + function.Name
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		groups[receiver] = append(groups[receiver], function)
+	}
+	for _, aFile := range pkg.FileSet {
+		for _, function := range aFile.Functions {
+			add(function.Receiver, function)
+		}
+		for _, aType := range aFile.Types {
+			if !aType.IsExported {
+				continue
+			}
+			for _, method := range aType.Methods {
+				receiver := method.Receiver
+				if receiver == "" {
+					receiver = aType.Name
+				}
+				add(receiver, method)
+			}
+		}
+	}
+
+	builder.WriteString("## Functions\n\n")
+	if len(groups) == 0 {
+		builder.WriteString("_None._\n\n")
+		return
+	}
+	receivers := make([]string, 0, len(groups))
+	for receiver := range groups {
+		receivers = append(receivers, receiver)
+	}
+	sort.Strings(receivers)
+	for _, receiver := range receivers {
+		functions := groups[receiver]
+		sort.Slice(functions, func(i, j int) bool { return functions[i].Name < functions[j].Name })
+		if receiver == "" {
+			builder.WriteString("### Package functions\n\n")
+		} else {
+			fmt.Fprintf(builder, "### %s methods\n\n", receiver)
+		}
+		for _, function := range functions {
+			signature := function.Signature
+			if signature == "" {
+				signature = function.Name
+			}
+			fmt.Fprintf(builder, "- `%s`", signature)
+			if comment := graph.FirstSentence(graph.CommentText(function.Comment)); comment != "" {
+				builder.WriteString(": " + comment)
+			}
+			builder.WriteString("\n")
+		}
+		builder.WriteString("\n")
+	}
+}
+
+// writeDependencies writes outbound, inbound and external package dependencies
+func (g *Generator) writeDependencies(builder *strings.Builder, info *packageInfo) {
+	builder.WriteString("## Dependencies\n\n")
+	writeList := func(title string, items []string, render func(string) string) {
+		fmt.Fprintf(builder, "### %s\n\n", title)
+		if len(items) == 0 {
+			builder.WriteString("_None._\n\n")
+			return
+		}
+		for _, item := range items {
+			builder.WriteString("- " + render(item) + "\n")
+		}
+		builder.WriteString("\n")
+	}
+	link := func(pkgPath string) string {
+		return fmt.Sprintf("[%s](%s.md)", pkgPath, strings.ReplaceAll(pkgPath, "/", "-"))
+	}
+	writeList("Outbound", info.outbound, link)
+	writeList("Inbound", info.inbound, link)
+	writeList("External", info.external, code)
+}
+
+// writeMetrics writes file count, lines of code, the most complex and (with a call graph) the most called functions
+func (g *Generator) writeMetrics(builder *strings.Builder, info *packageInfo, fanIn map[string]int) {
+	files, lines := fileMetrics(info.pkg)
+	builder.WriteString("## Metrics\n\n| Metric | Value |\n| --- | --- |\n")
+	fmt.Fprintf(builder, "| Files | %d |\n| Lines of code | %d |\n\n", files, lines)
+
+	var complexity, called []*ranked
+	seen := map[string]bool{}
+	visit := func(receiver string, function *graph.Function) {
+		label := function.Name
+		if receiver = receiverType(receiver); receiver != "" {
+			label = receiver + "." + function.Name
+		}
+		if seen[label] {
+			return
+		}
+		seen[label] = true
+		if function.Complexity > 0 {
+			complexity = append(complexity, &ranked{label: label, value: function.Complexity})
+		}
+		if count := fanIn[info.path+"."+label]; count > 0 {
+			called = append(called, &ranked{label: label, value: count})
+		}
+	}
+	for _, aFile := range info.pkg.FileSet {
+		for _, function := range aFile.Functions {
+			visit(function.Receiver, function)
+		}
+		for _, aType := range aFile.Types {
+			for _, method := range aType.Methods {
+				receiver := method.Receiver
+				if receiver == "" {
+					receiver = aType.Name
+				}
+				visit(receiver, method)
+			}
+		}
+	}
+	writeRanking(builder, "Top complexity", "Complexity", complexity)
+	if len(g.calls) > 0 {
+		writeRanking(builder, "Most called", "Callers", called)
+	}
+}
+
+// writeRanking writes the top ranked functions table
+func writeRanking(builder *strings.Builder, title, column string, items []*ranked) {
+	fmt.Fprintf(builder, "### %s\n\n", title)
+	if len(items) == 0 {
+		builder.WriteString("_None._\n\n")
+		return
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].value == items[j].value {
+			return items[i].label < items[j].label
+		}
+		return items[i].value > items[j].value
+	})
+	if len(items) > topLimit {
+		items = items[:topLimit]
+	}
+	fmt.Fprintf(builder, "| Function | %s |\n| --- | --- |\n", column)
+	for _, item := range items {
+		fmt.Fprintf(builder, "| `%s` | %d |\n", item.label, item.value)
+	}
+	builder.WriteString("\n")
+}
+
+// packagePath returns package import path derived from the project module name and root path
+func packagePath(project *graph.Project, pkg *graph.Package) string {
+	if project.RootPath != "" && filepath.IsAbs(pkg.ImportPath) {
+		if rel, err := filepath.Rel(project.RootPath, pkg.ImportPath); err == nil && !strings.HasPrefix(rel, "..") {
+			rel = filepath.ToSlash(rel)
+			switch {
+			case project.Name == "":
+				return rel
+			case rel == ".":
+				return project.Name
+			}
+			return path.Join(project.Name, rel)
+		}
+	}
+	if pkg.ImportPath == "" {
+		return pkg.Name
+	}
+	return pkg.ImportPath
+}
+
+// packagePurpose returns the first sentence of the package documentation (doc.go first) or README
+func packagePurpose(pkg *graph.Package) string {
+	files := append([]*graph.File{}, pkg.FileSet...)
+	sort.SliceStable(files, func(i, j int) bool {
+		if isDocFile(files[i]) != isDocFile(files[j]) {
+			return isDocFile(files[i])
+		}
+		return files[i].Name < files[j].Name
+	})
+	for _, aFile := range files {
+		if aFile.Doc != "" {
+			return graph.FirstSentence(aFile.Doc)
+		}
+	}
+	for _, asset := range pkg.Assets {
+		if strings.HasPrefix(strings.ToLower(asset.Name), "readme") {
+			content, err := asset.Load(context.Background())
+			if err != nil {
+				continue
+			}
+			if purpose := readmePurpose(string(content)); purpose != "" {
+				return purpose
+			}
+		}
+	}
+	return ""
+}
+
+func isDocFile(aFile *graph.File) bool {
+	return aFile.Name == "doc.go"
+}
+
+// readmePurpose returns the first sentence of the first README paragraph, skipping headings, badges and HTML
+func readmePurpose(content string) string {
+	var paragraph []string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			if len(paragraph) > 0 {
+				return graph.FirstSentence(strings.Join(paragraph, " "))
+			}
+		case strings.HasPrefix(line, "#"), strings.HasPrefix(line, "[!"), strings.HasPrefix(line, "!["), strings.HasPrefix(line, "<"):
+			if len(paragraph) > 0 {
+				return graph.FirstSentence(strings.Join(paragraph, " "))
+			}
+		default:
+			paragraph = append(paragraph, line)
+		}
+	}
+	return graph.FirstSentence(strings.Join(paragraph, " "))
+}
+
+// exportedTypes returns exported package types sorted by name, merged across files
+func exportedTypes(pkg *graph.Package) []*typeInfo {
+	byName := map[string]*typeInfo{}
+	var result []*typeInfo
+	for _, aFile := range pkg.FileSet {
+		for _, aType := range aFile.Types {
+			if !aType.IsExported {
+				continue
+			}
+			info, ok := byName[aType.Name]
+			if !ok {
+				info = &typeInfo{typ: aType}
+				byName[aType.Name] = info
+				result = append(result, info)
+			}
+			if info.typ.Comment == nil || info.typ.Comment.Text == "" {
+				info.typ = aType
+			}
+			for _, field := range aType.Fields {
+				if field.IsExported {
+					info.fields = append(info.fields, field)
+				}
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].typ.Name < result[j].typ.Name })
+	return result
+}
+
+// fileMetrics returns number of files and lines of code of a package
+func fileMetrics(pkg *graph.Package) (int, int) {
+	lines := 0
+	for _, aFile := range pkg.FileSet {
+		lines += aFile.Lines
+	}
+	return len(pkg.FileSet), lines
+}
+
+// receiverType returns receiver type name without pointer and type parameters (e.g. *Stack[T] -> Stack)
+func receiverType(receiver string) string {
+	receiver = strings.TrimPrefix(strings.TrimSpace(receiver), "*")
+	if idx := strings.Index(receiver, "["); idx != -1 {
+		receiver = receiver[:idx]
+	}
+	return receiver
+}
+
+func code(text string) string {
+	if text == "" {
+		return ""
+	}
+	return "`" + escapeCell(text) + "`"
+}
+
+func escapeCell(text string) string {
+	return strings.ReplaceAll(text, "|", `\|`)
+}
@@ -0,0 +1,54 @@
+package markdown_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/afs"
+	"github.com/viant/afs/url"
+	"github.com/viant/linager/inspector/golang"
+	"github.com/viant/linager/inspector/graph"
+	"github.com/viant/linager/inspector/markdown"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerator_Generate(t *testing.T) {
+	rootPath, err := filepath.Abs("../golang/testdata")
+	if !assert.NoError(t, err) {
+		return
+	}
+	inspector := golang.NewInspector(&graph.Config{IncludeUnexported: true, SkipTests: true})
+	packages, err := inspector.InspectPackages(rootPath)
+	if !assert.NoError(t, err) {
+		return
+	}
+	project := &graph.Project{Name: "myapp", RootPath: rootPath, Packages: packages}
+	project.Init()
+
+	fs := afs.New()
+	destURL := "mem://localhost/docs"
+	generator := markdown.New(fs, markdown.WithCallGraph(map[string][]string{
+		"myapp/app.main": {"myapp/stack.New", "myapp/stack.Stack.Push", "myapp/stack.Stack.Pop", "myapp/util.Inspect"},
+	}))
+	if !assert.NoError(t, generator.Generate(context.Background(), project, destURL)) {
+		return
+	}
+
+	for _, name := range []string{"index.md", "myapp-app.md", "myapp-stack.md", "myapp-util.md"} {
+		expected, err := os.ReadFile(filepath.Join("testdata", name))
+		if !assert.NoError(t, err, name) {
+			continue
+		}
+		actual, err := fs.DownloadWithURL(context.Background(), url.Join(destURL, name))
+		if !assert.NoError(t, err, name) {
+			continue
+		}
+		assert.Equal(t, string(expected), string(actual), name)
+	}
+
+	// output is deterministic across runs
+	first := generator.Render(project)
+	second := generator.Render(project)
+	assert.Equal(t, first, second)
+}
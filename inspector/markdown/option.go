@@ -0,0 +1,27 @@
+package markdown
+
+// Option represents a generator option
+type Option func(*Generator)
+
+// WithDependencies sets a package dependency graph (package path -> imported package paths),
+// overriding dependencies derived from file imports
+func WithDependencies(dependencies map[string][]string) Option {
+	return func(g *Generator) {
+		g.dependencies = dependencies
+	}
+}
+
+// WithCallGraph sets a call graph (caller -> callees) keyed by qualified function names
+// (e.g. myapp/stack.New, myapp/stack.Stack.Push), used to report the most called functions
+func WithCallGraph(calls map[string][]string) Option {
+	return func(g *Generator) {
+		g.calls = calls
+	}
+}
+
+// WithIndexName sets the index document name, index.md by default
+func WithIndexName(name string) Option {
+	return func(g *Generator) {
+		g.indexName = name
+	}
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"fmt"
 	"github.com/viant/linager/inspector/graph"
+	"math"
 	"os"
 	"path/filepath"
 	"strings"
@@ -10,14 +11,19 @@ import (
 
 // HasFileWithSuffixes checks if a directory contains Go files
 func HasFileWithSuffixes(dirPath string, inclusionSuffix, exclusionSuffix []string) (bool, error) {
-	entries, err := os.ReadDir(dirPath)
+	return HasSourceWithSuffixes(nil, dirPath, inclusionSuffix, exclusionSuffix)
+}
+
+// HasSourceWithSuffixes is HasFileWithSuffixes of a directory read with a source provider, local when sources is nil
+func HasSourceWithSuffixes(sources graph.SourceProvider, dirPath string, inclusionSuffix, exclusionSuffix []string) (bool, error) {
+	entries, err := readDir(sources, dirPath)
 	if err != nil {
 		return false, err
 	}
 
 outer:
 	for _, entry := range entries {
-		if entry.IsDir() {
+		if entry.IsDir() || isSpecial(entry.Type()) {
 			continue
 		}
 		for _, suffix := range inclusionSuffix {
@@ -37,9 +43,22 @@ outer:
 	return false, nil
 }
 
+// ReadAssetsRecursively reads package assets with their content, sub folders with source files are skipped
 func ReadAssetsRecursively(packageDir string, isRoot bool, importPath func(relative string) string, skipExt ...string) ([]*graph.Asset, error) {
+	return ScanAssets(packageDir, isRoot, importPath, math.MaxInt64, skipExt...)
+}
+
+// ScanAssets collects package assets, content of assets up to eagerSize bytes is read immediately while larger
+// assets only record their size and are loaded on demand (see graph.Asset.Load); sub folders with source files are skipped
+func ScanAssets(packageDir string, isRoot bool, importPath func(relative string) string, eagerSize int64, skipExt ...string) ([]*graph.Asset, error) {
+	return ScanSourceAssets(nil, packageDir, isRoot, importPath, eagerSize, skipExt...)
+}
+
+// ScanSourceAssets is ScanAssets of a directory read with a source provider, local when sources is nil; lazily loaded
+// assets of a provider are loaded with NewSourceLoader
+func ScanSourceAssets(sources graph.SourceProvider, packageDir string, isRoot bool, importPath func(relative string) string, eagerSize int64, skipExt ...string) ([]*graph.Asset, error) {
 	var assets []*graph.Asset
-	entries, err := os.ReadDir(packageDir)
+	entries, err := readDir(sources, packageDir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read directory: %w", err)
 	}
@@ -51,6 +70,9 @@ outer:
 			subFolders = append(subFolders, entry.Name())
 			continue
 		}
+		if isSpecial(entry.Type()) || !isRegularTarget(sources, filepath.Join(packageDir, entry.Name()), entry.Type()) {
+			continue
+		}
 
 		for _, ext := range skipExt {
 			// Skip Go files (already processed)
@@ -62,16 +84,10 @@ outer:
 
 		// Process as asset
 		filePath := filepath.Join(packageDir, entry.Name())
-		content, err := os.ReadFile(filePath)
+		asset, err := newAsset(sources, filePath, importPath(packageDir), eagerSize)
 		if err != nil {
 			return nil, fmt.Errorf("failed to read asset %s: %w", filePath, err)
 		}
-
-		asset := &graph.Asset{
-			Path:       filePath,
-			ImportPath: importPath(packageDir),
-			Content:    content,
-		}
 		assets = append(assets, asset)
 	}
 
@@ -79,7 +95,7 @@ outer:
 		return []*graph.Asset{}, nil
 	}
 	for _, subFolder := range subFolders {
-		subAssets, err := ReadAssetsRecursively(filepath.Join(packageDir, subFolder), false, importPath, skipExt...)
+		subAssets, err := ScanSourceAssets(sources, filepath.Join(packageDir, subFolder), false, importPath, eagerSize, skipExt...)
 		if err != nil {
 			return nil, fmt.Errorf("failed to read assets in subfolder %s: %w", subFolder, err)
 		}
@@ -88,3 +104,18 @@ outer:
 
 	return assets, nil
 }
+
+// isSpecial reports whether a directory entry type is a special file: a socket, device, named pipe or irregular file
+func isSpecial(mode os.FileMode) bool {
+	return mode&(os.ModeSocket|os.ModeDevice|os.ModeCharDevice|os.ModeNamedPipe|os.ModeIrregular) != 0
+}
+
+// isRegularTarget reports whether an entry is a regular file or a symlink to one, symlinked directories are not
+// scanned for assets
+func isRegularTarget(sources graph.SourceProvider, path string, mode os.FileMode) bool {
+	if mode&os.ModeSymlink == 0 {
+		return true
+	}
+	info, err := stat(sources, path)
+	return err == nil && info.Mode().IsRegular()
+}
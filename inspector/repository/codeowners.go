@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"github.com/viant/linager/inspector/graph"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// codeOwnersLocations lists CODEOWNERS file locations in GitHub lookup order
+var codeOwnersLocations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// OwnershipRule represents a single CODEOWNERS rule
+type OwnershipRule struct {
+	Pattern string   // Pattern as written in CODEOWNERS
+	Owners  []string // Team (@org/team), user (@user) or email handles; empty means the path is unowned
+	Line    int      // Line number in CODEOWNERS
+	expr    *regexp.Regexp
+}
+
+// Match reports whether rule pattern matches a slash separated path relative to the repository root
+func (r *OwnershipRule) Match(path string) bool {
+	return r.expr.MatchString(strings.TrimPrefix(filepath.ToSlash(path), "/"))
+}
+
+// CodeOwners represents parsed CODEOWNERS rules
+type CodeOwners struct {
+	Rules   []*OwnershipRule
+	Skipped []int // Lines with unsupported patterns (e.g. negations) ignored per GitHub spec
+}
+
+// Owners returns owners of a path relative to the repository root, the last matching rule wins
+func (c *CodeOwners) Owners(path string) []string {
+	for i := len(c.Rules) - 1; i >= 0; i-- {
+		if c.Rules[i].Match(path) {
+			return c.Rules[i].Owners
+		}
+	}
+	return nil
+}
+
+// ParseCodeOwners parses GitHub-style CODEOWNERS content
+func ParseCodeOwners(reader io.Reader) (*CodeOwners, error) {
+	result := &CodeOwners{}
+	scanner := bufio.NewScanner(reader)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if idx := strings.Index(line, " #"); idx != -1 {
+			line = strings.TrimSpace(line[:idx])
+		}
+		fields := strings.Fields(line)
+		pattern := strings.Replace(fields[0], `\#`, "#", 1)
+		if strings.HasPrefix(pattern, "!") || strings.Contains(pattern, "[") {
+			// negation and character ranges are not supported by CODEOWNERS
+			result.Skipped = append(result.Skipped, lineNumber)
+			continue
+		}
+		expr, err := regexp.Compile(patternExpression(pattern))
+		if err != nil {
+			return nil, fmt.Errorf("invalid CODEOWNERS pattern %q at line %d: %w", pattern, lineNumber, err)
+		}
+		result.Rules = append(result.Rules, &OwnershipRule{Pattern: pattern, Owners: fields[1:], Line: lineNumber, expr: expr})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// LoadCodeOwners loads CODEOWNERS from the repository root (.github/, root or docs/ directory)
+func LoadCodeOwners(rootPath string) (*CodeOwners, error) {
+	for _, location := range codeOwnersLocations {
+		data, err := os.ReadFile(filepath.Join(rootPath, location))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		return ParseCodeOwners(bytes.NewReader(data))
+	}
+	return nil, &graph.ErrNotFound{Kind: "file", Name: filepath.Join(rootPath, "CODEOWNERS")}
+}
+
+// patternExpression converts CODEOWNERS (gitignore style) pattern into a regular expression
+func patternExpression(pattern string) string {
+	if pattern == "*" {
+		return ".*"
+	}
+	isDir := strings.HasSuffix(pattern, "/")
+	trimmed := strings.Trim(pattern, "/")
+	// patterns with a leading or inner slash are relative to the root, otherwise they match at any depth
+	anchored := strings.HasPrefix(pattern, "/") || strings.Contains(trimmed, "/")
+
+	builder := &strings.Builder{}
+	builder.WriteString("^")
+	if !anchored {
+		builder.WriteString("(.*/)?")
+	}
+	for i := 0; i < len(trimmed); i++ {
+		switch ch := trimmed[i]; ch {
+		case '*':
+			if i+1 < len(trimmed) && trimmed[i+1] == '*' {
+				if i+2 < len(trimmed) && trimmed[i+2] == '/' {
+					builder.WriteString("(.*/)?")
+					i += 2
+				} else {
+					builder.WriteString(".*")
+					i++
+				}
+				continue
+			}
+			builder.WriteString("[^/]*")
+		case '?':
+			builder.WriteString("[^/]")
+		default:
+			builder.WriteString(regexp.QuoteMeta(string(ch)))
+		}
+	}
+	switch {
+	case isDir:
+		builder.WriteString("/.*")
+	case strings.HasSuffix(trimmed, "/*"):
+		// docs/* matches direct children only
+	default:
+		builder.WriteString("(/.*)?")
+	}
+	builder.WriteString("$")
+	return builder.String()
+}
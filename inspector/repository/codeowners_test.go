@@ -0,0 +1,100 @@
+package repository_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/linager/analyzer/linage"
+	"github.com/viant/linager/inspector/graph"
+	"github.com/viant/linager/inspector/repository"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCodeOwners_Owners(t *testing.T) {
+	codeOwners := `# default owners
+*                       @acme/platform
+
+# services
+/services/billing/      @acme/billing @alice
+services/search         @acme/search
+*.md                    docs@acme.com
+/docs/*                 @acme/writers
+**/generated/**         @acme/codegen
+/services/search/vendor/
+!/services/billing/legacy/
+`
+	rules, err := repository.ParseCodeOwners(strings.NewReader(codeOwners))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []int{11}, rules.Skipped)
+
+	testCases := []struct {
+		path   string
+		expect []string
+	}{
+		{path: "main.go", expect: []string{"@acme/platform"}},
+		{path: "services/billing/invoice.go", expect: []string{"@acme/billing", "@alice"}},
+		{path: "services/billing/legacy/old.go", expect: []string{"@acme/billing", "@alice"}},
+		{path: "services/search/index.go", expect: []string{"@acme/search"}},
+		{path: "services/search/README.md", expect: []string{"docs@acme.com"}},
+		{path: "docs/guide.txt", expect: []string{"@acme/writers"}},
+		{path: "docs/api/guide.txt", expect: []string{"@acme/platform"}},
+		{path: "services/search/generated/model.go", expect: []string{"@acme/codegen"}},
+		{path: "services/search/vendor/lib.go", expect: []string{}},
+	}
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expect, rules.Owners(testCase.path), testCase.path)
+	}
+}
+
+func TestProject_ApplyOwnership(t *testing.T) {
+	root := t.TempDir()
+	if !assert.NoError(t, os.MkdirAll(filepath.Join(root, ".github"), 0755)) {
+		return
+	}
+	codeOwners := "* @acme/platform\n/billing/ @acme/billing\n/search/ @acme/search\n"
+	if !assert.NoError(t, os.WriteFile(filepath.Join(root, ".github", "CODEOWNERS"), []byte(codeOwners), 0644)) {
+		return
+	}
+	rules, err := repository.LoadCodeOwners(root)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	newPackage := func(name string) *graph.Package {
+		location := &graph.Location{Raw: "func Run() {}"}
+		return &graph.Package{Name: name, FileSet: []*graph.File{{
+			Name:      "run.go",
+			Path:      filepath.Join(root, name, "run.go"),
+			Package:   name,
+			Functions: []*graph.Function{{Name: "Run", Location: location}},
+		}}}
+	}
+	project := &graph.Project{Name: "acme", RootPath: root, Packages: []*graph.Package{newPackage("billing"), newPackage("search"), newPackage("tools")}}
+	project.ApplyOwnership(rules)
+
+	owners := map[string][]string{}
+	for _, pkg := range project.Packages {
+		owners[pkg.Name] = pkg.Owners
+		assert.Equal(t, pkg.Owners, pkg.FileSet[0].Owners, pkg.Name)
+	}
+	assert.Equal(t, map[string][]string{"billing": {"@acme/billing"}, "search": {"@acme/search"}, "tools": {"@acme/platform"}}, owners)
+
+	documents, err := project.CreateDocuments(context.Background(), "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	for _, doc := range documents {
+		assert.Equal(t, owners[doc.Package], doc.Owners, doc.Package)
+	}
+
+	point := &linage.DataPoint{Definition: linage.CodeLocation{FilePath: "search/run.go"}}
+	point.ApplyOwners(rules)
+	assert.Equal(t, []string{"@acme/search"}, point.Metadata["owners"])
+
+	_, err = repository.LoadCodeOwners(t.TempDir())
+	assert.ErrorIs(t, err, &graph.ErrNotFound{Kind: "file"})
+}
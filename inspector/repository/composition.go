@@ -0,0 +1,429 @@
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"github.com/viant/linager/inspector/graph"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// generatedHeaderLines is the number of leading non-blank lines searched for generated file markers
+const generatedHeaderLines = 10
+
+// generatedExpr matches generated file markers, e.g. the Go convention "Code generated by stringer; DO NOT EDIT."
+var generatedExpr = regexp.MustCompile(`(?i)(code generated .*do not edit|@generated|<auto-generated|autogenerated file|this file (is|was) (auto-?)?generated)`)
+
+// generatedSuffixes are file name suffixes of generated files
+var generatedSuffixes = []string{".pb.go", ".pb.gw.go", "_generated.go", ".gen.go", "_gen.go", ".min.js", ".generated.ts"}
+
+// commentSyntax holds line and block comment delimiters of a language
+type commentSyntax struct {
+	line       []string
+	blockStart string
+	blockEnd   string
+}
+
+var (
+	cStyle    = &commentSyntax{line: []string{"//"}, blockStart: "/*", blockEnd: "*/"}
+	hashStyle = &commentSyntax{line: []string{"#"}}
+	sqlStyle  = &commentSyntax{line: []string{"--"}, blockStart: "/*", blockEnd: "*/"}
+	xmlStyle  = &commentSyntax{blockStart: "<!--", blockEnd: "-->"}
+	noComment = &commentSyntax{}
+)
+
+// scanLanguage describes a language recognized by Scan
+type scanLanguage struct {
+	name    string
+	comment *commentSyntax
+}
+
+// extensionLanguages maps lower case file extensions to languages
+var extensionLanguages = map[string]*scanLanguage{
+	".go":    {"go", cStyle},
+	".java":  {"java", cStyle},
+	".kt":    {"kotlin", cStyle},
+	".scala": {"scala", cStyle},
+	".js":    {"javascript", cStyle},
+	".jsx":   {"javascript", cStyle},
+	".mjs":   {"javascript", cStyle},
+	".cjs":   {"javascript", cStyle},
+	".ts":    {"typescript", cStyle},
+	".tsx":   {"typescript", cStyle},
+	".c":     {"c", cStyle},
+	".h":     {"c", cStyle},
+	".cc":    {"cpp", cStyle},
+	".cpp":   {"cpp", cStyle},
+	".hpp":   {"cpp", cStyle},
+	".cs":    {"csharp", cStyle},
+	".rs":    {"rust", cStyle},
+	".swift": {"swift", cStyle},
+	".proto": {"protobuf", cStyle},
+	".py":    {"python", hashStyle},
+	".rb":    {"ruby", hashStyle},
+	".pl":    {"perl", hashStyle},
+	".sh":    {"shell", hashStyle},
+	".bash":  {"shell", hashStyle},
+	".yaml":  {"yaml", hashStyle},
+	".yml":   {"yaml", hashStyle},
+	".toml":  {"toml", hashStyle},
+	".sql":   {"sql", sqlStyle},
+	".xml":   {"xml", xmlStyle},
+	".html":  {"html", xmlStyle},
+	".md":    {"markdown", noComment},
+	".json":  {"json", noComment},
+}
+
+// interpreterLanguages maps shebang interpreters to language extensions
+var interpreterLanguages = map[string]string{
+	"sh": ".sh", "bash": ".sh", "zsh": ".sh", "python": ".py", "python3": ".py", "node": ".js", "ruby": ".rb", "perl": ".pl",
+}
+
+// CodeStats holds file and line counts, code lines exclude comment and blank lines
+type CodeStats struct {
+	Files         int `json:"files"`
+	Lines         int `json:"lines"`
+	Code          int `json:"code"`
+	Comment       int `json:"comment"`
+	Blank         int `json:"blank"`
+	Generated     int `json:"generated"`     // generated files
+	GeneratedCode int `json:"generatedCode"` // code lines of generated files
+	Tests         int `json:"tests"`         // test files
+	TestCode      int `json:"testCode"`      // code lines of test files
+}
+
+// LanguageStats holds counts of a language
+type LanguageStats struct {
+	Language string `json:"language"`
+	CodeStats
+}
+
+// DirectoryStats holds counts of files directly in a directory
+type DirectoryStats struct {
+	Path string `json:"path"` // slash separated path relative to the scan root, "." for the root
+	CodeStats
+	Languages []*LanguageStats `json:"languages"`
+}
+
+// CompositionSummary holds totals of a composition report
+type CompositionSummary struct {
+	CodeStats
+	Languages      int     `json:"languages"`
+	GeneratedRatio float64 `json:"generatedRatio"` // generated code lines to all code lines
+	TestRatio      float64 `json:"testRatio"`      // test code lines to other code lines
+}
+
+// CompositionReport describes the composition of a source tree, see Scan
+type CompositionReport struct {
+	Root        string              `json:"root"`
+	Summary     *CompositionSummary `json:"summary"`
+	Languages   []*LanguageStats    `json:"languages"`   // ordered by code lines
+	Directories []*DirectoryStats   `json:"directories"` // ordered by code lines
+}
+
+// Scan computes the composition of a source tree without parsing: languages are detected by file extension or the
+// shebang of extensionless files, lines are classified as code, comment or blank by language comment syntax and
+// generated files are detected by header markers (e.g. "Code generated ... DO NOT EDIT.") or file name suffixes.
+// Hidden, vendor and node_modules directories, symlinks, binary and unrecognized files are skipped.
+func Scan(root string) (*CompositionReport, error) {
+	report := &CompositionReport{Root: root, Summary: &CompositionSummary{}}
+	languages := map[string]*LanguageStats{}
+	directories := map[string]*DirectoryStats{}
+	err := Walk(root, false, func(aPath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		name := info.Name()
+		if info.IsDir() {
+			if aPath != root && (strings.HasPrefix(name, ".") || name == "vendor" || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		stats, language, err := scanFile(aPath)
+		if err != nil || stats == nil {
+			return err
+		}
+		relative, err := filepath.Rel(root, filepath.Dir(aPath))
+		if err != nil {
+			return err
+		}
+		relative = filepath.ToSlash(relative)
+		directory, ok := directories[relative]
+		if !ok {
+			directory = &DirectoryStats{Path: relative}
+			directories[relative] = directory
+		}
+		if directory.language(language) == nil {
+			directory.Languages = append(directory.Languages, &LanguageStats{Language: language})
+		}
+		if languages[language] == nil {
+			languages[language] = &LanguageStats{Language: language}
+		}
+		for _, target := range []*CodeStats{&report.Summary.CodeStats, &languages[language].CodeStats, &directory.CodeStats, &directory.language(language).CodeStats} {
+			target.add(stats)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", root, graph.NotFoundError("directory", root, err))
+	}
+	for _, language := range languages {
+		report.Languages = append(report.Languages, language)
+	}
+	sortLanguages(report.Languages)
+	for _, directory := range directories {
+		sortLanguages(directory.Languages)
+		report.Directories = append(report.Directories, directory)
+	}
+	sort.Slice(report.Directories, func(i, j int) bool {
+		a, b := report.Directories[i], report.Directories[j]
+		return a.Code > b.Code || a.Code == b.Code && a.Path < b.Path
+	})
+	summary := report.Summary
+	summary.Languages = len(report.Languages)
+	if summary.Code > 0 {
+		summary.GeneratedRatio = float64(summary.GeneratedCode) / float64(summary.Code)
+	}
+	if source := summary.Code - summary.TestCode; source > 0 {
+		summary.TestRatio = float64(summary.TestCode) / float64(source)
+	}
+	return report, nil
+}
+
+// Language returns counts of a language, nil when the language was not found
+func (r *CompositionReport) Language(name string) *LanguageStats {
+	for _, language := range r.Languages {
+		if language.Language == name {
+			return language
+		}
+	}
+	return nil
+}
+
+// Directory returns counts of a directory by its path relative to the scan root, nil when not found
+func (r *CompositionReport) Directory(relative string) *DirectoryStats {
+	for _, directory := range r.Directories {
+		if directory.Path == relative {
+			return directory
+		}
+	}
+	return nil
+}
+
+// WriteTable writes the summary, languages and up to top largest directories (all when top is not positive) as
+// aligned text tables
+func (r *CompositionReport) WriteTable(w io.Writer, top int) error {
+	writer := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	summary := r.Summary
+	fmt.Fprintf(writer, "LANGUAGE\tFILES\tCODE\tCOMMENT\tBLANK\tGENERATED\tTESTS\t\n")
+	for _, language := range r.Languages {
+		fmt.Fprintf(writer, "%s\t%d\t%d\t%d\t%d\t%d\t%d\t\n", language.Language, language.Files, language.Code, language.Comment, language.Blank, language.Generated, language.Tests)
+	}
+	fmt.Fprintf(writer, "total\t%d\t%d\t%d\t%d\t%d\t%d\t\n", summary.Files, summary.Code, summary.Comment, summary.Blank, summary.Generated, summary.Tests)
+	fmt.Fprintf(writer, "\nDIRECTORY\tFILES\tCODE\tLANGUAGES\t\n")
+	for i, directory := range r.Directories {
+		if top > 0 && i == top {
+			break
+		}
+		var names []string
+		for _, language := range directory.Languages {
+			names = append(names, language.Language)
+		}
+		fmt.Fprintf(writer, "%s\t%d\t%d\t%s\t\n", directory.Path, directory.Files, directory.Code, strings.Join(names, ","))
+	}
+	fmt.Fprintf(writer, "\ngenerated code: %.1f%%, test to source code: %.2f\n", 100*summary.GeneratedRatio, summary.TestRatio)
+	return writer.Flush()
+}
+
+// language returns counts of a directory language
+func (d *DirectoryStats) language(name string) *LanguageStats {
+	for _, language := range d.Languages {
+		if language.Language == name {
+			return language
+		}
+	}
+	return nil
+}
+
+func (s *CodeStats) add(other *CodeStats) {
+	s.Files += other.Files
+	s.Lines += other.Lines
+	s.Code += other.Code
+	s.Comment += other.Comment
+	s.Blank += other.Blank
+	s.Generated += other.Generated
+	s.GeneratedCode += other.GeneratedCode
+	s.Tests += other.Tests
+	s.TestCode += other.TestCode
+}
+
+// sortLanguages orders languages by code lines, then name
+func sortLanguages(languages []*LanguageStats) {
+	sort.Slice(languages, func(i, j int) bool {
+		a, b := languages[i], languages[j]
+		return a.Code > b.Code || a.Code == b.Code && a.Language < b.Language
+	})
+}
+
+// scanFile counts lines of a recognized source file, nil stats for unrecognized or binary files
+func scanFile(filePath string) (*CodeStats, string, error) {
+	name := filepath.Base(filePath)
+	ext := strings.ToLower(filepath.Ext(name))
+	language, known := extensionLanguages[ext]
+	if !known && ext != "" {
+		return nil, "", nil
+	}
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, "", err
+	}
+	defer file.Close()
+	reader := bufio.NewReader(file)
+	head, _ := reader.Peek(512)
+	if graph.IsBinary(head) {
+		return nil, "", nil
+	}
+	if !known {
+		if language = shebangLanguage(head); language == nil {
+			return nil, "", nil
+		}
+	}
+	stats := &CodeStats{Files: 1}
+	generated := IsGeneratedFile(name)
+	headerLines := 0
+	inBlock := false
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		stats.Lines++
+		switch classifyLine(line, language.comment, &inBlock) {
+		case lineBlank:
+			stats.Blank++
+			continue
+		case lineComment:
+			stats.Comment++
+		default:
+			stats.Code++
+		}
+		if !generated && headerLines < generatedHeaderLines {
+			headerLines++
+			generated = generatedExpr.MatchString(line)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, "", fmt.Errorf("failed to scan %s: %w", filePath, err)
+	}
+	if generated {
+		stats.Generated, stats.GeneratedCode = 1, stats.Code
+	}
+	if isTestSource(name) {
+		stats.Tests, stats.TestCode = 1, stats.Code
+	}
+	return stats, language.name, nil
+}
+
+// shebangLanguage returns the language of an interpreter script, e.g. #!/usr/bin/env python3
+func shebangLanguage(head []byte) *scanLanguage {
+	if !bytes.HasPrefix(head, []byte("#!")) {
+		return nil
+	}
+	line, _, _ := bytes.Cut(head[2:], []byte("\n"))
+	fields := strings.Fields(string(line))
+	if len(fields) == 0 {
+		return nil
+	}
+	interpreter := path.Base(fields[0])
+	if interpreter == "env" && len(fields) > 1 {
+		interpreter = fields[1]
+	}
+	return extensionLanguages[interpreterLanguages[interpreter]]
+}
+
+// IsGeneratedFile reports whether a file name has a suffix of generated files, e.g. .pb.go; header markers are not
+// checked
+func IsGeneratedFile(name string) bool {
+	for _, suffix := range generatedSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTestSource reports whether a file name follows test naming conventions of Go, Java, JavaScript/TypeScript or Python
+func isTestSource(name string) bool {
+	if graph.IsTestFile(name) {
+		return true
+	}
+	if strings.Contains(name, ".test.") || strings.Contains(name, ".spec.") {
+		return true
+	}
+	return strings.HasSuffix(name, ".py") && (strings.HasPrefix(name, "test_") || strings.HasSuffix(name, "_test.py"))
+}
+
+// Line kinds
+const (
+	lineCode = iota
+	lineComment
+	lineBlank
+)
+
+// classifyLine classifies a line as blank, comment or code, lines holding code and comments are code; block comment
+// state is carried across lines, delimiters within string literals are not recognized
+func classifyLine(line string, syntax *commentSyntax, inBlock *bool) int {
+	rest := strings.TrimSpace(line)
+	if rest == "" {
+		return lineBlank
+	}
+	hasCode := false
+	for rest != "" {
+		if *inBlock {
+			index := strings.Index(rest, syntax.blockEnd)
+			if index == -1 {
+				break
+			}
+			*inBlock = false
+			rest = strings.TrimSpace(rest[index+len(syntax.blockEnd):])
+			continue
+		}
+		if hasLinePrefix(rest, syntax.line) {
+			break
+		}
+		if syntax.blockStart != "" && strings.HasPrefix(rest, syntax.blockStart) {
+			*inBlock = true
+			rest = rest[len(syntax.blockStart):]
+			continue
+		}
+		hasCode = true
+		if syntax.blockStart == "" {
+			break
+		}
+		index := strings.Index(rest, syntax.blockStart)
+		if index == -1 {
+			break
+		}
+		rest = rest[index:]
+	}
+	if hasCode {
+		return lineCode
+	}
+	return lineComment
+}
+
+func hasLinePrefix(text string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(text, prefix) {
+			return true
+		}
+	}
+	return false
+}
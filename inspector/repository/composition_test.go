@@ -0,0 +1,66 @@
+package repository_test
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/linager/inspector/repository"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestScan(t *testing.T) {
+	root := t.TempDir()
+	for name, content := range map[string]string{
+		"main.go":             "// Package main runs the app\npackage main\n\n/* block\n   comment */\nfunc main() { /* inline */ }\n",
+		"main_test.go":        "package main\n\nfunc TestMain() {}\n",
+		"zz_generated.go":     "// Code generated by stringer; DO NOT EDIT.\n\npackage main\n\nvar names = []string{\"a\"}\n",
+		"src/App.java":        "/**\n * App\n */\npublic class App {\n    int x; // field\n}\n",
+		"web/app.js":          "const a = 1;\n",
+		"web/app.test.js":     "test('a', () => {});\n",
+		"bin/tool":            "#!/usr/bin/env python3\n# tool\nprint('x')\n",
+		"README":              "plain text\n",
+		"logo.png":            "\x89PNG\x00\x00",
+		"vendor/lib/lib.go":   "package lib\n",
+		".git/hooks/pre-push": "#!/bin/sh\nexit 0\n",
+	} {
+		assert.NoError(t, os.MkdirAll(filepath.Join(root, filepath.Dir(name)), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(root, name), []byte(content), 0644))
+	}
+	report, err := repository.Scan(root)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var languages []string
+	for _, language := range report.Languages {
+		languages = append(languages, language.Language)
+	}
+	assert.Equal(t, []string{"go", "java", "javascript", "python"}, languages)
+	assert.Equal(t, repository.CodeStats{Files: 3, Lines: 14, Code: 6, Comment: 4, Blank: 4, Generated: 1, GeneratedCode: 2, Tests: 1, TestCode: 2}, report.Language("go").CodeStats)
+	assert.Equal(t, repository.CodeStats{Files: 1, Lines: 6, Code: 3, Comment: 3}, report.Language("java").CodeStats)
+	assert.Equal(t, repository.CodeStats{Files: 2, Lines: 2, Code: 2, Tests: 1, TestCode: 1}, report.Language("javascript").CodeStats)
+	assert.Equal(t, repository.CodeStats{Files: 1, Lines: 3, Code: 1, Comment: 2}, report.Language("python").CodeStats, "shebang detected")
+
+	summary := report.Summary
+	assert.Equal(t, 7, summary.Files)
+	assert.Equal(t, 12, summary.Code)
+	assert.Equal(t, 4, summary.Languages)
+	assert.InDelta(t, 2.0/12, summary.GeneratedRatio, 1e-9)
+	assert.InDelta(t, 3.0/9, summary.TestRatio, 1e-9)
+
+	var directories []string
+	for _, directory := range report.Directories {
+		directories = append(directories, directory.Path)
+	}
+	assert.Equal(t, []string{".", "src", "web", "bin"}, directories, "largest first, hidden and vendor skipped")
+	assert.Equal(t, 2, report.Directory("web").Files)
+
+	buffer := &bytes.Buffer{}
+	assert.NoError(t, report.WriteTable(buffer, 2))
+	table := buffer.String()
+	assert.Contains(t, table, "LANGUAGE")
+	assert.Contains(t, table, "src")
+	assert.False(t, strings.Contains(table, "web"), "limited to top directories")
+}
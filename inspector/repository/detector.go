@@ -2,19 +2,74 @@ package repository
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"github.com/viant/afs"
+	"github.com/viant/linager/inspector/graph"
 	"golang.org/x/mod/modfile"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 )
 
-// Detector identifies project root folders and provides project-related information
+// Detector identifies project root folders and provides project-related information; upward marker searches are
+// memoized by directory, see InvalidateCache
 type Detector struct {
 	// Common project root marker files/directories
 	markers []string
+	// sources provide project markers and go.mod, fs files when nil
+	sources graph.SourceProvider
+	// fs reads local (or virtual) files
+	fs    afs.Service
+	cache *detectorCache
+}
+
+// CacheStats counts detector cache use
+type CacheStats struct {
+	Hits     int // Searches answered by a memoized directory
+	Searches int // Upward searches probing at least one directory
+}
+
+// detectorCache memoizes upward searches and project files by directory
+type detectorCache struct {
+	mux      sync.Mutex
+	roots    map[string]markerRoot // project root by searched directory
+	gitRoots map[string]markerRoot // git root by searched directory
+	projects map[string]*Project   // project info by root, without file relative path
+	origins  map[string]string     // origin URL by git root
+	stats    CacheStats
+}
+
+// markerRoot is the result of an upward search, empty path when nothing was found
+type markerRoot struct {
+	path        string
+	projectType string
+}
+
+func newDetectorCache() *detectorCache {
+	return &detectorCache{
+		roots:    map[string]markerRoot{},
+		gitRoots: map[string]markerRoot{},
+		projects: map[string]*Project{},
+		origins:  map[string]string{},
+	}
+}
+
+// NewWithSources creates a project detector reading project markers and go.mod with a source provider, e.g. a
+// GitObjectProvider; repositories are still detected from local files
+func NewWithSources(sources graph.SourceProvider) *Detector {
+	ret := New()
+	ret.sources = sources
+	return ret
+}
+
+// NewWithFS creates a project detector reading all files with a file system service, e.g. afs.NewFaker() in tests
+func NewWithFS(fs afs.Service) *Detector {
+	ret := New()
+	ret.fs = fs
+	return ret
 }
 
 // New creates a new project detector instance
@@ -24,6 +79,7 @@ func New() *Detector {
 			"go.mod",           // Go projects
 			"pom.xml",          // Java/Maven projects
 			"build.gradle",     // Java/Gradle projects
+			"build.sbt",        // Scala/sbt projects
 			"package.json",     // JavaScript/Node projects
 			"composer.json",    // PHP projects
 			"Cargo.toml",       // Rust projects
@@ -32,11 +88,29 @@ func New() *Detector {
 			"Gemfile",          // Ruby projects
 			".git",             // Generic VCS marker
 		},
+		fs:    afs.New(),
+		cache: newDetectorCache(),
 	}
 }
 
+// InvalidateCache drops memoized searches and project files, e.g. after markers were added or removed
+func (d *Detector) InvalidateCache() {
+	d.cache.mux.Lock()
+	defer d.cache.mux.Unlock()
+	stats := d.cache.stats
+	d.cache = newDetectorCache()
+	d.cache.stats = stats
+}
+
+// CacheStats returns counts of memoized and performed upward searches
+func (d *Detector) CacheStats() CacheStats {
+	d.cache.mux.Lock()
+	defer d.cache.mux.Unlock()
+	return d.cache.stats
+}
+
 // DetectProject identifies the project root for the given file path and returns project info
-func (d *Detector) DetectProject(filePath string, baseURL ...string) (*Project, error) {
+func (d *Detector) DetectProject(ctx context.Context, filePath string, baseURL ...string) (*Project, error) {
 	// Get the absolute path
 	absPath, err := filepath.Abs(filePath)
 	if err != nil {
@@ -46,9 +120,9 @@ func (d *Detector) DetectProject(filePath string, baseURL ...string) (*Project,
 	// If the path is a directory, start from there
 	// If it's a file, start from its parent directory
 	startDir := absPath
-	fileInfo, err := os.Stat(absPath)
+	fileInfo, err := d.stat(ctx, d.sources, absPath)
 	if err != nil {
-		return nil, err
+		return nil, graph.NotFoundError("path", absPath, err)
 	}
 
 	if !fileInfo.IsDir() {
@@ -56,7 +130,10 @@ func (d *Detector) DetectProject(filePath string, baseURL ...string) (*Project,
 	}
 
 	// Search up the directory tree for project markers
-	rootPath, projectType := d.findProjectRoot(startDir)
+	root := d.findProjectRoot(ctx, startDir)
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
 
 	// Create default Project with fallback values
 	info := &Project{
@@ -65,24 +142,14 @@ func (d *Detector) DetectProject(filePath string, baseURL ...string) (*Project,
 	}
 
 	// Use baseURL if provided and no project root found
-	if rootPath == "" && len(baseURL) > 0 && baseURL[0] != "" {
+	if root.path == "" && len(baseURL) > 0 && baseURL[0] != "" {
 		info.RootPath = baseURL[0]
-	} else if rootPath != "" {
-		info.RootPath = rootPath
-		info.Type = projectType
-	}
-
-	if projectType == "go" {
-		goModPath := filepath.Join(info.RootPath, "go.mod")
-		if _, err := os.Stat(goModPath); err == nil {
-			data, err := os.ReadFile(goModPath)
-			if err == nil {
-				mod, _ := modfile.Parse(goModPath, data, nil)
-				if mod != nil {
-					info.GoModule = mod.Module
-				}
-			}
-		}
+	} else if root.path != "" {
+		detected := d.rootProject(ctx, root)
+		info.RootPath = root.path
+		info.Type = root.projectType
+		info.Name = detected.Name
+		info.GoModule = detected.GoModule
 	}
 
 	// Calculate relative path from project root to the file
@@ -92,17 +159,45 @@ func (d *Detector) DetectProject(filePath string, baseURL ...string) (*Project,
 		relPath = filepath.Base(absPath)
 	}
 	info.RelativePath = filepath.ToSlash(relPath)
+	return info, nil
+}
 
-	// Try to extract project name from config files
-	if projectType != "" {
-		info.Name = d.extractProjectName(rootPath, projectType)
+// rootProject returns the name and Go module of a project root, memoized by root
+func (d *Detector) rootProject(ctx context.Context, root markerRoot) *Project {
+	cache := d.cache
+	cache.mux.Lock()
+	detected, ok := cache.projects[root.path]
+	cache.mux.Unlock()
+	if ok {
+		return detected
+	}
+	detected = &Project{}
+	if root.projectType == "go" {
+		goModPath := filepath.Join(root.path, "go.mod")
+		if data, err := d.readFile(ctx, d.sources, goModPath); err == nil {
+			if mod, _ := modfile.Parse(goModPath, data, nil); mod != nil {
+				detected.GoModule = mod.Module
+			}
+		}
 	}
 
-	return info, nil
+	// Try to extract project name from config files
+	switch {
+	case d.sources != nil && detected.GoModule != nil:
+		detected.Name = detected.GoModule.Mod.Path
+	case root.projectType != "":
+		detected.Name = d.extractProjectName(ctx, root.path, root.projectType)
+	}
+	if ctx.Err() == nil {
+		cache.mux.Lock()
+		cache.projects[root.path] = detected
+		cache.mux.Unlock()
+	}
+	return detected
 }
 
 // DetectRepository identifies the repository containing the given file path
-func (d *Detector) DetectRepository(filePath string) (*Repository, error) {
+func (d *Detector) DetectRepository(ctx context.Context, filePath string) (*Repository, error) {
 	// Get the absolute path
 	absPath, err := filepath.Abs(filePath)
 	if err != nil {
@@ -112,9 +207,9 @@ func (d *Detector) DetectRepository(filePath string) (*Repository, error) {
 	// If the path is a directory, start from there
 	// If it's a file, start from its parent directory
 	startDir := absPath
-	fileInfo, err := os.Stat(absPath)
+	fileInfo, err := d.stat(ctx, nil, absPath)
 	if err != nil {
-		return nil, err
+		return nil, graph.NotFoundError("path", absPath, err)
 	}
 
 	if !fileInfo.IsDir() {
@@ -122,7 +217,10 @@ func (d *Detector) DetectRepository(filePath string) (*Repository, error) {
 	}
 
 	// First try to find a git repository
-	gitRoot := d.findGitRoot(startDir)
+	gitRoot := d.findGitRoot(ctx, startDir)
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
 	if gitRoot != "" {
 		// We found a git repository
 		repo := &Repository{
@@ -131,10 +229,10 @@ func (d *Detector) DetectRepository(filePath string) (*Repository, error) {
 		}
 
 		// Try to get the origin URL
-		repo.Origin = d.extractGitOrigin(gitRoot)
+		repo.Origin = d.extractGitOrigin(ctx, gitRoot)
 
 		// Get project info
-		info, err := d.DetectProject(filePath)
+		info, err := d.DetectProject(ctx, filePath)
 		if err == nil {
 			repo.Info = info
 		}
@@ -143,7 +241,7 @@ func (d *Detector) DetectRepository(filePath string) (*Repository, error) {
 	}
 
 	// If no git repository, try to find another type of project
-	info, err := d.DetectProject(filePath)
+	info, err := d.DetectProject(ctx, filePath)
 	if err != nil {
 		return nil, err
 	}
@@ -159,72 +257,154 @@ func (d *Detector) DetectRepository(filePath string) (*Repository, error) {
 }
 
 // findProjectRoot searches up from the current directory for project markers
-func (d *Detector) findProjectRoot(startDir string) (string, string) {
-	dir := startDir
-
-	// Search up the directory tree
-	for {
+func (d *Detector) findProjectRoot(ctx context.Context, startDir string) markerRoot {
+	return d.searchUp(ctx, d.cache.roots, startDir, "", func(dir string) (markerRoot, bool) {
 		for _, marker := range d.markers {
 			markerPath := filepath.Join(dir, marker)
-			if _, err := os.Stat(markerPath); err == nil {
-				projectType := determineProjectType(marker)
-				return dir, projectType
+			if _, err := d.stat(ctx, d.sources, markerPath); err == nil {
+				return markerRoot{path: dir, projectType: determineProjectType(marker)}, true
 			}
 		}
+		return markerRoot{}, false
+	})
+}
+
+// findGitRoot finds the root of the git repository containing the given directory, the home directory is not searched
+func (d *Detector) findGitRoot(ctx context.Context, startDir string) string {
+	root := d.searchUp(ctx, d.cache.gitRoots, startDir, os.Getenv("HOME"), func(dir string) (markerRoot, bool) {
+		// .git is a directory, or a file pointing to the git directory of a worktree or submodule
+		if _, err := d.stat(ctx, nil, filepath.Join(dir, ".git")); err == nil {
+			return markerRoot{path: dir, projectType: "git"}, true
+		}
+		return markerRoot{}, false
+	})
+	return root.path
+}
 
+// searchUp probes directories from startDir up to the filesystem root (or a directory below stopAt) and returns the
+// first match; results are memoized for every probed directory, so sibling searches stop at the first known parent
+func (d *Detector) searchUp(ctx context.Context, results map[string]markerRoot, startDir, stopAt string, probe func(dir string) (markerRoot, bool)) markerRoot {
+	cache := d.cache
+	lookup := func(dir string) (markerRoot, bool) {
+		cache.mux.Lock()
+		defer cache.mux.Unlock()
+		result, ok := results[dir]
+		return result, ok
+	}
+	var chain []string
+	var result markerRoot
+	for dir := startDir; ; {
+		if cached, ok := lookup(dir); ok {
+			result = cached
+			break
+		}
+		if ctx.Err() != nil {
+			return markerRoot{} // an interrupted search is not memoized
+		}
+		chain = append(chain, dir)
+		if found, ok := probe(dir); ok {
+			result = found
+			break
+		}
 		// Move up one directory
 		parent := filepath.Dir(dir)
-		if parent == dir {
-			// We've reached the filesystem root with no match
+		if parent == dir || parent == stopAt {
+			// We've reached the filesystem root (or the stop directory) with no match
 			break
 		}
 		dir = parent
 	}
-
-	return "", ""
+	cache.mux.Lock()
+	defer cache.mux.Unlock()
+	if len(chain) == 0 {
+		cache.stats.Hits++
+	} else {
+		cache.stats.Searches++
+	}
+	for _, dir := range chain {
+		results[dir] = result
+	}
+	return result
 }
 
-// findGitRoot finds the root of the git repository containing the given directory
-func (d *Detector) findGitRoot(startDir string) string {
-	dir := startDir
+// stat returns file info read with a source provider, the detector file system when sources is nil
+func (d *Detector) stat(ctx context.Context, sources graph.SourceProvider, path string) (os.FileInfo, error) {
+	if sources != nil {
+		return sources.Stat(path)
+	}
+	return d.fs.Object(ctx, path)
+}
 
-	homeDir := os.Getenv("HOME")
-	// Search up the directory tree for .git directory
-	for {
-		gitDir := filepath.Join(dir, ".git")
-		if _, err := os.Stat(gitDir); err == nil {
-			return dir
-		}
+// readFile reads a file with a source provider, the detector file system when sources is nil
+func (d *Detector) readFile(ctx context.Context, sources graph.SourceProvider, path string) ([]byte, error) {
+	if sources != nil {
+		return sources.ReadFile(path)
+	}
+	return d.fs.DownloadWithURL(ctx, path)
+}
 
-		// Move up one directory
-		parent := filepath.Dir(dir)
-		if parent == dir {
-			// We've reached the filesystem root with no match
-			break
-		}
-		if homeDir == parent {
-			return ""
+// gitConfigPath returns the git config of a repository root; a .git file (worktree or submodule) points to the git
+// directory with a "gitdir: <path>" line, worktrees share the config of their common directory
+func (d *Detector) gitConfigPath(ctx context.Context, gitRoot string) string {
+	gitDir := filepath.Join(gitRoot, ".git")
+	info, err := d.stat(ctx, nil, gitDir)
+	if err != nil {
+		return ""
+	}
+	if info.IsDir() {
+		return filepath.Join(gitDir, "config")
+	}
+	data, err := d.readFile(ctx, nil, gitDir)
+	if err != nil {
+		return ""
+	}
+	location, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return ""
+	}
+	if gitDir = strings.TrimSpace(location); !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(gitRoot, gitDir)
+	}
+	if data, err := d.readFile(ctx, nil, filepath.Join(gitDir, "commondir")); err == nil {
+		commonDir := strings.TrimSpace(string(data))
+		if !filepath.IsAbs(commonDir) {
+			commonDir = filepath.Join(gitDir, commonDir)
 		}
-		dir = parent
+		return filepath.Join(commonDir, "config")
 	}
+	return filepath.Join(gitDir, "config")
+}
 
-	return ""
+// extractGitOrigin extracts the origin URL from git config, memoized by git root
+func (d *Detector) extractGitOrigin(ctx context.Context, gitRoot string) string {
+	cache := d.cache
+	cache.mux.Lock()
+	origin, ok := cache.origins[gitRoot]
+	cache.mux.Unlock()
+	if ok {
+		return origin
+	}
+	origin = d.gitOrigin(ctx, gitRoot)
+	if ctx.Err() == nil {
+		cache.mux.Lock()
+		cache.origins[gitRoot] = origin
+		cache.mux.Unlock()
+	}
+	return origin
 }
 
-// extractGitOrigin extracts the origin URL from git config
-func (d *Detector) extractGitOrigin(gitRoot string) string {
-	configPath := filepath.Join(gitRoot, ".git", "config")
-	if _, err := os.Stat(configPath); err != nil {
+// gitOrigin reads the origin URL from git config, empty when not configured
+func (d *Detector) gitOrigin(ctx context.Context, gitRoot string) string {
+	configPath := d.gitConfigPath(ctx, gitRoot)
+	if configPath == "" {
 		return ""
 	}
-
-	file, err := os.Open(configPath)
+	data, err := d.readFile(ctx, nil, configPath)
 	if err != nil {
 		return ""
 	}
-	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
 	foundRemote := false
 
 	for scanner.Scan() {
@@ -244,27 +424,29 @@ func (d *Detector) extractGitOrigin(gitRoot string) string {
 }
 
 // extractProjectName attempts to extract a project name from configuration files
-func (d *Detector) extractProjectName(rootPath string, projectType string) string {
+func (d *Detector) extractProjectName(ctx context.Context, rootPath string, projectType string) string {
 	switch projectType {
 	case "go":
-		return extractGoModuleName(filepath.Join(rootPath, "go.mod"))
+		return d.extractGoModuleName(ctx, filepath.Join(rootPath, "go.mod"))
 	case "javascript":
-		return extractJSPackageName(filepath.Join(rootPath, "package.json"))
+		return d.extractJSPackageName(ctx, filepath.Join(rootPath, "package.json"))
 	case "java":
-		if name := extractMavenProjectName(filepath.Join(rootPath, "pom.xml")); name != "" {
+		if name := d.extractMavenProjectName(ctx, filepath.Join(rootPath, "pom.xml")); name != "" {
 			return name
 		}
-		return extractGradleProjectName(filepath.Join(rootPath, "build.gradle"))
+		return d.extractGradleProjectName(ctx, filepath.Join(rootPath, "build.gradle"))
 	case "python":
-		if name := extractPyProjectName(filepath.Join(rootPath, "pyproject.toml")); name != "" {
+		if name := d.extractPyProjectName(ctx, filepath.Join(rootPath, "pyproject.toml")); name != "" {
 			return name
 		}
-		return extractPythonPackageName(rootPath)
+		return d.extractPythonPackageName(ctx, rootPath)
 	case "rust":
-		return extractCargoProjectName(filepath.Join(rootPath, "Cargo.toml"))
+		return d.extractCargoProjectName(ctx, filepath.Join(rootPath, "Cargo.toml"))
+	case "scala":
+		return d.extractSbtProjectName(ctx, filepath.Join(rootPath, "build.sbt"))
 	case "git":
 		// Extract name from git remote or directory name
-		return extractGitProjectName(rootPath)
+		return d.extractGitProjectName(ctx, rootPath)
 	default:
 		// Fall back to directory name
 		return filepath.Base(rootPath)
@@ -273,18 +455,14 @@ func (d *Detector) extractProjectName(rootPath string, projectType string) strin
 
 // Helper functions to extract project names from various config files
 
-func extractGoModuleName(goModPath string) string {
-	fs := afs.New()
-	if content, _ := fs.DownloadWithURL(context.Background(), goModPath); len(content) > 0 {
-		if mod, _ := modfile.Parse(goModPath, content, nil); mod != nil {
-			return mod.Module.Mod.Path
-		}
-
-	}
-	data, err := os.ReadFile(goModPath)
+func (d *Detector) extractGoModuleName(ctx context.Context, goModPath string) string {
+	data, err := d.readFile(ctx, nil, goModPath)
 	if err != nil {
 		return filepath.Base(filepath.Dir(goModPath))
 	}
+	if mod, _ := modfile.Parse(goModPath, data, nil); mod != nil && mod.Module != nil {
+		return mod.Module.Mod.Path
+	}
 	moduleRegex := regexp.MustCompile(`module\s+([^\s]+)`)
 	matches := moduleRegex.FindSubmatch(data)
 	if len(matches) < 2 {
@@ -296,8 +474,8 @@ func extractGoModuleName(goModPath string) string {
 	return modulePath
 }
 
-func extractJSPackageName(packageJsonPath string) string {
-	data, err := os.ReadFile(packageJsonPath)
+func (d *Detector) extractJSPackageName(ctx context.Context, packageJsonPath string) string {
+	data, err := d.readFile(ctx, nil, packageJsonPath)
 	if err != nil {
 		return filepath.Base(filepath.Dir(packageJsonPath))
 	}
@@ -313,8 +491,8 @@ func extractJSPackageName(packageJsonPath string) string {
 	return string(matches[1])
 }
 
-func extractMavenProjectName(pomPath string) string {
-	data, err := os.ReadFile(pomPath)
+func (d *Detector) extractMavenProjectName(ctx context.Context, pomPath string) string {
+	data, err := d.readFile(ctx, nil, pomPath)
 	if err != nil {
 		return ""
 	}
@@ -329,8 +507,8 @@ func extractMavenProjectName(pomPath string) string {
 	return string(matches[1])
 }
 
-func extractGradleProjectName(gradlePath string) string {
-	data, err := os.ReadFile(gradlePath)
+func (d *Detector) extractGradleProjectName(ctx context.Context, gradlePath string) string {
+	data, err := d.readFile(ctx, nil, gradlePath)
 	if err != nil {
 		return filepath.Base(filepath.Dir(gradlePath))
 	}
@@ -345,8 +523,8 @@ func extractGradleProjectName(gradlePath string) string {
 	return string(matches[1])
 }
 
-func extractPyProjectName(pyprojectPath string) string {
-	data, err := os.ReadFile(pyprojectPath)
+func (d *Detector) extractPyProjectName(ctx context.Context, pyprojectPath string) string {
+	data, err := d.readFile(ctx, nil, pyprojectPath)
 	if err != nil {
 		return ""
 	}
@@ -361,17 +539,14 @@ func extractPyProjectName(pyprojectPath string) string {
 	return string(matches[1])
 }
 
-func extractPythonPackageName(rootPath string) string {
+func (d *Detector) extractPythonPackageName(ctx context.Context, rootPath string) string {
 	// Look for setup.py or __init__.py to determine package name
 	setupPath := filepath.Join(rootPath, "setup.py")
-	if _, err := os.Stat(setupPath); err == nil {
-		data, err := os.ReadFile(setupPath)
-		if err == nil {
-			nameRegex := regexp.MustCompile(`name\s*=\s*["']([^"']+)["']`)
-			matches := nameRegex.FindSubmatch(data)
-			if len(matches) >= 2 {
-				return string(matches[1])
-			}
+	if data, err := d.readFile(ctx, nil, setupPath); err == nil {
+		nameRegex := regexp.MustCompile(`name\s*=\s*["']([^"']+)["']`)
+		matches := nameRegex.FindSubmatch(data)
+		if len(matches) >= 2 {
+			return string(matches[1])
 		}
 	}
 
@@ -379,8 +554,8 @@ func extractPythonPackageName(rootPath string) string {
 	return filepath.Base(rootPath)
 }
 
-func extractCargoProjectName(cargoPath string) string {
-	data, err := os.ReadFile(cargoPath)
+func (d *Detector) extractCargoProjectName(ctx context.Context, cargoPath string) string {
+	data, err := d.readFile(ctx, nil, cargoPath)
 	if err != nil {
 		return filepath.Base(filepath.Dir(cargoPath))
 	}
@@ -395,36 +570,29 @@ func extractCargoProjectName(cargoPath string) string {
 	return string(matches[1])
 }
 
-func extractGitProjectName(gitRoot string) string {
+func (d *Detector) extractSbtProjectName(ctx context.Context, sbtPath string) string {
+	data, err := d.readFile(ctx, nil, sbtPath)
+	if err != nil {
+		return filepath.Base(filepath.Dir(sbtPath))
+	}
+
+	// Extract the first name := "..." setting, e.g. ThisBuild / name or name within a project settings block
+	nameRegex := regexp.MustCompile(`\bname\s*:=\s*"([^"]+)"`)
+	matches := nameRegex.FindSubmatch(data)
+	if len(matches) < 2 {
+		return filepath.Base(filepath.Dir(sbtPath))
+	}
+
+	return string(matches[1])
+}
+
+func (d *Detector) extractGitProjectName(ctx context.Context, gitRoot string) string {
 	// Try to get the name from the origin remote
-	configPath := filepath.Join(gitRoot, ".git", "config")
-	if _, err := os.Stat(configPath); err == nil {
-		file, err := os.Open(configPath)
-		if err == nil {
-			defer file.Close()
-			scanner := bufio.NewScanner(file)
-			foundRemote := false
-
-			for scanner.Scan() {
-				line := strings.TrimSpace(scanner.Text())
-
-				if strings.Contains(line, "[remote \"origin\"]") {
-					foundRemote = true
-					continue
-				}
-
-				if foundRemote && strings.HasPrefix(line, "url = ") {
-					url := strings.TrimPrefix(line, "url = ")
-					// Extract repo name from URL
-					url = strings.TrimSuffix(url, ".git")
-					parts := strings.Split(url, "/")
-					if len(parts) > 0 {
-						return parts[len(parts)-1]
-					}
-					break
-				}
-			}
-		}
+	if url := d.extractGitOrigin(ctx, gitRoot); url != "" {
+		// Extract repo name from URL
+		url = strings.TrimSuffix(url, ".git")
+		parts := strings.Split(url, "/")
+		return parts[len(parts)-1]
 	}
 
 	// Fall back to directory name
@@ -442,6 +610,8 @@ func determineProjectType(marker string) string {
 		return "javascript"
 	case "Cargo.toml":
 		return "rust"
+	case "build.sbt":
+		return "scala"
 	case "pyproject.toml", "requirements.txt":
 		return "python"
 	case "Gemfile":
@@ -0,0 +1,93 @@
+package repository_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/afs"
+	"github.com/viant/linager/inspector/repository"
+)
+
+// uploadFiles writes files to a file system service
+func uploadFiles(t *testing.T, fs afs.Service, files map[string]string) {
+	for location, content := range files {
+		assert.NoError(t, fs.Upload(context.Background(), location, 0644, strings.NewReader(content)))
+	}
+}
+
+func TestDetector_DetectRepository(t *testing.T) {
+	ctx := context.Background()
+	fs := afs.NewFaker()
+	uploadFiles(t, fs, map[string]string{
+		"/detect/repo/.git/config":                      "[core]\n\tbare = false\n[remote \"origin\"]\n\turl = https://github.com/acme/shop.git\n",
+		"/detect/repo/.git/worktrees/feature/HEAD":      "ref: refs/heads/feature\n",
+		"/detect/repo/.git/worktrees/feature/commondir": "../..\n",
+		"/detect/repo/go.mod":                           "module github.com/acme/shop\n\ngo 1.23\n",
+		"/detect/repo/svc/svc.go":                       "package svc\n",
+		"/detect/feature/.git":                          "gitdir: /detect/repo/.git/worktrees/feature\n",
+		"/detect/feature/go.mod":                        "module github.com/acme/shop\n\ngo 1.23\n",
+		"/detect/feature/api/api.go":                    "package api\n",
+	})
+	detector := repository.NewWithFS(fs)
+
+	project, err := detector.DetectProject(ctx, "/detect/repo/svc/svc.go")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "go", project.Type)
+	assert.Equal(t, "github.com/acme/shop", project.Name)
+	assert.Equal(t, filepath.FromSlash("/detect/repo"), project.RootPath)
+	assert.Equal(t, "svc/svc.go", project.RelativePath)
+	if assert.NotNil(t, project.GoModule) {
+		assert.Equal(t, "github.com/acme/shop", project.GoModule.Mod.Path)
+	}
+
+	repo, err := detector.DetectRepository(ctx, "/detect/repo/svc/svc.go")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "git", repo.Kind)
+		assert.Equal(t, "https://github.com/acme/shop.git", repo.Origin)
+	}
+	worktree, err := detector.DetectRepository(ctx, "/detect/feature/api/api.go")
+	if assert.NoError(t, err) {
+		assert.Equal(t, filepath.FromSlash("/detect/feature"), worktree.Root)
+		assert.Equal(t, "https://github.com/acme/shop.git", worktree.Origin, "worktree reads the config of its common git directory")
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = repository.NewWithFS(fs).DetectProject(canceled, "/detect/repo/svc/svc.go")
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestDetector_CacheStats(t *testing.T) {
+	ctx := context.Background()
+	fs := afs.NewFaker()
+	files := map[string]string{"/cache/go.mod": "module example.com/cache\n\ngo 1.23\n"}
+	var paths []string
+	for i := 0; i < 20; i++ {
+		for j := 0; j < 10; j++ {
+			path := fmt.Sprintf("/cache/pkg%d/file%d.go", i, j)
+			files[path] = fmt.Sprintf("package pkg%d\n", i)
+			paths = append(paths, path)
+		}
+	}
+	uploadFiles(t, fs, files)
+	detector := repository.NewWithFS(fs)
+	for _, path := range paths {
+		project, err := detector.DetectProject(ctx, path)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, "example.com/cache", project.Name)
+	}
+	assert.Equal(t, repository.CacheStats{Hits: 180, Searches: 20}, detector.CacheStats(), "one search per unique directory")
+
+	detector.InvalidateCache()
+	_, err := detector.DetectProject(ctx, paths[0])
+	assert.NoError(t, err)
+	assert.Equal(t, 21, detector.CacheStats().Searches)
+}
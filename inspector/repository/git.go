@@ -0,0 +1,291 @@
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/viant/linager/inspector/graph"
+)
+
+// batchBlobSize is the largest sibling blob loaded together with a requested blob
+const batchBlobSize = 1 << 20
+
+// GitRunner runs a git command in a repository directory, passing stdin to the command and returning its stdout
+type GitRunner func(ctx context.Context, dir string, stdin []byte, args ...string) ([]byte, error)
+
+// ExecGit runs the git executable, see GitRunner
+func ExecGit(ctx context.Context, dir string, stdin []byte, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return output, nil
+}
+
+// GitObjectProvider provides sources of a git revision read from the object database of a bare or normal repository,
+// without a checkout; files of the revision tree are addressed by paths under Root as if the revision were checked out
+// there. Symlinks are neither followed nor walked and submodules are omitted.
+type GitObjectProvider struct {
+	Root     string // Absolute path of the repository the revision tree is addressed by
+	Revision string // Requested revision
+	Commit   string // Resolved commit ID
+	ctx      context.Context
+	runner   GitRunner
+	entries  map[string]*gitFileInfo   // tree entries by slash path relative to Root, the root tree is ""
+	children map[string][]*gitFileInfo // directory entries by slash path sorted by name
+	mux      sync.Mutex
+	blobs    map[string][]byte // blob content by object ID
+}
+
+// NewGitObjectProvider creates a provider of a revision of the repository at root, the git executable is used when
+// runner is nil
+func NewGitObjectProvider(ctx context.Context, root, revision string, runner GitRunner) (*GitObjectProvider, error) {
+	if runner == nil {
+		runner = ExecGit
+	}
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	output, err := runner(ctx, root, nil, "rev-parse", "--verify", "--quiet", revision+"^{commit}")
+	if err != nil {
+		return nil, graph.NotFoundError("revision", revision, err)
+	}
+	ret := &GitObjectProvider{
+		Root:     root,
+		Revision: revision,
+		Commit:   strings.TrimSpace(string(output)),
+		ctx:      ctx,
+		runner:   runner,
+		blobs:    map[string][]byte{},
+	}
+	if output, err = runner(ctx, root, nil, "ls-tree", "-r", "-t", "-l", "-z", ret.Commit); err != nil {
+		return nil, fmt.Errorf("failed to list tree of %s: %w", revision, err)
+	}
+	if err = ret.loadTree(output); err != nil {
+		return nil, fmt.Errorf("failed to list tree of %s: %w", revision, err)
+	}
+	return ret, nil
+}
+
+// loadTree indexes ls-tree -r -t -l -z output, entries are formatted as "mode type object size\tpath"
+func (p *GitObjectProvider) loadTree(output []byte) error {
+	p.entries = map[string]*gitFileInfo{"": {name: filepath.Base(p.Root), mode: os.ModeDir | 0755}}
+	p.children = map[string][]*gitFileInfo{}
+	for _, record := range bytes.Split(output, []byte{0}) {
+		if len(record) == 0 {
+			continue
+		}
+		header, name, ok := strings.Cut(string(record), "\t")
+		fields := strings.Fields(header)
+		if !ok || len(fields) != 4 {
+			return fmt.Errorf("invalid tree entry %q", record)
+		}
+		info := &gitFileInfo{name: path.Base(name), path: name, oid: fields[2]}
+		switch fields[0] {
+		case "040000":
+			info.mode = os.ModeDir | 0755
+		case "100644":
+			info.mode = 0644
+		case "100755":
+			info.mode = 0755
+		case "120000":
+			info.mode = os.ModeSymlink | 0777
+		default: // submodule commits
+			continue
+		}
+		if fields[3] != "-" {
+			size, err := strconv.ParseInt(fields[3], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid tree entry %q: %w", record, err)
+			}
+			info.size = size
+		}
+		p.entries[name] = info
+		p.children[parentKey(name)] = append(p.children[parentKey(name)], info)
+	}
+	for _, entries := range p.children {
+		sort.Slice(entries, func(i, j int) bool { return entries[i].name < entries[j].name })
+	}
+	return nil
+}
+
+// Stat returns file info of a tree entry, symlinks are not followed
+func (p *GitObjectProvider) Stat(location string) (os.FileInfo, error) {
+	info, err := p.lookup("stat", location)
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// ReadDir returns entries of a tree sorted by name
+func (p *GitObjectProvider) ReadDir(location string) ([]os.DirEntry, error) {
+	info, err := p.lookup("readdir", location)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: location, Err: fmt.Errorf("not a directory")}
+	}
+	children := p.children[info.path]
+	result := make([]os.DirEntry, len(children))
+	for i, child := range children {
+		result[i] = child
+	}
+	return result, nil
+}
+
+// ReadFile returns content of a blob; small blobs of the same tree are loaded together as packages are read file by file
+func (p *GitObjectProvider) ReadFile(location string) ([]byte, error) {
+	info, err := p.lookup("open", location)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, &fs.PathError{Op: "read", Path: location, Err: fmt.Errorf("is a directory")}
+	}
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	if content, ok := p.blobs[info.oid]; ok {
+		return content, nil
+	}
+	batch := []string{info.oid}
+	for _, sibling := range p.children[parentKey(info.path)] {
+		if sibling != info && sibling.mode.IsRegular() && sibling.size <= batchBlobSize && p.blobs[sibling.oid] == nil {
+			batch = append(batch, sibling.oid)
+		}
+	}
+	if err = p.loadBlobs(batch); err != nil {
+		return nil, &fs.PathError{Op: "read", Path: location, Err: err}
+	}
+	return p.blobs[info.oid], nil
+}
+
+// loadBlobs reads blobs with cat-file --batch, records are formatted as "object type size\ncontent\n"
+func (p *GitObjectProvider) loadBlobs(oids []string) error {
+	input := strings.Join(oids, "\n") + "\n"
+	output, err := p.runner(p.ctx, p.Root, []byte(input), "cat-file", "--batch")
+	if err != nil {
+		return err
+	}
+	reader := bufio.NewReader(bytes.NewReader(output))
+	for range oids {
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("invalid cat-file output: %w", err)
+		}
+		fields := strings.Fields(header)
+		if len(fields) != 3 {
+			return fmt.Errorf("invalid cat-file record %q", strings.TrimSpace(header))
+		}
+		size, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return fmt.Errorf("invalid cat-file record %q: %w", strings.TrimSpace(header), err)
+		}
+		content := make([]byte, size+1) // content is terminated by a new line
+		if _, err = io.ReadFull(reader, content); err != nil {
+			return fmt.Errorf("invalid cat-file output: %w", err)
+		}
+		p.blobs[fields[0]] = content[:size]
+	}
+	return nil
+}
+
+// Walk walks a tree like filepath.Walk in lexical order, symlinks are skipped
+func (p *GitObjectProvider) Walk(root string, fn filepath.WalkFunc) error {
+	info, err := p.lookup("lstat", root)
+	if err != nil {
+		err = fn(root, nil, err)
+	} else {
+		err = p.walk(root, info, fn)
+	}
+	if err == filepath.SkipDir || err == filepath.SkipAll {
+		return nil
+	}
+	return err
+}
+
+func (p *GitObjectProvider) walk(location string, info *gitFileInfo, fn filepath.WalkFunc) error {
+	if !info.IsDir() {
+		return fn(location, info, nil)
+	}
+	if err := fn(location, info, nil); err != nil {
+		return err
+	}
+	for _, child := range p.children[info.path] {
+		if !child.IsDir() && !child.mode.IsRegular() {
+			continue
+		}
+		if err := p.walk(filepath.Join(location, child.name), child, fn); err != nil && (!child.IsDir() || err != filepath.SkipDir) {
+			return err
+		}
+	}
+	return nil
+}
+
+// lookup returns the tree entry of a path, relative paths are resolved against the working directory like file paths
+func (p *GitObjectProvider) lookup(op, location string) (*gitFileInfo, error) {
+	abs, err := filepath.Abs(location)
+	if err != nil {
+		return nil, &fs.PathError{Op: op, Path: location, Err: err}
+	}
+	rel, err := filepath.Rel(p.Root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, &fs.PathError{Op: op, Path: location, Err: fs.ErrNotExist}
+	}
+	key := filepath.ToSlash(rel)
+	if key == "." {
+		key = ""
+	}
+	info, ok := p.entries[key]
+	if !ok {
+		return nil, &fs.PathError{Op: op, Path: location, Err: fs.ErrNotExist}
+	}
+	return info, nil
+}
+
+// parentKey returns the slash path of the tree holding an entry, the root tree is ""
+func parentKey(name string) string {
+	if parent := path.Dir(name); parent != "." {
+		return parent
+	}
+	return ""
+}
+
+// gitFileInfo is a tree entry, it implements both os.FileInfo and os.DirEntry
+type gitFileInfo struct {
+	name string
+	path string // slash path relative to the provider root
+	oid  string
+	mode os.FileMode
+	size int64
+}
+
+func (i *gitFileInfo) Name() string               { return i.name }
+func (i *gitFileInfo) Size() int64                { return i.size }
+func (i *gitFileInfo) Mode() os.FileMode          { return i.mode }
+func (i *gitFileInfo) ModTime() time.Time         { return time.Time{} }
+func (i *gitFileInfo) IsDir() bool                { return i.mode.IsDir() }
+func (i *gitFileInfo) Sys() interface{}           { return nil }
+func (i *gitFileInfo) Type() os.FileMode          { return i.mode.Type() }
+func (i *gitFileInfo) Info() (os.FileInfo, error) { return i, nil }
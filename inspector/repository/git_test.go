@@ -0,0 +1,80 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/linager/inspector/repository"
+)
+
+// commitFiles writes files into a git work tree and commits them
+func commitFiles(t *testing.T, root string, files map[string]string) {
+	for name, content := range files {
+		assert.NoError(t, os.MkdirAll(filepath.Join(root, filepath.Dir(name)), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(root, name), []byte(content), 0644))
+	}
+	for _, args := range [][]string{{"add", "-A"}, {"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "update"}} {
+		_, err := repository.ExecGit(context.Background(), root, nil, args...)
+		assert.NoError(t, err)
+	}
+}
+
+func TestGitObjectProvider(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	root := t.TempDir()
+	if _, err := repository.ExecGit(context.Background(), root, nil, "init", "-q"); !assert.NoError(t, err) {
+		return
+	}
+	commitFiles(t, root, map[string]string{"go.mod": "module example.com/app\n", "a/a.go": "package a\n", "a/b.go": "package a\n\nconst B = 1\n"})
+	commitFiles(t, root, map[string]string{"a/b.go": "package a\n\nconst B = 2\n", "c/c.go": "package c\n"})
+
+	var calls []string
+	runner := func(ctx context.Context, dir string, stdin []byte, args ...string) ([]byte, error) {
+		calls = append(calls, args[0])
+		return repository.ExecGit(ctx, dir, stdin, args...)
+	}
+	provider, err := repository.NewGitObjectProvider(context.Background(), root, "HEAD~1", runner)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var visited []string
+	err = provider.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relative, _ := filepath.Rel(root, path)
+		visited = append(visited, filepath.ToSlash(relative))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{".", "a", "a/a.go", "a/b.go", "go.mod"}, visited)
+
+	content, err := provider.ReadFile(filepath.Join(root, "a", "b.go"))
+	assert.NoError(t, err)
+	assert.Equal(t, "package a\n\nconst B = 1\n", string(content))
+	content, err = provider.ReadFile(filepath.Join(root, "a", "a.go"))
+	assert.NoError(t, err)
+	assert.Equal(t, "package a\n", string(content))
+	assert.Equal(t, []string{"rev-parse", "ls-tree", "cat-file"}, calls, "blobs of a tree are loaded together")
+
+	info, err := provider.Stat(filepath.Join(root, "a"))
+	assert.NoError(t, err)
+	assert.True(t, info.IsDir())
+	_, err = provider.Stat(filepath.Join(root, "c", "c.go"))
+	assert.True(t, errors.Is(err, fs.ErrNotExist), "files of later commits do not exist")
+	_, err = provider.ReadFile(filepath.Join(filepath.Dir(root), "outside.go"))
+	assert.True(t, errors.Is(err, fs.ErrNotExist))
+
+	_, err = repository.NewGitObjectProvider(context.Background(), root, "missing", nil)
+	assert.True(t, err != nil && strings.Contains(err.Error(), "missing"))
+}
@@ -16,4 +16,5 @@ type Project struct {
 	Name         string // Name of the project (extracted from config files)
 	RelativePath string // Path from project root to the specified file
 	GoModule     *modfile.Module
+	Composition  *CompositionReport // Optional composition scan of the project, see Scan
 }
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/viant/linager/inspector/graph"
+)
+
+// FileSystem provides sources of the local file system
+type FileSystem struct {
+	FollowSymlinks bool // Follow symlinks in walks, see Walk
+}
+
+// Stat returns file info of a local path
+func (f *FileSystem) Stat(path string) (os.FileInfo, error) {
+	return os.Stat(path)
+}
+
+// ReadDir returns entries of a local directory
+func (f *FileSystem) ReadDir(path string) ([]os.DirEntry, error) {
+	return os.ReadDir(path)
+}
+
+// ReadFile returns content of a local file
+func (f *FileSystem) ReadFile(path string) ([]byte, error) {
+	return os.ReadFile(path)
+}
+
+// Walk walks a local file tree, see Walk
+func (f *FileSystem) Walk(root string, fn filepath.WalkFunc) error {
+	return Walk(root, f.FollowSymlinks, fn)
+}
+
+// Sources returns the source provider of a configuration, the local file system when none is configured
+func Sources(config *graph.Config) graph.SourceProvider {
+	if config != nil && config.Sources != nil {
+		return config.Sources
+	}
+	return &FileSystem{FollowSymlinks: config != nil && config.FollowSymlinks}
+}
+
+// sourceLoader loads lazily loaded assets with a source provider
+type sourceLoader struct {
+	sources graph.SourceProvider
+}
+
+// Load reads content of an asset location
+func (l *sourceLoader) Load(ctx context.Context, location string) ([]byte, error) {
+	content, err := l.sources.ReadFile(location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load asset %s: %w", location, graph.NotFoundError("asset", location, err))
+	}
+	return content, nil
+}
+
+// NewSourceLoader creates an asset loader reading assets with a source provider
+func NewSourceLoader(sources graph.SourceProvider) graph.AssetLoader {
+	return &sourceLoader{sources: sources}
+}
+
+// readDir returns directory entries read with a source provider, local when sources is nil
+func readDir(sources graph.SourceProvider, path string) ([]os.DirEntry, error) {
+	if sources == nil {
+		return os.ReadDir(path)
+	}
+	return sources.ReadDir(path)
+}
+
+// stat returns file info read with a source provider, local when sources is nil
+func stat(sources graph.SourceProvider, path string) (os.FileInfo, error) {
+	if sources == nil {
+		return os.Stat(path)
+	}
+	return sources.Stat(path)
+}
+
+// newAsset creates an asset of a file read with a source provider, see graph.NewAsset
+func newAsset(sources graph.SourceProvider, path, importPath string, eagerSize int64) (*graph.Asset, error) {
+	if sources == nil {
+		return graph.NewAsset(path, importPath, eagerSize, nil)
+	}
+	info, err := sources.Stat(path)
+	if err != nil {
+		return nil, graph.NotFoundError("asset", path, err)
+	}
+	asset := &graph.Asset{Path: path, ImportPath: importPath, Size: info.Size()}
+	asset.SetLoader(NewSourceLoader(sources))
+	if info.Size() > eagerSize {
+		return asset, nil
+	}
+	if asset.Content, err = sources.ReadFile(path); err != nil {
+		return nil, graph.NotFoundError("asset", path, err)
+	}
+	asset.Size = int64(len(asset.Content))
+	asset.Hash, err = graph.Hash(asset.Content)
+	return asset, err
+}
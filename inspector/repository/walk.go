@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// Walk walks the file tree rooted at root like filepath.Walk, calling fn for directories and regular files in lexical
+// order; special files (sockets, devices, named pipes) are skipped and symlinks are followed only with followSymlinks.
+// Directories are visited once by their resolved absolute path, case folded on case-insensitive file systems, which
+// breaks symlink cycles and duplicate path spellings; paths passed to fn keep the spelling they were reached by.
+func Walk(root string, followSymlinks bool, fn filepath.WalkFunc) error {
+	info, err := os.Stat(root)
+	if err != nil {
+		err = fn(root, nil, err)
+	} else {
+		w := &walker{follow: followSymlinks, fn: fn, visited: map[string]bool{}, foldCase: isCaseInsensitive(root)}
+		err = w.walk(root, info)
+	}
+	if err == filepath.SkipDir || err == filepath.SkipAll {
+		return nil
+	}
+	return err
+}
+
+// walker holds the state of a Walk
+type walker struct {
+	follow   bool
+	foldCase bool
+	fn       filepath.WalkFunc
+	visited  map[string]bool
+}
+
+func (w *walker) walk(path string, info os.FileInfo) error {
+	if !info.IsDir() {
+		return w.fn(path, info, nil)
+	}
+	key := w.dirKey(path)
+	if w.visited[key] {
+		return nil
+	}
+	w.visited[key] = true
+	entries, err := os.ReadDir(path)
+	if err = w.fn(path, info, err); err != nil || entries == nil {
+		return err
+	}
+	for _, entry := range entries {
+		name := filepath.Join(path, entry.Name())
+		entryInfo, err := w.entryInfo(name, entry)
+		if err != nil {
+			if err = w.fn(name, nil, err); err != nil && err != filepath.SkipDir {
+				return err
+			}
+			continue
+		}
+		if entryInfo == nil {
+			continue
+		}
+		if err = w.walk(name, entryInfo); err != nil && (!entryInfo.IsDir() || err != filepath.SkipDir) {
+			return err
+		}
+	}
+	return nil
+}
+
+// entryInfo returns info of a directory or regular file entry, symlinks resolved when followed, nil for skipped entries
+func (w *walker) entryInfo(path string, entry os.DirEntry) (os.FileInfo, error) {
+	mode := entry.Type()
+	if mode&os.ModeSymlink != 0 {
+		if !w.follow {
+			return nil, nil
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, nil // dangling symlink
+		}
+		mode = info.Mode()
+		if mode.IsDir() || mode.IsRegular() {
+			return info, nil
+		}
+		return nil, nil
+	}
+	if !mode.IsDir() && !mode.IsRegular() {
+		return nil, nil
+	}
+	return entry.Info()
+}
+
+// dirKey returns the identity of a directory: its resolved absolute path
+func (w *walker) dirKey(path string) string {
+	key, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		key = path
+	}
+	if abs, err := filepath.Abs(key); err == nil {
+		key = abs
+	}
+	if w.foldCase {
+		key = strings.ToLower(key)
+	}
+	return key
+}
+
+// isCaseInsensitive reports whether the file system holding path resolves a case swapped spelling of the nearest path
+// element with letters to the same file
+func isCaseInsensitive(path string) bool {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	for {
+		base := filepath.Base(path)
+		if swapped := swapCase(base); swapped != base {
+			info, err := os.Stat(path)
+			if err != nil {
+				return false
+			}
+			other, err := os.Stat(filepath.Join(filepath.Dir(path), swapped))
+			return err == nil && os.SameFile(info, other)
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return false
+		}
+		path = parent
+	}
+}
+
+// swapCase swaps the case of letters
+func swapCase(text string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsUpper(r) {
+			return unicode.ToLower(r)
+		}
+		return unicode.ToUpper(r)
+	}, text)
+}
@@ -0,0 +1,51 @@
+package repository_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/linager/inspector/repository"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWalk(t *testing.T) {
+	root := t.TempDir()
+	for name, content := range map[string]string{"a/a.go": "package a\n", "Lib/lib.go": "package lib\n"} {
+		assert.NoError(t, os.MkdirAll(filepath.Join(root, filepath.Dir(name)), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(root, name), []byte(content), 0644))
+	}
+	if err := os.Symlink("..", filepath.Join(root, "a", "loop")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	external := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(external, "x.go"), []byte("package x\n"), 0644))
+	assert.NoError(t, os.Symlink(external, filepath.Join(root, "ext")))
+	// a second spelling of Lib, as seen on case-insensitive file systems
+	assert.NoError(t, os.Symlink("Lib", filepath.Join(root, "lib")))
+	if listener, err := net.Listen("unix", filepath.Join(root, "a", "socket.go")); err == nil {
+		defer listener.Close()
+	}
+
+	var testCases = []struct {
+		description    string
+		followSymlinks bool
+		expect         []string
+	}{
+		{description: "symlinks skipped", expect: []string{".", "Lib", "Lib/lib.go", "a", "a/a.go"}},
+		{description: "symlinks followed once", followSymlinks: true, expect: []string{".", "Lib", "Lib/lib.go", "a", "a/a.go", "ext", "ext/x.go"}},
+	}
+	for _, testCase := range testCases {
+		var visited []string
+		err := repository.Walk(root, testCase.followSymlinks, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			relative, _ := filepath.Rel(root, path)
+			visited = append(visited, filepath.ToSlash(relative))
+			return nil
+		})
+		assert.NoError(t, err, testCase.description)
+		assert.Equal(t, testCase.expect, visited, testCase.description)
+	}
+}
@@ -0,0 +1,345 @@
+package scala
+
+import (
+	"reflect"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/viant/linager/inspector/graph"
+)
+
+// declarations extracts declarations of a compilation unit into a file
+type declarations struct {
+	file              *graph.File
+	source            []byte
+	includeUnexported bool
+}
+
+// compilationUnit processes top level package clauses, imports and definitions
+func (d *declarations) compilationUnit(root *sitter.Node) {
+	var packages []string
+	for j := 0; j < int(root.NamedChildCount()); j++ {
+		switch node := root.NamedChild(j); node.Type() {
+		case "package_clause":
+			if name := node.ChildByFieldName("name"); name != nil {
+				packages = append(packages, d.text(name)) // chained package clauses, e.g. package com.acme; package shop
+				d.file.ImportPath = strings.Join(packages, ".")
+				d.file.Package = d.file.ImportPath[strings.LastIndex(d.file.ImportPath, ".")+1:]
+			}
+		case "import_declaration":
+			d.file.Imports = append(d.file.Imports, d.imports(node)...)
+		case "class_definition", "object_definition", "trait_definition":
+			d.addType(node, "")
+		case "function_definition", "function_declaration":
+			d.file.Functions = append(d.file.Functions, d.function(node, true))
+		case "val_definition", "var_definition":
+			d.addValue(node, "")
+		}
+	}
+}
+
+// imports returns imports of an import declaration, selectors {A, B => C} produce one import each
+func (d *declarations) imports(node *sitter.Node) []graph.Import {
+	var path []string
+	var result []graph.Import
+	for j := 0; j < int(node.ChildCount()); j++ {
+		child := node.Child(j)
+		switch {
+		case node.FieldNameForChild(j) == "path" && child.Type() == "identifier":
+			path = append(path, d.text(child))
+		case child.Type() == "namespace_selectors":
+			prefix := strings.Join(path, ".")
+			for k := 0; k < int(child.NamedChildCount()); k++ {
+				selector := child.NamedChild(k)
+				switch selector.Type() {
+				case "identifier":
+					name := d.text(selector)
+					result = append(result, graph.Import{Name: name, Path: prefix + "." + name})
+				case "arrow_renamed_identifier":
+					name, alias := selector.ChildByFieldName("name"), selector.ChildByFieldName("alias")
+					if name != nil && alias != nil {
+						result = append(result, graph.Import{Name: d.text(alias), Path: prefix + "." + d.text(name)})
+					}
+				}
+			}
+			return result
+		case child.Type() == "namespace_wildcard":
+			return append(result, graph.Import{Path: strings.Join(path, ".") + "._"})
+		}
+	}
+	if len(path) > 0 {
+		result = append(result, graph.Import{Name: path[len(path)-1], Path: strings.Join(path, ".")})
+	}
+	return result
+}
+
+// addType adds a class, case class, object or trait with its nested definitions, nested types are qualified by the
+// enclosing type name (e.g. Outer.Inner)
+func (d *declarations) addType(node *sitter.Node, parent string) {
+	name := node.ChildByFieldName("name")
+	if name == nil {
+		return
+	}
+	aType := &graph.Type{
+		Name:       d.text(name),
+		Kind:       reflect.Struct,
+		IsExported: isPublic(node),
+		Comment:    d.comment(node),
+		Location:   d.location(node),
+	}
+	if node.Type() == "trait_definition" {
+		aType.Kind = reflect.Interface
+	}
+	if parent != "" {
+		aType.Name = parent + "." + aType.Name
+		aType.ParentType = parent
+	}
+	if !d.includeUnexported && !aType.IsExported {
+		return
+	}
+	aType.Package, aType.PackagePath = d.file.Package, d.file.ImportPath
+	for j := 0; j < int(node.ChildCount()); j++ {
+		child := node.Child(j)
+		switch node.FieldNameForChild(j) {
+		case "type_parameters":
+			aType.TypeParams = d.typeParams(child)
+		case "class_parameters":
+			aType.Fields = append(aType.Fields, d.classParameters(child, isCase(node))...)
+		case "extend":
+			aType.Extends = d.extends(child)
+		}
+	}
+	d.file.Types = append(d.file.Types, aType)
+
+	body := node.ChildByFieldName("body")
+	if body == nil {
+		return
+	}
+	isObject := node.Type() == "object_definition"
+	for j := 0; j < int(body.NamedChildCount()); j++ {
+		switch member := body.NamedChild(j); member.Type() {
+		case "function_definition", "function_declaration":
+			method := d.function(member, isObject)
+			method.Receiver = aType.Name
+			if d.includeUnexported || method.IsExported {
+				aType.Methods = append(aType.Methods, method)
+			}
+		case "val_definition", "var_definition":
+			if isObject {
+				d.addValue(member, aType.Name)
+			}
+		case "class_definition", "object_definition", "trait_definition":
+			d.addType(member, aType.Name)
+		}
+	}
+}
+
+// classParameters returns fields of a class parameter list, plain class parameters are fields only when declared
+// with val or var
+func (d *declarations) classParameters(node *sitter.Node, isCase bool) []*graph.Field {
+	var fields []*graph.Field
+	for j := 0; j < int(node.NamedChildCount()); j++ {
+		param := node.NamedChild(j)
+		if param.Type() != "class_parameter" {
+			continue
+		}
+		if !isCase && !hasToken(param, "val", "var") {
+			continue
+		}
+		name := param.ChildByFieldName("name")
+		if name == nil {
+			continue
+		}
+		field := &graph.Field{
+			Name:       d.text(name),
+			Type:       d.typeOf(param.ChildByFieldName("type")),
+			IsExported: isPublic(param),
+			Location:   d.location(param),
+		}
+		if value := param.ChildByFieldName("default_value"); value != nil {
+			field.Default = d.text(value)
+			field.DefaultFrom = &graph.Location{Start: int(value.StartByte()), End: int(value.EndByte())}
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// function returns a def, the body is captured as raw text
+func (d *declarations) function(node *sitter.Node, isStatic bool) *graph.Function {
+	fn := &graph.Function{
+		IsExported: isPublic(node),
+		IsStatic:   isStatic,
+		Comment:    d.comment(node),
+		Location:   d.location(node),
+	}
+	if name := node.ChildByFieldName("name"); name != nil {
+		fn.Name = d.text(name)
+	}
+	for j := 0; j < int(node.ChildCount()); j++ {
+		child := node.Child(j)
+		switch node.FieldNameForChild(j) {
+		case "type_parameters":
+			fn.TypeParams = d.typeParams(child)
+		case "parameters":
+			fn.Parameters = append(fn.Parameters, d.parameters(child)...)
+		}
+	}
+	if result := node.ChildByFieldName("return_type"); result != nil {
+		fn.Results = []*graph.Parameter{{Type: d.typeOf(result)}}
+	}
+	fn.Signature = strings.TrimSpace(d.text(node))
+	if body := node.ChildByFieldName("body"); body != nil {
+		fn.Body = &graph.LocationNode{Text: d.text(body), Location: graph.Location{Start: int(body.StartByte()), End: int(body.EndByte())}}
+		fn.Signature = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(string(d.source[node.StartByte():body.StartByte()])), "="))
+	}
+	return fn
+}
+
+// parameters returns parameters of a parameter list
+func (d *declarations) parameters(node *sitter.Node) []*graph.Parameter {
+	var params []*graph.Parameter
+	for j := 0; j < int(node.NamedChildCount()); j++ {
+		param := node.NamedChild(j)
+		if param.Type() != "parameter" {
+			continue
+		}
+		parameter := &graph.Parameter{Type: d.typeOf(param.ChildByFieldName("type"))}
+		if name := param.ChildByFieldName("name"); name != nil {
+			parameter.Name = d.text(name)
+		}
+		if value := param.ChildByFieldName("default_value"); value != nil {
+			parameter.Default = d.text(value)
+		}
+		if parameter.Type != nil && strings.HasSuffix(parameter.Type.Name, "*") {
+			parameter.IsVariadic = true
+			parameter.Type.Name = strings.TrimSpace(strings.TrimSuffix(parameter.Type.Name, "*"))
+		}
+		params = append(params, parameter)
+	}
+	return params
+}
+
+// addValue adds a val as a constant or a var as a variable, owner qualifies object members (e.g. Config.Limit)
+func (d *declarations) addValue(node *sitter.Node, owner string) {
+	pattern := node.ChildByFieldName("pattern")
+	if pattern == nil || pattern.Type() != "identifier" {
+		return // destructuring patterns are not supported
+	}
+	name := d.text(pattern)
+	if owner != "" {
+		name = owner + "." + name
+	}
+	isExported := isPublic(node)
+	if !d.includeUnexported && !isExported {
+		return
+	}
+	var value string
+	if valueNode := node.ChildByFieldName("value"); valueNode != nil {
+		value = d.text(valueNode)
+	}
+	var comment string
+	if doc := d.comment(node); doc != nil {
+		comment = doc.Text
+	}
+	aType := d.typeOf(node.ChildByFieldName("type"))
+	if node.Type() == "val_definition" {
+		d.file.Constants = append(d.file.Constants, &graph.Constant{Name: name, Comment: comment, Value: value, Type: aType,
+			File: d.file, IsExported: isExported, Location: d.location(node)})
+		return
+	}
+	d.file.Variables = append(d.file.Variables, &graph.Variable{Name: name, Comment: comment, Value: value, Type: aType,
+		File: d.file, IsExported: isExported, Location: d.location(node)})
+}
+
+// typeParams returns type parameters, upper bounds are kept as constraints
+func (d *declarations) typeParams(node *sitter.Node) []*graph.TypeParam {
+	var params []*graph.TypeParam
+	for j := 0; j < int(node.ChildCount()); j++ {
+		if node.FieldNameForChild(j) != "name" {
+			continue
+		}
+		param := &graph.TypeParam{Name: d.text(node.Child(j))}
+		if next := node.Child(j + 1); next != nil && next.Type() == "upper_bound" {
+			param.Constraint = strings.TrimSpace(strings.TrimPrefix(d.text(next), "<:"))
+		}
+		params = append(params, param)
+	}
+	return params
+}
+
+// extends returns the parent class and mixed in traits of an extends clause
+func (d *declarations) extends(node *sitter.Node) []string {
+	var result []string
+	for j := 0; j < int(node.NamedChildCount()); j++ {
+		if child := node.NamedChild(j); child.Type() != "arguments" {
+			result = append(result, d.text(child))
+		}
+	}
+	return result
+}
+
+// typeOf returns a type with the declared type kept as raw text, nil for inferred types
+func (d *declarations) typeOf(node *sitter.Node) *graph.Type {
+	if node == nil {
+		return nil
+	}
+	raw := d.text(node)
+	return &graph.Type{Name: raw, RawName: raw}
+}
+
+// comment returns the doc comment preceding a definition
+func (d *declarations) comment(node *sitter.Node) *graph.LocationNode {
+	prev := node.PrevNamedSibling()
+	if prev == nil || prev.Type() != "block_comment" || !strings.HasPrefix(d.text(prev), "/**") {
+		return nil
+	}
+	return &graph.LocationNode{Text: d.text(prev), Location: graph.Location{Start: int(prev.StartByte()), End: int(prev.EndByte())}}
+}
+
+// location returns the location of a node
+func (d *declarations) location(node *sitter.Node) *graph.Location {
+	return &graph.Location{Start: int(node.StartByte()), End: int(node.EndByte()), Raw: d.text(node)}
+}
+
+// text returns node source text
+func (d *declarations) text(node *sitter.Node) string {
+	return node.Content(d.source)
+}
+
+// isPublic reports whether a definition has no private or protected access modifier
+func isPublic(node *sitter.Node) bool {
+	for j := 0; j < int(node.NamedChildCount()); j++ {
+		child := node.NamedChild(j)
+		if child.Type() != "modifiers" {
+			continue
+		}
+		for k := 0; k < int(child.NamedChildCount()); k++ {
+			if child.NamedChild(k).Type() == "access_modifier" {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isCase reports whether a class definition is a case class
+func isCase(node *sitter.Node) bool {
+	return hasToken(node, "case")
+}
+
+// hasToken reports whether a node has a direct anonymous child of one of the token types
+func hasToken(node *sitter.Node, tokens ...string) bool {
+	for j := 0; j < int(node.ChildCount()); j++ {
+		child := node.Child(j)
+		if child.IsNamed() {
+			continue
+		}
+		for _, token := range tokens {
+			if child.Type() == token {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,197 @@
+// Package scala provides a minimal Scala inspector: classes, case classes, objects and traits are extracted as types
+// with their members, bodies are kept as raw text without further analysis
+package scala
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/scala"
+	"github.com/viant/linager/inspector/graph"
+	"github.com/viant/linager/inspector/repository"
+	"github.com/viant/linager/inspector/treesitter"
+)
+
+const defaultFilename = "source.scala"
+
+// Inspector extracts types, functions, constants and variables of Scala sources
+type Inspector struct {
+	config *graph.Config
+	trees  *treesitter.Trees // retained parsed trees, nil unless Config.RetainTrees is set
+}
+
+// NewInspector creates a Scala inspector with the provided configuration
+func NewInspector(config *graph.Config) *Inspector {
+	if config == nil {
+		config = &graph.Config{IncludeUnexported: true}
+	}
+	ret := &Inspector{config: config}
+	if config.RetainTrees {
+		ret.trees = treesitter.NewTrees()
+	}
+	return ret
+}
+
+// InspectSource parses Scala source code from a byte slice
+func (i *Inspector) InspectSource(src []byte) (*graph.File, error) {
+	return i.inspect(defaultFilename, src)
+}
+
+// InspectFile parses a Scala source file
+func (i *Inspector) InspectFile(filename string) (*graph.File, error) {
+	src, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", filename, graph.NotFoundError("file", filename, err))
+	}
+	return i.inspect(filename, src)
+}
+
+// inspect parses source of a file
+func (i *Inspector) inspect(filename string, src []byte) (*graph.File, error) {
+	parser := sitter.NewParser()
+	parser.SetLanguage(scala.GetLanguage())
+	tree, err := treesitter.Parse(parser, src, i.config.ParseDeadline())
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &graph.File{Name: filepath.Base(filename), Path: filename, Warnings: []*graph.ParseWarning{treesitter.TimeoutWarning(filename, i.config.ParseDeadline())}}, nil
+	}
+	if err != nil {
+		return nil, &graph.ErrParse{Path: filename, Cause: err}
+	}
+	if i.trees != nil {
+		i.trees.Retain(filename, tree, src, scala.GetLanguage())
+	} else {
+		defer tree.Close()
+	}
+	file := &graph.File{Name: filepath.Base(filename), Path: filename}
+	file.SetSource(src)
+	d := &declarations{file: file, source: src, includeUnexported: i.config.IncludeUnexported}
+	d.compilationUnit(tree.RootNode())
+	return file, nil
+}
+
+// Query runs a tree-sitter query over the retained tree of a file (see graph.Config.RetainTrees)
+func (i *Inspector) Query(file string, query string) ([]treesitter.QueryMatch, error) {
+	if i.trees == nil {
+		return nil, &graph.ErrNotFound{Kind: "tree", Name: file}
+	}
+	return i.trees.Query(file, query)
+}
+
+// Close releases retained parsed trees
+func (i *Inspector) Close() error {
+	if i.trees == nil {
+		return nil
+	}
+	return i.trees.Close()
+}
+
+// InspectPackage inspects Scala files of a directory, the package import path is taken from package clauses
+func (i *Inspector) InspectPackage(packagePath string) (*graph.Package, error) {
+	absPath, err := filepath.Abs(packagePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	entries, err := os.ReadDir(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read package directory: %w", graph.NotFoundError("directory", absPath, err))
+	}
+	pkg := &graph.Package{Name: filepath.Base(absPath), FileSet: []*graph.File{}}
+	for _, entry := range entries {
+		filePath := filepath.Join(absPath, entry.Name())
+		if entry.IsDir() || filepath.Ext(filePath) != ".scala" {
+			continue
+		}
+		if i.config.SkipTests && graph.IsTestFile(entry.Name()) {
+			continue
+		}
+		skipped, err := i.config.CheckFile(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("error processing %s: %w", filePath, err)
+		}
+		if skipped != nil {
+			pkg.Skipped = append(pkg.Skipped, skipped)
+			continue
+		}
+		file, err := i.InspectFile(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("error processing %s: %w", filePath, err)
+		}
+		if file.ImportPath != "" {
+			pkg.ImportPath = file.ImportPath
+			pkg.Name = file.Package
+		}
+		pkg.AddFile(file)
+	}
+	if len(pkg.FileSet) == 0 && len(pkg.Skipped) == 0 {
+		return nil, fmt.Errorf("no Scala files found in package: %s", packagePath)
+	}
+	return pkg, nil
+}
+
+// InspectPackages inspects directories with Scala files recursively
+func (i *Inspector) InspectPackages(rootPath string) ([]*graph.Package, error) {
+	absPath, err := filepath.Abs(rootPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	var packages []*graph.Package
+	err = repository.Walk(absPath, i.config.FollowSymlinks, func(aPath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if aPath != absPath && (strings.HasPrefix(info.Name(), ".") || info.Name() == "target") {
+			return filepath.SkipDir
+		}
+		hasScalaFiles, err := repository.HasFileWithSuffixes(aPath, []string{".scala"}, nil)
+		if err != nil || !hasScalaFiles {
+			return err
+		}
+		pkg, err := i.InspectPackage(aPath)
+		if err != nil {
+			return fmt.Errorf("error inspecting package in %s: %w", aPath, err)
+		}
+		packages = append(packages, pkg)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error walking package directories: %w", err)
+	}
+	return packages, nil
+}
+
+// InspectProject inspects a Scala project (sbt build) rooted at or above location
+func (i *Inspector) InspectProject(location string) (*graph.Project, error) {
+	detector := repository.New()
+	project := &graph.Project{}
+	if info, err := detector.DetectProject(context.Background(), location); err == nil {
+		project.Name = info.Name
+		project.Type = info.Type
+		project.RootPath = info.RootPath
+		if info.RootPath != "" {
+			location = info.RootPath
+		}
+	}
+	if info, err := detector.DetectRepository(context.Background(), location); err == nil {
+		project.RepositoryURL = info.Origin
+	}
+	var err error
+	if project.Packages, err = i.InspectPackages(location); err != nil {
+		return nil, err
+	}
+	project.Init()
+	if i.config.Classifier != nil {
+		project.Classify(i.config.Classifier)
+	}
+	if i.config.Redactor != nil {
+		project.Redact(i.config.Redactor)
+	}
+	return project, nil
+}
@@ -0,0 +1,130 @@
+package scala_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/linager/inspector/graph"
+	"github.com/viant/linager/inspector/scala"
+	"reflect"
+	"testing"
+)
+
+const orderSource = `package com.acme
+package shop
+
+import scala.concurrent.{ExecutionContext => EC, Future}
+
+/** Order placed by a customer */
+case class Order(id: Long, customer: String, total: BigDecimal = 0) extends Entity with Serializable
+
+trait OrderRepository[T <: Entity] {
+  def find(id: Long): Future[Option[T]]
+  def save(order: T)(implicit ec: EC): Future[Unit]
+}
+
+object Orders {
+  val MaxItems: Int = 100
+  var created = 0
+
+  def create(customer: String, total: BigDecimal): Order = {
+    created += 1
+    Order(created, customer, total)
+  }
+
+  private def reset(): Unit = created = 0
+}
+`
+
+func TestInspector_InspectSource(t *testing.T) {
+	file, err := scala.NewInspector(nil).InspectSource([]byte(orderSource))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "com.acme.shop", file.ImportPath)
+	assert.Equal(t, "shop", file.Package)
+	assert.Equal(t, []graph.Import{{Name: "EC", Path: "scala.concurrent.ExecutionContext"}, {Name: "Future", Path: "scala.concurrent.Future"}}, file.Imports)
+
+	t.Run("case class", func(t *testing.T) {
+		order := file.LookupType("Order")
+		if !assert.NotNil(t, order) {
+			return
+		}
+		assert.Equal(t, reflect.Struct, order.Kind)
+		assert.Equal(t, "com.acme.shop", order.PackagePath)
+		assert.Equal(t, []string{"Entity", "Serializable"}, order.Extends)
+		assert.Equal(t, "/** Order placed by a customer */", order.Comment.Text)
+		var fields []string
+		for _, field := range order.Fields {
+			fields = append(fields, field.Name+" "+field.Type.Name)
+		}
+		assert.Equal(t, []string{"id Long", "customer String", "total BigDecimal"}, fields)
+		assert.Equal(t, "0", order.Fields[2].Default)
+	})
+
+	t.Run("object with defs", func(t *testing.T) {
+		orders := file.LookupType("Orders")
+		if !assert.NotNil(t, orders) || !assert.Len(t, orders.Methods, 2) {
+			return
+		}
+		assert.Equal(t, reflect.Struct, orders.Kind)
+		create := orders.Methods[0]
+		assert.Equal(t, "create", create.Name)
+		assert.True(t, create.IsExported)
+		assert.True(t, create.IsStatic)
+		assert.Equal(t, "def create(customer: String, total: BigDecimal): Order", create.Signature)
+		if assert.Len(t, create.Parameters, 2) {
+			assert.Equal(t, "customer", create.Parameters[0].Name)
+			assert.Equal(t, "BigDecimal", create.Parameters[1].Type.Name)
+		}
+		assert.Equal(t, "Order", create.Results[0].Type.Name)
+		assert.Contains(t, create.Body.Text, "created += 1")
+		reset := orders.Methods[1]
+		assert.Equal(t, "reset", reset.Name)
+		assert.False(t, reset.IsExported)
+		assert.Equal(t, "created = 0", reset.Body.Text)
+
+		if assert.Len(t, file.Constants, 1) {
+			assert.Equal(t, "Orders.MaxItems", file.Constants[0].Name)
+			assert.Equal(t, "100", file.Constants[0].Value)
+			assert.Equal(t, "Int", file.Constants[0].Type.Name)
+		}
+		if assert.Len(t, file.Variables, 1) {
+			assert.Equal(t, "Orders.created", file.Variables[0].Name)
+			assert.Nil(t, file.Variables[0].Type)
+		}
+	})
+
+	t.Run("trait", func(t *testing.T) {
+		repository := file.LookupType("OrderRepository")
+		if !assert.NotNil(t, repository) {
+			return
+		}
+		assert.Equal(t, reflect.Interface, repository.Kind)
+		assert.Equal(t, []*graph.TypeParam{{Name: "T", Constraint: "Entity"}}, repository.TypeParams)
+		if assert.Len(t, repository.Methods, 2) {
+			save := repository.Methods[1]
+			assert.Nil(t, save.Body)
+			assert.Equal(t, "OrderRepository", save.Receiver)
+			var params []string
+			for _, param := range save.Parameters {
+				params = append(params, param.Name+" "+param.Type.Name)
+			}
+			assert.Equal(t, []string{"order T", "ec EC"}, params)
+			assert.Equal(t, "Future[Unit]", save.Results[0].Type.Name)
+		}
+	})
+}
+
+func TestInspector_InspectProject(t *testing.T) {
+	project, err := scala.NewInspector(nil).InspectProject("testdata/shop/src/main/scala/com/acme/shop")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "shop-service", project.Name)
+	assert.Equal(t, "scala", project.Type)
+	if assert.Len(t, project.Packages, 1) {
+		pkg := project.Packages[0]
+		assert.Equal(t, "com.acme.shop", pkg.ImportPath)
+		assert.Equal(t, "shop", pkg.Name)
+		assert.Len(t, pkg.FileSet[0].Types, 3)
+	}
+}
@@ -0,0 +1,23 @@
+package com.acme.shop
+
+import scala.concurrent.{ExecutionContext, Future}
+
+/** Order placed by a customer */
+case class Order(id: Long, customer: String, total: BigDecimal = 0)
+
+trait OrderRepository {
+  def find(id: Long): Future[Option[Order]]
+  def save(order: Order)(implicit ec: ExecutionContext): Future[Unit]
+}
+
+object Orders {
+  val MaxItems: Int = 100
+  var created = 0
+
+  def create(customer: String, total: BigDecimal): Order = {
+    created += 1
+    Order(created, customer, total)
+  }
+
+  private def reset(): Unit = created = 0
+}
@@ -0,0 +1,118 @@
+package treesitter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/viant/linager/inspector/graph"
+)
+
+// Parse parses source with a deadline, no deadline when timeout is not positive; a parse exceeding the deadline
+// returns context.DeadlineExceeded
+func Parse(parser *sitter.Parser, source []byte, timeout time.Duration) (*sitter.Tree, error) {
+	// the parser timeout is set by SetOperationLimit in microseconds, a cancelable context could leave the
+	// cancellation flag of a reused parser set once the parse completed
+	limit := 0
+	if timeout > 0 {
+		limit = max(int(timeout.Microseconds()), 1)
+	}
+	parser.SetOperationLimit(limit)
+	tree, err := parser.ParseCtx(context.Background(), nil, source)
+	if errors.Is(err, sitter.ErrOperationLimit) {
+		parser.Reset() // an interrupted parse would otherwise be resumed by the next parse
+		return nil, context.DeadlineExceeded
+	}
+	return tree, err
+}
+
+// Budget limits the number of nodes visited by traversals of a file, a nil budget is unlimited
+type Budget struct {
+	limit   int
+	visited int
+}
+
+// NewBudget creates a node budget, limit 0 is unlimited (see graph.Config.NodeLimit)
+func NewBudget(limit int) *Budget {
+	return &Budget{limit: limit}
+}
+
+// Visit counts a visited node, it reports false once the budget is exhausted
+func (b *Budget) Visit() bool {
+	if b == nil || b.limit <= 0 {
+		return true
+	}
+	if b.visited >= b.limit {
+		return false
+	}
+	b.visited++
+	return true
+}
+
+// Exhausted reports whether a traversal was cut short by the budget
+func (b *Budget) Exhausted() bool {
+	return b != nil && b.limit > 0 && b.visited >= b.limit
+}
+
+// Walk visits named nodes of a subtree in pre-order with an explicit stack, visit returns false to skip children of
+// a node; it reports false when the budget was exhausted before the walk finished
+func (b *Budget) Walk(root *sitter.Node, visit func(node *sitter.Node) bool) bool {
+	if root == nil {
+		return true
+	}
+	stack := []*sitter.Node{root}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if !b.Visit() {
+			return false
+		}
+		if !visit(node) {
+			continue
+		}
+		for i := int(node.NamedChildCount()) - 1; i >= 0; i-- {
+			stack = append(stack, node.NamedChild(i))
+		}
+	}
+	return true
+}
+
+// Find returns the first named node of a subtree in pre-order satisfying match, nil when no node matches or the
+// budget is exhausted first
+func (b *Budget) Find(root *sitter.Node, match func(node *sitter.Node) bool) *sitter.Node {
+	if root == nil {
+		return nil
+	}
+	stack := []*sitter.Node{root}
+	for len(stack) > 0 && b.Visit() {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if match(node) {
+			return node
+		}
+		for i := int(node.NamedChildCount()) - 1; i >= 0; i-- {
+			stack = append(stack, node.NamedChild(i))
+		}
+	}
+	return nil
+}
+
+// TimeoutWarning returns the warning of a file whose parsing exceeded the deadline
+func TimeoutWarning(path string, timeout time.Duration) *graph.ParseWarning {
+	return &graph.ParseWarning{Path: path, Reason: graph.WarnReasonTimeout, Message: fmt.Sprintf("parsing exceeded %v", timeout)}
+}
+
+// Warning returns the warning of a file whose traversals exhausted the budget, nil when the budget was not exhausted
+func (b *Budget) Warning(path string) *graph.ParseWarning {
+	if !b.Exhausted() {
+		return nil
+	}
+	return &graph.ParseWarning{Path: path, Reason: graph.WarnReasonNodeLimit, Message: fmt.Sprintf("traversals exceeded %d nodes", b.limit)}
+}
+
+// Walk visits named nodes of a subtree in pre-order with an explicit stack and no budget, see Budget.Walk
+func Walk(root *sitter.Node, visit func(node *sitter.Node) bool) {
+	(*Budget)(nil).Walk(root, visit)
+}
@@ -0,0 +1,108 @@
+package treesitter
+
+import (
+	"fmt"
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/viant/linager/inspector/graph"
+	"sort"
+	"sync"
+)
+
+// QueryMatch represents a node captured by a tree-sitter query
+type QueryMatch struct {
+	Pattern   int    `json:"pattern"` // Index of the matched query pattern
+	Capture   string `json:"capture"` // Capture name, e.g. name for @name
+	Type      string `json:"type"`    // Captured node type
+	StartByte uint32 `json:"startByte"`
+	EndByte   uint32 `json:"endByte"`
+	Text      string `json:"text"`
+}
+
+// Trees retains parsed tree-sitter trees per file so that custom queries can run without re-parsing
+type Trees struct {
+	mux     sync.Mutex
+	entries map[string]*entry
+}
+
+type entry struct {
+	tree     *sitter.Tree
+	source   []byte
+	language *sitter.Language
+}
+
+// NewTrees creates retained trees registry
+func NewTrees() *Trees {
+	return &Trees{entries: map[string]*entry{}}
+}
+
+// Retain keeps a parsed tree with its source and grammar, a previously retained tree of the file is released
+func (t *Trees) Retain(file string, tree *sitter.Tree, source []byte, language *sitter.Language) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	if prev, ok := t.entries[file]; ok && prev.tree != tree {
+		prev.tree.Close()
+	}
+	t.entries[file] = &entry{tree: tree, source: source, language: language}
+}
+
+// Files returns files with retained trees
+func (t *Trees) Files() []string {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	result := make([]string, 0, len(t.entries))
+	for file := range t.entries {
+		result = append(result, file)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Query compiles a tree-sitter query with the file grammar and returns captures over the retained file tree
+func (t *Trees) Query(file string, query string) ([]QueryMatch, error) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	anEntry, ok := t.entries[file]
+	if !ok {
+		return nil, &graph.ErrNotFound{Kind: "tree", Name: file}
+	}
+	compiled, err := sitter.NewQuery([]byte(query), anEntry.language)
+	if err != nil {
+		return nil, fmt.Errorf("invalid query for %s: %w", file, err)
+	}
+	defer compiled.Close()
+	cursor := sitter.NewQueryCursor()
+	defer cursor.Close()
+	cursor.Exec(compiled, anEntry.tree.RootNode())
+
+	var result []QueryMatch
+	for {
+		match, ok := cursor.NextMatch()
+		if !ok {
+			break
+		}
+		match = cursor.FilterPredicates(match, anEntry.source)
+		for _, capture := range match.Captures {
+			node := capture.Node
+			result = append(result, QueryMatch{
+				Pattern:   int(match.PatternIndex),
+				Capture:   compiled.CaptureNameForId(capture.Index),
+				Type:      node.Type(),
+				StartByte: node.StartByte(),
+				EndByte:   node.EndByte(),
+				Text:      node.Content(anEntry.source),
+			})
+		}
+	}
+	return result, nil
+}
+
+// Close releases all retained trees
+func (t *Trees) Close() error {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	for file, anEntry := range t.entries {
+		anEntry.tree.Close()
+		delete(t.entries, file)
+	}
+	return nil
+}
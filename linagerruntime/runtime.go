@@ -0,0 +1,120 @@
+// Package linagerruntime records symbol accesses of programs instrumented for runtime lineage correlation, see
+// analyzer.Instrument; it depends on the standard library only.
+package linagerruntime
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// EnvSink names the environment variable selecting the sink of instrumented programs: stderr or a file path,
+// nothing is recorded when it is not set
+const EnvSink = "LINAGER_RUNTIME_SINK"
+
+// Touch kinds
+const (
+	KindCall  = "call"  // function entry
+	KindWrite = "write" // field write site
+)
+
+// Sink receives touched symbol references
+type Sink interface {
+	Touch(ref, kind string)
+}
+
+// SinkFunc adapts a callback to a Sink
+type SinkFunc func(ref, kind string)
+
+// Touch calls the callback
+func (f SinkFunc) Touch(ref, kind string) {
+	f(ref, kind)
+}
+
+// writerSink writes a kind<TAB>ref line per touch
+type writerSink struct {
+	writer io.Writer
+}
+
+// Touch writes a line in a single write, so that lines of a file opened in append mode are not interleaved
+func (s *writerSink) Touch(ref, kind string) {
+	_, _ = io.WriteString(s.writer, kind+"\t"+ref+"\n")
+}
+
+// NewWriterSink creates a sink writing kind<TAB>ref lines to writer, see ReadHits
+func NewWriterSink(writer io.Writer) Sink {
+	return &writerSink{writer: writer}
+}
+
+// NewStderrSink creates a sink writing kind<TAB>ref lines to standard error
+func NewStderrSink() Sink {
+	return NewWriterSink(os.Stderr)
+}
+
+// NewFileSink creates a sink appending kind<TAB>ref lines to a file, lines are written unbuffered so that
+// touches before os.Exit are kept
+func NewFileSink(path string) (Sink, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open runtime sink %s: %w", path, err)
+	}
+	return NewWriterSink(file), nil
+}
+
+var (
+	mux  sync.Mutex
+	sink Sink
+)
+
+func init() {
+	switch location := os.Getenv(EnvSink); location {
+	case "":
+	case "stderr":
+		sink = NewStderrSink()
+	default:
+		if fileSink, err := NewFileSink(location); err == nil {
+			sink = fileSink
+		} else {
+			fmt.Fprintln(os.Stderr, err)
+		}
+	}
+}
+
+// SetSink replaces the sink and returns the previous one, nil disables recording
+func SetSink(s Sink) Sink {
+	mux.Lock()
+	defer mux.Unlock()
+	previous := sink
+	sink = s
+	return previous
+}
+
+// Touch records an access of a canonical symbol reference, e.g. a data point ID, with the access kind
+func Touch(ref, kind string) {
+	mux.Lock()
+	defer mux.Unlock()
+	if sink != nil {
+		sink.Touch(ref, kind)
+	}
+}
+
+// ReadHits counts references of kind<TAB>ref lines, other lines (e.g. program output on stderr) are ignored
+func ReadHits(reader io.Reader) (map[string]int, error) {
+	result := map[string]int{}
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		kind, ref, ok := strings.Cut(scanner.Text(), "\t")
+		if !ok || ref == "" || (kind != KindCall && kind != KindWrite) {
+			continue
+		}
+		result[ref]++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read runtime hits: %w", err)
+	}
+	return result, nil
+}
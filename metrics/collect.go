@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/golang"
+	"github.com/smacker/go-tree-sitter/java"
+	"github.com/smacker/go-tree-sitter/javascript"
+	"github.com/viant/linager/analyzer"
+	"github.com/viant/linager/inspector"
+	"github.com/viant/linager/inspector/graph"
+	"github.com/viant/linager/inspector/repository"
+	"strings"
+)
+
+// grammars holds lineage grammars with matching source files by language name
+var grammars = map[string]struct {
+	language *sitter.Language
+	matcher  analyzer.MatcherFn
+}{
+	"go":         {language: golang.GetLanguage(), matcher: analyzer.GolangFiles},
+	"java":       {language: java.GetLanguage(), matcher: analyzer.JavaFiles},
+	"javascript": {language: javascript.GetLanguage(), matcher: analyzer.JSXFiles},
+}
+
+// Collect inspects the project under root and analyzes its lineage in a language (go by default), counters of both
+// runs are accumulated by the returned collector
+func Collect(ctx context.Context, root, language string, config *graph.Config, options ...analyzer.Option) (*Collector, error) {
+	if language == "" {
+		language = "go"
+	}
+	grammar, ok := grammars[strings.ToLower(language)]
+	if !ok {
+		return nil, &graph.ErrUnsupported{Language: language}
+	}
+	detected, err := repository.New().DetectProject(ctx, root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect project: %w", err)
+	}
+	project, err := inspector.NewFactory(config).InspectProject(&repository.Project{RootPath: root, Type: detected.Type, Name: detected.Name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect project %s: %w", root, err)
+	}
+	collector := NewCollector()
+	collector.ObserveProject(project)
+	options = append([]analyzer.Option{
+		analyzer.WithLanguage(grammar.language),
+		analyzer.WithMatcher(grammar.matcher),
+		analyzer.WithLanguageName(strings.ToLower(language)),
+		analyzer.WithPlugin(collector),
+	}, options...)
+	model, err := analyzer.NewAnalyzer(options...).AnalyzeAll(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	collector.ObserveModel(model)
+	return collector, nil
+}
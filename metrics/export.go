@@ -0,0 +1,96 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Export formats
+const (
+	FormatPrometheus  = "prom"        // Prometheus text exposition format 0.0.4
+	FormatOpenMetrics = "openmetrics" // OpenMetrics text format 1.0.0, e.g. for a Pushgateway
+	FormatJSON        = "json"        // Snapshot JSON with timestamp and project fingerprint
+)
+
+// ContentType returns the HTTP content type of an export format
+func ContentType(format string) string {
+	switch format {
+	case FormatPrometheus:
+		return "text/plain; version=0.0.4; charset=utf-8"
+	case FormatOpenMetrics:
+		return "application/openmetrics-text; version=1.0.0; charset=utf-8"
+	}
+	return "application/json"
+}
+
+// Export writes a snapshot in a format
+func (s *Snapshot) Export(writer io.Writer, format string) error {
+	switch format {
+	case FormatPrometheus:
+		_, err := io.WriteString(writer, s.exposition(false))
+		return err
+	case FormatOpenMetrics:
+		_, err := io.WriteString(writer, s.exposition(true))
+		return err
+	case FormatJSON:
+		data, err := json.MarshalIndent(s, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = writer.Write(append(data, '\n'))
+		return err
+	}
+	return fmt.Errorf("unsupported metrics format: %v", format)
+}
+
+// exposition renders metric families with HELP and TYPE metadata; OpenMetrics families of counters are named
+// without the _total suffix and the exposition ends with # EOF
+func (s *Snapshot) exposition(openMetrics bool) string {
+	builder := strings.Builder{}
+	family := ""
+	for _, sample := range s.Samples {
+		if sample.Name != family {
+			family = sample.Name
+			name, kind := sample.Name, "gauge"
+			if strings.HasSuffix(name, "_total") {
+				kind = "counter"
+				if openMetrics {
+					name = strings.TrimSuffix(name, "_total")
+				}
+			}
+			builder.WriteString("# HELP " + name + " " + help[sample.Name] + "\n")
+			builder.WriteString("# TYPE " + name + " " + kind + "\n")
+		}
+		builder.WriteString(sample.Name)
+		builder.WriteString(formatLabels(sample.Labels))
+		builder.WriteString(" ")
+		builder.WriteString(strconv.FormatFloat(sample.Value, 'g', -1, 64))
+		builder.WriteString("\n")
+	}
+	if openMetrics {
+		builder.WriteString("# EOF\n")
+	}
+	return builder.String()
+}
+
+// formatLabels renders sorted labels with escaped values, e.g. {kind="xfer"}; empty without labels
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, name+`="`+escaper.Replace(labels[name])+`"`)
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
@@ -0,0 +1,205 @@
+package metrics
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/viant/linager/analyzer"
+	"github.com/viant/linager/analyzer/linage"
+	"github.com/viant/linager/inspector/graph"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Metric names are stable across releases so that nightly runs chart as series; counters end with _total
+const (
+	// PackagesMetric counts inspected packages
+	PackagesMetric = "linager_packages_total"
+	// FilesMetric counts inspected source files
+	FilesMetric = "linager_files_total"
+	// TypesMetric counts declared types
+	TypesMetric = "linager_types_total"
+	// FunctionsMetric counts declared functions and methods
+	FunctionsMetric = "linager_functions_total"
+	// ComplexityMetric sums cyclomatic complexity of declared functions and methods
+	ComplexityMetric = "linager_complexity_total"
+	// AnalyzedFunctionsMetric counts function, method and constructor declarations walked by the analyzer
+	AnalyzedFunctionsMetric = "linager_analyzed_functions_total"
+	// IdentifiersMetric counts identifier resolutions of the analyzer
+	IdentifiersMetric = "linager_identifiers_total"
+	// EdgesMetric counts data flow edges, labeled by kind (read, write, call, xfer)
+	EdgesMetric = "linager_edges_total"
+	// DataPointsMetric counts lineage data points
+	DataPointsMetric = "linager_data_points_total"
+	// SensitiveFindingsMetric counts sinks reached by sensitive data (see analyzer.NewSensitivityReport)
+	SensitiveFindingsMetric = "linager_sensitive_findings_total"
+	// InfoMetric is a gauge of value 1 labeled with the project fingerprint
+	InfoMetric = "linager_info"
+	// TimestampMetric is a gauge holding the run time in Unix seconds
+	TimestampMetric = "linager_run_timestamp_seconds"
+)
+
+// help holds metric descriptions exported with Prometheus and OpenMetrics formats
+var help = map[string]string{
+	PackagesMetric:          "Inspected packages.",
+	FilesMetric:             "Inspected source files.",
+	TypesMetric:             "Declared types.",
+	FunctionsMetric:         "Declared functions and methods.",
+	ComplexityMetric:        "Cyclomatic complexity of declared functions and methods.",
+	AnalyzedFunctionsMetric: "Function declarations walked by the lineage analyzer.",
+	IdentifiersMetric:       "Identifier resolutions of the lineage analyzer.",
+	EdgesMetric:             "Data flow edges by kind.",
+	DataPointsMetric:        "Lineage data points.",
+	SensitiveFindingsMetric: "Sinks reached by sensitive data.",
+	InfoMetric:              "Analysis run information.",
+	TimestampMetric:         "Analysis run time in Unix seconds.",
+}
+
+// functionDeclarations are tree-sitter node types of declarations counted by AnalyzedFunctionsMetric
+var functionDeclarations = map[string]bool{
+	"function_declaration": true, "method_declaration": true, "constructor_declaration": true,
+}
+
+// Sample is a metric value with its labels
+type Sample struct {
+	Name   string            `json:"name"`
+	Labels map[string]string `json:"labels,omitempty"`
+	Value  float64           `json:"value"`
+}
+
+// key returns the sample identity: name and sorted labels
+func (s *Sample) key() string {
+	return s.Name + formatLabels(s.Labels)
+}
+
+// Snapshot holds metric values of a run
+type Snapshot struct {
+	Timestamp   time.Time `json:"timestamp"`
+	Fingerprint string    `json:"fingerprint,omitempty"`
+	Samples     []*Sample `json:"samples"`
+}
+
+// Collector accumulates counters of inspection and analysis runs. It is an analyzer.AnalyzerPlugin counting walked
+// declarations and resolved identifiers as the analyzer runs; inspected projects and analyzed models contribute
+// totals with ObserveProject and ObserveModel.
+type Collector struct {
+	mu          sync.Mutex
+	samples     map[string]*Sample
+	fingerprint string
+}
+
+// NewCollector creates a collector
+func NewCollector() *Collector {
+	return &Collector{samples: map[string]*Sample{}}
+}
+
+// Add adds value to a counter, labels are name, value pairs
+func (c *Collector) Add(name string, value float64, labels ...string) {
+	sample := &Sample{Name: name, Value: value}
+	for i := 0; i+1 < len(labels); i += 2 {
+		if sample.Labels == nil {
+			sample.Labels = map[string]string{}
+		}
+		sample.Labels[labels[i]] = labels[i+1]
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if existing, ok := c.samples[sample.key()]; ok {
+		existing.Value += value
+		return
+	}
+	c.samples[sample.key()] = sample
+}
+
+// Value returns a counter value
+func (c *Collector) Value(name string, labels ...string) float64 {
+	sample := &Sample{Name: name}
+	for i := 0; i+1 < len(labels); i += 2 {
+		if sample.Labels == nil {
+			sample.Labels = map[string]string{}
+		}
+		sample.Labels[labels[i]] = labels[i+1]
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if existing, ok := c.samples[sample.key()]; ok {
+		return existing.Value
+	}
+	return 0
+}
+
+// NodeTypes limits dispatch to function declarations
+func (c *Collector) NodeTypes() []string {
+	return []string{"function_declaration", "method_declaration", "constructor_declaration"}
+}
+
+// BeforeWalk counts function declarations walked by the analyzer
+func (c *Collector) BeforeWalk(n *sitter.Node, src []byte, scope *linage.Scope, model *linage.PackageModel) {
+	if functionDeclarations[n.Type()] {
+		c.Add(AnalyzedFunctionsMetric, 1)
+	}
+}
+
+// AfterResolveIdent counts identifier resolutions
+func (c *Collector) AfterResolveIdent(n *sitter.Node, id *linage.Identifier, scope *linage.Scope, model *linage.PackageModel) {
+	c.Add(IdentifiersMetric, 1)
+}
+
+// ObserveProject adds declaration totals of an inspected project and sets the project fingerprint
+func (c *Collector) ObserveProject(project *graph.Project) {
+	hash := sha256.New()
+	var entries []string
+	for _, pkg := range project.Packages {
+		c.Add(PackagesMetric, 1)
+		for _, file := range pkg.FileSet {
+			c.Add(FilesMetric, 1)
+			c.Add(TypesMetric, float64(len(file.Types)))
+			functions := file.Functions
+			for _, aType := range file.Types {
+				functions = append(functions, aType.Methods...)
+			}
+			checksum := fmt.Sprintf("%s:%d", file.Path, file.Lines)
+			for _, function := range functions {
+				c.Add(FunctionsMetric, 1)
+				c.Add(ComplexityMetric, float64(function.Complexity))
+				checksum += fmt.Sprintf(":%s=%d", function.Name, function.Hash)
+			}
+			entries = append(entries, checksum)
+		}
+	}
+	sort.Strings(entries)
+	hash.Write([]byte(strings.Join(entries, "\n")))
+	c.mu.Lock()
+	c.fingerprint = hex.EncodeToString(hash.Sum(nil))[:16]
+	c.mu.Unlock()
+}
+
+// ObserveModel adds edge, data point and sensitive finding totals of an analyzed model
+func (c *Collector) ObserveModel(model *linage.PackageModel) {
+	for _, edge := range model.DataFlows {
+		c.Add(EdgesMetric, 1, "kind", strings.ToLower(string(edge.Kind)))
+	}
+	c.Add(DataPointsMetric, float64(len(linage.NewDataPoints(model))))
+	c.Add(SensitiveFindingsMetric, float64(len(analyzer.NewSensitivityReport(model).Sinks)))
+}
+
+// Snapshot returns collected counters with run information gauges, ordered by name and labels
+func (c *Collector) Snapshot(timestamp time.Time) *Snapshot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ret := &Snapshot{Timestamp: timestamp, Fingerprint: c.fingerprint}
+	for _, sample := range c.samples {
+		copied := *sample
+		ret.Samples = append(ret.Samples, &copied)
+	}
+	info := &Sample{Name: InfoMetric, Value: 1}
+	if c.fingerprint != "" {
+		info.Labels = map[string]string{"fingerprint": c.fingerprint}
+	}
+	ret.Samples = append(ret.Samples, info, &Sample{Name: TimestampMetric, Value: float64(timestamp.Unix())})
+	sort.Slice(ret.Samples, func(i, j int) bool { return ret.Samples[i].key() < ret.Samples[j].key() })
+	return ret
+}
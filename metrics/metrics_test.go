@@ -0,0 +1,170 @@
+package metrics_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/smacker/go-tree-sitter/golang"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/linager/analyzer"
+	"github.com/viant/linager/inspector/graph"
+	"github.com/viant/linager/metrics"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const fixture = `package app
+
+type User struct {
+	Name string
+}
+
+func (u *User) Label() string {
+	if u.Name == "" {
+		return "anonymous"
+	}
+	return u.Name
+}
+
+func greet(u *User) string {
+	name := u.Label()
+	return "hi " + name
+}
+`
+
+var (
+	metadataLine = regexp.MustCompile(`^# (HELP|TYPE) ([a-zA-Z_:][a-zA-Z0-9_:]*) (.*)$`)
+	sampleLine   = regexp.MustCompile(`^([a-zA-Z_:][a-zA-Z0-9_:]*)(\{(?:[a-zA-Z_][a-zA-Z0-9_]*="(?:[^"\\]|\\.)*",?)*\})? (\S+)$`)
+)
+
+// parseExposition validates a text exposition: metadata precedes samples of its family, families are contiguous,
+// series are unique and values are numbers; it returns sample values by series
+func parseExposition(text string, openMetrics bool) (map[string]float64, error) {
+	values := map[string]float64{}
+	types := map[string]string{}
+	seen := map[string]bool{}
+	family := ""
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	eof := false
+	for scanner.Scan() {
+		line := scanner.Text()
+		if eof {
+			return nil, fmt.Errorf("content after # EOF: %q", line)
+		}
+		if line == "# EOF" && openMetrics {
+			eof = true
+			continue
+		}
+		if match := metadataLine.FindStringSubmatch(line); match != nil {
+			if match[1] == "TYPE" {
+				if _, ok := types[match[2]]; ok {
+					return nil, fmt.Errorf("duplicate TYPE of %v", match[2])
+				}
+				if match[3] != "counter" && match[3] != "gauge" {
+					return nil, fmt.Errorf("invalid type %q", match[3])
+				}
+				types[match[2]] = match[3]
+				family = match[2]
+			}
+			continue
+		}
+		match := sampleLine.FindStringSubmatch(line)
+		if match == nil {
+			return nil, fmt.Errorf("invalid line: %q", line)
+		}
+		name := match[1]
+		if openMetrics && types[family] == "counter" {
+			name = strings.TrimSuffix(name, "_total")
+		}
+		if name != family {
+			return nil, fmt.Errorf("sample %v outside of its family %v", match[1], family)
+		}
+		series := match[1] + match[2]
+		if seen[series] {
+			return nil, fmt.Errorf("duplicate series %v", series)
+		}
+		seen[series] = true
+		value, err := strconv.ParseFloat(match[3], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of %v: %w", series, err)
+		}
+		values[series] = value
+	}
+	if openMetrics && !eof {
+		return nil, fmt.Errorf("missing # EOF")
+	}
+	return values, nil
+}
+
+func TestCollect(t *testing.T) {
+	root := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/app\n\ngo 1.23\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "user.go"), []byte(fixture), 0644))
+	collector, err := metrics.Collect(context.Background(), root, "go", graph.DefaultConfig())
+	if !assert.NoError(t, err) {
+		return
+	}
+	model, err := analyzer.NewAnalyzer(analyzer.WithLanguage(golang.GetLanguage()), analyzer.WithMatcher(analyzer.GolangFiles)).AnalyzeAll(context.Background(), root)
+	if !assert.NoError(t, err) {
+		return
+	}
+	edges := map[string]float64{}
+	for _, edge := range model.DataFlows {
+		edges[`linager_edges_total{kind="`+strings.ToLower(string(edge.Kind))+`"}`]++
+	}
+
+	snapshot := collector.Snapshot(time.Unix(1700000000, 0))
+	buffer := &bytes.Buffer{}
+	assert.NoError(t, snapshot.Export(buffer, metrics.FormatPrometheus))
+	values, err := parseExposition(buffer.String(), false)
+	if !assert.NoError(t, err, buffer.String()) {
+		return
+	}
+	expect := map[string]float64{
+		metrics.PackagesMetric:          1,
+		metrics.FilesMetric:             1,
+		metrics.TypesMetric:             1,
+		metrics.FunctionsMetric:         2,
+		metrics.ComplexityMetric:        3,
+		metrics.AnalyzedFunctionsMetric: 2,
+		metrics.SensitiveFindingsMetric: 0,
+		metrics.TimestampMetric:         1700000000,
+		metrics.InfoMetric + `{fingerprint="` + snapshot.Fingerprint + `"}`: 1,
+	}
+	for series, value := range edges {
+		expect[series] = value
+	}
+	for series, value := range expect {
+		assert.Equal(t, value, values[series], series)
+	}
+	assert.NotEmpty(t, edges)
+	assert.Positive(t, values[metrics.IdentifiersMetric])
+	assert.Positive(t, values[metrics.DataPointsMetric])
+	assert.Len(t, snapshot.Fingerprint, 16)
+
+	buffer.Reset()
+	assert.NoError(t, snapshot.Export(buffer, metrics.FormatOpenMetrics))
+	openValues, err := parseExposition(buffer.String(), true)
+	assert.NoError(t, err, buffer.String())
+	assert.Equal(t, values, openValues)
+	assert.Contains(t, buffer.String(), "# TYPE linager_edges counter\n")
+
+	buffer.Reset()
+	assert.NoError(t, snapshot.Export(buffer, metrics.FormatJSON))
+	decoded := &metrics.Snapshot{}
+	assert.NoError(t, json.Unmarshal(buffer.Bytes(), decoded))
+	assert.Equal(t, snapshot.Fingerprint, decoded.Fingerprint)
+	assert.True(t, snapshot.Timestamp.Equal(decoded.Timestamp))
+	assert.Equal(t, len(snapshot.Samples), len(decoded.Samples))
+
+	assert.Error(t, snapshot.Export(buffer, "xml"))
+	_, err = metrics.Collect(context.Background(), root, "cobol", graph.DefaultConfig())
+	assert.Error(t, err)
+}
@@ -0,0 +1,343 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/viant/linager/analyzer/linage"
+	"github.com/viant/linager/inspector/graph"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type (
+	// InspectRequest requests a project inspection, a cached project is returned unless Refresh is set
+	InspectRequest struct {
+		Path    string `json:"path"`
+		Refresh bool   `json:"refresh,omitempty"`
+		Summary bool   `json:"summary,omitempty"` // Include exported symbol summaries of project files
+	}
+
+	// InspectResponse returns an inspected project with file summaries
+	InspectResponse struct {
+		*graph.Project
+		Summaries []*graph.FileSummary `json:"summaries"`
+	}
+
+	// DocumentsRequest requests project documents of packages with a path prefix, up to MaxSize bytes (0 for all)
+	DocumentsRequest struct {
+		Root    string `json:"root,omitempty"`
+		PkgPath string `json:"pkgPath"`
+		MaxSize int    `json:"maxSize,omitempty"`
+	}
+
+	// LineageRequest requests data points of sources under a path
+	LineageRequest struct {
+		Path     string `json:"path"`
+		Language string `json:"language,omitempty"` // go (default), java or javascript
+		Refresh  bool   `json:"refresh,omitempty"`
+	}
+
+	// PackageInfo summarizes a project package
+	PackageInfo struct {
+		Name       string   `json:"name"`
+		ImportPath string   `json:"importPath,omitempty"`
+		Files      []string `json:"files,omitempty"`
+	}
+
+	// TypeInfo describes a project type with its declaring package
+	TypeInfo struct {
+		Package    string      `json:"package"`
+		ImportPath string      `json:"importPath,omitempty"`
+		Type       *graph.Type `json:"type"`
+	}
+)
+
+func (s *Server) routes() {
+	s.mux.HandleFunc("POST /inspect", s.handleInspect)
+	s.mux.HandleFunc("GET /project/packages", s.handlePackages)
+	s.mux.HandleFunc("GET /project/types", s.handleTypes)
+	s.mux.HandleFunc("POST /documents", s.handleDocuments)
+	s.mux.HandleFunc("POST /lineage", s.handleLineage)
+	s.mux.HandleFunc("GET /symbol", s.handleSymbol)
+}
+
+// handleInspect inspects a project and returns graph.Project, with file summaries when requested
+func (s *Server) handleInspect(w http.ResponseWriter, r *http.Request) {
+	request := &InspectRequest{}
+	if err := decode(r, request); err != nil {
+		writeError(w, err)
+		return
+	}
+	root, err := s.resolve(request.Path)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	project, err := s.project(root, request.Refresh)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if request.Summary {
+		writeJSON(w, &InspectResponse{Project: project, Summaries: project.Summaries()})
+		return
+	}
+	writeJSON(w, project)
+}
+
+// handlePackages lists packages of the project at the root query parameter
+func (s *Server) handlePackages(w http.ResponseWriter, r *http.Request) {
+	project, err := s.queryProject(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	result := make([]*PackageInfo, 0, len(project.Packages))
+	for _, pkg := range project.Packages {
+		info := &PackageInfo{Name: pkg.Name, ImportPath: pkg.ImportPath}
+		for _, file := range pkg.FileSet {
+			info.Files = append(info.Files, file.Path)
+		}
+		result = append(result, info)
+	}
+	writeJSON(w, result)
+}
+
+// handleTypes returns project types with the name query parameter
+func (s *Server) handleTypes(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		writeError(w, &requestError{message: "name parameter is required"})
+		return
+	}
+	project, err := s.queryProject(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	var result []*TypeInfo
+	for _, pkg := range project.Packages {
+		for _, file := range pkg.FileSet {
+			if aType := file.LookupType(name); aType != nil {
+				result = append(result, &TypeInfo{Package: pkg.Name, ImportPath: pkg.ImportPath, Type: aType})
+			}
+		}
+	}
+	if len(result) == 0 {
+		writeError(w, &graph.ErrNotFound{Kind: "type", Name: name})
+		return
+	}
+	writeJSON(w, result)
+}
+
+// errLimitReached stops document streaming once the requested size is reached
+var errLimitReached = errors.New("document size limit reached")
+
+// handleDocuments streams project documents as a JSON array
+func (s *Server) handleDocuments(w http.ResponseWriter, r *http.Request) {
+	request := &DocumentsRequest{}
+	if err := decode(r, request); err != nil {
+		writeError(w, err)
+		return
+	}
+	if request.MaxSize < 0 {
+		writeError(w, &requestError{message: "maxSize must not be negative"})
+		return
+	}
+	root, err := s.resolve(request.Root)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	project, err := s.project(root, false)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if request.PkgPath != "" && !hasPackagePath(project, request.PkgPath) {
+		writeError(w, &graph.ErrNotFound{Kind: "package", Name: request.PkgPath})
+		return
+	}
+	stream := newArrayStream(w)
+	size := 0
+	err = project.CreateDocumentsStream(r.Context(), request.PkgPath, func(doc *graph.Document) error {
+		// same limit as Documents.FilterBySize
+		if size += doc.Size(); request.MaxSize > 0 && size >= request.MaxSize {
+			return errLimitReached
+		}
+		return stream.write(doc)
+	})
+	if errors.Is(err, errLimitReached) {
+		err = nil
+	}
+	stream.close(err)
+}
+
+// handleLineage streams data points of analyzed sources as a JSON array
+func (s *Server) handleLineage(w http.ResponseWriter, r *http.Request) {
+	request := &LineageRequest{}
+	if err := decode(r, request); err != nil {
+		writeError(w, err)
+		return
+	}
+	root, err := s.resolve(request.Path)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	result, err := s.lineage(r.Context(), root, request.Language, request.Refresh)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	stream := newArrayStream(w)
+	for _, point := range result.points {
+		if err = stream.write(point); err != nil {
+			break
+		}
+	}
+	stream.close(err)
+}
+
+// handleSymbol returns the data point of the symbol at a byte offset of a file relative to the root
+func (s *Server) handleSymbol(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	file := query.Get("file")
+	offset, err := strconv.Atoi(query.Get("offset"))
+	if file == "" || err != nil || offset < 0 {
+		writeError(w, &requestError{message: "file and non-negative offset parameters are required"})
+		return
+	}
+	root, err := s.resolve(query.Get("root"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if _, err = s.resolve(filepath.Join(root, file)); err != nil {
+		writeError(w, err)
+		return
+	}
+	result, err := s.lineage(r.Context(), root, query.Get("language"), false)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	symbol := result.model.SymbolAt(file, offset)
+	if symbol == nil {
+		writeError(w, &graph.ErrNotFound{Kind: "symbol", Name: fmt.Sprintf("%s:%d", file, offset)})
+		return
+	}
+	for _, point := range result.points {
+		if point.ID == symbol.ID {
+			writeJSON(w, point)
+			return
+		}
+	}
+	writeJSON(w, &linage.DataPoint{Identifier: *symbol})
+}
+
+// queryProject returns the project of the root query parameter
+func (s *Server) queryProject(r *http.Request) (*graph.Project, error) {
+	root, err := s.resolve(r.URL.Query().Get("root"))
+	if err != nil {
+		return nil, err
+	}
+	return s.project(root, false)
+}
+
+// hasPackagePath reports whether any project package file has a path prefix
+func hasPackagePath(project *graph.Project, pkgPath string) bool {
+	for _, pkg := range project.Packages {
+		for _, file := range pkg.FileSet {
+			if strings.HasPrefix(file.Path, pkgPath) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// decode reads a JSON request body
+func decode(r *http.Request, target interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(target); err != nil {
+		return &requestError{message: fmt.Sprintf("invalid request body: %v", err)}
+	}
+	return nil
+}
+
+// writeJSON encodes a response directly to the response writer
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+// errorResponse is the body of failed requests
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// writeError maps typed errors to HTTP status codes: invalid requests to 400, missing elements to 404,
+// unparsable sources and unsupported languages to 422
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	var reqErr *requestError
+	switch {
+	case errors.As(err, &reqErr):
+		status = http.StatusBadRequest
+	case errors.Is(err, &graph.ErrNotFound{}):
+		status = http.StatusNotFound
+	case errors.Is(err, &graph.ErrParse{}), errors.Is(err, &graph.ErrUnsupported{}):
+		status = http.StatusUnprocessableEntity
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(&errorResponse{Error: err.Error()})
+}
+
+// arrayStream writes JSON array elements as they are produced, flushing each element
+type arrayStream struct {
+	w       http.ResponseWriter
+	encoder *json.Encoder
+	count   int
+}
+
+func newArrayStream(w http.ResponseWriter) *arrayStream {
+	return &arrayStream{w: w, encoder: json.NewEncoder(w)}
+}
+
+func (s *arrayStream) write(value interface{}) error {
+	separator := ","
+	if s.count == 0 {
+		s.w.Header().Set("Content-Type", "application/json")
+		separator = "["
+	}
+	s.count++
+	if _, err := s.w.Write([]byte(separator)); err != nil {
+		return err
+	}
+	if err := s.encoder.Encode(value); err != nil {
+		return err
+	}
+	if flusher, ok := s.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return nil
+}
+
+// close terminates the array, errors before the first element are reported with their status code while later errors
+// truncate the array
+func (s *arrayStream) close(err error) {
+	if s.count == 0 {
+		if err != nil {
+			writeError(s.w, err)
+			return
+		}
+		s.w.Header().Set("Content-Type", "application/json")
+		_, _ = s.w.Write([]byte("[]\n"))
+		return
+	}
+	_, _ = s.w.Write([]byte("]\n"))
+}
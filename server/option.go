@@ -0,0 +1,20 @@
+package server
+
+import "github.com/viant/linager/inspector/graph"
+
+// Option represents a Server option
+type Option func(*Server)
+
+// WithConfig sets the inspector configuration used to load projects
+func WithConfig(config *graph.Config) Option {
+	return func(s *Server) {
+		s.config = config
+	}
+}
+
+// WithInterprocedural enables inter-procedural lineage analysis of /lineage and /symbol requests
+func WithInterprocedural() Option {
+	return func(s *Server) {
+		s.interprocedural = true
+	}
+}
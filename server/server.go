@@ -0,0 +1,206 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/golang"
+	"github.com/smacker/go-tree-sitter/java"
+	"github.com/smacker/go-tree-sitter/javascript"
+	"github.com/viant/linager/analyzer"
+	"github.com/viant/linager/analyzer/linage"
+	"github.com/viant/linager/inspector"
+	"github.com/viant/linager/inspector/graph"
+	"github.com/viant/linager/inspector/repository"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Server exposes project inspection, documents and data lineage over JSON HTTP endpoints; loaded projects and
+// lineage models are cached per root until refreshed
+type Server struct {
+	root            string
+	config          *graph.Config
+	interprocedural bool
+	mux             *http.ServeMux
+	httpServer      *http.Server
+	mu              sync.Mutex
+	projects        map[string]*graph.Project
+	lineages        map[string]*lineage // root|language -> analyzed model
+}
+
+// lineage holds an analyzed model with its data points
+type lineage struct {
+	model  *linage.PackageModel
+	points []*linage.DataPoint
+}
+
+// language describes a tree-sitter grammar with matching source files
+type language struct {
+	grammar *sitter.Language
+	matcher analyzer.MatcherFn
+	name    string
+}
+
+// languages lists lineage languages by request name
+var languages = map[string]*language{
+	"go":         {grammar: golang.GetLanguage(), matcher: analyzer.GolangFiles, name: "go"},
+	"golang":     {grammar: golang.GetLanguage(), matcher: analyzer.GolangFiles, name: "go"},
+	"java":       {grammar: java.GetLanguage(), matcher: analyzer.JavaFiles, name: "java"},
+	"javascript": {grammar: javascript.GetLanguage(), matcher: analyzer.JSXFiles, name: "javascript"},
+	"jsx":        {grammar: javascript.GetLanguage(), matcher: analyzer.JSXFiles, name: "javascript"},
+}
+
+// requestError reports an invalid request, it is mapped to 400 Bad Request
+type requestError struct {
+	message string
+}
+
+// Error returns error message
+func (e *requestError) Error() string {
+	return e.message
+}
+
+// New creates a server for projects under root, request paths are resolved relative to root
+func New(root string, options ...Option) *Server {
+	if absolute, err := filepath.Abs(root); err == nil {
+		root = absolute
+	}
+	s := &Server{
+		root:     root,
+		mux:      http.NewServeMux(),
+		projects: map[string]*graph.Project{},
+		lineages: map[string]*lineage{},
+	}
+	for _, option := range options {
+		option(s)
+	}
+	s.routes()
+	s.httpServer = &http.Server{Handler: s.mux}
+	return s
+}
+
+// Handler returns the HTTP handler of server endpoints
+func (s *Server) Handler() http.Handler {
+	return s.mux
+}
+
+// ListenAndServe serves requests on addr until Shutdown is called
+func (s *Server) ListenAndServe(addr string) error {
+	s.httpServer.Addr = addr
+	if err := s.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Shutdown gracefully stops the server, in-flight requests complete unless ctx expires first
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.httpServer.Shutdown(ctx)
+}
+
+// Refresh drops cached project and lineage models of a root, they are reloaded on the next request
+func (s *Server) Refresh(root string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.projects, root)
+	for key := range s.lineages {
+		if strings.HasPrefix(key, root+"|") {
+			delete(s.lineages, key)
+		}
+	}
+}
+
+// resolve returns absolute location of a request path, paths outside the server root are rejected
+func (s *Server) resolve(location string) (string, error) {
+	if location == "" {
+		return s.root, nil
+	}
+	if !filepath.IsAbs(location) {
+		location = filepath.Join(s.root, location)
+	}
+	location = filepath.Clean(location)
+	if relative, err := filepath.Rel(s.root, location); err != nil || relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+		return "", &requestError{message: fmt.Sprintf("path %s is outside of %s", location, s.root)}
+	}
+	if _, err := os.Stat(location); err != nil {
+		return "", graph.NotFoundError("path", location, err)
+	}
+	return location, nil
+}
+
+// project returns cached or newly inspected project of a root
+func (s *Server) project(root string, refresh bool) (*graph.Project, error) {
+	if refresh {
+		s.Refresh(root)
+	}
+	s.mu.Lock()
+	project, ok := s.projects[root]
+	s.mu.Unlock()
+	if ok {
+		return project, nil
+	}
+	detected, err := repository.New().DetectProject(context.Background(), root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect project: %w", err)
+	}
+	repoProject := &repository.Project{RootPath: root, Type: detected.Type, Name: detected.Name}
+	if project, err = inspector.NewFactory(s.config).InspectProject(repoProject); err != nil {
+		return nil, fmt.Errorf("failed to inspect project %s: %w", root, err)
+	}
+	prepare(project)
+	s.mu.Lock()
+	s.projects[root] = project
+	s.mu.Unlock()
+	return project, nil
+}
+
+// prepare builds lazily indexed types, functions and file summaries up front, so that concurrent requests sharing a
+// cached project only read it
+func prepare(project *graph.Project) {
+	for _, pkg := range project.Packages {
+		for _, file := range pkg.FileSet {
+			file.IndexTypes()
+			file.IndexFunctions()
+		}
+	}
+	project.Summaries()
+}
+
+// lineage returns cached or newly analyzed lineage model of a root
+func (s *Server) lineage(ctx context.Context, root, languageName string, refresh bool) (*lineage, error) {
+	if languageName == "" {
+		languageName = "go"
+	}
+	lang, ok := languages[strings.ToLower(languageName)]
+	if !ok {
+		return nil, &graph.ErrUnsupported{Language: languageName}
+	}
+	key := root + "|" + lang.name
+	s.mu.Lock()
+	if refresh {
+		delete(s.lineages, key)
+	}
+	result, ok := s.lineages[key]
+	s.mu.Unlock()
+	if ok {
+		return result, nil
+	}
+	options := []analyzer.Option{analyzer.WithLanguage(lang.grammar), analyzer.WithMatcher(lang.matcher), analyzer.WithLanguageName(lang.name)}
+	if s.interprocedural {
+		options = append(options, analyzer.WithInterprocedural())
+	}
+	model, err := analyzer.NewAnalyzer(options...).AnalyzeAll(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	result = &lineage{model: model, points: linage.NewDataPoints(model)}
+	s.mu.Lock()
+	s.lineages[key] = result
+	s.mu.Unlock()
+	return result, nil
+}
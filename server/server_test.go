@@ -0,0 +1,298 @@
+package server_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/linager/analyzer/linage"
+	"github.com/viant/linager/inspector/graph"
+	"github.com/viant/linager/server"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testRoot = "../inspector/golang/testdata"
+
+func TestServer_Endpoints(t *testing.T) {
+	source, err := os.ReadFile(testRoot + "/app/main.go")
+	if !assert.NoError(t, err) {
+		return
+	}
+	topOffset := strconv.Itoa(strings.Index(string(source), "top, _"))
+
+	httpServer := httptest.NewServer(server.New(testRoot).Handler())
+	defer httpServer.Close()
+
+	testCases := []struct {
+		description  string
+		method       string
+		path         string
+		body         string
+		expectStatus int
+		check        func(t *testing.T, body []byte)
+	}{
+		{
+			description:  "inspect project",
+			method:       http.MethodPost,
+			path:         "/inspect",
+			body:         `{"path":""}`,
+			expectStatus: http.StatusOK,
+			check: func(t *testing.T, body []byte) {
+				project := &graph.Project{}
+				assert.NoError(t, json.Unmarshal(body, project))
+				assert.NotEmpty(t, project.Packages)
+			},
+		},
+		{
+			description:  "inspect project with summaries",
+			method:       http.MethodPost,
+			path:         "/inspect",
+			body:         `{"path":"","summary":true}`,
+			expectStatus: http.StatusOK,
+			check: func(t *testing.T, body []byte) {
+				response := &server.InspectResponse{}
+				assert.NoError(t, json.Unmarshal(body, response))
+				assert.NotEmpty(t, response.Packages)
+				var types []string
+				for _, summary := range response.Summaries {
+					types = append(types, summary.Types...)
+				}
+				assert.Contains(t, types, "Stack")
+			},
+		},
+		{
+			description:  "inspect missing path",
+			method:       http.MethodPost,
+			path:         "/inspect",
+			body:         `{"path":"missing"}`,
+			expectStatus: http.StatusNotFound,
+		},
+		{
+			description:  "inspect path outside root",
+			method:       http.MethodPost,
+			path:         "/inspect",
+			body:         `{"path":"../../"}`,
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			description:  "inspect invalid body",
+			method:       http.MethodPost,
+			path:         "/inspect",
+			body:         `{"path":`,
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			description:  "list packages",
+			method:       http.MethodGet,
+			path:         "/project/packages",
+			expectStatus: http.StatusOK,
+			check: func(t *testing.T, body []byte) {
+				var packages []*server.PackageInfo
+				assert.NoError(t, json.Unmarshal(body, &packages))
+				var names []string
+				for _, pkg := range packages {
+					names = append(names, pkg.Name)
+				}
+				assert.Contains(t, names, "stack")
+				assert.Contains(t, names, "main")
+			},
+		},
+		{
+			description:  "lookup type",
+			method:       http.MethodGet,
+			path:         "/project/types?name=Stack",
+			expectStatus: http.StatusOK,
+			check: func(t *testing.T, body []byte) {
+				var types []*server.TypeInfo
+				assert.NoError(t, json.Unmarshal(body, &types))
+				if assert.Len(t, types, 1) {
+					assert.Equal(t, "stack", types[0].Package)
+					assert.Equal(t, "Stack", types[0].Type.Name)
+				}
+			},
+		},
+		{
+			description:  "lookup missing type",
+			method:       http.MethodGet,
+			path:         "/project/types?name=Queue",
+			expectStatus: http.StatusNotFound,
+		},
+		{
+			description:  "lookup type without name",
+			method:       http.MethodGet,
+			path:         "/project/types",
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			description:  "create documents",
+			method:       http.MethodPost,
+			path:         "/documents",
+			body:         `{"pkgPath":"stack"}`,
+			expectStatus: http.StatusOK,
+			check: func(t *testing.T, body []byte) {
+				var documents graph.Documents
+				assert.NoError(t, json.Unmarshal(body, &documents))
+				assert.NotEmpty(t, documents)
+				for _, doc := range documents {
+					assert.True(t, strings.HasPrefix(doc.Path, "stack"), doc.Path)
+				}
+			},
+		},
+		{
+			description:  "create documents limited by size",
+			method:       http.MethodPost,
+			path:         "/documents",
+			body:         `{"pkgPath":"stack","maxSize":1}`,
+			expectStatus: http.StatusOK,
+			check: func(t *testing.T, body []byte) {
+				assert.Equal(t, "[]", strings.TrimSpace(string(body)))
+			},
+		},
+		{
+			description:  "create documents of missing package",
+			method:       http.MethodPost,
+			path:         "/documents",
+			body:         `{"pkgPath":"queue"}`,
+			expectStatus: http.StatusNotFound,
+		},
+		{
+			description:  "lineage",
+			method:       http.MethodPost,
+			path:         "/lineage",
+			body:         `{"path":"stack","language":"go"}`,
+			expectStatus: http.StatusOK,
+			check: func(t *testing.T, body []byte) {
+				var points []*linage.DataPoint
+				assert.NoError(t, json.Unmarshal(body, &points))
+				var names []string
+				for _, point := range points {
+					names = append(names, point.Name)
+				}
+				assert.Contains(t, names, "items")
+			},
+		},
+		{
+			description:  "lineage of unsupported language",
+			method:       http.MethodPost,
+			path:         "/lineage",
+			body:         `{"path":"stack","language":"cobol"}`,
+			expectStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			description:  "symbol at offset",
+			method:       http.MethodGet,
+			path:         "/symbol?file=app/main.go&offset=" + topOffset,
+			expectStatus: http.StatusOK,
+			check: func(t *testing.T, body []byte) {
+				point := &linage.DataPoint{}
+				assert.NoError(t, json.Unmarshal(body, point))
+				assert.Equal(t, "top", point.Name)
+				assert.Equal(t, 15, point.Definition.LineNumber)
+			},
+		},
+		{
+			description:  "symbol at offset without identifier",
+			method:       http.MethodGet,
+			path:         "/symbol?file=app/main.go&offset=0",
+			expectStatus: http.StatusNotFound,
+		},
+		{
+			description:  "symbol without offset",
+			method:       http.MethodGet,
+			path:         "/symbol?file=app/main.go",
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			description:  "symbol of missing file",
+			method:       http.MethodGet,
+			path:         "/symbol?file=app/missing.go&offset=1",
+			expectStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			request, err := http.NewRequest(testCase.method, httpServer.URL+testCase.path, bytes.NewBufferString(testCase.body))
+			if !assert.NoError(t, err) {
+				return
+			}
+			response, err := http.DefaultClient.Do(request)
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer response.Body.Close()
+			body, err := io.ReadAll(response.Body)
+			assert.NoError(t, err)
+			if !assert.Equal(t, testCase.expectStatus, response.StatusCode, string(body)) {
+				return
+			}
+			assert.Equal(t, "application/json", response.Header.Get("Content-Type"))
+			if testCase.check != nil {
+				testCase.check(t, body)
+			}
+		})
+	}
+}
+
+// TestServer_ConcurrentRequests reads one cached project from concurrent requests, run with -race
+func TestServer_ConcurrentRequests(t *testing.T) {
+	handler := server.New(testRoot).Handler()
+	warmup := httptest.NewRecorder()
+	handler.ServeHTTP(warmup, httptest.NewRequest(http.MethodGet, "/project/packages", nil))
+	if !assert.Equal(t, http.StatusOK, warmup.Code, warmup.Body.String()) {
+		return
+	}
+
+	requests := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{method: http.MethodGet, path: "/project/types?name=Stack"},
+		{method: http.MethodGet, path: "/project/types?name=Run"},
+		{method: http.MethodGet, path: "/project/packages"},
+		{method: http.MethodPost, path: "/inspect", body: `{"path":"","summary":true}`},
+		{method: http.MethodPost, path: "/documents", body: `{"pkgPath":"app"}`},
+	}
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		for _, item := range requests {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-start
+				recorder := httptest.NewRecorder()
+				handler.ServeHTTP(recorder, httptest.NewRequest(item.method, item.path, strings.NewReader(item.body)))
+				assert.NotEqual(t, http.StatusInternalServerError, recorder.Code, item.path+": "+recorder.Body.String())
+			}()
+		}
+	}
+	close(start)
+	wg.Wait()
+}
+
+func TestServer_Shutdown(t *testing.T) {
+	srv := server.New(testRoot)
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.ListenAndServe("127.0.0.1:0")
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, srv.Shutdown(ctx))
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("server did not stop")
+	}
+}
@@ -0,0 +1,677 @@
+package tui
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/viant/linager/analyzer/linage"
+	"github.com/viant/linager/inspector/graph"
+	"github.com/viant/linager/inspector/index"
+)
+
+// Focus is the explorer pane receiving navigation
+type Focus int
+
+// Explorer panes
+const (
+	FocusTree Focus = iota
+	FocusLinks
+	FocusSearch
+)
+
+// Link kinds, see Explorer.ShowLinks
+const (
+	LinkUpstream   = "upstream"
+	LinkDownstream = "downstream"
+	LinkCallers    = "callers"
+	LinkCallees    = "callees"
+	LinkReferences = "references"
+)
+
+// maxSearchResults limits symbols listed by a search
+const maxSearchResults = 200
+
+// Node is a tree element: a package, type, field, function, variable or constant; children are loaded on expansion
+type Node struct {
+	Label    string
+	Ref      string // Canonical reference, see graph.Ref
+	Kind     string // Ref kind, see graph.RefKindType
+	Depth    int
+	Expanded bool
+	Parent   *Node
+	children []*Node
+	loaded   bool
+	load     func() []*Node
+}
+
+// Leaf reports whether the node has no children to load
+func (n *Node) Leaf() bool {
+	return n.load == nil && len(n.children) == 0
+}
+
+// Link is an element related to the selected one, its Ref is empty when it has no project element to jump to
+type Link struct {
+	Label    string
+	Ref      string
+	Location string // file:line of the relation, e.g. a data flow site
+}
+
+// Detail describes the selected element
+type Detail struct {
+	Ref      string
+	Kind     string
+	File     string
+	Metadata []string
+	Snippet  string
+}
+
+// Explorer is the terminal UI view model: a lazily loaded package/type/field tree, the selected element detail, links
+// to upstream/downstream identifiers, callers/callees and references, and a search over the symbol index. All data is
+// read through the project and lineage query APIs (graph.Project ByRef, CallGraph and References, index.Symbols and
+// linage.PackageModel data flows); rendering is left to Run
+type Explorer struct {
+	Focus   Focus
+	Message string // Status message of the last action, e.g. a failed jump
+
+	project *graph.Project
+	symbols index.Symbols
+	model   *linage.PackageModel
+	roots   []*Node
+	visible []*Node
+	cursor  int
+	history []string // refs selected before jumps, see Back
+
+	query         string
+	results       []*index.Symbol
+	resultCursor  int
+	linkKind      string
+	links         []*Link
+	linkCursor    int
+	callers       map[string][]string             // callee ref -> caller refs
+	callees       map[string][]string             // caller ref -> callee refs
+	references    *graph.ReferenceIndex           // lazily built, see Project.References
+	identsByRef   map[string][]*linage.Identifier // lineage identifiers by canonical reference
+	flowsBySource map[string][]*linage.DataFlowEdge
+	flowsByTarget map[string][]*linage.DataFlowEdge
+}
+
+// NewExplorer creates an explorer of a project with its symbol index and optional lineage model
+func NewExplorer(project *graph.Project, symbols index.Symbols, model *linage.PackageModel) *Explorer {
+	if symbols == nil {
+		symbols = index.NewSymbols(project)
+	}
+	ret := &Explorer{project: project, symbols: symbols, model: model}
+	for _, pkg := range project.Packages {
+		ret.roots = append(ret.roots, ret.packageNode(pkg))
+	}
+	sort.Slice(ret.roots, func(i, j int) bool { return ret.roots[i].Label < ret.roots[j].Label })
+	ret.refresh()
+	return ret
+}
+
+// packageNode creates a package node loading its types, variables and constants, then functions
+func (e *Explorer) packageNode(pkg *graph.Package) *Node {
+	pkgRef := pkg.Ref()
+	ret := &Node{Label: pkgRef.Package, Ref: pkgRef.String(), Kind: graph.RefKindPackage}
+	ret.load = func() []*Node {
+		var types, values, functions []*Node
+		methods := map[string][]*graph.Function{} // methods declared outside of types by receiver type
+		for _, file := range pkg.FileSet {
+			for _, function := range file.Functions {
+				if ref := function.Ref(pkgRef); ref.Type != "" {
+					methods[ref.Type] = append(methods[ref.Type], function)
+					continue
+				}
+				functions = append(functions, &Node{Label: function.Name + "()", Ref: function.Ref(pkgRef).String(), Kind: graph.RefKindFunction})
+			}
+			for _, variable := range file.Variables {
+				values = append(values, &Node{Label: "var " + variable.Name, Ref: graph.NewTypeRef(pkgRef.Package, variable.Name).String(), Kind: graph.RefKindType})
+			}
+			for _, constant := range file.Constants {
+				values = append(values, &Node{Label: "const " + constant.Name, Ref: graph.NewTypeRef(pkgRef.Package, constant.Name).String(), Kind: graph.RefKindType})
+			}
+		}
+		for _, file := range pkg.FileSet {
+			for _, aType := range file.Types {
+				types = append(types, typeNode(pkgRef, aType, methods))
+			}
+		}
+		sortNodes(types)
+		sortNodes(values)
+		sortNodes(functions)
+		return append(append(types, values...), functions...)
+	}
+	return ret
+}
+
+// typeNode creates a type node loading its fields and methods
+func typeNode(pkgRef graph.Ref, aType *graph.Type, methods map[string][]*graph.Function) *Node {
+	typeRef := aType.Ref()
+	typeRef.Package = pkgRef.Package
+	ret := &Node{Label: "type " + typeRef.Type, Ref: typeRef.String(), Kind: graph.RefKindType}
+	declared := methods[typeRef.Type]
+	if len(aType.Fields) == 0 && len(aType.Methods) == 0 && len(declared) == 0 {
+		return ret
+	}
+	ret.load = func() []*Node {
+		var fields, functions []*Node
+		for _, field := range aType.Fields {
+			fields = append(fields, &Node{Label: field.Name, Ref: field.Ref(typeRef).String(), Kind: graph.RefKindField})
+		}
+		for _, method := range append(append([]*graph.Function{}, aType.Methods...), declared...) {
+			functions = append(functions, &Node{Label: method.Name + "()", Ref: method.Ref(typeRef).String(), Kind: graph.RefKindMethod})
+		}
+		sortNodes(functions)
+		return append(fields, functions...)
+	}
+	return ret
+}
+
+// sortNodes sorts nodes by label
+func sortNodes(nodes []*Node) {
+	sort.SliceStable(nodes, func(i, j int) bool { return nodes[i].Label < nodes[j].Label })
+}
+
+// children returns loaded node children
+func (e *Explorer) children(node *Node) []*Node {
+	if !node.loaded && node.load != nil {
+		node.children = node.load()
+		for _, child := range node.children {
+			child.Parent, child.Depth = node, node.Depth+1
+		}
+		node.loaded = true
+	}
+	return node.children
+}
+
+// refresh flattens expanded nodes, only loaded children of expanded nodes are visited
+func (e *Explorer) refresh() {
+	e.visible = e.visible[:0]
+	var visit func(nodes []*Node)
+	visit = func(nodes []*Node) {
+		for _, node := range nodes {
+			e.visible = append(e.visible, node)
+			if node.Expanded {
+				visit(node.children)
+			}
+		}
+	}
+	visit(e.roots)
+	e.cursor = clamp(e.cursor, len(e.visible))
+}
+
+// Visible returns nodes of the tree as displayed
+func (e *Explorer) Visible() []*Node {
+	return e.visible
+}
+
+// Cursor returns the position of the selected node in Visible
+func (e *Explorer) Cursor() int {
+	return e.cursor
+}
+
+// Selected returns the selected tree node, nil for an empty project
+func (e *Explorer) Selected() *Node {
+	if len(e.visible) == 0 {
+		return nil
+	}
+	return e.visible[e.cursor]
+}
+
+// Move moves the cursor of the focused pane
+func (e *Explorer) Move(delta int) {
+	switch e.Focus {
+	case FocusLinks:
+		e.linkCursor = clamp(e.linkCursor+delta, len(e.links))
+	case FocusSearch:
+		e.resultCursor = clamp(e.resultCursor+delta, len(e.results))
+	default:
+		e.cursor = clamp(e.cursor+delta, len(e.visible))
+		e.clearLinks()
+	}
+}
+
+// Expand expands the selected node, loading its children
+func (e *Explorer) Expand() {
+	node := e.Selected()
+	if node == nil || node.Expanded {
+		return
+	}
+	if len(e.children(node)) == 0 {
+		return
+	}
+	node.Expanded = true
+	e.refresh()
+}
+
+// Collapse collapses the selected node or, when it is collapsed, selects its parent
+func (e *Explorer) Collapse() {
+	node := e.Selected()
+	switch {
+	case node == nil:
+		return
+	case node.Expanded:
+		node.Expanded = false
+	case node.Parent != nil:
+		node = node.Parent
+		node.Expanded = false
+	default:
+		return
+	}
+	e.refresh()
+	e.cursor = e.indexOf(node)
+	e.clearLinks()
+}
+
+// Toggle expands a collapsed or collapses an expanded node
+func (e *Explorer) Toggle() {
+	if node := e.Selected(); node != nil && node.Expanded {
+		e.Collapse()
+		return
+	}
+	e.Expand()
+}
+
+// indexOf returns the position of a visible node
+func (e *Explorer) indexOf(node *Node) int {
+	for i, candidate := range e.visible {
+		if candidate == node {
+			return i
+		}
+	}
+	return 0
+}
+
+// Select selects the tree node of a canonical reference, expanding its ancestors; it reports whether the node exists
+func (e *Explorer) Select(ref string) bool {
+	parsed, err := graph.ParseRef(ref)
+	if err != nil {
+		return false
+	}
+	var pkg *Node
+	for _, root := range e.roots {
+		if root.Ref == parsed.Package {
+			pkg = root
+		}
+	}
+	if pkg == nil {
+		return false
+	}
+	node := pkg
+	if parsed.Kind() != graph.RefKindPackage {
+		if node = e.find(e.children(pkg), parsed.String()); node == nil {
+			owner := graph.NewTypeRef(parsed.Package, parsed.Type).String()
+			if owner := e.find(e.children(pkg), owner); owner != nil {
+				node = e.find(e.children(owner), parsed.String())
+			}
+		}
+	}
+	if node == nil {
+		return false
+	}
+	for parent := node.Parent; parent != nil; parent = parent.Parent {
+		parent.Expanded = true
+	}
+	e.refresh()
+	e.cursor = e.indexOf(node)
+	e.Focus = FocusTree
+	e.clearLinks()
+	return true
+}
+
+// find returns a node with a reference
+func (e *Explorer) find(nodes []*Node, ref string) *Node {
+	for _, node := range nodes {
+		if node.Ref == ref {
+			return node
+		}
+	}
+	return nil
+}
+
+// Detail describes the selected element resolved with graph.Project ByRef
+func (e *Explorer) Detail() *Detail {
+	node := e.Selected()
+	if node == nil {
+		return nil
+	}
+	ret := &Detail{Ref: node.Ref, Kind: node.Kind}
+	target := e.project.ByRef(node.Ref)
+	if target == nil {
+		return ret
+	}
+	if target.File != nil {
+		ret.File = target.File.Path
+	}
+	add := func(key string, value interface{}) {
+		if text := fmt.Sprint(value); text != "" && text != "0" {
+			ret.Metadata = append(ret.Metadata, key+": "+text)
+		}
+	}
+	switch {
+	case target.Field != nil:
+		field := target.Field
+		if field.Type != nil {
+			add("type", field.Type.Name)
+		}
+		add("tag", string(field.Tag))
+		add("column", field.Column)
+		add("comment", strings.TrimSpace(field.Comment))
+		add("usages", len(field.UsageRefs))
+		if field.Location != nil {
+			ret.Snippet = field.Location.Raw
+		}
+	case target.Function != nil:
+		function := target.Function
+		add("signature", function.Signature)
+		if function.Comment != nil {
+			add("comment", strings.TrimSpace(function.Comment.Text))
+		}
+		add("usages", len(function.UsageRefs))
+		if ret.Snippet = function.Content(); ret.Snippet == "" && function.Body != nil {
+			ret.Snippet = function.Body.Text
+		}
+	case target.Variable != nil:
+		add("value", target.Variable.Value)
+		add("usages", len(target.Variable.UsageRefs))
+		if target.Variable.Location != nil {
+			ret.Snippet = target.Variable.Location.Raw
+		}
+	case target.Constant != nil:
+		add("value", target.Constant.Value)
+		add("usages", len(target.Constant.UsageRefs))
+		if target.Constant.Location != nil {
+			ret.Snippet = target.Constant.Location.Raw
+		}
+	case target.Type != nil:
+		aType := target.Type
+		add("kind", aType.Kind)
+		add("fields", len(aType.Fields))
+		add("methods", len(aType.Methods))
+		if aType.Comment != nil {
+			add("comment", strings.TrimSpace(aType.Comment.Text))
+		}
+		add("usages", len(aType.UsageRefs))
+		if aType.Location != nil {
+			ret.Snippet = aType.Location.Raw
+		}
+	default:
+		ret.File = ""
+		add("name", target.Package.Name)
+		add("files", len(target.Package.FileSet))
+		add("owners", strings.Join(target.Package.Owners, ", "))
+	}
+	return ret
+}
+
+// ShowLinks lists elements related to the selected one and focuses them, see Link* kinds
+func (e *Explorer) ShowLinks(kind string) {
+	node := e.Selected()
+	if node == nil {
+		return
+	}
+	e.linkKind, e.linkCursor, e.Message = kind, 0, ""
+	switch kind {
+	case LinkUpstream, LinkDownstream:
+		e.links = e.flowLinks(node.Ref, kind == LinkUpstream)
+	case LinkCallers, LinkCallees:
+		e.links = e.callLinks(node.Ref, kind == LinkCallers)
+	case LinkReferences:
+		e.links = e.referenceLinks(node.Ref)
+	default:
+		e.links = nil
+	}
+	if len(e.links) == 0 {
+		e.linkKind, e.Message = "", "no "+kind
+		return
+	}
+	e.Focus = FocusLinks
+}
+
+// Links returns listed links with their kind and the selected link position
+func (e *Explorer) Links() (string, []*Link, int) {
+	return e.linkKind, e.links, e.linkCursor
+}
+
+// clearLinks drops links of a previous selection
+func (e *Explorer) clearLinks() {
+	e.linkKind, e.links, e.linkCursor = "", nil, 0
+	if e.Focus == FocusLinks {
+		e.Focus = FocusTree
+	}
+}
+
+// Jump selects the element of the selected link or search result, recording the current one for Back
+func (e *Explorer) Jump() bool {
+	var ref string
+	switch e.Focus {
+	case FocusLinks:
+		if len(e.links) > 0 {
+			ref = e.links[e.linkCursor].Ref
+		}
+	case FocusSearch:
+		if len(e.results) > 0 {
+			ref = e.results[e.resultCursor].Ref
+		}
+	}
+	if ref == "" {
+		e.Message = "no project element to jump to"
+		return false
+	}
+	current := e.Selected()
+	if !e.Select(ref) {
+		e.Message = "not found: " + ref
+		return false
+	}
+	if current != nil {
+		e.history = append(e.history, current.Ref)
+	}
+	e.Message = ""
+	return true
+}
+
+// Back selects the element selected before the last jump
+func (e *Explorer) Back() bool {
+	if len(e.history) == 0 {
+		return false
+	}
+	ref := e.history[len(e.history)-1]
+	e.history = e.history[:len(e.history)-1]
+	return e.Select(ref)
+}
+
+// StartSearch focuses the search box
+func (e *Explorer) StartSearch() {
+	e.Focus = FocusSearch
+	e.Search(e.query)
+}
+
+// CancelSearch returns focus to the tree
+func (e *Explorer) CancelSearch() {
+	e.Focus = FocusTree
+}
+
+// Query returns the search query with matching symbols and the selected result position
+func (e *Explorer) Query() (string, []*index.Symbol, int) {
+	return e.query, e.results, e.resultCursor
+}
+
+// Search lists symbols matching a query: exact references and names (see index.Symbols Lookup) first, then symbols
+// whose name, then reference, contains the query case insensitively, shorter names first, up to maxSearchResults
+func (e *Explorer) Search(query string) {
+	e.query, e.results, e.resultCursor = query, nil, 0
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return
+	}
+	e.results = e.symbols.Lookup(query)
+	seen := map[string]bool{}
+	for _, symbol := range e.results {
+		seen[symbol.Ref] = true
+	}
+	lower := strings.ToLower(query)
+	var byName, byRef []*index.Symbol
+	for _, symbol := range e.symbols {
+		switch {
+		case seen[symbol.Ref]:
+		case strings.Contains(strings.ToLower(symbol.Name), lower):
+			byName = append(byName, symbol)
+		case strings.Contains(strings.ToLower(symbol.Ref), lower):
+			byRef = append(byRef, symbol)
+		}
+	}
+	for _, matched := range [][]*index.Symbol{byName, byRef} {
+		sort.Slice(matched, func(i, j int) bool {
+			if len(matched[i].Name) != len(matched[j].Name) {
+				return len(matched[i].Name) < len(matched[j].Name)
+			}
+			return matched[i].Ref < matched[j].Ref
+		})
+		e.results = append(e.results, matched...)
+	}
+	if len(e.results) > maxSearchResults {
+		e.results = e.results[:maxSearchResults]
+	}
+}
+
+// callLinks returns callers or callees of a function or method from graph.Project CallGraph
+func (e *Explorer) callLinks(ref string, callers bool) []*Link {
+	if e.callers == nil {
+		e.indexCalls()
+	}
+	related := e.callees[ref]
+	if callers {
+		related = e.callers[ref]
+	}
+	var result []*Link
+	for _, item := range related {
+		result = append(result, &Link{Label: item, Ref: item})
+	}
+	return result
+}
+
+// indexCalls maps call graph keys (e.g. myapp/stack.Stack.Push) back to function references of the symbol index
+func (e *Explorer) indexCalls() {
+	e.callers, e.callees = map[string][]string{}, map[string][]string{}
+	byKey := map[string][]string{}
+	for ref, symbol := range e.symbols {
+		if symbol.Kind != graph.RefKindFunction && symbol.Kind != graph.RefKindMethod {
+			continue
+		}
+		parsed, err := graph.ParseRef(ref)
+		if err != nil {
+			continue
+		}
+		key := parsed.Package + "." + parsed.Function
+		if parsed.Type != "" {
+			key = parsed.Package + "." + parsed.Type + "." + parsed.Function
+		}
+		byKey[key] = append(byKey[key], ref)
+	}
+	for caller, callees := range e.project.CallGraph() {
+		for _, callerRef := range byKey[caller] {
+			for _, callee := range callees {
+				for _, calleeRef := range byKey[callee] {
+					e.callees[callerRef] = append(e.callees[callerRef], calleeRef)
+					e.callers[calleeRef] = append(e.callers[calleeRef], callerRef)
+				}
+			}
+		}
+	}
+	for _, refs := range e.callers {
+		sort.Strings(refs)
+	}
+}
+
+// referenceLinks returns packages referencing an element from outside of its package, see graph.ReferenceIndex
+func (e *Explorer) referenceLinks(ref string) []*Link {
+	parsed, err := graph.ParseRef(ref)
+	if err != nil || parsed.Kind() == graph.RefKindPackage {
+		return nil
+	}
+	if e.references == nil {
+		e.references = e.project.References()
+	}
+	var result []*Link
+	for _, pkg := range e.references.ExternalReferences(parsed) {
+		link := &Link{Label: pkg}
+		if !strings.HasSuffix(pkg, "_test") {
+			link.Ref = pkg
+		}
+		result = append(result, link)
+	}
+	return result
+}
+
+// flowLinks returns identifiers the element flows into (downstream) or receives values from (upstream) through XFER
+// edges of the lineage model, identifiers are matched by their canonical reference, see linage.Identifier Ref
+func (e *Explorer) flowLinks(ref string, upstream bool) []*Link {
+	if e.model == nil {
+		return nil
+	}
+	if e.identsByRef == nil {
+		e.indexFlows()
+	}
+	seen := map[string]bool{}
+	var result []*Link
+	for _, id := range e.identsByRef[ref] {
+		edges := e.flowsBySource[id.ID]
+		if upstream {
+			edges = e.flowsByTarget[id.ID]
+		}
+		for _, edge := range edges {
+			related := edge.Dst
+			if upstream {
+				related = edge.Src
+			}
+			link := &Link{Label: related.Name, Ref: related.Ref}
+			if related.Kind != "" {
+				link.Label += " (" + related.Kind + ")"
+			}
+			if site := edge.Site(); site != nil {
+				link.Location = fmt.Sprintf("%s:%d", path.Base(site.FilePath), site.LineNumber)
+			} else if related.Node != nil {
+				link.Location = fmt.Sprintf("%s:%d", path.Base(related.File), related.Node.StartPoint().Row+1)
+			}
+			if key := link.Label + "\x00" + link.Location; !seen[key] {
+				seen[key] = true
+				result = append(result, link)
+			}
+		}
+	}
+	return result
+}
+
+// indexFlows indexes lineage identifiers by reference and XFER edges by endpoint
+func (e *Explorer) indexFlows() {
+	e.identsByRef = map[string][]*linage.Identifier{}
+	e.flowsBySource, e.flowsByTarget = map[string][]*linage.DataFlowEdge{}, map[string][]*linage.DataFlowEdge{}
+	for _, id := range e.model.Idents {
+		if id.Ref != "" {
+			e.identsByRef[id.Ref] = append(e.identsByRef[id.Ref], id)
+		}
+	}
+	for _, ids := range e.identsByRef {
+		sort.Slice(ids, func(i, j int) bool { return ids[i].ID < ids[j].ID })
+	}
+	for _, edge := range e.model.DataFlows {
+		if edge.Kind != linage.Xfer || edge.Src == nil || edge.Dst == nil {
+			continue
+		}
+		e.flowsBySource[edge.Src.ID] = append(e.flowsBySource[edge.Src.ID], edge)
+		e.flowsByTarget[edge.Dst.ID] = append(e.flowsByTarget[edge.Dst.ID], edge)
+	}
+}
+
+// clamp bounds a position within n items
+func clamp(position, n int) int {
+	if position >= n {
+		position = n - 1
+	}
+	if position < 0 {
+		return 0
+	}
+	return position
+}
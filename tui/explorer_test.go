@@ -0,0 +1,175 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	sitter "github.com/smacker/go-tree-sitter/golang"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/linager/analyzer"
+	"github.com/viant/linager/analyzer/linage"
+	"github.com/viant/linager/inspector"
+	"github.com/viant/linager/inspector/graph"
+	"github.com/viant/linager/inspector/repository"
+)
+
+var explorerSources = map[string]string{
+	"go.mod": "module example\n\ngo 1.21\n",
+	"model/user.go": "package model\n\n// User is an account holder\ntype User struct {\n\tName  string\n\tEmail string `json:\"email\"`\n}\n\nfunc (u *User) Label() string {\n\treturn u.Name\n}\n\n" +
+		"func (u *User) Contact() string {\n\temail := u.Email\n\treturn email\n}\n",
+	"service/notify.go": "package service\n\nimport \"example/model\"\n\nconst Prefix = \"to:\"\n\n" +
+		"func Notify(u *model.User) string {\n\taddress := u.Email\n\treturn format(address)\n}\n\n" +
+		"func format(value string) string {\n\treturn Prefix + value\n}\n",
+}
+
+// newTestExplorer inspects and analyzes explorerSources, packages are referenced by their directory
+func newTestExplorer(t *testing.T) (*Explorer, func(ref string) string) {
+	root := t.TempDir()
+	for name, content := range explorerSources {
+		assert.NoError(t, os.MkdirAll(filepath.Join(root, filepath.Dir(name)), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(root, name), []byte(content), 0644))
+	}
+	project, err := inspector.NewFactory(graph.DefaultConfig()).InspectProject(&repository.Project{RootPath: root, Type: "go"})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	var models []*linage.PackageModel
+	for _, name := range []string{"model/user.go", "service/notify.go"} {
+		model, err := analyzer.NewAnalyzer(analyzer.WithLanguage(sitter.GetLanguage())).AnalyzeModel([]byte(explorerSources[name]), filepath.Join(root, filepath.Dir(name)), filepath.Base(name))
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		models = append(models, model)
+	}
+	return NewExplorer(project, nil, linage.Merge(models...)), func(ref string) string { return root + "/" + ref }
+}
+
+// labels returns labels of visible nodes
+func labels(explorer *Explorer) []string {
+	var result []string
+	for _, node := range explorer.Visible() {
+		result = append(result, node.Label)
+	}
+	return result
+}
+
+func TestExplorer_Navigation(t *testing.T) {
+	explorer, ref := newTestExplorer(t)
+	assert.Equal(t, []string{ref("model"), ref("service")}, labels(explorer))
+	for _, root := range explorer.roots {
+		assert.False(t, root.loaded, "children are loaded on expansion")
+	}
+
+	explorer.Move(1)
+	explorer.Expand()
+	assert.Equal(t, []string{ref("model"), ref("service"), "const Prefix", "Notify()", "format()"}, labels(explorer))
+	assert.False(t, explorer.roots[0].loaded)
+	explorer.Move(1)
+	assert.Equal(t, ref("service#Prefix"), explorer.Selected().Ref)
+	explorer.Collapse()
+	assert.Equal(t, ref("service"), explorer.Selected().Ref, "collapsing a leaf selects its parent")
+	assert.Equal(t, []string{ref("model"), ref("service")}, labels(explorer))
+	explorer.Move(-5)
+	assert.Equal(t, 0, explorer.Cursor())
+
+	assert.True(t, explorer.Select(ref("model#User/Email")))
+	assert.Equal(t, []string{ref("model"), "type User", "Name", "Email", "Contact()", "Label()", ref("service")}, labels(explorer))
+	detail := explorer.Detail()
+	if assert.NotNil(t, detail) {
+		assert.Equal(t, graph.RefKindField, detail.Kind)
+		assert.Contains(t, detail.Metadata, "type: string")
+		assert.Contains(t, detail.Metadata, `tag: json:"email"`)
+	}
+	assert.True(t, explorer.Select(ref("model#User.Label()")))
+	assert.Contains(t, explorer.Detail().Snippet, "return u.Name")
+	assert.False(t, explorer.Select(ref("model#Missing")))
+}
+
+func TestExplorer_Links(t *testing.T) {
+	explorer, ref := newTestExplorer(t)
+	explorer.Select(ref("service#format(string)"))
+	explorer.ShowLinks(LinkCallers)
+	kind, links, _ := explorer.Links()
+	assert.Equal(t, LinkCallers, kind)
+	if assert.Len(t, links, 1) {
+		assert.Equal(t, ref("service#Notify(*model.User)"), links[0].Ref)
+	}
+	assert.Equal(t, FocusLinks, explorer.Focus)
+	assert.True(t, explorer.Jump())
+	assert.Equal(t, ref("service#Notify(*model.User)"), explorer.Selected().Ref)
+	assert.Equal(t, FocusTree, explorer.Focus)
+
+	explorer.ShowLinks(LinkCallees)
+	_, links, _ = explorer.Links()
+	if assert.Len(t, links, 1) {
+		assert.Equal(t, ref("service#format(string)"), links[0].Ref)
+	}
+	assert.True(t, explorer.Back())
+	assert.Equal(t, ref("service#format(string)"), explorer.Selected().Ref)
+	assert.False(t, explorer.Back())
+
+	explorer.Select(ref("model#User"))
+	explorer.ShowLinks(LinkReferences)
+	_, links, _ = explorer.Links()
+	if assert.Len(t, links, 1) {
+		assert.Equal(t, ref("service"), links[0].Ref)
+	}
+
+	explorer.Select(ref("model#User/Email"))
+	explorer.ShowLinks(LinkDownstream)
+	_, links, _ = explorer.Links()
+	if assert.NotEmpty(t, links) {
+		assert.Equal(t, "email", strings.Fields(links[0].Label)[0])
+		assert.Equal(t, "user.go:14", links[0].Location)
+	}
+	explorer.Move(1)
+	assert.Equal(t, FocusLinks, explorer.Focus, "moving in links keeps the tree selection")
+
+	explorer.Focus = FocusTree
+	explorer.Select(ref("model#User/Name"))
+	explorer.ShowLinks(LinkCallers)
+	_, links, _ = explorer.Links()
+	assert.Empty(t, links)
+	assert.Equal(t, "no callers", explorer.Message)
+	assert.Equal(t, FocusTree, explorer.Focus)
+}
+
+func TestExplorer_Search(t *testing.T) {
+	explorer, ref := newTestExplorer(t)
+	explorer.StartSearch()
+	assert.Equal(t, FocusSearch, explorer.Focus)
+
+	explorer.Search("format")
+	_, results, _ := explorer.Query()
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, ref("service#format(string)"), results[0].Ref)
+	}
+
+	explorer.Search("User")
+	_, results, _ = explorer.Query()
+	var refs []string
+	for _, symbol := range results {
+		refs = append(refs, symbol.Ref)
+	}
+	assert.Equal(t, []string{ref("model#User"), ref("model#User/Name"), ref("model#User.Label()"), ref("model#User/Email"), ref("model#User.Contact()"),
+		ref("service#Notify(*model.User)")}, refs, "exact names first, then shorter names, then references")
+
+	explorer.Search("mAiL")
+	_, results, _ = explorer.Query()
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, ref("model#User/Email"), results[0].Ref)
+	}
+	assert.True(t, explorer.Jump())
+	assert.Equal(t, ref("model#User/Email"), explorer.Selected().Ref)
+	assert.Equal(t, FocusTree, explorer.Focus)
+
+	explorer.StartSearch()
+	explorer.Search("missing")
+	_, results, _ = explorer.Query()
+	assert.Empty(t, results)
+	assert.False(t, explorer.Jump())
+	explorer.CancelSearch()
+	assert.Equal(t, FocusTree, explorer.Focus)
+}
@@ -0,0 +1,243 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// help lists keybindings shown in the status line
+const help = "↑↓ move  →← expand/collapse  u/d upstream/downstream  c/e callers/callees  r references  / search  b back  q quit"
+
+var (
+	styleDefault  = tcell.StyleDefault
+	styleSelected = tcell.StyleDefault.Reverse(true)
+	styleHeader   = tcell.StyleDefault.Bold(true)
+	styleDim      = tcell.StyleDefault.Dim(true)
+)
+
+// Run renders an explorer on a screen and handles keyboard events until the user quits; only rows of the tree around
+// the cursor are rendered, children are loaded as nodes are expanded
+func Run(screen tcell.Screen, explorer *Explorer) error {
+	if err := screen.Init(); err != nil {
+		return fmt.Errorf("failed to initialize terminal: %w", err)
+	}
+	defer screen.Fini()
+	offset := 0
+	for {
+		offset = draw(screen, explorer, offset)
+		switch event := screen.PollEvent().(type) {
+		case nil:
+			return nil
+		case *tcell.EventResize:
+			screen.Sync()
+		case *tcell.EventKey:
+			if !handleKey(explorer, event) {
+				return nil
+			}
+		}
+	}
+}
+
+// handleKey applies a key to the explorer, it returns false when the user quits
+func handleKey(explorer *Explorer, event *tcell.EventKey) bool {
+	if event.Key() == tcell.KeyCtrlC {
+		return false
+	}
+	if explorer.Focus == FocusSearch {
+		query, _, _ := explorer.Query()
+		switch event.Key() {
+		case tcell.KeyEscape:
+			explorer.CancelSearch()
+		case tcell.KeyEnter:
+			explorer.Jump()
+		case tcell.KeyUp:
+			explorer.Move(-1)
+		case tcell.KeyDown:
+			explorer.Move(1)
+		case tcell.KeyBackspace, tcell.KeyBackspace2:
+			if query != "" {
+				_, size := utf8.DecodeLastRuneInString(query)
+				explorer.Search(query[:len(query)-size])
+			}
+		case tcell.KeyRune:
+			explorer.Search(query + string(event.Rune()))
+		}
+		return true
+	}
+	switch event.Key() {
+	case tcell.KeyUp:
+		explorer.Move(-1)
+	case tcell.KeyDown:
+		explorer.Move(1)
+	case tcell.KeyPgUp:
+		explorer.Move(-10)
+	case tcell.KeyPgDn:
+		explorer.Move(10)
+	case tcell.KeyRight:
+		explorer.Expand()
+	case tcell.KeyLeft:
+		explorer.Collapse()
+	case tcell.KeyEnter:
+		if explorer.Focus == FocusLinks {
+			explorer.Jump()
+		} else {
+			explorer.Toggle()
+		}
+	case tcell.KeyEscape:
+		explorer.Focus = FocusTree
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		explorer.Back()
+	case tcell.KeyRune:
+		switch event.Rune() {
+		case 'q':
+			return false
+		case 'k':
+			explorer.Move(-1)
+		case 'j':
+			explorer.Move(1)
+		case 'l':
+			explorer.Expand()
+		case 'h':
+			explorer.Collapse()
+		case 'u':
+			explorer.ShowLinks(LinkUpstream)
+		case 'd':
+			explorer.ShowLinks(LinkDownstream)
+		case 'c':
+			explorer.ShowLinks(LinkCallers)
+		case 'e':
+			explorer.ShowLinks(LinkCallees)
+		case 'r':
+			explorer.ShowLinks(LinkReferences)
+		case 'b':
+			explorer.Back()
+		case '/':
+			explorer.StartSearch()
+		}
+	}
+	return true
+}
+
+// draw renders the tree, detail and status panes; it returns the first displayed tree row keeping the cursor visible
+func draw(screen tcell.Screen, explorer *Explorer, offset int) int {
+	screen.Clear()
+	width, height := screen.Size()
+	treeWidth := width * 2 / 5
+	rows := height - 1
+	if rows <= 0 {
+		screen.Show()
+		return offset
+	}
+
+	cursor := explorer.Cursor()
+	if cursor < offset {
+		offset = cursor
+	} else if cursor >= offset+rows {
+		offset = cursor - rows + 1
+	}
+	visible := explorer.Visible()
+	for row := 0; row < rows && offset+row < len(visible); row++ {
+		node := visible[offset+row]
+		marker := "  "
+		switch {
+		case node.Expanded:
+			marker = "▾ "
+		case !node.Leaf():
+			marker = "▸ "
+		}
+		style := styleDefault
+		if offset+row == cursor {
+			style = styleSelected
+		}
+		drawText(screen, 0, row, treeWidth-1, strings.Repeat("  ", node.Depth)+marker+node.Label, style)
+	}
+	for row := 0; row < rows; row++ {
+		screen.SetContent(treeWidth-1, row, '│', nil, styleDim)
+	}
+
+	right := newPane(screen, treeWidth+1, width-treeWidth-1, rows)
+	switch explorer.Focus {
+	case FocusSearch:
+		query, results, selected := explorer.Query()
+		right.line("search: "+query+"▏", styleHeader)
+		for i, symbol := range results {
+			style := styleDefault
+			if i == selected {
+				style = styleSelected
+			}
+			right.line(symbol.Ref+"  "+symbol.Kind, style)
+		}
+	default:
+		if detail := explorer.Detail(); detail != nil {
+			right.line(detail.Ref, styleHeader)
+			right.line(strings.TrimSpace(detail.Kind+"  "+detail.File), styleDim)
+			for _, item := range detail.Metadata {
+				right.line(item, styleDefault)
+			}
+			if kind, links, selected := explorer.Links(); kind != "" {
+				right.line("", styleDefault)
+				right.line(kind+":", styleHeader)
+				for i, link := range links {
+					style := styleDefault
+					if i == selected && explorer.Focus == FocusLinks {
+						style = styleSelected
+					}
+					right.line(strings.TrimSpace(link.Label+"  "+link.Location), style)
+				}
+			}
+			if detail.Snippet != "" {
+				right.line("", styleDefault)
+				for _, line := range strings.Split(detail.Snippet, "\n") {
+					right.line(strings.ReplaceAll(line, "\t", "    "), styleDefault)
+				}
+			}
+		}
+	}
+
+	status := help
+	if explorer.Message != "" {
+		status = explorer.Message
+	}
+	drawText(screen, 0, height-1, width, status, styleDim)
+	screen.Show()
+	return offset
+}
+
+// pane writes lines into a screen region, lines past its height are dropped
+type pane struct {
+	screen tcell.Screen
+	x      int
+	width  int
+	height int
+	row    int
+}
+
+func newPane(screen tcell.Screen, x, width, height int) *pane {
+	return &pane{screen: screen, x: x, width: width, height: height}
+}
+
+func (p *pane) line(text string, style tcell.Style) {
+	if p.row >= p.height {
+		return
+	}
+	drawText(p.screen, p.x, p.row, p.width, text, style)
+	p.row++
+}
+
+// drawText draws a single line clipped to width
+func drawText(screen tcell.Screen, x, y, width int, text string, style tcell.Style) {
+	column := 0
+	for _, r := range text {
+		if column >= width {
+			return
+		}
+		screen.SetContent(x+column, y, r, nil, style)
+		column++
+	}
+	for ; style == styleSelected && column < width; column++ {
+		screen.SetContent(x+column, y, ' ', nil, style)
+	}
+}